@@ -3,10 +3,18 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"jamesbot/internal/control"
@@ -14,25 +22,77 @@ import (
 
 // Client is an HTTP client for the control API.
 type Client struct {
-	endpoint    string
-	statsURL    string
-	rulesURL    string
-	rulesSetURL string
-	httpClient  *http.Client
+	endpoint      string
+	healthURL     string
+	statsURL      string
+	topURL        string
+	overviewURL   string
+	rulesURL      string
+	rulesSetURL   string
+	rulesBatchURL string
+	rulesTestURL  string
+	clearWarnURL  string
+	banURL        string
+	kickURL       string
+	muteURL       string
+	unbanURL      string
+	punishURL     string
+	commandsURL   string
+	maintURL      string
+	transport     *http.Transport
+	httpClient    *http.Client
+	retries       int
 }
 
 // NewClient creates a new API client.
-func NewClient(endpoint string) *Client {
+// Connections to the endpoint are kept alive and reused across calls, so
+// repeated polling does not open a new TCP connection per request.
+func NewClient(endpoint string, opts ...Option) *Client {
 	endpoint = strings.TrimSuffix(endpoint, "/")
-	return &Client{
-		endpoint:    endpoint,
-		statsURL:    endpoint + "/stats",
-		rulesURL:    endpoint + "/rules",
-		rulesSetURL: endpoint + "/rules/set",
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = DefaultMaxIdleConns
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConns
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+
+	c := &Client{
+		endpoint:      endpoint,
+		healthURL:     endpoint + "/health",
+		statsURL:      endpoint + "/stats",
+		topURL:        endpoint + "/stats/commands/top",
+		overviewURL:   endpoint + "/overview",
+		rulesURL:      endpoint + "/rules",
+		rulesSetURL:   endpoint + "/rules/set",
+		rulesBatchURL: endpoint + "/rules/batch",
+		rulesTestURL:  endpoint + "/rules/test",
+		clearWarnURL:  endpoint + "/warnings/clear",
+		banURL:        endpoint + "/moderation/ban",
+		kickURL:       endpoint + "/moderation/kick",
+		muteURL:       endpoint + "/moderation/mute",
+		unbanURL:      endpoint + "/moderation/unban",
+		punishURL:     endpoint + "/punishments",
+		commandsURL:   endpoint + "/commands",
+		maintURL:      endpoint + "/maintenance",
+		transport:     transport,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Transport: transport,
+			Timeout:   10 * time.Second,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// closeBody drains what is left of a response body and closes it. A body
+// closed before EOF forces the transport to drop the connection instead of
+// returning it to the idle pool.
+func closeBody(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	_ = body.Close()
 }
 
 // Timeout returns the HTTP client timeout duration.
@@ -43,6 +103,42 @@ func (c *Client) Timeout() time.Duration {
 	return c.httpClient.Timeout
 }
 
+// Health checks that a control server answers at the endpoint. It waits at
+// most HealthTimeout, so it can be used as a quick probe after another call
+// fails.
+func (c *Client) Health() error {
+	if c == nil {
+		return fmt.Errorf("client is nil")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), HealthTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.healthURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var health control.Health
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("decode failed: %w", err)
+	}
+	if health.Status != "ok" {
+		return fmt.Errorf("unexpected health status %q", health.Status)
+	}
+	return nil
+}
+
 // GetStats retrieves bot statistics from the control API.
 func (c *Client) GetStats() (*control.Stats, error) {
 	if c == nil {
@@ -53,7 +149,7 @@ func (c *Client) GetStats() (*control.Stats, error) {
 	if err != nil {
 		return nil, fmt.Errorf("connection failed: %w", err)
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
@@ -67,17 +163,92 @@ func (c *Client) GetStats() (*control.Stats, error) {
 	return &stats, nil
 }
 
+// TopCommands retrieves the n most executed commands, most first, from the
+// control API. The server caps n at control.MaxTopCommands.
+func (c *Client) TopCommands(n int) ([]control.CommandStats, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	topURL := c.topURL + "?" + url.Values{"n": {strconv.Itoa(n)}}.Encode()
+
+	resp, err := c.httpClient.Get(topURL)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var top []control.CommandStats
+	if err := json.NewDecoder(resp.Body).Decode(&top); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return top, nil
+}
+
+// GetOverview retrieves bot statistics and all moderation rules from the
+// control API in a single request.
+func (c *Client) GetOverview() (*control.Overview, error) {
+	return c.GetGuildOverview("")
+}
+
+// GetGuildOverview retrieves bot statistics and the moderation rules in
+// effect in a guild from the control API in a single request. An empty
+// guildID returns the global rules, like GetOverview.
+func (c *Client) GetGuildOverview(guildID string) (*control.Overview, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	overviewURL := c.overviewURL
+	if guildID != "" {
+		overviewURL += "?" + url.Values{"guild": {guildID}}.Encode()
+	}
+
+	resp, err := c.httpClient.Get(overviewURL)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var overview control.Overview
+	if err := json.NewDecoder(resp.Body).Decode(&overview); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return &overview, nil
+}
+
 // ListRules retrieves all moderation rules from the control API.
 func (c *Client) ListRules() ([]control.Rule, error) {
+	return c.ListGuildRules("")
+}
+
+// ListGuildRules retrieves the moderation rules in effect in a guild from the
+// control API. An empty guildID lists the global rules, like ListRules.
+func (c *Client) ListGuildRules(guildID string) ([]control.Rule, error) {
 	if c == nil {
 		return nil, fmt.Errorf("client is nil")
 	}
 
-	resp, err := c.httpClient.Get(c.rulesURL)
+	rulesURL := c.rulesURL
+	if guildID != "" {
+		rulesURL += "?" + url.Values{"guild": {guildID}}.Encode()
+	}
+
+	resp, err := c.httpClient.Get(rulesURL)
 	if err != nil {
 		return nil, fmt.Errorf("connection failed: %w", err)
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
@@ -93,28 +264,517 @@ func (c *Client) ListRules() ([]control.Rule, error) {
 
 // SetRule modifies a rule setting via the control API.
 func (c *Client) SetRule(name, key, value string) error {
+	return c.SetGuildRule("", name, key, value)
+}
+
+// SetGuildRule modifies a rule setting for one guild via the control API.
+// An empty guildID modifies the global setting, like SetRule.
+//
+// A setting needing a privileged gateway intent the bot did not request is
+// rejected with an error wrapping control.ErrIntentNotRequested. A setting
+// that took effect but could not be saved, so will not survive a restart,
+// returns an error wrapping control.ErrRuleNotSaved.
+//
+// The request carries an idempotency key generated for this call, so retries
+// configured with WithRetries reuse it and the server applies the setting at
+// most once even when an earlier attempt's response was lost.
+func (c *Client) SetGuildRule(guildID, name, key, value string) error {
 	if c == nil {
 		return fmt.Errorf("client is nil")
 	}
 
+	idempotencyKey, err := newIdempotencyKey()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(control.SetRuleRequest{
+		Guild:          guildID,
+		Name:           name,
+		Key:            key,
+		Value:          value,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return fmt.Errorf("encode failed: %w", err)
+	}
+
+	backoff := RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Post(c.rulesSetURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			if attempt < c.retries && retryableError(err) {
+				time.Sleep(backoff)
+				backoff *= 2
+				continue
+			}
+			return fmt.Errorf("connection failed: %w", err)
+		}
+		if resp.StatusCode == http.StatusConflict {
+			defer closeBody(resp.Body)
+			return fmt.Errorf("rule update failed: %w: %s", control.ErrIntentNotRequested, intentDetail(resp.Body))
+		}
+		if resp.StatusCode == http.StatusOK {
+			defer closeBody(resp.Body)
+			// Older servers send no warning, or no body at all
+			var result control.SetRuleResponse
+			if json.NewDecoder(resp.Body).Decode(&result) == nil && result.Warning != "" {
+				return fmt.Errorf("%w: %s", control.ErrRuleNotSaved, trimSentinel(result.Warning, control.ErrRuleNotSaved))
+			}
+			return nil
+		}
+		closeBody(resp.Body)
+
+		if attempt < c.retries && retryableStatus(resp.StatusCode) {
+			time.Sleep(backoff)
+			backoff *= 2
+			continue
+		}
+		return fmt.Errorf("rule update failed: status %d", resp.StatusCode)
+	}
+}
+
+// intentDetail returns the server's explanation, read from body, of which
+// intent a rejected rule update needs.
+func intentDetail(body io.Reader) string {
+	msg, _ := io.ReadAll(io.LimitReader(body, 1024))
+	return trimSentinel(strings.TrimSpace(string(msg)), control.ErrIntentNotRequested)
+}
+
+// trimSentinel returns the part of a server error message after sentinel's
+// text, so it is not repeated when the message is wrapped in sentinel again.
+func trimSentinel(msg string, sentinel error) string {
+	if _, after, ok := strings.Cut(msg, sentinel.Error()+": "); ok {
+		return after
+	}
+	return msg
+}
+
+// newIdempotencyKey returns a random key identifying one logical call.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// retryableError reports whether a request that failed with err may be
+// retried. A refused connection is not, since nothing is listening to answer
+// a retry either.
+func retryableError(err error) bool {
+	return !errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// retryableStatus reports whether a response with status means the request
+// may succeed if sent again.
+func retryableStatus(status int) bool {
+	return status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable ||
+		status == http.StatusGatewayTimeout
+}
+
+// SetRules applies several rule settings in one request via the control API.
+// Items are applied independently; the returned response reports which
+// succeeded and which failed. An error is returned only if the request as a
+// whole could not be completed.
+func (c *Client) SetRules(reqs []control.SetRuleRequest) (*control.BatchSetRulesResponse, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, fmt.Errorf("encode failed: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.rulesBatchURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("batch rule update failed: status %d", resp.StatusCode)
+	}
+
+	var result control.BatchSetRulesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// TestRule runs a content rule, as configured in guildID or globally when
+// guildID is empty, against input via the control API, without the bot
+// acting on it. The returned error wraps control.ErrRuleNotFound if the rule
+// does not exist, or control.ErrRuleNotTestable if it does not match message
+// content.
+func (c *Client) TestRule(guildID, name, input string) (*control.RuleTestResult, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	body, err := json.Marshal(control.TestRuleRequest{
+		Guild: guildID,
+		Name:  name,
+		Input: input,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode failed: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.rulesTestURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", control.ErrRuleNotFound, name)
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("%w: %s", control.ErrRuleNotTestable, name)
+	default:
+		return nil, fmt.Errorf("rule test failed: status %d", resp.StatusCode)
+	}
+
+	var result control.RuleTestResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ClearWarnings deletes a member's warnings via the control API and returns
+// how many were removed. The client must be created with WithAuthToken;
+// otherwise the returned error wraps control.ErrUnauthorized.
+func (c *Client) ClearWarnings(guildID, userID string) (int, error) {
+	if c == nil {
+		return 0, fmt.Errorf("client is nil")
+	}
+
 	body, err := json.Marshal(map[string]string{
-		"name":  name,
-		"key":   key,
-		"value": value,
+		"guild_id": guildID,
+		"user_id":  userID,
 	})
+	if err != nil {
+		return 0, fmt.Errorf("encode failed: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.clearWarnURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return 0, fmt.Errorf("clear warnings failed: %w", control.ErrUnauthorized)
+	default:
+		return 0, fmt.Errorf("clear warnings failed: status %d", resp.StatusCode)
+	}
+
+	var result control.ClearWarningsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return result.Removed, nil
+}
+
+// Ban bans userID from guildID via the control API, deleting their messages
+// from the last deleteDays days. The client must be created with
+// WithAuthToken; otherwise the returned error wraps control.ErrUnauthorized.
+func (c *Client) Ban(guildID, userID, reason string, deleteDays int) error {
+	return c.moderate(c.banURL, "ban", userID, control.ModerationRequest{
+		GuildID:    guildID,
+		UserID:     userID,
+		Reason:     reason,
+		DeleteDays: deleteDays,
+	})
+}
+
+// Kick removes userID from guildID via the control API. The returned error
+// wraps control.ErrMemberNotFound if the user is not a member, or
+// control.ErrUnauthorized if the auth token is missing or wrong.
+func (c *Client) Kick(guildID, userID, reason string) error {
+	return c.moderate(c.kickURL, "kick", userID, control.ModerationRequest{
+		GuildID: guildID,
+		UserID:  userID,
+		Reason:  reason,
+	})
+}
+
+// Mute times userID out in guildID for duration via the control API. The
+// returned error wraps control.ErrMemberNotFound if the user is not a member,
+// or control.ErrUnauthorized if the auth token is missing or wrong.
+func (c *Client) Mute(guildID, userID string, duration time.Duration, reason string) error {
+	return c.moderate(c.muteURL, "mute", userID, control.ModerationRequest{
+		GuildID:  guildID,
+		UserID:   userID,
+		Reason:   reason,
+		Duration: duration.String(),
+	})
+}
+
+// CancelPunishment lifts userID's temporary punishment of type kind, such as
+// control.PunishmentMute, in guildID via the control API before it expires.
+// The returned error wraps control.ErrNoPunishment if none is in effect,
+// control.ErrMemberNotFound if the user is not a member, or
+// control.ErrUnauthorized if the auth token is missing or wrong.
+func (c *Client) CancelPunishment(guildID, userID, kind, reason string) error {
+	if c == nil {
+		return fmt.Errorf("client is nil")
+	}
+	return c.moderate(c.punishURL+"/cancel", "cancel", userID, control.CancelPunishmentRequest{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    kind,
+		Reason:  reason,
+	})
+}
+
+// moderate sends req, which acts on userID, to the moderation endpoint at
+// url. Errors the server explains, such as an invalid ID, include its
+// explanation.
+func (c *Client) moderate(url, action, userID string, req any) error {
+	if c == nil {
+		return fmt.Errorf("client is nil")
+	}
+
+	body, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("encode failed: %w", err)
 	}
 
-	resp, err := c.httpClient.Post(c.rulesSetURL, "application/json", bytes.NewReader(body))
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("connection failed: %w", err)
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s failed: %w", action, control.ErrUnauthorized)
+	case http.StatusNotFound:
+		return fmt.Errorf("%s failed: %w: %s", action, control.ErrMemberNotFound, userID)
+	case http.StatusConflict:
+		return fmt.Errorf("%s failed: %w", action, control.ErrNoPunishment)
+	case http.StatusBadRequest:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s failed: %s", action, strings.TrimSpace(strings.TrimPrefix(string(msg), "Bad request:")))
+	default:
+		return fmt.Errorf("%s failed: status %d", action, resp.StatusCode)
+	}
+}
+
+// Unban lifts the bans on userIDs in guildID via the control API, recording
+// reason in the guild's audit log. Users are unbanned independently; the
+// returned response reports which succeeded and which failed. At most
+// control.MaxUnbanUsers users can be unbanned per call, and the client must
+// be created with WithAuthToken. An error is returned only if the request as
+// a whole could not be completed.
+func (c *Client) Unban(guildID string, userIDs []string, reason string) (*control.UnbanResponse, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	body, err := json.Marshal(control.UnbanRequest{
+		GuildID: guildID,
+		UserIDs: userIDs,
+		Reason:  reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode failed: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.unbanURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("unban failed: %w", control.ErrUnauthorized)
+	default:
+		return nil, fmt.Errorf("unban failed: status %d", resp.StatusCode)
+	}
+
+	var result control.UnbanResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ListCommands retrieves the bot's registered commands and whether each is
+// enabled from the control API.
+func (c *Client) ListCommands() ([]control.CommandState, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	resp, err := c.httpClient.Get(c.commandsURL)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("rule update failed: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
 	}
 
-	return nil
+	var states []control.CommandState
+	if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return states, nil
+}
+
+// SetCommandEnabled enables or disables a command at runtime via the control
+// API. The returned error wraps control.ErrCommandNotFound if the bot has no
+// command with that name, or control.ErrUnauthorized if the auth token is
+// missing or wrong.
+func (c *Client) SetCommandEnabled(name string, enabled bool) error {
+	if c == nil {
+		return fmt.Errorf("client is nil")
+	}
+
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+
+	resp, err := c.httpClient.Post(c.commandsURL+"/"+url.PathEscape(name)+"/"+action, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", control.ErrCommandNotFound, name)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("command %s failed: %w", action, control.ErrUnauthorized)
+	default:
+		return fmt.Errorf("command %s failed: status %d", action, resp.StatusCode)
+	}
+}
+
+// ReloadCommands has the bot rebuild its commands and sync them with Discord
+// via the control API, and returns how Discord's commands changed. The
+// client must be created with WithAuthToken; otherwise the returned error
+// wraps control.ErrUnauthorized. Errors the bot explains, such as a failed
+// sync, include its explanation.
+func (c *Client) ReloadCommands() (*control.CommandReloadResult, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	resp, err := c.httpClient.Post(c.commandsURL+"/reload", "application/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("reload failed: %w", control.ErrUnauthorized)
+	case http.StatusInternalServerError:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("reload failed: %s", strings.TrimSpace(strings.TrimPrefix(string(msg), "Failed to reload commands:")))
+	default:
+		return nil, fmt.Errorf("reload failed: status %d", resp.StatusCode)
+	}
+
+	var result control.CommandReloadResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ListPunishments retrieves the temporary punishments in effect in a guild,
+// or in every guild when guildID is empty, from the control API.
+func (c *Client) ListPunishments(guildID string) ([]control.Punishment, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	punishURL := c.punishURL
+	if guildID != "" {
+		punishURL += "?" + url.Values{"guild": {guildID}}.Encode()
+	}
+
+	resp, err := c.httpClient.Get(punishURL)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s", strings.TrimSpace(strings.TrimPrefix(string(msg), "Bad request:")))
+	default:
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var punishments []control.Punishment
+	if err := json.NewDecoder(resp.Body).Decode(&punishments); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return punishments, nil
+}
+
+// SetMaintenance turns the bot's maintenance mode on or off via the control
+// API and returns the resulting state. The client must be created with
+// WithAuthToken; otherwise the returned error wraps control.ErrUnauthorized.
+func (c *Client) SetMaintenance(enabled bool) (*control.MaintenanceState, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+
+	resp, err := c.httpClient.Post(c.maintURL+"/"+action, "application/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("maintenance %s failed: %w", action, control.ErrUnauthorized)
+	default:
+		return nil, fmt.Errorf("maintenance %s failed: status %d", action, resp.StatusCode)
+	}
+
+	var state control.MaintenanceState
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return &state, nil
 }
@@ -1,8 +1,12 @@
 package api_test
 
 import (
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +15,7 @@ import (
 	"time"
 
 	"jamesbot/internal/api"
+	"jamesbot/internal/control"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -275,6 +280,52 @@ func Test_NewClient_PrecomputedURLs_EmptyEndpoint(t *testing.T) {
 		"Client with empty endpoint should still have 10 second timeout")
 }
 
+// =============================================================================
+// Health Tests
+// =============================================================================
+
+func Test_Health(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{name: "healthy", status: http.StatusOK, body: `{"status":"ok"}`},
+		{name: "not a control server", status: http.StatusNotFound, body: "404 page not found", wantErr: "unexpected status: 404"},
+		{name: "not JSON", status: http.StatusOK, body: "<html></html>", wantErr: "decode failed"},
+		{name: "unexpected status", status: http.StatusOK, body: `{"status":"starting"}`, wantErr: `unexpected health status "starting"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/health", r.URL.Path)
+				assert.Equal(t, http.MethodGet, r.Method)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			err := api.NewClient(server.URL).Health()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_Health_ServerDown(t *testing.T) {
+	err := api.NewClient("http://127.0.0.1:59999").Health()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection failed")
+}
+
 // =============================================================================
 // GetStats Tests
 // =============================================================================
@@ -403,6 +454,58 @@ func Test_GetStats_InvalidJSON(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// TopCommands Tests
+// =============================================================================
+
+func Test_TopCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []control.CommandStats
+		wantErr bool
+	}{
+		{
+			name:   "ranked commands",
+			status: http.StatusOK,
+			body:   `[{"name":"ban","executions":7},{"name":"kick","executions":3}]`,
+			want:   []control.CommandStats{{Name: "ban", Executions: 7}, {Name: "kick", Executions: 3}},
+		},
+		{name: "bad request", status: http.StatusBadRequest, body: "Bad request", wantErr: true},
+		{name: "invalid JSON", status: http.StatusOK, body: `[`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/stats/commands/top", r.URL.Path)
+				assert.Equal(t, "5", r.URL.Query().Get("n"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			got, err := api.NewClient(server.URL).TopCommands(5)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_TopCommands_ServerDown(t *testing.T) {
+	_, err := api.NewClient("http://127.0.0.1:59997").TopCommands(5)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection failed")
+}
+
 // =============================================================================
 // ListRules Tests
 // =============================================================================
@@ -541,6 +644,658 @@ func Test_ListRules_InvalidJSON(t *testing.T) {
 
 // =============================================================================
 // SetRule Tests
+// =============================================================================
+// Guild Rule Tests
+// =============================================================================
+
+func Test_ListGuildRules_SendsGuildQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		guildID   string
+		wantQuery string
+	}{
+		{name: "guild", guildID: "123456789012345678", wantQuery: "guild=123456789012345678"},
+		{name: "guild is escaped", guildID: "a b&c", wantQuery: "guild=a+b%26c"},
+		{name: "empty guild lists global rules", guildID: "", wantQuery: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rules", r.URL.Path)
+				gotQuery = r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(rulesResponse()))
+			})
+			defer server.Close()
+
+			rules, err := api.NewClient(server.URL).ListGuildRules(tt.guildID)
+
+			require.NoError(t, err)
+			assert.Len(t, rules, 2)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+		})
+	}
+}
+
+func Test_GetOverview(t *testing.T) {
+	tests := []struct {
+		name      string
+		guildID   string
+		status    int
+		body      string
+		wantQuery string
+		wantErr   string
+	}{
+		{
+			name:   "stats and rules",
+			status: http.StatusOK,
+			body:   `{"stats": ` + statsResponse() + `, "rules": ` + rulesResponse() + `}`,
+		},
+		{
+			name:      "guild is sent as a query",
+			guildID:   "a b&c",
+			status:    http.StatusOK,
+			body:      `{"stats": ` + statsResponse() + `, "rules": ` + rulesResponse() + `}`,
+			wantQuery: "guild=a+b%26c",
+		},
+		{
+			name:    "unexpected status",
+			status:  http.StatusInternalServerError,
+			body:    "Internal server error",
+			wantErr: "unexpected status: 500",
+		},
+		{
+			name:    "invalid JSON",
+			status:  http.StatusOK,
+			body:    `{"stats": `,
+			wantErr: "decode failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/overview", r.URL.Path)
+				gotQuery = r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			overview, err := api.NewClient(server.URL).GetGuildOverview(tt.guildID)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, overview.Stats)
+			assert.Equal(t, int64(42), overview.Stats.CommandsExecuted)
+			assert.Len(t, overview.Rules, 2)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+		})
+	}
+}
+
+func Test_GetOverview_NilClient(t *testing.T) {
+	var client *api.Client
+	_, err := client.GetOverview()
+	assert.Error(t, err)
+}
+
+func Test_SetGuildRule_SendsGuild(t *testing.T) {
+	var body map[string]string
+	server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rules/set", r.URL.Path)
+		body = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"status": "ok"}`))
+	})
+	defer server.Close()
+
+	client := api.NewClient(server.URL)
+
+	require.NoError(t, client.SetGuildRule("guild-1", "anti-spam", "threshold", "3"))
+	assert.NotEmpty(t, body["idempotency_key"], "updates should carry an idempotency key")
+	delete(body, "idempotency_key")
+	assert.Equal(t, map[string]string{"guild": "guild-1", "name": "anti-spam", "key": "threshold", "value": "3"}, body)
+
+	require.NoError(t, client.SetRule("anti-spam", "threshold", "5"))
+	assert.NotContains(t, body, "guild", "global settings should omit the guild")
+}
+
+func Test_SetGuildRule_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		retries      int
+		statuses     []int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "retries reuse the idempotency key",
+			retries:      2,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusOK},
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after the configured retries",
+			retries:      1,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 2,
+			wantErr:      true,
+		},
+		{
+			name:         "no retries by default",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "client errors are not retried",
+			retries:      2,
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				var req control.SetRuleRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				keys = append(keys, req.IdempotencyKey)
+				w.WriteHeader(tt.statuses[len(keys)-1])
+			})
+			defer server.Close()
+
+			err := api.NewClient(server.URL, api.WithRetries(tt.retries)).SetRule("anti-spam", "threshold", "3")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			require.Len(t, keys, tt.wantAttempts)
+			for _, key := range keys {
+				assert.Equal(t, keys[0], key, "every attempt should send the same key")
+			}
+		})
+	}
+}
+
+func Test_SetGuildRule_IntentNotRequested(t *testing.T) {
+	server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+		err := fmt.Errorf("%w: it needs the Message Content intent", control.ErrIntentNotRequested)
+		http.Error(w, fmt.Sprintf("Failed to set rule: %v", err), http.StatusConflict)
+	})
+	defer server.Close()
+
+	err := api.NewClient(server.URL).SetRule("word-filter", "enabled", "true")
+
+	require.ErrorIs(t, err, control.ErrIntentNotRequested)
+	assert.Equal(t, "rule update failed: "+control.ErrIntentNotRequested.Error()+": it needs the Message Content intent", err.Error(),
+		"the server's explanation should be passed on once")
+}
+
+func Test_SetGuildRule_NotSaved(t *testing.T) {
+	server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+		warning := fmt.Errorf("%w: read-only file system", control.ErrRuleNotSaved).Error()
+		_ = json.NewEncoder(w).Encode(control.SetRuleResponse{Status: "ok", Warning: warning})
+	})
+	defer server.Close()
+
+	err := api.NewClient(server.URL).SetRule("anti-spam", "enabled", "true")
+
+	require.ErrorIs(t, err, control.ErrRuleNotSaved)
+	assert.Equal(t, control.ErrRuleNotSaved.Error()+": read-only file system", err.Error(),
+		"the server's warning should be passed on once")
+}
+
+func Test_SetGuildRule_KeyPerCall(t *testing.T) {
+	var keys []string
+	server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req control.SetRuleRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		keys = append(keys, req.IdempotencyKey)
+	})
+	defer server.Close()
+
+	client := api.NewClient(server.URL)
+	require.NoError(t, client.SetRule("anti-spam", "threshold", "3"))
+	require.NoError(t, client.SetRule("anti-spam", "threshold", "3"))
+
+	require.Len(t, keys, 2)
+	assert.NotEqual(t, keys[0], keys[1], "separate calls should not share a key")
+}
+
+// =============================================================================
+// Command Toggle Tests
+// =============================================================================
+
+func Test_ListCommands(t *testing.T) {
+	server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/commands", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = w.Write([]byte(`[{"name":"ban","description":"Ban a member","enabled":false}]`))
+	})
+	defer server.Close()
+
+	states, err := api.NewClient(server.URL).ListCommands()
+
+	require.NoError(t, err)
+	assert.Equal(t, []control.CommandState{{Name: "ban", Description: "Ban a member", Enabled: false}}, states)
+}
+
+func Test_SetCommandEnabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   string
+		enabled   bool
+		status    int
+		wantPath  string
+		wantErrIs error
+		wantErr   bool
+	}{
+		{name: "disable", command: "ban", status: http.StatusOK, wantPath: "/commands/ban/disable"},
+		{name: "enable", command: "ban", enabled: true, status: http.StatusOK, wantPath: "/commands/ban/enable"},
+		{name: "name is escaped", command: "a/b", status: http.StatusOK, wantPath: "/commands/a%2Fb/disable"},
+		{name: "unknown command", command: "nope", status: http.StatusNotFound, wantPath: "/commands/nope/disable", wantErr: true, wantErrIs: control.ErrCommandNotFound},
+		{name: "server error", command: "ban", status: http.StatusInternalServerError, wantPath: "/commands/ban/disable", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, tt.wantPath, r.URL.EscapedPath())
+				w.WriteHeader(tt.status)
+			})
+			defer server.Close()
+
+			err := api.NewClient(server.URL).SetCommandEnabled(tt.command, tt.enabled)
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if tt.wantErrIs != nil {
+				assert.True(t, errors.Is(err, tt.wantErrIs), "error should wrap %v, got %v", tt.wantErrIs, err)
+			}
+		})
+	}
+}
+
+func Test_SetMaintenance(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		status   int
+		body     string
+		wantPath string
+		want     *control.MaintenanceState
+		wantErr  bool
+	}{
+		{name: "enable", enabled: true, status: http.StatusOK, body: `{"enabled":true,"since":1700000000}`,
+			wantPath: "/maintenance/enable", want: &control.MaintenanceState{Enabled: true, Since: 1700000000}},
+		{name: "disable", status: http.StatusOK, body: `{"enabled":false}`,
+			wantPath: "/maintenance/disable", want: &control.MaintenanceState{}},
+		{name: "not supported", enabled: true, status: http.StatusNotImplemented, wantPath: "/maintenance/enable", wantErr: true},
+		{name: "invalid response", enabled: true, status: http.StatusOK, body: "{", wantPath: "/maintenance/enable", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, tt.wantPath, r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			got, err := api.NewClient(server.URL).SetMaintenance(tt.enabled)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ReloadCommands(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		want         *control.CommandReloadResult
+		wantErrIs    error
+		wantErrMatch string
+	}{
+		{name: "reloaded", status: http.StatusOK, body: `{"created":["poll"],"updated":null,"deleted":["echo"],"unchanged":3}`,
+			want: &control.CommandReloadResult{Created: []string{"poll"}, Deleted: []string{"echo"}, Unchanged: 3}},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErrIs: control.ErrUnauthorized},
+		{name: "no token configured", status: http.StatusForbidden, wantErrIs: control.ErrUnauthorized},
+		{name: "reload fails", status: http.StatusInternalServerError, body: "Failed to reload commands: discord unavailable\n",
+			wantErrMatch: "reload failed: discord unavailable"},
+		{name: "not supported", status: http.StatusNotImplemented, wantErrMatch: "status 501"},
+		{name: "invalid response", status: http.StatusOK, body: "{", wantErrMatch: "decode failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/commands/reload", r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			got, err := api.NewClient(server.URL, api.WithAuthToken("secret")).ReloadCommands()
+
+			switch {
+			case tt.wantErrIs != nil:
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			case tt.wantErrMatch != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrMatch)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_TestRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		want      *control.RuleTestResult
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:   "match",
+			status: http.StatusOK,
+			body:   `{"name":"word-filter","guild":"g1","enabled":true,"matched":true,"match":"darn","action":"delete"}`,
+			want:   &control.RuleTestResult{Name: "word-filter", Guild: "g1", Enabled: true, Matched: true, Match: "darn", Action: "delete"},
+		},
+		{name: "unknown rule", status: http.StatusNotFound, wantErr: true, wantErrIs: control.ErrRuleNotFound},
+		{name: "not a content rule", status: http.StatusBadRequest, wantErr: true, wantErrIs: control.ErrRuleNotTestable},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: true},
+		{name: "invalid JSON", status: http.StatusOK, body: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/rules/test", r.URL.Path)
+				var req control.TestRuleRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, control.TestRuleRequest{Guild: "g1", Name: "word-filter", Input: "well darn it"}, req)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			got, err := api.NewClient(server.URL).TestRule("g1", "word-filter", "well darn it")
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			require.Error(t, err)
+			if tt.wantErrIs != nil {
+				assert.True(t, errors.Is(err, tt.wantErrIs), "error should wrap %v, got %v", tt.wantErrIs, err)
+			}
+		})
+	}
+}
+
+func Test_Unban(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *control.UnbanResponse
+		wantErr bool
+	}{
+		{
+			name:   "per-user results",
+			status: http.StatusOK,
+			body:   `{"succeeded":1,"failed":1,"results":[{"user_id":"1"},{"user_id":"2","error":"user is not banned"}]}`,
+			want: &control.UnbanResponse{
+				Succeeded: 1,
+				Failed:    1,
+				Results:   []control.UnbanResult{{UserID: "1"}, {UserID: "2", Error: "user is not banned"}},
+			},
+		},
+		{name: "not implemented", status: http.StatusNotImplemented, wantErr: true},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: true},
+		{name: "bad request", status: http.StatusBadRequest, wantErr: true},
+		{name: "invalid JSON", status: http.StatusOK, body: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/moderation/unban", r.URL.Path)
+				var req control.UnbanRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, control.UnbanRequest{GuildID: "g1", UserIDs: []string{"1", "2"}, Reason: "amnesty"}, req)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			got, err := api.NewClient(server.URL).Unban("g1", []string{"1", "2"}, "amnesty")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Moderation(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		call      func(c *api.Client) error
+		want      control.ModerationRequest
+		status    int
+		body      string
+		wantErrIs error
+		wantErr   string
+	}{
+		{
+			name:   "ban",
+			path:   "/moderation/ban",
+			call:   func(c *api.Client) error { return c.Ban("111", "222", "spam", 1) },
+			want:   control.ModerationRequest{GuildID: "111", UserID: "222", Reason: "spam", DeleteDays: 1},
+			status: http.StatusOK,
+		},
+		{
+			name:   "kick",
+			path:   "/moderation/kick",
+			call:   func(c *api.Client) error { return c.Kick("111", "222", "rude") },
+			want:   control.ModerationRequest{GuildID: "111", UserID: "222", Reason: "rude"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "mute",
+			path:   "/moderation/mute",
+			call:   func(c *api.Client) error { return c.Mute("111", "222", 90*time.Minute, "") },
+			want:   control.ModerationRequest{GuildID: "111", UserID: "222", Duration: "1h30m0s"},
+			status: http.StatusOK,
+		},
+		{
+			name:      "not a member",
+			path:      "/moderation/kick",
+			call:      func(c *api.Client) error { return c.Kick("111", "222", "") },
+			want:      control.ModerationRequest{GuildID: "111", UserID: "222"},
+			status:    http.StatusNotFound,
+			wantErrIs: control.ErrMemberNotFound,
+		},
+		{
+			name:      "unauthorized",
+			path:      "/moderation/ban",
+			call:      func(c *api.Client) error { return c.Ban("111", "222", "", 0) },
+			want:      control.ModerationRequest{GuildID: "111", UserID: "222"},
+			status:    http.StatusForbidden,
+			wantErrIs: control.ErrUnauthorized,
+		},
+		{
+			name:    "bad request explains",
+			path:    "/moderation/mute",
+			call:    func(c *api.Client) error { return c.Mute("111", "222", time.Second, "") },
+			want:    control.ModerationRequest{GuildID: "111", UserID: "222", Duration: "1s"},
+			status:  http.StatusBadRequest,
+			body:    "Bad request: duration must be between 1m0s and 672h0m0s\n",
+			wantErr: "mute failed: duration must be between 1m0s and 672h0m0s",
+		},
+		{
+			name:    "server error",
+			path:    "/moderation/ban",
+			call:    func(c *api.Client) error { return c.Ban("111", "222", "", 0) },
+			want:    control.ModerationRequest{GuildID: "111", UserID: "222"},
+			status:  http.StatusInternalServerError,
+			wantErr: "status 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, tt.path, r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				var req control.ModerationRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, tt.want, req)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			err := tt.call(api.NewClient(server.URL, api.WithAuthToken("secret")))
+
+			switch {
+			case tt.wantErrIs != nil:
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			case tt.wantErr != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_CancelPunishment(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantErrIs error
+		wantErr   string
+	}{
+		{name: "lifted", status: http.StatusOK},
+		{name: "not in effect", status: http.StatusConflict, wantErrIs: control.ErrNoPunishment},
+		{name: "not a member", status: http.StatusNotFound, wantErrIs: control.ErrMemberNotFound},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErrIs: control.ErrUnauthorized},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: "cancel failed: status 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/punishments/cancel", r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				var req control.CancelPunishmentRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, control.CancelPunishmentRequest{GuildID: "111", UserID: "222", Type: "mute", Reason: "appeal"}, req)
+				w.WriteHeader(tt.status)
+			})
+			defer server.Close()
+
+			err := api.NewClient(server.URL, api.WithAuthToken("secret")).CancelPunishment("111", "222", control.PunishmentMute, "appeal")
+
+			switch {
+			case tt.wantErrIs != nil:
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			case tt.wantErr != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_CancelPunishment_NilClient(t *testing.T) {
+	var client *api.Client
+
+	assert.Error(t, client.CancelPunishment("111", "222", control.PunishmentMute, ""))
+}
+
+func Test_WithAuthToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []api.Option
+		wantH string
+	}{
+		{name: "token sent", opts: []api.Option{api.WithAuthToken("secret")}, wantH: "Bearer secret"},
+		{name: "empty token sends nothing", opts: []api.Option{api.WithAuthToken("")}, wantH: ""},
+		{name: "no option sends nothing", wantH: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+				_, _ = w.Write([]byte(`{}`))
+			})
+			defer server.Close()
+
+			_, err := api.NewClient(server.URL, tt.opts...).GetStats()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantH, got)
+		})
+	}
+}
+
+func Test_Unban_ServerDown(t *testing.T) {
+	_, err := api.NewClient("http://127.0.0.1:1").Unban("g1", []string{"1"}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection failed")
+}
+
 // =============================================================================
 
 func Test_SetRule_SuccessfulUpdate(t *testing.T) {
@@ -802,6 +1557,98 @@ func Test_ListRules_ManyRules(t *testing.T) {
 	assert.Len(t, result, 100)
 }
 
+// =============================================================================
+// Connection Reuse Tests
+// =============================================================================
+
+// newConnCountingServer starts a stats server that counts new TCP connections.
+func newConnCountingServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(statsResponse()))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server, &conns
+}
+
+func Test_Client_ReusesConnection(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []api.Option
+		wantConns int32
+	}{
+		{name: "default keeps connections alive", wantConns: 1},
+		{name: "tuned idle pool", opts: []api.Option{api.WithMaxIdleConns(1), api.WithIdleConnTimeout(time.Minute)}, wantConns: 1},
+		{name: "zero idle conns disables reuse", opts: []api.Option{api.WithMaxIdleConns(0)}, wantConns: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, conns := newConnCountingServer(t)
+			client := api.NewClient(server.URL, tt.opts...)
+
+			for i := 0; i < 3; i++ {
+				_, err := client.GetStats()
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantConns, atomic.LoadInt32(conns))
+		})
+	}
+}
+
+// =============================================================================
+// TLS Tests
+// =============================================================================
+
+func Test_Client_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(statsResponse()))
+	}))
+	t.Cleanup(server.Close)
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(server.Certificate())
+
+	tests := []struct {
+		name    string
+		opts    []api.Option
+		wantErr bool
+	}{
+		{name: "unknown CA is rejected", wantErr: true},
+		{name: "custom CA is trusted", opts: []api.Option{api.WithRootCAs(trusted)}},
+		{name: "verification can be skipped", opts: []api.Option{api.WithInsecureSkipVerify()}},
+		{name: "other CA is rejected", opts: []api.Option{api.WithRootCAs(x509.NewCertPool())}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := api.NewClient(server.URL, tt.opts...)
+
+			stats, err := client.GetStats()
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "certificate")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), stats.CommandsExecuted)
+		})
+	}
+}
+
 // =============================================================================
 // Concurrent Request Tests
 // =============================================================================
@@ -906,6 +1753,234 @@ func Test_SetRule_NilClient(t *testing.T) {
 	assert.Error(t, err, "SetRule on nil client should return error")
 }
 
+// =============================================================================
+// SetRules Tests
+// =============================================================================
+
+func Test_SetRules_Success(t *testing.T) {
+	var received []control.SetRuleRequest
+
+	server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rules/batch", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"succeeded":1,"failed":1,"results":[` +
+			`{"name":"anti-spam","key":"threshold","value":"5"},` +
+			`{"name":"bogus","key":"k","value":"v","error":"rule not found"}]}`))
+	})
+	defer server.Close()
+
+	reqs := []control.SetRuleRequest{
+		{Name: "anti-spam", Key: "threshold", Value: "5"},
+		{Name: "bogus", Key: "k", Value: "v"},
+	}
+	result, err := api.NewClient(server.URL).SetRules(reqs)
+
+	require.NoError(t, err)
+	assert.Equal(t, reqs, received)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Results, 2)
+	assert.Empty(t, result.Results[0].Error)
+	assert.Equal(t, "rule not found", result.Results[1].Error)
+}
+
+func Test_SetRules_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    string
+	}{
+		{name: "bad request", statusCode: http.StatusBadRequest, wantErr: "status 400"},
+		{name: "server error", statusCode: http.StatusInternalServerError, wantErr: "status 500"},
+		{name: "invalid json", statusCode: http.StatusOK, body: "nope", wantErr: "decode failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			_, err := api.NewClient(server.URL).SetRules([]control.SetRuleRequest{{Name: "a", Key: "b"}})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_SetRules_ServerDown(t *testing.T) {
+	_, err := api.NewClient("http://127.0.0.1:59997").SetRules([]control.SetRuleRequest{{Name: "a", Key: "b"}})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection failed")
+}
+
+func Test_SetRules_NilClient(t *testing.T) {
+	var client *api.Client
+
+	_, err := client.SetRules(nil)
+	assert.Error(t, err)
+}
+
+// =============================================================================
+// ClearWarnings Tests
+// =============================================================================
+
+func Test_ClearWarnings_Success(t *testing.T) {
+	var received struct {
+		GuildID string `json:"guild_id"`
+		UserID  string `json:"user_id"`
+	}
+
+	server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/warnings/clear", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"removed": 4}`))
+	})
+	defer server.Close()
+
+	removed, err := api.NewClient(server.URL).ClearWarnings("g1", "u1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, removed)
+	assert.Equal(t, "g1", received.GuildID)
+	assert.Equal(t, "u1", received.UserID)
+}
+
+func Test_ClearWarnings_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    string
+	}{
+		{name: "bad request", statusCode: http.StatusBadRequest, wantErr: "status 400"},
+		{name: "server error", statusCode: http.StatusInternalServerError, wantErr: "status 500"},
+		{name: "invalid json", statusCode: http.StatusOK, body: "nope", wantErr: "decode failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			_, err := api.NewClient(server.URL).ClearWarnings("g1", "u1")
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_ClearWarnings_ServerDown(t *testing.T) {
+	_, err := api.NewClient("http://127.0.0.1:59997").ClearWarnings("g1", "u1")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection failed")
+}
+
+func Test_ClearWarnings_NilClient(t *testing.T) {
+	var client *api.Client
+
+	_, err := client.ClearWarnings("g1", "u1")
+	assert.Error(t, err)
+}
+
+// =============================================================================
+// ListPunishments Tests
+// =============================================================================
+
+func Test_ListPunishments(t *testing.T) {
+	tests := []struct {
+		name      string
+		guildID   string
+		wantQuery string
+	}{
+		{name: "every guild", wantQuery: ""},
+		{name: "one guild", guildID: "111", wantQuery: "guild=111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/punishments", r.URL.Path)
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, tt.wantQuery, r.URL.RawQuery)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[{"guild_id":"111","user_id":"1","type":"mute","reason":"spam","expires_at":"2024-01-01T12:00:00Z"}]`))
+			})
+			defer server.Close()
+
+			punishments, err := api.NewClient(server.URL).ListPunishments(tt.guildID)
+
+			require.NoError(t, err)
+			assert.Equal(t, []control.Punishment{{
+				GuildID:   "111",
+				UserID:    "1",
+				Type:      control.PunishmentMute,
+				Reason:    "spam",
+				ExpiresAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			}}, punishments)
+		})
+	}
+}
+
+func Test_ListPunishments_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    string
+	}{
+		{name: "bad request", statusCode: http.StatusBadRequest, body: "Bad request: guild must be a Discord ID\n", wantErr: "guild must be a Discord ID"},
+		{name: "not implemented", statusCode: http.StatusNotImplemented, wantErr: "status: 501"},
+		{name: "invalid json", statusCode: http.StatusOK, body: "nope", wantErr: "decode failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			_, err := api.NewClient(server.URL).ListPunishments("")
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_ListPunishments_ServerDown(t *testing.T) {
+	_, err := api.NewClient("http://127.0.0.1:59997").ListPunishments("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection failed")
+}
+
+func Test_ListPunishments_NilClient(t *testing.T) {
+	var client *api.Client
+
+	_, err := client.ListPunishments("")
+	assert.Error(t, err)
+}
+
 // =============================================================================
 // Benchmark Tests
 // =============================================================================
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+)
+
+// Default transport tuning for the control API client. Every request goes to
+// the same host, so idle connections are kept for that host rather than
+// capped at the net/http per-host default of two.
+const (
+	DefaultMaxIdleConns    = 4
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// HealthTimeout bounds how long Client.Health waits for the control server.
+const HealthTimeout = 2 * time.Second
+
+// Retry tuning for calls that are safe to repeat. DefaultRetries is how many
+// times the rules set command retries an update; retries wait RetryBackoff,
+// doubling after each attempt.
+const (
+	DefaultRetries = 2
+	RetryBackoff   = 250 * time.Millisecond
+)
+
+// Option is a functional option for configuring the Client.
+type Option func(*Client)
+
+// WithMaxIdleConns sets how many idle keep-alive connections the client keeps
+// open to the control API. Zero disables connection reuse.
+func WithMaxIdleConns(n int) Option {
+	return func(c *Client) {
+		if n <= 0 {
+			c.transport.DisableKeepAlives = true
+			return
+		}
+		c.transport.MaxIdleConns = n
+		c.transport.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle keep-alive connection is kept
+// before it is closed.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.transport.IdleConnTimeout = d
+	}
+}
+
+// WithRetries makes calls that are safe to repeat, such as SetGuildRule,
+// retry up to n times when a request may not have reached the control API or
+// its response was lost, as when it times out. Zero disables retries.
+func WithRetries(n int) Option {
+	return func(c *Client) {
+		c.retries = max(n, 0)
+	}
+}
+
+// WithRootCAs makes the client trust only the certificate authorities in pool
+// when the endpoint is HTTPS, such as the CA that signed a control API
+// certificate.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		c.tlsConfig().RootCAs = pool
+	}
+}
+
+// WithInsecureSkipVerify makes the client accept any certificate from an
+// HTTPS endpoint. It is meant for self-signed certificates in development
+// and leaves the connection open to interception.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		c.tlsConfig().InsecureSkipVerify = true
+	}
+}
+
+// WithAuthToken sends token as a bearer token with every request, as the
+// control API's moderation, warning, command, and maintenance endpoints
+// require. An empty token sends nothing.
+func WithAuthToken(token string) Option {
+	return func(c *Client) {
+		if token == "" {
+			return
+		}
+		c.httpClient.Transport = &authTransport{base: c.transport, token: token}
+	}
+}
+
+// authTransport adds a bearer token to each request before passing it to base.
+type authTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// tlsConfig returns the transport's TLS config, creating it if needed.
+func (c *Client) tlsConfig() *tls.Config {
+	if c.transport.TLSClientConfig == nil {
+		c.transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return c.transport.TLSClientConfig
+}
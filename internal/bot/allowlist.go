@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"jamesbot/internal/command"
+	"jamesbot/internal/i18n"
+	"jamesbot/internal/middleware"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// guildAction is what the bot does in a guild, as decided by
+// discord.allowed_guilds.
+type guildAction int
+
+const (
+	// guildServe runs commands in the guild as usual.
+	guildServe guildAction = iota
+	// guildIgnore stays in the guild but does not run its commands.
+	guildIgnore
+	// guildLeave leaves the guild, and ignores its commands until it has.
+	guildLeave
+)
+
+// String returns the action's name, as logged.
+func (a guildAction) String() string {
+	switch a {
+	case guildIgnore:
+		return "ignore"
+	case guildLeave:
+		return "leave"
+	default:
+		return "serve"
+	}
+}
+
+// guildAllowlist limits the guilds a private bot operates in, so it cannot
+// be used from servers it was added to without permission.
+type guildAllowlist struct {
+	allowed map[string]bool
+	// leave makes the bot leave other guilds instead of ignoring them.
+	leave bool
+}
+
+// newGuildAllowlist creates an allowlist of guildIDs. Other guilds are left
+// if leave is set and ignored otherwise. It returns nil, which serves every
+// guild, when guildIDs is empty.
+func newGuildAllowlist(guildIDs []string, leave bool) *guildAllowlist {
+	if len(guildIDs) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(guildIDs))
+	for _, id := range guildIDs {
+		allowed[id] = true
+	}
+	return &guildAllowlist{allowed: allowed, leave: leave}
+}
+
+// decide returns what the bot does in guildID. Commands outside any guild,
+// such as in DMs, are always served.
+func (a *guildAllowlist) decide(guildID string) guildAction {
+	switch {
+	case a == nil, guildID == "", a.allowed[guildID]:
+		return guildServe
+	case a.leave:
+		return guildLeave
+	default:
+		return guildIgnore
+	}
+}
+
+// handleGuildCreate leaves, or warns about, a guild the bot was found in that
+// is not on the allowlist. GuildCreate is sent for every guild on connecting
+// and whenever the bot is added to another.
+func (b *Bot) handleGuildCreate(s *discordgo.Session, e *discordgo.GuildCreate) {
+	if e.Guild == nil {
+		return
+	}
+
+	switch b.allowlist.decide(e.ID) {
+	case guildIgnore:
+		b.logger.Warn().
+			Str("guild_id", e.ID).
+			Str("guild_name", e.Name).
+			Msg("ignoring commands from guild not in discord.allowed_guilds")
+	case guildLeave:
+		if err := s.GuildLeave(e.ID); err != nil {
+			b.logger.Error().
+				Err(err).
+				Str("guild_id", e.ID).
+				Str("guild_name", e.Name).
+				Msg("failed to leave guild not in discord.allowed_guilds; ignoring its commands")
+			return
+		}
+		b.logger.Warn().
+			Str("guild_id", e.ID).
+			Str("guild_name", e.Name).
+			Msg("left guild not in discord.allowed_guilds")
+	}
+}
+
+// guildGuard returns a middleware that stops commands from guilds the bot
+// does not serve, returning middleware.ErrCommandBlocked. Slash commands get
+// an ephemeral notice; text commands are ignored without a reply.
+func (b *Bot) guildGuard() middleware.Middleware {
+	return func(next middleware.HandlerFunc) middleware.HandlerFunc {
+		if b.allowlist == nil {
+			return next
+		}
+		return func(ctx *command.Context) error {
+			if b.allowlist.decide(ctx.GuildID()) == guildServe {
+				return next(ctx)
+			}
+			if ctx.Message != nil {
+				return middleware.ErrCommandBlocked
+			}
+			return middleware.Block(ctx, ctx.T(i18n.MsgGuildNotAllowed))
+		}
+	}
+}
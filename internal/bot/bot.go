@@ -4,14 +4,20 @@ package bot
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"jamesbot/internal/buildinfo"
 	"jamesbot/internal/command"
 	"jamesbot/internal/config"
 	"jamesbot/internal/control"
 	"jamesbot/internal/handler"
+	"jamesbot/internal/metrics"
 	"jamesbot/internal/middleware"
+	"jamesbot/internal/rules"
+	"jamesbot/internal/warnings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/zerolog"
@@ -22,32 +28,111 @@ import (
 type Bot struct {
 	session     *discordgo.Session
 	registry    *command.Registry
+	components  *command.ComponentRegistry
+	rules       *rules.Set
+	warnings    *warnings.Store
 	config      *config.Config
 	logger      zerolog.Logger
 	middlewares []middleware.Middleware
 
+	// rulesMu serializes rule changes, so each is checked against the
+	// intents of the settings it is applied to.
+	rulesMu sync.Mutex
+
+	// rulesLoadErr is why rules.file could not be loaded at startup, or nil.
+	// While it is set the bot runs on default settings and leaves the file
+	// alone, rather than overwrite what is in it.
+	rulesLoadErr error
+
+	// rulesSaveErr is why the last save to rules.file failed, or nil.
+	// Guarded by rulesMu.
+	rulesSaveErr error
+
+	// errorHandler tells users about failed commands; nil uses the handler's default.
+	errorHandler handler.ErrorHandler
+
+	// commandFlags records commands disabled at runtime through the control API.
+	commandFlags *middleware.CommandFlags
+
+	// maintenance records whether the bot was put into maintenance mode
+	// through the control API.
+	maintenance *middleware.Maintenance
+
+	// allowlist limits the guilds the bot operates in; nil when
+	// discord.allowed_guilds is empty.
+	allowlist *guildAllowlist
+
 	interactionHandler *handler.InteractionHandler
 	readyHandler       *handler.ReadyHandler
+	messageHandler     *handler.MessageHandler
+	memberHandler      *handler.MemberHandler
+
+	// textHandler runs text commands starting with the prefix in the
+	// settings of the guild they are sent in.
+	textHandler *handler.TextCommandHandler
+
+	// pool runs commands while the bot is started.
+	pool *handler.WorkerPool
+
+	// members caches guild members fetched by commands.
+	members *command.MemberCache
+
+	// snipes remembers recently deleted messages for the snipe command; nil
+	// when snipe.retention is zero.
+	snipes *command.SnipeCache
+
+	// guilds tracks which guilds are available, so Start can wait for the
+	// one commands are registered in.
+	guilds           *guildAvailability
+	guildWaitTimeout time.Duration
 
 	// Stats tracking
 	startTime        time.Time
+	connectedSince   int64 // atomic Unix seconds, zero while disconnected
 	commandsExecuted int64 // atomic counter
+	countsMu         sync.Mutex
+	commandCounts    map[string]int64
+	latency          *metrics.Collector
+
+	// lifetime holds the counts saved by earlier runs when stats.file is
+	// set, and is nil otherwise. Counts since start are added to it when
+	// reporting or saving, so it never changes.
+	lifetime *metrics.Lifetime
+	// statsStop and statsDone stop and wait for the goroutine saving counts.
+	statsStop chan struct{}
+	statsDone chan struct{}
+
+	// reloadMu serializes command reloads and guards commandLoader, which
+	// rebuilds the commands on reload; nil reloads keep the registered ones.
+	reloadMu      sync.Mutex
+	commandLoader func() []command.Command
 }
 
+// Bot serves the control API, so it must keep satisfying its interfaces.
+var (
+	_ control.BotInfo            = (*Bot)(nil)
+	_ control.GuildRuleManager   = (*Bot)(nil)
+	_ control.CommandManager     = (*Bot)(nil)
+	_ control.RuleTester         = (*Bot)(nil)
+	_ control.Moderator          = (*Bot)(nil)
+	_ control.MaintenanceManager = (*Bot)(nil)
+	_ control.CommandReloader    = (*Bot)(nil)
+)
+
 // New creates a new Bot instance with the provided configuration and logger.
 // It validates the configuration, creates a Discord session, and sets up handlers.
 // Functional options can be provided to customize the bot's behavior.
 //
-// Returns an error if the configuration is invalid or if the Discord session
-// cannot be created.
+// Returns ErrNilConfig or ErrEmptyToken if the configuration is invalid, or an
+// error if the Discord session cannot be created.
 func New(cfg *config.Config, logger zerolog.Logger, opts ...Option) (*Bot, error) {
 	if cfg == nil {
-		return nil, fmt.Errorf("config cannot be nil")
+		return nil, ErrNilConfig
 	}
 
 	// Validate that Discord token is not empty
 	if cfg.Discord.Token == "" {
-		return nil, fmt.Errorf("discord token cannot be empty")
+		return nil, ErrEmptyToken
 	}
 
 	// Create Discord session
@@ -56,16 +141,29 @@ func New(cfg *config.Config, logger zerolog.Logger, opts ...Option) (*Bot, error
 		return nil, fmt.Errorf("failed to create discord session: %w", err)
 	}
 
-	// Set Discord intents
-	session.Identify.Intents = discordgo.IntentsGuilds
-
 	// Create bot instance
 	bot := &Bot{
-		session:     session,
-		registry:    command.NewRegistry(logger),
-		config:      cfg,
-		logger:      logger,
-		middlewares: make([]middleware.Middleware, 0),
+		session:      session,
+		registry:     command.NewRegistry(logger),
+		components:   command.NewComponentRegistry(logger),
+		rules:        rules.NewSet(rules.Defaults()...),
+		warnings:     warnings.NewStore(),
+		config:       cfg,
+		logger:       logger,
+		middlewares:  make([]middleware.Middleware, 0),
+		commandFlags: middleware.NewCommandFlags(),
+		latency:      metrics.NewCollector(),
+		members:      command.NewMemberCache(cfg.Cache.MemberTTL, cfg.Cache.MemberSize),
+		snipes:       command.NewSnipeCache(cfg.Snipe.Retention, cfg.Snipe.ExcludedChannels...),
+
+		maintenance: middleware.NewMaintenance(
+			middleware.WithMaintenanceMessage(cfg.Commands.MaintenanceMessage),
+			middleware.WithMaintenanceExempt(cfg.Commands.MaintenanceExempt...),
+		),
+		allowlist: newGuildAllowlist(cfg.Discord.AllowedGuilds, cfg.Discord.LeaveOtherGuilds),
+
+		guilds:           newGuildAvailability(),
+		guildWaitTimeout: DefaultGuildWaitTimeout,
 	}
 
 	// Apply functional options
@@ -73,14 +171,73 @@ func New(cfg *config.Config, logger zerolog.Logger, opts ...Option) (*Bot, error
 		opt(bot)
 	}
 
+	// commands.prefix is the prefix of guilds that have not set their own,
+	// and saved settings, including guilds' own, take over from the config
+	if err := bot.rules.SetDefault(rules.RuleSettings, rules.KeyPrefix, cfg.Commands.Prefix); err != nil {
+		return nil, err
+	}
+	if cfg.Rules.File != "" {
+		// A corrupt or unreadable file should not keep the bot offline, so
+		// it starts on default settings instead, loaded into a copy so none
+		// of the file is half applied
+		loaded := bot.rules.Clone()
+		if err := loaded.Load(cfg.Rules.File); err != nil {
+			bot.rulesLoadErr = err
+			bot.logger.Warn().
+				Err(err).
+				Str("file", cfg.Rules.File).
+				Msg("failed to load rules file; starting with default settings, and rule changes will not be saved")
+		} else {
+			bot.rules = loaded
+		}
+	}
+
+	// Request the gateway intents the loaded settings need
+	session.Identify.Intents = bot.intents()
+
+	// Pick up counts where the last run left off
+	if cfg.Stats.File != "" {
+		lifetime, err := metrics.LoadLifetime(cfg.Stats.File)
+		if err != nil {
+			return nil, err
+		}
+		bot.lifetime = &lifetime
+	}
+
 	// Create handlers
 	bot.readyHandler = handler.NewReadyHandler(logger)
-
-	// Create middleware chain
-	var combinedMiddleware middleware.Middleware
-	if len(bot.middlewares) > 0 {
-		combinedMiddleware = middleware.Chain(bot.middlewares...)
+	bot.messageHandler = handler.NewMessageHandler(bot.rules, bot.warnings, logger)
+	bot.memberHandler = handler.NewMemberHandler(bot.rules, logger)
+
+	// Create middleware chain, outermost first: auto-deferral, the configured
+	// middlewares, the allowlist, maintenance, guard, scope, and cooldown
+	// checks, the timer, and error alerts. Time spent in any middleware counts
+	// toward Discord's deadline, and rejected commands are still logged and
+	// recovered but not timed.
+	chain := make([]middleware.Middleware, 0, len(bot.middlewares)+8)
+	chain = append(chain, middleware.AutoDefer(cfg.Commands.AutoDefer))
+	chain = append(chain, bot.middlewares...)
+	chain = append(chain,
+		bot.guildGuard(),
+		middleware.MaintenanceGuard(bot.maintenance),
+		middleware.Guard(bot.commandFlags),
+		middleware.Scope(bot.registry),
+		middleware.Cooldown(middleware.NewCooldowns(cfg.Commands.Cooldown,
+			middleware.WithCooldownBypass(
+				middleware.BypassPermission(middleware.DefaultCooldownBypassPermission),
+				middleware.BypassRoles(cfg.Commands.CooldownBypassRoles...),
+			))),
+		middleware.Metrics(bot.latency),
+	)
+	if alerts := cfg.Alerts; alerts.ChannelID != "" {
+		chain = append(chain, middleware.ErrorAlerts(middleware.NewErrorAlerter(alerts.ChannelID,
+			middleware.WithAlertThreshold(alerts.ErrorThreshold),
+			middleware.WithAlertWindow(alerts.Window),
+			middleware.WithAlertMinCommands(alerts.MinCommands),
+			middleware.WithAlertCooldown(alerts.Cooldown, alerts.MaxCooldown),
+		)))
 	}
+	combinedMiddleware := middleware.Chain(chain...)
 
 	bot.interactionHandler = handler.NewInteractionHandler(
 		bot.registry,
@@ -89,7 +246,19 @@ func New(cfg *config.Config, logger zerolog.Logger, opts ...Option) (*Bot, error
 	)
 
 	// Set callback to track command executions
-	bot.interactionHandler.SetCommandExecutedCallback(bot.IncrementCommandsExecuted)
+	bot.interactionHandler.SetCommandExecutedCallback(bot.recordCommand)
+	bot.interactionHandler.SetErrorHandler(bot.errorHandler)
+	bot.interactionHandler.SetMemberCache(bot.members)
+	bot.interactionHandler.SetComponentRegistry(bot.components)
+	bot.interactionHandler.SetGuildSettings(bot.rules)
+
+	// Any guild may set a prefix, so text commands are handled even when
+	// commands.prefix is empty
+	bot.textHandler = handler.NewTextCommandHandler(cfg.Commands.Prefix, bot.interactionHandler, logger)
+	bot.textHandler.SetDeniedMessage(cfg.Commands.DeniedMessage)
+	if alerts := cfg.Alerts; alerts.ChannelID != "" {
+		bot.textHandler.SetDenialTracker(handler.NewDenialTracker(alerts.ChannelID, alerts.DenialThreshold, alerts.DenialWindow))
+	}
 
 	return bot, nil
 }
@@ -97,8 +266,8 @@ func New(cfg *config.Config, logger zerolog.Logger, opts ...Option) (*Bot, error
 // RegisterCommand registers a command with the bot's command registry.
 // The command will be available for execution once the bot is started.
 //
-// Returns an error if the command is nil or if a command with the same name
-// is already registered.
+// Returns an error wrapping ErrNilCommand if the command is nil, or
+// ErrCommandExists if a command with the same name is already registered.
 func (b *Bot) RegisterCommand(cmd command.Command) error {
 	if b == nil {
 		return fmt.Errorf("bot cannot be nil")
@@ -106,12 +275,79 @@ func (b *Bot) RegisterCommand(cmd command.Command) error {
 	return b.registry.Register(cmd)
 }
 
+// SetCommandLoader sets how ReloadCommands rebuilds the bot's commands: load
+// returns the full set to register, from their current definitions, in place
+// of those registered now.
+func (b *Bot) SetCommandLoader(load func() []command.Command) {
+	if b == nil {
+		return
+	}
+	b.reloadMu.Lock()
+	defer b.reloadMu.Unlock()
+	b.commandLoader = load
+}
+
+// ReloadCommands rebuilds the bot's commands with the loader set by
+// SetCommandLoader, if any, and syncs them with Discord where Start
+// registers them, returning what changed. It lets command definitions change
+// without restarting the bot. If the rebuilt commands cannot all be
+// registered, the registered commands are kept and Discord is not touched.
+// Implements control.CommandReloader interface.
+func (b *Bot) ReloadCommands() (*control.CommandReloadResult, error) {
+	if b == nil {
+		return nil, fmt.Errorf("bot cannot be nil")
+	}
+	b.reloadMu.Lock()
+	defer b.reloadMu.Unlock()
+
+	if b.commandLoader != nil {
+		if err := b.registry.ReplaceAll(b.commandLoader()); err != nil {
+			return nil, fmt.Errorf("failed to rebuild commands: %w", err)
+		}
+	}
+
+	diff, err := b.SyncCommands(false)
+	if err != nil {
+		return nil, err
+	}
+	return &control.CommandReloadResult{
+		Created:   diff.Created,
+		Updated:   diff.Updated,
+		Deleted:   diff.Deleted,
+		Unchanged: len(diff.Unchanged),
+	}, nil
+}
+
+// RegisterComponent routes message component interactions, such as button
+// clicks, whose custom ID is key or starts with key and
+// command.ComponentIDSeparator to handler.
+//
+// Returns an error wrapping ErrNilComponentHandler if handler is nil, or
+// ErrCommandExists if key is already registered.
+func (b *Bot) RegisterComponent(key string, handler command.ComponentHandler) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	return b.components.Register(key, handler)
+}
+
+// Commands returns the commands registered with the bot, in no particular order.
+// The returned slice is a copy and can be safely modified by the caller.
+func (b *Bot) Commands() []command.Command {
+	if b == nil {
+		return nil
+	}
+	return b.registry.All()
+}
+
 // Start starts the bot and connects to Discord.
-// It registers event handlers, opens the Discord session, and registers
-// slash commands with Discord's API.
+// It registers event handlers, opens the Discord session, and syncs slash
+// commands with Discord's API, deleting any no longer in the registry.
 //
-// The context parameter is currently unused but is included for future
-// support of graceful startup cancellation.
+// Discord makes guilds available asynchronously after connecting, so when
+// commands are registered in a guild, Start first waits for it, up to the
+// guild wait timeout or until ctx is done, and logs a warning if it never
+// appears.
 func (b *Bot) Start(ctx context.Context) error {
 	if b == nil {
 		return fmt.Errorf("bot cannot be nil")
@@ -120,21 +356,109 @@ func (b *Bot) Start(ctx context.Context) error {
 	// Record start time
 	b.startTime = time.Now()
 
+	// Bound how many commands run at once
+	workers, queueSize := b.config.Interactions.Workers, b.config.Interactions.QueueSize
+	if workers < 1 {
+		workers, queueSize = handler.DefaultPoolWorkers, handler.DefaultPoolQueueSize
+	}
+	b.pool = handler.NewWorkerPool(workers, queueSize)
+	b.interactionHandler.SetWorkerPool(b.pool)
+	b.startSavingStats()
+
 	// Add event handlers
 	b.session.AddHandler(b.readyHandler.Handle)
+	b.session.AddHandler(b.markConnected)
+	b.session.AddHandler(b.markResumed)
+	b.session.AddHandler(b.markDisconnected)
+	b.session.AddHandler(b.guilds.handleReady)
+	b.session.AddHandler(b.guilds.handleGuildCreate)
+	b.session.AddHandler(b.guilds.handleGuildDelete)
+	if b.allowlist != nil {
+		b.session.AddHandler(b.handleGuildCreate)
+	}
 	b.session.AddHandler(b.interactionHandler.Handle)
+	b.session.AddHandler(b.messageHandler.HandleCreate)
+	b.session.AddHandler(b.messageHandler.HandleUpdate)
+	b.session.AddHandler(b.memberHandler.HandleAdd)
+	b.session.AddHandler(b.memberHandler.HandleRemove)
+	b.session.AddHandler(b.invalidateUpdatedMember)
+	b.session.AddHandler(b.invalidateRemovedMember)
+	if b.snipes != nil {
+		b.session.AddHandler(b.rememberCreatedMessage)
+		b.session.AddHandler(b.rememberUpdatedMessage)
+		b.session.AddHandler(b.rememberDeletedMessage)
+	}
+	b.session.AddHandler(b.textHandler.HandleCreate)
 
 	// Open Discord session
 	if err := b.session.Open(); err != nil {
-		return fmt.Errorf("failed to open discord session: %w", err)
+		return b.openError(err)
 	}
 
 	b.logger.Info().Msg("discord session opened")
 
-	// Register slash commands with Discord
+	// Register slash commands with Discord, once their guild is available
+	guildID := b.commandGuild(false)
+	if guildID != "" {
+		b.waitForGuild(ctx, guildID)
+	}
+	if _, err := b.syncCommands(b.session.State.User.ID, guildID); err != nil {
+		return err
+	}
+
+	b.logger.Info().Msg("bot started successfully")
+
+	return nil
+}
+
+// SyncCommands makes the slash commands registered with Discord match the
+// bot's registry without opening a gateway connection, and returns what
+// changed. Commands go where Start registers them, or globally when global is
+// true.
+func (b *Bot) SyncCommands(global bool) (command.SyncDiff, error) {
+	if b == nil {
+		return command.SyncDiff{}, fmt.Errorf("bot cannot be nil")
+	}
+
+	// Without an open session the state has no user, so ask Discord who we are.
+	var appID string
+	if b.session.State != nil && b.session.State.User != nil {
+		appID = b.session.State.User.ID
+	} else {
+		user, err := b.session.User("@me")
+		if err != nil {
+			return command.SyncDiff{}, fmt.Errorf("failed to look up bot user: %w", err)
+		}
+		appID = user.ID
+	}
+
+	return b.syncCommands(appID, b.commandGuild(global))
+}
+
+// CommandGuild returns the guild SyncCommands registers slash commands in, or
+// empty when it registers them globally.
+func (b *Bot) CommandGuild(global bool) string {
+	if b == nil {
+		return ""
+	}
+	return b.commandGuild(global)
+}
+
+// commandGuild returns the guild slash commands are registered in, or empty
+// to register them globally: when global is true, when discord.global is set,
+// or when no guild is configured.
+func (b *Bot) commandGuild(global bool) string {
+	if global || b.config.Discord.Global {
+		return ""
+	}
+	return b.config.Discord.GuildID
+}
+
+// syncCommands overwrites the application's commands in guildID (or globally
+// when empty) with the registry's, logging the resulting diff.
+func (b *Bot) syncCommands(appID, guildID string) (command.SyncDiff, error) {
 	appCommands := b.registry.ApplicationCommands()
 
-	guildID := b.config.Discord.GuildID
 	if guildID != "" {
 		b.logger.Info().
 			Str("guild_id", guildID).
@@ -146,24 +470,23 @@ func (b *Bot) Start(ctx context.Context) error {
 			Msg("registering global commands")
 	}
 
-	for _, appCmd := range appCommands {
-		_, err := b.session.ApplicationCommandCreate(
-			b.session.State.User.ID,
-			guildID,
-			appCmd,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to register command %q: %w", appCmd.Name, err)
-		}
-
-		b.logger.Debug().
-			Str("command", appCmd.Name).
-			Msg("registered command")
+	diff, err := command.SyncApplicationCommands(b.session, appID, guildID, appCommands)
+	if err != nil {
+		return diff, fmt.Errorf("failed to register commands: %w", err)
 	}
 
-	b.logger.Info().Msg("bot started successfully")
+	b.logger.Info().
+		Strs("created", diff.Created).
+		Strs("updated", diff.Updated).
+		Strs("deleted", diff.Deleted).
+		Int("unchanged", len(diff.Unchanged)).
+		Msg("synced commands with discord")
+	if guildID == "" && diff.Changed() {
+		b.logger.Warn().Msg("global command changes can take up to an hour to reach every server; " +
+			"set discord.guild_id without discord.global for instant updates during development")
+	}
 
-	return nil
+	return diff, nil
 }
 
 // Stop gracefully stops the bot and disconnects from Discord.
@@ -182,7 +505,7 @@ func (b *Bot) Stop(ctx context.Context) error {
 	if b.config.Discord.CleanupOnShutdown {
 		b.logger.Info().Msg("cleaning up slash commands")
 
-		guildID := b.config.Discord.GuildID
+		guildID := b.commandGuild(false)
 		commands, err := b.session.ApplicationCommands(b.session.State.User.ID, guildID)
 		if err != nil {
 			b.logger.Error().
@@ -214,11 +537,87 @@ func (b *Bot) Stop(ctx context.Context) error {
 		return fmt.Errorf("failed to close discord session: %w", err)
 	}
 
+	// Let commands already accepted finish
+	if b.pool != nil {
+		if err := b.pool.Close(ctx); err != nil {
+			return fmt.Errorf("failed to finish queued commands: %w", err)
+		}
+	}
+
+	// Save counts, including those of the commands just finished
+	if b.statsStop != nil {
+		close(b.statsStop)
+		<-b.statsDone
+		b.statsStop = nil
+	}
+	if err := b.saveStats(); err != nil {
+		b.logger.Error().Err(err).Msg("failed to save command counts")
+	}
+
 	b.logger.Info().Msg("bot stopped")
 
 	return nil
 }
 
+// markConnected records when the gateway session became ready, which happens
+// on the first connection and whenever Discord makes the bot start a new
+// session after a reconnect.
+func (b *Bot) markConnected(_ *discordgo.Session, _ *discordgo.Ready) {
+	atomic.StoreInt64(&b.connectedSince, time.Now().Unix())
+}
+
+// markResumed records when a dropped gateway connection was re-established
+// by resuming the previous session.
+func (b *Bot) markResumed(_ *discordgo.Session, _ *discordgo.Resumed) {
+	atomic.StoreInt64(&b.connectedSince, time.Now().Unix())
+	b.logger.Info().Msg("gateway connection resumed")
+}
+
+// markDisconnected records that the gateway connection was lost. discordgo
+// reconnects on its own, after which markConnected or markResumed runs.
+func (b *Bot) markDisconnected(_ *discordgo.Session, _ *discordgo.Disconnect) {
+	atomic.StoreInt64(&b.connectedSince, 0)
+	b.logger.Warn().Msg("gateway connection lost")
+}
+
+// invalidateUpdatedMember drops a member from the member cache when Discord
+// reports a change, such as new roles or a timeout.
+func (b *Bot) invalidateUpdatedMember(_ *discordgo.Session, m *discordgo.GuildMemberUpdate) {
+	if m != nil && m.Member != nil && m.User != nil {
+		b.members.Invalidate(m.GuildID, m.User.ID)
+	}
+}
+
+// invalidateRemovedMember drops a member who left, or was kicked or banned,
+// from the member cache.
+func (b *Bot) invalidateRemovedMember(_ *discordgo.Session, m *discordgo.GuildMemberRemove) {
+	if m != nil && m.Member != nil && m.User != nil {
+		b.members.Invalidate(m.GuildID, m.User.ID)
+	}
+}
+
+// rememberCreatedMessage keeps a new message for the snipe command.
+func (b *Bot) rememberCreatedMessage(_ *discordgo.Session, m *discordgo.MessageCreate) {
+	if m != nil {
+		b.snipes.Add(m.Message)
+	}
+}
+
+// rememberUpdatedMessage keeps the edited content of a message, so the snipe
+// command shows what was deleted rather than what was first sent.
+func (b *Bot) rememberUpdatedMessage(_ *discordgo.Session, m *discordgo.MessageUpdate) {
+	if m != nil {
+		b.snipes.Add(m.Message)
+	}
+}
+
+// rememberDeletedMessage marks a kept message as deleted for the snipe command.
+func (b *Bot) rememberDeletedMessage(_ *discordgo.Session, m *discordgo.MessageDelete) {
+	if m != nil && m.Message != nil {
+		b.snipes.Delete(m.ChannelID, m.ID)
+	}
+}
+
 // IncrementCommandsExecuted atomically increments the commands executed counter.
 // This method is called by the interaction handler after each command execution.
 func (b *Bot) IncrementCommandsExecuted() {
@@ -228,6 +627,34 @@ func (b *Bot) IncrementCommandsExecuted() {
 	atomic.AddInt64(&b.commandsExecuted, 1)
 }
 
+// recordCommand counts a successful execution of the named command.
+func (b *Bot) recordCommand(name string) {
+	b.IncrementCommandsExecuted()
+
+	b.countsMu.Lock()
+	defer b.countsMu.Unlock()
+	if b.commandCounts == nil {
+		b.commandCounts = make(map[string]int64)
+	}
+	b.commandCounts[name]++
+}
+
+// CommandCounts returns how many times each command has executed successfully.
+// Implements control.CommandCounter interface.
+func (b *Bot) CommandCounts() map[string]int64 {
+	if b == nil {
+		return nil
+	}
+
+	b.countsMu.Lock()
+	defer b.countsMu.Unlock()
+	counts := make(map[string]int64, len(b.commandCounts))
+	for name, n := range b.commandCounts {
+		counts[name] = n
+	}
+	return counts
+}
+
 // Stats returns current bot statistics.
 // Implements control.BotInfo interface.
 func (b *Bot) Stats() *control.Stats {
@@ -241,13 +668,136 @@ func (b *Bot) Stats() *control.Stats {
 		guildCount = len(b.session.State.Guilds)
 	}
 
-	return &control.Stats{
+	var lastCommandAt int64
+	if last := b.latency.LastObserved(); !last.IsZero() {
+		lastCommandAt = last.Unix()
+	}
+	build := buildinfo.Get()
+
+	stats := &control.Stats{
 		Uptime:           uptime.String(),
 		StartTime:        b.startTime.Unix(),
+		ConnectedSince:   atomic.LoadInt64(&b.connectedSince),
 		CommandsExecuted: atomic.LoadInt64(&b.commandsExecuted),
 		GuildCount:       guildCount,
-		ActiveRules:      0, // Rules not implemented yet
+		ActiveRules:      b.rules.ActiveCount(),
+		LastCommandAt:    lastCommandAt,
+		Commands:         b.commandStats(),
+		Interactions:     b.interactionStats(),
+		Build:            &build,
+		Maintenance:      b.maintenanceState(),
+		Intents:          b.gatewayIntents(),
+		RulesFile:        b.rulesFileState(),
+	}
+	if lifetime, ok := b.lifetimeCounts(); ok {
+		stats.LifetimeCommandsExecuted = lifetime.CommandsExecuted
 	}
+	return stats
+}
+
+// lifetimeCounts returns the counts saved by earlier runs plus those since
+// start. It reports false when counts are not kept across restarts.
+func (b *Bot) lifetimeCounts() (metrics.Lifetime, bool) {
+	if b.lifetime == nil {
+		return metrics.Lifetime{}, false
+	}
+	return b.lifetime.Add(atomic.LoadInt64(&b.commandsExecuted), b.CommandCounts()), true
+}
+
+// saveStats writes the lifetime counts to stats.file. It does nothing when
+// counts are not kept across restarts.
+func (b *Bot) saveStats() error {
+	lifetime, ok := b.lifetimeCounts()
+	if !ok {
+		return nil
+	}
+	return metrics.SaveLifetime(b.config.Stats.File, lifetime)
+}
+
+// startSavingStats saves the lifetime counts every stats.flush_interval until
+// Stop, so a crash loses at most one interval of counts.
+func (b *Bot) startSavingStats() {
+	if b.lifetime == nil || b.config.Stats.FlushInterval <= 0 {
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	b.statsStop, b.statsDone = stop, done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(b.config.Stats.FlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := b.saveStats(); err != nil {
+					b.logger.Error().Err(err).Msg("failed to save command counts")
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// interactionStats returns a snapshot of the worker pool, or nil before Start.
+func (b *Bot) interactionStats() *control.InteractionStats {
+	if b.pool == nil {
+		return nil
+	}
+	return &control.InteractionStats{
+		Workers:       b.pool.Workers(),
+		ActiveWorkers: b.pool.ActiveWorkers(),
+		QueueDepth:    b.pool.QueueDepth(),
+		QueueCapacity: b.pool.QueueCapacity(),
+	}
+}
+
+// commandStats combines execution counts and latency percentiles for every
+// command that has run, in earlier runs too when counts are kept across
+// restarts, in name order.
+func (b *Bot) commandStats() []control.CommandStats {
+	counts := b.CommandCounts()
+	latencies := b.latency.Percentiles()
+	lifetime, _ := b.lifetimeCounts()
+
+	seen := make(map[string]bool, len(counts)+len(latencies)+len(lifetime.Commands))
+	var names []string
+	for _, set := range []map[string]int64{counts, lifetime.Commands} {
+		for name := range set {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	for name := range latencies {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	stats := make([]control.CommandStats, 0, len(names))
+	for _, name := range names {
+		entry := control.CommandStats{Name: name, Executions: counts[name], LifetimeExecutions: lifetime.Commands[name]}
+		if p, ok := latencies[name]; ok && p.Count > 0 {
+			entry.Latency = &control.LatencyPercentiles{
+				Samples: p.Count,
+				P50:     milliseconds(p.P50),
+				P95:     milliseconds(p.P95),
+				P99:     milliseconds(p.P99),
+			}
+		}
+		stats = append(stats, entry)
+	}
+	return stats
+}
+
+// milliseconds converts d to fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
 }
 
 // Rules returns the list of moderation rules.
@@ -256,16 +806,197 @@ func (b *Bot) Rules() []control.Rule {
 	if b == nil {
 		return nil
 	}
-	// Rules not implemented yet
-	return []control.Rule{}
+	return b.rules.Rules()
 }
 
-// SetRule updates a rule configuration.
+// SetRule updates a rule configuration and saves it to rules.file, if set.
+// The value is validated against the rule's definition; invalid keys or values
+// return an error wrapping control.ErrInvalidRule, and settings needing a
+// privileged intent the bot did not request one wrapping
+// control.ErrIntentNotRequested.
 // Implements control.BotInfo interface.
 func (b *Bot) SetRule(name, key, value string) error {
 	if b == nil {
 		return fmt.Errorf("bot cannot be nil")
 	}
-	// Rules not implemented yet
-	return fmt.Errorf("rules not implemented")
+	b.rulesMu.Lock()
+	defer b.rulesMu.Unlock()
+	change := func(set *rules.Set) error { return set.SetRule(name, key, value) }
+	if err := b.checkIntents(change); err != nil {
+		return err
+	}
+	if err := change(b.rules); err != nil {
+		return err
+	}
+	return b.saveRules()
+}
+
+// GuildRules returns the rule settings in effect in a guild.
+// Implements control.GuildRuleManager interface.
+func (b *Bot) GuildRules(guildID string) []control.Rule {
+	if b == nil {
+		return nil
+	}
+	return b.rules.GuildRules(guildID)
+}
+
+// SetGuildRule overrides a rule setting for one guild, validated and saved as
+// for SetRule.
+// Implements control.GuildRuleManager interface.
+func (b *Bot) SetGuildRule(guildID, name, key, value string) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	b.rulesMu.Lock()
+	defer b.rulesMu.Unlock()
+	change := func(set *rules.Set) error { return set.SetGuildRule(guildID, name, key, value) }
+	if err := b.checkIntents(change); err != nil {
+		return err
+	}
+	if err := change(b.rules); err != nil {
+		return err
+	}
+	return b.saveRules()
+}
+
+// saveRules writes the rule settings to rules.file. It does nothing when
+// settings are not kept across restarts. The change being saved has already
+// taken effect, so an error, which wraps control.ErrRuleNotSaved, only means
+// it will not survive a restart. The caller must hold b.rulesMu.
+func (b *Bot) saveRules() error {
+	if b.config.Rules.File == "" {
+		return nil
+	}
+	if b.rulesLoadErr != nil {
+		return fmt.Errorf("%w: the rules file could not be loaded at startup: %v", control.ErrRuleNotSaved, b.rulesLoadErr)
+	}
+	if err := b.rules.Save(b.config.Rules.File); err != nil {
+		b.rulesSaveErr = err
+		return fmt.Errorf("%w: %w", control.ErrRuleNotSaved, err)
+	}
+	b.rulesSaveErr = nil
+	return nil
+}
+
+// rulesFileState reports whether rule settings are being saved to
+// rules.file, or nil when they are not kept across restarts.
+func (b *Bot) rulesFileState() *control.RulesFileState {
+	if b.config == nil || b.config.Rules.File == "" {
+		return nil
+	}
+	b.rulesMu.Lock()
+	defer b.rulesMu.Unlock()
+
+	state := &control.RulesFileState{Path: b.config.Rules.File}
+	switch {
+	case b.rulesLoadErr != nil:
+		state.Error = fmt.Sprintf("not loaded at startup, so changes are not saved: %v", b.rulesLoadErr)
+	case b.rulesSaveErr != nil:
+		state.Error = fmt.Sprintf("last save failed: %v", b.rulesSaveErr)
+	}
+	return state
+}
+
+// TestRule runs a content rule, as configured in guildID, against input
+// without acting on it. Implements control.RuleTester interface.
+func (b *Bot) TestRule(guildID, name, input string) (*control.RuleTestResult, error) {
+	if b == nil {
+		return nil, fmt.Errorf("bot cannot be nil")
+	}
+	return b.rules.TestRule(guildID, name, input)
+}
+
+// ClearWarnings deletes a member's warnings and returns how many were removed.
+// Implements control.BotInfo interface.
+func (b *Bot) ClearWarnings(guildID, userID string) (int, error) {
+	if b == nil {
+		return 0, fmt.Errorf("bot cannot be nil")
+	}
+	return b.warnings.Clear(guildID, userID), nil
+}
+
+// CommandStates returns the registered commands, sorted by name, and whether
+// each is enabled.
+// Implements control.CommandManager interface.
+func (b *Bot) CommandStates() []control.CommandState {
+	if b == nil {
+		return nil
+	}
+
+	cmds := b.registry.All()
+	states := make([]control.CommandState, 0, len(cmds))
+	for _, cmd := range cmds {
+		states = append(states, control.CommandState{
+			Name:        cmd.Name(),
+			Description: cmd.Description(),
+			Enabled:     b.commandFlags.Enabled(cmd.Name()),
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// SetCommandEnabled enables or disables a registered command at runtime.
+// A disabled command stays registered with Discord but answers with an
+// ephemeral notice instead of running. The setting lasts until the bot
+// restarts. Returns an error wrapping control.ErrCommandNotFound if no
+// command with that name is registered.
+// Implements control.CommandManager interface.
+func (b *Bot) SetCommandEnabled(name string, enabled bool) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	if _, ok := b.registry.Get(name); !ok {
+		return fmt.Errorf("%w: %s", control.ErrCommandNotFound, name)
+	}
+	b.commandFlags.SetEnabled(name, enabled)
+	return nil
+}
+
+// SetMaintenance turns maintenance mode on or off and returns the resulting
+// state. While it is on, commands other than those in
+// commands.maintenance_exempt answer with a maintenance notice instead of
+// running, unless an administrator runs them. It starts off on every start.
+// Implements control.MaintenanceManager interface.
+func (b *Bot) SetMaintenance(enabled bool) control.MaintenanceState {
+	if b == nil {
+		return control.MaintenanceState{}
+	}
+	b.maintenance.Set(enabled)
+	return *b.maintenanceState()
+}
+
+// maintenanceState reports whether the bot is in maintenance mode.
+func (b *Bot) maintenanceState() *control.MaintenanceState {
+	enabled, since := b.maintenance.State()
+	state := &control.MaintenanceState{Enabled: enabled}
+	if enabled {
+		state.Since = since.Unix()
+	}
+	return state
+}
+
+// RuleSet returns the bot's rule set for commands and handlers that enforce rules.
+func (b *Bot) RuleSet() *rules.Set {
+	if b == nil {
+		return nil
+	}
+	return b.rules
+}
+
+// Warnings returns the store of warnings issued to guild members.
+func (b *Bot) Warnings() *warnings.Store {
+	if b == nil {
+		return nil
+	}
+	return b.warnings
+}
+
+// Snipes returns the cache of deleted messages shown by the snipe command, or
+// nil if remembering messages is disabled.
+func (b *Bot) Snipes() *command.SnipeCache {
+	if b == nil {
+		return nil
+	}
+	return b.snipes
 }
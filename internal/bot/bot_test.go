@@ -1,19 +1,32 @@
 package bot_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"jamesbot/internal/bot"
+	"jamesbot/internal/buildinfo"
 	"jamesbot/internal/command"
 	"jamesbot/internal/config"
+	"jamesbot/internal/control"
 	"jamesbot/internal/middleware"
+	"jamesbot/internal/rules"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -335,17 +348,16 @@ func Test_RegisterCommand_MultipleUniqueCommands(t *testing.T) {
 	}
 }
 
-func Test_RegisterCommand_CaseSensitive(t *testing.T) {
+func Test_RegisterCommand_RejectsUppercase(t *testing.T) {
 	b, err := bot.New(validConfig(), discardLogger())
 	require.NoError(t, err)
 
-	// Register with one case
+	// Discord only accepts lowercase command names
 	err = b.RegisterCommand(newMockCommand("Ping"))
-	require.NoError(t, err)
+	assert.ErrorIs(t, err, command.ErrInvalidCommand)
 
-	// Register with different case - should succeed (different name)
 	err = b.RegisterCommand(newMockCommand("ping"))
-	assert.NoError(t, err, "commands with different cases should be treated as different")
+	assert.NoError(t, err, "the lowercase name should still be free")
 }
 
 func Test_RegisterCommand_EmptyName(t *testing.T) {
@@ -390,6 +402,88 @@ func Test_RegisterCommand_SpecialCharacters(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Commands() Tests
+// =============================================================================
+
+func Test_Commands(t *testing.T) {
+	tests := []struct {
+		name     string
+		register []string
+		want     []string
+	}{
+		{name: "no commands", want: []string{}},
+		{name: "registered commands", register: []string{"ping", "kick", "ban"}, want: []string{"ban", "kick", "ping"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bot.New(validConfig(), discardLogger())
+			require.NoError(t, err)
+			for _, name := range tt.register {
+				require.NoError(t, b.RegisterCommand(newMockCommand(name)))
+			}
+
+			cmds := b.Commands()
+
+			names := make([]string, 0, len(cmds))
+			for _, cmd := range cmds {
+				names = append(names, cmd.Name())
+			}
+			sort.Strings(names)
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func Test_Commands_ReturnsCopy(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+	require.NoError(t, b.RegisterCommand(newMockCommand("ping")))
+
+	cmds := b.Commands()
+	cmds[0] = newMockCommand("replaced")
+	_ = append(cmds, newMockCommand("extra"))
+
+	again := b.Commands()
+	require.Len(t, again, 1)
+	assert.Equal(t, "ping", again[0].Name())
+}
+
+func Test_Commands_NilBot(t *testing.T) {
+	var b *bot.Bot
+
+	assert.Nil(t, b.Commands())
+}
+
+func Test_SetCommandEnabled(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+	require.NoError(t, b.RegisterCommand(newMockCommand("ping")))
+	require.NoError(t, b.RegisterCommand(newMockCommand("ban")))
+
+	require.NoError(t, b.SetCommandEnabled("ban", false))
+
+	assert.Equal(t, []control.CommandState{
+		{Name: "ban", Description: "A mock command for testing", Enabled: false},
+		{Name: "ping", Description: "A mock command for testing", Enabled: true},
+	}, b.CommandStates())
+
+	require.NoError(t, b.SetCommandEnabled("ban", true))
+	assert.True(t, b.CommandStates()[0].Enabled)
+}
+
+func Test_SetCommandEnabled_UnknownCommand(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	err = b.SetCommandEnabled("missing", false)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, control.ErrCommandNotFound))
+	assert.Empty(t, b.CommandStates())
+}
+
 // =============================================================================
 // Concurrent Registration Tests
 // =============================================================================
@@ -449,7 +543,7 @@ func Test_RegisterCommand_ConcurrentDifferentCommands(t *testing.T) {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
-			cmd := newMockCommand("cmd-" + string(rune('A'+id%26)) + string(rune('0'+id/26)))
+			cmd := newMockCommand("cmd-" + string(rune('a'+id%26)) + string(rune('0'+id/26)))
 			err := b.RegisterCommand(cmd)
 			if err != nil {
 				errChan <- err
@@ -574,6 +668,141 @@ func Test_Stop_WithTimeout(t *testing.T) {
 	t.Logf("Stop() with timeout context returned: %v", err)
 }
 
+// =============================================================================
+// SyncCommands() Tests
+// =============================================================================
+
+// discordAPI is an http.RoundTripper standing in for Discord's REST API. It
+// answers "@me" lookups as application app-1 and every other request with an
+// empty list, recording each request as "METHOD /path".
+type discordAPI struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (d *discordAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	d.mu.Lock()
+	d.requests = append(d.requests, req.Method+" "+req.URL.Path)
+	d.mu.Unlock()
+
+	body := `[]`
+	if strings.HasSuffix(req.URL.Path, "/users/@me") {
+		body = `{"id":"app-1"}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func Test_SyncCommands_RegistrationScope(t *testing.T) {
+	const (
+		guildPath  = "/api/v9/applications/app-1/guilds/test-guild-id/commands"
+		globalPath = "/api/v9/applications/app-1/commands"
+	)
+
+	tests := []struct {
+		name     string
+		guildID  string
+		global   bool
+		syncFlag bool
+		wantPath string
+	}{
+		{name: "guild configured", guildID: "test-guild-id", wantPath: guildPath},
+		{name: "no guild configured", guildID: "", wantPath: globalPath},
+		{name: "global configured", guildID: "test-guild-id", global: true, wantPath: globalPath},
+		{name: "global requested", guildID: "test-guild-id", syncFlag: true, wantPath: globalPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Discord.GuildID = tt.guildID
+			cfg.Discord.Global = tt.global
+			b, err := bot.New(cfg, discardLogger())
+			require.NoError(t, err)
+			require.NoError(t, b.RegisterCommand(newMockCommand("ping")))
+			api := &discordAPI{}
+			b.SetHTTPClient(&http.Client{Transport: api})
+
+			diff, err := b.SyncCommands(tt.syncFlag)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"ping"}, diff.Created)
+			assert.Equal(t, []string{
+				http.MethodGet + " /api/v9/users/@me",
+				http.MethodGet + " " + tt.wantPath,
+				http.MethodPut + " " + tt.wantPath,
+			}, api.requests)
+		})
+	}
+}
+
+func Test_ReloadCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		loader      func() []command.Command
+		wantCreated []string
+		wantErr     bool
+		wantNames   []string
+	}{
+		{
+			name:        "without a loader the registered commands are synced",
+			wantCreated: []string{"ping"},
+			wantNames:   []string{"ping"},
+		},
+		{
+			name: "the loader replaces the registered commands",
+			loader: func() []command.Command {
+				return []command.Command{newMockCommand("echo"), newMockCommand("ping")}
+			},
+			wantCreated: []string{"echo", "ping"},
+			wantNames:   []string{"echo", "ping"},
+		},
+		{
+			name: "commands that cannot be registered are not synced",
+			loader: func() []command.Command {
+				return []command.Command{newMockCommand("echo"), newMockCommand("echo")}
+			},
+			wantErr:   true,
+			wantNames: []string{"ping"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bot.New(validConfig(), discardLogger())
+			require.NoError(t, err)
+			require.NoError(t, b.RegisterCommand(newMockCommand("ping")))
+			if tt.loader != nil {
+				b.SetCommandLoader(tt.loader)
+			}
+			api := &discordAPI{}
+			b.SetHTTPClient(&http.Client{Transport: api})
+
+			result, err := b.ReloadCommands()
+
+			var names []string
+			for _, cmd := range b.Commands() {
+				names = append(names, cmd.Name())
+			}
+			sort.Strings(names)
+			assert.Equal(t, tt.wantNames, names)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Empty(t, api.requests, "Discord should not be touched")
+				return
+			}
+			require.NoError(t, err)
+			sort.Strings(result.Created)
+			assert.Equal(t, tt.wantCreated, result.Created)
+			assert.Empty(t, result.Deleted)
+		})
+	}
+}
+
 // =============================================================================
 // Option Tests
 // =============================================================================
@@ -785,7 +1014,7 @@ func Test_RegisterCommand_AfterMultipleOperations(t *testing.T) {
 
 	// Register some commands
 	for i := 0; i < 5; i++ {
-		err := b.RegisterCommand(newMockCommand("initial-" + string(rune('A'+i))))
+		err := b.RegisterCommand(newMockCommand("initial-" + string(rune('a'+i))))
 		require.NoError(t, err)
 	}
 
@@ -795,7 +1024,7 @@ func Test_RegisterCommand_AfterMultipleOperations(t *testing.T) {
 
 	// Register more unique commands (should succeed)
 	for i := 0; i < 5; i++ {
-		err := b.RegisterCommand(newMockCommand("later-" + string(rune('A'+i))))
+		err := b.RegisterCommand(newMockCommand("later-" + string(rune('a'+i))))
 		assert.NoError(t, err)
 	}
 }
@@ -903,7 +1132,7 @@ func Test_RegisterCommand_ManyCommands(t *testing.T) {
 	numCommands := 1000
 
 	for i := 0; i < numCommands; i++ {
-		cmd := newMockCommand("stress-cmd-" + string(rune(i/26/26+'A')) +
+		cmd := newMockCommand("stress-cmd-" + string(rune(i/26/26+'a')) +
 			string(rune(i/26%26+'a')) +
 			string(rune(i%26+'a')))
 		err := b.RegisterCommand(cmd)
@@ -924,8 +1153,67 @@ func Test_New_ErrorsCanBeCheckedWithErrorsIs(t *testing.T) {
 	var targetErr error = err
 	assert.NotNil(t, targetErr)
 
-	// Test that errors.Is doesn't panic (even if there's no specific sentinel)
-	_ = errors.Is(err, errors.New("some error"))
+	assert.ErrorIs(t, err, bot.ErrNilConfig)
+}
+
+func Test_Errors_SentinelsMatchWithErrorsIs(t *testing.T) {
+	var nilCmd *mockCommand
+
+	tests := []struct {
+		name        string
+		run         func() error
+		want        error
+		wantMessage string
+	}{
+		{
+			name:        "nil config",
+			run:         func() error { _, err := bot.New(nil, discardLogger()); return err },
+			want:        bot.ErrNilConfig,
+			wantMessage: "config",
+		},
+		{
+			name: "empty token",
+			run: func() error {
+				cfg := validConfig()
+				cfg.Discord.Token = ""
+				_, err := bot.New(cfg, discardLogger())
+				return err
+			},
+			want:        bot.ErrEmptyToken,
+			wantMessage: "token",
+		},
+		{
+			name: "nil command",
+			run: func() error {
+				b, err := bot.New(validConfig(), discardLogger())
+				require.NoError(t, err)
+				return b.RegisterCommand(nilCmd)
+			},
+			want:        bot.ErrNilCommand,
+			wantMessage: "nil command",
+		},
+		{
+			name: "duplicate command",
+			run: func() error {
+				b, err := bot.New(validConfig(), discardLogger())
+				require.NoError(t, err)
+				require.NoError(t, b.RegisterCommand(newMockCommand("dup")))
+				return b.RegisterCommand(newMockCommand("dup"))
+			},
+			want:        bot.ErrCommandExists,
+			wantMessage: "already registered",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.want)
+			assert.Contains(t, err.Error(), tt.wantMessage)
+		})
+	}
 }
 
 // =============================================================================
@@ -998,6 +1286,15 @@ func Test_WithMiddleware_ReturnsOption(t *testing.T) {
 	assert.NotNil(t, opt, "WithMiddleware should return non-nil Option")
 }
 
+func Test_WithErrorHandler(t *testing.T) {
+	opt := bot.WithErrorHandler(func(ctx *command.Context, err error) {})
+	require.NotNil(t, opt, "WithErrorHandler should return non-nil Option")
+
+	b, err := bot.New(validConfig(), discardLogger(), opt)
+	require.NoError(t, err)
+	assert.NotNil(t, b)
+}
+
 // =============================================================================
 // Regression Tests
 // =============================================================================
@@ -1031,3 +1328,678 @@ func Test_New_ConfigNotMutated(t *testing.T) {
 	assert.Equal(t, originalToken, cfg.Discord.Token, "token should not be mutated")
 	assert.Equal(t, originalGuildID, cfg.Discord.GuildID, "guild ID should not be mutated")
 }
+
+// =============================================================================
+// Rules Tests
+// =============================================================================
+
+func Test_SetRule_Validation(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		key       string
+		value     string
+		wantErrIs error
+	}{
+		{name: "valid threshold", rule: "anti-spam", key: "threshold", value: "8"},
+		{name: "valid enabled", rule: "anti-spam", key: "enabled", value: "true"},
+		{name: "invalid threshold", rule: "anti-spam", key: "threshold", value: "notanumber", wantErrIs: control.ErrInvalidRule},
+		{name: "invalid enabled", rule: "anti-spam", key: "enabled", value: "maybe", wantErrIs: control.ErrInvalidRule},
+		{name: "unknown key", rule: "anti-spam", key: "bogus", value: "1", wantErrIs: control.ErrInvalidRule},
+		{name: "unknown rule", rule: "nonexistent", key: "enabled", value: "true", wantErrIs: control.ErrRuleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bot.New(validConfig(), discardLogger())
+			require.NoError(t, err)
+
+			err = b.SetRule(tt.rule, tt.key, tt.value)
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			require.NoError(t, err)
+
+			found := false
+			for _, r := range b.Rules() {
+				if r.Name == tt.rule && (r.Key == tt.key || tt.key == "enabled") {
+					found = true
+				}
+			}
+			assert.True(t, found, "rule should appear in Rules()")
+		})
+	}
+}
+
+func Test_Stats_ActiveRulesReflectsEnabledRules(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, b.Stats().ActiveRules)
+
+	require.NoError(t, b.SetRule("anti-spam", "enabled", "true"))
+
+	assert.Equal(t, 1, b.Stats().ActiveRules)
+}
+
+func Test_Stats_ConnectedSince(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	assert.Zero(t, b.Stats().ConnectedSince, "not connected before the session is ready")
+
+	before := time.Now().Unix()
+	b.HandleGatewayEvent(&discordgo.Ready{})
+	connected := b.Stats().ConnectedSince
+	assert.GreaterOrEqual(t, connected, before)
+
+	b.HandleGatewayEvent(&discordgo.Disconnect{})
+	assert.Zero(t, b.Stats().ConnectedSince, "disconnected until the gateway reconnects")
+
+	b.HandleGatewayEvent(&discordgo.Resumed{})
+	assert.GreaterOrEqual(t, b.Stats().ConnectedSince, connected, "a resumed session counts as reconnected")
+}
+
+func Test_WaitForGuild(t *testing.T) {
+	unavailable := func(id string) *discordgo.Guild { return &discordgo.Guild{ID: id, Unavailable: true} }
+
+	tests := []struct {
+		name    string
+		events  []interface{}
+		wantErr error
+	}{
+		{
+			name: "available once Discord sends the guild",
+			events: []interface{}{
+				&discordgo.Ready{Guilds: []*discordgo.Guild{unavailable("g1"), unavailable("g2")}},
+				&discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "g1"}},
+			},
+		},
+		{
+			name: "guild joined after connecting",
+			events: []interface{}{
+				&discordgo.Ready{},
+				&discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "g1"}},
+			},
+		},
+		{
+			name:    "not a member of the guild",
+			events:  []interface{}{&discordgo.Ready{Guilds: []*discordgo.Guild{unavailable("g2")}}},
+			wantErr: bot.ErrGuildNotJoined,
+		},
+		{
+			name:    "guild never becomes available",
+			events:  []interface{}{&discordgo.Ready{Guilds: []*discordgo.Guild{unavailable("g1")}}},
+			wantErr: bot.ErrGuildUnavailable,
+		},
+		{
+			name: "guild lost in an outage",
+			events: []interface{}{
+				&discordgo.Ready{Guilds: []*discordgo.Guild{unavailable("g1")}},
+				&discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "g1"}},
+				&discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "g1", Unavailable: true}},
+			},
+			wantErr: bot.ErrGuildUnavailable,
+		},
+		{
+			name: "bot removed from the guild",
+			events: []interface{}{
+				&discordgo.Ready{Guilds: []*discordgo.Guild{unavailable("g1")}},
+				&discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "g1"}},
+				&discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "g1"}},
+			},
+			wantErr: bot.ErrGuildNotJoined,
+		},
+		{
+			name:    "no gateway events",
+			wantErr: bot.ErrGuildUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bot.New(validConfig(), discardLogger())
+			require.NoError(t, err)
+
+			for _, event := range tt.events {
+				b.HandleGatewayEvent(event)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err = b.WaitForGuild(ctx, "g1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), "g1", "error should name the guild")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_WaitForGuild_WakesWhenGuildArrives(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+	b.HandleGatewayEvent(&discordgo.Ready{Guilds: []*discordgo.Guild{{ID: "g1", Unavailable: true}}})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.WaitForGuild(context.Background(), "g1")
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("WaitForGuild returned %v before the guild was available", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	b.HandleGatewayEvent(&discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "g1"}})
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("WaitForGuild did not return once the guild was available")
+	}
+}
+
+func Test_GuildAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		leave   bool
+		guildID string
+		want    string
+	}{
+		{name: "no allowlist serves every guild", guildID: "g9", want: "serve"},
+		{name: "allowed guild", allowed: []string{"g1", "g2"}, leave: true, guildID: "g2", want: "serve"},
+		{name: "other guild is left", allowed: []string{"g1", "g2"}, leave: true, guildID: "g9", want: "leave"},
+		{name: "other guild is ignored", allowed: []string{"g1", "g2"}, guildID: "g9", want: "ignore"},
+		{name: "commands outside guilds are served", allowed: []string{"g1"}, leave: true, guildID: "", want: "serve"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Discord.GuildID = ""
+			cfg.Discord.AllowedGuilds = tt.allowed
+			cfg.Discord.LeaveOtherGuilds = tt.leave
+			b, err := bot.New(cfg, discardLogger())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, b.GuildAction(tt.guildID))
+		})
+	}
+}
+
+func Test_GuildCreate_LeavesOtherGuilds(t *testing.T) {
+	tests := []struct {
+		name         string
+		leave        bool
+		guildID      string
+		wantRequests []string
+	}{
+		{name: "other guild is left", leave: true, guildID: "g9",
+			wantRequests: []string{http.MethodDelete + " /api/v9/users/@me/guilds/g9"}},
+		{name: "allowed guild is kept", leave: true, guildID: "g1"},
+		{name: "other guild is kept when ignoring", guildID: "g9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Discord.GuildID = "g1"
+			cfg.Discord.AllowedGuilds = []string{"g1"}
+			cfg.Discord.LeaveOtherGuilds = tt.leave
+			b, err := bot.New(cfg, discardLogger())
+			require.NoError(t, err)
+			api := &discordAPI{}
+			b.SetHTTPClient(&http.Client{Transport: api})
+
+			b.HandleGatewayEvent(&discordgo.GuildCreate{Guild: &discordgo.Guild{ID: tt.guildID, Name: "Some Server"}})
+
+			assert.Equal(t, tt.wantRequests, api.requests)
+		})
+	}
+}
+
+func Test_Stats_Build(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	build := b.Stats().Build
+	require.NotNil(t, build, "stats should describe the running binary")
+	assert.Equal(t, buildinfo.Version, build.Version)
+	assert.Equal(t, runtime.Version(), build.GoVersion)
+	assert.Equal(t, runtime.GOOS, build.OS)
+	assert.Equal(t, runtime.GOARCH, build.Arch)
+}
+
+func Test_CommandCounts(t *testing.T) {
+	var _ control.CommandCounter = (*bot.Bot)(nil)
+
+	var nilBot *bot.Bot
+	assert.Nil(t, nilBot.CommandCounts())
+
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	counts := b.CommandCounts()
+	assert.NotNil(t, counts)
+	assert.Empty(t, counts)
+	assert.Empty(t, b.Stats().Commands, "no per-command stats before any command runs")
+	assert.Zero(t, b.Stats().LastCommandAt, "no last command time before any command runs")
+}
+
+func Test_SetMaintenance(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, &control.MaintenanceState{}, b.Stats().Maintenance, "maintenance mode starts off")
+
+	state := b.SetMaintenance(true)
+	assert.True(t, state.Enabled)
+	assert.InDelta(t, time.Now().Unix(), state.Since, 5)
+	assert.Equal(t, &state, b.Stats().Maintenance)
+
+	assert.Equal(t, control.MaintenanceState{}, b.SetMaintenance(false))
+	assert.Equal(t, &control.MaintenanceState{}, b.Stats().Maintenance)
+}
+
+func Test_Stats_Lifetime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"commands_executed": 10, "commands": {"ban": 4, "ping": 6}}`), 0o600))
+
+	cfg := validConfig()
+	cfg.Stats.File = path
+	b, err := bot.New(cfg, discardLogger())
+	require.NoError(t, err)
+
+	b.RecordCommand("ping")
+	b.RecordCommand("ping")
+
+	stats := b.Stats()
+	assert.Equal(t, int64(2), stats.CommandsExecuted, "commands since start")
+	assert.Equal(t, int64(12), stats.LifetimeCommandsExecuted, "commands over every run")
+	assert.Equal(t, []control.CommandStats{
+		{Name: "ban", Executions: 0, LifetimeExecutions: 4},
+		{Name: "ping", Executions: 2, LifetimeExecutions: 8},
+	}, stats.Commands)
+
+	// A restart picks up where the last run left off
+	require.NoError(t, b.SaveStats())
+	restarted, err := bot.New(cfg, discardLogger())
+	require.NoError(t, err)
+
+	stats = restarted.Stats()
+	assert.Zero(t, stats.CommandsExecuted)
+	assert.Equal(t, int64(12), stats.LifetimeCommandsExecuted)
+}
+
+func Test_Stats_LifetimeDisabled(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	b.RecordCommand("ping")
+	require.NoError(t, b.SaveStats(), "saving without stats.file does nothing")
+
+	stats := b.Stats()
+	assert.Equal(t, int64(1), stats.CommandsExecuted)
+	assert.Zero(t, stats.LifetimeCommandsExecuted)
+	assert.Equal(t, []control.CommandStats{{Name: "ping", Executions: 1}}, stats.Commands)
+}
+
+func Test_New_CorruptStatsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	cfg := validConfig()
+	cfg.Stats.File = path
+	_, err := bot.New(cfg, discardLogger())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path, "error should name the stats file")
+}
+
+func Test_RulesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	cfg := validConfig()
+	cfg.Commands.Prefix = "!"
+	cfg.Rules.File = path
+
+	b, err := bot.New(cfg, discardLogger())
+	require.NoError(t, err)
+	assert.Equal(t, "!", b.RuleSet().GuildSettings("guild-1").Prefix, "commands.prefix is the default prefix")
+
+	require.NoError(t, b.SetGuildRule("guild-1", rules.RuleSettings, rules.KeyPrefix, "?"))
+	require.NoError(t, b.SetRule("anti-spam", "enabled", "true"))
+	assert.FileExists(t, path, "changes should be saved as they are made")
+
+	// A restart keeps the changes, with the config supplying everything else
+	cfg.Commands.Prefix = "$"
+	restarted, err := bot.New(cfg, discardLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, "?", restarted.RuleSet().GuildSettings("guild-1").Prefix)
+	assert.Equal(t, "$", restarted.RuleSet().GuildSettings("guild-2").Prefix)
+	assert.True(t, restarted.RuleSet().Enabled("anti-spam"))
+}
+
+func Test_RulesFile_Disabled(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+	assert.Nil(t, b.Stats().RulesFile, "nothing to report without rules.file")
+
+	require.NoError(t, b.SetRule("anti-spam", "enabled", "true"), "setting without rules.file does nothing else")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func Test_New_InvalidRulesFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "corrupt", content: "not json"},
+		{name: "invalid setting", content: `[{"name":"anti-spam","key":"enabled","value":"true"},{"name":"settings","key":"prefix","value":"a b"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			cfg := validConfig()
+			cfg.Rules.File = path
+			b, err := bot.New(cfg, discardLogger())
+			require.NoError(t, err, "the bot should start on default settings")
+			assert.False(t, b.RuleSet().Enabled("anti-spam"), "no part of the file should be applied")
+
+			err = b.SetRule("anti-spam", "enabled", "true")
+			require.ErrorIs(t, err, control.ErrRuleNotSaved)
+			assert.True(t, b.RuleSet().Enabled("anti-spam"), "the change should still take effect")
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(data), "the file should be left alone")
+
+			state := b.Stats().RulesFile
+			require.NotNil(t, state)
+			assert.Equal(t, path, state.Path)
+			assert.Contains(t, state.Error, "not loaded at startup")
+		})
+	}
+}
+
+func Test_RulesFile_SaveFails(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "rules")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	cfg := validConfig()
+	cfg.Rules.File = filepath.Join(dir, "rules.json")
+	b, err := bot.New(cfg, discardLogger())
+	require.NoError(t, err)
+	assert.Empty(t, b.Stats().RulesFile.Error)
+
+	require.NoError(t, os.Remove(dir))
+	err = b.SetRule("anti-spam", "enabled", "true")
+	require.ErrorIs(t, err, control.ErrRuleNotSaved)
+	assert.True(t, b.RuleSet().Enabled("anti-spam"), "the change should still take effect")
+	assert.Contains(t, b.Stats().RulesFile.Error, "last save failed")
+
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	require.NoError(t, b.SetRule("anti-spam", "enabled", "false"))
+	assert.Empty(t, b.Stats().RulesFile.Error, "a successful save clears the error")
+}
+
+func Test_CommandGuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		guildID string
+		global  bool
+		flag    bool
+		want    string
+	}{
+		{name: "configured guild", guildID: "123", want: "123"},
+		{name: "no guild", want: ""},
+		{name: "discord.global", guildID: "123", global: true, want: ""},
+		{name: "--global", guildID: "123", flag: true, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Discord.GuildID = tt.guildID
+			cfg.Discord.Global = tt.global
+			b, err := bot.New(cfg, discardLogger())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, b.CommandGuild(tt.flag))
+		})
+	}
+
+	var nilBot *bot.Bot
+	assert.Empty(t, nilBot.CommandGuild(false))
+}
+
+func Test_New_Intents(t *testing.T) {
+	const (
+		base    = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages
+		content = discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+	)
+
+	tests := []struct {
+		name   string
+		config func(*config.Config)
+		rules  string
+		want   discordgo.Intent
+	}{
+		{
+			name: "slash commands alone",
+			want: base,
+		},
+		{
+			name:   "snipe command",
+			config: func(cfg *config.Config) { cfg.Snipe.Retention = time.Minute },
+			want:   base,
+		},
+		{
+			name:   "commands.prefix",
+			config: func(cfg *config.Config) { cfg.Commands.Prefix = "!" },
+			want:   base | content,
+		},
+		{
+			name: "discord.privileged_intents",
+			config: func(cfg *config.Config) {
+				cfg.Discord.PrivilegedIntents = []string{config.IntentMessageContent, config.IntentServerMembers}
+			},
+			want: base | content | discordgo.IntentsGuildMembers,
+		},
+		{
+			name:  "guild prefix",
+			rules: `[{"name":"settings","key":"prefix","value":"?","guild":"guild-1"}]`,
+			want:  base | content,
+		},
+		{
+			name:  "content rule enabled in a guild",
+			rules: `[{"name":"link-filter","key":"enabled","value":"true","guild":"guild-1"}]`,
+			want:  base | content,
+		},
+		{
+			name:  "welcome channel",
+			rules: `[{"name":"welcome","key":"enabled","value":"true"},{"name":"welcome","key":"channel","value":"123456789012345678"}]`,
+			want:  base | discordgo.IntentsGuildMembers,
+		},
+		{
+			name:  "welcome role in a guild",
+			rules: `[{"name":"welcome","key":"enabled","value":"true","guild":"guild-1"},{"name":"welcome","key":"role","value":"123456789012345678","guild":"guild-1"}]`,
+			want:  base | discordgo.IntentsGuildMembers,
+		},
+		{
+			name:  "goodbye channel",
+			rules: `[{"name":"goodbye","key":"enabled","value":"true"},{"name":"goodbye","key":"channel","value":"123456789012345678"}]`,
+			want:  base | discordgo.IntentsGuildMembers,
+		},
+		{
+			name:  "welcome enabled with nothing to do",
+			rules: `[{"name":"welcome","key":"enabled","value":"true"}]`,
+			want:  base,
+		},
+		{
+			name:  "other rules",
+			rules: `[{"name":"anti-spam","key":"enabled","value":"true"}]`,
+			want:  base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			if tt.config != nil {
+				tt.config(cfg)
+			}
+			if tt.rules != "" {
+				cfg.Rules.File = filepath.Join(t.TempDir(), "rules.json")
+				require.NoError(t, os.WriteFile(cfg.Rules.File, []byte(tt.rules), 0o600))
+			}
+
+			b, err := bot.New(cfg, discardLogger())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, b.Intents())
+		})
+	}
+}
+
+func Test_SetRule_RejectsMissingIntents(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, b.SetRule("anti-spam", "enabled", "true"), "anti-spam needs no further intents")
+	require.NoError(t, b.SetRule(rules.RuleWelcome, "enabled", "true"), "welcome does nothing without a channel")
+
+	err = b.SetGuildRule("guild-1", rules.RuleWordFilter, "enabled", "true")
+	require.ErrorIs(t, err, control.ErrIntentNotRequested)
+	assert.Contains(t, err.Error(), "Message Content")
+	assert.Contains(t, err.Error(), config.IntentMessageContent)
+	assert.False(t, ruleEnabled(b.GuildRules("guild-1"), rules.RuleWordFilter), "a rejected change should not apply")
+
+	err = b.SetRule(rules.RuleWelcome, rules.KeyChannel, "123456789012345678")
+	require.ErrorIs(t, err, control.ErrIntentNotRequested)
+	assert.Contains(t, err.Error(), "Server Members")
+
+	require.NoError(t, b.SetRule(rules.RuleWordFilter, "enabled", "false"), "changes needing nothing new are allowed")
+	assert.Equal(t, discordgo.IntentsGuilds|discordgo.IntentsGuildMessages, b.Intents(), "intents only change on connecting")
+	assert.Equal(t, &control.GatewayIntents{Requested: []string{}}, b.Stats().Intents)
+}
+
+func Test_SetRule_ConfiguredIntents(t *testing.T) {
+	cfg := validConfig()
+	cfg.Discord.PrivilegedIntents = []string{config.IntentMessageContent}
+	b, err := bot.New(cfg, discardLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, b.SetGuildRule("guild-1", rules.RuleWordFilter, "enabled", "true"))
+	assert.True(t, ruleEnabled(b.GuildRules("guild-1"), rules.RuleWordFilter))
+	assert.Equal(t, &control.GatewayIntents{Requested: []string{"Message Content"}}, b.Stats().Intents)
+}
+
+// ruleEnabled reports whether the rule called name is enabled in list.
+func ruleEnabled(list []control.Rule, name string) bool {
+	for _, r := range list {
+		if r.Name == name {
+			return r.Enabled
+		}
+	}
+	return false
+}
+
+func Test_Start_DisallowedIntents(t *testing.T) {
+	cfg := validConfig()
+	cfg.Commands.Prefix = "!"
+	cfg.Rules.File = filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(cfg.Rules.File,
+		[]byte(`[{"name":"goodbye","key":"enabled","value":"true"},{"name":"goodbye","key":"channel","value":"123456789012345678"}]`), 0o600))
+
+	var buf bytes.Buffer
+	b, err := bot.New(cfg, zerolog.New(&buf))
+	require.NoError(t, err)
+
+	err = b.OpenError(&websocket.CloseError{Code: 4014, Text: "Disallowed intent(s)."})
+	require.ErrorIs(t, err, bot.ErrDisallowedIntents)
+	assert.Contains(t, err.Error(), "Message Content and Server Members")
+	assert.Contains(t, buf.String(), "Developer Portal", "the refusal should be logged with what to do")
+
+	err = b.OpenError(&websocket.CloseError{Code: 4004, Text: "Authentication failed."})
+	assert.NotErrorIs(t, err, bot.ErrDisallowedIntents)
+	assert.Contains(t, err.Error(), "failed to open discord session")
+}
+
+// =============================================================================
+// Control API Integration Tests
+// =============================================================================
+
+// Test_ControlServer_WithRealBot wires a real Bot into the control API, which
+// the control package's own tests only exercise through a mock BotInfo.
+func Test_ControlServer_WithRealBot(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	server := httptest.NewServer(control.NewServer(0, b, discardLogger()).Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stats")
+	require.NoError(t, err)
+	var stats control.Stats
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 0, stats.ActiveRules)
+	assert.Equal(t, int64(0), stats.CommandsExecuted)
+
+	resp, err = http.Post(server.URL+"/rules/set", "application/json",
+		strings.NewReader(`{"name":"anti-spam","key":"enabled","value":"true"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/rules/set", "application/json",
+		strings.NewReader(`{"name":"anti-spam","key":"threshold","value":"nope"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "validation errors map to 400")
+
+	resp, err = http.Get(server.URL + "/rules")
+	require.NoError(t, err)
+	var list []control.Rule
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	assert.Equal(t, b.Rules(), list)
+
+	resp, err = http.Get(server.URL + "/stats")
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	resp.Body.Close()
+	assert.Equal(t, 1, stats.ActiveRules)
+}
+
+func Test_RegisterComponent(t *testing.T) {
+	var nilBot *bot.Bot
+	assert.Error(t, nilBot.RegisterComponent("confirm", func(ctx *command.Context) error { return nil }))
+
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, b.RegisterComponent("confirm", func(ctx *command.Context) error { return nil }))
+
+	err = b.RegisterComponent("confirm", func(ctx *command.Context) error { return nil })
+	assert.ErrorIs(t, err, command.ErrCommandExists)
+	assert.ErrorIs(t, b.RegisterComponent("cancel", nil), command.ErrNilComponentHandler)
+}
@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"errors"
+
+	"jamesbot/internal/command"
+)
+
+// Sentinel errors returned by New, RegisterCommand, and while starting. Returned errors wrap
+// these with context, so check them with errors.Is rather than by message.
+var (
+	// ErrNilConfig is returned by New when the config is nil.
+	ErrNilConfig = errors.New("config cannot be nil")
+
+	// ErrEmptyToken is returned by New when the config has no Discord token.
+	ErrEmptyToken = errors.New("discord token cannot be empty")
+
+	// ErrNilCommand is returned by RegisterCommand when the command is nil.
+	ErrNilCommand = command.ErrNilCommand
+
+	// ErrCommandExists is returned by RegisterCommand when a command with the
+	// same name is already registered.
+	ErrCommandExists = command.ErrCommandExists
+
+	// ErrGuildNotJoined reports that the guild commands are registered in is
+	// not one the bot is a member of.
+	ErrGuildNotJoined = errors.New("bot is not a member of the guild")
+
+	// ErrGuildUnavailable reports that the guild commands are registered in
+	// did not become available in time.
+	ErrGuildUnavailable = errors.New("guild did not become available")
+
+	// ErrDisallowedIntents reports that Discord refused the connection
+	// because a privileged intent the bot requests is not enabled for it.
+	ErrDisallowedIntents = errors.New("discord refused privileged gateway intents")
+)
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetHTTPClient routes the bot's Discord REST requests through client, so
+// tests can observe them without network access.
+func (b *Bot) SetHTTPClient(client *http.Client) {
+	b.session.Client = client
+}
+
+// HandleGatewayEvent runs the bot's connection, guild tracking, and guild
+// allowlist handlers for a Ready, Resumed, Disconnect, GuildCreate, or
+// GuildDelete event, as the session would once started.
+func (b *Bot) HandleGatewayEvent(event interface{}) {
+	switch e := event.(type) {
+	case *discordgo.Ready:
+		b.markConnected(b.session, e)
+		b.guilds.handleReady(b.session, e)
+	case *discordgo.GuildCreate:
+		b.guilds.handleGuildCreate(b.session, e)
+		if b.allowlist != nil {
+			b.handleGuildCreate(b.session, e)
+		}
+	case *discordgo.GuildDelete:
+		b.guilds.handleGuildDelete(b.session, e)
+	case *discordgo.Resumed:
+		b.markResumed(b.session, e)
+	case *discordgo.Disconnect:
+		b.markDisconnected(b.session, e)
+	}
+}
+
+// WaitForGuild waits, as Start does before registering commands, for guildID
+// to become available or ctx to be done.
+func (b *Bot) WaitForGuild(ctx context.Context, guildID string) error {
+	return b.guilds.wait(ctx, guildID)
+}
+
+// RecordCommand counts a successful execution of the named command, as the
+// interaction handler does after running it.
+func (b *Bot) RecordCommand(name string) {
+	b.recordCommand(name)
+}
+
+// SaveStats writes the lifetime counts to stats.file, as the bot does
+// periodically and on Stop.
+func (b *Bot) SaveStats() error {
+	return b.saveStats()
+}
+
+// GuildAction returns what the bot does in guildID under
+// discord.allowed_guilds: "serve", "ignore", or "leave".
+func (b *Bot) GuildAction(guildID string) string {
+	return b.allowlist.decide(guildID).String()
+}
+
+// AddGuildToState caches guild and its members in the session's state, as
+// the session does on GuildCreate.
+func (b *Bot) AddGuildToState(guild *discordgo.Guild) error {
+	return b.session.State.GuildAdd(guild)
+}
+
+// Intents returns the gateway intents the session requests on connecting.
+func (b *Bot) Intents() discordgo.Intent {
+	return b.session.Identify.Intents
+}
+
+// OpenError returns the error Start reports when opening the gateway
+// connection fails with err.
+func (b *Bot) OpenError(err error) error {
+	return b.openError(err)
+}
@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultGuildWaitTimeout bounds how long Start waits for the guild commands
+// are registered in to become available, unless WithGuildWaitTimeout says
+// otherwise.
+const DefaultGuildWaitTimeout = 15 * time.Second
+
+// guildAvailability tracks the guilds Discord has made available since the
+// gateway connected. Discord lists the bot's guilds as unavailable in Ready
+// and sends a GuildCreate for each one as it becomes available, after Open
+// has returned. It is safe for concurrent use.
+type guildAvailability struct {
+	mu sync.Mutex
+	// joined holds the guilds listed in the last Ready, and ready is set
+	// once one has been received.
+	joined map[string]bool
+	ready  bool
+	// available holds the guilds Discord has sent since.
+	available map[string]bool
+	// changed is closed, and replaced, whenever any of the above changes.
+	changed chan struct{}
+}
+
+// newGuildAvailability creates a tracker that has seen no guilds.
+func newGuildAvailability() *guildAvailability {
+	return &guildAvailability{
+		joined:    make(map[string]bool),
+		available: make(map[string]bool),
+		changed:   make(chan struct{}),
+	}
+}
+
+// handleReady records the guilds the bot is a member of. A new session
+// starts with every guild unavailable until Discord sends it again.
+func (g *guildAvailability) handleReady(_ *discordgo.Session, r *discordgo.Ready) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.joined = make(map[string]bool, len(r.Guilds))
+	g.available = make(map[string]bool, len(r.Guilds))
+	for _, guild := range r.Guilds {
+		if guild == nil {
+			continue
+		}
+		g.joined[guild.ID] = true
+		if !guild.Unavailable {
+			g.available[guild.ID] = true
+		}
+	}
+	g.ready = true
+	g.notify()
+}
+
+// handleGuildCreate records a guild becoming available, either one listed in
+// Ready or one the bot has just joined.
+func (g *guildAvailability) handleGuildCreate(_ *discordgo.Session, e *discordgo.GuildCreate) {
+	if e.Guild == nil || e.Unavailable {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.joined[e.ID] = true
+	g.available[e.ID] = true
+	g.notify()
+}
+
+// handleGuildDelete records a guild becoming unavailable in an outage, or
+// the bot leaving it.
+func (g *guildAvailability) handleGuildDelete(_ *discordgo.Session, e *discordgo.GuildDelete) {
+	if e.Guild == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.available, e.ID)
+	if !e.Unavailable {
+		delete(g.joined, e.ID)
+	}
+	g.notify()
+}
+
+// notify wakes callers waiting for a change. Callers must hold g.mu.
+func (g *guildAvailability) notify() {
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
+
+// wait blocks until guildID is available, returning nil. It returns an error
+// wrapping ErrGuildNotJoined as soon as Ready shows the bot is not a member
+// of guildID, or one wrapping ErrGuildUnavailable if ctx is done first.
+func (g *guildAvailability) wait(ctx context.Context, guildID string) error {
+	for {
+		g.mu.Lock()
+		available, joined, ready, changed := g.available[guildID], g.joined[guildID], g.ready, g.changed
+		g.mu.Unlock()
+
+		switch {
+		case available:
+			return nil
+		case ready && !joined:
+			return fmt.Errorf("guild %s: %w", guildID, ErrGuildNotJoined)
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fmt.Errorf("guild %s: %w: %w", guildID, ErrGuildUnavailable, ctx.Err())
+		}
+	}
+}
+
+// waitForGuild waits up to the bot's guild wait timeout for guildID to become
+// available before commands are registered in it, logging a warning if it
+// does not. Registration is attempted either way, since it uses the REST API
+// and may still succeed.
+func (b *Bot) waitForGuild(ctx context.Context, guildID string) {
+	ctx, cancel := context.WithTimeout(ctx, b.guildWaitTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := b.guilds.wait(ctx, guildID); err != nil {
+		b.logger.Warn().
+			Err(err).
+			Str("guild_id", guildID).
+			Dur("waited", time.Since(start)).
+			Msg("configured guild is not available; check that discord.guild_id is correct " +
+				"and that the bot has been invited to that server")
+		return
+	}
+
+	b.logger.Debug().
+		Str("guild_id", guildID).
+		Dur("waited", time.Since(start)).
+		Msg("guild available")
+}
@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"jamesbot/internal/config"
+	"jamesbot/internal/control"
+	"jamesbot/internal/rules"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// closeDisallowedIntents is the gateway close code Discord sends when the bot
+// requests a privileged intent not enabled for it in the Developer Portal.
+const closeDisallowedIntents = 4014
+
+// privilegedIntents lists the privileged gateway intents the bot can request,
+// by their Developer Portal names and as named in discord.privileged_intents.
+var privilegedIntents = []struct {
+	intent discordgo.Intent
+	name   string
+	key    string
+}{
+	{discordgo.IntentsMessageContent, "Message Content", config.IntentMessageContent},
+	{discordgo.IntentsGuildMembers, "Server Members", config.IntentServerMembers},
+}
+
+// intents returns the gateway intents the bot's config and rule settings
+// need. Message Content and Server Members are privileged: Discord closes the
+// connection unless they are also enabled in the Developer Portal, so each is
+// requested only when discord.privileged_intents lists it or a feature that
+// needs it is in use, globally or in any guild. Bots run with slash commands
+// alone then connect without either. The snipe command only has text to show
+// for messages read with Message Content.
+func (b *Bot) intents() discordgo.Intent {
+	return b.intentsFor(b.rules)
+}
+
+// intentsFor returns the gateway intents the bot needs with the rule settings
+// in set.
+func (b *Bot) intentsFor(set *rules.Set) discordgo.Intent {
+	// Guild Messages is not privileged, so it is always requested: the
+	// anti-spam rule, which can be enabled at any time, counts messages
+	// without reading them, as the snipe command sees deletions
+	intents := discordgo.IntentsGuilds | discordgo.IntentsGuildMessages
+	for _, key := range b.config.Discord.PrivilegedIntents {
+		switch key {
+		case config.IntentMessageContent:
+			intents |= discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+		case config.IntentServerMembers:
+			intents |= discordgo.IntentsGuildMembers
+		}
+	}
+	if readsMessageContent(set) {
+		intents |= discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+	}
+	if watchesMembers(set) {
+		// Without it, cached members expire rather than being dropped on
+		// change
+		intents |= discordgo.IntentsGuildMembers
+	}
+	return intents
+}
+
+// readsMessageContent reports whether any feature enabled in set reads the
+// text of members' messages.
+func readsMessageContent(set *rules.Set) bool {
+	return set.EnabledAnywhere(rules.RuleWordFilter) ||
+		set.EnabledAnywhere(rules.RuleLinkFilter) ||
+		set.SetAnywhere(rules.RuleSettings, rules.KeyPrefix)
+}
+
+// watchesMembers reports whether the welcome or goodbye rule is enabled in
+// set with something to do when members join or leave.
+func watchesMembers(set *rules.Set) bool {
+	welcome := set.SetAnywhere(rules.RuleWelcome, rules.KeyChannel) ||
+		set.SetAnywhere(rules.RuleWelcome, rules.KeyWelcomeRole)
+	return (set.EnabledAnywhere(rules.RuleWelcome) && welcome) ||
+		(set.EnabledAnywhere(rules.RuleGoodbye) && set.SetAnywhere(rules.RuleGoodbye, rules.KeyChannel))
+}
+
+// privilegedIntentNames returns the Developer Portal names of the privileged
+// intents in intents.
+func privilegedIntentNames(intents discordgo.Intent) []string {
+	names := []string{}
+	for _, p := range privilegedIntents {
+		if intents&p.intent != 0 {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// openError explains a failure to open the gateway connection. When Discord
+// refused the privileged intents requested, it says which to enable rather
+// than passing on the bare close code.
+func (b *Bot) openError(err error) error {
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != closeDisallowedIntents {
+		return fmt.Errorf("failed to open discord session: %w", err)
+	}
+
+	names := strings.Join(privilegedIntentNames(b.session.Identify.Intents), " and ")
+	b.logger.Error().
+		Str("intents", names).
+		Msg("discord refused the gateway intents; enable them for the bot in the Developer Portal")
+	return fmt.Errorf("%w: enable the %s intents for the bot in the Developer Portal", ErrDisallowedIntents, names)
+}
+
+// gatewayIntents reports the privileged intents the session was opened with
+// and those the rule settings need beyond them.
+func (b *Bot) gatewayIntents() *control.GatewayIntents {
+	if b.session == nil {
+		return nil
+	}
+	requested := b.session.Identify.Intents
+	state := &control.GatewayIntents{Requested: privilegedIntentNames(requested)}
+	if missing := privilegedIntentNames(b.intents() &^ requested); len(missing) > 0 {
+		state.Missing = missing
+	}
+	return state
+}
+
+// checkIntents returns an error wrapping control.ErrIntentNotRequested if
+// change, applied to a copy of the rule settings, would need privileged
+// intents the session was not opened with. Intents are only requested on
+// connecting, so such a change would do nothing until a restart; changes
+// needing nothing the current settings do not already need are allowed.
+func (b *Bot) checkIntents(change func(*rules.Set) error) error {
+	next := b.rules.Clone()
+	if err := change(next); err != nil {
+		return err
+	}
+	missing := b.intentsFor(next) &^ b.session.Identify.Intents &^ b.intents()
+
+	var names, keys []string
+	for _, p := range privilegedIntents {
+		if missing&p.intent != 0 {
+			names = append(names, p.name)
+			keys = append(keys, p.key)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: it needs the %s intent; add %s to discord.privileged_intents and restart the bot",
+		control.ErrIntentNotRequested, strings.Join(names, " and "), strings.Join(keys, " and "))
+}
@@ -0,0 +1,354 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/control"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// The moderation methods act on members for the control API, outside of any
+// slash command. They only need the bot's REST session, not a gateway
+// connection, and validate IDs before sending anything to Discord.
+
+// Ban bans userID from guildID, deleting their messages from the last
+// deleteDays days and recording reason, if set, in the guild's audit log.
+// Implements control.Moderator interface.
+func (b *Bot) Ban(guildID, userID, reason string, deleteDays int) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	if err := validateMemberIDs(guildID, userID); err != nil {
+		return err
+	}
+	if deleteDays < 0 || deleteDays > control.MaxBanDeleteDays {
+		return fmt.Errorf("delete days must be between 0 and %d", control.MaxBanDeleteDays)
+	}
+
+	if err := b.session.GuildBanCreateWithReason(guildID, userID, reason, deleteDays); err != nil {
+		return moderationError("ban", userID, err)
+	}
+	b.logModeration("banned user", guildID, command.ModLogEntry{Action: command.ModLogBanned, TargetID: userID, Reason: reason})
+	return nil
+}
+
+// Kick removes userID from guildID, recording reason, if set, in the guild's
+// audit log. It returns an error wrapping control.ErrMemberNotFound if the
+// user is not a member. Implements control.Moderator interface.
+func (b *Bot) Kick(guildID, userID, reason string) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	if err := validateMemberIDs(guildID, userID); err != nil {
+		return err
+	}
+
+	if err := b.session.GuildMemberDeleteWithReason(guildID, userID, reason); err != nil {
+		return moderationError("kick", userID, err)
+	}
+	b.logModeration("kicked user", guildID, command.ModLogEntry{Action: command.ModLogKicked, TargetID: userID, Reason: reason})
+	return nil
+}
+
+// Mute times userID out in guildID for duration, recording reason, if set, in
+// the guild's audit log. It returns an error wrapping
+// control.ErrMemberNotFound if the user is not a member.
+// Implements control.Moderator interface.
+func (b *Bot) Mute(guildID, userID string, duration time.Duration, reason string) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	if err := validateMemberIDs(guildID, userID); err != nil {
+		return err
+	}
+	if duration < control.MinMuteDuration || duration > control.MaxMuteDuration {
+		return fmt.Errorf("duration must be between %s and %s", control.MinMuteDuration, control.MaxMuteDuration)
+	}
+
+	until := time.Now().Add(duration)
+	if err := b.session.GuildMemberTimeout(guildID, userID, &until, auditReason(reason)...); err != nil {
+		return moderationError("mute", userID, err)
+	}
+	b.logModeration("muted user", guildID, command.ModLogEntry{Action: command.ModLogTimedOut, TargetID: userID, Reason: reason, Duration: duration})
+	return nil
+}
+
+// Unban lifts userID's ban from guildID, recording reason, if set, in the
+// guild's audit log. It returns control.ErrNotBanned if the user is not
+// banned. Implements control.Moderator interface.
+func (b *Bot) Unban(guildID, userID, reason string) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	if err := validateMemberIDs(guildID, userID); err != nil {
+		return err
+	}
+
+	if err := b.session.GuildBanDelete(guildID, userID, auditReason(reason)...); err != nil {
+		if discordErrorCode(err) == discordgo.ErrCodeUnknownBan {
+			return control.ErrNotBanned
+		}
+		return moderationError("unban", userID, err)
+	}
+	b.logModeration("unbanned user", guildID, command.ModLogEntry{Action: command.ModLogUnbanned, TargetID: userID, Reason: reason})
+	return nil
+}
+
+// Audit log paging for Punishments.
+const (
+	// auditLogPageSize is the most entries Discord returns per request.
+	auditLogPageSize = 100
+
+	// maxTimeoutAuditPages bounds how many pages of a guild's audit log are
+	// read looking for timeouts.
+	maxTimeoutAuditPages = 5
+
+	// maxTimeout is the longest timeout Discord allows. Audit log entries
+	// older than it cannot belong to a timeout still in effect.
+	maxTimeout = 28 * 24 * time.Hour
+)
+
+// Punishments returns the member timeouts in effect in guildID, or in every
+// guild when guildID is empty, soonest to expire first. Members are found
+// timed out in the session's state or, when the bot can read it, each guild's
+// audit log, which also gives the reasons, and every one is looked up over
+// REST, so the list does not depend on the Server Members intent keeping the
+// state current. Implements control.PunishmentLister interface.
+func (b *Bot) Punishments(guildID string) []control.Punishment {
+	if b == nil || b.session == nil || b.session.State == nil {
+		return nil
+	}
+
+	guildIDs := []string{guildID}
+	if guildID == "" {
+		guildIDs = nil
+		b.session.State.RLock()
+		for _, guild := range b.session.State.Guilds {
+			guildIDs = append(guildIDs, guild.ID)
+		}
+		b.session.State.RUnlock()
+	}
+
+	var punishments []control.Punishment
+	for _, id := range guildIDs {
+		punishments = append(punishments, b.guildTimeouts(id)...)
+	}
+
+	sort.Slice(punishments, func(i, j int) bool {
+		return punishments[i].ExpiresAt.Before(punishments[j].ExpiresAt)
+	})
+	return punishments
+}
+
+// guildTimeouts returns the member timeouts in effect in guildID, in no
+// particular order.
+func (b *Bot) guildTimeouts(guildID string) []control.Punishment {
+	now := time.Now()
+	audited, err := b.auditedTimeouts(guildID)
+	if err != nil {
+		b.logger.Debug().Err(err).Str("guild_id", guildID).Msg("failed to read timeouts from audit log")
+	}
+
+	candidates := make(map[string]control.Punishment)
+	for userID, timeout := range audited {
+		if timeout.until.After(now) {
+			candidates[userID] = control.Punishment{
+				GuildID:   guildID,
+				UserID:    userID,
+				Type:      control.PunishmentMute,
+				Reason:    timeout.reason,
+				ExpiresAt: timeout.until,
+			}
+		}
+	}
+	if guild, err := b.session.State.Guild(guildID); err == nil {
+		b.session.State.RLock()
+		for _, member := range guild.Members {
+			until := member.CommunicationDisabledUntil
+			if member.User == nil || until == nil || !until.After(now) {
+				continue
+			}
+			candidates[member.User.ID] = control.Punishment{
+				GuildID:   guildID,
+				UserID:    member.User.ID,
+				Username:  member.User.Username,
+				Type:      control.PunishmentMute,
+				Reason:    audited[member.User.ID].reason,
+				ExpiresAt: *until,
+			}
+		}
+		b.session.State.RUnlock()
+	}
+
+	// Either source may be out of date, so each timeout is confirmed with
+	// Discord; one that cannot be looked up is kept as found
+	punishments := make([]control.Punishment, 0, len(candidates))
+	for userID, p := range candidates {
+		member, err := b.session.GuildMember(guildID, userID)
+		switch {
+		case err == nil:
+			until := member.CommunicationDisabledUntil
+			if until == nil || !until.After(now) {
+				continue
+			}
+			p.ExpiresAt = *until
+			if member.User != nil {
+				p.Username = member.User.Username
+			}
+		case isUnknownMember(err):
+			continue
+		default:
+			b.logger.Debug().Err(err).Str("guild_id", guildID).Str("user_id", userID).Msg("failed to look up timed out member")
+		}
+		punishments = append(punishments, p)
+	}
+	return punishments
+}
+
+// CancelPunishment lifts userID's punishment of type kind in guildID before
+// it expires, recording reason, if set, in the guild's audit log. Mutes, the
+// only kind, are lifted by removing the member's timeout. The member is
+// looked up over REST first, and control.ErrNoPunishment returned if they are
+// not timed out. Implements control.PunishmentCanceler interface.
+func (b *Bot) CancelPunishment(guildID, userID, kind, reason string) error {
+	if b == nil {
+		return fmt.Errorf("bot cannot be nil")
+	}
+	if err := validateMemberIDs(guildID, userID); err != nil {
+		return err
+	}
+	if kind != control.PunishmentMute {
+		return fmt.Errorf("unknown punishment type %q", kind)
+	}
+
+	member, err := b.session.GuildMember(guildID, userID)
+	if err != nil {
+		return moderationError("unmute", userID, err)
+	}
+	if until := member.CommunicationDisabledUntil; until == nil || !until.After(time.Now()) {
+		return control.ErrNoPunishment
+	}
+
+	if err := b.session.GuildMemberTimeout(guildID, userID, nil, auditReason(reason)...); err != nil {
+		return moderationError("unmute", userID, err)
+	}
+	b.logModeration("lifted timeout", guildID, command.ModLogEntry{Action: command.ModLogUnmuted, TargetID: userID, Reason: reason})
+	return nil
+}
+
+// auditedTimeout is the latest change to a member's timeout in an audit log.
+type auditedTimeout struct {
+	// until is when the timeout ends, or zero if it was removed.
+	until  time.Time
+	reason string
+}
+
+// auditedTimeouts returns the latest timeout change to each member in
+// guildID's audit log, by user ID, reading back as far as a timeout could
+// still be in effect. It returns an error if the audit log cannot be read,
+// such as without the View Audit Log permission, along with the changes read
+// before it failed.
+func (b *Bot) auditedTimeouts(guildID string) (map[string]auditedTimeout, error) {
+	timeouts := make(map[string]auditedTimeout)
+	cutoff := time.Now().Add(-maxTimeout)
+	before := ""
+	for range maxTimeoutAuditPages {
+		log, err := b.session.GuildAuditLog(guildID, "", before, int(discordgo.AuditLogActionMemberUpdate), auditLogPageSize)
+		if err != nil {
+			return timeouts, err
+		}
+
+		// Entries are newest first, so the first timeout change per member wins
+		for _, entry := range log.AuditLogEntries {
+			before = entry.ID
+			if _, ok := timeouts[entry.TargetID]; ok {
+				continue
+			}
+			for _, change := range entry.Changes {
+				if change.Key == nil || *change.Key != discordgo.AuditLogChangeKeyCommunicationDisabledUntil {
+					continue
+				}
+				timeout := auditedTimeout{reason: entry.Reason}
+				if value, ok := change.NewValue.(string); ok {
+					timeout.until, _ = time.Parse(time.RFC3339, value)
+				}
+				timeouts[entry.TargetID] = timeout
+				break
+			}
+		}
+
+		if len(log.AuditLogEntries) < auditLogPageSize {
+			break
+		}
+		if created, err := discordgo.SnowflakeTimestamp(before); err != nil || created.Before(cutoff) {
+			break
+		}
+	}
+	return timeouts, nil
+}
+
+// logModeration records a moderation action taken through the control API,
+// and posts it to the guild's mod-log channel.
+func (b *Bot) logModeration(msg, guildID string, entry command.ModLogEntry) {
+	b.logger.Info().
+		Str("guild_id", guildID).
+		Str("user_id", entry.TargetID).
+		Str("reason", entry.Reason).
+		Msg(msg)
+	entry.Source = "the control API"
+	command.PostModLog(b.session, b.rules, guildID, entry, b.logger)
+}
+
+// validateMemberIDs checks that guildID and userID look like Discord IDs.
+func validateMemberIDs(guildID, userID string) error {
+	if _, err := strconv.ParseUint(guildID, 10, 64); err != nil {
+		return fmt.Errorf("invalid guild ID %q", guildID)
+	}
+	if _, err := strconv.ParseUint(userID, 10, 64); err != nil {
+		return fmt.Errorf("invalid user ID %q", userID)
+	}
+	return nil
+}
+
+// auditReason returns the request options that record reason in the guild's
+// audit log, or none if reason is empty.
+func auditReason(reason string) []discordgo.RequestOption {
+	if reason == "" {
+		return nil
+	}
+	return []discordgo.RequestOption{discordgo.WithAuditLogReason(reason)}
+}
+
+// discordErrorCode returns the JSON error code of a Discord REST error, or 0.
+func discordErrorCode(err error) int {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		return restErr.Message.Code
+	}
+	return 0
+}
+
+// isUnknownMember reports whether err is Discord saying a member or user does
+// not exist.
+func isUnknownMember(err error) bool {
+	switch discordErrorCode(err) {
+	case discordgo.ErrCodeUnknownMember, discordgo.ErrCodeUnknownUser:
+		return true
+	}
+	return false
+}
+
+// moderationError wraps err from taking action on userID, wrapping
+// control.ErrMemberNotFound as well when Discord does not know the member.
+func moderationError(action, userID string, err error) error {
+	if isUnknownMember(err) {
+		return fmt.Errorf("failed to %s user %s: %w: %w", action, userID, control.ErrMemberNotFound, err)
+	}
+	return fmt.Errorf("failed to %s user %s: %w", action, userID, err)
+}
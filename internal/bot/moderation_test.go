@@ -0,0 +1,366 @@
+package bot_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"jamesbot/internal/bot"
+	"jamesbot/internal/control"
+	"jamesbot/internal/rules"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moderationAPI is a Discord REST stand-in for moderation requests. User
+// "404" is not a member, only users in banned can be unbanned, members are
+// looked up in members by user ID, and every other request succeeds.
+type moderationAPI struct {
+	banned   map[string]bool
+	members  map[string]*discordgo.Member
+	requests []string
+	reasons  []string
+}
+
+func (d *moderationAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req.Method+" "+req.URL.Path)
+	reason := req.Header.Get("X-Audit-Log-Reason")
+	if reason == "" {
+		reason = req.URL.Query().Get("reason")
+	}
+	d.reasons = append(d.reasons, reason)
+
+	userID := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	status, body := http.StatusNoContent, ""
+	switch {
+	case userID == "404":
+		status, body = http.StatusNotFound, `{"code":10007,"message":"Unknown Member"}`
+	case req.Method == http.MethodDelete && strings.Contains(req.URL.Path, "/bans/") && !d.banned[userID]:
+		status, body = http.StatusNotFound, `{"code":10026,"message":"Unknown Ban"}`
+	case req.Method == http.MethodGet:
+		status, body = memberResponse(d.members[userID])
+	case req.Method == http.MethodPatch || req.Method == http.MethodPost:
+		status, body = http.StatusOK, `{}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func Test_Moderation(t *testing.T) {
+	tests := []struct {
+		name        string
+		act         func(b *bot.Bot) error
+		wantRequest string
+		wantReason  string
+		wantErr     error
+		wantErrText string
+	}{
+		{
+			name:        "ban",
+			act:         func(b *bot.Bot) error { return b.Ban("111", "222", "spam", 1) },
+			wantRequest: "PUT /api/v9/guilds/111/bans/222",
+			wantReason:  "spam",
+		},
+		{
+			name:        "ban deletes too many days",
+			act:         func(b *bot.Bot) error { return b.Ban("111", "222", "", 8) },
+			wantErrText: "delete days",
+		},
+		{
+			name:        "kick",
+			act:         func(b *bot.Bot) error { return b.Kick("111", "222", "rude") },
+			wantRequest: "DELETE /api/v9/guilds/111/members/222",
+			wantReason:  "rude",
+		},
+		{
+			name:    "kick non-member",
+			act:     func(b *bot.Bot) error { return b.Kick("111", "404", "") },
+			wantErr: control.ErrMemberNotFound,
+		},
+		{
+			name:        "mute",
+			act:         func(b *bot.Bot) error { return b.Mute("111", "222", time.Hour, "cool off") },
+			wantRequest: "PATCH /api/v9/guilds/111/members/222",
+			wantReason:  "cool off",
+		},
+		{
+			name:        "mute too long",
+			act:         func(b *bot.Bot) error { return b.Mute("111", "222", 29*24*time.Hour, "") },
+			wantErrText: "duration",
+		},
+		{
+			name:        "unban",
+			act:         func(b *bot.Bot) error { return b.Unban("111", "222", "appeal accepted") },
+			wantRequest: "DELETE /api/v9/guilds/111/bans/222",
+			wantReason:  "appeal accepted",
+		},
+		{
+			name:        "unban without reason",
+			act:         func(b *bot.Bot) error { return b.Unban("111", "222", "") },
+			wantRequest: "DELETE /api/v9/guilds/111/bans/222",
+		},
+		{
+			name:    "unban user who is not banned",
+			act:     func(b *bot.Bot) error { return b.Unban("111", "333", "") },
+			wantErr: control.ErrNotBanned,
+		},
+		{
+			name:        "invalid user ID",
+			act:         func(b *bot.Bot) error { return b.Kick("111", "not-an-id", "") },
+			wantErrText: "invalid user ID",
+		},
+		{
+			name:        "invalid guild ID",
+			act:         func(b *bot.Bot) error { return b.Unban("guild", "222", "") },
+			wantErrText: "invalid guild ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bot.New(validConfig(), discardLogger())
+			require.NoError(t, err)
+			api := &moderationAPI{banned: map[string]bool{"222": true}}
+			b.SetHTTPClient(&http.Client{Transport: api})
+
+			err = tt.act(b)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantErrText != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrText)
+				assert.Empty(t, api.requests, "invalid requests are not sent to Discord")
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, []string{tt.wantRequest}, api.requests)
+				assert.Equal(t, []string{tt.wantReason}, api.reasons)
+			}
+		})
+	}
+}
+
+func Test_Moderation_ModLog(t *testing.T) {
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+	require.NoError(t, b.SetGuildRule("111", rules.RuleSettings, rules.KeyModLogChannel, "333"))
+	api := &moderationAPI{}
+	b.SetHTTPClient(&http.Client{Transport: api})
+
+	require.NoError(t, b.Kick("111", "222", "rude"))
+	require.NoError(t, b.Kick("444", "222", "rude"))
+
+	assert.Equal(t, []string{
+		"DELETE /api/v9/guilds/111/members/222",
+		"POST /api/v9/channels/333/messages",
+		"DELETE /api/v9/guilds/444/members/222",
+	}, api.requests, "actions are posted to the mod-log channel of guilds that set one")
+}
+
+func Test_Moderation_NilBot(t *testing.T) {
+	var b *bot.Bot
+	assert.Error(t, b.Ban("111", "222", "", 0))
+	assert.Error(t, b.Kick("111", "222", ""))
+	assert.Error(t, b.Mute("111", "222", time.Hour, ""))
+	assert.Error(t, b.Unban("111", "222", ""))
+	assert.Nil(t, b.Punishments(""))
+	assert.Error(t, b.CancelPunishment("111", "222", control.PunishmentMute, ""))
+}
+
+// memberResponse returns the status and body of a Discord REST response
+// with member, or Unknown Member if it is nil.
+func memberResponse(member *discordgo.Member) (int, string) {
+	if member == nil {
+		return http.StatusNotFound, `{"code":10007,"message":"Unknown Member"}`
+	}
+	body, _ := json.Marshal(member)
+	return http.StatusOK, string(body)
+}
+
+// punishmentsAPI answers guild audit log requests with each guild's entries,
+// or with 403 Missing Permissions for guilds in forbidden, and member lookups
+// from members, keyed by guild and user ID as "guild/user".
+type punishmentsAPI struct {
+	entries   map[string][]*discordgo.AuditLogEntry
+	forbidden map[string]bool
+	members   map[string]*discordgo.Member
+	requests  []string
+}
+
+func (d *punishmentsAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req.Method+" "+req.URL.Path)
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/api/v9/guilds/"), "/")
+	guildID := parts[0]
+
+	var status int
+	var body string
+	switch {
+	case parts[1] == "members":
+		status, body = memberResponse(d.members[guildID+"/"+parts[2]])
+	case d.forbidden[guildID]:
+		status, body = http.StatusForbidden, `{"code":50013,"message":"Missing Permissions"}`
+	default:
+		log, _ := json.Marshal(discordgo.GuildAuditLog{AuditLogEntries: d.entries[guildID]})
+		status, body = http.StatusOK, string(log)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func Test_Punishments(t *testing.T) {
+	soon := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	later := soon.Add(time.Hour)
+	past := soon.Add(-2 * time.Hour)
+	timeoutKey := discordgo.AuditLogChangeKeyCommunicationDisabledUntil
+	nickKey := discordgo.AuditLogChangeKeyNick
+
+	member := func(id string, until *time.Time) *discordgo.Member {
+		return &discordgo.Member{User: &discordgo.User{ID: id, Username: "user" + id}, CommunicationDisabledUntil: until}
+	}
+	timeout := func(id, reason string, until time.Time) *discordgo.AuditLogEntry {
+		return &discordgo.AuditLogEntry{TargetID: id, Reason: reason, Changes: []*discordgo.AuditLogChange{
+			{Key: &timeoutKey, NewValue: until.Format(time.RFC3339)},
+		}}
+	}
+
+	b, err := bot.New(validConfig(), discardLogger())
+	require.NoError(t, err)
+	require.NoError(t, b.AddGuildToState(&discordgo.Guild{ID: "111", Members: []*discordgo.Member{
+		member("1", &later),
+		member("2", &past),
+		member("3", nil),
+		member("7", &later),
+	}}))
+	require.NoError(t, b.AddGuildToState(&discordgo.Guild{ID: "222", Members: []*discordgo.Member{
+		member("4", &soon),
+	}}))
+	require.NoError(t, b.AddGuildToState(&discordgo.Guild{ID: "333"}))
+
+	api := &punishmentsAPI{
+		forbidden: map[string]bool{"222": true},
+		entries: map[string][]*discordgo.AuditLogEntry{"111": {
+			{TargetID: "1", Reason: "renamed", Changes: []*discordgo.AuditLogChange{{Key: &nickKey}}},
+			timeout("1", "spam", later),
+			timeout("1", "older timeout", soon),
+			timeout("5", "flooding", later),
+			timeout("6", "lifted since", later),
+		}},
+		members: map[string]*discordgo.Member{
+			"111/1": member("1", &later),
+			"111/5": member("5", &later),
+			"111/6": member("6", nil),
+			"111/7": member("7", nil),
+			"222/4": member("4", &soon),
+		},
+	}
+	b.SetHTTPClient(&http.Client{Transport: api})
+
+	t.Run("every guild", func(t *testing.T) {
+		punishments := b.Punishments("")
+
+		assert.ElementsMatch(t, []control.Punishment{
+			{GuildID: "222", UserID: "4", Username: "user4", Type: control.PunishmentMute, ExpiresAt: soon},
+			{GuildID: "111", UserID: "1", Username: "user1", Type: control.PunishmentMute, Reason: "spam", ExpiresAt: later},
+			{GuildID: "111", UserID: "5", Username: "user5", Type: control.PunishmentMute, Reason: "flooding", ExpiresAt: later},
+		}, punishments, "timeouts missing from the state are found in the audit log, lifted ones are confirmed over REST, "+
+			"and a guild whose audit log cannot be read has no reasons")
+		require.NotEmpty(t, punishments)
+		assert.Equal(t, "4", punishments[0].UserID, "the soonest to expire should come first")
+	})
+
+	t.Run("one guild", func(t *testing.T) {
+		api.requests = nil
+
+		punishments := b.Punishments("111")
+
+		require.Len(t, punishments, 2)
+		assert.NotContains(t, api.requests, "GET /api/v9/guilds/222/audit-logs", "other guilds should not be read")
+	})
+
+	t.Run("no timeouts", func(t *testing.T) {
+		api.requests = nil
+
+		assert.Empty(t, b.Punishments("333"))
+		assert.Equal(t, []string{"GET /api/v9/guilds/333/audit-logs"}, api.requests, "no members need looking up")
+	})
+}
+
+func Test_CancelPunishment(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		userID      string
+		kind        string
+		wantRequest bool
+		wantErr     error
+		wantErrText string
+	}{
+		{name: "lifts timeout", userID: "1", kind: control.PunishmentMute, wantRequest: true},
+		{name: "stale state", userID: "9", kind: control.PunishmentMute, wantRequest: true},
+		{name: "expired timeout", userID: "2", kind: control.PunishmentMute, wantErr: control.ErrNoPunishment},
+		{name: "lifted since cached", userID: "8", kind: control.PunishmentMute, wantErr: control.ErrNoPunishment},
+		{name: "never timed out", userID: "3", kind: control.PunishmentMute, wantErr: control.ErrNoPunishment},
+		{name: "not a member", userID: "404", kind: control.PunishmentMute, wantErr: control.ErrMemberNotFound},
+		{name: "unknown type", userID: "1", kind: "ban", wantErrText: "unknown punishment type"},
+		{name: "invalid user ID", userID: "abc", kind: control.PunishmentMute, wantErrText: "invalid user ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bot.New(validConfig(), discardLogger())
+			require.NoError(t, err)
+			// The state is out of date for 8 and 9, as without the Server
+			// Members intent, so only the REST lookups count
+			require.NoError(t, b.AddGuildToState(&discordgo.Guild{ID: "111", Members: []*discordgo.Member{
+				{User: &discordgo.User{ID: "8"}, CommunicationDisabledUntil: &later},
+				{User: &discordgo.User{ID: "9"}},
+			}}))
+			api := &moderationAPI{members: map[string]*discordgo.Member{
+				"1": {User: &discordgo.User{ID: "1"}, CommunicationDisabledUntil: &later},
+				"2": {User: &discordgo.User{ID: "2"}, CommunicationDisabledUntil: &past},
+				"3": {User: &discordgo.User{ID: "3"}},
+				"8": {User: &discordgo.User{ID: "8"}},
+				"9": {User: &discordgo.User{ID: "9"}, CommunicationDisabledUntil: &later},
+			}}
+			b.SetHTTPClient(&http.Client{Transport: api})
+
+			err = b.CancelPunishment("111", tt.userID, tt.kind, "appeal accepted")
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantErrText != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrText)
+			default:
+				require.NoError(t, err)
+			}
+			lookup := "GET /api/v9/guilds/111/members/" + tt.userID
+			switch {
+			case tt.wantRequest:
+				assert.Equal(t, []string{lookup, "PATCH /api/v9/guilds/111/members/" + tt.userID}, api.requests)
+				assert.Equal(t, "appeal accepted", api.reasons[1])
+			case tt.wantErr != nil:
+				assert.Equal(t, []string{lookup}, api.requests, "nothing to lift is not sent to Discord")
+			default:
+				assert.Empty(t, api.requests)
+			}
+		})
+	}
+}
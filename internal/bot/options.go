@@ -1,7 +1,12 @@
 // Package bot provides the core bot implementation for JamesBot.
 package bot
 
-import "jamesbot/internal/middleware"
+import (
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+)
 
 // Option is a functional option for configuring the Bot.
 // Functional options allow for flexible and extensible bot configuration
@@ -25,3 +30,37 @@ func WithMiddleware(mw ...middleware.Middleware) Option {
 		b.middlewares = append(b.middlewares, mw...)
 	}
 }
+
+// WithErrorHandler sets how users are told that a command or component
+// failed. onError runs after the middleware chain with the error the handler
+// returned, which has already been logged, and is responsible for responding.
+// Without it, users get the error's user-friendly message or a generic one,
+// ephemerally (see handler.DefaultErrorHandler).
+//
+// Example:
+//
+//	bot, err := bot.New(cfg, logger,
+//	    bot.WithErrorHandler(func(ctx *command.Context, err error) {
+//	        if errors.Is(err, errQuotaExceeded) {
+//	            _ = ctx.RespondEphemeral("Try again tomorrow.")
+//	            return
+//	        }
+//	        handler.DefaultErrorHandler(ctx, err)
+//	    }),
+//	)
+func WithErrorHandler(onError func(ctx *command.Context, err error)) Option {
+	return func(b *Bot) {
+		b.errorHandler = onError
+	}
+}
+
+// WithGuildWaitTimeout sets how long Start waits for the guild slash commands
+// are registered in to become available before registering them anyway.
+// A timeout that is not positive keeps DefaultGuildWaitTimeout.
+func WithGuildWaitTimeout(d time.Duration) Option {
+	return func(b *Bot) {
+		if d > 0 {
+			b.guildWaitTimeout = d
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package buildinfo describes the running JamesBot binary.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"jamesbot/internal/control"
+)
+
+// Version is the current version of JamesBot.
+const Version = "1.1.0"
+
+// Commit is the revision the binary was built from. Builds may set it with
+// -ldflags "-X jamesbot/internal/buildinfo.Commit=<rev>"; otherwise Get
+// falls back to the revision the Go toolchain records when building from a
+// git checkout.
+var Commit string
+
+// Get returns the version, commit, Go version, and platform of the running
+// binary. Commit is empty when it is not set and no revision was recorded,
+// as under go test or go run.
+func Get() control.BuildInfo {
+	return control.BuildInfo{
+		Version:   Version,
+		Commit:    commit(),
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+}
+
+// commit returns Commit, or the recorded VCS revision if it is not set.
+func commit() string {
+	if Commit != "" {
+		return Commit
+	}
+	return vcsRevision()
+}
+
+// vcsRevision returns the revision the Go toolchain recorded for the build,
+// with a "-dirty" suffix if the checkout had uncommitted changes, or "".
+var vcsRevision = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+	return revision
+})
@@ -0,0 +1,32 @@
+package buildinfo_test
+
+import (
+	"runtime"
+	"testing"
+
+	"jamesbot/internal/buildinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// =============================================================================
+// Get Tests
+// =============================================================================
+
+func Test_Get(t *testing.T) {
+	info := buildinfo.Get()
+
+	assert.Equal(t, buildinfo.Version, info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, runtime.GOOS, info.OS)
+	assert.Equal(t, runtime.GOARCH, info.Arch)
+}
+
+func Test_Get_CommitOverride(t *testing.T) {
+	original := buildinfo.Commit
+	t.Cleanup(func() { buildinfo.Commit = original })
+
+	buildinfo.Commit = "3f2a9c1d0b4e"
+
+	assert.Equal(t, "3f2a9c1d0b4e", buildinfo.Get().Commit, "a commit set at link time takes precedence")
+}
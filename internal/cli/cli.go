@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 
+	"jamesbot/internal/buildinfo"
 	"jamesbot/internal/cli/commands"
 )
 
 const (
 	// Version is the current version of JamesBot.
-	Version = "1.1.0"
+	Version = buildinfo.Version
 
 	// AppName is the application name displayed in help text.
 	AppName = "jamesbot"
@@ -37,9 +40,11 @@ func Run(args []string, stdout, stderr io.Writer) int {
 	// Handle global flags and special commands
 	firstArg := args[0]
 	switch firstArg {
-	case "-h", "--help", "help":
+	case "-h", "--help":
 		printUsage(stdout)
 		return ExitSuccess
+	case "help":
+		return runHelp(args[1:], stdout, stderr)
 	case "-v", "--version", "version":
 		fmt.Fprintf(stdout, "%s version %s\n", AppName, Version)
 		return ExitSuccess
@@ -98,60 +103,113 @@ func runCommand(cmd CLICommand, args []string, stdout, stderr io.Writer) int {
 
 // runParentCommand handles execution of parent commands with subcommands.
 func runParentCommand(parent ParentCommand, args []string, stdout, stderr io.Writer) int {
-	// If no subcommand specified, print parent command usage
-	if len(args) == 0 {
+	// Handle help for parent command
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "help") {
 		fmt.Fprintf(stdout, "%s\n", parent.Usage())
 		return ExitSuccess
 	}
 
-	// Handle help for parent command
-	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+	// A runnable parent runs itself unless a subcommand is named
+	if runnable, ok := parent.(RunnableParent); ok && runnable.RunsWithoutSubcommand() &&
+		(len(args) == 0 || strings.HasPrefix(args[0], "-")) {
+		return runCommand(parent, args, stdout, stderr)
+	}
+
+	// If no subcommand specified, print parent command usage
+	if len(args) == 0 {
 		fmt.Fprintf(stdout, "%s\n", parent.Usage())
 		return ExitSuccess
 	}
 
 	// Find the subcommand
-	subcommands := parent.Subcommands()
 	subcmdName := args[0]
-
-	for _, subcmd := range subcommands {
-		if subcmd.Name() == subcmdName {
-			return runCommand(subcmd, args[1:], stdout, stderr)
-		}
+	if subcmd, ok := findSubcommand(parent, subcmdName); ok {
+		return runCommand(subcmd, args[1:], stdout, stderr)
 	}
 
 	// Subcommand not found
-	fmt.Fprintf(stderr, "Error: unknown subcommand %q for %q\n\n", subcmdName, parent.Name())
-	fmt.Fprintf(stderr, "%s\n", parent.Usage())
+	printUnknownSubcommand(stderr, parent, subcmdName)
 	return ExitUsageError
 }
 
+// printUnknownSubcommand reports that parent has no subcommand called name,
+// listing the subcommands it does have so a typo is easy to correct.
+func printUnknownSubcommand(w io.Writer, parent ParentCommand, name string) {
+	fmt.Fprintf(w, "Error: unknown subcommand %q for %q\n\n", name, parent.Name())
+	fmt.Fprintf(w, "Available subcommands:\n")
+	for _, subcmd := range parent.Subcommands() {
+		fmt.Fprintf(w, "  %-12s %s\n", subcmd.Name(), subcmd.Synopsis())
+	}
+	fmt.Fprintf(w, "\nUse \"%s help %s\" for more information.\n", AppName, parent.Name())
+}
+
+// findSubcommand looks up a subcommand of parent by name.
+func findSubcommand(parent ParentCommand, name string) (CLICommand, bool) {
+	for _, subcmd := range parent.Subcommands() {
+		if subcmd.Name() == name {
+			return subcmd, true
+		}
+	}
+	return nil, false
+}
+
 // printUsage prints the general usage information.
+// The command list is built from the registered command tree, so new commands
+// and subcommands appear without editing this function.
 func printUsage(w io.Writer) {
 	fmt.Fprintf(w, "Usage: %s <command> [options]\n\n", AppName)
 	fmt.Fprintf(w, "JamesBot - Discord moderation bot\n\n")
 	fmt.Fprintf(w, "Commands:\n")
 
-	commands := getCommands()
-	for _, name := range []string{"serve", "stats", "rules"} {
-		if cmd, ok := commands[name]; ok {
-			fmt.Fprintf(w, "  %-12s %s\n", name, cmd.Synopsis())
+	for _, cmd := range Commands() {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.Name(), cmd.Synopsis())
+		if parent, ok := cmd.(ParentCommand); ok {
+			for _, subcmd := range parent.Subcommands() {
+				fmt.Fprintf(w, "    %-10s %s\n", subcmd.Name(), subcmd.Synopsis())
+			}
 		}
 	}
+	fmt.Fprintf(w, "  %-12s %s\n", "help", "Show help for a command")
 
 	fmt.Fprintf(w, "\nGlobal Options:\n")
 	fmt.Fprintf(w, "  -h, --help     Show help\n")
 	fmt.Fprintf(w, "  -v, --version  Show version\n")
-	fmt.Fprintf(w, "\nUse \"%s <command> -h\" for more information about a command.\n", AppName)
+	fmt.Fprintf(w, "\nUse \"%s help <command> [subcommand]\" for more information about a command.\n", AppName)
+}
+
+// Commands returns the registered top-level commands sorted by name.
+// Parent commands expose their subcommands through ParentCommand.
+func Commands() []CLICommand {
+	commands := getCommands()
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]CLICommand, 0, len(names))
+	for _, name := range names {
+		result = append(result, commands[name])
+	}
+	return result
 }
 
 // getCommands returns the map of available commands.
 // This is the command registry for the CLI.
 func getCommands() map[string]CLICommand {
 	return map[string]CLICommand{
-		"serve": newServeCommandAdapter(),
-		"stats": newStatsCommandAdapter(),
-		"rules": newRulesCommandAdapter(),
+		"serve":       newServeCommandAdapter(),
+		"stats":       newStatsCommandAdapter(),
+		"rules":       newRulesCommandAdapter(),
+		"config":      newConfigCommandAdapter(),
+		"warnings":    newWarningsCommandAdapter(),
+		"ban":         newBanCommandAdapter(),
+		"mod":         newModCommandAdapter(),
+		"commands":    newCommandsCommandAdapter(),
+		"doctor":      newDoctorCommandAdapter(),
+		"sync":        newSyncCommandAdapter(),
+		"maintenance": newMaintenanceCommandAdapter(),
+		"punishments": newPunishmentsCommandAdapter(),
 	}
 }
 
@@ -194,6 +252,8 @@ func (a *serveCommandAdapter) Run(ctx *Context, args []string) int {
 }
 
 // statsCommandAdapter adapts commands.StatsCommand to the CLICommand interface.
+// This adapter also implements RunnableParent, so "jamesbot stats" still shows
+// the statistics while "jamesbot stats top" routes to its subcommand.
 type statsCommandAdapter struct {
 	cmd *commands.StatsCommand
 }
@@ -231,6 +291,54 @@ func (a *statsCommandAdapter) Run(ctx *Context, args []string) int {
 	return a.cmd.Run(cmdCtx, args)
 }
 
+func (a *statsCommandAdapter) Subcommands() []CLICommand {
+	return []CLICommand{
+		newStatsTopCommandAdapter(),
+	}
+}
+
+func (a *statsCommandAdapter) RunsWithoutSubcommand() bool {
+	return true
+}
+
+// statsTopCommandAdapter adapts commands.StatsTopCommand to the CLICommand interface.
+type statsTopCommandAdapter struct {
+	cmd *commands.StatsTopCommand
+}
+
+func newStatsTopCommandAdapter() *statsTopCommandAdapter {
+	return &statsTopCommandAdapter{
+		cmd: commands.NewStatsTopCommand(),
+	}
+}
+
+func (a *statsTopCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *statsTopCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *statsTopCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *statsTopCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *statsTopCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
 // rulesCommandAdapter adapts commands.RulesCommand to the CLICommand interface.
 // This adapter also implements ParentCommand for subcommand routing.
 type rulesCommandAdapter struct {
@@ -274,6 +382,9 @@ func (a *rulesCommandAdapter) Subcommands() []CLICommand {
 	return []CLICommand{
 		newRulesListCommandAdapter(),
 		newRulesSetCommandAdapter(),
+		newRulesTestCommandAdapter(),
+		newRulesImportCommandAdapter(),
+		newRulesExportCommandAdapter(),
 	}
 }
 
@@ -352,3 +463,851 @@ func (a *rulesSetCommandAdapter) Run(ctx *Context, args []string) int {
 	}
 	return a.cmd.Run(cmdCtx, args)
 }
+
+// rulesImportCommandAdapter adapts commands.RulesImportCommand to the CLICommand interface.
+type rulesImportCommandAdapter struct {
+	cmd *commands.RulesImportCommand
+}
+
+func newRulesImportCommandAdapter() *rulesImportCommandAdapter {
+	return &rulesImportCommandAdapter{
+		cmd: commands.NewRulesImportCommand(),
+	}
+}
+
+func (a *rulesImportCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *rulesImportCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *rulesImportCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *rulesImportCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *rulesImportCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// rulesTestCommandAdapter adapts commands.RulesTestCommand to the CLICommand interface.
+type rulesTestCommandAdapter struct {
+	cmd *commands.RulesTestCommand
+}
+
+func newRulesTestCommandAdapter() *rulesTestCommandAdapter {
+	return &rulesTestCommandAdapter{
+		cmd: commands.NewRulesTestCommand(),
+	}
+}
+
+func (a *rulesTestCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *rulesTestCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *rulesTestCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *rulesTestCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *rulesTestCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// rulesExportCommandAdapter adapts commands.RulesExportCommand to the CLICommand interface.
+type rulesExportCommandAdapter struct {
+	cmd *commands.RulesExportCommand
+}
+
+func newRulesExportCommandAdapter() *rulesExportCommandAdapter {
+	return &rulesExportCommandAdapter{
+		cmd: commands.NewRulesExportCommand(),
+	}
+}
+
+func (a *rulesExportCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *rulesExportCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *rulesExportCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *rulesExportCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *rulesExportCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// configCommandAdapter adapts commands.ConfigCommand to the CLICommand interface.
+// This adapter also implements ParentCommand for subcommand routing.
+type configCommandAdapter struct {
+	cmd *commands.ConfigCommand
+}
+
+func newConfigCommandAdapter() *configCommandAdapter {
+	return &configCommandAdapter{
+		cmd: commands.NewConfigCommand(),
+	}
+}
+
+func (a *configCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *configCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *configCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *configCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *configCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+func (a *configCommandAdapter) Subcommands() []CLICommand {
+	return []CLICommand{
+		newConfigShowCommandAdapter(),
+	}
+}
+
+// configShowCommandAdapter adapts commands.ConfigShowCommand to the CLICommand interface.
+type configShowCommandAdapter struct {
+	cmd *commands.ConfigShowCommand
+}
+
+func newConfigShowCommandAdapter() *configShowCommandAdapter {
+	return &configShowCommandAdapter{
+		cmd: commands.NewConfigShowCommand(),
+	}
+}
+
+func (a *configShowCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *configShowCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *configShowCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *configShowCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *configShowCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// warningsCommandAdapter adapts commands.WarningsCommand to the CLICommand interface.
+// This adapter also implements ParentCommand for subcommand routing.
+type warningsCommandAdapter struct {
+	cmd *commands.WarningsCommand
+}
+
+func newWarningsCommandAdapter() *warningsCommandAdapter {
+	return &warningsCommandAdapter{
+		cmd: commands.NewWarningsCommand(),
+	}
+}
+
+func (a *warningsCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *warningsCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *warningsCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *warningsCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *warningsCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+func (a *warningsCommandAdapter) Subcommands() []CLICommand {
+	return []CLICommand{
+		newWarningsClearCommandAdapter(),
+	}
+}
+
+// warningsClearCommandAdapter adapts commands.WarningsClearCommand to the CLICommand interface.
+type warningsClearCommandAdapter struct {
+	cmd *commands.WarningsClearCommand
+}
+
+func newWarningsClearCommandAdapter() *warningsClearCommandAdapter {
+	return &warningsClearCommandAdapter{
+		cmd: commands.NewWarningsClearCommand(),
+	}
+}
+
+func (a *warningsClearCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *warningsClearCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *warningsClearCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *warningsClearCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *warningsClearCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// punishmentsCommandAdapter adapts commands.PunishmentsCommand to the CLICommand interface.
+// This adapter also implements ParentCommand for subcommand routing.
+type punishmentsCommandAdapter struct {
+	cmd *commands.PunishmentsCommand
+}
+
+func newPunishmentsCommandAdapter() *punishmentsCommandAdapter {
+	return &punishmentsCommandAdapter{
+		cmd: commands.NewPunishmentsCommand(),
+	}
+}
+
+func (a *punishmentsCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *punishmentsCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *punishmentsCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *punishmentsCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *punishmentsCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+func (a *punishmentsCommandAdapter) Subcommands() []CLICommand {
+	return []CLICommand{
+		newPunishmentsListCommandAdapter(),
+		newPunishmentsCancelCommandAdapter(),
+	}
+}
+
+// punishmentsListCommandAdapter adapts commands.PunishmentsListCommand to the CLICommand interface.
+type punishmentsListCommandAdapter struct {
+	cmd *commands.PunishmentsListCommand
+}
+
+func newPunishmentsListCommandAdapter() *punishmentsListCommandAdapter {
+	return &punishmentsListCommandAdapter{
+		cmd: commands.NewPunishmentsListCommand(),
+	}
+}
+
+func (a *punishmentsListCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *punishmentsListCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *punishmentsListCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *punishmentsListCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *punishmentsListCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// punishmentsCancelCommandAdapter adapts commands.PunishmentsCancelCommand to the CLICommand interface.
+type punishmentsCancelCommandAdapter struct {
+	cmd *commands.PunishmentsCancelCommand
+}
+
+func newPunishmentsCancelCommandAdapter() *punishmentsCancelCommandAdapter {
+	return &punishmentsCancelCommandAdapter{
+		cmd: commands.NewPunishmentsCancelCommand(),
+	}
+}
+
+func (a *punishmentsCancelCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *punishmentsCancelCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *punishmentsCancelCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *punishmentsCancelCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *punishmentsCancelCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// banCommandAdapter adapts commands.BanCommand to the CLICommand interface.
+// This adapter also implements ParentCommand for subcommand routing.
+type banCommandAdapter struct {
+	cmd *commands.BanCommand
+}
+
+func newBanCommandAdapter() *banCommandAdapter {
+	return &banCommandAdapter{
+		cmd: commands.NewBanCommand(),
+	}
+}
+
+func (a *banCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *banCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *banCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *banCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *banCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+func (a *banCommandAdapter) Subcommands() []CLICommand {
+	return []CLICommand{
+		newBanUnbanAllCommandAdapter(),
+	}
+}
+
+// banUnbanAllCommandAdapter adapts commands.BanUnbanAllCommand to the CLICommand interface.
+type banUnbanAllCommandAdapter struct {
+	cmd *commands.BanUnbanAllCommand
+}
+
+func newBanUnbanAllCommandAdapter() *banUnbanAllCommandAdapter {
+	return &banUnbanAllCommandAdapter{
+		cmd: commands.NewBanUnbanAllCommand(),
+	}
+}
+
+func (a *banUnbanAllCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *banUnbanAllCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *banUnbanAllCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *banUnbanAllCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *banUnbanAllCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// modCommandAdapter adapts commands.ModCommand to the CLICommand interface.
+// This adapter also implements ParentCommand for subcommand routing.
+type modCommandAdapter struct {
+	cmd *commands.ModCommand
+}
+
+func newModCommandAdapter() *modCommandAdapter {
+	return &modCommandAdapter{
+		cmd: commands.NewModCommand(),
+	}
+}
+
+func (a *modCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *modCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *modCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *modCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *modCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+func (a *modCommandAdapter) Subcommands() []CLICommand {
+	return []CLICommand{
+		newModActionCommandAdapter(commands.NewModBanCommand()),
+		newModActionCommandAdapter(commands.NewModKickCommand()),
+		newModActionCommandAdapter(commands.NewModMuteCommand()),
+	}
+}
+
+// modActionCommandAdapter adapts commands.ModActionCommand, used for the
+// mod ban, mod kick, and mod mute subcommands, to the CLICommand interface.
+type modActionCommandAdapter struct {
+	cmd *commands.ModActionCommand
+}
+
+func newModActionCommandAdapter(cmd *commands.ModActionCommand) *modActionCommandAdapter {
+	return &modActionCommandAdapter{
+		cmd: cmd,
+	}
+}
+
+func (a *modActionCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *modActionCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *modActionCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *modActionCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *modActionCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// commandsCommandAdapter adapts commands.CommandsCommand to the CLICommand interface.
+// This adapter also implements ParentCommand for subcommand routing.
+type commandsCommandAdapter struct {
+	cmd *commands.CommandsCommand
+}
+
+func newCommandsCommandAdapter() *commandsCommandAdapter {
+	return &commandsCommandAdapter{
+		cmd: commands.NewCommandsCommand(),
+	}
+}
+
+func (a *commandsCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *commandsCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *commandsCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *commandsCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *commandsCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+func (a *commandsCommandAdapter) Subcommands() []CLICommand {
+	return []CLICommand{
+		newCommandsListCommandAdapter(),
+		newCommandsToggleCommandAdapter(commands.NewCommandsEnableCommand()),
+		newCommandsToggleCommandAdapter(commands.NewCommandsDisableCommand()),
+		newCommandsReloadCommandAdapter(),
+	}
+}
+
+// commandsListCommandAdapter adapts commands.CommandsListCommand to the CLICommand interface.
+type commandsListCommandAdapter struct {
+	cmd *commands.CommandsListCommand
+}
+
+func newCommandsListCommandAdapter() *commandsListCommandAdapter {
+	return &commandsListCommandAdapter{
+		cmd: commands.NewCommandsListCommand(),
+	}
+}
+
+func (a *commandsListCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *commandsListCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *commandsListCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *commandsListCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *commandsListCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// commandsToggleCommandAdapter adapts commands.CommandsToggleCommand, used
+// for both commands enable and commands disable, to the CLICommand interface.
+type commandsToggleCommandAdapter struct {
+	cmd *commands.CommandsToggleCommand
+}
+
+func newCommandsToggleCommandAdapter(cmd *commands.CommandsToggleCommand) *commandsToggleCommandAdapter {
+	return &commandsToggleCommandAdapter{
+		cmd: cmd,
+	}
+}
+
+func (a *commandsToggleCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *commandsToggleCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *commandsToggleCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *commandsToggleCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *commandsToggleCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// commandsReloadCommandAdapter adapts commands.CommandsReloadCommand to the CLICommand interface.
+type commandsReloadCommandAdapter struct {
+	cmd *commands.CommandsReloadCommand
+}
+
+func newCommandsReloadCommandAdapter() *commandsReloadCommandAdapter {
+	return &commandsReloadCommandAdapter{
+		cmd: commands.NewCommandsReloadCommand(),
+	}
+}
+
+func (a *commandsReloadCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *commandsReloadCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *commandsReloadCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *commandsReloadCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *commandsReloadCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// doctorCommandAdapter adapts commands.DoctorCommand to the CLICommand interface.
+type doctorCommandAdapter struct {
+	cmd *commands.DoctorCommand
+}
+
+func newDoctorCommandAdapter() *doctorCommandAdapter {
+	return &doctorCommandAdapter{
+		cmd: commands.NewDoctorCommand(),
+	}
+}
+
+func (a *doctorCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *doctorCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *doctorCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *doctorCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *doctorCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// maintenanceCommandAdapter adapts commands.MaintenanceCommand to the CLICommand interface.
+type maintenanceCommandAdapter struct {
+	cmd *commands.MaintenanceCommand
+}
+
+func newMaintenanceCommandAdapter() *maintenanceCommandAdapter {
+	return &maintenanceCommandAdapter{
+		cmd: commands.NewMaintenanceCommand(),
+	}
+}
+
+func (a *maintenanceCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *maintenanceCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *maintenanceCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *maintenanceCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *maintenanceCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
+
+// syncCommandAdapter adapts commands.SyncCommand to the CLICommand interface.
+type syncCommandAdapter struct {
+	cmd *commands.SyncCommand
+}
+
+func newSyncCommandAdapter() *syncCommandAdapter {
+	return &syncCommandAdapter{
+		cmd: commands.NewSyncCommand(),
+	}
+}
+
+func (a *syncCommandAdapter) Name() string {
+	return a.cmd.Name()
+}
+
+func (a *syncCommandAdapter) Synopsis() string {
+	return a.cmd.Synopsis()
+}
+
+func (a *syncCommandAdapter) Usage() string {
+	return a.cmd.Usage()
+}
+
+func (a *syncCommandAdapter) SetFlags(fs *flag.FlagSet) {
+	a.cmd.SetFlags(fs)
+}
+
+func (a *syncCommandAdapter) Run(ctx *Context, args []string) int {
+	// Convert cli.Context to commands.CLIContext
+	cmdCtx := &commands.CLIContext{
+		Stdout:      ctx.Stdout,
+		Stderr:      ctx.Stderr,
+		Config:      ctx.Config,
+		APIEndpoint: ctx.APIEndpoint,
+	}
+	return a.cmd.Run(cmdCtx, args)
+}
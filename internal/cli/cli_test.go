@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"flag"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -69,7 +71,7 @@ func Test_Run_Cases(t *testing.T) {
 		{
 			name:         "unknown command returns error",
 			args:         []string{"unknown"},
-			wantExitCode: 1,
+			wantExitCode: cli.ExitUsageError,
 			wantStderr:   []string{"unknown command"},
 		},
 		{
@@ -81,7 +83,7 @@ func Test_Run_Cases(t *testing.T) {
 		{
 			name:         "rules unknown subcommand returns error",
 			args:         []string{"rules", "unknown"},
-			wantExitCode: 1,
+			wantExitCode: cli.ExitUsageError,
 			wantStderr:   []string{"unknown"},
 		},
 	}
@@ -158,10 +160,50 @@ func Test_Run_StatsCommand(t *testing.T) {
 		"stats should be a recognized command")
 
 	// Exit code depends on implementation requirements
-	assert.True(t, exitCode == 0 || exitCode == 1,
+	assert.Contains(t, []int{cli.ExitSuccess, cli.ExitFailure, cli.ExitConnectionFail}, exitCode,
 		"stats command should return valid exit code, got %d", exitCode)
 }
 
+// Test_Run_StatsSubcommands tests that stats runs on its own, with or without
+// flags, while stats top routes to its subcommand.
+func Test_Run_StatsSubcommands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stats":
+			w.Write([]byte(`{"uptime":"5m0s","commands_executed":3}`))
+		case "/stats/commands/top":
+			assert.Equal(t, "2", r.URL.Query().Get("n"))
+			w.Write([]byte(`[{"name":"ban","executions":2},{"name":"kick","executions":1}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("JAMESBOT_API_ENDPOINT", server.URL)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStdout string
+	}{
+		{name: "stats", args: []string{"stats"}, wantStdout: "Uptime: 5m0s"},
+		{name: "stats with a flag", args: []string{"stats", "--json"}, wantStdout: `"uptime": "5m0s"`},
+		{name: "stats top", args: []string{"stats", "top", "-n", "2"}, wantStdout: "   1  ban"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			exitCode := cli.Run(tt.args, stdout, stderr)
+
+			assert.Equal(t, cli.ExitSuccess, exitCode, stderr.String())
+			assert.Contains(t, stdout.String(), tt.wantStdout)
+		})
+	}
+}
+
 // Test_Run_RulesListCommand tests that rules list subcommand is routed correctly.
 func Test_Run_RulesListCommand(t *testing.T) {
 	stdout := &bytes.Buffer{}
@@ -176,7 +218,7 @@ func Test_Run_RulesListCommand(t *testing.T) {
 		"rules list should be a recognized subcommand")
 
 	// Exit code depends on implementation requirements
-	assert.True(t, exitCode == 0 || exitCode == 1,
+	assert.Contains(t, []int{cli.ExitSuccess, cli.ExitFailure, cli.ExitConnectionFail}, exitCode,
 		"rules list command should return valid exit code, got %d", exitCode)
 }
 
@@ -194,7 +236,8 @@ func Test_Run_RulesSetCommand(t *testing.T) {
 		"rules set should be a recognized subcommand")
 
 	// Exit code depends on implementation requirements
-	assert.True(t, exitCode == 0 || exitCode == 1,
+	// Missing arguments is a usage error
+	assert.Equal(t, cli.ExitUsageError, exitCode,
 		"rules set command should return valid exit code, got %d", exitCode)
 }
 
@@ -215,11 +258,29 @@ func Test_Run_MultipleUnknownCommands(t *testing.T) {
 
 			exitCode := cli.Run(args, stdout, stderr)
 
-			assert.Equal(t, 1, exitCode, "unknown command %v should return exit code 1", args)
+			assert.Equal(t, cli.ExitUsageError, exitCode, "unknown command %v should return a usage error", args)
 		})
 	}
 }
 
+// Test_Run_UnknownSubcommand tests that an unknown subcommand is a usage
+// error that lists the parent's subcommands.
+func Test_Run_UnknownSubcommand(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := cli.Run([]string{"rules", "frobnicate"}, stdout, stderr)
+
+	assert.Equal(t, cli.ExitUsageError, exitCode)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), `unknown subcommand "frobnicate" for "rules"`)
+	assert.Contains(t, stderr.String(), "Available subcommands:")
+	for _, sub := range []string{"list", "set", "import", "export", "test"} {
+		assert.Regexp(t, `(?m)^  `+sub+` +\S`, stderr.String(), "subcommand %q should be listed", sub)
+	}
+	assert.Contains(t, stderr.String(), `Use "jamesbot help rules"`)
+}
+
 // Test_Run_HelpVariations tests all variations of help requests.
 func Test_Run_HelpVariations(t *testing.T) {
 	helpArgs := [][]string{
@@ -547,17 +608,17 @@ func Test_Run_CommandParsing(t *testing.T) {
 		{
 			name:         "single dash is unknown",
 			args:         []string{"-"},
-			wantExitCode: 1,
+			wantExitCode: cli.ExitUsageError,
 		},
 		{
 			name:         "double dash alone is unknown",
 			args:         []string{"--"},
-			wantExitCode: 1,
+			wantExitCode: cli.ExitUsageError,
 		},
 		{
 			name:         "empty string argument is unknown",
 			args:         []string{""},
-			wantExitCode: 1,
+			wantExitCode: cli.ExitUsageError,
 		},
 	}
 
@@ -618,3 +679,119 @@ func Test_Run_SubcommandHelp(t *testing.T) {
 		})
 	}
 }
+
+// Test_Run_HelpCommand tests "help <command> [subcommand]".
+func Test_Run_HelpCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+		wantStdout   []string
+		wantStderr   []string
+	}{
+		{
+			name:         "help for a command prints its usage and flags",
+			args:         []string{"help", "stats"},
+			wantExitCode: 0,
+			wantStdout:   []string{"Usage: jamesbot stats", "Flags:", "-endpoint"},
+		},
+		{
+			name:         "help for a subcommand prints its usage and flags",
+			args:         []string{"help", "rules", "set"},
+			wantExitCode: 0,
+			wantStdout:   []string{"Usage: jamesbot rules set", "Flags:", "-endpoint"},
+		},
+		{
+			name:         "help for a subcommand of a runnable parent prints its usage",
+			args:         []string{"help", "stats", "top"},
+			wantExitCode: 0,
+			wantStdout:   []string{"Usage: jamesbot stats top", "Flags:", "-n"},
+		},
+		{
+			name:         "help for a parent command prints its usage",
+			args:         []string{"help", "rules"},
+			wantExitCode: 0,
+			wantStdout:   []string{"Usage: jamesbot rules"},
+		},
+		{
+			name:         "help for an unknown command fails",
+			args:         []string{"help", "bogus"},
+			wantExitCode: cli.ExitUsageError,
+			wantStderr:   []string{`unknown command "bogus"`},
+		},
+		{
+			name:         "help for an unknown subcommand fails",
+			args:         []string{"help", "rules", "bogus"},
+			wantExitCode: cli.ExitUsageError,
+			wantStderr:   []string{`unknown subcommand "bogus"`},
+		},
+		{
+			name:         "help for a subcommand of a leaf command fails",
+			args:         []string{"help", "doctor", "bogus"},
+			wantExitCode: cli.ExitUsageError,
+			wantStderr:   []string{"has no subcommands"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			exitCode := cli.Run(tt.args, stdout, stderr)
+
+			assert.Equal(t, tt.wantExitCode, exitCode)
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr.String(), want)
+			}
+		})
+	}
+}
+
+// Test_Run_HelpListsEveryCommand verifies the top-level help is built from the command tree.
+func Test_Run_HelpListsEveryCommand(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	require.Equal(t, 0, cli.Run([]string{"help"}, stdout, &bytes.Buffer{}))
+
+	for _, cmd := range cli.Commands() {
+		assert.Contains(t, stdout.String(), cmd.Synopsis(), "help should list %q", cmd.Name())
+		if parent, ok := cmd.(cli.ParentCommand); ok {
+			for _, subcmd := range parent.Subcommands() {
+				assert.Contains(t, stdout.String(), subcmd.Synopsis(),
+					"help should list %q %q", cmd.Name(), subcmd.Name())
+			}
+		}
+	}
+}
+
+// Test_Commands_SynopsisUsageConsistency verifies every command and subcommand
+// has a synopsis and a usage string that names its full invocation path.
+func Test_Commands_SynopsisUsageConsistency(t *testing.T) {
+	var check func(path string, cmd cli.CLICommand)
+	check = func(path string, cmd cli.CLICommand) {
+		t.Run(path, func(t *testing.T) {
+			assert.NotEmpty(t, strings.TrimSpace(cmd.Synopsis()), "synopsis should not be empty")
+			assert.True(t, strings.HasPrefix(cmd.Usage(), "Usage: jamesbot "+path),
+				"usage should start with %q, got %q", "Usage: jamesbot "+path, firstLine(cmd.Usage()))
+		})
+		if parent, ok := cmd.(cli.ParentCommand); ok {
+			for _, subcmd := range parent.Subcommands() {
+				check(path+" "+subcmd.Name(), subcmd)
+			}
+		}
+	}
+
+	commands := cli.Commands()
+	require.NotEmpty(t, commands)
+	for _, cmd := range commands {
+		check(cmd.Name(), cmd)
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -37,3 +37,14 @@ type ParentCommand interface {
 	// The CLI router will use this to dispatch to nested commands.
 	Subcommands() []CLICommand
 }
+
+// RunnableParent is an optional interface for parent commands that also run
+// on their own, like "jamesbot stats" alongside "jamesbot stats top".
+type RunnableParent interface {
+	ParentCommand
+
+	// RunsWithoutSubcommand reports whether the router should run the command
+	// itself, rather than print its usage, when no subcommand is named or the
+	// first argument is a flag.
+	RunsWithoutSubcommand() bool
+}
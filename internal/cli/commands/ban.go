@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"flag"
+	"strings"
+)
+
+// BanCommand is a parent command for ban management.
+// It acts as a container for subcommands like unban-all.
+type BanCommand struct{}
+
+// NewBanCommand creates a new BanCommand instance.
+func NewBanCommand() *BanCommand {
+	return &BanCommand{}
+}
+
+// Name returns the name of the command.
+func (c *BanCommand) Name() string {
+	return "ban"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *BanCommand) Synopsis() string {
+	return "Manage guild bans"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *BanCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot ban <subcommand> [options]\n\n")
+	sb.WriteString("Manage bans in a guild the bot is in.\n\n")
+	sb.WriteString("Subcommands:\n")
+	sb.WriteString("  unban-all  Unban every user listed in a file\n\n")
+	sb.WriteString("Use \"jamesbot ban <subcommand> -h\" for more information about a subcommand.\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the ban command.
+// Parent commands typically don't have their own flags.
+func (c *BanCommand) SetFlags(fs *flag.FlagSet) {
+	// No flags for parent command
+}
+
+// Run executes the ban command.
+// When invoked without a subcommand, it prints usage information.
+func (c *BanCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stdout.Write([]byte(c.Usage()))
+	return ExitOK
+}
@@ -0,0 +1,239 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+	"jamesbot/internal/control"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// BanUnbanAllCommand Tests
+// ===========================================================================
+
+// newUnbanServer returns a control API stand-in that unbans every user except
+// those whose ID starts with "9", who are not banned, and records each
+// request it receives.
+func newUnbanServer(t *testing.T, received *[]control.UnbanRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderation/unban", r.URL.Path)
+		var req control.UnbanRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*received = append(*received, req)
+
+		var resp control.UnbanResponse
+		for _, userID := range req.UserIDs {
+			if strings.HasPrefix(userID, "9") {
+				resp.Failed++
+				resp.Results = append(resp.Results, control.UnbanResult{UserID: userID, Error: "user is not banned"})
+				continue
+			}
+			resp.Succeeded++
+			resp.Results = append(resp.Results, control.UnbanResult{UserID: userID})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeBanList writes content to a ban list file and returns its path.
+func writeBanList(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bans.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func Test_BanUnbanAllCommand_Metadata(t *testing.T) {
+	cmd := commands.NewBanUnbanAllCommand()
+
+	assert.Equal(t, "unban-all", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot ban unban-all")
+}
+
+func Test_BanUnbanAllCommand_Run(t *testing.T) {
+	tests := []struct {
+		name         string
+		flags        []string
+		list         string
+		wantExit     int
+		wantUserIDs  []string
+		wantReason   string
+		wantStdout   []string
+		wantStderr   []string
+		wantRequests int
+	}{
+		{
+			name:         "unbans listed users",
+			flags:        []string{"--reason", "amnesty"},
+			list:         "# exported bans\n111\n\n222,someone\n333\tsomeone else\n111\n",
+			wantExit:     commands.ExitOK,
+			wantUserIDs:  []string{"111", "222", "333"},
+			wantReason:   "amnesty",
+			wantStdout:   []string{"ok    111", "ok    333", "Unbanned 3 user(s) in guild guild-1, 0 failed"},
+			wantRequests: 1,
+		},
+		{
+			name:         "reports users who are not banned",
+			list:         "111\n999\n",
+			wantExit:     commands.ExitAPIError,
+			wantUserIDs:  []string{"111", "999"},
+			wantStdout:   []string{"ok    111", "Unbanned 1 user(s) in guild guild-1, 1 failed"},
+			wantStderr:   []string{"FAIL  999: user is not banned"},
+			wantRequests: 1,
+		},
+		{
+			name:       "empty list",
+			list:       "# nothing here\n\n",
+			wantExit:   commands.ExitError,
+			wantStderr: []string{"No user IDs found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []control.UnbanRequest
+			server := newUnbanServer(t, &received)
+
+			cmd := commands.NewBanUnbanAllCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(append(tt.flags, "guild-1", writeBanList(t, tt.list))))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, stderr.String())
+			require.Len(t, received, tt.wantRequests)
+			if tt.wantRequests > 0 {
+				assert.Equal(t, control.UnbanRequest{GuildID: "guild-1", UserIDs: tt.wantUserIDs, Reason: tt.wantReason}, received[0])
+			}
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr.String(), want)
+			}
+		})
+	}
+}
+
+func Test_BanUnbanAllCommand_Run_Batches(t *testing.T) {
+	var received []control.UnbanRequest
+	server := newUnbanServer(t, &received)
+
+	var list strings.Builder
+	for i := 1; i <= control.MaxUnbanUsers+5; i++ {
+		fmt.Fprintf(&list, "%d\n", 1000+i)
+	}
+
+	stdout := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+	exitCode := commands.NewBanUnbanAllCommand().Run(ctx, []string{"guild-1", writeBanList(t, list.String())})
+
+	assert.Equal(t, commands.ExitOK, exitCode)
+	require.Len(t, received, 2)
+	assert.Len(t, received[0].UserIDs, control.MaxUnbanUsers)
+	assert.Len(t, received[1].UserIDs, 5)
+	assert.Contains(t, stdout.String(), fmt.Sprintf("Unbanned %d user(s)", control.MaxUnbanUsers+5))
+}
+
+func Test_BanUnbanAllCommand_Run_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantExit   int
+		wantStderr string
+	}{
+		{
+			name:       "missing file is a usage error",
+			args:       []string{"guild-1"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Missing required arguments",
+		},
+		{
+			name:       "unreadable file",
+			args:       []string{"guild-1", filepath.Join(t.TempDir(), "missing.txt")},
+			wantExit:   commands.ExitError,
+			wantStderr: "Failed to read ban list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://localhost:1"}
+
+			exitCode := commands.NewBanUnbanAllCommand().Run(ctx, tt.args)
+
+			assert.Equal(t, tt.wantExit, exitCode)
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+}
+
+func Test_BanUnbanAllCommand_Run_ConnectionError(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://localhost:1"}
+
+	exitCode := commands.NewBanUnbanAllCommand().Run(ctx, []string{"guild-1", writeBanList(t, "111\n")})
+
+	assert.Equal(t, commands.ExitConnectionError, exitCode)
+	assert.Contains(t, stderr.String(), "Cannot connect")
+}
+
+func Test_BanUnbanAllCommand_Run_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer wrong", r.Header.Get("Authorization"))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(commands.AuthTokenEnvVar, "wrong")
+
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: server.URL}
+
+	exitCode := commands.NewBanUnbanAllCommand().Run(ctx, []string{"guild-1", writeBanList(t, "111\n")})
+
+	assert.Equal(t, commands.ExitAPIError, exitCode)
+	assert.Contains(t, stderr.String(), commands.AuthTokenEnvVar)
+}
+
+func Test_BanUnbanAllCommand_Run_Quiet(t *testing.T) {
+	var received []control.UnbanRequest
+	server := newUnbanServer(t, &received)
+
+	cmd := commands.NewBanUnbanAllCommand()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--quiet", "guild-1", writeBanList(t, "111\n")}))
+
+	stdout := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+	exitCode := cmd.Run(ctx, fs.Args())
+
+	assert.Equal(t, commands.ExitOK, exitCode)
+	assert.Empty(t, stdout.String())
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"jamesbot/internal/control"
+)
+
+// BanUnbanAllCommand implements the ban unban-all command for lifting the
+// bans on every user listed in a file.
+type BanUnbanAllCommand struct {
+	reason        string
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewBanUnbanAllCommand creates a new BanUnbanAllCommand instance.
+func NewBanUnbanAllCommand() *BanUnbanAllCommand {
+	return &BanUnbanAllCommand{}
+}
+
+// Name returns the name of the command.
+func (c *BanUnbanAllCommand) Name() string {
+	return "unban-all"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *BanUnbanAllCommand) Synopsis() string {
+	return "Unban every user listed in a file"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *BanUnbanAllCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot ban unban-all [options] <guild-id> <file>\n\n")
+	sb.WriteString("Lift the bans on every user listed in a file, such as when migrating servers\n")
+	sb.WriteString("or reversing a mass ban. The file lists one user ID per line; blank lines\n")
+	sb.WriteString("and lines starting with # are skipped, and anything after the ID on a line,\n")
+	sb.WriteString("separated by a comma or whitespace, is ignored.\n")
+	sb.WriteString("Every user is attempted; failures, including users who are not banned, are\n")
+	sb.WriteString("reported without stopping the rest.\n")
+	sb.WriteString("The bot API requires the auth token in $" + AuthTokenEnvVar + ".\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  <guild-id>  ID of the guild to unban the users in\n")
+	sb.WriteString("  <file>      Path to the list of user IDs\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  --reason <text>     Reason recorded in the guild's audit log\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n\n")
+	sb.WriteString("Examples:\n")
+	sb.WriteString("  jamesbot ban unban-all 123456789012345678 bans.txt\n")
+	sb.WriteString("  jamesbot ban unban-all --reason \"Amnesty\" 123456789012345678 bans.csv\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the ban unban-all command.
+func (c *BanUnbanAllCommand) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.reason, "reason", "", "Reason recorded in the guild's audit log")
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the ban unban-all command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *BanUnbanAllCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Validate arguments
+	if len(args) < 2 {
+		fmt.Fprintf(stderr, "Error: Missing required arguments\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	guildID := args[0]
+	userIDs, err := readBanList(args[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to read ban list: %v\n", err)
+		return ExitError
+	}
+	if len(userIDs) == 0 {
+		fmt.Fprintf(stderr, "Error: No user IDs found in %s\n", args[1])
+		return ExitError
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	// Create API client, authenticated for the moderation endpoints
+	client, err := newModerationClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	// Unban in batches the control API accepts, reporting each as it completes
+	succeeded, failed := 0, 0
+	for start := 0; start < len(userIDs); start += control.MaxUnbanUsers {
+		end := min(start+control.MaxUnbanUsers, len(userIDs))
+
+		result, err := client.Unban(guildID, userIDs[start:end], c.reason)
+		if err != nil {
+			if start > 0 {
+				fmt.Fprintf(stderr, "Stopped after %d of %d user(s)\n", start, len(userIDs))
+			}
+
+			if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+				return ExitConnectionError
+			}
+			if errors.Is(err, control.ErrUnauthorized) {
+				writeUnauthorized(stderr)
+				return ExitAPIError
+			}
+
+			// Other API errors
+			fmt.Fprintf(stderr, "Error: Failed to unban users: %v\n", err)
+			return ExitAPIError
+		}
+
+		for _, r := range result.Results {
+			if r.Error != "" {
+				fmt.Fprintf(stderr, "FAIL  %s: %s\n", r.UserID, r.Error)
+				continue
+			}
+			if !c.quiet {
+				fmt.Fprintf(stdout, "ok    %s\n", r.UserID)
+			}
+		}
+		succeeded += result.Succeeded
+		failed += result.Failed
+	}
+
+	if !c.quiet {
+		fmt.Fprintf(stdout, "Unbanned %d user(s) in guild %s, %d failed\n", succeeded, guildID, failed)
+	}
+
+	if failed > 0 {
+		return ExitAPIError
+	}
+	return ExitOK
+}
+
+// readBanList reads user IDs from path, one per line, in file order without
+// duplicates. Blank lines and lines starting with # are skipped, and only the
+// first comma- or whitespace-separated field of a line is used, so exported
+// ban lists with a name column can be read as-is.
+func readBanList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var userIDs []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		if len(fields) == 0 || seen[fields[0]] {
+			continue
+		}
+		userID := fields[0]
+		seen[userID] = true
+		userIDs = append(userIDs, userID)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"flag"
+	"strings"
+)
+
+// CommandsCommand is a parent command for managing the bot's slash commands.
+// It acts as a container for subcommands like list, enable, and disable.
+type CommandsCommand struct{}
+
+// NewCommandsCommand creates a new CommandsCommand instance.
+func NewCommandsCommand() *CommandsCommand {
+	return &CommandsCommand{}
+}
+
+// Name returns the name of the command.
+func (c *CommandsCommand) Name() string {
+	return "commands"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *CommandsCommand) Synopsis() string {
+	return "Enable, disable, or reload slash commands on a running bot"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *CommandsCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot commands <subcommand> [options]\n\n")
+	sb.WriteString("Inspect, toggle, and reload the slash commands of a running bot without\n")
+	sb.WriteString("restarting it. Commands stay enabled or disabled until the bot restarts;\n")
+	sb.WriteString("use commands.disabled in the config file to keep a command off.\n\n")
+	sb.WriteString("Subcommands:\n")
+	sb.WriteString("  list     List registered commands and whether each is enabled\n")
+	sb.WriteString("  enable   Allow a disabled command to run again\n")
+	sb.WriteString("  disable  Stop a command from running\n")
+	sb.WriteString("  reload   Rebuild the commands and sync them with Discord\n\n")
+	sb.WriteString("Use \"jamesbot commands <subcommand> -h\" for more information about a subcommand.\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the commands command.
+// Parent commands typically don't have their own flags.
+func (c *CommandsCommand) SetFlags(fs *flag.FlagSet) {
+	// No flags for parent command
+}
+
+// Run executes the commands command.
+// When invoked without a subcommand, it prints usage information.
+func (c *CommandsCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stdout.Write([]byte(c.Usage()))
+	return ExitOK
+}
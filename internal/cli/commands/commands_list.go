@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"jamesbot/internal/control"
+)
+
+// CommandsListCommand implements the commands list command for showing which
+// slash commands are enabled.
+type CommandsListCommand struct {
+	jsonOutput    bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewCommandsListCommand creates a new CommandsListCommand instance.
+func NewCommandsListCommand() *CommandsListCommand {
+	return &CommandsListCommand{}
+}
+
+// Name returns the name of the command.
+func (c *CommandsListCommand) Name() string {
+	return "list"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *CommandsListCommand) Synopsis() string {
+	return "List registered commands and whether each is enabled"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *CommandsListCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot commands list [options]\n\n")
+	sb.WriteString("List the slash commands registered by the running bot.\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  --json              Output commands as JSON instead of human-readable format\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the commands list command.
+func (c *CommandsListCommand) SetFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.jsonOutput, "json", false, "Output commands as JSON")
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the commands list command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *CommandsListCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+	states, err := client.ListCommands()
+	if err != nil {
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		// Other API errors
+		fmt.Fprintf(stderr, "Error: Failed to get commands: %v\n", err)
+		return ExitAPIError
+	}
+
+	if err := renderList(stdout, states, c.jsonOutput, "No commands registered", writeCommandsTable); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to write commands: %v\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
+
+// writeCommandsTable writes commands as an aligned name, enabled, and description table.
+func writeCommandsTable(w io.Writer, states []control.CommandState) {
+	maxNameLen := len("Name")
+	for _, s := range states {
+		if len(s.Name) > maxNameLen {
+			maxNameLen = len(s.Name)
+		}
+	}
+
+	fmt.Fprintf(w, "%-*s  %-7s  %s\n", maxNameLen, "Name", "Enabled", "Description")
+	fmt.Fprintf(w, "%s  %s  %s\n", strings.Repeat("-", maxNameLen), strings.Repeat("-", 7), strings.Repeat("-", len("Description")))
+	for _, s := range states {
+		enabled := "false"
+		if s.Enabled {
+			enabled = "true"
+		}
+		fmt.Fprintf(w, "%-*s  %-7s  %s\n", maxNameLen, s.Name, enabled, s.Description)
+	}
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"jamesbot/internal/control"
+)
+
+// CommandsReloadCommand implements the commands reload command, which has a
+// running bot rebuild its slash commands and sync them with Discord.
+type CommandsReloadCommand struct {
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewCommandsReloadCommand creates a new CommandsReloadCommand instance.
+func NewCommandsReloadCommand() *CommandsReloadCommand {
+	return &CommandsReloadCommand{}
+}
+
+// Name returns the name of the command.
+func (c *CommandsReloadCommand) Name() string {
+	return "reload"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *CommandsReloadCommand) Synopsis() string {
+	return "Rebuild the commands and sync them with Discord"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *CommandsReloadCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot commands reload [options]\n\n")
+	sb.WriteString("Rebuild a running bot's slash commands from their current definitions,\n")
+	sb.WriteString("including those of plugins, and sync them with Discord as on startup,\n")
+	sb.WriteString("without restarting the bot. Prints the commands created, updated, and\n")
+	sb.WriteString("deleted. The bot API requires the auth token in $" + AuthTokenEnvVar + ".\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the commands reload command.
+func (c *CommandsReloadCommand) SetFlags(fs *flag.FlagSet) {
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the commands reload command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *CommandsReloadCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	// Reloading touches Discord, so it is authenticated like moderation
+	client, err := newModerationClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+	result, err := client.ReloadCommands()
+	if err != nil {
+		if errors.Is(err, control.ErrUnauthorized) {
+			writeUnauthorized(stderr)
+			return ExitAPIError
+		}
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		// Other API errors
+		fmt.Fprintf(stderr, "Error: Failed to reload commands: %v\n", err)
+		return ExitAPIError
+	}
+
+	if c.quiet {
+		return ExitOK
+	}
+	fmt.Fprintf(stdout, "Reloaded commands: %d created, %d updated, %d deleted, %d unchanged\n",
+		len(result.Created), len(result.Updated), len(result.Deleted), result.Unchanged)
+	for _, name := range result.Created {
+		fmt.Fprintf(stdout, "  + %s\n", name)
+	}
+	for _, name := range result.Updated {
+		fmt.Fprintf(stdout, "  ~ %s\n", name)
+	}
+	for _, name := range result.Deleted {
+		fmt.Fprintf(stdout, "  - %s\n", name)
+	}
+	if len(result.Created)+len(result.Updated)+len(result.Deleted) == 0 {
+		fmt.Fprintf(stdout, "Discord is already up to date\n")
+	}
+	return ExitOK
+}
@@ -0,0 +1,257 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+	"jamesbot/internal/control"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commandsBot serves toggleable commands through the control API.
+type commandsBot struct {
+	rulesBot
+	states []control.CommandState
+}
+
+func (b *commandsBot) CommandStates() []control.CommandState { return b.states }
+
+func (b *commandsBot) SetCommandEnabled(name string, enabled bool) error {
+	for i := range b.states {
+		if b.states[i].Name == name {
+			b.states[i].Enabled = enabled
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", control.ErrCommandNotFound, name)
+}
+
+// reloaderBot serves command reloading through the control API, reporting
+// result, or failing with err.
+type reloaderBot struct {
+	rulesBot
+	result control.CommandReloadResult
+	err    error
+}
+
+func (b *reloaderBot) ReloadCommands() (*control.CommandReloadResult, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	result := b.result
+	return &result, nil
+}
+
+// newCommandsServer starts a control API server for a bot with ban and ping commands.
+func newCommandsServer(t *testing.T) (*httptest.Server, *commandsBot) {
+	t.Helper()
+	bot := &commandsBot{states: []control.CommandState{
+		{Name: "ban", Description: "Ban a member from the server", Enabled: true},
+		{Name: "ping", Description: "Check bot latency", Enabled: true},
+	}}
+	server := httptest.NewServer(control.NewServer(0, bot, zerolog.New(io.Discard), control.WithAuthToken("secret")).Handler())
+	t.Cleanup(server.Close)
+	return server, bot
+}
+
+// runCLICommand parses args with cmd's flags and runs it against endpoint.
+func runCLICommand(t *testing.T, cmd interface {
+	SetFlags(*flag.FlagSet)
+	Run(*commands.CLIContext, []string) int
+}, endpoint string, args ...string) (int, string, string) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cmd.SetFlags(fs)
+	require.NoError(t, fs.Parse(args))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: endpoint}
+	return cmd.Run(ctx, fs.Args()), stdout.String(), stderr.String()
+}
+
+// ===========================================================================
+// Commands Tests
+// ===========================================================================
+
+func Test_CommandsCommands_Metadata(t *testing.T) {
+	tests := []struct {
+		cmd interface {
+			Name() string
+			Synopsis() string
+			Usage() string
+		}
+		name  string
+		usage string
+	}{
+		{cmd: commands.NewCommandsCommand(), name: "commands", usage: "Usage: jamesbot commands <subcommand>"},
+		{cmd: commands.NewCommandsListCommand(), name: "list", usage: "Usage: jamesbot commands list"},
+		{cmd: commands.NewCommandsEnableCommand(), name: "enable", usage: "Usage: jamesbot commands enable <name>"},
+		{cmd: commands.NewCommandsDisableCommand(), name: "disable", usage: "Usage: jamesbot commands disable <name>"},
+		{cmd: commands.NewCommandsReloadCommand(), name: "reload", usage: "Usage: jamesbot commands reload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.name, tt.cmd.Name())
+			assert.NotEmpty(t, tt.cmd.Synopsis())
+			assert.Contains(t, tt.cmd.Usage(), tt.usage)
+		})
+	}
+}
+
+func Test_CommandsListCommand_Run(t *testing.T) {
+	server, bot := newCommandsServer(t)
+	bot.states[0].Enabled = false
+
+	exit, stdout, stderr := runCLICommand(t, commands.NewCommandsListCommand(), server.URL)
+
+	require.Equal(t, commands.ExitOK, exit, stderr)
+	assert.Contains(t, stdout, "Name  Enabled  Description")
+	assert.Contains(t, stdout, "ban   false    Ban a member from the server")
+	assert.Contains(t, stdout, "ping  true     Check bot latency")
+}
+
+func Test_CommandsListCommand_Run_JSON(t *testing.T) {
+	server, bot := newCommandsServer(t)
+
+	exit, stdout, stderr := runCLICommand(t, commands.NewCommandsListCommand(), server.URL, "--json")
+
+	require.Equal(t, commands.ExitOK, exit, stderr)
+	var got []control.CommandState
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	assert.Equal(t, bot.states, got)
+}
+
+func Test_CommandsToggleCommand_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		enable      bool
+		args        []string
+		wantExit    int
+		wantBan     bool
+		wantStdout  string
+		wantStderr  string
+		wantSilent  bool
+		unreachable bool
+		noToken     bool
+	}{
+		{name: "disable", args: []string{"ban"}, wantExit: commands.ExitOK, wantBan: false, wantStdout: "Command /ban disabled"},
+		{name: "disable with leading slash", args: []string{"/ban"}, wantExit: commands.ExitOK, wantBan: false},
+		{name: "enable", enable: true, args: []string{"ban"}, wantExit: commands.ExitOK, wantBan: true, wantStdout: "Command /ban enabled"},
+		{name: "quiet", args: []string{"-q", "ban"}, wantExit: commands.ExitOK, wantBan: false, wantSilent: true},
+		{name: "unknown command", args: []string{"nope"}, wantExit: commands.ExitAPIError, wantBan: true, wantStderr: `No command named "nope"`},
+		{name: "missing name", wantExit: commands.ExitUsage, wantBan: true, wantStderr: "Missing required argument"},
+		{name: "missing token", args: []string{"ban"}, noToken: true, wantExit: commands.ExitAPIError, wantBan: true, wantStderr: commands.AuthTokenEnvVar},
+		{name: "bot not running", args: []string{"ban"}, unreachable: true, wantExit: commands.ExitConnectionError, wantBan: true, wantStderr: "Cannot connect"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := "secret"
+			if tt.noToken {
+				token = ""
+			}
+			t.Setenv(commands.AuthTokenEnvVar, token)
+			server, bot := newCommandsServer(t)
+			bot.states[0].Enabled = !tt.enable
+			endpoint := server.URL
+			if tt.unreachable {
+				endpoint = "http://localhost:1"
+			}
+
+			cmd := commands.NewCommandsDisableCommand()
+			if tt.enable {
+				cmd = commands.NewCommandsEnableCommand()
+			}
+			exit, stdout, stderr := runCLICommand(t, cmd, endpoint, tt.args...)
+
+			assert.Equal(t, tt.wantExit, exit, stderr)
+			assert.Equal(t, tt.wantBan, bot.states[0].Enabled)
+			if tt.wantStdout != "" {
+				assert.Contains(t, stdout, tt.wantStdout)
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr, tt.wantStderr)
+			}
+			if tt.wantSilent {
+				assert.Empty(t, stdout)
+			}
+		})
+	}
+}
+
+func Test_CommandsReloadCommand_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		token       string
+		result      control.CommandReloadResult
+		reloadErr   error
+		unreachable bool
+		wantExit    int
+		wantStdout  []string
+		wantStderr  string
+		wantSilent  bool
+	}{
+		{
+			name:     "reload",
+			token:    "secret",
+			result:   control.CommandReloadResult{Created: []string{"poll"}, Updated: []string{"ban"}, Deleted: []string{"echo"}, Unchanged: 2},
+			wantExit: commands.ExitOK,
+			wantStdout: []string{
+				"Reloaded commands: 1 created, 1 updated, 1 deleted, 2 unchanged",
+				"  + poll", "  ~ ban", "  - echo",
+			},
+		},
+		{
+			name:       "nothing changed",
+			token:      "secret",
+			result:     control.CommandReloadResult{Unchanged: 3},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"Discord is already up to date"},
+		},
+		{name: "quiet", args: []string{"-q"}, token: "secret", wantExit: commands.ExitOK, wantSilent: true},
+		{name: "missing token", wantExit: commands.ExitAPIError, wantStderr: commands.AuthTokenEnvVar},
+		{name: "reload fails", token: "secret", reloadErr: errors.New("discord unavailable"),
+			wantExit: commands.ExitAPIError, wantStderr: "Failed to reload commands: reload failed: discord unavailable"},
+		{name: "bot not running", token: "secret", unreachable: true, wantExit: commands.ExitConnectionError, wantStderr: "Cannot connect"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(commands.AuthTokenEnvVar, tt.token)
+			bot := &reloaderBot{result: tt.result, err: tt.reloadErr}
+			server := httptest.NewServer(control.NewServer(0, bot, zerolog.New(io.Discard), control.WithAuthToken("secret")).Handler())
+			t.Cleanup(server.Close)
+			endpoint := server.URL
+			if tt.unreachable {
+				endpoint = "http://localhost:1"
+			}
+
+			exit, stdout, stderr := runCLICommand(t, commands.NewCommandsReloadCommand(), endpoint, tt.args...)
+
+			assert.Equal(t, tt.wantExit, exit, stderr)
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout, want)
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr, tt.wantStderr)
+			}
+			if tt.wantSilent {
+				assert.Empty(t, stdout)
+			}
+		})
+	}
+}
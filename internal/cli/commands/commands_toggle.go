@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"jamesbot/internal/control"
+)
+
+// CommandsToggleCommand implements the commands enable and commands disable
+// commands, which differ only in the state they set.
+type CommandsToggleCommand struct {
+	enable        bool
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewCommandsEnableCommand creates the commands enable command.
+func NewCommandsEnableCommand() *CommandsToggleCommand {
+	return &CommandsToggleCommand{enable: true}
+}
+
+// NewCommandsDisableCommand creates the commands disable command.
+func NewCommandsDisableCommand() *CommandsToggleCommand {
+	return &CommandsToggleCommand{enable: false}
+}
+
+// Name returns the name of the command.
+func (c *CommandsToggleCommand) Name() string {
+	if c.enable {
+		return "enable"
+	}
+	return "disable"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *CommandsToggleCommand) Synopsis() string {
+	if c.enable {
+		return "Allow a disabled command to run again"
+	}
+	return "Stop a command from running"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *CommandsToggleCommand) Usage() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Usage: jamesbot commands %s <name> [options]\n\n", c.Name())
+	if c.enable {
+		sb.WriteString("Let a command disabled with 'jamesbot commands disable' run again.\n")
+	} else {
+		sb.WriteString("Stop a command from running without restarting the bot. Members who\n")
+		sb.WriteString("use it are told it is disabled. The command comes back on restart.\n")
+	}
+	sb.WriteString("The bot API requires the auth token in $" + AuthTokenEnvVar + ".\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  <name>  Name of the slash command, without the leading /\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the command.
+func (c *CommandsToggleCommand) SetFlags(fs *flag.FlagSet) {
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *CommandsToggleCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Validate arguments
+	if len(args) < 1 {
+		fmt.Fprintf(stderr, "Error: Missing required argument <name>\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+	name := strings.TrimPrefix(args[0], "/")
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	client, err := newModerationClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+	if err := client.SetCommandEnabled(name, c.enable); err != nil {
+		switch {
+		case errors.Is(err, control.ErrCommandNotFound):
+			fmt.Fprintf(stderr, "Error: No command named %q is registered; see 'jamesbot commands list'\n", name)
+			return ExitAPIError
+		case errors.Is(err, control.ErrUnauthorized):
+			writeUnauthorized(stderr)
+			return ExitAPIError
+		}
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		// Other API errors
+		fmt.Fprintf(stderr, "Error: Failed to %s command: %v\n", c.Name(), err)
+		return ExitAPIError
+	}
+
+	if c.quiet {
+		return ExitOK
+	}
+	fmt.Fprintf(stdout, "Command /%s %sd\n", name, c.Name())
+	return ExitOK
+}
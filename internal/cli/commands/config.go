@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"flag"
+	"strings"
+)
+
+// ConfigCommand is a parent command for inspecting configuration.
+// It acts as a container for subcommands like show.
+type ConfigCommand struct{}
+
+// NewConfigCommand creates a new ConfigCommand instance.
+func NewConfigCommand() *ConfigCommand {
+	return &ConfigCommand{}
+}
+
+// Name returns the name of the command.
+func (c *ConfigCommand) Name() string {
+	return "config"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *ConfigCommand) Synopsis() string {
+	return "Inspect bot configuration"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *ConfigCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot config <subcommand> [options]\n\n")
+	sb.WriteString("Inspect the configuration the bot would run with.\n\n")
+	sb.WriteString("Subcommands:\n")
+	sb.WriteString("  show   Print the resolved configuration with secrets redacted\n\n")
+	sb.WriteString("Use \"jamesbot config <subcommand> -h\" for more information about a subcommand.\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the config command.
+// Parent commands typically don't have their own flags.
+func (c *ConfigCommand) SetFlags(fs *flag.FlagSet) {
+	// No flags for parent command
+}
+
+// Run executes the config command.
+// When invoked without a subcommand, it prints usage information.
+func (c *ConfigCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stdout.Write([]byte(c.Usage()))
+	return ExitOK
+}
@@ -0,0 +1,55 @@
+package commands
+
+import "jamesbot/internal/config"
+
+// resolvedConfig is the configuration in effect after file discovery and
+// environment overrides, along with where it came from.
+type resolvedConfig struct {
+	cfg *config.Config
+
+	// path is the discovered config file, or empty if none was found.
+	path string
+
+	// searched lists the locations checked during discovery.
+	searched []string
+
+	// fileErr is set when path was found but could not be loaded, in which
+	// case cfg was built from environment variables only.
+	fileErr error
+}
+
+// resolveConfig discovers and loads configuration the way serve does: the
+// explicit --config path, if passed, must exist and no other is searched, and
+// a file that fails to load falls back to environment variables only. If that
+// fails too, the file's error is returned, as the one more likely to explain
+// the problem. overrides, such as those of command-line flags, are applied
+// either way.
+func resolveConfig(configPath *stringValue, overrides ...config.Override) (*resolvedConfig, error) {
+	explicit := ""
+	if configPath != nil && configPath.set {
+		explicit = configPath.value
+	}
+
+	path, err := config.Discover(explicit)
+	if err != nil {
+		return nil, err
+	}
+	r := &resolvedConfig{
+		path:     path,
+		searched: config.SearchPaths(explicit),
+	}
+
+	cfg, err := config.Load(r.path, overrides...)
+	if err != nil && r.path != "" {
+		r.fileErr = err
+		if cfg, err = config.Load("", overrides...); err != nil {
+			return nil, r.fileErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.cfg = cfg
+	return r, nil
+}
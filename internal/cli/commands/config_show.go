@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// ConfigShowCommand prints the resolved configuration with secrets redacted.
+type ConfigShowCommand struct {
+	configPath stringValue
+}
+
+// NewConfigShowCommand creates a new ConfigShowCommand instance.
+func NewConfigShowCommand() *ConfigShowCommand {
+	return &ConfigShowCommand{}
+}
+
+// Name returns the name of the command.
+func (c *ConfigShowCommand) Name() string {
+	return "show"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *ConfigShowCommand) Synopsis() string {
+	return "Print the resolved configuration with secrets redacted"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *ConfigShowCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot config show [options]\n\n")
+	sb.WriteString("Load configuration exactly as 'jamesbot serve' would, applying file\n")
+	sb.WriteString("discovery and environment overrides, and print the result. Secrets\n")
+	sb.WriteString("such as the Discord token are redacted.\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  -c, --config <path>  Path to config file (searched like serve)\n")
+	sb.WriteString("  -h, --help           Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the config show command.
+func (c *ConfigShowCommand) SetFlags(fs *flag.FlagSet) {
+	c.configPath = stringValue{value: "config/config.yaml"}
+	fs.Var(&c.configPath, "c", "Path to config file")
+	fs.Var(&c.configPath, "config", "Path to config file")
+}
+
+// Run executes the config show command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *ConfigShowCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	resolved, err := resolveConfig(&c.configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to load configuration: %v\n", err)
+		return ExitConfigError
+	}
+
+	switch {
+	case resolved.fileErr != nil:
+		fmt.Fprintf(stdout, "# config file: %s (failed to load: %v; using environment only)\n", resolved.path, resolved.fileErr)
+	case resolved.path != "":
+		fmt.Fprintf(stdout, "# config file: %s\n", resolved.path)
+	default:
+		fmt.Fprintf(stdout, "# config file: none found (using environment only)\n")
+	}
+
+	for _, setting := range resolved.cfg.Redacted().Settings() {
+		fmt.Fprintf(stdout, "%s = %s\n", setting.Key, setting.Value)
+	}
+
+	return ExitOK
+}
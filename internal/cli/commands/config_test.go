@@ -0,0 +1,100 @@
+package commands_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// ConfigShowCommand Tests
+// ===========================================================================
+
+func runConfigShow(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	cmd := commands.NewConfigShowCommand()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+	require.NoError(t, fs.Parse(args))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	exitCode := cmd.Run(&commands.CLIContext{Stdout: stdout, Stderr: stderr}, fs.Args())
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func Test_ConfigShowCommand_Run(t *testing.T) {
+	const token = "file-token-abcdef-98765"
+
+	tests := []struct {
+		name        string
+		fileToken   string
+		envToken    string
+		wantExit    int
+		wantStdout  []string
+		wantStderr  string
+		forbidToken string
+	}{
+		{
+			name:        "file token is redacted",
+			fileToken:   token,
+			wantExit:    commands.ExitOK,
+			wantStdout:  []string{"discord.token = file****8765", "# config file: "},
+			forbidToken: token,
+		},
+		{
+			name:        "env override is shown redacted",
+			fileToken:   token,
+			envToken:    "env-token-0000-1111",
+			wantExit:    commands.ExitOK,
+			wantStdout:  []string{"discord.token = env-****1111"},
+			forbidToken: "env-token-0000-1111",
+		},
+		{
+			name:       "missing token is a config error",
+			wantExit:   commands.ExitConfigError,
+			wantStderr: "token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("JAMESBOT_CONFIG", "")
+			t.Setenv("JAMESBOT_DISCORD_TOKEN", tt.envToken)
+			if tt.envToken == "" {
+				os.Unsetenv("JAMESBOT_DISCORD_TOKEN")
+			}
+
+			var args []string
+			if tt.fileToken != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				require.NoError(t, os.WriteFile(path, []byte("discord:\n  token: "+tt.fileToken+"\n"), 0o600))
+				args = []string{"--config", path}
+			}
+
+			exitCode, stdout, stderr := runConfigShow(t, args...)
+
+			assert.Equal(t, tt.wantExit, exitCode, "stderr: %s", stderr)
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout, want)
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr, tt.wantStderr)
+			}
+			if tt.forbidToken != "" {
+				assert.NotContains(t, stdout+stderr, tt.forbidToken, "secret must never be printed")
+			}
+		})
+	}
+}
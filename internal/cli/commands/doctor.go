@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"jamesbot/internal/config"
+	"jamesbot/internal/control"
+	"jamesbot/internal/rules"
+	"jamesbot/pkg/errutil"
+)
+
+// Doctor check outcomes.
+const (
+	checkPass = "PASS"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+	checkSkip = "SKIP"
+)
+
+// privilegedIntents lists the privileged gateway intents the bot can request.
+var privilegedIntents = []string{"Message Content", "Server Members"}
+
+// ruleIntents maps each rule that only works with a privileged intent to it.
+var ruleIntents = map[string]string{
+	rules.RuleWordFilter: "Message Content",
+	rules.RuleLinkFilter: "Message Content",
+	rules.RuleWelcome:    "Server Members",
+	rules.RuleGoodbye:    "Server Members",
+}
+
+// doctorCheck is the outcome of one doctor check. Only FAIL results make the
+// command exit non-zero; WARN results are printed with a hint but tolerated.
+type doctorCheck struct {
+	name   string
+	status string
+	detail string
+	hint   string
+}
+
+// DoctorCommand implements the doctor command, which checks the operator's
+// environment for the usual reasons the bot will not start or misbehaves.
+type DoctorCommand struct {
+	configPath stringValue
+	endpoint   endpointFlags
+}
+
+// NewDoctorCommand creates a new DoctorCommand instance.
+func NewDoctorCommand() *DoctorCommand {
+	return &DoctorCommand{}
+}
+
+// Name returns the name of the command.
+func (c *DoctorCommand) Name() string {
+	return "doctor"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *DoctorCommand) Synopsis() string {
+	return "Check the configuration and environment for common problems"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *DoctorCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot doctor [options]\n\n")
+	sb.WriteString("Run a checklist of the things 'jamesbot serve' needs: a loadable config\n")
+	sb.WriteString("file, a well-formed Discord token, a reachable control API, and the\n")
+	sb.WriteString("gateway intents required by the enabled rules. Each failed or doubtful\n")
+	sb.WriteString("check is printed with a hint. Exits non-zero if any check fails.\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  -c, --config <path>  Path to config file (searched like serve)\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString("  -h, --help           Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the doctor command.
+func (c *DoctorCommand) SetFlags(fs *flag.FlagSet) {
+	c.configPath = stringValue{value: "config/config.yaml"}
+	fs.Var(&c.configPath, "c", "Path to config file")
+	fs.Var(&c.configPath, "config", "Path to config file")
+	addEndpointFlag(fs, &c.endpoint)
+}
+
+// Run executes the doctor command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *DoctorCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+
+	checks := c.checkConfig()
+	checks = append(checks, c.checkControlAPI(resolveEndpoint(ctx, &c.endpoint))...)
+
+	failed := writeChecks(stdout, checks)
+	if failed > 0 {
+		fmt.Fprintf(stdout, "\n%d check(s) failed\n", failed)
+		return ExitError
+	}
+
+	fmt.Fprintf(stdout, "\nAll required checks passed\n")
+	return ExitOK
+}
+
+// checkConfig checks that the config file loads and that the Discord token
+// it resolves to (from the file or the environment) is well-formed.
+func (c *DoctorCommand) checkConfig() []doctorCheck {
+	explicit := ""
+	if c.configPath.set {
+		explicit = c.configPath.value
+	}
+	path, discoverErr := config.Discover(explicit)
+
+	file := doctorCheck{name: "Config file"}
+	cfg, err := config.Load(path)
+	switch {
+	case discoverErr != nil:
+		file.status = checkFail
+		file.detail = discoverErr.Error()
+		file.hint = "check the path passed with --config"
+	case path == "":
+		file.status = checkWarn
+		file.detail = "none found, using environment variables only"
+		file.hint = "searched: " + strings.Join(config.SearchPaths(explicit), ", ")
+	case err != nil && !isTokenError(err):
+		file.status = checkFail
+		file.detail = fmt.Sprintf("%s: %v", path, err)
+		file.hint = "fix the file, or inspect what serve would load with 'jamesbot config show'"
+		cfg, err = config.Load("")
+	default:
+		file.status = checkPass
+		file.detail = path
+	}
+
+	token := doctorCheck{name: "Discord token", status: checkPass, detail: "present and well-formed"}
+	if err == nil {
+		err = config.ValidateToken(cfg.Discord.Token)
+	}
+	if err != nil {
+		token.status = checkFail
+		token.detail = err.Error()
+		token.hint = "set JAMESBOT_DISCORD_TOKEN or discord.token, or discord.token_file to a file holding it, to the bot token from the Discord Developer Portal"
+	}
+
+	return []doctorCheck{file, token}
+}
+
+// checkControlAPI checks that a running bot answers on the control API and,
+// if it does, that it requested the privileged intents its enabled rules
+// need. The API being down is only a warning, since the bot may simply not be
+// running yet.
+func (c *DoctorCommand) checkControlAPI(endpoint string) []doctorCheck {
+	reach := doctorCheck{name: "Control API"}
+	intents := doctorCheck{name: "Gateway intents"}
+
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		reach.status = checkFail
+		reach.detail = err.Error()
+		reach.hint = "check --ca-file and $" + CAFileEnvVar + ", or $" + InsecureEnvVar
+		intents.status = checkSkip
+		intents.detail = "control API not checked"
+		return []doctorCheck{reach, intents}
+	}
+	list, err := client.ListRules()
+	if err != nil {
+		reach.status = checkWarn
+		reach.detail = fmt.Sprintf("not reachable at %s", endpoint)
+		reach.hint = "start the bot with 'jamesbot serve', or pass --endpoint if it listens elsewhere"
+		intents.status = checkSkip
+		intents.detail = "bot is not running"
+		intents.hint = "if serve fails with \"discord refused privileged gateway intents\", enable the intents it names in the Developer Portal"
+		return []doctorCheck{reach, intents}
+	}
+
+	reach.status = checkPass
+	reach.detail = endpoint
+
+	// Group the enabled rules by the privileged intent they need, in the
+	// order the intents are listed in privilegedIntents.
+	needs := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, r := range list {
+		intent, ok := ruleIntents[r.Name]
+		if r.Enabled && ok && !seen[r.Name] {
+			seen[r.Name] = true
+			needs[intent] = append(needs[intent], r.Name)
+		}
+	}
+
+	// Bots that report the intents they connected with are checked against
+	// them. Older ones request the privileged intents their enabled rules
+	// need and Discord refuses the connection if they are not enabled, so a
+	// running bot has them.
+	var reported *control.GatewayIntents
+	if stats, err := client.GetStats(); err == nil {
+		reported = stats.Intents
+	}
+	if reported == nil {
+		reported = &control.GatewayIntents{}
+		for _, intent := range privilegedIntents {
+			if len(needs[intent]) > 0 {
+				reported.Requested = append(reported.Requested, intent)
+			}
+		}
+	}
+
+	intents.status = checkPass
+	var granted []string
+	for _, intent := range privilegedIntents {
+		if !slices.Contains(reported.Requested, intent) {
+			continue
+		}
+		if names := needs[intent]; len(names) > 0 {
+			granted = append(granted, fmt.Sprintf("%s granted for %s", intent, strings.Join(names, ", ")))
+		} else {
+			granted = append(granted, intent+" granted")
+		}
+	}
+	switch {
+	case len(reported.Missing) > 0:
+		var missing []string
+		for _, intent := range reported.Missing {
+			if names := needs[intent]; len(names) > 0 {
+				intent = fmt.Sprintf("%s (for %s)", intent, strings.Join(names, ", "))
+			}
+			missing = append(missing, intent)
+		}
+		intents.status = checkFail
+		intents.detail = "bot did not request " + strings.Join(missing, " and ")
+		intents.hint = "enable them for the bot in the Developer Portal, then restart it so it requests them"
+	case len(granted) == 0:
+		intents.detail = "no enabled rules need privileged intents"
+	default:
+		intents.detail = strings.Join(granted, "; ")
+	}
+
+	return []doctorCheck{reach, intents}
+}
+
+// isTokenError reports whether err is the loader rejecting a missing token or
+// an unreadable token file, which doctor reports under the token check rather
+// than the file check.
+func isTokenError(err error) bool {
+	var configErr *errutil.ConfigError
+	return errors.As(err, &configErr) &&
+		(configErr.Key == "discord.token" || configErr.Key == "discord.token_file")
+}
+
+// writeChecks prints the checklist and returns how many checks failed.
+func writeChecks(w io.Writer, checks []doctorCheck) int {
+	failed := 0
+	for _, check := range checks {
+		fmt.Fprintf(w, "[%s] %s: %s\n", check.status, check.name, check.detail)
+		if check.hint != "" && check.status != checkPass {
+			fmt.Fprintf(w, "       %s\n", check.hint)
+		}
+		if check.status == checkFail {
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,180 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+	"jamesbot/internal/control"
+	"jamesbot/internal/rules"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// DoctorCommand Tests
+// ===========================================================================
+
+func Test_DoctorCommand_Metadata(t *testing.T) {
+	cmd := commands.NewDoctorCommand()
+
+	assert.Equal(t, "doctor", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot doctor")
+}
+
+func Test_DoctorCommand_Run(t *testing.T) {
+	validToken := base64.RawURLEncoding.EncodeToString([]byte("123456789012345678")) + ".GhIjKl.abcdefghijklmnop"
+
+	tests := []struct {
+		name        string
+		fileContent string
+		configPath  string
+		envToken    string
+		running     bool
+		enable      []string
+		intents     *control.GatewayIntents
+		wantExit    int
+		wantStdout  []string
+	}{
+		{
+			name:        "everything healthy",
+			fileContent: "discord:\n  token: " + validToken + "\n",
+			running:     true,
+			wantExit:    commands.ExitOK,
+			wantStdout: []string{
+				"[PASS] Config file: ",
+				"[PASS] Discord token: present and well-formed",
+				"[PASS] Control API: ",
+				"[PASS] Gateway intents: no enabled rules need privileged intents",
+				"All required checks passed",
+			},
+		},
+		{
+			name:        "content rules report the intent they need",
+			fileContent: "discord:\n  token: " + validToken + "\n",
+			running:     true,
+			enable:      []string{"word-filter", "link-filter"},
+			wantExit:    commands.ExitOK,
+			wantStdout:  []string{"[PASS] Gateway intents: Message Content granted for word-filter, link-filter"},
+		},
+		{
+			name:        "welcome rule reports the members intent",
+			fileContent: "discord:\n  token: " + validToken + "\n",
+			running:     true,
+			enable:      []string{"welcome", "word-filter"},
+			wantExit:    commands.ExitOK,
+			wantStdout:  []string{"[PASS] Gateway intents: Message Content granted for word-filter; Server Members granted for welcome"},
+		},
+		{
+			name:        "reported intents are compared with the enabled rules",
+			fileContent: "discord:\n  token: " + validToken + "\n",
+			running:     true,
+			enable:      []string{"word-filter"},
+			intents:     &control.GatewayIntents{Requested: []string{"Message Content", "Server Members"}},
+			wantExit:    commands.ExitOK,
+			wantStdout:  []string{"[PASS] Gateway intents: Message Content granted for word-filter; Server Members granted"},
+		},
+		{
+			name:        "intents the bot did not request fail",
+			fileContent: "discord:\n  token: " + validToken + "\n",
+			running:     true,
+			enable:      []string{"word-filter"},
+			intents:     &control.GatewayIntents{Requested: []string{}, Missing: []string{"Message Content"}},
+			wantExit:    commands.ExitError,
+			wantStdout:  []string{"[FAIL] Gateway intents: bot did not request Message Content (for word-filter)", "restart"},
+		},
+		{
+			name:       "no file and bot not running only warns",
+			envToken:   validToken,
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"[WARN] Config file: none found", "[WARN] Control API: not reachable", "[SKIP] Gateway intents"},
+		},
+		{
+			name:       "missing token fails",
+			wantExit:   commands.ExitError,
+			wantStdout: []string{"[FAIL] Discord token: ", "JAMESBOT_DISCORD_TOKEN", "1 check(s) failed"},
+		},
+		{
+			name:        "malformed token fails",
+			fileContent: "discord:\n  token: Bot " + validToken + "\n",
+			wantExit:    commands.ExitError,
+			wantStdout:  []string{"[PASS] Config file: ", "[FAIL] Discord token: ", `"Bot " prefix`},
+		},
+		{
+			name:        "missing token file fails the token check",
+			fileContent: "discord:\n  token_file: /nonexistent/jamesbot-token\n",
+			wantExit:    commands.ExitError,
+			wantStdout:  []string{"[PASS] Config file: ", "[FAIL] Discord token: ", "discord.token_file", "1 check(s) failed"},
+		},
+		{
+			name:       "missing --config file fails without searching elsewhere",
+			configPath: "missing.yaml",
+			envToken:   validToken,
+			wantExit:   commands.ExitError,
+			wantStdout: []string{"[FAIL] Config file: ", "missing.yaml", "--config", "[PASS] Discord token"},
+		},
+		{
+			name:        "unreadable file fails and falls back to the environment",
+			fileContent: "discord: [\n",
+			envToken:    validToken,
+			wantExit:    commands.ExitError,
+			wantStdout:  []string{"[FAIL] Config file: ", "'jamesbot config show'", "[PASS] Discord token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("JAMESBOT_CONFIG", "")
+			t.Setenv("JAMESBOT_DISCORD_TOKEN", tt.envToken)
+			if tt.envToken == "" {
+				os.Unsetenv("JAMESBOT_DISCORD_TOKEN")
+			}
+
+			var args []string
+			if tt.fileContent != "" {
+				args = append(args, "--config", writeFile(t, "config.yaml", tt.fileContent))
+			}
+			if tt.configPath != "" {
+				args = append(args, "--config", tt.configPath)
+			}
+
+			endpoint := "http://localhost:1"
+			if tt.running {
+				set := rules.NewSet(rules.Defaults()...)
+				for _, name := range tt.enable {
+					require.NoError(t, set.SetRule(name, "enabled", "true"))
+				}
+				bot := &rulesBot{set: set, intents: tt.intents}
+				server := httptest.NewServer(control.NewServer(0, bot, zerolog.New(io.Discard)).Handler())
+				t.Cleanup(server.Close)
+				endpoint = server.URL
+			}
+
+			cmd := commands.NewDoctorCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(args))
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: endpoint}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, "stdout: %s", stdout.String())
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"jamesbot/internal/api"
+)
+
+// DefaultAPIEndpoint is the control API endpoint used when neither the
+// --endpoint flag nor the JAMESBOT_API_ENDPOINT environment variable is set.
+const DefaultAPIEndpoint = "http://127.0.0.1:8765"
+
+// DefaultTLSAPIEndpoint replaces DefaultAPIEndpoint when the control API
+// serves HTTPS, as $JAMESBOT_CONTROL_TLS_CERT_FILE or the CLI's own TLS
+// flags say it does.
+const DefaultTLSAPIEndpoint = "https://127.0.0.1:8765"
+
+// EndpointEnvVar is the environment variable that overrides the default API endpoint.
+const EndpointEnvVar = "JAMESBOT_API_ENDPOINT"
+
+// Environment variables for trusting an HTTPS control API. CAFileEnvVar names
+// a PEM file of certificate authorities to trust, and InsecureEnvVar, when
+// true, skips verifying the certificate. TLSCertEnvVar is serve's
+// control.tls_cert_file; when set, the API is assumed to serve HTTPS.
+const (
+	CAFileEnvVar   = "JAMESBOT_API_CA_FILE"
+	InsecureEnvVar = "JAMESBOT_API_INSECURE"
+	TLSCertEnvVar  = "JAMESBOT_CONTROL_TLS_CERT_FILE"
+)
+
+// endpointUsage is the --endpoint line, and the lines of the TLS flags that go
+// with it, shared by the usage text of API-calling commands.
+const endpointUsage = "  --endpoint <url>    API endpoint (default: $" + EndpointEnvVar + " or " + DefaultAPIEndpoint + ", https when TLS is configured)\n" +
+	"  --ca-file <path>    PEM certificate authorities to trust for an https endpoint (default: $" + CAFileEnvVar + ")\n" +
+	"  --insecure          Don't verify the https endpoint's certificate (default: $" + InsecureEnvVar + ")\n"
+
+// endpointFlags holds the shared flags naming the control API endpoint and
+// how to trust it when it serves HTTPS.
+type endpointFlags struct {
+	url      stringValue
+	caFile   string
+	insecure bool
+}
+
+// addEndpointFlag registers the shared --endpoint flag, and the TLS flags
+// that go with it, on fs.
+func addEndpointFlag(fs *flag.FlagSet, v *endpointFlags) {
+	*v = endpointFlags{url: stringValue{value: DefaultAPIEndpoint}}
+	fs.Var(&v.url, "endpoint", "API endpoint (overrides $"+EndpointEnvVar+")")
+	fs.StringVar(&v.caFile, "ca-file", "", "PEM certificate authorities to trust for an https endpoint (overrides $"+CAFileEnvVar+")")
+	fs.BoolVar(&v.insecure, "insecure", false, "Don't verify the https endpoint's certificate")
+}
+
+// healthCheckUsage is the --no-health-check line shared by the usage text of
+// API-calling commands.
+const healthCheckUsage = "  --no-health-check   Don't probe the bot's /health endpoint to explain a failed request\n"
+
+// addHealthCheckFlag registers the shared --no-health-check flag on fs.
+func addHealthCheckFlag(fs *flag.FlagSet, v *bool) {
+	fs.BoolVar(v, "no-health-check", false, "Don't probe /health to explain a failed request")
+}
+
+// resolveEndpoint returns the API endpoint a command should call.
+// Precedence, highest first: the context's APIEndpoint, an explicitly passed
+// --endpoint flag, $JAMESBOT_API_ENDPOINT, then DefaultTLSAPIEndpoint when
+// TLS is configured or DefaultAPIEndpoint when it is not.
+func resolveEndpoint(ctx *CLIContext, flags *endpointFlags) string {
+	if ctx != nil && ctx.APIEndpoint != "" {
+		return ctx.APIEndpoint
+	}
+	if flags != nil && flags.url.set && flags.url.value != "" {
+		return flags.url.value
+	}
+	if env := strings.TrimSpace(os.Getenv(EndpointEnvVar)); env != "" {
+		return env
+	}
+	if usesTLS(ctx, flags) {
+		return DefaultTLSAPIEndpoint
+	}
+	return DefaultAPIEndpoint
+}
+
+// usesTLS reports whether the control API is configured to serve HTTPS, by
+// the config in ctx or $JAMESBOT_CONTROL_TLS_CERT_FILE, or the CLI is told
+// how to trust it.
+func usesTLS(ctx *CLIContext, flags *endpointFlags) bool {
+	if ctx != nil && ctx.Config != nil && ctx.Config.Control.TLSCertFile != "" {
+		return true
+	}
+	if strings.TrimSpace(os.Getenv(TLSCertEnvVar)) != "" || strings.TrimSpace(os.Getenv(CAFileEnvVar)) != "" {
+		return true
+	}
+	return flags != nil && (flags.caFile != "" || flags.insecure)
+}
+
+// newClient creates an API client for endpoint that trusts the certificate
+// authorities, or skips verification, as flags and the environment say.
+// It returns an error if the CA file cannot be used.
+func newClient(endpoint string, flags *endpointFlags, opts ...api.Option) (*api.Client, error) {
+	tlsOpts, err := flags.tlsOptions()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(endpoint, append(tlsOpts, opts...)...), nil
+}
+
+// tlsOptions returns the client options for the --ca-file and --insecure
+// flags, falling back to $JAMESBOT_API_CA_FILE and $JAMESBOT_API_INSECURE.
+func (v *endpointFlags) tlsOptions() ([]api.Option, error) {
+	var caFile string
+	var insecure bool
+	if v != nil {
+		caFile, insecure = v.caFile, v.insecure
+	}
+	if caFile == "" {
+		caFile = strings.TrimSpace(os.Getenv(CAFileEnvVar))
+	}
+	if env := strings.TrimSpace(os.Getenv(InsecureEnvVar)); !insecure && env != "" {
+		parsed, err := strconv.ParseBool(env)
+		if err != nil {
+			return nil, fmt.Errorf("$%s must be true or false, not %q", InsecureEnvVar, env)
+		}
+		insecure = parsed
+	}
+
+	var opts []api.Option
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file %s", caFile)
+		}
+		opts = append(opts, api.WithRootCAs(pool))
+	}
+	if insecure {
+		opts = append(opts, api.WithInsecureSkipVerify())
+	}
+	return opts, nil
+}
+
+// isConnectionError reports whether err, returned by an api.Client call, means
+// the request got no response at all.
+func isConnectionError(err error) bool {
+	return strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection failed")
+}
+
+// reportUnreachable reports whether err, returned by a call to the control
+// API at endpoint, means no bot control server is answering there, and if so
+// explains that on stderr. When probe is set, a request that got no response,
+// or one that could not be decoded, is followed by a quick health check. That
+// catches answers from something other than the bot, such as another service
+// on the port, and lets a timeout from a bot that is up fall through to the
+// caller's own error. Otherwise only connection errors are reported.
+func reportUnreachable(stderr io.Writer, client *api.Client, endpoint string, err error, probe bool) bool {
+	connErr := isConnectionError(err)
+	switch {
+	case probe && (connErr || strings.Contains(err.Error(), "decode failed")):
+		if client.Health() == nil {
+			return false
+		}
+	case !connErr:
+		return false
+	}
+
+	fmt.Fprintf(stderr, "Error: Cannot connect to bot API at %s\n", endpoint)
+	fmt.Fprintf(stderr, "The bot control server at %s is not reachable; is the bot running?\n", endpoint)
+	if !connErr {
+		fmt.Fprintf(stderr, "The request failed: %v\n", err)
+	}
+	fmt.Fprintf(stderr, "Start it with 'jamesbot serve', or point --endpoint at the bot's control API\n")
+	return true
+}
+
+// AuthTokenEnvVar is the environment variable holding the auth token that
+// moderation commands send to the control API. It also sets serve's
+// control.auth_token, so the bot and the CLI can share one environment.
+const AuthTokenEnvVar = "JAMESBOT_CONTROL_AUTH_TOKEN"
+
+// newModerationClient creates an API client for endpoint, like newClient,
+// that authenticates with the token in $JAMESBOT_CONTROL_AUTH_TOKEN, as the
+// moderation endpoints require.
+func newModerationClient(endpoint string, flags *endpointFlags) (*api.Client, error) {
+	return newClient(endpoint, flags, api.WithAuthToken(strings.TrimSpace(os.Getenv(AuthTokenEnvVar))))
+}
+
+// writeUnauthorized explains a moderation request the control API refused.
+func writeUnauthorized(stderr io.Writer) {
+	fmt.Fprintf(stderr, "Error: The bot API refused the request\n")
+	fmt.Fprintf(stderr, "Set $%s to the bot's control.auth_token\n", AuthTokenEnvVar)
+}
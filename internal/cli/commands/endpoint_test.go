@@ -0,0 +1,323 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/pem"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// Endpoint Resolution Tests
+// ===========================================================================
+
+// newCountingAPIServer starts a control API stub that answers every endpoint
+// the CLI calls and counts the requests it receives.
+func newCountingAPIServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/stats":
+			w.Write([]byte(`{"uptime":"1s","guild_count":0,"commands_executed":0,"active_rules":0}`))
+		case "/rules", "/punishments":
+			w.Write([]byte(`[]`))
+		case "/warnings/clear":
+			w.Write([]byte(`{"removed":0}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &hits
+}
+
+// Test_Commands_EndpointPrecedence verifies that every API-calling command
+// resolves its endpoint as flag, then JAMESBOT_API_ENDPOINT, then default.
+func Test_Commands_EndpointPrecedence(t *testing.T) {
+	type apiCommand interface {
+		SetFlags(fs *flag.FlagSet)
+		Run(ctx *commands.CLIContext, args []string) int
+	}
+
+	cmds := []struct {
+		name string
+		new  func() apiCommand
+		args []string
+	}{
+		{name: "stats", new: func() apiCommand { return &commands.StatsCommand{} }},
+		{name: "rules list", new: func() apiCommand { return &commands.RulesListCommand{} }},
+		{name: "rules set", new: func() apiCommand { return &commands.RulesSetCommand{} }, args: []string{"anti-spam", "enabled", "true"}},
+		{name: "warnings clear", new: func() apiCommand { return &commands.WarningsClearCommand{} }, args: []string{"guild-1", "user-1"}},
+		{name: "punishments list", new: func() apiCommand { return &commands.PunishmentsListCommand{} }},
+	}
+
+	tests := []struct {
+		name         string
+		passFlag     bool
+		setEnv       bool
+		wantFlagHits int32
+		wantEnvHits  int32
+	}{
+		{
+			name:         "flag wins over env",
+			passFlag:     true,
+			setEnv:       true,
+			wantFlagHits: 1,
+			wantEnvHits:  0,
+		},
+		{
+			name:         "env wins over default",
+			passFlag:     false,
+			setEnv:       true,
+			wantFlagHits: 0,
+			wantEnvHits:  1,
+		},
+		{
+			name:         "flag used without env",
+			passFlag:     true,
+			setEnv:       false,
+			wantFlagHits: 1,
+			wantEnvHits:  0,
+		},
+	}
+
+	for _, c := range cmds {
+		for _, tt := range tests {
+			t.Run(c.name+"/"+tt.name, func(t *testing.T) {
+				flagServer, flagHits := newCountingAPIServer(t)
+				envServer, envHits := newCountingAPIServer(t)
+
+				if tt.setEnv {
+					t.Setenv(commands.EndpointEnvVar, envServer.URL)
+				} else {
+					t.Setenv(commands.EndpointEnvVar, "")
+				}
+
+				cmd := c.new()
+				fs := flag.NewFlagSet("test", flag.ContinueOnError)
+				fs.SetOutput(&bytes.Buffer{})
+				cmd.SetFlags(fs)
+
+				parseArgs := []string{}
+				if tt.passFlag {
+					parseArgs = append(parseArgs, "--endpoint", flagServer.URL)
+				}
+				require.NoError(t, fs.Parse(append(parseArgs, c.args...)))
+
+				stderr := &bytes.Buffer{}
+				ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr}
+
+				exitCode := cmd.Run(ctx, fs.Args())
+
+				assert.Equal(t, commands.ExitOK, exitCode, "stderr: %s", stderr.String())
+				assert.Equal(t, tt.wantFlagHits, flagHits.Load(), "requests to flag endpoint")
+				assert.Equal(t, tt.wantEnvHits, envHits.Load(), "requests to env endpoint")
+			})
+		}
+	}
+}
+
+// Test_Commands_EndpointDefault verifies the default endpoint is used when
+// neither the flag nor the environment variable is set.
+func Test_Commands_EndpointDefault(t *testing.T) {
+	t.Setenv(commands.EndpointEnvVar, "")
+
+	cmd := &commands.StatsCommand{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+
+	endpointFlag := fs.Lookup("endpoint")
+	require.NotNil(t, endpointFlag)
+	assert.Equal(t, commands.DefaultAPIEndpoint, endpointFlag.DefValue)
+	assert.Contains(t, cmd.Usage(), commands.EndpointEnvVar, "usage should mention the env override")
+}
+
+// Test_Commands_EndpointDefaultTLS verifies the default endpoint is https
+// when the control API is configured to serve it.
+func Test_Commands_EndpointDefaultTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		tlsCert string
+		flags   []string
+		want    string
+	}{
+		{name: "plain http", want: commands.DefaultAPIEndpoint},
+		{name: "bot serves TLS", tlsCert: "control.pem", want: commands.DefaultTLSAPIEndpoint},
+		{name: "insecure flag", flags: []string{"--insecure"}, want: commands.DefaultTLSAPIEndpoint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(commands.EndpointEnvVar, "")
+			t.Setenv(commands.CAFileEnvVar, "")
+			t.Setenv(commands.InsecureEnvVar, "")
+			t.Setenv(commands.TLSCertEnvVar, tt.tlsCert)
+
+			cmd := commands.NewStatsCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(append(tt.flags, "--no-health-check")))
+
+			stderr := &bytes.Buffer{}
+			cmd.Run(&commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr}, fs.Args())
+
+			assert.Contains(t, stderr.String(), "Cannot connect to bot API at "+tt.want+"\n")
+		})
+	}
+}
+
+// Test_Commands_TLS verifies that API-calling commands trust an https
+// endpoint's certificate as --ca-file, --insecure, or their environment
+// variables say.
+func Test_Commands_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"uptime":"1s"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+	notPEM := filepath.Join(dir, "ca.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name       string
+		flags      []string
+		caEnv      string
+		insecure   string
+		wantExit   int
+		wantStderr string
+	}{
+		{name: "unknown CA is rejected", flags: []string{"--no-health-check"}, wantExit: commands.ExitConnectionError},
+		{name: "CA file flag", flags: []string{"--ca-file", caFile}, wantExit: commands.ExitOK},
+		{name: "CA file env", caEnv: caFile, wantExit: commands.ExitOK},
+		{name: "insecure flag", flags: []string{"--insecure"}, wantExit: commands.ExitOK},
+		{name: "insecure env", insecure: "true", wantExit: commands.ExitOK},
+		{name: "missing CA file", flags: []string{"--ca-file", filepath.Join(dir, "missing.pem")}, wantExit: commands.ExitError, wantStderr: "failed to read CA file"},
+		{name: "CA file without certificates", flags: []string{"--ca-file", notPEM}, wantExit: commands.ExitError, wantStderr: "no PEM certificates"},
+		{name: "invalid insecure env", insecure: "maybe", wantExit: commands.ExitError, wantStderr: "$" + commands.InsecureEnvVar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(commands.EndpointEnvVar, "")
+			t.Setenv(commands.TLSCertEnvVar, "")
+			t.Setenv(commands.CAFileEnvVar, tt.caEnv)
+			t.Setenv(commands.InsecureEnvVar, tt.insecure)
+
+			cmd := commands.NewStatsCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(append([]string{"--endpoint", server.URL}, tt.flags...)))
+
+			stderr := &bytes.Buffer{}
+			exitCode := cmd.Run(&commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr}, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, "stderr: %s", stderr.String())
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+}
+
+// ===========================================================================
+// Health Check Tests
+// ===========================================================================
+
+// Test_Commands_HealthCheck verifies that API-calling commands probe /health
+// to explain a failed request, unless --no-health-check is given.
+func Test_Commands_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		endpoint      func(t *testing.T, healthHits *atomic.Int32) string
+		flags         []string
+		wantExit      int
+		wantStderr    string
+		wantNoStderr  string
+		wantHealthHit bool
+	}{
+		{
+			name: "bot not running",
+			endpoint: func(t *testing.T, _ *atomic.Int32) string {
+				return "http://127.0.0.1:1"
+			},
+			wantExit:   commands.ExitConnectionError,
+			wantStderr: "The bot control server at http://127.0.0.1:1 is not reachable; is the bot running?",
+		},
+		{
+			name:          "something other than the bot answers",
+			endpoint:      newWebPageServer,
+			wantExit:      commands.ExitConnectionError,
+			wantStderr:    "is not reachable; is the bot running?\nThe request failed: decode failed",
+			wantHealthHit: true,
+		},
+		{
+			name:         "no health check reports the raw error",
+			endpoint:     newWebPageServer,
+			flags:        []string{"--no-health-check"},
+			wantExit:     commands.ExitAPIError,
+			wantStderr:   "Failed to get stats: decode failed",
+			wantNoStderr: "is the bot running?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var healthHits atomic.Int32
+			endpoint := tt.endpoint(t, &healthHits)
+
+			cmd := commands.NewStatsCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(append([]string{"--endpoint", endpoint}, tt.flags...)))
+
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, "stderr: %s", stderr.String())
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+			if tt.wantNoStderr != "" {
+				assert.NotContains(t, stderr.String(), tt.wantNoStderr)
+			}
+			assert.Equal(t, tt.wantHealthHit, healthHits.Load() > 0, "health probed")
+		})
+	}
+}
+
+// newWebPageServer starts a server that answers every request with an HTML
+// page, as an unrelated service on the bot's port would, counting requests
+// for /health.
+func newWebPageServer(t *testing.T, healthHits *atomic.Int32) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			healthHits.Add(1)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>It works!</body></html>"))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
@@ -0,0 +1,26 @@
+package commands
+
+// Exit codes returned by command Run methods.
+// They share values with the exit codes in the cli package so scripts see the
+// same code for the same failure whether it comes from the dispatcher or a command.
+const (
+	// ExitOK indicates the command completed successfully.
+	ExitOK = 0
+
+	// ExitError indicates a general failure, such as a file that could not
+	// be read or a bot that failed to start.
+	ExitError = 1
+
+	// ExitConnectionError indicates the bot API could not be reached.
+	ExitConnectionError = 2
+
+	// ExitAPIError indicates the bot API was reached but refused or failed
+	// the request, or reported that part of it failed.
+	ExitAPIError = 3
+
+	// ExitConfigError indicates configuration could not be loaded or is invalid.
+	ExitConfigError = 4
+
+	// ExitUsage indicates missing or invalid arguments.
+	ExitUsage = 5
+)
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"flag"
+	"strconv"
+)
+
+// stringValue is a flag.Value holding a string that records whether the flag
+// was passed explicitly, so an unset flag can defer to the environment or a
+// search path instead of its default.
+type stringValue struct {
+	value string
+	set   bool
+}
+
+func (v *stringValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *stringValue) Set(s string) error {
+	v.value = s
+	v.set = true
+	return nil
+}
+
+// intValue is a flag.Value holding an int that records whether the flag was
+// passed explicitly, so a setting the flag would override can warn about it.
+type intValue struct {
+	value int
+	set   bool
+}
+
+func (v *intValue) String() string {
+	if v == nil {
+		return "0"
+	}
+	return strconv.Itoa(v.value)
+}
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	v.value = n
+	v.set = true
+	return nil
+}
+
+// quietUsage is the --quiet line shared by the usage text of mutating commands.
+const quietUsage = "  -q, --quiet         Suppress the success message; the exit code reports the outcome\n"
+
+// addQuietFlag registers the shared --quiet flag and its -q shorthand on fs.
+func addQuietFlag(fs *flag.FlagSet, v *bool) {
+	fs.BoolVar(v, "quiet", false, "Suppress the success message")
+	fs.BoolVar(v, "q", false, "Suppress the success message (shorthand)")
+}
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"jamesbot/internal/control"
+)
+
+// MaintenanceCommand implements the maintenance command, which puts a
+// running bot into maintenance mode, takes it out, or shows whether it is in.
+type MaintenanceCommand struct {
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewMaintenanceCommand creates a new MaintenanceCommand instance.
+func NewMaintenanceCommand() *MaintenanceCommand {
+	return &MaintenanceCommand{}
+}
+
+// Name returns the name of the command.
+func (c *MaintenanceCommand) Name() string {
+	return "maintenance"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *MaintenanceCommand) Synopsis() string {
+	return "Hold back members' commands while the bot is being worked on"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *MaintenanceCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot maintenance [on|off|status] [options]\n\n")
+	sb.WriteString("Quiesce a running bot, such as during a deploy, without stopping it. In\n")
+	sb.WriteString("maintenance mode, members who run a command are told the bot is under\n")
+	sb.WriteString("maintenance instead. Administrators, and commands listed in\n")
+	sb.WriteString("commands.maintenance_exempt, are not held back. Maintenance mode ends\n")
+	sb.WriteString("when the bot restarts. Turning it on or off requires the bot API's auth\n")
+	sb.WriteString("token in $" + AuthTokenEnvVar + ".\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  on      Turn maintenance mode on\n")
+	sb.WriteString("  off     Turn maintenance mode off\n")
+	sb.WriteString("  status  Show whether maintenance mode is on (the default)\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the maintenance command.
+func (c *MaintenanceCommand) SetFlags(fs *flag.FlagSet) {
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the maintenance command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *MaintenanceCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if action != "on" && action != "off" && action != "status" {
+		fmt.Fprintf(stderr, "Error: Unknown argument %q; expected on, off, or status\n\n", action)
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+	client, err := newModerationClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	var state *control.MaintenanceState
+	if action == "status" {
+		var stats *control.Stats
+		stats, err = client.GetStats()
+		if err == nil {
+			state = stats.Maintenance
+			if state == nil {
+				fmt.Fprintf(stderr, "Error: The bot does not support maintenance mode\n")
+				return ExitAPIError
+			}
+		}
+	} else {
+		state, err = client.SetMaintenance(action == "on")
+	}
+	if err != nil {
+		if errors.Is(err, control.ErrUnauthorized) {
+			writeUnauthorized(stderr)
+			return ExitAPIError
+		}
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		// Other API errors
+		fmt.Fprintf(stderr, "Error: Failed to %s maintenance mode: %v\n", maintenanceVerb(action), err)
+		return ExitAPIError
+	}
+
+	if c.quiet {
+		return ExitOK
+	}
+	fmt.Fprintf(stdout, "Maintenance mode: %s\n", formatMaintenance(state, time.Now()))
+	return ExitOK
+}
+
+// maintenanceVerb describes what the maintenance command does for action,
+// for error messages.
+func maintenanceVerb(action string) string {
+	switch action {
+	case "on":
+		return "turn on"
+	case "off":
+		return "turn off"
+	default:
+		return "get"
+	}
+}
+
+// formatMaintenance describes a maintenance state relative to now, such as
+// "on for 5m", or "off".
+func formatMaintenance(state *control.MaintenanceState, now time.Time) string {
+	if state == nil || !state.Enabled {
+		return "off"
+	}
+	if state.Since == 0 {
+		return "on"
+	}
+	return "on for " + formatAge(now.Sub(time.Unix(state.Since, 0)))
+}
@@ -0,0 +1,101 @@
+package commands_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"jamesbot/internal/cli/commands"
+	"jamesbot/internal/control"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// maintenanceBot serves maintenance mode through the control API.
+type maintenanceBot struct {
+	rulesBot
+	state control.MaintenanceState
+}
+
+func (b *maintenanceBot) Stats() *control.Stats {
+	state := b.state
+	return &control.Stats{Maintenance: &state}
+}
+
+func (b *maintenanceBot) SetMaintenance(enabled bool) control.MaintenanceState {
+	b.state = control.MaintenanceState{Enabled: enabled}
+	if enabled {
+		b.state.Since = time.Now().Add(-5 * time.Minute).Unix()
+	}
+	return b.state
+}
+
+// ===========================================================================
+// Maintenance Tests
+// ===========================================================================
+
+func Test_MaintenanceCommand_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		on          bool
+		plainBot    bool
+		unreachable bool
+		wantExit    int
+		wantOn      bool
+		wantStdout  string
+		wantStderr  string
+		wantSilent  bool
+		noToken     bool
+	}{
+		{name: "turn on", args: []string{"on"}, wantExit: commands.ExitOK, wantOn: true, wantStdout: "Maintenance mode: on for 5m"},
+		{name: "turn off", args: []string{"off"}, on: true, wantExit: commands.ExitOK, wantOn: false, wantStdout: "Maintenance mode: off"},
+		{name: "status while on", args: []string{"status"}, on: true, wantExit: commands.ExitOK, wantOn: true, wantStdout: "Maintenance mode: on for 5m"},
+		{name: "status by default", wantExit: commands.ExitOK, wantStdout: "Maintenance mode: off"},
+		{name: "quiet", args: []string{"-q", "on"}, wantExit: commands.ExitOK, wantOn: true, wantSilent: true},
+		{name: "unknown argument", args: []string{"pause"}, wantExit: commands.ExitUsage, wantStderr: `Unknown argument "pause"`},
+		{name: "bot without maintenance mode", plainBot: true, wantExit: commands.ExitAPIError, wantStderr: "does not support maintenance mode"},
+		{name: "bot without maintenance mode rejects on", args: []string{"on"}, plainBot: true, wantExit: commands.ExitAPIError, wantStderr: "Failed to turn on maintenance mode"},
+		{name: "missing token", args: []string{"on"}, noToken: true, wantExit: commands.ExitAPIError, wantStderr: commands.AuthTokenEnvVar},
+		{name: "status without a token", args: []string{"status"}, on: true, noToken: true, wantExit: commands.ExitOK, wantOn: true},
+		{name: "bot not running", args: []string{"on"}, unreachable: true, wantExit: commands.ExitConnectionError, wantStderr: "Cannot connect"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := "secret"
+			if tt.noToken {
+				token = ""
+			}
+			t.Setenv(commands.AuthTokenEnvVar, token)
+			bot := &maintenanceBot{}
+			bot.SetMaintenance(tt.on)
+			var info control.BotInfo = bot
+			if tt.plainBot {
+				info = &bot.rulesBot
+			}
+			server := httptest.NewServer(control.NewServer(0, info, zerolog.New(io.Discard), control.WithAuthToken("secret")).Handler())
+			t.Cleanup(server.Close)
+			endpoint := server.URL
+			if tt.unreachable {
+				endpoint = "http://localhost:1"
+			}
+
+			exit, stdout, stderr := runCLICommand(t, commands.NewMaintenanceCommand(), endpoint, tt.args...)
+
+			assert.Equal(t, tt.wantExit, exit, stderr)
+			assert.Equal(t, tt.wantOn, bot.state.Enabled)
+			if tt.wantStdout != "" {
+				assert.Contains(t, stdout, tt.wantStdout)
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr, tt.wantStderr)
+			}
+			if tt.wantSilent {
+				assert.Empty(t, stdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"flag"
+	"strings"
+)
+
+// ModCommand is a parent command for moderation actions.
+// It acts as a container for subcommands like ban, kick, and mute.
+type ModCommand struct{}
+
+// NewModCommand creates a new ModCommand instance.
+func NewModCommand() *ModCommand {
+	return &ModCommand{}
+}
+
+// Name returns the name of the command.
+func (c *ModCommand) Name() string {
+	return "mod"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *ModCommand) Synopsis() string {
+	return "Ban, kick, or mute a member through the running bot"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *ModCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot mod <subcommand> [options]\n\n")
+	sb.WriteString("Take moderation actions as the bot, without Discord's slash commands, such\n")
+	sb.WriteString("as from scripts. The bot API refuses these unless $" + AuthTokenEnvVar + "\n")
+	sb.WriteString("matches the bot's control.auth_token.\n\n")
+	sb.WriteString("Subcommands:\n")
+	sb.WriteString("  ban   Ban a member from a guild\n")
+	sb.WriteString("  kick  Kick a member from a guild\n")
+	sb.WriteString("  mute  Time a member out in a guild\n\n")
+	sb.WriteString("Use \"jamesbot mod <subcommand> -h\" for more information about a subcommand.\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the mod command.
+// Parent commands typically don't have their own flags.
+func (c *ModCommand) SetFlags(fs *flag.FlagSet) {
+	// No flags for parent command
+}
+
+// Run executes the mod command.
+// When invoked without a subcommand, it prints usage information.
+func (c *ModCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stdout.Write([]byte(c.Usage()))
+	return ExitOK
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"jamesbot/internal/control"
+)
+
+// ModActionCommand implements the mod ban, mod kick, and mod mute commands,
+// which differ only in the action they take and the arguments it needs.
+type ModActionCommand struct {
+	action        string
+	reason        string
+	deleteDays    int
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewModBanCommand creates the mod ban command.
+func NewModBanCommand() *ModActionCommand {
+	return &ModActionCommand{action: "ban"}
+}
+
+// NewModKickCommand creates the mod kick command.
+func NewModKickCommand() *ModActionCommand {
+	return &ModActionCommand{action: "kick"}
+}
+
+// NewModMuteCommand creates the mod mute command.
+func NewModMuteCommand() *ModActionCommand {
+	return &ModActionCommand{action: "mute"}
+}
+
+// Name returns the name of the command.
+func (c *ModActionCommand) Name() string {
+	return c.action
+}
+
+// Synopsis returns a brief description of the command.
+func (c *ModActionCommand) Synopsis() string {
+	switch c.action {
+	case "ban":
+		return "Ban a member from a guild"
+	case "kick":
+		return "Kick a member from a guild"
+	}
+	return "Time a member out in a guild"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *ModActionCommand) Usage() string {
+	var sb strings.Builder
+	if c.action == "mute" {
+		sb.WriteString("Usage: jamesbot mod mute [options] <guild-id> <user-id> <duration>\n\n")
+	} else {
+		fmt.Fprintf(&sb, "Usage: jamesbot mod %s [options] <guild-id> <user-id>\n\n", c.action)
+	}
+	fmt.Fprintf(&sb, "%s, as the bot. The reason, if given, is recorded in the\n", c.Synopsis())
+	sb.WriteString("guild's audit log. The bot API requires the auth token in\n")
+	sb.WriteString("$" + AuthTokenEnvVar + ".\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  <guild-id>  ID of the guild\n")
+	sb.WriteString("  <user-id>   ID of the member\n")
+	if c.action == "mute" {
+		sb.WriteString("  <duration>  How long to mute for, such as 30m or 12h (1m to 28 days)\n")
+	}
+	sb.WriteString("\nOptions:\n")
+	sb.WriteString("  --reason <text>     Reason recorded in the guild's audit log\n")
+	if c.action == "ban" {
+		fmt.Fprintf(&sb, "  --delete-days <n>   Delete the member's messages from the last n days (0-%d)\n", control.MaxBanDeleteDays)
+	}
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the command.
+func (c *ModActionCommand) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.reason, "reason", "", "Reason recorded in the guild's audit log")
+	if c.action == "ban" {
+		fs.IntVar(&c.deleteDays, "delete-days", 0, "Delete the member's messages from the last n days")
+	}
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *ModActionCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Validate arguments
+	wantArgs := 2
+	if c.action == "mute" {
+		wantArgs = 3
+	}
+	if len(args) < wantArgs {
+		fmt.Fprintf(stderr, "Error: Missing required arguments\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	guildID := args[0]
+	userID := args[1]
+
+	var duration time.Duration
+	if c.action == "mute" {
+		var err error
+		duration, err = time.ParseDuration(args[2])
+		if err != nil || duration < control.MinMuteDuration || duration > control.MaxMuteDuration {
+			fmt.Fprintf(stderr, "Error: Invalid duration %q; use a duration from 1m to 672h, such as 30m or 12h\n", args[2])
+			return ExitUsage
+		}
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	// Create API client, authenticated for the moderation endpoints
+	client, err := newModerationClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	// Take the action via API
+	var done string
+	switch c.action {
+	case "ban":
+		err = client.Ban(guildID, userID, c.reason, c.deleteDays)
+		done = fmt.Sprintf("Banned user %s in guild %s", userID, guildID)
+	case "kick":
+		err = client.Kick(guildID, userID, c.reason)
+		done = fmt.Sprintf("Kicked user %s from guild %s", userID, guildID)
+	case "mute":
+		err = client.Mute(guildID, userID, duration, c.reason)
+		done = fmt.Sprintf("Muted user %s in guild %s for %s", userID, guildID, duration)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, control.ErrUnauthorized):
+			writeUnauthorized(stderr)
+			return ExitAPIError
+		case errors.Is(err, control.ErrMemberNotFound):
+			fmt.Fprintf(stderr, "Error: User %s is not a member of guild %s\n", userID, guildID)
+			return ExitAPIError
+		}
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		fmt.Fprintf(stderr, "Error: Failed to %s user: %v\n", c.action, err)
+		return ExitAPIError
+	}
+
+	if c.quiet {
+		return ExitOK
+	}
+	fmt.Fprintln(stdout, done)
+	return ExitOK
+}
@@ -0,0 +1,234 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+	"jamesbot/internal/control"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// ModActionCommand Tests
+// ===========================================================================
+
+// modRequest is a moderation request received by newModServer.
+type modRequest struct {
+	path string
+	auth string
+	body control.ModerationRequest
+}
+
+// newModServer returns a control API stand-in that accepts moderation
+// requests carrying the "secret" token, reports user "404" as not a member,
+// and records each request it receives.
+func newModServer(t *testing.T, received *[]modRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req control.ModerationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*received = append(*received, modRequest{path: r.URL.Path, auth: r.Header.Get("Authorization"), body: req})
+
+		switch {
+		case r.Header.Get("Authorization") != "Bearer secret":
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		case req.UserID == "404":
+			http.Error(w, "Not found: member not found", http.StatusNotFound)
+		default:
+			_ = json.NewEncoder(w).Encode(control.ModerationResponse{GuildID: req.GuildID, UserID: req.UserID})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_ModActionCommand_Metadata(t *testing.T) {
+	tests := []struct {
+		cmd       *commands.ModActionCommand
+		wantName  string
+		wantUsage string
+	}{
+		{cmd: commands.NewModBanCommand(), wantName: "ban", wantUsage: "--delete-days"},
+		{cmd: commands.NewModKickCommand(), wantName: "kick", wantUsage: "jamesbot mod kick [options] <guild-id> <user-id>\n"},
+		{cmd: commands.NewModMuteCommand(), wantName: "mute", wantUsage: "<user-id> <duration>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			assert.Equal(t, tt.wantName, tt.cmd.Name())
+			assert.NotEmpty(t, tt.cmd.Synopsis())
+			assert.Contains(t, tt.cmd.Usage(), tt.wantUsage)
+			assert.Contains(t, tt.cmd.Usage(), commands.AuthTokenEnvVar)
+		})
+	}
+}
+
+func Test_ModActionCommand_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        *commands.ModActionCommand
+		token      string
+		args       []string
+		wantExit   int
+		wantPath   string
+		wantBody   control.ModerationRequest
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "ban with reason and deleted messages",
+			cmd:        commands.NewModBanCommand(),
+			token:      "secret",
+			args:       []string{"--reason", "spam", "--delete-days", "2", "guild-1", "user-1"},
+			wantExit:   commands.ExitOK,
+			wantPath:   "/moderation/ban",
+			wantBody:   control.ModerationRequest{GuildID: "guild-1", UserID: "user-1", Reason: "spam", DeleteDays: 2},
+			wantStdout: "Banned user user-1 in guild guild-1",
+		},
+		{
+			name:       "kick",
+			cmd:        commands.NewModKickCommand(),
+			token:      "secret",
+			args:       []string{"guild-1", "user-1"},
+			wantExit:   commands.ExitOK,
+			wantPath:   "/moderation/kick",
+			wantBody:   control.ModerationRequest{GuildID: "guild-1", UserID: "user-1"},
+			wantStdout: "Kicked user user-1 from guild guild-1",
+		},
+		{
+			name:       "mute",
+			cmd:        commands.NewModMuteCommand(),
+			token:      "secret",
+			args:       []string{"guild-1", "user-1", "90m"},
+			wantExit:   commands.ExitOK,
+			wantPath:   "/moderation/mute",
+			wantBody:   control.ModerationRequest{GuildID: "guild-1", UserID: "user-1", Duration: "1h30m0s"},
+			wantStdout: "Muted user user-1 in guild guild-1 for 1h30m0s",
+		},
+		{
+			name:     "quiet",
+			cmd:      commands.NewModKickCommand(),
+			token:    "secret",
+			args:     []string{"-q", "guild-1", "user-1"},
+			wantExit: commands.ExitOK,
+			wantPath: "/moderation/kick",
+			wantBody: control.ModerationRequest{GuildID: "guild-1", UserID: "user-1"},
+		},
+		{
+			name:       "member not found",
+			cmd:        commands.NewModKickCommand(),
+			token:      "secret",
+			args:       []string{"guild-1", "404"},
+			wantExit:   commands.ExitAPIError,
+			wantPath:   "/moderation/kick",
+			wantBody:   control.ModerationRequest{GuildID: "guild-1", UserID: "404"},
+			wantStderr: "User 404 is not a member of guild guild-1",
+		},
+		{
+			name:       "missing token",
+			cmd:        commands.NewModBanCommand(),
+			args:       []string{"guild-1", "user-1"},
+			wantExit:   commands.ExitAPIError,
+			wantPath:   "/moderation/ban",
+			wantBody:   control.ModerationRequest{GuildID: "guild-1", UserID: "user-1"},
+			wantStderr: commands.AuthTokenEnvVar,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(commands.AuthTokenEnvVar, tt.token)
+			var received []modRequest
+			server := newModServer(t, &received)
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			tt.cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(tt.args))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := tt.cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, stderr.String())
+			require.Len(t, received, 1)
+			assert.Equal(t, tt.wantPath, received[0].path)
+			assert.Equal(t, tt.wantBody, received[0].body)
+			if tt.wantStdout != "" {
+				assert.Contains(t, stdout.String(), tt.wantStdout)
+			} else if tt.wantExit == commands.ExitOK {
+				assert.Empty(t, stdout.String())
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func Test_ModActionCommand_Run_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        *commands.ModActionCommand
+		args       []string
+		wantExit   int
+		wantStderr string
+	}{
+		{
+			name:       "missing user",
+			cmd:        commands.NewModBanCommand(),
+			args:       []string{"guild-1"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Missing required arguments",
+		},
+		{
+			name:       "mute without duration",
+			cmd:        commands.NewModMuteCommand(),
+			args:       []string{"guild-1", "user-1"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Missing required arguments",
+		},
+		{
+			name:       "unparseable duration",
+			cmd:        commands.NewModMuteCommand(),
+			args:       []string{"guild-1", "user-1", "soon"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Invalid duration",
+		},
+		{
+			name:       "duration too long",
+			cmd:        commands.NewModMuteCommand(),
+			args:       []string{"guild-1", "user-1", "700h"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Invalid duration",
+		},
+		{
+			name:       "bot not running",
+			cmd:        commands.NewModKickCommand(),
+			args:       []string{"guild-1", "user-1"},
+			wantExit:   commands.ExitConnectionError,
+			wantStderr: "Cannot connect to bot API",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://localhost:1"}
+
+			exitCode := tt.cmd.Run(ctx, tt.args)
+
+			assert.Equal(t, tt.wantExit, exitCode)
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"flag"
+	"strings"
+)
+
+// PunishmentsCommand is a parent command for temporary punishments.
+// It acts as a container for subcommands like list and cancel.
+type PunishmentsCommand struct{}
+
+// NewPunishmentsCommand creates a new PunishmentsCommand instance.
+func NewPunishmentsCommand() *PunishmentsCommand {
+	return &PunishmentsCommand{}
+}
+
+// Name returns the name of the command.
+func (c *PunishmentsCommand) Name() string {
+	return "punishments"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *PunishmentsCommand) Synopsis() string {
+	return "Inspect and cancel temporary punishments"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *PunishmentsCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot punishments <subcommand> [options]\n\n")
+	sb.WriteString("Inspect temporary punishments, such as mutes, that are still in effect, and\n")
+	sb.WriteString("lift them early.\n\n")
+	sb.WriteString("Subcommands:\n")
+	sb.WriteString("  list    List the temporary punishments in effect\n")
+	sb.WriteString("  cancel  Lift a temporary punishment early\n\n")
+	sb.WriteString("Use \"jamesbot punishments <subcommand> -h\" for more information about a subcommand.\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the punishments command.
+// Parent commands typically don't have their own flags.
+func (c *PunishmentsCommand) SetFlags(fs *flag.FlagSet) {
+	// No flags for parent command
+}
+
+// Run executes the punishments command.
+// When invoked without a subcommand, it prints usage information.
+func (c *PunishmentsCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stdout.Write([]byte(c.Usage()))
+	return ExitOK
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"jamesbot/internal/control"
+)
+
+// PunishmentsCancelCommand implements the punishments cancel command, which
+// lifts a temporary punishment before it expires.
+type PunishmentsCancelCommand struct {
+	reason        string
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewPunishmentsCancelCommand creates a new PunishmentsCancelCommand instance.
+func NewPunishmentsCancelCommand() *PunishmentsCancelCommand {
+	return &PunishmentsCancelCommand{}
+}
+
+// Name returns the name of the command.
+func (c *PunishmentsCancelCommand) Name() string {
+	return "cancel"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *PunishmentsCancelCommand) Synopsis() string {
+	return "Lift a temporary punishment early"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *PunishmentsCancelCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot punishments cancel [options] <guild-id> <user-id> <type>\n\n")
+	sb.WriteString("Lift a member's temporary punishment now rather than when it expires. Mutes\n")
+	sb.WriteString("are Discord timeouts, so cancelling one removes the timeout; Discord caps\n")
+	sb.WriteString("timeouts at 28 days, so a mute cannot be made permanent. The reason, if\n")
+	sb.WriteString("given, is recorded in the guild's audit log. The bot API requires the auth\n")
+	sb.WriteString("token in $" + AuthTokenEnvVar + ".\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  <guild-id>  ID of the guild\n")
+	sb.WriteString("  <user-id>   ID of the member\n")
+	sb.WriteString("  <type>      Type of punishment to lift: " + control.PunishmentMute + "\n")
+	sb.WriteString("\nOptions:\n")
+	sb.WriteString("  --reason <text>     Reason recorded in the guild's audit log\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the punishments cancel command.
+func (c *PunishmentsCancelCommand) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.reason, "reason", "", "Reason recorded in the guild's audit log")
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the punishments cancel command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *PunishmentsCancelCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Validate arguments
+	if len(args) < 3 {
+		fmt.Fprintf(stderr, "Error: Missing required arguments\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	guildID := args[0]
+	userID := args[1]
+	kind := args[2]
+	if kind != control.PunishmentMute {
+		fmt.Fprintf(stderr, "Error: Unknown punishment type %q; only %s can be cancelled\n", kind, control.PunishmentMute)
+		return ExitUsage
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	// Create API client, authenticated like the moderation endpoints
+	client, err := newModerationClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	if err := client.CancelPunishment(guildID, userID, kind, c.reason); err != nil {
+		switch {
+		case errors.Is(err, control.ErrUnauthorized):
+			writeUnauthorized(stderr)
+			return ExitAPIError
+		case errors.Is(err, control.ErrNoPunishment):
+			fmt.Fprintf(stderr, "Error: User %s has no %s in effect in guild %s\n", userID, kind, guildID)
+			return ExitAPIError
+		case errors.Is(err, control.ErrMemberNotFound):
+			fmt.Fprintf(stderr, "Error: User %s is not a member of guild %s\n", userID, guildID)
+			return ExitAPIError
+		}
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		fmt.Fprintf(stderr, "Error: Failed to cancel %s: %v\n", kind, err)
+		return ExitAPIError
+	}
+
+	if c.quiet {
+		return ExitOK
+	}
+	fmt.Fprintf(stdout, "Lifted %s for user %s in guild %s\n", kind, userID, guildID)
+	return ExitOK
+}
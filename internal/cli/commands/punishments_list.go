@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"jamesbot/internal/control"
+)
+
+// PunishmentsListCommand implements the punishments list command, which shows
+// the temporary punishments in effect and when each expires.
+type PunishmentsListCommand struct {
+	jsonOutput    bool
+	guild         string
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewPunishmentsListCommand creates a new PunishmentsListCommand instance.
+func NewPunishmentsListCommand() *PunishmentsListCommand {
+	return &PunishmentsListCommand{}
+}
+
+// Name returns the name of the command.
+func (c *PunishmentsListCommand) Name() string {
+	return "list"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *PunishmentsListCommand) Synopsis() string {
+	return "List the temporary punishments in effect"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *PunishmentsListCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot punishments list [options]\n\n")
+	sb.WriteString("List the temporary punishments in effect, soonest to expire first, with\n")
+	sb.WriteString("the reason recorded in the server's audit log. Mutes are Discord timeouts,\n")
+	sb.WriteString("which Discord lifts on its own when they expire.\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  --json              Output as JSON instead of a table\n")
+	sb.WriteString("  --guild <id>        Only list punishments in this guild\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the punishments list command.
+func (c *PunishmentsListCommand) SetFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.jsonOutput, "json", false, "Output as JSON")
+	fs.StringVar(&c.guild, "guild", "", "Only list punishments in this guild")
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the punishments list command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *PunishmentsListCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	if len(args) > 0 {
+		fmt.Fprintf(stderr, "Error: Unexpected arguments: %s\n\n", strings.Join(args, " "))
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	punishments, err := client.ListPunishments(c.guild)
+	if err != nil {
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		fmt.Fprintf(stderr, "Error: Failed to get punishments: %v\n", err)
+		return ExitAPIError
+	}
+
+	if err := renderList(stdout, punishments, c.jsonOutput, "No temporary punishments in effect", writePunishmentsTable); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to write punishments: %v\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
+
+// writePunishmentsTable writes punishments as an aligned guild, user, type,
+// expiry, and reason table. Users are shown by ID, followed by their username
+// when known.
+func writePunishmentsTable(w io.Writer, punishments []control.Punishment) {
+	users := make([]string, len(punishments))
+	maxGuildLen, maxUserLen, maxTypeLen := len("Guild"), len("User"), len("Type")
+	for i, p := range punishments {
+		users[i] = p.UserID
+		if p.Username != "" {
+			users[i] += " (" + p.Username + ")"
+		}
+		maxGuildLen = max(maxGuildLen, len(p.GuildID))
+		maxUserLen = max(maxUserLen, len(users[i]))
+		maxTypeLen = max(maxTypeLen, len(p.Type))
+	}
+	expiresLen := len("2006-01-02T15:04:05Z")
+
+	fmt.Fprintf(w, "%-*s  %-*s  %-*s  %-*s  %s\n", maxGuildLen, "Guild", maxUserLen, "User", maxTypeLen, "Type", expiresLen, "Expires", "Reason")
+	fmt.Fprintf(w, "%s  %s  %s  %s  %s\n", strings.Repeat("-", maxGuildLen), strings.Repeat("-", maxUserLen),
+		strings.Repeat("-", maxTypeLen), strings.Repeat("-", expiresLen), strings.Repeat("-", len("Reason")))
+	for i, p := range punishments {
+		reason := p.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%-*s  %-*s  %-*s  %-*s  %s\n", maxGuildLen, p.GuildID, maxUserLen, users[i], maxTypeLen, p.Type,
+			expiresLen, p.ExpiresAt.UTC().Format(time.RFC3339), reason)
+	}
+}
@@ -0,0 +1,283 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+	"jamesbot/internal/control"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// PunishmentsListCommand Tests
+// ===========================================================================
+
+func Test_PunishmentsListCommand_Metadata(t *testing.T) {
+	cmd := commands.NewPunishmentsListCommand()
+
+	assert.Equal(t, "list", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot punishments list")
+}
+
+func Test_PunishmentsListCommand_Run(t *testing.T) {
+	const twoPunishments = `[
+		{"guild_id":"111","user_id":"1","username":"spammer","type":"mute","reason":"spam","expires_at":"2024-01-01T12:00:00Z"},
+		{"guild_id":"111","user_id":"22","type":"mute","expires_at":"2024-01-02T12:00:00+02:00"}
+	]`
+
+	tests := []struct {
+		name       string
+		args       []string
+		status     int
+		response   string
+		wantExit   int
+		wantQuery  string
+		wantStdout []string
+		wantStderr string
+	}{
+		{
+			name:     "table",
+			status:   http.StatusOK,
+			response: twoPunishments,
+			wantExit: commands.ExitOK,
+			wantStdout: []string{
+				"Guild  User         Type  Expires               Reason\n",
+				"111    1 (spammer)  mute  2024-01-01T12:00:00Z  spam\n",
+				"111    22           mute  2024-01-02T10:00:00Z  -\n",
+			},
+		},
+		{
+			name:       "guild filter",
+			args:       []string{"--guild", "111"},
+			status:     http.StatusOK,
+			response:   twoPunishments,
+			wantExit:   commands.ExitOK,
+			wantQuery:  "guild=111",
+			wantStdout: []string{"1 (spammer)"},
+		},
+		{
+			name:       "none in effect",
+			status:     http.StatusOK,
+			response:   `[]`,
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"No temporary punishments in effect\n"},
+		},
+		{
+			name:       "none in effect as JSON",
+			args:       []string{"--json"},
+			status:     http.StatusOK,
+			response:   `[]`,
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"[]\n"},
+		},
+		{
+			name:       "invalid guild",
+			args:       []string{"--guild", "abc"},
+			status:     http.StatusBadRequest,
+			response:   "Bad request: guild must be a Discord ID\n",
+			wantExit:   commands.ExitAPIError,
+			wantQuery:  "guild=abc",
+			wantStderr: "Failed to get punishments: guild must be a Discord ID",
+		},
+		{
+			name:       "unexpected argument",
+			args:       []string{"extra"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Unexpected arguments: extra",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/punishments", r.URL.Path)
+				assert.Equal(t, tt.wantQuery, r.URL.RawQuery)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			cmd := commands.NewPunishmentsListCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(tt.args))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, stderr.String())
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func Test_PunishmentsListCommand_Run_JSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"guild_id":"111","user_id":"1","type":"mute","reason":"spam","expires_at":"2024-01-01T12:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	cmd := commands.NewPunishmentsListCommand()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--json"}))
+
+	stdout := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+	exitCode := cmd.Run(ctx, fs.Args())
+
+	require.Equal(t, commands.ExitOK, exitCode)
+	var punishments []control.Punishment
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &punishments))
+	require.Len(t, punishments, 1)
+	assert.Equal(t, "spam", punishments[0].Reason)
+	assert.Equal(t, control.PunishmentMute, punishments[0].Type)
+}
+
+func Test_PunishmentsListCommand_Run_ConnectionError(t *testing.T) {
+	cmd := commands.NewPunishmentsListCommand()
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://localhost:1"}
+
+	exitCode := cmd.Run(ctx, nil)
+
+	assert.Equal(t, commands.ExitConnectionError, exitCode)
+	assert.Contains(t, stderr.String(), "Cannot connect")
+}
+
+// ===========================================================================
+// PunishmentsCancelCommand Tests
+// ===========================================================================
+
+func Test_PunishmentsCancelCommand_Metadata(t *testing.T) {
+	cmd := commands.NewPunishmentsCancelCommand()
+
+	assert.Equal(t, "cancel", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot punishments cancel")
+	assert.Contains(t, cmd.Usage(), commands.AuthTokenEnvVar)
+}
+
+func Test_PunishmentsCancelCommand_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		token       string
+		status      int
+		wantExit    int
+		wantRequest *control.CancelPunishmentRequest
+		wantStdout  string
+		wantStderr  string
+	}{
+		{
+			name:        "lifts mute",
+			args:        []string{"--reason", "appeal accepted", "111", "222", "mute"},
+			token:       "secret",
+			status:      http.StatusOK,
+			wantExit:    commands.ExitOK,
+			wantRequest: &control.CancelPunishmentRequest{GuildID: "111", UserID: "222", Type: "mute", Reason: "appeal accepted"},
+			wantStdout:  "Lifted mute for user 222 in guild 111\n",
+		},
+		{
+			name:       "quiet",
+			args:       []string{"-q", "111", "222", "mute"},
+			token:      "secret",
+			status:     http.StatusOK,
+			wantExit:   commands.ExitOK,
+			wantStdout: "",
+		},
+		{
+			name:       "not in effect",
+			args:       []string{"111", "222", "mute"},
+			token:      "secret",
+			status:     http.StatusConflict,
+			wantExit:   commands.ExitAPIError,
+			wantStderr: "User 222 has no mute in effect in guild 111",
+		},
+		{
+			name:       "not a member",
+			args:       []string{"111", "222", "mute"},
+			token:      "secret",
+			status:     http.StatusNotFound,
+			wantExit:   commands.ExitAPIError,
+			wantStderr: "User 222 is not a member of guild 111",
+		},
+		{
+			name:       "missing token",
+			args:       []string{"111", "222", "mute"},
+			status:     http.StatusUnauthorized,
+			wantExit:   commands.ExitAPIError,
+			wantStderr: commands.AuthTokenEnvVar,
+		},
+		{
+			name:       "unknown type",
+			args:       []string{"111", "222", "ban"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: `Unknown punishment type "ban"`,
+		},
+		{
+			name:       "missing type",
+			args:       []string{"111", "222"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Missing required arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *control.CancelPunishmentRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/punishments/cancel", r.URL.Path)
+				var req control.CancelPunishmentRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				received = &req
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			t.Setenv(commands.AuthTokenEnvVar, tt.token)
+
+			cmd := commands.NewPunishmentsCancelCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(tt.args))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, stderr.String())
+			assert.Equal(t, tt.wantStdout, stdout.String())
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+			if tt.wantRequest != nil {
+				assert.Equal(t, tt.wantRequest, received)
+			}
+			if tt.status == 0 {
+				assert.Nil(t, received, "usage errors are not sent to the bot")
+			}
+		})
+	}
+}
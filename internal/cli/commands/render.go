@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// writeJSON writes v as indented JSON followed by a newline.
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// renderList writes the output of a list command so every list command treats
+// empty results the same way. As JSON, items are always written as an array,
+// "[]" when empty, never null. Otherwise an empty list prints the empty line
+// instead of a table, so the output is never blank.
+func renderList[T any](w io.Writer, items []T, asJSON bool, empty string, table func(io.Writer, []T)) error {
+	if asJSON {
+		if items == nil {
+			items = []T{}
+		}
+		return writeJSON(w, items)
+	}
+
+	if len(items) == 0 {
+		_, err := fmt.Fprintln(w, empty)
+		return err
+	}
+
+	table(w, items)
+	return nil
+}
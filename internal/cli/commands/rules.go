@@ -7,7 +7,7 @@ import (
 )
 
 // RulesCommand is a parent command for rule management.
-// It acts as a container for subcommands like list and set.
+// It acts as a container for subcommands like list, set, test, import, and export.
 type RulesCommand struct{}
 
 // NewRulesCommand creates a new RulesCommand instance.
@@ -31,8 +31,11 @@ func (c *RulesCommand) Usage() string {
 	sb.WriteString("Usage: jamesbot rules <subcommand> [options]\n\n")
 	sb.WriteString("Manage server rules and rule configurations.\n\n")
 	sb.WriteString("Subcommands:\n")
-	sb.WriteString("  list   List all server rules\n")
-	sb.WriteString("  set    Set or update a rule\n\n")
+	sb.WriteString("  list    List all server rules\n")
+	sb.WriteString("  set     Set or update a rule\n")
+	sb.WriteString("  test    Test a content rule against a sample message\n")
+	sb.WriteString("  import  Apply rule settings from a file\n")
+	sb.WriteString("  export  Write all rule settings to a file\n\n")
 	sb.WriteString("Use \"jamesbot rules <subcommand> -h\" for more information about a subcommand.\n")
 	return sb.String()
 }
@@ -50,5 +53,5 @@ func (c *RulesCommand) Run(ctx *CLIContext, args []string) int {
 	// registered as a ParentCommand, but we provide a fallback implementation.
 	stdout := ctx.Stdout
 	stdout.Write([]byte(c.Usage()))
-	return 0
+	return ExitOK
 }
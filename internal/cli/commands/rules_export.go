@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Export formats accepted by the rules export command.
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// RulesExportCommand implements the rules export command for backing up rule
+// settings in the format read by rules import.
+type RulesExportCommand struct {
+	format        string
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewRulesExportCommand creates a new RulesExportCommand instance.
+func NewRulesExportCommand() *RulesExportCommand {
+	return &RulesExportCommand{}
+}
+
+// Name returns the name of the command.
+func (c *RulesExportCommand) Name() string {
+	return "export"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *RulesExportCommand) Synopsis() string {
+	return "Write all rule settings to a file"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *RulesExportCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot rules export [file] [options]\n\n")
+	sb.WriteString("Write every rule setting as JSON or YAML, in the format read by \"rules import\".\n")
+	sb.WriteString("Without a file, the rules are written to stdout.\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  [file]  Path to write; overwritten if it exists\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  --format <fmt>      Output format: json or yaml (default: yaml for .yaml/.yml files, otherwise json)\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n\n")
+	sb.WriteString("Examples:\n")
+	sb.WriteString("  jamesbot rules export rules.json\n")
+	sb.WriteString("  jamesbot rules export --format yaml > rules.yaml\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the rules export command.
+func (c *RulesExportCommand) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.format, "format", "", "Output format: json or yaml")
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the rules export command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *RulesExportCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	format := strings.ToLower(c.format)
+	if format == "" {
+		format = formatJSON
+		if isYAMLPath(path) {
+			format = formatYAML
+		}
+	}
+	if format != formatJSON && format != formatYAML {
+		fmt.Fprintf(stderr, "Error: Unknown format %q (want json or yaml)\n\n", c.format)
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	// Create API client
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	// Get rules from API
+	rules, err := client.ListRules()
+	if err != nil {
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		// Other API errors
+		fmt.Fprintf(stderr, "Error: Failed to get rules: %v\n", err)
+		return ExitAPIError
+	}
+
+	var buf bytes.Buffer
+	if err := writeRules(&buf, rules, format == formatYAML); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to encode rules: %v\n", err)
+		return ExitError
+	}
+
+	if path == "" {
+		stdout.Write(buf.Bytes())
+		return ExitOK
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to write %s: %v\n", path, err)
+		return ExitError
+	}
+
+	fmt.Fprintf(stdout, "Exported %d rule setting(s) to %s\n", len(rules), path)
+	return ExitOK
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"jamesbot/internal/control"
+	"jamesbot/internal/rules"
+)
+
+// ruleEntry is one rule setting in a rules file.
+// Its JSON form matches control.Rule, so the output of "rules list --json" and
+// "rules export" can be imported as-is. Description is informational only.
+// Guild, when set, scopes the setting to that guild.
+type ruleEntry struct {
+	Guild       string `json:"guild,omitempty" yaml:"guild,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Enabled     *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Key         string `json:"key" yaml:"key"`
+	Value       string `json:"value" yaml:"value"`
+}
+
+// isYAMLPath reports whether path has a YAML file extension.
+func isYAMLPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// readRuleFile reads a list of rule settings from a JSON or YAML file.
+// Files ending in .yaml or .yml are parsed as YAML; anything else as JSON.
+// An entry's enabled field becomes an "enabled" setting for its rule, in the
+// entry's guild scope, unless the file also sets that key explicitly there or
+// the entry belongs to the settings group, which cannot be turned off.
+func readRuleFile(path string) ([]control.SetRuleRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ruleEntry
+	if isYAMLPath(path) {
+		err = yaml.Unmarshal(data, &entries)
+	} else {
+		err = json.Unmarshal(data, &entries)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	// scope identifies a rule within a guild, or globally when guild is empty.
+	type scope struct{ guild, name string }
+
+	reqs := make([]control.SetRuleRequest, 0, len(entries))
+	explicit := make(map[scope]bool)
+	enabled := make(map[scope]bool)
+	var enabledOrder []scope
+	for _, e := range entries {
+		reqs = append(reqs, control.SetRuleRequest{Guild: e.Guild, Name: e.Name, Key: e.Key, Value: e.Value})
+		sc := scope{e.Guild, e.Name}
+		if e.Key == rules.KeyEnabled {
+			explicit[sc] = true
+		}
+		if e.Enabled != nil && e.Name != rules.RuleSettings {
+			if _, seen := enabled[sc]; !seen {
+				enabledOrder = append(enabledOrder, sc)
+			}
+			enabled[sc] = *e.Enabled
+		}
+	}
+
+	for _, sc := range enabledOrder {
+		if explicit[sc] {
+			continue
+		}
+		reqs = append(reqs, control.SetRuleRequest{
+			Guild: sc.guild,
+			Name:  sc.name,
+			Key:   rules.KeyEnabled,
+			Value: strconv.FormatBool(enabled[sc]),
+		})
+	}
+	return reqs, nil
+}
+
+// writeRules encodes rules in the rules file format as indented JSON or YAML.
+func writeRules(w io.Writer, list []control.Rule, asYAML bool) error {
+	entries := make([]ruleEntry, 0, len(list))
+	for _, r := range list {
+		enabled := r.Enabled
+		entries = append(entries, ruleEntry{
+			Guild:       r.Guild,
+			Name:        r.Name,
+			Description: r.Description,
+			Enabled:     &enabled,
+			Key:         r.Key,
+			Value:       r.Value,
+		})
+	}
+
+	if asYAML {
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(entries); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	return writeJSON(w, entries)
+}
+
+// settingLabel formats a rule setting for output as name.key = value,
+// followed by its guild when the setting is guild-scoped.
+func settingLabel(guild, name, key, value string) string {
+	label := fmt.Sprintf("%s.%s = %s", name, key, value)
+	if guild != "" {
+		label += fmt.Sprintf(" (guild %s)", guild)
+	}
+	return label
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// RulesImportCommand implements the rules import command for applying rule
+// settings from a file in a single batch.
+type RulesImportCommand struct {
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewRulesImportCommand creates a new RulesImportCommand instance.
+func NewRulesImportCommand() *RulesImportCommand {
+	return &RulesImportCommand{}
+}
+
+// Name returns the name of the command.
+func (c *RulesImportCommand) Name() string {
+	return "import"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *RulesImportCommand) Synopsis() string {
+	return "Apply rule settings from a file"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *RulesImportCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot rules import <file> [options]\n\n")
+	sb.WriteString("Apply rule settings from a JSON or YAML file in one batch.\n")
+	sb.WriteString("The file holds a list of objects with name, key, and value fields, and\n")
+	sb.WriteString("an optional guild field to scope a setting to one guild.\n")
+	sb.WriteString("Files ending in .yaml or .yml are read as YAML; anything else as JSON.\n")
+	sb.WriteString("Every setting is attempted; failures are reported without stopping the rest.\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  <file>  Path to the rules file\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n\n")
+	sb.WriteString("Examples:\n")
+	sb.WriteString("  jamesbot rules import rules.json\n")
+	sb.WriteString("  jamesbot rules import rules.yaml\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the rules import command.
+func (c *RulesImportCommand) SetFlags(fs *flag.FlagSet) {
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the rules import command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *RulesImportCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Validate arguments
+	if len(args) < 1 {
+		fmt.Fprintf(stderr, "Error: Missing required arguments\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	reqs, err := readRuleFile(args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to read rules file: %v\n", err)
+		return ExitError
+	}
+	if len(reqs) == 0 {
+		fmt.Fprintf(stderr, "Error: No rules found in %s\n", args[0])
+		return ExitError
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	// Create API client
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	// Apply rules via API
+	result, err := client.SetRules(reqs)
+	if err != nil {
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		// Other API errors
+		fmt.Fprintf(stderr, "Error: Failed to import rules: %v\n", err)
+		return ExitAPIError
+	}
+
+	for _, r := range result.Results {
+		if r.Error != "" {
+			fmt.Fprintf(stderr, "FAIL  %s: %s\n", settingLabel(r.Guild, r.Name, r.Key, r.Value), r.Error)
+			continue
+		}
+		if r.Warning != "" {
+			fmt.Fprintf(stderr, "WARN  %s: %s\n", settingLabel(r.Guild, r.Name, r.Key, r.Value), r.Warning)
+			continue
+		}
+		if !c.quiet {
+			fmt.Fprintf(stdout, "ok    %s\n", settingLabel(r.Guild, r.Name, r.Key, r.Value))
+		}
+	}
+	if !c.quiet {
+		fmt.Fprintf(stdout, "Imported %d rule setting(s), %d failed\n", result.Succeeded, result.Failed)
+	}
+
+	if result.Failed > 0 {
+		return ExitAPIError
+	}
+	return ExitOK
+}
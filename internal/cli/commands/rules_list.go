@@ -2,18 +2,20 @@
 package commands
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"strings"
 
-	"jamesbot/internal/api"
+	"jamesbot/internal/control"
 )
 
 // RulesListCommand implements the rules list command for displaying all server rules.
 type RulesListCommand struct {
-	jsonOutput bool
-	endpoint   string
+	jsonOutput    bool
+	guild         string
+	endpoint      endpointFlags
+	noHealthCheck bool
 }
 
 // NewRulesListCommand creates a new RulesListCommand instance.
@@ -38,7 +40,9 @@ func (c *RulesListCommand) Usage() string {
 	sb.WriteString("List all configured server rules.\n\n")
 	sb.WriteString("Options:\n")
 	sb.WriteString("  --json              Output rules as JSON instead of human-readable format\n")
-	sb.WriteString("  --endpoint <url>    API endpoint (default: http://127.0.0.1:8765)\n")
+	sb.WriteString("  --guild <id>        List the settings in effect in a guild, including its overrides\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
 	sb.WriteString("  -h, --help          Show this help message\n")
 	return sb.String()
 }
@@ -46,7 +50,9 @@ func (c *RulesListCommand) Usage() string {
 // SetFlags configures the command-line flags for the rules list command.
 func (c *RulesListCommand) SetFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.jsonOutput, "json", false, "Output rules as JSON")
-	fs.StringVar(&c.endpoint, "endpoint", "http://127.0.0.1:8765", "API endpoint")
+	fs.StringVar(&c.guild, "guild", "", "List the settings in effect in a guild")
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
 }
 
 // Run executes the rules list command.
@@ -56,81 +62,66 @@ func (c *RulesListCommand) Run(ctx *CLIContext, args []string) int {
 	stdout := ctx.Stdout
 	stderr := ctx.Stderr
 
-	// Use API endpoint from context if provided, otherwise use flag value
-	endpoint := c.endpoint
-	if ctx.APIEndpoint != "" {
-		endpoint = ctx.APIEndpoint
-	}
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
 
 	// Create API client
-	client := api.NewClient(endpoint)
-	if client == nil {
-		fmt.Fprintf(stderr, "Error: Failed to create API client\n")
-		return 1
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
 	}
 
 	// Get rules from API
-	rules, err := client.ListRules()
+	rules, err := client.ListGuildRules(c.guild)
 	if err != nil {
-		// Check if this is a connection error
-		if strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection failed") {
-			fmt.Fprintf(stderr, "Error: Cannot connect to bot API at %s\n", endpoint)
-			fmt.Fprintf(stderr, "Make sure the bot is running with 'jamesbot serve'\n")
-			return 1
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
 		}
 
 		// Other API errors
 		fmt.Fprintf(stderr, "Error: Failed to get rules: %v\n", err)
-		return 1
+		return ExitAPIError
 	}
 
 	// Handle nil rules
 	if rules == nil {
 		fmt.Fprintf(stderr, "Error: Received nil rules from API\n")
-		return 1
+		return ExitError
 	}
 
-	// Output rules in requested format
-	if c.jsonOutput {
-		// JSON output
-		encoder := json.NewEncoder(stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(rules); err != nil {
-			fmt.Fprintf(stderr, "Error: Failed to encode rules as JSON: %v\n", err)
-			return 1
+	if err := renderList(stdout, rules, c.jsonOutput, "No rules configured", writeRulesTable); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to write rules: %v\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
+
+// writeRulesTable writes rules as an aligned name, enabled, and description table.
+func writeRulesTable(w io.Writer, rules []control.Rule) {
+	// Calculate column widths
+	maxNameLen := len("Name")
+	maxDescLen := len("Description")
+	for _, rule := range rules {
+		if len(rule.Name) > maxNameLen {
+			maxNameLen = len(rule.Name)
 		}
-	} else {
-		// Human-readable table output
-		if len(rules) == 0 {
-			fmt.Fprintf(stdout, "No rules configured\n")
-			return 0
+		if len(rule.Description) > maxDescLen {
+			maxDescLen = len(rule.Description)
 		}
+	}
 
-		// Calculate column widths
-		maxNameLen := len("Name")
-		maxDescLen := len("Description")
-		for _, rule := range rules {
-			if len(rule.Name) > maxNameLen {
-				maxNameLen = len(rule.Name)
-			}
-			if len(rule.Description) > maxDescLen {
-				maxDescLen = len(rule.Description)
-			}
-		}
+	// Print header
+	fmt.Fprintf(w, "%-*s  %-7s  %-*s\n", maxNameLen, "Name", "Enabled", maxDescLen, "Description")
+	fmt.Fprintf(w, "%s  %s  %s\n", strings.Repeat("-", maxNameLen), strings.Repeat("-", 7), strings.Repeat("-", maxDescLen))
 
-		// Print header
-		fmt.Fprintf(stdout, "%-*s  %-7s  %-*s\n", maxNameLen, "Name", "Enabled", maxDescLen, "Description")
-		fmt.Fprintf(stdout, "%s  %s  %s\n", strings.Repeat("-", maxNameLen), strings.Repeat("-", 7), strings.Repeat("-", maxDescLen))
-
-		// Print rules
-		for _, rule := range rules {
-			enabledStr := "false"
-			if rule.Enabled {
-				enabledStr = "true"
-			}
-			fmt.Fprintf(stdout, "%-*s  %-7s  %-*s\n", maxNameLen, rule.Name, enabledStr, maxDescLen, rule.Description)
+	// Print rules
+	for _, rule := range rules {
+		enabledStr := "false"
+		if rule.Enabled {
+			enabledStr = "true"
 		}
+		fmt.Fprintf(w, "%-*s  %-7s  %-*s\n", maxNameLen, rule.Name, enabledStr, maxDescLen, rule.Description)
 	}
-
-	return 0
 }
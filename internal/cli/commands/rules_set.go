@@ -2,16 +2,21 @@
 package commands
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"strings"
 
 	"jamesbot/internal/api"
+	"jamesbot/internal/control"
 )
 
 // RulesSetCommand implements the rules set command for modifying rule settings.
 type RulesSetCommand struct {
-	endpoint string
+	quiet         bool
+	guild         string
+	endpoint      endpointFlags
+	noHealthCheck bool
 }
 
 // NewRulesSetCommand creates a new RulesSetCommand instance.
@@ -39,17 +44,24 @@ func (c *RulesSetCommand) Usage() string {
 	sb.WriteString("  <key>        Configuration key to set\n")
 	sb.WriteString("  <value>      Value to set for the key\n\n")
 	sb.WriteString("Options:\n")
-	sb.WriteString("  --endpoint <url>    API endpoint (default: http://127.0.0.1:8765)\n")
+	sb.WriteString("  --guild <id>        Override the setting for one guild instead of globally\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
 	sb.WriteString("  -h, --help          Show this help message\n\n")
 	sb.WriteString("Examples:\n")
 	sb.WriteString("  jamesbot rules set spam-filter enabled true\n")
 	sb.WriteString("  jamesbot rules set auto-mod threshold 5\n")
+	sb.WriteString("  jamesbot rules set --guild 123456789012345678 word-filter enabled true\n")
 	return sb.String()
 }
 
 // SetFlags configures the command-line flags for the rules set command.
 func (c *RulesSetCommand) SetFlags(fs *flag.FlagSet) {
-	fs.StringVar(&c.endpoint, "endpoint", "http://127.0.0.1:8765", "API endpoint")
+	fs.StringVar(&c.guild, "guild", "", "Guild ID to scope the setting to")
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
 }
 
 // Run executes the rules set command.
@@ -63,42 +75,46 @@ func (c *RulesSetCommand) Run(ctx *CLIContext, args []string) int {
 	if len(args) < 3 {
 		fmt.Fprintf(stderr, "Error: Missing required arguments\n\n")
 		fmt.Fprintf(stderr, "%s", c.Usage())
-		return 1
+		return ExitUsage
 	}
 
 	ruleName := args[0]
 	key := args[1]
 	value := args[2]
 
-	// Use API endpoint from context if provided, otherwise use flag value
-	endpoint := c.endpoint
-	if ctx.APIEndpoint != "" {
-		endpoint = ctx.APIEndpoint
-	}
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
 
-	// Create API client
-	client := api.NewClient(endpoint)
-	if client == nil {
-		fmt.Fprintf(stderr, "Error: Failed to create API client\n")
-		return 1
+	// Create API client; updates carry an idempotency key, so retrying one
+	// whose response was lost cannot apply it twice
+	client, err := newClient(endpoint, &c.endpoint, api.WithRetries(api.DefaultRetries))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
 	}
 
 	// Set rule via API
-	err := client.SetRule(ruleName, key, value)
+	err = client.SetGuildRule(c.guild, ruleName, key, value)
+	if errors.Is(err, control.ErrRuleNotSaved) {
+		// The setting took effect; warn, even when quiet, that it will not
+		// survive a restart
+		fmt.Fprintf(stderr, "Warning: %s: %v\n", settingLabel(c.guild, ruleName, key, value), err)
+		return ExitOK
+	}
 	if err != nil {
-		// Check if this is a connection error
-		if strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection failed") {
-			fmt.Fprintf(stderr, "Error: Cannot connect to bot API at %s\n", endpoint)
-			fmt.Fprintf(stderr, "Make sure the bot is running with 'jamesbot serve'\n")
-			return 1
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
 		}
 
 		// Other API errors
 		fmt.Fprintf(stderr, "Error: Failed to set rule: %v\n", err)
-		return 1
+		return ExitAPIError
 	}
 
 	// Success message
-	fmt.Fprintf(stdout, "Successfully set %s.%s = %s\n", ruleName, key, value)
-	return 0
+	if c.quiet {
+		return ExitOK
+	}
+	fmt.Fprintf(stdout, "Successfully set %s\n", settingLabel(c.guild, ruleName, key, value))
+	return ExitOK
 }
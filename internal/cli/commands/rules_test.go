@@ -4,16 +4,23 @@ import (
 	"bytes"
 	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"jamesbot/internal/cli/commands"
 	"jamesbot/internal/control"
+	"jamesbot/internal/rules"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // Note: RulesCommand and subcommands use commands.CLIContext instead of cli.Context
@@ -119,7 +126,7 @@ func Test_RulesCommand_Run_WithoutSubcommand(t *testing.T) {
 		{
 			name:                 "no subcommand prints usage and exits 0",
 			args:                 []string{},
-			expectExitCode:       0,
+			expectExitCode:       commands.ExitOK,
 			expectOutputContains: []string{"list", "set"},
 		},
 	}
@@ -268,7 +275,7 @@ func Test_RulesListCommand_SetFlags(t *testing.T) {
 	}{
 		{
 			name:          "registers json and endpoint flags",
-			expectedFlags: []string{"json", "endpoint"},
+			expectedFlags: []string{"json", "endpoint", "no-health-check"},
 		},
 	}
 
@@ -335,7 +342,7 @@ func Test_RulesListCommand_Run_SuccessfulList(t *testing.T) {
 				{Name: "anti-spam", Description: "Prevents spam", Enabled: true, Key: "threshold", Value: "5"},
 				{Name: "link-filter", Description: "Filters links", Enabled: false, Key: "domains", Value: "*.xyz"},
 			},
-			expectExitCode: 0,
+			expectExitCode: commands.ExitOK,
 			expectContains: []string{"anti-spam", "link-filter"},
 		},
 		{
@@ -343,7 +350,7 @@ func Test_RulesListCommand_Run_SuccessfulList(t *testing.T) {
 			rules: []control.Rule{
 				{Name: "profanity-filter", Description: "Filters profanity", Enabled: true, Key: "level", Value: "strict"},
 			},
-			expectExitCode: 0,
+			expectExitCode: commands.ExitOK,
 			expectContains: []string{"profanity-filter"},
 		},
 	}
@@ -403,7 +410,7 @@ func Test_RulesListCommand_Run_EmptyRules(t *testing.T) {
 		{
 			name:              "empty rules shows no rules message",
 			rules:             []control.Rule{},
-			expectExitCode:    0,
+			expectExitCode:    commands.ExitOK,
 			expectContainsOne: []string{"no rules", "no configured rules", "empty", "none"},
 		},
 	}
@@ -458,6 +465,41 @@ func Test_RulesListCommand_Run_EmptyRules(t *testing.T) {
 	}
 }
 
+// Test_RulesListCommand_Run_EmptyJSON verifies an empty rules list is written
+// as a JSON array with a trailing newline rather than blank output.
+func Test_RulesListCommand_Run_EmptyJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{name: "empty array", response: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			cmd := &commands.RulesListCommand{}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse([]string{"--json"}))
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, commands.ExitOK, exitCode)
+			assert.Equal(t, "[]\n", stdout.String())
+		})
+	}
+}
+
 // Test_RulesListCommand_Run_JSONOutput tests JSON output format.
 func Test_RulesListCommand_Run_JSONOutput(t *testing.T) {
 	tests := []struct {
@@ -471,12 +513,12 @@ func Test_RulesListCommand_Run_JSONOutput(t *testing.T) {
 				{Name: "spam-filter", Description: "Filters spam", Enabled: true, Key: "rate", Value: "10"},
 				{Name: "caps-filter", Description: "Filters caps", Enabled: false, Key: "threshold", Value: "50"},
 			},
-			expectExitCode: 0,
+			expectExitCode: commands.ExitOK,
 		},
 		{
 			name:           "empty rules outputs empty JSON array",
 			rules:          []control.Rule{},
-			expectExitCode: 0,
+			expectExitCode: commands.ExitOK,
 		},
 	}
 
@@ -519,6 +561,7 @@ func Test_RulesListCommand_Run_JSONOutput(t *testing.T) {
 			var output []map[string]interface{}
 			err = json.Unmarshal(stdout.Bytes(), &output)
 			assert.NoError(t, err, "stdout should be valid JSON array")
+			assert.NotNil(t, output, "stdout should be a JSON array, never null")
 			assert.Len(t, output, len(tt.rules), "JSON array should have correct length")
 		})
 	}
@@ -536,7 +579,12 @@ func Test_RulesListCommand_Run_JSONContainsFields(t *testing.T) {
 			rules: []control.Rule{
 				{Name: "test-rule", Description: "Test description", Enabled: true, Key: "test-key", Value: "test-value"},
 			},
-			expectedFields: []string{"name", "description", "enabled"},
+			expectedFields: []string{"name", "description", "enabled", "key", "value", "guild"},
+		},
+		{
+			name:           "zero-value fields are still emitted",
+			rules:          []control.Rule{{}},
+			expectedFields: []string{"name", "description", "enabled", "key", "value", "guild"},
 		},
 	}
 
@@ -571,7 +619,7 @@ func Test_RulesListCommand_Run_JSONContainsFields(t *testing.T) {
 			}
 
 			exitCode := cmd.Run(ctx, fs.Args())
-			assert.Equal(t, 0, exitCode, "Run() should return exit code 0")
+			assert.Equal(t, commands.ExitOK, exitCode, "Run() should return ExitOK")
 
 			// Parse JSON and verify fields
 			var output []map[string]interface{}
@@ -583,6 +631,11 @@ func Test_RulesListCommand_Run_JSONContainsFields(t *testing.T) {
 				_, exists := output[0][field]
 				assert.True(t, exists, "JSON output should contain field %q", field)
 			}
+			assert.Len(t, output[0], len(tt.expectedFields), "JSON output should contain no extra fields")
+			assert.IsType(t, true, output[0]["enabled"], "enabled should be a JSON boolean")
+			for _, field := range []string{"name", "description", "key", "value", "guild"} {
+				assert.IsType(t, "", output[0][field], "%s should be a JSON string", field)
+			}
 		})
 	}
 }
@@ -595,8 +648,8 @@ func Test_RulesListCommand_Run_ConnectionError(t *testing.T) {
 		expectStderrContain []string
 	}{
 		{
-			name:                "connection error returns exit 1",
-			expectExitCode:      1,
+			name:                "connection error returns connection exit code",
+			expectExitCode:      commands.ExitConnectionError,
 			expectStderrContain: []string{"cannot connect", "connection", "error", "failed"},
 		},
 	}
@@ -651,19 +704,19 @@ func Test_RulesListCommand_Run_ServerError(t *testing.T) {
 		expectExitCode int
 	}{
 		{
-			name:           "server 500 error returns exit 1",
+			name:           "server 500 error returns error exit code",
 			statusCode:     500,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 		{
-			name:           "server 404 error returns exit 1",
+			name:           "server 404 error returns error exit code",
 			statusCode:     404,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 		{
-			name:           "server 503 error returns exit 1",
+			name:           "server 503 error returns error exit code",
 			statusCode:     503,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 	}
 
@@ -823,7 +876,7 @@ func Test_RulesSetCommand_SetFlags(t *testing.T) {
 	}{
 		{
 			name:          "registers endpoint flag",
-			expectedFlags: []string{"endpoint"},
+			expectedFlags: []string{"endpoint", "no-health-check"},
 		},
 	}
 
@@ -854,13 +907,13 @@ func Test_RulesSetCommand_Run_SuccessfulSet(t *testing.T) {
 		{
 			name:              "successful set displays success message",
 			args:              []string{"spam-filter", "threshold", "10"},
-			expectExitCode:    0,
+			expectExitCode:    commands.ExitOK,
 			expectContainsOne: []string{"success", "updated", "set", "ok"},
 		},
 		{
 			name:              "setting rule with different values succeeds",
 			args:              []string{"link-filter", "enabled", "true"},
-			expectExitCode:    0,
+			expectExitCode:    commands.ExitOK,
 			expectContainsOne: []string{"success", "updated", "set", "ok"},
 		},
 	}
@@ -934,21 +987,21 @@ func Test_RulesSetCommand_Run_MissingArgs(t *testing.T) {
 		expectContainsOne []string
 	}{
 		{
-			name:              "no arguments returns exit 1 with usage error",
+			name:              "no arguments returns usage exit code",
 			args:              []string{},
-			expectExitCode:    1,
+			expectExitCode:    commands.ExitUsage,
 			expectContainsOne: []string{"usage", "error", "required", "missing", "argument"},
 		},
 		{
-			name:              "only name argument returns exit 1",
+			name:              "only name argument returns usage exit code",
 			args:              []string{"spam-filter"},
-			expectExitCode:    1,
+			expectExitCode:    commands.ExitUsage,
 			expectContainsOne: []string{"usage", "error", "required", "missing", "argument"},
 		},
 		{
-			name:              "only name and key returns exit 1",
+			name:              "only name and key returns usage exit code",
 			args:              []string{"spam-filter", "threshold"},
-			expectExitCode:    1,
+			expectExitCode:    commands.ExitUsage,
 			expectContainsOne: []string{"usage", "error", "required", "missing", "argument"},
 		},
 	}
@@ -1008,9 +1061,9 @@ func Test_RulesSetCommand_Run_ConnectionError(t *testing.T) {
 		expectStderrContain []string
 	}{
 		{
-			name:                "connection error returns exit 1",
+			name:                "connection error returns connection exit code",
 			args:                []string{"test-rule", "key", "value"},
-			expectExitCode:      1,
+			expectExitCode:      commands.ExitConnectionError,
 			expectStderrContain: []string{"cannot connect", "connection", "error", "failed"},
 		},
 	}
@@ -1066,22 +1119,22 @@ func Test_RulesSetCommand_Run_ServerError(t *testing.T) {
 		expectExitCode int
 	}{
 		{
-			name:           "server 400 bad request returns exit 1",
+			name:           "server 400 bad request returns error exit code",
 			args:           []string{"test-rule", "key", "value"},
 			statusCode:     400,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 		{
-			name:           "server 500 error returns exit 1",
+			name:           "server 500 error returns error exit code",
 			args:           []string{"test-rule", "key", "value"},
 			statusCode:     500,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 		{
-			name:           "server 404 not found returns exit 1",
+			name:           "server 404 not found returns error exit code",
 			args:           []string{"nonexistent-rule", "key", "value"},
 			statusCode:     404,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 	}
 
@@ -1127,6 +1180,27 @@ func Test_RulesSetCommand_Run_ServerError(t *testing.T) {
 	}
 }
 
+func Test_RulesSetCommand_Run_NotSaved(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		warning := fmt.Errorf("%w: read-only file system", control.ErrRuleNotSaved).Error()
+		_ = json.NewEncoder(w).Encode(control.SetRuleResponse{Status: "ok", Warning: warning})
+	}))
+	defer server.Close()
+
+	cmd := &commands.RulesSetCommand{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.SetFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--quiet", "--endpoint", server.URL}))
+
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+	exitCode := cmd.Run(ctx, []string{"anti-spam", "enabled", "true"})
+
+	assert.Equal(t, commands.ExitOK, exitCode, "the setting was applied")
+	assert.Contains(t, stderr.String(), "read-only file system", "the warning is shown even when quiet")
+}
+
 // Test_RulesSetCommand_ImplementsCLICommand verifies the command has required methods.
 func Test_RulesSetCommand_ImplementsCLICommand(t *testing.T) {
 	cmd := &commands.RulesSetCommand{}
@@ -1148,6 +1222,548 @@ func Test_RulesSetCommand_ImplementsCLICommand(t *testing.T) {
 	assert.NotNil(t, ctx, "CLIContext should be constructible")
 }
 
+// =============================================================================
+// RulesImportCommand Tests
+// =============================================================================
+
+// rulesBot serves a real rule set through the control API for import and
+// test command tests. Its stats report intents, if set.
+type rulesBot struct {
+	set     *rules.Set
+	intents *control.GatewayIntents
+}
+
+func (b *rulesBot) Stats() *control.Stats                 { return &control.Stats{Intents: b.intents} }
+func (b *rulesBot) Rules() []control.Rule                 { return b.set.Rules() }
+func (b *rulesBot) SetRule(name, key, value string) error { return b.set.SetRule(name, key, value) }
+func (b *rulesBot) ClearWarnings(guildID, userID string) (int, error) {
+	return 0, nil
+}
+func (b *rulesBot) GuildRules(guildID string) []control.Rule { return b.set.GuildRules(guildID) }
+func (b *rulesBot) SetGuildRule(guildID, name, key, value string) error {
+	return b.set.SetGuildRule(guildID, name, key, value)
+}
+func (b *rulesBot) TestRule(guildID, name, input string) (*control.RuleTestResult, error) {
+	return b.set.TestRule(guildID, name, input)
+}
+
+// newRulesServer starts a control API server backed by the default rules.
+func newRulesServer(t *testing.T) (*httptest.Server, *rules.Set) {
+	t.Helper()
+	set := rules.NewSet(rules.Defaults()...)
+	logger := zerolog.New(io.Discard)
+	server := httptest.NewServer(control.NewServer(0, &rulesBot{set: set}, logger).Handler())
+	t.Cleanup(server.Close)
+	return server, set
+}
+
+// writeFile writes content to name inside a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func Test_RulesImportCommand_Metadata(t *testing.T) {
+	cmd := commands.NewRulesImportCommand()
+
+	assert.Equal(t, "import", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot rules import")
+}
+
+func Test_RulesImportCommand_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		content    string
+		wantExit   int
+		wantStdout []string
+		wantStderr []string
+		wantValues map[string]string
+	}{
+		{
+			name:     "json file",
+			file:     "rules.json",
+			content:  `[{"name":"anti-spam","key":"threshold","value":"9"},{"name":"anti-spam","key":"enabled","value":"true"}]`,
+			wantExit: commands.ExitOK,
+			wantStdout: []string{
+				"ok    anti-spam.threshold = 9",
+				"Imported 2 rule setting(s), 0 failed",
+			},
+			wantValues: map[string]string{"anti-spam.threshold": "9", "anti-spam.enabled": "true"},
+		},
+		{
+			name:       "yaml file",
+			file:       "rules.yaml",
+			content:    "- name: link-filter\n  key: action\n  value: warn\n",
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"ok    link-filter.action = warn"},
+			wantValues: map[string]string{"link-filter.action": "warn"},
+		},
+		{
+			name: "failures are reported and the rest applied",
+			file: "rules.json",
+			content: `[{"name":"bogus","key":"k","value":"v"},` +
+				`{"name":"anti-spam","key":"threshold","value":"-1"},` +
+				`{"name":"anti-spam","key":"window","value":"30s"}]`,
+			wantExit:   commands.ExitAPIError,
+			wantStdout: []string{"ok    anti-spam.window = 30s", "Imported 1 rule setting(s), 2 failed"},
+			wantStderr: []string{"FAIL  bogus.k = v", "FAIL  anti-spam.threshold = -1"},
+			wantValues: map[string]string{"anti-spam.window": "30s", "anti-spam.threshold": "5"},
+		},
+		{
+			name:       "malformed file",
+			file:       "rules.json",
+			content:    `{`,
+			wantExit:   commands.ExitError,
+			wantStderr: []string{"Failed to read rules file"},
+		},
+		{
+			name:       "empty file",
+			file:       "rules.json",
+			content:    `[]`,
+			wantExit:   commands.ExitError,
+			wantStderr: []string{"No rules found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, set := newRulesServer(t)
+			path := writeFile(t, tt.file, tt.content)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := commands.NewRulesImportCommand().Run(ctx, []string{path})
+
+			assert.Equal(t, tt.wantExit, exitCode, "stderr: %s", stderr.String())
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr.String(), want)
+			}
+			for dotted, want := range tt.wantValues {
+				name, key, _ := strings.Cut(dotted, ".")
+				got, _ := set.Get(name, key)
+				assert.Equal(t, want, got, dotted)
+			}
+		})
+	}
+}
+
+// Test_RulesMutations_Quiet verifies --quiet and -q leave stdout empty on
+// success while the exit code and stderr still report failures.
+func Test_RulesMutations_Quiet(t *testing.T) {
+	type mutatingCommand interface {
+		SetFlags(fs *flag.FlagSet)
+		Run(ctx *commands.CLIContext, args []string) int
+	}
+
+	tests := []struct {
+		name       string
+		cmd        func(path string) (mutatingCommand, []string)
+		content    string
+		wantExit   int
+		wantStderr string
+	}{
+		{
+			name: "rules set --quiet",
+			cmd: func(string) (mutatingCommand, []string) {
+				return commands.NewRulesSetCommand(), []string{"--quiet", "anti-spam", "threshold", "9"}
+			},
+			wantExit: commands.ExitOK,
+		},
+		{
+			name: "rules set -q failure",
+			cmd: func(string) (mutatingCommand, []string) {
+				return commands.NewRulesSetCommand(), []string{"-q", "bogus", "k", "v"}
+			},
+			wantExit:   commands.ExitAPIError,
+			wantStderr: "Failed to set rule",
+		},
+		{
+			name: "rules import --quiet",
+			cmd: func(path string) (mutatingCommand, []string) {
+				return commands.NewRulesImportCommand(), []string{"--quiet", path}
+			},
+			content:  `[{"name":"anti-spam","key":"threshold","value":"9"}]`,
+			wantExit: commands.ExitOK,
+		},
+		{
+			name: "rules import -q with failures",
+			cmd: func(path string) (mutatingCommand, []string) {
+				return commands.NewRulesImportCommand(), []string{"-q", path}
+			},
+			content:    `[{"name":"bogus","key":"k","value":"v"},{"name":"anti-spam","key":"threshold","value":"9"}]`,
+			wantExit:   commands.ExitAPIError,
+			wantStderr: "FAIL  bogus.k = v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newRulesServer(t)
+			path := writeFile(t, "rules.json", tt.content)
+
+			cmd, args := tt.cmd(path)
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(args))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, "stderr: %s", stderr.String())
+			assert.Empty(t, stdout.String())
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func Test_RulesImportCommand_Run_MissingFile(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr}
+
+	exitCode := commands.NewRulesImportCommand().Run(ctx, nil)
+
+	assert.Equal(t, commands.ExitUsage, exitCode)
+	assert.Contains(t, stderr.String(), "Missing required arguments")
+}
+
+func Test_RulesImportCommand_Run_ConnectionError(t *testing.T) {
+	path := writeFile(t, "rules.json", `[{"name":"anti-spam","key":"threshold","value":"9"}]`)
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://127.0.0.1:59996"}
+
+	exitCode := commands.NewRulesImportCommand().Run(ctx, []string{path})
+
+	assert.Equal(t, commands.ExitConnectionError, exitCode)
+	assert.Contains(t, stderr.String(), "Cannot connect")
+}
+
+// =============================================================================
+// RulesExportCommand Tests
+// =============================================================================
+
+func Test_RulesExportCommand_Metadata(t *testing.T) {
+	cmd := commands.NewRulesExportCommand()
+
+	assert.Equal(t, "export", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot rules export")
+}
+
+func Test_RulesExportCommand_Run_Stdout(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    []string
+		wantExit int
+		decode   func(t *testing.T, data []byte) []map[string]any
+	}{
+		{
+			name:     "json by default",
+			wantExit: commands.ExitOK,
+			decode: func(t *testing.T, data []byte) []map[string]any {
+				var out []map[string]any
+				require.NoError(t, json.Unmarshal(data, &out))
+				return out
+			},
+		},
+		{
+			name:     "yaml with format flag",
+			flags:    []string{"--format", "yaml"},
+			wantExit: commands.ExitOK,
+			decode: func(t *testing.T, data []byte) []map[string]any {
+				var out []map[string]any
+				require.NoError(t, yaml.Unmarshal(data, &out))
+				return out
+			},
+		},
+		{
+			name:     "unknown format",
+			flags:    []string{"--format", "toml"},
+			wantExit: commands.ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, set := newRulesServer(t)
+
+			cmd := commands.NewRulesExportCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(tt.flags))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			require.Equal(t, tt.wantExit, exitCode, "stderr: %s", stderr.String())
+			if tt.decode == nil {
+				return
+			}
+			out := tt.decode(t, stdout.Bytes())
+			require.Len(t, out, len(set.Rules()))
+			for _, field := range []string{"name", "description", "enabled", "key", "value"} {
+				assert.Contains(t, out[0], field)
+			}
+		})
+	}
+}
+
+func Test_RulesExportCommand_Run_WritesFile(t *testing.T) {
+	server, set := newRulesServer(t)
+	path := filepath.Join(t.TempDir(), "rules.yml")
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+	exitCode := commands.NewRulesExportCommand().Run(ctx, []string{path})
+
+	require.Equal(t, commands.ExitOK, exitCode, "stderr: %s", stderr.String())
+	assert.Contains(t, stdout.String(), "Exported")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var out []map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &out), ".yml files should default to YAML")
+	assert.Len(t, out, len(set.Rules()))
+}
+
+func Test_RulesExportCommand_Run_ConnectionError(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://127.0.0.1:59996"}
+
+	exitCode := commands.NewRulesExportCommand().Run(ctx, nil)
+
+	assert.Equal(t, commands.ExitConnectionError, exitCode)
+	assert.Contains(t, stderr.String(), "Cannot connect")
+}
+
+// Test_RulesExportImport_RoundTrip verifies that importing an export into a
+// fresh bot reproduces the exported rules exactly.
+func Test_RulesExportImport_RoundTrip(t *testing.T) {
+	for _, file := range []string{"rules.json", "rules.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			source, sourceSet := newRulesServer(t)
+			require.NoError(t, sourceSet.SetRule("anti-spam", "enabled", "true"))
+			require.NoError(t, sourceSet.SetRule("anti-spam", "threshold", "9"))
+			require.NoError(t, sourceSet.SetRule("word-filter", "words", "foo,bar"))
+			require.NoError(t, sourceSet.SetRule("link-filter", "action", "warn"))
+
+			path := filepath.Join(t.TempDir(), file)
+			stderr := &bytes.Buffer{}
+			exportCtx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: source.URL}
+			require.Equal(t, commands.ExitOK, commands.NewRulesExportCommand().Run(exportCtx, []string{path}), stderr.String())
+
+			target, targetSet := newRulesServer(t)
+			require.NotEqual(t, sourceSet.Rules(), targetSet.Rules())
+
+			importCtx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: target.URL}
+			require.Equal(t, commands.ExitOK, commands.NewRulesImportCommand().Run(importCtx, []string{path}), stderr.String())
+
+			assert.Equal(t, sourceSet.Rules(), targetSet.Rules())
+		})
+	}
+}
+
+// =============================================================================
+// Guild Scope Tests
+// =============================================================================
+
+// Test_RulesCommands_Guild verifies that rules set --guild overrides a setting
+// for one guild only, and rules list --guild shows the override.
+func Test_RulesCommands_Guild(t *testing.T) {
+	server, set := newRulesServer(t)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+	setCmd := commands.NewRulesSetCommand()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	setCmd.SetFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--guild", "guild-1", "anti-spam", "threshold", "3"}))
+
+	require.Equal(t, commands.ExitOK, setCmd.Run(ctx, fs.Args()), stderr.String())
+	assert.Contains(t, stdout.String(), "anti-spam.threshold = 3 (guild guild-1)")
+
+	value, _ := set.GuildValue("guild-1", "anti-spam", "threshold")
+	assert.Equal(t, "3", value)
+	global, _ := set.Get("anti-spam", "threshold")
+	assert.NotEqual(t, "3", global, "a guild override must not change the global setting")
+
+	stdout.Reset()
+	listCmd := commands.NewRulesListCommand()
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	listCmd.SetFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--guild", "guild-1", "--json"}))
+
+	require.Equal(t, commands.ExitOK, listCmd.Run(ctx, fs.Args()), stderr.String())
+
+	var got []control.Rule
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+	var found bool
+	for _, r := range got {
+		if r.Name == "anti-spam" && r.Key == "threshold" {
+			found = true
+			assert.Equal(t, "3", r.Value)
+			assert.Equal(t, "guild-1", r.Guild)
+		} else {
+			assert.Empty(t, r.Guild, "%s.%s is not overridden", r.Name, r.Key)
+		}
+	}
+	assert.True(t, found, "anti-spam.threshold should be listed")
+}
+
+// Test_RulesImportCommand_Guild verifies that a file entry's guild field
+// scopes its setting, including the shorthand enabled field.
+func Test_RulesImportCommand_Guild(t *testing.T) {
+	server, set := newRulesServer(t)
+	path := writeFile(t, "rules.yaml", "- guild: guild-1\n  name: word-filter\n  enabled: true\n  key: words\n  value: foo\n")
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+	require.Equal(t, commands.ExitOK, commands.NewRulesImportCommand().Run(ctx, []string{path}), stderr.String())
+
+	assert.Contains(t, stdout.String(), "ok    word-filter.words = foo (guild guild-1)")
+	assert.True(t, set.GuildEnabled("guild-1", "word-filter"))
+	assert.False(t, set.Enabled("word-filter"))
+}
+
+// =============================================================================
+// RulesTestCommand Tests
+// =============================================================================
+
+func Test_RulesTestCommand_Metadata(t *testing.T) {
+	cmd := commands.NewRulesTestCommand()
+
+	assert.Equal(t, "test", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot rules test")
+}
+
+func Test_RulesTestCommand_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		flags      []string
+		args       []string
+		wantExit   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "match on a disabled rule",
+			args:       []string{"word-filter", "--input", "well darn it"},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{`word-filter would match "darn" (action: delete)`, "word-filter is disabled"},
+		},
+		{
+			name:       "flags before the rule name",
+			flags:      []string{"--input", "hello"},
+			args:       []string{"word-filter"},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"word-filter would not match the message"},
+		},
+		{
+			name:       "guild overrides",
+			args:       []string{"word-filter", "--guild", "guild-1", "--input", "oh heck"},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{`word-filter would match "heck" (action: warn)`},
+		},
+		{
+			name:       "json output",
+			args:       []string{"word-filter", "--input", "darn", "--json"},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{`"matched": true`, `"match": "darn"`},
+		},
+		{
+			name:       "missing rule name",
+			wantExit:   commands.ExitUsage,
+			wantStderr: []string{"Missing required rule name"},
+		},
+		{
+			name:       "missing input",
+			args:       []string{"word-filter"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: []string{"--input is required"},
+		},
+		{
+			name:       "unexpected arguments",
+			args:       []string{"word-filter", "--input", "darn", "extra"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: []string{"Unexpected arguments"},
+		},
+		{
+			name:       "unknown rule",
+			args:       []string{"nonexistent", "--input", "darn"},
+			wantExit:   commands.ExitAPIError,
+			wantStderr: []string{`No rule named "nonexistent"`},
+		},
+		{
+			name:       "rule without content matching",
+			args:       []string{"welcome", "--input", "darn"},
+			wantExit:   commands.ExitAPIError,
+			wantStderr: []string{"only word-filter and link-filter can be tested"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, set := newRulesServer(t)
+			require.NoError(t, set.SetRule("word-filter", "words", "darn"))
+			require.NoError(t, set.SetGuildRule("guild-1", "word-filter", "words", "heck"))
+			require.NoError(t, set.SetGuildRule("guild-1", "word-filter", "action", "warn"))
+			require.NoError(t, set.SetGuildRule("guild-1", "word-filter", "enabled", "true"))
+
+			cmd := commands.NewRulesTestCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(tt.flags))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, tt.args)
+
+			assert.Equal(t, tt.wantExit, exitCode, stderr.String())
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr.String(), want)
+			}
+			assert.False(t, set.Enabled("word-filter"), "testing a rule must not enable it")
+		})
+	}
+}
+
+func Test_RulesTestCommand_Run_ConnectionError(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://127.0.0.1:59996"}
+
+	exitCode := commands.NewRulesTestCommand().Run(ctx, []string{"word-filter", "--input", "darn"})
+
+	assert.Equal(t, commands.ExitConnectionError, exitCode)
+	assert.Contains(t, stderr.String(), "Cannot connect")
+}
+
 // =============================================================================
 // Benchmark Tests
 // =============================================================================
@@ -0,0 +1,151 @@
+// Package commands provides CLI command implementations for JamesBot.
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"jamesbot/internal/control"
+)
+
+// RulesTestCommand implements the rules test command for trying a content
+// rule against a sample message without the bot acting on it.
+type RulesTestCommand struct {
+	input         string
+	guild         string
+	jsonOutput    bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewRulesTestCommand creates a new RulesTestCommand instance.
+func NewRulesTestCommand() *RulesTestCommand {
+	return &RulesTestCommand{}
+}
+
+// Name returns the name of the command.
+func (c *RulesTestCommand) Name() string {
+	return "test"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *RulesTestCommand) Synopsis() string {
+	return "Test a content rule against a sample message"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *RulesTestCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot rules test <rule-name> --input <message> [options]\n\n")
+	sb.WriteString("Check whether a content rule would match a message and what it would do,\n")
+	sb.WriteString("using the rule's current settings. Nothing is posted, deleted, or recorded,\n")
+	sb.WriteString("and the rule is tested even while it is disabled.\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  <rule-name>  Content rule to test (word-filter or link-filter)\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  --input <message>   Sample message text (required)\n")
+	sb.WriteString("  --guild <id>        Test the rule as configured in a guild, including its overrides\n")
+	sb.WriteString("  --json              Output the result as JSON\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n\n")
+	sb.WriteString("Examples:\n")
+	sb.WriteString("  jamesbot rules test word-filter --input \"well darn it\"\n")
+	sb.WriteString("  jamesbot rules test --guild 123456789012345678 link-filter --input \"see https://example.com\"\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the rules test command.
+func (c *RulesTestCommand) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.input, "input", "", "Sample message text")
+	fs.StringVar(&c.guild, "guild", "", "Test the rule as configured in a guild")
+	fs.BoolVar(&c.jsonOutput, "json", false, "Output the result as JSON")
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the rules test command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *RulesTestCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	if len(args) < 1 {
+		fmt.Fprintf(stderr, "Error: Missing required rule name\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+	ruleName := args[0]
+
+	// Flags may also follow the rule name, as in "rules test word-filter --input ..."
+	if len(args) > 1 {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.StringVar(&c.input, "input", c.input, "")
+		fs.StringVar(&c.guild, "guild", c.guild, "")
+		fs.BoolVar(&c.jsonOutput, "json", c.jsonOutput, "")
+		fs.Var(&c.endpoint.url, "endpoint", "")
+		fs.StringVar(&c.endpoint.caFile, "ca-file", c.endpoint.caFile, "")
+		fs.BoolVar(&c.endpoint.insecure, "insecure", c.endpoint.insecure, "")
+		fs.BoolVar(&c.noHealthCheck, "no-health-check", c.noHealthCheck, "")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 0 {
+			fmt.Fprintf(stderr, "Error: Unexpected arguments after rule name: %s\n\n", strings.Join(args[1:], " "))
+			fmt.Fprintf(stderr, "%s", c.Usage())
+			return ExitUsage
+		}
+	}
+
+	if c.input == "" {
+		fmt.Fprintf(stderr, "Error: --input is required\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	result, err := client.TestRule(c.guild, ruleName, c.input)
+	if err != nil {
+		switch {
+		case errors.Is(err, control.ErrRuleNotFound):
+			fmt.Fprintf(stderr, "Error: No rule named %q; see 'jamesbot rules list'\n", ruleName)
+			return ExitAPIError
+		case errors.Is(err, control.ErrRuleNotTestable):
+			fmt.Fprintf(stderr, "Error: Rule %q does not match message content; only word-filter and link-filter can be tested\n", ruleName)
+			return ExitAPIError
+		}
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		fmt.Fprintf(stderr, "Error: Failed to test rule: %v\n", err)
+		return ExitAPIError
+	}
+
+	if c.jsonOutput {
+		if err := writeJSON(stdout, result); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to write result: %v\n", err)
+			return ExitError
+		}
+		return ExitOK
+	}
+
+	if result.Matched {
+		fmt.Fprintf(stdout, "%s would match %q (action: %s)\n", result.Name, result.Match, result.Action)
+	} else {
+		fmt.Fprintf(stdout, "%s would not match the message\n", result.Name)
+	}
+	if !result.Enabled {
+		fmt.Fprintf(stdout, "Note: %s is disabled, so it is not currently enforced\n", result.Name)
+	}
+	return ExitOK
+}
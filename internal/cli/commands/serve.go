@@ -16,6 +16,7 @@ import (
 	"jamesbot/internal/command"
 	"jamesbot/internal/config"
 	"jamesbot/internal/control"
+	"jamesbot/internal/crash"
 	"jamesbot/internal/middleware"
 	"jamesbot/internal/plugin"
 	"jamesbot/internal/plugin/plugins/jamesprial"
@@ -34,8 +35,11 @@ type CLIContext struct {
 
 // ServeCommand implements the serve command for starting the Discord bot.
 type ServeCommand struct {
-	configPath string
-	apiPort    int
+	configPath stringValue
+	apiPort    intValue
+	check      bool
+	guild      string
+	global     bool
 }
 
 // NewServeCommand creates a new ServeCommand instance.
@@ -59,17 +63,47 @@ func (c *ServeCommand) Usage() string {
 	sb.WriteString("Usage: jamesbot serve [options]\n\n")
 	sb.WriteString("Start the Discord bot server and connect to Discord.\n\n")
 	sb.WriteString("Options:\n")
-	sb.WriteString("  -c, --config <path>  Path to config file (default: config/config.yaml)\n")
-	sb.WriteString("  --api-port <port>    Control API port (default: 8765)\n")
-	sb.WriteString("  -h, --help           Show this help message\n")
+	sb.WriteString("  -c, --config <path>  Path to config file\n")
+	sb.WriteString("  --api-port <port>    Control API port (default: 8765; ignored when control.enabled is false)\n")
+	sb.WriteString("  --check              Validate the config, print what serve would do, and exit\n")
+	sb.WriteString("                       without connecting to Discord\n")
+	sb.WriteString("  --guild <id>         Register slash commands to this guild for this run,\n")
+	sb.WriteString("                       overriding discord.guild_id and discord.global\n")
+	sb.WriteString("  --global             Register slash commands globally for this run, even if\n")
+	sb.WriteString("                       discord.guild_id is set\n")
+	sb.WriteString("  -h, --help           Show this help message\n\n")
+	sb.WriteString("Config file search order (first existing file wins):\n")
+	sb.WriteString("  1. --config flag\n")
+	sb.WriteString("  2. $JAMESBOT_CONFIG\n")
+	sb.WriteString("  3. ./config/config.yaml\n")
+	sb.WriteString("  4. $XDG_CONFIG_HOME/jamesbot/config.yaml\n")
+	sb.WriteString("  5. /etc/jamesbot/config.yaml\n")
+	sb.WriteString("If none exists, configuration is read from environment variables only.\n")
 	return sb.String()
 }
 
 // SetFlags configures the command-line flags for the serve command.
 func (c *ServeCommand) SetFlags(fs *flag.FlagSet) {
-	fs.StringVar(&c.configPath, "c", "config/config.yaml", "Path to config file")
-	fs.StringVar(&c.configPath, "config", "config/config.yaml", "Path to config file")
-	fs.IntVar(&c.apiPort, "api-port", 8765, "Control API port")
+	c.configPath = stringValue{value: "config/config.yaml"}
+	fs.Var(&c.configPath, "c", "Path to config file")
+	fs.Var(&c.configPath, "config", "Path to config file")
+	c.apiPort = intValue{value: 8765}
+	fs.Var(&c.apiPort, "api-port", "Control API port")
+	fs.BoolVar(&c.check, "check", false, "Validate the config and print what serve would do, then exit")
+	fs.StringVar(&c.guild, "guild", "", "Register slash commands to this guild for this run")
+	fs.BoolVar(&c.global, "global", false, "Register slash commands globally for this run")
+}
+
+// applyScope applies the --guild and --global flags, which take precedence
+// over the config file and environment, to the Discord config.
+func (c *ServeCommand) applyScope(cfg *config.Config) {
+	switch {
+	case c.global:
+		cfg.Discord.Global = true
+	case c.guild != "":
+		cfg.Discord.GuildID = c.guild
+		cfg.Discord.Global = false
+	}
 }
 
 // Run executes the serve command.
@@ -81,16 +115,25 @@ func (c *ServeCommand) Run(ctx *CLIContext, args []string) int {
 		stderr = os.Stderr
 	}
 
-	// Load configuration
-	cfg, err := config.Load(c.configPath)
+	c.guild = strings.TrimSpace(c.guild)
+	if c.guild != "" && c.global {
+		fmt.Fprintf(stderr, "Error: --guild and --global cannot be used together\n")
+		return ExitUsage
+	}
+
+	// Discover and load configuration
+	resolved, err := resolveConfig(&c.configPath, c.applyScope)
 	if err != nil {
-		// If config file not found, try loading from environment variables only
-		cfg, err = config.Load("")
-		if err != nil {
-			fmt.Fprintf(stderr, "Error: Failed to load configuration: %v\n", err)
-			return 1
+		fmt.Fprintf(stderr, "Error: Failed to load configuration: %v\n", err)
+		if c.check {
+			return ExitConfigError
 		}
+		return ExitError
+	}
+	if c.check {
+		return c.runCheck(ctx.Stdout, stderr, resolved)
 	}
+	cfg := resolved.cfg
 
 	// Create logger
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
@@ -105,107 +148,299 @@ func (c *ServeCommand) Run(ctx *CLIContext, args []string) int {
 	}
 	logger = logger.Level(level)
 
+	// Report panics that escape everything else, with every goroutine's stack
+	reporter := crash.NewReporter(logger, resolved.path, cfg.Logging.CrashFile)
+	if err := reporter.Install(); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("path", cfg.Logging.CrashFile).
+			Msg("crash reports will only be logged")
+	}
+	defer reporter.Guard()
+
+	switch {
+	case resolved.fileErr != nil:
+		logger.Warn().
+			Err(resolved.fileErr).
+			Str("path", resolved.path).
+			Msg("failed to load config file, using environment variables only")
+	case resolved.path != "":
+		logger.Info().Str("path", resolved.path).Msg("using config file")
+	default:
+		logger.Info().
+			Strs("searched", resolved.searched).
+			Msg("no config file found, using environment variables only")
+	}
+	switch {
+	case c.global:
+		logger.Info().Msg("registering slash commands globally (--global)")
+	case c.guild != "":
+		logger.Info().Str("guild_id", c.guild).Msg("registering slash commands to guild (--guild)")
+	}
+
+	// Log or audit command executions
+	commandLog, err := CommandLogging(cfg.Logging, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to open audit log")
+		return ExitError
+	}
+	defer func() {
+		if err := commandLog.Close(); err != nil {
+			logger.Error().Err(err).Msg("error closing audit log")
+		}
+	}()
+
 	// Create bot with middleware
 	b, err := bot.New(cfg, logger,
-		bot.WithMiddleware(
-			middleware.Recovery(logger),
-			middleware.Logging(logger),
-		),
+		bot.WithMiddleware(middleware.Recovery(logger), commandLog.Middleware),
 	)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create bot")
-		return 1
+		return ExitError
 	}
 
 	// Register core commands
-	if err := c.registerCommands(b, logger); err != nil {
+	confirmer, err := newConfirmer(b, cfg.Commands, commandLog.Confirmations)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to register commands")
+		return ExitError
+	}
+	knownCommands, err := registerCommands(b, cfg.Commands, confirmer, logger)
+	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to register commands")
-		return 1
+		return ExitError
 	}
 
-	// Load plugins
-	pluginLoader := c.loadPlugins(logger)
+	// Load plugins and register their commands
+	pluginLoader := loadPlugins(logger)
 	defer pluginLoader.ShutdownAll()
+	knownCommands = append(knownCommands, registerPluginCommands(b, pluginLoader, cfg.Commands, logger)...)
 
-	// Register plugin commands
-	for _, cmd := range pluginLoader.Commands() {
-		if err := b.RegisterCommand(cmd); err != nil {
-			logger.Warn().
-				Str("command", cmd.Name()).
-				Err(err).
-				Msg("failed to register plugin command")
-		} else {
-			logger.Debug().
-				Str("command", cmd.Name()).
-				Msg("registered plugin command")
-		}
+	// Let the control API rebuild the commands without a restart
+	b.SetCommandLoader(commandLoader(b, cfg.Commands, confirmer, pluginLoader))
+
+	// Warn about configured names that match no command (likely typos)
+	for _, name := range cfg.Commands.UnknownNames(knownCommands) {
+		logger.Warn().
+			Str("command", name).
+			Msg("commands config references an unknown command")
 	}
 
 	// Start bot
 	botCtx := context.Background()
 	if err := b.Start(botCtx); err != nil {
 		logger.Fatal().Err(err).Msg("failed to start bot")
-		return 1
+		return ExitError
 	}
 
 	// Start control API server
-	controlServer := control.NewServer(c.apiPort, b, logger)
-	if err := controlServer.Start(); err != nil {
+	controlServer, err := c.StartControlServer(cfg.Control, b, logger)
+	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to start control API server")
-		return 1
+		return ExitError
+	}
+	if controlServer != nil {
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := controlServer.Stop(shutdownCtx); err != nil {
+				logger.Error().Err(err).Msg("error stopping control API server")
+			}
+		}()
 	}
-	defer func() {
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := controlServer.Stop(shutdownCtx); err != nil {
-			logger.Error().Err(err).Msg("error stopping control API server")
-		}
-	}()
 
 	// Wait for interrupt signal
 	logger.Info().Msg("bot is running. Press CTRL-C to exit.")
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
-	<-stop
+	sig := <-stop
 	signal.Stop(stop) // Clean up signal handler
 
-	// Graceful shutdown
-	logger.Info().Msg("shutting down...")
-	shutdownCtx, cancel := context.WithTimeout(botCtx, cfg.Shutdown.Timeout)
+	// Graceful shutdown, allowing the time configured for the signal received
+	timeout := cfg.Shutdown.TimeoutFor(sig)
+	logger.Info().
+		Str("signal", sig.String()).
+		Dur("timeout", timeout).
+		Msg("shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(botCtx, timeout)
 	defer cancel()
 
 	if err := b.Stop(shutdownCtx); err != nil {
 		logger.Error().Err(err).Msg("error during shutdown")
-		return 1
+		return ExitError
 	}
 
 	logger.Info().Msg("shutdown complete")
-	return 0
+	return ExitOK
+}
+
+// StartControlServer starts the control API on the --api-port port for b, as
+// Run does once the bot is up, serving HTTPS if cfg names a certificate and
+// key and allowing moderation requests that carry cfg's auth token, if set.
+// If cfg disables the control API, it binds nothing, logs that the API is off,
+// warns if --api-port was passed anyway, and returns a nil server.
+func (c *ServeCommand) StartControlServer(cfg config.ControlConfig, b control.BotInfo, logger zerolog.Logger) (*control.Server, error) {
+	if !cfg.Enabled {
+		if c.apiPort.set {
+			logger.Warn().
+				Int("port", c.apiPort.value).
+				Msg("--api-port is ignored because the control API is disabled")
+		}
+		logger.Info().Msg("control API is disabled by config; CLI commands cannot reach this bot")
+		return nil, nil
+	}
+
+	var opts []control.ServerOption
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		opts = append(opts, control.WithTLS(cfg.TLSCertFile, cfg.TLSKeyFile))
+	}
+	if cfg.AuthToken != "" {
+		opts = append(opts, control.WithAuthToken(cfg.AuthToken))
+	}
+
+	server := control.NewServer(c.apiPort.value, b, logger, opts...)
+	if err := server.Start(); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// CommandLog records command executions as Run configures it.
+type CommandLog struct {
+	// Middleware logs or audits each execution.
+	Middleware middleware.Middleware
+
+	// Confirmations audits the answers to confirmation prompts, or is nil
+	// when auditing is disabled.
+	Confirmations command.ConfirmationObserver
+
+	// Close closes the audit file, if one was opened, once the bot has
+	// stopped.
+	Close func() error
+}
+
+// CommandLogging returns the CommandLog that records command executions as
+// Run configures it from cfg.
+//
+// Executions are logged to logger, with successes sampled, unless auditing is
+// enabled. Audit records then go to cfg.AuditFile, alongside the usual log
+// lines, or replace those lines in logger when no file is set, so no
+// execution is written to the same output twice. Answers to confirmation
+// prompts are audited along with the executions.
+func CommandLogging(cfg config.LoggingConfig, logger zerolog.Logger) (*CommandLog, error) {
+	noClose := func() error { return nil }
+	opts := []middleware.LoggingOption{
+		middleware.WithSuccessSampling(uint32(max(cfg.SampleSuccesses, 0))),
+		middleware.WithAlwaysLogged(command.ModerationCommands...),
+	}
+	if cfg.Options {
+		opts = append(opts, middleware.WithCommandOptions(cfg.OptionsRedact...))
+	}
+	logging := middleware.Logging(logger, opts...)
+	if !cfg.Audit {
+		return &CommandLog{Middleware: logging, Close: noClose}, nil
+	}
+
+	redact := middleware.WithRedactedOptions(cfg.AuditRedact...)
+	if cfg.AuditFile == "" {
+		return &CommandLog{
+			Middleware:    middleware.Audit(logger, redact),
+			Confirmations: middleware.AuditConfirmations(logger, redact),
+			Close:         noClose,
+		}, nil
+	}
+
+	// Audit records may name members and hold their messages
+	file, err := os.OpenFile(cfg.AuditFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	auditLogger := zerolog.New(file).With().Timestamp().Logger()
+	return &CommandLog{
+		Middleware:    middleware.Chain(logging, middleware.Audit(auditLogger, redact)),
+		Confirmations: middleware.AuditConfirmations(auditLogger, redact),
+		Close:         file.Close,
+	}, nil
+}
+
+// newConfirmer returns the confirmer destructive commands ask for
+// confirmation with, registering its buttons with b, or nil if cfg opts out
+// of confirmations. observer, if not nil, is told how each prompt is
+// answered.
+func newConfirmer(b *bot.Bot, cfg config.CommandsConfig, observer command.ConfirmationObserver) (*command.Confirmer, error) {
+	if !cfg.ConfirmDestructive {
+		return nil, nil
+	}
+	var opts []command.ConfirmerOption
+	if observer != nil {
+		opts = append(opts, command.WithConfirmationObserver(observer))
+	}
+	confirmer := command.NewConfirmer(command.DefaultConfirmTimeout, opts...)
+	if err := b.RegisterComponent(command.ConfirmComponentKey, confirmer.HandleComponent); err != nil {
+		return nil, fmt.Errorf("failed to register confirmation buttons: %w", err)
+	}
+	return confirmer, nil
 }
 
-// registerCommands registers all bot commands with the bot instance.
-func (c *ServeCommand) registerCommands(b *bot.Bot, logger zerolog.Logger) error {
-	commands := []command.Command{
+// coreCommands returns the core bot commands, configured by cfg, whether
+// enabled or not. Destructive commands ask for confirmation with confirmer,
+// unless it is nil.
+func coreCommands(b *bot.Bot, cfg config.CommandsConfig, confirmer *command.Confirmer) []command.Command {
+	return []command.Command{
 		&command.PingCommand{},
 		&command.EchoCommand{},
-		&command.KickCommand{},
-		&command.BanCommand{},
-		&command.MuteCommand{},
-		&command.WarnCommand{},
+		&command.KickCommand{NotifyTarget: cfg.NotifyTargets},
+		&command.BanCommand{NotifyTarget: cfg.NotifyTargets, Confirmer: confirmer},
+		&command.MuteCommand{NotifyTarget: cfg.NotifyTargets},
+		&command.WarnCommand{Warnings: b.Warnings(), Rules: b.RuleSet()},
+		&command.ClearWarningsCommand{Warnings: b.Warnings()},
+		&command.SnipeCommand{Snipes: b.Snipes()},
+		&command.PermsCommand{Commands: b.Commands},
+		&command.ExportRulesCommand{Rules: b.RuleSet()},
 	}
+}
+
+// registerCommands registers the core bot commands enabled by cfg, asking
+// for confirmation with confirmer as coreCommands does.
+// Disabled commands are skipped entirely so they are never sent to Discord.
+// It returns the names of all core commands, enabled or not, so callers can
+// validate the commands config against them.
+func registerCommands(b *bot.Bot, cfg config.CommandsConfig, confirmer *command.Confirmer, logger zerolog.Logger) ([]string, error) {
+	commands := coreCommands(b, cfg, confirmer)
 
+	names := make([]string, 0, len(commands))
 	for _, cmd := range commands {
+		names = append(names, cmd.Name())
+		if !cfg.IsEnabled(cmd.Name()) {
+			logger.Info().Str("command", cmd.Name()).Msg("command disabled by config")
+			continue
+		}
 		if err := b.RegisterCommand(cmd); err != nil {
-			return fmt.Errorf("failed to register %s command: %w", cmd.Name(), err)
+			return nil, fmt.Errorf("failed to register %s command: %w", cmd.Name(), err)
 		}
 		logger.Debug().Str("command", cmd.Name()).Msg("registered command")
 	}
 
-	return nil
+	return names, nil
+}
+
+// commandLoader returns how the bot rebuilds its commands when reloaded: the
+// core commands and those of loaded plugins, from their current definitions,
+// that cfg enables.
+func commandLoader(b *bot.Bot, cfg config.CommandsConfig, confirmer *command.Confirmer, plugins *plugin.Loader) func() []command.Command {
+	return func() []command.Command {
+		var enabled []command.Command
+		for _, cmd := range append(coreCommands(b, cfg, confirmer), plugins.Commands()...) {
+			if cfg.IsEnabled(cmd.Name()) {
+				enabled = append(enabled, cmd)
+			}
+		}
+		return enabled
+	}
 }
 
 // loadPlugins initializes and loads all plugins.
-func (c *ServeCommand) loadPlugins(logger zerolog.Logger) *plugin.Loader {
+func loadPlugins(logger zerolog.Logger) *plugin.Loader {
 	registry := plugin.NewRegistry(logger)
 	loader := plugin.NewLoader(registry, logger)
 
@@ -230,3 +465,30 @@ func (c *ServeCommand) loadPlugins(logger zerolog.Logger) *plugin.Loader {
 
 	return loader
 }
+
+// registerPluginCommands registers the commands of loaded plugins enabled by
+// cfg. A plugin command that fails to register is logged and skipped. It
+// returns the names of all plugin commands, enabled or not.
+func registerPluginCommands(b *bot.Bot, loader *plugin.Loader, cfg config.CommandsConfig, logger zerolog.Logger) []string {
+	var names []string
+	for _, cmd := range loader.Commands() {
+		names = append(names, cmd.Name())
+		if !cfg.IsEnabled(cmd.Name()) {
+			logger.Info().
+				Str("command", cmd.Name()).
+				Msg("plugin command disabled by config")
+			continue
+		}
+		if err := b.RegisterCommand(cmd); err != nil {
+			logger.Warn().
+				Str("command", cmd.Name()).
+				Err(err).
+				Msg("failed to register plugin command")
+		} else {
+			logger.Debug().
+				Str("command", cmd.Name()).
+				Msg("registered plugin command")
+		}
+	}
+	return names
+}
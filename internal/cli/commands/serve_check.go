@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"jamesbot/internal/bot"
+
+	"github.com/rs/zerolog"
+)
+
+// runCheck validates the resolved configuration and prints what Run would do
+// with it: the commands it would register, where it would sync them, and the
+// control API address. It builds the bot and its command registry but never
+// connects to Discord, so the token only has to be present, not valid.
+//
+// It returns ExitConfigError if the config file cannot be loaded or the
+// control API's TLS files cannot be read, and ExitError if a command fails to
+// register.
+func (c *ServeCommand) runCheck(stdout, stderr io.Writer, resolved *resolvedConfig) int {
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	cfg := resolved.cfg
+
+	// Unlike Run, a config file that fails to load is an error rather than a
+	// fallback to environment variables, since it would not be what runs.
+	if resolved.fileErr != nil {
+		fmt.Fprintf(stderr, "Error: Failed to load %s: %v\n", resolved.path, resolved.fileErr)
+		return ExitConfigError
+	}
+
+	// Only problems are worth logging while checking.
+	logger := zerolog.New(stderr).With().Timestamp().Logger().Level(zerolog.WarnLevel)
+
+	b, err := bot.New(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create bot: %v\n", err)
+		return ExitError
+	}
+	confirmer, err := newConfirmer(b, cfg.Commands, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to register commands: %v\n", err)
+		return ExitError
+	}
+	knownCommands, err := registerCommands(b, cfg.Commands, confirmer, logger)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to register commands: %v\n", err)
+		return ExitError
+	}
+	pluginLoader := loadPlugins(logger)
+	defer pluginLoader.ShutdownAll()
+	knownCommands = append(knownCommands, registerPluginCommands(b, pluginLoader, cfg.Commands, logger)...)
+
+	if cfg.Control.Enabled && cfg.Control.TLSCertFile != "" {
+		if _, err := tls.LoadX509KeyPair(cfg.Control.TLSCertFile, cfg.Control.TLSKeyFile); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to load control API certificate: %v\n", err)
+			return ExitConfigError
+		}
+	}
+
+	if resolved.path != "" {
+		fmt.Fprintf(stdout, "Config: %s\n", resolved.path)
+	} else {
+		fmt.Fprintf(stdout, "Config: environment variables only (searched %s)\n", strings.Join(resolved.searched, ", "))
+	}
+	if _, err := zerolog.ParseLevel(cfg.Logging.Level); err != nil {
+		fmt.Fprintf(stdout, "Warning: invalid log level %q, info would be used\n", cfg.Logging.Level)
+	}
+
+	registered := make(map[string]bool)
+	var names []string
+	for _, cmd := range b.Commands() {
+		registered[cmd.Name()] = true
+		names = append(names, cmd.Name())
+	}
+	sort.Strings(names)
+	fmt.Fprintf(stdout, "Commands (%d): %s\n", len(names), strings.Join(names, ", "))
+
+	var skipped []string
+	for _, name := range knownCommands {
+		if !registered[name] {
+			skipped = append(skipped, name)
+		}
+	}
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		fmt.Fprintf(stdout, "Not registered: %s\n", strings.Join(skipped, ", "))
+	}
+	for _, name := range cfg.Commands.UnknownNames(knownCommands) {
+		fmt.Fprintf(stdout, "Warning: commands config references an unknown command %q\n", name)
+	}
+
+	if cfg.Discord.GuildID != "" && !cfg.Discord.Global {
+		fmt.Fprintf(stdout, "Slash commands: synced to guild %s\n", cfg.Discord.GuildID)
+	} else {
+		fmt.Fprintf(stdout, "Slash commands: synced globally\n")
+	}
+	if cfg.Commands.Prefix != "" {
+		fmt.Fprintf(stdout, "Text commands: prefix %q\n", cfg.Commands.Prefix)
+	}
+
+	switch {
+	case !cfg.Control.Enabled:
+		fmt.Fprintf(stdout, "Control API: disabled\n")
+	case cfg.Control.TLSCertFile != "":
+		fmt.Fprintf(stdout, "Control API: https://127.0.0.1:%d\n", c.apiPort.value)
+	default:
+		fmt.Fprintf(stdout, "Control API: http://127.0.0.1:%d\n", c.apiPort.value)
+	}
+	if cfg.Control.Enabled && cfg.Control.AuthToken == "" {
+		fmt.Fprintf(stdout, "Moderation endpoints: disabled (control.auth_token is not set)\n")
+	}
+
+	fmt.Fprintf(stdout, "Configuration is valid; not connecting to Discord.\n")
+	return ExitOK
+}
@@ -2,14 +2,23 @@ package commands_test
 
 import (
 	"bytes"
+	"context"
 	"flag"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
 	"jamesbot/internal/cli/commands"
+	"jamesbot/internal/command"
+	"jamesbot/internal/config"
+	"jamesbot/internal/rules"
 
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -335,8 +344,8 @@ logging:
 			exitCode := cmd.Run(ctx, fs.Args())
 
 			if tt.wantExitNonZero {
-				assert.NotEqual(t, 0, exitCode,
-					"Run() should return non-zero exit code for missing token")
+				assert.Equal(t, commands.ExitError, exitCode,
+					"Run() should return ExitError for missing token")
 			}
 
 			if tt.wantStderrSubstr != "" {
@@ -352,6 +361,149 @@ logging:
 	}
 }
 
+// Test_ServeCommand_Run_Check verifies --check reports what serve would do
+// without connecting to Discord, and fails on an invalid config.
+func Test_ServeCommand_Run_Check(t *testing.T) {
+	t.Setenv("JAMESBOT_DISCORD_TOKEN", "")
+	t.Setenv("JAMESBOT_DISCORD_GUILD_ID", "")
+
+	tests := []struct {
+		name       string
+		config     string
+		args       []string
+		wantExit   int
+		wantStdout []string
+		wantStderr string
+	}{
+		{
+			name: "valid config prints plan",
+			config: `
+discord:
+  token: "not-a-real-token"
+  guild_id: "123456789012345678"
+`,
+			args:     []string{"--api-port", "9999"},
+			wantExit: commands.ExitOK,
+			wantStdout: []string{
+				"Commands (",
+				"ping",
+				"Slash commands: synced to guild 123456789012345678",
+				"Control API: http://127.0.0.1:9999",
+				"Moderation endpoints: disabled",
+				"Configuration is valid; not connecting to Discord.",
+			},
+		},
+		{
+			name: "global sync",
+			config: `
+discord:
+  token: "not-a-real-token"
+  global: true
+`,
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"Slash commands: synced globally"},
+		},
+		{
+			name: "--guild overrides the configured guild",
+			config: `
+discord:
+  token: "not-a-real-token"
+  guild_id: "123456789012345678"
+`,
+			args:       []string{"--guild", "876543210987654321"},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"Slash commands: synced to guild 876543210987654321"},
+		},
+		{
+			name: "--guild overrides global registration",
+			config: `
+discord:
+  token: "not-a-real-token"
+  global: true
+`,
+			args:       []string{"--guild", "876543210987654321"},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"Slash commands: synced to guild 876543210987654321"},
+		},
+		{
+			name: "--global overrides the configured guild",
+			config: `
+discord:
+  token: "not-a-real-token"
+  guild_id: "123456789012345678"
+`,
+			args:       []string{"--global"},
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"Slash commands: synced globally"},
+		},
+		{
+			name: "--guild is validated with the config",
+			config: `
+discord:
+  token: "not-a-real-token"
+  allowed_guilds: ["123456789012345678"]
+`,
+			args:       []string{"--guild", "876543210987654321"},
+			wantExit:   commands.ExitConfigError,
+			wantStderr: "allowed_guilds",
+		},
+		{
+			name: "--guild and --global together fail",
+			config: `
+discord:
+  token: "not-a-real-token"
+`,
+			args:       []string{"--guild", "876543210987654321", "--global"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "cannot be used together",
+		},
+		{
+			name: "disabled command is listed as not registered",
+			config: `
+discord:
+  token: "not-a-real-token"
+commands:
+  disabled: ["ping"]
+`,
+			wantExit:   commands.ExitOK,
+			wantStdout: []string{"Not registered: ping"},
+		},
+		{
+			name: "missing token fails",
+			config: `
+discord:
+  guild_id: "123456789012345678"
+`,
+			wantExit:   commands.ExitConfigError,
+			wantStderr: "token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0600))
+
+			cmd := &commands.ServeCommand{}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(append([]string{"--check", "-c", configPath}, tt.args...)))
+
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			exitCode := cmd.Run(&commands.CLIContext{Stdout: stdout, Stderr: stderr}, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode, "stderr: %s", stderr.String())
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, strings.ToLower(stderr.String()), tt.wantStderr)
+			}
+		})
+	}
+}
+
 // Test_ServeCommand_Run_InvalidConfigPath verifies error handling for invalid config path.
 func Test_ServeCommand_Run_InvalidConfigPath(t *testing.T) {
 	tests := []struct {
@@ -404,8 +556,8 @@ func Test_ServeCommand_Run_InvalidConfigPath(t *testing.T) {
 			exitCode := cmd.Run(ctx, fs.Args())
 
 			if tt.wantExitNonZero {
-				assert.NotEqual(t, 0, exitCode,
-					"Run() should return non-zero exit code for invalid config path: %s", tt.configPath)
+				assert.Equal(t, commands.ExitError, exitCode,
+					"Run() should return ExitError for invalid config path: %s", tt.configPath)
 			}
 		})
 	}
@@ -444,8 +596,8 @@ func Test_ServeCommand_Run_EmptyConfigPath(t *testing.T) {
 	exitCode := cmd.Run(ctx, fs.Args())
 
 	// Without a config file or environment variable, it should fail
-	assert.NotEqual(t, 0, exitCode,
-		"Run() should return non-zero exit code when no config and no env token")
+	assert.Equal(t, commands.ExitError, exitCode,
+		"Run() should return ExitError when no config and no env token")
 }
 
 // Test_ServeCommand_ImplementsCLICommand verifies the command has required methods.
@@ -472,6 +624,47 @@ func Test_ServeCommand_ImplementsCLICommand(t *testing.T) {
 	assert.NotNil(t, ctx, "CLIContext should be constructible")
 }
 
+// Test_ServeCommand_Run_GuildFlagOverridesEnv verifies --guild also takes
+// precedence over the guild set in the environment.
+func Test_ServeCommand_Run_GuildFlagOverridesEnv(t *testing.T) {
+	t.Setenv("JAMESBOT_DISCORD_TOKEN", "not-a-real-token")
+	t.Setenv("JAMESBOT_DISCORD_GUILD_ID", "123456789012345678")
+
+	cmd := &commands.ServeCommand{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("logging:\n  level: info\n"), 0o600))
+	require.NoError(t, fs.Parse([]string{"--check", "-c", configPath, "--guild", "876543210987654321"}))
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	exitCode := cmd.Run(&commands.CLIContext{Stdout: stdout, Stderr: stderr}, fs.Args())
+
+	assert.Equal(t, commands.ExitOK, exitCode, "stderr: %s", stderr.String())
+	assert.Contains(t, stdout.String(), "Slash commands: synced to guild 876543210987654321")
+}
+
+// Test_ServeCommand_Run_MissingConfigFlagFile verifies a --config file that
+// does not exist is an error rather than a reason to load another.
+func Test_ServeCommand_Run_MissingConfigFlagFile(t *testing.T) {
+	t.Setenv("JAMESBOT_DISCORD_TOKEN", "not-a-real-token")
+	t.Setenv(config.PathEnvVar, writeFile(t, "env.yaml", "discord:\n  guild_id: \"123456789012345678\"\n"))
+
+	cmd := &commands.ServeCommand{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+	configPath := filepath.Join(t.TempDir(), "missing.yaml")
+	require.NoError(t, fs.Parse([]string{"--check", "-c", configPath}))
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	exitCode := cmd.Run(&commands.CLIContext{Stdout: stdout, Stderr: stderr}, fs.Args())
+
+	assert.Equal(t, commands.ExitConfigError, exitCode)
+	assert.Contains(t, stderr.String(), configPath)
+}
+
 // Test_ServeCommand_SetFlags_AllFlagsRegistered verifies all expected flags are registered.
 func Test_ServeCommand_SetFlags_AllFlagsRegistered(t *testing.T) {
 	cmd := &commands.ServeCommand{}
@@ -481,7 +674,7 @@ func Test_ServeCommand_SetFlags_AllFlagsRegistered(t *testing.T) {
 	cmd.SetFlags(fs)
 
 	// Verify expected flags exist
-	expectedFlags := []string{"config", "c", "api-port"}
+	expectedFlags := []string{"config", "c", "api-port", "check", "guild", "global"}
 	for _, flagName := range expectedFlags {
 		f := fs.Lookup(flagName)
 		assert.NotNil(t, f, "Flag %q should be registered", flagName)
@@ -542,8 +735,9 @@ func Test_ServeCommand_Run_MethodSignature(t *testing.T) {
 //
 //  1. Creates a buffered channel: stop := make(chan os.Signal, 1)
 //  2. Registers for signals: signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
-//  3. Blocks until signal received: <-stop
+//  3. Blocks until signal received: sig := <-stop
 //  4. Cleans up signal handler: signal.Stop(stop)
+//  5. Shuts down within cfg.Shutdown.TimeoutFor(sig), logging the signal
 //
 // This cleanup is important because:
 //   - It prevents goroutine leaks in the signal package
@@ -560,7 +754,8 @@ func Test_ServeCommand_Run_MethodSignature(t *testing.T) {
 //
 //  1. Run the bot: go run cmd/bot/main.go serve
 //  2. Send SIGTERM: kill -TERM <pid>
-//  3. Observe graceful shutdown in logs
+//  3. Observe graceful shutdown in logs, with signal=terminated and the
+//     shutdown.terminate_timeout (or shutdown.timeout) in effect
 func Test_ServeCommand_SignalCleanup_Documentation(t *testing.T) {
 	// This test documents the signal cleanup behavior.
 	// The actual signal.Stop call is verified by:
@@ -589,6 +784,195 @@ func Benchmark_ServeCommand_Name(b *testing.B) {
 	}
 }
 
+// Test_ServeCommand_StartControlServer verifies the control API binds its port
+// only when enabled, and that --api-port is ignored with a warning otherwise.
+func Test_ServeCommand_StartControlServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		passPort    bool
+		wantBound   bool
+		wantLogs    []string
+		notWantLogs []string
+	}{
+		{
+			name:      "enabled binds the port",
+			enabled:   true,
+			passPort:  true,
+			wantBound: true,
+			wantLogs:  []string{"control API server starting"},
+		},
+		{
+			name:        "disabled binds nothing",
+			enabled:     false,
+			wantLogs:    []string{"control API is disabled"},
+			notWantLogs: []string{"--api-port is ignored"},
+		},
+		{
+			name:     "disabled warns about an explicit port",
+			enabled:  false,
+			passPort: true,
+			wantLogs: []string{"control API is disabled", "--api-port is ignored"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := freePort(t)
+
+			cmd := commands.NewServeCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			var args []string
+			if tt.passPort {
+				args = []string{"--api-port", strconv.Itoa(port)}
+			}
+			require.NoError(t, fs.Parse(args))
+
+			logs := &bytes.Buffer{}
+			server, err := cmd.StartControlServer(
+				config.ControlConfig{Enabled: tt.enabled},
+				&rulesBot{set: rules.NewSet(rules.Defaults()...)},
+				zerolog.New(logs),
+			)
+			require.NoError(t, err)
+
+			if tt.wantBound {
+				require.NotNil(t, server)
+				t.Cleanup(func() { _ = server.Stop(context.Background()) })
+				assert.Equal(t, fmt.Sprintf("127.0.0.1:%d", port), server.Addr())
+			} else {
+				assert.Nil(t, server)
+				// The port must still be free for anything else to use
+				listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+				require.NoError(t, err, "control API should not bind a port when disabled")
+				listener.Close()
+			}
+
+			for _, want := range tt.wantLogs {
+				assert.Contains(t, logs.String(), want)
+			}
+			for _, unwanted := range tt.notWantLogs {
+				assert.NotContains(t, logs.String(), unwanted)
+			}
+		})
+	}
+}
+
+func Test_CommandLogging(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          config.LoggingConfig
+		toFile       bool
+		wantLogs     []string
+		wantAudit    int
+		notWantInLog string
+	}{
+		{
+			name:         "logs executions without auditing",
+			cfg:          config.LoggingConfig{SampleSuccesses: 1},
+			wantLogs:     []string{"command executed successfully"},
+			notWantInLog: "command audit",
+		},
+		{
+			name:         "option values are left out of log lines by default",
+			cfg:          config.LoggingConfig{SampleSuccesses: 1},
+			wantLogs:     []string{"command executed successfully"},
+			notWantInLog: "hello",
+		},
+		{
+			name:         "log lines include options when enabled",
+			cfg:          config.LoggingConfig{SampleSuccesses: 1, Options: true},
+			wantLogs:     []string{"command executed successfully", `"options":{"message":"hello"}`},
+			notWantInLog: "[REDACTED]",
+		},
+		{
+			name:         "logged options are redacted by name",
+			cfg:          config.LoggingConfig{SampleSuccesses: 1, Options: true, OptionsRedact: []string{"message"}},
+			wantLogs:     []string{`"options":{"message":"[REDACTED]"}`},
+			notWantInLog: "hello",
+		},
+		{
+			name:         "audit records replace log lines without a file",
+			cfg:          config.LoggingConfig{SampleSuccesses: 1, Audit: true, AuditRedact: []string{"message"}},
+			wantLogs:     []string{"command audit", `"message":"[REDACTED]"`},
+			notWantInLog: "command executed successfully",
+		},
+		{
+			name:         "audit file gets records apart from the log",
+			cfg:          config.LoggingConfig{SampleSuccesses: 1, Audit: true},
+			toFile:       true,
+			wantLogs:     []string{"command executed successfully"},
+			wantAudit:    1,
+			notWantInLog: "command audit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditPath := filepath.Join(t.TempDir(), "audit.log")
+			if tt.toFile {
+				tt.cfg.AuditFile = auditPath
+			}
+			logs := &bytes.Buffer{}
+
+			commandLog, err := commands.CommandLogging(tt.cfg, zerolog.New(logs))
+			require.NoError(t, err)
+			assert.Equal(t, tt.cfg.Audit, commandLog.Confirmations != nil, "confirmations are audited only with executions")
+
+			ctx := command.NewContext(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+				ID:     "interaction-1",
+				Type:   discordgo.InteractionApplicationCommand,
+				Member: &discordgo.Member{User: &discordgo.User{ID: "user-1"}},
+				Data: discordgo.ApplicationCommandInteractionData{
+					Name: "echo",
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "message", Type: discordgo.ApplicationCommandOptionString, Value: "hello"},
+					},
+				},
+			}}, zerolog.Nop())
+			require.NoError(t, commandLog.Middleware(func(*command.Context) error { return nil })(ctx))
+			require.NoError(t, commandLog.Close())
+
+			for _, want := range tt.wantLogs {
+				assert.Contains(t, logs.String(), want)
+			}
+			assert.Equal(t, 1, strings.Count(logs.String(), "\n"), "each execution is logged once")
+			assert.NotContains(t, logs.String(), tt.notWantInLog)
+
+			audit, err := os.ReadFile(auditPath)
+			if tt.wantAudit == 0 {
+				assert.True(t, os.IsNotExist(err), "no audit file should be created")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAudit, strings.Count(string(audit), `"message":"command audit"`))
+			info, err := os.Stat(auditPath)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		})
+	}
+}
+
+func Test_CommandLogging_UnwritableAuditFile(t *testing.T) {
+	cfg := config.LoggingConfig{Audit: true, AuditFile: filepath.Join(t.TempDir(), "missing", "audit.log")}
+
+	_, err := commands.CommandLogging(cfg, zerolog.Nop())
+
+	assert.ErrorContains(t, err, "audit file")
+}
+
+// freePort returns a localhost port that was free a moment ago.
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	return port
+}
+
 func Benchmark_ServeCommand_Synopsis(b *testing.B) {
 	cmd := &commands.ServeCommand{}
 
@@ -2,18 +2,21 @@
 package commands
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"time"
 
-	"jamesbot/internal/api"
+	"jamesbot/internal/control"
 )
 
 // StatsCommand implements the stats command for displaying bot statistics.
 type StatsCommand struct {
-	jsonOutput bool
-	endpoint   string
+	jsonOutput    bool
+	endpoint      endpointFlags
+	noHealthCheck bool
 }
 
 // NewStatsCommand creates a new StatsCommand instance.
@@ -34,11 +37,15 @@ func (c *StatsCommand) Synopsis() string {
 // Usage returns detailed usage information for the command.
 func (c *StatsCommand) Usage() string {
 	var sb strings.Builder
-	sb.WriteString("Usage: jamesbot stats [options]\n\n")
+	sb.WriteString("Usage: jamesbot stats [options]\n")
+	sb.WriteString("       jamesbot stats <subcommand> [options]\n\n")
 	sb.WriteString("Display statistics about the bot's operation.\n\n")
+	sb.WriteString("Subcommands:\n")
+	sb.WriteString("  top     List the most used commands\n\n")
 	sb.WriteString("Options:\n")
 	sb.WriteString("  --json              Output stats as JSON instead of human-readable format\n")
-	sb.WriteString("  --endpoint <url>    API endpoint (default: http://127.0.0.1:8765)\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
 	sb.WriteString("  -h, --help          Show this help message\n")
 	return sb.String()
 }
@@ -46,7 +53,8 @@ func (c *StatsCommand) Usage() string {
 // SetFlags configures the command-line flags for the stats command.
 func (c *StatsCommand) SetFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.jsonOutput, "json", false, "Output stats as JSON")
-	fs.StringVar(&c.endpoint, "endpoint", "http://127.0.0.1:8765", "API endpoint")
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
 }
 
 // Run executes the stats command.
@@ -56,56 +64,159 @@ func (c *StatsCommand) Run(ctx *CLIContext, args []string) int {
 	stdout := ctx.Stdout
 	stderr := ctx.Stderr
 
-	// Use API endpoint from context if provided, otherwise use flag value
-	endpoint := c.endpoint
-	if ctx.APIEndpoint != "" {
-		endpoint = ctx.APIEndpoint
-	}
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
 
 	// Create API client
-	client := api.NewClient(endpoint)
-	if client == nil {
-		fmt.Fprintf(stderr, "Error: Failed to create API client\n")
-		return 1
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
 	}
 
 	// Get stats from API
 	stats, err := client.GetStats()
 	if err != nil {
-		// Check if this is a connection error
-		if strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection failed") {
-			fmt.Fprintf(stderr, "Error: Cannot connect to bot API at %s\n", endpoint)
-			fmt.Fprintf(stderr, "Make sure the bot is running with 'jamesbot serve'\n")
-			return 1
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
 		}
 
 		// Other API errors
 		fmt.Fprintf(stderr, "Error: Failed to get stats: %v\n", err)
-		return 1
+		return ExitAPIError
 	}
 
 	// Handle nil stats
 	if stats == nil {
 		fmt.Fprintf(stderr, "Error: Received nil stats from API\n")
-		return 1
+		return ExitError
 	}
 
 	// Output stats in requested format
 	if c.jsonOutput {
 		// JSON output
-		encoder := json.NewEncoder(stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(stats); err != nil {
+		if err := writeJSON(stdout, stats); err != nil {
 			fmt.Fprintf(stderr, "Error: Failed to encode stats as JSON: %v\n", err)
-			return 1
+			return ExitError
 		}
 	} else {
 		// Human-readable output
 		fmt.Fprintf(stdout, "Uptime: %s\n", stats.Uptime)
-		fmt.Fprintf(stdout, "Commands executed: %d\n", stats.CommandsExecuted)
+		fmt.Fprintf(stdout, "Connected: %s\n", formatConnected(stats.ConnectedSince, time.Now()))
+		if stats.LifetimeCommandsExecuted > 0 {
+			fmt.Fprintf(stdout, "Commands executed: %d since start, %d lifetime\n",
+				stats.CommandsExecuted, stats.LifetimeCommandsExecuted)
+		} else {
+			fmt.Fprintf(stdout, "Commands executed: %d\n", stats.CommandsExecuted)
+		}
 		fmt.Fprintf(stdout, "Guilds: %d\n", stats.GuildCount)
 		fmt.Fprintf(stdout, "Active rules: %d\n", stats.ActiveRules)
+		fmt.Fprintf(stdout, "Last command: %s\n", formatLastCommand(stats.LastCommandAt, time.Now()))
+		if maintenance := stats.Maintenance; maintenance != nil && maintenance.Enabled {
+			fmt.Fprintf(stdout, "Maintenance mode: %s\n", formatMaintenance(maintenance, time.Now()))
+		}
+		if rulesFile := stats.RulesFile; rulesFile != nil && rulesFile.Error != "" {
+			fmt.Fprintf(stdout, "Rules file: %s: %s\n", rulesFile.Path, rulesFile.Error)
+		}
+		if build := stats.Build; build != nil {
+			fmt.Fprintf(stdout, "Build: %s\n", formatBuild(build))
+		}
+		if pool := stats.Interactions; pool != nil {
+			fmt.Fprintf(stdout, "Workers: %d/%d busy, queue %d/%d\n",
+				pool.ActiveWorkers, pool.Workers, pool.QueueDepth, pool.QueueCapacity)
+		}
+		if len(stats.Commands) > 0 {
+			fmt.Fprintln(stdout)
+			writeCommandStatsTable(stdout, stats.Commands)
+		}
 	}
 
-	return 0
+	return ExitOK
+}
+
+// formatLastCommand describes a Unix time relative to now in its largest
+// whole unit, such as "5m ago", or "never" for zero.
+func formatLastCommand(unix int64, now time.Time) string {
+	if unix == 0 {
+		return "never"
+	}
+	return formatAge(now.Sub(time.Unix(unix, 0))) + " ago"
+}
+
+// formatConnected describes how long the bot has been connected to Discord
+// since a Unix time, such as "2h", or "no" for zero.
+func formatConnected(since int64, now time.Time) string {
+	if since == 0 {
+		return "no"
+	}
+	return formatAge(now.Sub(time.Unix(since, 0)))
+}
+
+// formatAge describes a duration in its largest whole unit, such as "5m".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(int(d/time.Second), 0))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
+
+// formatBuild describes a build on one line, such as
+// "1.1.0 (3f2a9c1d0b4e) go1.25.0 linux/amd64". The commit is shortened to
+// twelve characters and left out when unknown.
+func formatBuild(build *control.BuildInfo) string {
+	version := build.Version
+	if build.Commit != "" {
+		commit, dirty := strings.CutSuffix(build.Commit, "-dirty")
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if dirty {
+			commit += "-dirty"
+		}
+		version += " (" + commit + ")"
+	}
+	return fmt.Sprintf("%s %s %s/%s", version, build.GoVersion, build.OS, build.Arch)
+}
+
+// writeCommandStatsTable writes per-command executions and p95 latency as a
+// table. Commands that have not been timed show "-" for p95. When the bot
+// keeps counts across restarts, a Lifetime column follows Executions, which
+// then counts executions since start.
+func writeCommandStatsTable(w io.Writer, cmds []control.CommandStats) {
+	maxNameLen := len("Command")
+	lifetime := false
+	for _, cmd := range cmds {
+		if len(cmd.Name) > maxNameLen {
+			maxNameLen = len(cmd.Name)
+		}
+		if cmd.LifetimeExecutions > 0 {
+			lifetime = true
+		}
+	}
+
+	if lifetime {
+		fmt.Fprintf(w, "%-*s  %10s  %10s  %10s\n", maxNameLen, "Command", "Executions", "Lifetime", "p95")
+		fmt.Fprintf(w, "%s  %s  %s  %s\n", strings.Repeat("-", maxNameLen),
+			strings.Repeat("-", 10), strings.Repeat("-", 10), strings.Repeat("-", 10))
+	} else {
+		fmt.Fprintf(w, "%-*s  %10s  %10s\n", maxNameLen, "Command", "Executions", "p95")
+		fmt.Fprintf(w, "%s  %s  %s\n", strings.Repeat("-", maxNameLen), strings.Repeat("-", 10), strings.Repeat("-", 10))
+	}
+	for _, cmd := range cmds {
+		p95 := "-"
+		if cmd.Latency != nil {
+			p95 = strconv.FormatFloat(cmd.Latency.P95, 'f', 1, 64) + "ms"
+		}
+		if lifetime {
+			fmt.Fprintf(w, "%-*s  %10d  %10d  %10s\n", maxNameLen, cmd.Name, cmd.Executions, cmd.LifetimeExecutions, p95)
+		} else {
+			fmt.Fprintf(w, "%-*s  %10d  %10s\n", maxNameLen, cmd.Name, cmd.Executions, p95)
+		}
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"jamesbot/internal/cli/commands"
 	"jamesbot/internal/control"
@@ -108,7 +109,7 @@ func Test_StatsCommand_SetFlags(t *testing.T) {
 	}{
 		{
 			name:          "registers json and endpoint flags",
-			expectedFlags: []string{"json", "endpoint"},
+			expectedFlags: []string{"json", "endpoint", "no-health-check"},
 		},
 	}
 
@@ -282,7 +283,7 @@ func Test_StatsCommand_Run_HumanReadable(t *testing.T) {
 				GuildCount:       5,
 				ActiveRules:      3,
 			},
-			expectExitCode: 0,
+			expectExitCode: commands.ExitOK,
 			expectContains: []string{"uptime", "commands", "guilds"},
 		},
 		{
@@ -294,7 +295,7 @@ func Test_StatsCommand_Run_HumanReadable(t *testing.T) {
 				GuildCount:       10,
 				ActiveRules:      7,
 			},
-			expectExitCode: 0,
+			expectExitCode: commands.ExitOK,
 			expectContains: []string{"uptime", "commands", "guilds"},
 		},
 	}
@@ -344,6 +345,288 @@ func Test_StatsCommand_Run_HumanReadable(t *testing.T) {
 	}
 }
 
+// Test_StatsCommand_Run_CommandTable verifies the per-command table and its p95 column.
+func Test_StatsCommand_Run_CommandTable(t *testing.T) {
+	tests := []struct {
+		name          string
+		commands      []control.CommandStats
+		lifetime      int64
+		expectLines   []string
+		expectMissing []string
+	}{
+		{
+			name:          "no table without per-command stats",
+			expectLines:   []string{"Commands executed: 15"},
+			expectMissing: []string{"Executions", "p95", "lifetime"},
+		},
+		{
+			name: "shows p95 when timed and a dash when not",
+			commands: []control.CommandStats{
+				{Name: "ban", Executions: 3, Latency: &control.LatencyPercentiles{Samples: 3, P50: 10, P95: 42.31, P99: 50}},
+				{Name: "ping", Executions: 12},
+			},
+			expectLines: []string{
+				"Command  Executions         p95",
+				"ban               3      42.3ms",
+				"ping             12           -",
+			},
+			expectMissing: []string{"Lifetime"},
+		},
+		{
+			name: "shows lifetime counts when kept across restarts",
+			commands: []control.CommandStats{
+				{Name: "ban", Executions: 3, LifetimeExecutions: 40, Latency: &control.LatencyPercentiles{Samples: 3, P50: 10, P95: 42.31, P99: 50}},
+				{Name: "kick", LifetimeExecutions: 7},
+				{Name: "ping", Executions: 12, LifetimeExecutions: 853},
+			},
+			lifetime: 900,
+			expectLines: []string{
+				"Commands executed: 15 since start, 900 lifetime",
+				"Command  Executions    Lifetime         p95",
+				"ban               3          40      42.3ms",
+				"kick              0           7           -",
+				"ping             12         853           -",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := control.Stats{Uptime: "1m0s", CommandsExecuted: 15, LifetimeCommandsExecuted: tt.lifetime, Commands: tt.commands}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(stats)
+			}))
+			defer server.Close()
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+			exitCode := commands.NewStatsCommand().Run(ctx, nil)
+
+			assert.Equal(t, commands.ExitOK, exitCode)
+			for _, line := range tt.expectLines {
+				assert.Contains(t, stdout.String(), line+"\n")
+			}
+			for _, missing := range tt.expectMissing {
+				assert.NotContains(t, stdout.String(), missing)
+			}
+		})
+	}
+}
+
+// Test_StatsCommand_Run_Workers verifies the worker pool line appears only when reported.
+func Test_StatsCommand_Run_Workers(t *testing.T) {
+	tests := []struct {
+		name         string
+		interactions *control.InteractionStats
+		expectLine   string
+	}{
+		{
+			name: "bot not started",
+		},
+		{
+			name:         "pool reported",
+			interactions: &control.InteractionStats{Workers: 16, ActiveWorkers: 2, QueueDepth: 5, QueueCapacity: 100},
+			expectLine:   "Workers: 2/16 busy, queue 5/100\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := control.Stats{Uptime: "1m0s", Interactions: tt.interactions}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(stats)
+			}))
+			defer server.Close()
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+			exitCode := commands.NewStatsCommand().Run(ctx, nil)
+
+			assert.Equal(t, commands.ExitOK, exitCode)
+			if tt.expectLine == "" {
+				assert.NotContains(t, stdout.String(), "Workers:")
+			} else {
+				assert.Contains(t, stdout.String(), tt.expectLine)
+			}
+		})
+	}
+}
+
+// Test_StatsCommand_Run_RulesFile verifies the rules file is mentioned only
+// when changes to rules are not being saved.
+func Test_StatsCommand_Run_RulesFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		rulesFile  *control.RulesFileState
+		expectLine string
+	}{
+		{
+			name: "not kept",
+		},
+		{
+			name:      "saving",
+			rulesFile: &control.RulesFileState{Path: "rules.json"},
+		},
+		{
+			name:       "not saving",
+			rulesFile:  &control.RulesFileState{Path: "rules.json", Error: "last save failed: disk full"},
+			expectLine: "Rules file: rules.json: last save failed: disk full\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := control.Stats{Uptime: "1m0s", RulesFile: tt.rulesFile}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(stats)
+			}))
+			defer server.Close()
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+			exitCode := commands.NewStatsCommand().Run(ctx, nil)
+
+			assert.Equal(t, commands.ExitOK, exitCode)
+			if tt.expectLine == "" {
+				assert.NotContains(t, stdout.String(), "Rules file:")
+			} else {
+				assert.Contains(t, stdout.String(), tt.expectLine)
+			}
+		})
+	}
+}
+
+// Test_StatsCommand_Run_Build verifies the build line appears only when reported.
+func Test_StatsCommand_Run_Build(t *testing.T) {
+	tests := []struct {
+		name       string
+		build      *control.BuildInfo
+		expectLine string
+	}{
+		{
+			name: "not reported",
+		},
+		{
+			name:       "commit is shortened",
+			build:      &control.BuildInfo{Version: "1.1.0", Commit: "3f2a9c1d0b4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a", GoVersion: "go1.25.0", OS: "linux", Arch: "amd64"},
+			expectLine: "Build: 1.1.0 (3f2a9c1d0b4e) go1.25.0 linux/amd64\n",
+		},
+		{
+			name:       "dirty checkout",
+			build:      &control.BuildInfo{Version: "1.1.0", Commit: "3f2a9c1d0b4e5f6a-dirty", GoVersion: "go1.25.0", OS: "darwin", Arch: "arm64"},
+			expectLine: "Build: 1.1.0 (3f2a9c1d0b4e-dirty) go1.25.0 darwin/arm64\n",
+		},
+		{
+			name:       "unknown commit",
+			build:      &control.BuildInfo{Version: "1.1.0", GoVersion: "go1.25.0", OS: "linux", Arch: "amd64"},
+			expectLine: "Build: 1.1.0 go1.25.0 linux/amd64\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := control.Stats{Uptime: "1m0s", Build: tt.build}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(stats)
+			}))
+			defer server.Close()
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+			exitCode := commands.NewStatsCommand().Run(ctx, nil)
+
+			assert.Equal(t, commands.ExitOK, exitCode)
+			if tt.expectLine == "" {
+				assert.NotContains(t, stdout.String(), "Build:")
+			} else {
+				assert.Contains(t, stdout.String(), tt.expectLine)
+			}
+		})
+	}
+}
+
+// Test_StatsCommand_Run_LastCommand verifies how long ago the last command ran is shown.
+func Test_StatsCommand_Run_LastCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		ago        time.Duration
+		expectLine string
+	}{
+		{name: "no commands yet", expectLine: "Last command: never\n"},
+		{name: "seconds", ago: 30 * time.Second, expectLine: "Last command: 30s ago\n"},
+		{name: "minutes", ago: 5*time.Minute + 10*time.Second, expectLine: "Last command: 5m ago\n"},
+		{name: "hours", ago: 3*time.Hour + 20*time.Minute, expectLine: "Last command: 3h ago\n"},
+		{name: "days", ago: 50 * time.Hour, expectLine: "Last command: 2d ago\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := control.Stats{Uptime: "1m0s"}
+			if tt.ago > 0 {
+				stats.LastCommandAt = time.Now().Add(-tt.ago).Unix()
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(stats)
+			}))
+			defer server.Close()
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+			exitCode := commands.NewStatsCommand().Run(ctx, nil)
+
+			assert.Equal(t, commands.ExitOK, exitCode)
+			assert.Contains(t, stdout.String(), tt.expectLine)
+		})
+	}
+}
+
+// Test_StatsCommand_Run_Connected verifies the connection uptime is shown
+// separately from the process uptime.
+func Test_StatsCommand_Run_Connected(t *testing.T) {
+	tests := []struct {
+		name       string
+		ago        time.Duration
+		expectLine string
+	}{
+		{name: "disconnected", expectLine: "Connected: no\n"},
+		{name: "reconnected recently", ago: 2*time.Hour + 5*time.Minute, expectLine: "Connected: 2h\n"},
+		{name: "connected for days", ago: 75 * time.Hour, expectLine: "Connected: 3d\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := control.Stats{Uptime: "72h0m0s"}
+			if tt.ago > 0 {
+				stats.ConnectedSince = time.Now().Add(-tt.ago).Unix()
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(stats)
+			}))
+			defer server.Close()
+
+			stdout := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+			exitCode := commands.NewStatsCommand().Run(ctx, nil)
+
+			assert.Equal(t, commands.ExitOK, exitCode)
+			assert.Contains(t, stdout.String(), "Uptime: 72h0m0s\n")
+			assert.Contains(t, stdout.String(), tt.expectLine)
+		})
+	}
+}
+
 // Test_StatsCommand_Run_FormatsDuration verifies duration formatting.
 func Test_StatsCommand_Run_FormatsDuration(t *testing.T) {
 	tests := []struct {
@@ -403,7 +686,7 @@ func Test_StatsCommand_Run_FormatsDuration(t *testing.T) {
 
 			exitCode := cmd.Run(ctx, fs.Args())
 
-			assert.Equal(t, 0, exitCode, "Run() should return exit code 0")
+			assert.Equal(t, commands.ExitOK, exitCode, "Run() should return ExitOK")
 			assert.Contains(t, stdout.String(), tt.expectContains,
 				"stdout should contain formatted duration %q", tt.expectContains)
 		})
@@ -426,7 +709,7 @@ func Test_StatsCommand_Run_JSONOutput(t *testing.T) {
 				GuildCount:       3,
 				ActiveRules:      2,
 			},
-			expectExitCode: 0,
+			expectExitCode: commands.ExitOK,
 		},
 	}
 
@@ -524,7 +807,7 @@ func Test_StatsCommand_Run_JSONContainsFields(t *testing.T) {
 			}
 
 			exitCode := cmd.Run(ctx, fs.Args())
-			assert.Equal(t, 0, exitCode, "Run() should return exit code 0")
+			assert.Equal(t, commands.ExitOK, exitCode, "Run() should return ExitOK")
 
 			// Parse JSON and verify fields
 			var output map[string]interface{}
@@ -548,7 +831,7 @@ func Test_StatsCommand_Run_BotNotRunning(t *testing.T) {
 	}{
 		{
 			name:                "bot not running returns error",
-			expectExitCode:      1,
+			expectExitCode:      commands.ExitConnectionError,
 			expectStderrContain: []string{"cannot connect", "connection"},
 		},
 	}
@@ -603,19 +886,19 @@ func Test_StatsCommand_Run_ServerError(t *testing.T) {
 		expectExitCode int
 	}{
 		{
-			name:           "server 500 error returns exit 1",
+			name:           "server 500 error returns error exit code",
 			statusCode:     500,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 		{
-			name:           "server 404 error returns exit 1",
+			name:           "server 404 error returns error exit code",
 			statusCode:     404,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 		{
-			name:           "server 503 error returns exit 1",
+			name:           "server 503 error returns error exit code",
 			statusCode:     503,
-			expectExitCode: 1,
+			expectExitCode: commands.ExitAPIError,
 		},
 	}
 
@@ -664,19 +947,38 @@ func Test_StatsCommand_Run_ServerError(t *testing.T) {
 // Test_StatsCommand_Run_InvalidJSON tests error handling for invalid JSON response.
 func Test_StatsCommand_Run_InvalidJSON(t *testing.T) {
 	tests := []struct {
-		name           string
-		response       string
-		expectExitCode int
+		name             string
+		response         string
+		healthy          bool
+		flags            []string
+		expectExitCode   int
+		expectStderrText string
 	}{
 		{
-			name:           "invalid JSON response returns exit 1",
-			response:       "not valid json",
-			expectExitCode: 1,
+			name:             "invalid JSON from something other than the bot is a connection error",
+			response:         "not valid json",
+			expectExitCode:   commands.ExitConnectionError,
+			expectStderrText: "is not reachable; is the bot running?",
+		},
+		{
+			name:             "empty response from something other than the bot is a connection error",
+			response:         "",
+			expectExitCode:   commands.ExitConnectionError,
+			expectStderrText: "is not reachable; is the bot running?",
+		},
+		{
+			name:             "invalid JSON from a healthy bot returns error exit code",
+			response:         "not valid json",
+			healthy:          true,
+			expectExitCode:   commands.ExitAPIError,
+			expectStderrText: "Failed to get stats: decode failed",
 		},
 		{
-			name:           "empty response returns exit 1",
-			response:       "",
-			expectExitCode: 1,
+			name:             "invalid JSON without a health check returns error exit code",
+			response:         "not valid json",
+			flags:            []string{"--no-health-check"},
+			expectExitCode:   commands.ExitAPIError,
+			expectStderrText: "Failed to get stats: decode failed",
 		},
 	}
 
@@ -690,6 +992,10 @@ func Test_StatsCommand_Run_InvalidJSON(t *testing.T) {
 					w.Write([]byte(tt.response))
 					return
 				}
+				if r.URL.Path == "/health" && tt.healthy {
+					w.Write([]byte(`{"status":"ok"}`))
+					return
+				}
 				http.NotFound(w, r)
 			}))
 			defer server.Close()
@@ -702,7 +1008,7 @@ func Test_StatsCommand_Run_InvalidJSON(t *testing.T) {
 
 			cmd.SetFlags(fs)
 
-			err := fs.Parse([]string{"--endpoint", server.URL})
+			err := fs.Parse(append([]string{"--endpoint", server.URL}, tt.flags...))
 			require.NoError(t, err, "Flag parsing should succeed")
 
 			ctx := &commands.CLIContext{
@@ -715,6 +1021,7 @@ func Test_StatsCommand_Run_InvalidJSON(t *testing.T) {
 
 			assert.Equal(t, tt.expectExitCode, exitCode,
 				"Run() should return exit code %d on invalid JSON", tt.expectExitCode)
+			assert.Contains(t, stderr.String(), tt.expectStderrText)
 		})
 	}
 }
@@ -761,6 +1068,132 @@ func Test_StatsCommand_SetFlags_FlagDescriptions(t *testing.T) {
 	}
 }
 
+// StatsTopCommand tests
+
+func Test_StatsTopCommand_Metadata(t *testing.T) {
+	cmd := commands.NewStatsTopCommand()
+
+	assert.Equal(t, "top", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot stats top")
+}
+
+func Test_StatsTopCommand_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       []string
+		args        []string
+		top         string
+		wantN       string
+		wantExit    int
+		expectLines []string
+		wantStderr  string
+	}{
+		{
+			name:     "ranked table",
+			top:      `[{"name":"ban","executions":12},{"name":"timeout","executions":3}]`,
+			wantN:    "10",
+			wantExit: commands.ExitOK,
+			expectLines: []string{
+				"Rank  Command  Executions",
+				"   1  ban              12",
+				"   2  timeout           3",
+			},
+		},
+		{
+			name:        "custom count",
+			flags:       []string{"-n", "1"},
+			top:         `[{"name":"ban","executions":12}]`,
+			wantN:       "1",
+			wantExit:    commands.ExitOK,
+			expectLines: []string{"   1  ban              12"},
+		},
+		{
+			name:        "no commands",
+			top:         `[]`,
+			wantN:       "10",
+			wantExit:    commands.ExitOK,
+			expectLines: []string{"No commands have been executed yet"},
+		},
+		{
+			name:        "json output",
+			flags:       []string{"--json"},
+			top:         `[{"name":"ban","executions":12}]`,
+			wantN:       "10",
+			wantExit:    commands.ExitOK,
+			expectLines: []string{`    "name": "ban",`, `    "executions": 12`},
+		},
+		{
+			name:       "zero count",
+			flags:      []string{"-n", "0"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "-n must be a positive number",
+		},
+		{
+			name:       "unexpected arguments",
+			args:       []string{"ban"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Unexpected arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/stats/commands/top", r.URL.Path)
+				assert.Equal(t, tt.wantN, r.URL.Query().Get("n"))
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.top))
+			}))
+			defer server.Close()
+
+			cmd := commands.NewStatsTopCommand()
+			fs := flag.NewFlagSet("top", flag.ContinueOnError)
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(tt.flags))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, tt.args)
+
+			assert.Equal(t, tt.wantExit, exitCode, stderr.String())
+			for _, line := range tt.expectLines {
+				assert.Contains(t, stdout.String(), line+"\n")
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func Test_StatsTopCommand_Run_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: server.URL}
+
+	exitCode := commands.NewStatsTopCommand().Run(ctx, nil)
+
+	assert.Equal(t, commands.ExitAPIError, exitCode)
+	assert.Contains(t, stderr.String(), "Failed to get top commands")
+}
+
+func Test_StatsTopCommand_Run_BotNotRunning(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://127.0.0.1:59995"}
+
+	exitCode := commands.NewStatsTopCommand().Run(ctx, nil)
+
+	assert.Equal(t, commands.ExitConnectionError, exitCode)
+	assert.Contains(t, stderr.String(), "Cannot connect")
+}
+
 // Benchmark tests
 
 func Benchmark_StatsCommand_Name(b *testing.B) {
@@ -0,0 +1,130 @@
+// Package commands provides CLI command implementations for JamesBot.
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"jamesbot/internal/control"
+)
+
+// StatsTopCommand implements the stats top command for listing the most used
+// commands.
+type StatsTopCommand struct {
+	n             int
+	jsonOutput    bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewStatsTopCommand creates a new StatsTopCommand instance.
+func NewStatsTopCommand() *StatsTopCommand {
+	return &StatsTopCommand{n: control.DefaultTopCommands}
+}
+
+// Name returns the name of the command.
+func (c *StatsTopCommand) Name() string {
+	return "top"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *StatsTopCommand) Synopsis() string {
+	return "List the most used commands"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *StatsTopCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot stats top [options]\n\n")
+	sb.WriteString("List the commands executed most since the bot started, ranked by count.\n\n")
+	sb.WriteString("Options:\n")
+	fmt.Fprintf(&sb, "  -n <count>          Number of commands to list (default: %d, at most %d)\n", control.DefaultTopCommands, control.MaxTopCommands)
+	sb.WriteString("  --json              Output as JSON instead of a table\n")
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the stats top command.
+func (c *StatsTopCommand) SetFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.n, "n", control.DefaultTopCommands, "Number of commands to list")
+	fs.BoolVar(&c.jsonOutput, "json", false, "Output as JSON")
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the stats top command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *StatsTopCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	if len(args) > 0 {
+		fmt.Fprintf(stderr, "Error: Unexpected arguments: %s\n\n", strings.Join(args, " "))
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+	if c.n < 1 {
+		fmt.Fprintf(stderr, "Error: -n must be a positive number\n")
+		return ExitUsage
+	}
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	client, err := newClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+
+	top, err := client.TopCommands(c.n)
+	if err != nil {
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		fmt.Fprintf(stderr, "Error: Failed to get top commands: %v\n", err)
+		return ExitAPIError
+	}
+
+	if c.jsonOutput {
+		if top == nil {
+			top = []control.CommandStats{}
+		}
+		if err := writeJSON(stdout, top); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to encode top commands as JSON: %v\n", err)
+			return ExitError
+		}
+		return ExitOK
+	}
+
+	if len(top) == 0 {
+		fmt.Fprintln(stdout, "No commands have been executed yet")
+		return ExitOK
+	}
+	writeTopCommandsTable(stdout, top)
+	return ExitOK
+}
+
+// writeTopCommandsTable writes commands as a table ranked in the given order,
+// starting from 1.
+func writeTopCommandsTable(w io.Writer, cmds []control.CommandStats) {
+	rankWidth := max(len("Rank"), len(strconv.Itoa(len(cmds))))
+	maxNameLen := len("Command")
+	for _, cmd := range cmds {
+		if len(cmd.Name) > maxNameLen {
+			maxNameLen = len(cmd.Name)
+		}
+	}
+
+	fmt.Fprintf(w, "%*s  %-*s  %10s\n", rankWidth, "Rank", maxNameLen, "Command", "Executions")
+	fmt.Fprintf(w, "%s  %s  %s\n", strings.Repeat("-", rankWidth), strings.Repeat("-", maxNameLen), strings.Repeat("-", 10))
+	for i, cmd := range cmds {
+		fmt.Fprintf(w, "%*d  %-*s  %10d\n", rankWidth, i+1, maxNameLen, cmd.Name, cmd.Executions)
+	}
+}
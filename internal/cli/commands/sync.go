@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"jamesbot/internal/bot"
+
+	"github.com/rs/zerolog"
+)
+
+// SyncCommand implements the sync command, which pushes the bot's slash
+// command definitions to Discord without starting the bot.
+type SyncCommand struct {
+	configPath stringValue
+	global     bool
+}
+
+// NewSyncCommand creates a new SyncCommand instance.
+func NewSyncCommand() *SyncCommand {
+	return &SyncCommand{}
+}
+
+// Name returns the name of the command.
+func (c *SyncCommand) Name() string {
+	return "sync"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *SyncCommand) Synopsis() string {
+	return "Register slash commands with Discord without starting the bot"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *SyncCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot sync [options]\n\n")
+	sb.WriteString("Build the command set exactly as 'jamesbot serve' would and make the\n")
+	sb.WriteString("slash commands registered with Discord match it, then exit. Commands go\n")
+	sb.WriteString("to discord.guild_id if set, unless discord.global is true, and otherwise\n")
+	sb.WriteString("they are registered globally.\n")
+	sb.WriteString("Commands no longer defined are deleted. The changes are listed as\n")
+	sb.WriteString("+ created, ~ updated, and - deleted.\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString("  -c, --config <path>  Path to config file (searched like serve)\n")
+	sb.WriteString("  --global             Register globally even if a guild is configured\n")
+	sb.WriteString("  -h, --help           Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the sync command.
+func (c *SyncCommand) SetFlags(fs *flag.FlagSet) {
+	c.configPath = stringValue{value: "config/config.yaml"}
+	fs.Var(&c.configPath, "c", "Path to config file")
+	fs.Var(&c.configPath, "config", "Path to config file")
+	fs.BoolVar(&c.global, "global", false, "Register commands globally")
+}
+
+// Run executes the sync command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *SyncCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	resolved, err := resolveConfig(&c.configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to load configuration: %v\n", err)
+		return ExitConfigError
+	}
+	if resolved.fileErr != nil {
+		fmt.Fprintf(stderr, "Warning: Failed to load %s, using environment variables only: %v\n", resolved.path, resolved.fileErr)
+	}
+	cfg := resolved.cfg
+
+	// Only problems are worth logging for a one-shot command.
+	logger := zerolog.New(stderr).With().Timestamp().Logger().Level(zerolog.WarnLevel)
+
+	b, err := bot.New(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create bot: %v\n", err)
+		return ExitError
+	}
+
+	// Confirmations do not change the definitions sent to Discord
+	if _, err := registerCommands(b, cfg.Commands, nil, logger); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to register commands: %v\n", err)
+		return ExitError
+	}
+	pluginLoader := loadPlugins(logger)
+	defer pluginLoader.ShutdownAll()
+	registerPluginCommands(b, pluginLoader, cfg.Commands, logger)
+
+	diff, err := b.SyncCommands(c.global)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to sync commands: %v\n", err)
+		return ExitError
+	}
+
+	target := "globally"
+	if guildID := b.CommandGuild(c.global); guildID != "" {
+		target = "to guild " + guildID
+	}
+	fmt.Fprintf(stdout, "Synced commands %s: %s\n", target, diff)
+	for _, name := range diff.Created {
+		fmt.Fprintf(stdout, "  + %s\n", name)
+	}
+	for _, name := range diff.Updated {
+		fmt.Fprintf(stdout, "  ~ %s\n", name)
+	}
+	for _, name := range diff.Deleted {
+		fmt.Fprintf(stdout, "  - %s\n", name)
+	}
+	if !diff.Changed() {
+		fmt.Fprintf(stdout, "Discord is already up to date\n")
+	}
+
+	return ExitOK
+}
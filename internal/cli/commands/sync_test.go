@@ -0,0 +1,61 @@
+package commands_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// SyncCommand Tests
+// ===========================================================================
+
+func Test_SyncCommand_Metadata(t *testing.T) {
+	cmd := commands.NewSyncCommand()
+
+	assert.Equal(t, "sync", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot sync")
+	assert.Contains(t, cmd.Usage(), "--global")
+}
+
+func Test_SyncCommand_SetFlags(t *testing.T) {
+	cmd := commands.NewSyncCommand()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+
+	cmd.SetFlags(fs)
+
+	for _, name := range []string{"c", "config", "global"} {
+		assert.NotNil(t, fs.Lookup(name), "SetFlags should register --%s", name)
+	}
+	assert.Equal(t, "false", fs.Lookup("global").DefValue)
+}
+
+func Test_SyncCommand_Run_MissingToken(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("JAMESBOT_CONFIG", "")
+	t.Setenv("JAMESBOT_DISCORD_TOKEN", "")
+	os.Unsetenv("JAMESBOT_DISCORD_TOKEN")
+
+	cmd := commands.NewSyncCommand()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--global"}))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	exitCode := cmd.Run(&commands.CLIContext{Stdout: stdout, Stderr: stderr}, fs.Args())
+
+	assert.Equal(t, commands.ExitConfigError, exitCode)
+	assert.Contains(t, stderr.String(), "token")
+	assert.Empty(t, stdout.String())
+}
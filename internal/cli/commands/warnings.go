@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"flag"
+	"strings"
+)
+
+// WarningsCommand is a parent command for warning management.
+// It acts as a container for subcommands like clear.
+type WarningsCommand struct{}
+
+// NewWarningsCommand creates a new WarningsCommand instance.
+func NewWarningsCommand() *WarningsCommand {
+	return &WarningsCommand{}
+}
+
+// Name returns the name of the command.
+func (c *WarningsCommand) Name() string {
+	return "warnings"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *WarningsCommand) Synopsis() string {
+	return "Manage member warnings"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *WarningsCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot warnings <subcommand> [options]\n\n")
+	sb.WriteString("Manage warnings issued to guild members.\n\n")
+	sb.WriteString("Subcommands:\n")
+	sb.WriteString("  clear  Clear all warnings for a member\n\n")
+	sb.WriteString("Use \"jamesbot warnings <subcommand> -h\" for more information about a subcommand.\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the warnings command.
+// Parent commands typically don't have their own flags.
+func (c *WarningsCommand) SetFlags(fs *flag.FlagSet) {
+	// No flags for parent command
+}
+
+// Run executes the warnings command.
+// When invoked without a subcommand, it prints usage information.
+func (c *WarningsCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stdout.Write([]byte(c.Usage()))
+	return ExitOK
+}
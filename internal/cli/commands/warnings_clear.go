@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"jamesbot/internal/control"
+)
+
+// WarningsClearCommand implements the warnings clear command for deleting a member's warnings.
+type WarningsClearCommand struct {
+	quiet         bool
+	endpoint      endpointFlags
+	noHealthCheck bool
+}
+
+// NewWarningsClearCommand creates a new WarningsClearCommand instance.
+func NewWarningsClearCommand() *WarningsClearCommand {
+	return &WarningsClearCommand{}
+}
+
+// Name returns the name of the command.
+func (c *WarningsClearCommand) Name() string {
+	return "clear"
+}
+
+// Synopsis returns a brief description of the command.
+func (c *WarningsClearCommand) Synopsis() string {
+	return "Clear all warnings for a member"
+}
+
+// Usage returns detailed usage information for the command.
+func (c *WarningsClearCommand) Usage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: jamesbot warnings clear <guild-id> <user-id> [options]\n\n")
+	sb.WriteString("Delete every warning recorded for a member and report how many were removed.\n")
+	sb.WriteString("The bot API requires the auth token in $" + AuthTokenEnvVar + ".\n\n")
+	sb.WriteString("Arguments:\n")
+	sb.WriteString("  <guild-id>  ID of the guild the warnings were issued in\n")
+	sb.WriteString("  <user-id>   ID of the member whose warnings to clear\n\n")
+	sb.WriteString("Options:\n")
+	sb.WriteString(quietUsage)
+	sb.WriteString(endpointUsage)
+	sb.WriteString(healthCheckUsage)
+	sb.WriteString("  -h, --help          Show this help message\n")
+	return sb.String()
+}
+
+// SetFlags configures the command-line flags for the warnings clear command.
+func (c *WarningsClearCommand) SetFlags(fs *flag.FlagSet) {
+	addQuietFlag(fs, &c.quiet)
+	addEndpointFlag(fs, &c.endpoint)
+	addHealthCheckFlag(fs, &c.noHealthCheck)
+}
+
+// Run executes the warnings clear command.
+// It accepts a CLI context with stdout/stderr and command arguments.
+func (c *WarningsClearCommand) Run(ctx *CLIContext, args []string) int {
+	stdout := ctx.Stdout
+	stderr := ctx.Stderr
+
+	// Validate arguments
+	if len(args) < 2 {
+		fmt.Fprintf(stderr, "Error: Missing required arguments\n\n")
+		fmt.Fprintf(stderr, "%s", c.Usage())
+		return ExitUsage
+	}
+
+	guildID := args[0]
+	userID := args[1]
+
+	// Resolve API endpoint from context, flag, environment, or default
+	endpoint := resolveEndpoint(ctx, &c.endpoint)
+
+	// Clear warnings via API
+	client, err := newModerationClient(endpoint, &c.endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create API client: %v\n", err)
+		return ExitError
+	}
+	removed, err := client.ClearWarnings(guildID, userID)
+	if err != nil {
+		if errors.Is(err, control.ErrUnauthorized) {
+			writeUnauthorized(stderr)
+			return ExitAPIError
+		}
+		if reportUnreachable(stderr, client, endpoint, err, !c.noHealthCheck) {
+			return ExitConnectionError
+		}
+
+		// Other API errors
+		fmt.Fprintf(stderr, "Error: Failed to clear warnings: %v\n", err)
+		return ExitAPIError
+	}
+
+	if c.quiet {
+		return ExitOK
+	}
+	fmt.Fprintf(stdout, "Cleared %d warning(s) for user %s in guild %s\n", removed, userID, guildID)
+	return ExitOK
+}
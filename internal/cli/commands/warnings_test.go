@@ -0,0 +1,136 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jamesbot/internal/cli/commands"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// WarningsClearCommand Tests
+// ===========================================================================
+
+func Test_WarningsClearCommand_Metadata(t *testing.T) {
+	cmd := commands.NewWarningsClearCommand()
+
+	assert.Equal(t, "clear", cmd.Name())
+	assert.NotEmpty(t, cmd.Synopsis())
+	assert.Contains(t, cmd.Usage(), "Usage: jamesbot warnings clear")
+}
+
+func Test_WarningsClearCommand_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		status     int
+		response   string
+		wantExit   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "clears and reports count",
+			args:       []string{"guild-1", "user-1"},
+			status:     http.StatusOK,
+			response:   `{"removed": 2}`,
+			wantExit:   commands.ExitOK,
+			wantStdout: "Cleared 2 warning(s) for user user-1 in guild guild-1",
+		},
+		{
+			name:       "missing user id is a usage error",
+			args:       []string{"guild-1"},
+			wantExit:   commands.ExitUsage,
+			wantStderr: "Missing required arguments",
+		},
+		{
+			name:       "server error",
+			args:       []string{"guild-1", "user-1"},
+			status:     http.StatusInternalServerError,
+			wantExit:   commands.ExitAPIError,
+			wantStderr: "Failed to clear warnings",
+		},
+		{
+			name:       "token rejected",
+			args:       []string{"guild-1", "user-1"},
+			status:     http.StatusUnauthorized,
+			wantExit:   commands.ExitAPIError,
+			wantStderr: commands.AuthTokenEnvVar,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(commands.AuthTokenEnvVar, "secret")
+			var received map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/warnings/clear", r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				_ = json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			cmd := commands.NewWarningsClearCommand()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cmd.SetFlags(fs)
+			require.NoError(t, fs.Parse(tt.args))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			ctx := &commands.CLIContext{Stdout: stdout, Stderr: stderr, APIEndpoint: server.URL}
+
+			exitCode := cmd.Run(ctx, fs.Args())
+
+			assert.Equal(t, tt.wantExit, exitCode)
+			if tt.wantStdout != "" {
+				assert.Contains(t, stdout.String(), tt.wantStdout)
+				assert.Equal(t, map[string]string{"guild_id": "guild-1", "user_id": "user-1"}, received)
+			}
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func Test_WarningsClearCommand_Run_ConnectionError(t *testing.T) {
+	cmd := commands.NewWarningsClearCommand()
+	stderr := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: &bytes.Buffer{}, Stderr: stderr, APIEndpoint: "http://localhost:1"}
+
+	exitCode := cmd.Run(ctx, []string{"guild-1", "user-1"})
+
+	assert.Equal(t, commands.ExitConnectionError, exitCode)
+	assert.Contains(t, stderr.String(), "Cannot connect")
+}
+
+func Test_WarningsClearCommand_Run_Quiet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"removed": 2}`))
+	}))
+	defer server.Close()
+
+	cmd := commands.NewWarningsClearCommand()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	cmd.SetFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--quiet", "guild-1", "user-1"}))
+
+	stdout := &bytes.Buffer{}
+	ctx := &commands.CLIContext{Stdout: stdout, Stderr: &bytes.Buffer{}, APIEndpoint: server.URL}
+
+	exitCode := cmd.Run(ctx, fs.Args())
+
+	assert.Equal(t, commands.ExitOK, exitCode)
+	assert.Empty(t, stdout.String())
+}
@@ -1,28 +1,30 @@
 package cli
 
+import "jamesbot/internal/cli/commands"
+
 // Exit codes for CLI commands.
 // These are returned by command Run() methods and used as process exit codes.
 const (
 	// ExitSuccess indicates successful command execution.
-	ExitSuccess = 0
+	ExitSuccess = commands.ExitOK
 
 	// ExitUsageError indicates invalid command usage or arguments.
 	// This is returned when flags are incorrect or required arguments are missing.
-	ExitUsageError = 1
+	ExitUsageError = commands.ExitUsage
 
 	// ExitConnectionFail indicates a network connection failure.
 	// This is returned when the command cannot establish a connection to required services.
-	ExitConnectionFail = 2
+	ExitConnectionFail = commands.ExitConnectionError
 
 	// ExitAPIError indicates an API error response.
 	// This is returned when the API returns an error or unexpected response.
-	ExitAPIError = 3
+	ExitAPIError = commands.ExitAPIError
 
 	// ExitConfigError indicates a configuration error.
 	// This is returned when configuration is invalid or cannot be loaded.
-	ExitConfigError = 4
+	ExitConfigError = commands.ExitConfigError
 
 	// ExitFailure indicates a general failure.
 	// This is used for unspecified errors during command execution.
-	ExitFailure = 1
+	ExitFailure = commands.ExitError
 )
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+)
+
+// runHelp handles "jamesbot help [command [subcommand]]".
+// With no arguments it prints the general usage. Given a command (and
+// optionally a subcommand) it prints that command's full usage followed by
+// the flags it registers, introspected from SetFlags.
+func runHelp(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stdout)
+		return ExitSuccess
+	}
+
+	cmd, ok := getCommands()[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "Error: unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return ExitUsageError
+	}
+
+	if len(args) > 1 {
+		parent, isParent := cmd.(ParentCommand)
+		if !isParent {
+			fmt.Fprintf(stderr, "Error: command %q has no subcommands\n", cmd.Name())
+			return ExitUsageError
+		}
+
+		subcmd, found := findSubcommand(parent, args[1])
+		if !found {
+			printUnknownSubcommand(stderr, parent, args[1])
+			return ExitUsageError
+		}
+		cmd = subcmd
+	}
+
+	printCommandHelp(stdout, cmd)
+	return ExitSuccess
+}
+
+// printCommandHelp prints a command's usage text followed by its registered flags.
+func printCommandHelp(w io.Writer, cmd CLICommand) {
+	fmt.Fprintf(w, "%s\n", cmd.Usage())
+
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	cmd.SetFlags(fs)
+
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if !hasFlags {
+		return
+	}
+
+	fmt.Fprintf(w, "Flags:\n")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 
+	"jamesbot/internal/i18n"
 	"jamesbot/pkg/errutil"
 
 	"github.com/bwmarrin/discordgo"
@@ -10,7 +11,14 @@ import (
 
 // BanCommand implements a command to ban members from the server.
 // It requires the Ban Members permission to execute.
-type BanCommand struct{}
+type BanCommand struct {
+	// NotifyTarget DMs the member the action and reason before acting.
+	// Members with DMs disabled are banned without notice.
+	NotifyTarget bool
+
+	// Confirmer, when set, asks the moderator to confirm before banning.
+	Confirmer *Confirmer
+}
 
 // Name returns the command name.
 func (c *BanCommand) Name() string {
@@ -28,6 +36,12 @@ func (c *BanCommand) Permissions() int64 {
 	return discordgo.PermissionBanMembers
 }
 
+// GuildOnly reports that the command only works in a server, which has
+// members to act on.
+func (c *BanCommand) GuildOnly() bool {
+	return true
+}
+
 // Options returns the command options.
 // The ban command accepts a user, an optional reason, and optional message deletion days.
 func (c *BanCommand) Options() []*discordgo.ApplicationCommandOption {
@@ -56,7 +70,8 @@ func (c *BanCommand) Options() []*discordgo.ApplicationCommandOption {
 }
 
 // Execute runs the ban command.
-// It bans the specified user from the server with an optional reason and message deletion.
+// It bans the specified user from the server with an optional reason and message
+// deletion, after the moderator confirms when a Confirmer is set.
 func (c *BanCommand) Execute(ctx *Context) error {
 	if ctx == nil {
 		return fmt.Errorf("context cannot be nil")
@@ -74,7 +89,7 @@ func (c *BanCommand) Execute(ctx *Context) error {
 	// Validate cannot ban self
 	if targetUser.ID == ctx.UserID() {
 		return errutil.UserFriendlyError{
-			UserMessage: "You cannot ban yourself.",
+			UserMessage: ctx.T(i18n.MsgBanSelf),
 			Err:         fmt.Errorf("user attempted to ban yourself"),
 		}
 	}
@@ -82,7 +97,7 @@ func (c *BanCommand) Execute(ctx *Context) error {
 	// Validate cannot ban bots
 	if targetUser.Bot {
 		return errutil.UserFriendlyError{
-			UserMessage: "You cannot ban bots.",
+			UserMessage: ctx.T(i18n.MsgBanBot),
 			Err:         fmt.Errorf("user attempted to ban a bot"),
 		}
 	}
@@ -104,31 +119,63 @@ func (c *BanCommand) Execute(ctx *Context) error {
 
 	// Get guild ID
 	guildID := ctx.GuildID()
-	if guildID == "" {
+	if ctx.IsDM() {
 		return errutil.UserFriendlyError{
-			UserMessage: "This command can only be used in a server.",
+			UserMessage: ctx.T(i18n.MsgGuildOnly),
 			Err:         fmt.Errorf("ban command used outside of guild"),
 		}
 	}
 
 	// Check session before making Discord API calls
 	if ctx.Session == nil {
-		return fmt.Errorf("session cannot be nil")
-	}
-
-	// Perform the ban
-	err := ctx.Session.GuildBanCreateWithReason(guildID, targetUser.ID, reason, deleteDays)
-	if err != nil {
-		return errutil.UserFriendlyError{
-			UserMessage: fmt.Sprintf("Failed to ban %s. I may lack permissions or the user may have a higher role.", targetUser.Username),
-			Err:         fmt.Errorf("failed to ban user %s: %w", targetUser.ID, err),
-		}
+		return ErrNoSession
 	}
 
-	// Respond with success
-	successMsg := fmt.Sprintf("Successfully banned %s#%s. Reason: %s", targetUser.Username, targetUser.Discriminator, reason)
+	prompt := ctx.T(i18n.MsgBanPrompt, targetUser.Username, reason)
 	if deleteDays > 0 {
-		successMsg += fmt.Sprintf(" (Deleted %d days of messages)", deleteDays)
+		prompt += ctx.T(i18n.MsgBanPromptDeletes, deleteDays)
 	}
-	return ctx.RespondEphemeral(successMsg)
+
+	return c.Confirmer.Confirm(ctx, prompt, func(reply func(string) error) error {
+		// Notify before banning, while the member can still be messaged
+		var notified bool
+		if c.NotifyTarget {
+			notified = notifyTarget(ctx, guildID, targetUser, "banned from", reason, 0)
+		}
+
+		// Perform the ban
+		err := retryOnRateLimit(func(opts ...discordgo.RequestOption) error {
+			return ctx.Session.GuildBanCreateWithReason(guildID, targetUser.ID, reason, deleteDays, opts...)
+		})
+		if err != nil {
+			if msg, limited := rateLimitMessage(ctx, err); limited {
+				return errutil.UserFriendlyError{
+					UserMessage: msg,
+					Err:         fmt.Errorf("failed to ban user %s: %w", targetUser.ID, err),
+				}
+			}
+			if msg, forbidden := forbiddenMessage(ctx, err, i18n.MsgBanForbidden, targetUser.Username); forbidden {
+				return errutil.UserFriendlyError{
+					UserMessage: msg,
+					Err:         fmt.Errorf("failed to ban user %s: %w", targetUser.ID, err),
+				}
+			}
+			return errutil.UserFriendlyError{
+				UserMessage: ctx.T(i18n.MsgBanFailed, targetUser.Username),
+				Err:         fmt.Errorf("failed to ban user %s: %w", targetUser.ID, err),
+			}
+		}
+
+		modLog(ctx, guildID, ModLogEntry{Action: ModLogBanned, TargetID: targetUser.ID, Reason: reason})
+
+		// Respond with success
+		successMsg := ctx.T(i18n.MsgBanSuccess, targetUser.Username, targetUser.Discriminator, reason)
+		if deleteDays > 0 {
+			successMsg += ctx.T(i18n.MsgBanDeleted, deleteDays)
+		}
+		if c.NotifyTarget {
+			successMsg += notifyNote(ctx, notified)
+		}
+		return reply(successMsg)
+	})
 }
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"jamesbot/internal/warnings"
+	"jamesbot/pkg/errutil"
+)
+
+// ClearWarningsCommand implements a command to delete a member's warnings.
+// It requires the Moderate Members permission to execute.
+type ClearWarningsCommand struct {
+	// Warnings is the store to clear. When nil, the command reports that
+	// warning tracking is unavailable.
+	Warnings *warnings.Store
+}
+
+// Name returns the command name.
+func (c *ClearWarningsCommand) Name() string {
+	return "clearwarnings"
+}
+
+// Description returns the command description.
+func (c *ClearWarningsCommand) Description() string {
+	return "Clear all warnings for a member"
+}
+
+// Permissions returns the required Discord permissions.
+// Users must have the Moderate Members permission to execute this command.
+func (c *ClearWarningsCommand) Permissions() int64 {
+	return discordgo.PermissionModerateMembers
+}
+
+// GuildOnly reports that the command only works in a server, which has
+// members to act on.
+func (c *ClearWarningsCommand) GuildOnly() bool {
+	return true
+}
+
+// Options returns the command options.
+// The clearwarnings command accepts the user whose warnings are cleared.
+func (c *ClearWarningsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Name:        "user",
+			Description: "The user whose warnings to clear",
+			Required:    true,
+		},
+	}
+}
+
+// Execute runs the clearwarnings command.
+// It deletes the member's warnings and reports how many were removed.
+func (c *ClearWarningsCommand) Execute(ctx *Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	// Get the target user
+	targetUser := ctx.UserOption("user")
+	if targetUser == nil {
+		return errutil.ValidationError{
+			Field:   "user",
+			Message: "user is required",
+		}
+	}
+
+	// Get guild ID; warnings are tracked per guild
+	guildID := ctx.GuildID()
+	if ctx.IsDM() {
+		return errutil.UserFriendlyError{
+			UserMessage: "This command can only be used in a server.",
+			Err:         fmt.Errorf("clearwarnings command used outside of guild"),
+		}
+	}
+
+	if c.Warnings == nil {
+		return errutil.UserFriendlyError{
+			UserMessage: "Warning tracking is not enabled.",
+			Err:         fmt.Errorf("clearwarnings command has no warning store"),
+		}
+	}
+
+	removed := c.Warnings.Clear(guildID, targetUser.ID)
+
+	ctx.Logger.Info().
+		Str("target_id", targetUser.ID).
+		Int("removed", removed).
+		Msg("cleared warnings")
+
+	var responseMsg string
+	switch removed {
+	case 0:
+		responseMsg = fmt.Sprintf("%s has no warnings to clear.", targetUser.Username)
+	case 1:
+		responseMsg = fmt.Sprintf("Cleared 1 warning for %s.", targetUser.Username)
+	default:
+		responseMsg = fmt.Sprintf("Cleared %d warnings for %s.", removed, targetUser.Username)
+	}
+
+	return ctx.RespondEphemeral(responseMsg)
+}
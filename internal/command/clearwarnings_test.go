@@ -0,0 +1,122 @@
+package command_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/warnings"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createClearWarningsInteraction creates an interaction targeting a resolved user.
+func createClearWarningsInteraction(executorID, targetUserID, guildID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "interaction-clearwarnings-test",
+			GuildID: guildID,
+			Member: &discordgo.Member{
+				User: &discordgo.User{ID: executorID, Username: "moderator"},
+			},
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{
+				ID:   "cmd-data-clearwarnings",
+				Name: "clearwarnings",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: targetUserID},
+				},
+				Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+					Users: map[string]*discordgo.User{
+						targetUserID: {ID: targetUserID, Username: "targetuser"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_ClearWarningsCommand_Metadata(t *testing.T) {
+	cmd := &command.ClearWarningsCommand{}
+
+	assert.Equal(t, "clearwarnings", cmd.Name())
+	assert.NotEmpty(t, cmd.Description())
+	assert.Equal(t, int64(discordgo.PermissionModerateMembers), cmd.Permissions())
+
+	options := cmd.Options()
+	require.Len(t, options, 1)
+	assert.Equal(t, "user", options[0].Name)
+	assert.True(t, options[0].Required)
+
+	var _ command.PermissionedCommand = (*command.ClearWarningsCommand)(nil)
+}
+
+func Test_ClearWarningsCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		existing     int
+		wantResponse string
+	}{
+		{name: "clears several warnings", existing: 3, wantResponse: "Cleared 3 warnings for targetuser."},
+		{name: "clears a single warning", existing: 1, wantResponse: "Cleared 1 warning for targetuser."},
+		{name: "nothing to clear", existing: 0, wantResponse: "targetuser has no warnings to clear."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := warnings.NewStore()
+			for i := 0; i < tt.existing; i++ {
+				store.Add(warnings.Warning{GuildID: "guild-789", UserID: "target-456"})
+			}
+			store.Add(warnings.Warning{GuildID: "guild-789", UserID: "other-user"})
+
+			session, rt := newRecordingSession(t)
+			ctx := command.NewContext(session, createClearWarningsInteraction("moderator-123", "target-456", "guild-789"), warnTestLogger())
+
+			require.NoError(t, (&command.ClearWarningsCommand{Warnings: store}).Execute(ctx))
+
+			assert.Equal(t, 0, store.Count("guild-789", "target-456"))
+			assert.Equal(t, 1, store.Count("guild-789", "other-user"), "other members are untouched")
+
+			var response discordgo.InteractionResponse
+			for _, req := range rt.recorded() {
+				if strings.HasSuffix(req.Path, "/callback") {
+					require.NoError(t, json.Unmarshal(req.Body, &response))
+				}
+			}
+			require.NotNil(t, response.Data)
+			assert.Equal(t, tt.wantResponse, response.Data.Content)
+			assert.Equal(t, discordgo.MessageFlagsEphemeral, response.Data.Flags)
+		})
+	}
+}
+
+func Test_ClearWarningsCommand_Execute_Errors(t *testing.T) {
+	t.Run("nil context", func(t *testing.T) {
+		assert.Error(t, (&command.ClearWarningsCommand{}).Execute(nil))
+	})
+
+	t.Run("outside a guild", func(t *testing.T) {
+		ctx := command.NewContext(nil, createClearWarningsInteraction("moderator-123", "target-456", ""), warnTestLogger())
+
+		err := (&command.ClearWarningsCommand{Warnings: warnings.NewStore()}).Execute(ctx)
+
+		var friendly errutil.UserFriendlyError
+		require.ErrorAs(t, err, &friendly)
+		assert.Contains(t, friendly.UserMessage, "server")
+	})
+
+	t.Run("no warning store", func(t *testing.T) {
+		ctx := command.NewContext(nil, createClearWarningsInteraction("moderator-123", "target-456", "guild-789"), warnTestLogger())
+
+		err := (&command.ClearWarningsCommand{}).Execute(ctx)
+
+		var friendly errutil.UserFriendlyError
+		require.ErrorAs(t, err, &friendly)
+		assert.Contains(t, friendly.UserMessage, "not enabled")
+	})
+}
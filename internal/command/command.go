@@ -34,3 +34,27 @@ type PermissionedCommand interface {
 	// Use discordgo.Permission* constants to construct this value.
 	Permissions() int64
 }
+
+// GuildOnlyCommand is an optional interface for commands that only make sense
+// in a server. The scope middleware answers uses in direct messages with an
+// error instead of running the command.
+type GuildOnlyCommand interface {
+	Command
+
+	// GuildOnly reports whether the command may only be used in a server.
+	GuildOnly() bool
+}
+
+// DMOnlyCommand is an optional interface for commands that only make sense in
+// direct messages. The scope middleware answers uses in a server with an
+// error instead of running the command.
+type DMOnlyCommand interface {
+	Command
+
+	// DMOnly reports whether the command may only be used in direct messages.
+	DMOnly() bool
+}
+
+// ModerationCommands names the built-in commands that act on members. Their
+// executions are audit records, so they are never dropped by log sampling.
+var ModerationCommands = []string{"kick", "ban", "mute", "warn", "clearwarnings"}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// ComponentIDSeparator separates a component custom ID's routing key from its
+// data, as in "confirm:12345".
+const ComponentIDSeparator = ":"
+
+// ComponentHandler handles a message component interaction, such as a button
+// click. It returns an error if handling fails.
+type ComponentHandler func(ctx *Context) error
+
+// ComponentRegistry routes message component interactions to handlers by the
+// component's custom ID. A handler registered for "key" receives both the
+// custom ID "key" and any "key:data" custom ID that has no exact registration,
+// so components can carry per-message data. It is safe for concurrent use.
+type ComponentRegistry struct {
+	handlers map[string]ComponentHandler
+	mu       sync.RWMutex
+	logger   zerolog.Logger
+}
+
+// NewComponentRegistry creates an empty component registry.
+func NewComponentRegistry(logger zerolog.Logger) *ComponentRegistry {
+	return &ComponentRegistry{
+		handlers: make(map[string]ComponentHandler),
+		logger:   logger,
+	}
+}
+
+// Register routes custom IDs matching key to handler. It returns an error
+// wrapping ErrNilComponentHandler if handler is nil, or ErrCommandExists if
+// key is already registered.
+func (r *ComponentRegistry) Register(key string, handler ComponentHandler) error {
+	if handler == nil {
+		return fmt.Errorf("cannot register %w", ErrNilComponentHandler)
+	}
+	if key == "" {
+		return fmt.Errorf("cannot register component handler with empty key")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.handlers[key]; exists {
+		return fmt.Errorf("component handler %q is %w", key, ErrCommandExists)
+	}
+
+	r.handlers[key] = handler
+	r.logger.Debug().Str("component", key).Msg("registered component handler")
+
+	return nil
+}
+
+// Unregister removes the handler for key. It returns false if none was registered.
+func (r *ComponentRegistry) Unregister(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.handlers[key]; !exists {
+		return false
+	}
+	delete(r.handlers, key)
+	return true
+}
+
+// Get returns the handler for customID: the handler registered for the whole
+// ID if there is one, otherwise the handler registered for its key.
+func (r *ComponentRegistry) Get(customID string) (ComponentHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if handler, ok := r.handlers[customID]; ok {
+		return handler, true
+	}
+	if key, _, found := strings.Cut(customID, ComponentIDSeparator); found {
+		handler, ok := r.handlers[key]
+		return handler, ok
+	}
+	return nil, false
+}
+
+// ComponentID builds a custom ID that routes to the handler registered for key
+// and carries data, which Context.ComponentData returns.
+func ComponentID(key, data string) string {
+	return key + ComponentIDSeparator + data
+}
@@ -0,0 +1,203 @@
+package command_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// componentInteraction creates a button click with the given custom ID.
+func componentInteraction(customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "interaction-1",
+			Token:   "token-1",
+			GuildID: "guild-1",
+			Type:    discordgo.InteractionMessageComponent,
+			Member:  &discordgo.Member{User: &discordgo.User{ID: "user-1"}},
+			Data:    discordgo.MessageComponentInteractionData{CustomID: customID, ComponentType: discordgo.ButtonComponent},
+		},
+	}
+}
+
+// ============================================================================
+// ComponentRegistry Tests
+// ============================================================================
+
+func Test_ComponentRegistry_Get(t *testing.T) {
+	registry := command.NewComponentRegistry(testLogger())
+	called := ""
+	handlerFor := func(name string) command.ComponentHandler {
+		return func(ctx *command.Context) error {
+			called = name
+			return nil
+		}
+	}
+	require.NoError(t, registry.Register("confirm", handlerFor("confirm")))
+	require.NoError(t, registry.Register("confirm:special", handlerFor("special")))
+
+	tests := []struct {
+		name     string
+		customID string
+		want     string
+		found    bool
+	}{
+		{name: "exact key", customID: "confirm", want: "confirm", found: true},
+		{name: "key with data", customID: command.ComponentID("confirm", "123"), want: "confirm", found: true},
+		{name: "exact ID wins over key", customID: "confirm:special", want: "special", found: true},
+		{name: "unknown key", customID: "cancel:123", found: false},
+		{name: "key prefix without separator", customID: "confirmed", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = ""
+			handler, found := registry.Get(tt.customID)
+
+			require.Equal(t, tt.found, found)
+			if !found {
+				return
+			}
+			require.NoError(t, handler(nil))
+			assert.Equal(t, tt.want, called)
+		})
+	}
+}
+
+func Test_ComponentRegistry_Register(t *testing.T) {
+	registry := command.NewComponentRegistry(testLogger())
+	noop := func(ctx *command.Context) error { return nil }
+
+	require.NoError(t, registry.Register("confirm", noop))
+
+	err := registry.Register("confirm", noop)
+	assert.True(t, errors.Is(err, command.ErrCommandExists), "duplicate keys should be rejected")
+
+	err = registry.Register("cancel", nil)
+	assert.True(t, errors.Is(err, command.ErrNilComponentHandler))
+
+	assert.Error(t, registry.Register("", noop))
+
+	assert.True(t, registry.Unregister("confirm"))
+	assert.False(t, registry.Unregister("confirm"))
+	_, found := registry.Get("confirm")
+	assert.False(t, found)
+}
+
+// ============================================================================
+// Context Component Tests
+// ============================================================================
+
+func Test_Context_ComponentData(t *testing.T) {
+	tests := []struct {
+		name         string
+		interaction  *discordgo.InteractionCreate
+		wantCustomID string
+		wantData     string
+	}{
+		{
+			name:         "custom ID with data",
+			interaction:  componentInteraction("confirm:abc:def"),
+			wantCustomID: "confirm:abc:def",
+			wantData:     "abc:def",
+		},
+		{
+			name:         "custom ID without data",
+			interaction:  componentInteraction("confirm"),
+			wantCustomID: "confirm",
+		},
+		{
+			name:        "application command",
+			interaction: createTestInteractionCreate("user-1", "guild-1", "channel-1", nil),
+		},
+		{
+			name: "nil interaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := command.NewContext(nil, tt.interaction, testLogger())
+
+			assert.Equal(t, tt.wantCustomID, ctx.CustomID())
+			assert.Equal(t, tt.wantData, ctx.ComponentData())
+		})
+	}
+}
+
+func Test_Context_RespondComponents(t *testing.T) {
+	row := discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "Yes", Style: discordgo.DangerButton, CustomID: "confirm:1"},
+	}}
+
+	tests := []struct {
+		name      string
+		ephemeral bool
+		wantFlags discordgo.MessageFlags
+	}{
+		{name: "public", ephemeral: false},
+		{name: "ephemeral", ephemeral: true, wantFlags: discordgo.MessageFlagsEphemeral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			ctx := command.NewContext(s, createTestInteractionCreate("user-1", "guild-1", "channel-1", nil), testLogger())
+
+			require.NoError(t, ctx.RespondComponents("Sure?", tt.ephemeral, row))
+
+			requests := rt.recorded()
+			require.Len(t, requests, 1)
+			var body struct {
+				Type int `json:"type"`
+				Data struct {
+					Content    string                   `json:"content"`
+					Flags      discordgo.MessageFlags   `json:"flags"`
+					Components []map[string]interface{} `json:"components"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(requests[0].Body, &body))
+			assert.Equal(t, int(discordgo.InteractionResponseChannelMessageWithSource), body.Type)
+			assert.Equal(t, "Sure?", body.Data.Content)
+			assert.Equal(t, tt.wantFlags, body.Data.Flags)
+			require.Len(t, body.Data.Components, 1)
+			assert.Contains(t, string(requests[0].Body), `"custom_id":"confirm:1"`)
+		})
+	}
+}
+
+func Test_Context_UpdateMessage(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	ctx := command.NewContext(s, componentInteraction("confirm:1"), testLogger())
+
+	require.NoError(t, ctx.UpdateMessage("Done."))
+
+	requests := rt.recorded()
+	require.Len(t, requests, 1)
+	assert.Contains(t, requests[0].Path, "/interactions/interaction-1/token-1/callback")
+	var body struct {
+		Type int `json:"type"`
+		Data struct {
+			Content    string            `json:"content"`
+			Components []json.RawMessage `json:"components"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(requests[0].Body, &body))
+	assert.Equal(t, int(discordgo.InteractionResponseUpdateMessage), body.Type)
+	assert.Equal(t, "Done.", body.Data.Content)
+	assert.NotNil(t, body.Data.Components, "components should be cleared, not left unchanged")
+	assert.Empty(t, body.Data.Components)
+}
+
+func Test_Context_ComponentResponsesNeedSession(t *testing.T) {
+	ctx := command.NewContext(nil, componentInteraction("confirm"), testLogger())
+
+	assert.Error(t, ctx.RespondComponents("Sure?", true))
+	assert.Error(t, ctx.UpdateMessage("Done."))
+}
@@ -0,0 +1,235 @@
+package command
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ConfirmComponentKey is the component key of confirmation buttons. Register
+// Confirmer.HandleComponent for it so the buttons work.
+const ConfirmComponentKey = "confirm"
+
+// DefaultConfirmTimeout is how long a confirmation prompt waits for an answer.
+const DefaultConfirmTimeout = time.Minute
+
+// Button answers carried in a confirmation button's custom ID.
+const (
+	confirmYes = "yes"
+	confirmNo  = "no"
+)
+
+// Answers to a confirmation prompt, as reported to a ConfirmationObserver.
+const (
+	ConfirmAnswerConfirmed = "confirmed"
+	ConfirmAnswerCancelled = "cancelled"
+	ConfirmAnswerExpired   = "expired"
+)
+
+// ConfirmedAction performs an action the invoker has confirmed. It reports
+// the outcome through reply, which replaces the prompt when one was shown.
+type ConfirmedAction func(reply func(content string) error) error
+
+// ConfirmationOutcome describes how a confirmation prompt was answered.
+type ConfirmationOutcome struct {
+	// Answer is ConfirmAnswerConfirmed, ConfirmAnswerCancelled, or
+	// ConfirmAnswerExpired.
+	Answer string
+
+	// Err is the error the confirmed action returned, if any.
+	Err error
+
+	// Duration is how long the confirmed action took to run.
+	Duration time.Duration
+}
+
+// ConfirmationObserver is told the outcome of each confirmation prompt.
+// prompt is the context of the command that showed it, so the observer can
+// tell which command, invoker, and options the answer applies to.
+type ConfirmationObserver func(prompt *Context, outcome ConfirmationOutcome)
+
+// ConfirmerOption configures a Confirmer.
+type ConfirmerOption func(*Confirmer)
+
+// WithConfirmationObserver has observer told the outcome of every prompt, such
+// as to audit the actions that ran once confirmed, which the command's own
+// execution, ending when the prompt is shown, does not cover.
+func WithConfirmationObserver(observer ConfirmationObserver) ConfirmerOption {
+	return func(c *Confirmer) {
+		c.observer = observer
+	}
+}
+
+// Confirmer asks the invoker of a destructive command to confirm it with
+// Confirm and Cancel buttons before it runs. Prompts are ephemeral, answered
+// only by the invoker, and cancel themselves after a timeout. It is safe for
+// concurrent use; a nil Confirmer runs every action without asking.
+type Confirmer struct {
+	timeout  time.Duration
+	observer ConfirmationObserver
+
+	mu      sync.Mutex
+	pending map[string]*pendingConfirmation
+}
+
+type pendingConfirmation struct {
+	invokerID string
+	prompt    *Context
+	action    ConfirmedAction
+	timer     *time.Timer
+}
+
+// NewConfirmer creates a Confirmer whose prompts cancel after timeout, or
+// DefaultConfirmTimeout if timeout is not positive. Options are applied after
+// it is built.
+func NewConfirmer(timeout time.Duration, opts ...ConfirmerOption) *Confirmer {
+	if timeout <= 0 {
+		timeout = DefaultConfirmTimeout
+	}
+	c := &Confirmer{
+		timeout: timeout,
+		pending: make(map[string]*pendingConfirmation),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Confirm shows ctx's invoker prompt with Confirm and Cancel buttons and runs
+// action once they confirm. With a nil Confirmer, action runs immediately and
+// replies ephemerally to ctx.
+func (c *Confirmer) Confirm(ctx *Context, prompt string, action ConfirmedAction) error {
+	if c == nil {
+		return action(ctx.RespondEphemeral)
+	}
+
+	id, err := newConfirmationID()
+	if err != nil {
+		return fmt.Errorf("failed to create confirmation: %w", err)
+	}
+
+	p := &pendingConfirmation{invokerID: ctx.UserID(), prompt: ctx, action: action}
+	c.mu.Lock()
+	c.pending[id] = p
+	c.mu.Unlock()
+
+	row := discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: ctx.T(i18n.MsgConfirmButton), Style: discordgo.DangerButton, CustomID: ComponentID(ConfirmComponentKey, id+ComponentIDSeparator+confirmYes)},
+		discordgo.Button{Label: ctx.T(i18n.MsgCancelButton), Style: discordgo.SecondaryButton, CustomID: ComponentID(ConfirmComponentKey, id+ComponentIDSeparator+confirmNo)},
+	}}
+	if err := ctx.RespondComponents(prompt, true, row); err != nil {
+		c.take(id)
+		return err
+	}
+	ctx.awaitingConfirmation.Store(true)
+
+	c.mu.Lock()
+	if _, ok := c.pending[id]; ok {
+		p.timer = time.AfterFunc(c.timeout, func() { c.expire(id) })
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// HandleComponent answers a click on a confirmation button. Register it as
+// the component handler for ConfirmComponentKey.
+func (c *Confirmer) HandleComponent(ctx *Context) error {
+	id, answer, _ := strings.Cut(ctx.ComponentData(), ComponentIDSeparator)
+
+	c.mu.Lock()
+	p, ok := c.pending[id]
+	if ok && p.invokerID != ctx.UserID() {
+		c.mu.Unlock()
+		return ctx.RespondEphemeral(ctx.T(i18n.MsgConfirmNotInvoker))
+	}
+	c.mu.Unlock()
+
+	// Another click or the timeout may have answered it in the meantime
+	if !ok || c.take(id) == nil {
+		return ctx.UpdateMessage(ctx.T(i18n.MsgConfirmExpired))
+	}
+
+	if answer != confirmYes {
+		c.observe(p.prompt, ConfirmationOutcome{Answer: ConfirmAnswerCancelled})
+		return ctx.UpdateMessage(ctx.T(i18n.MsgConfirmCancelled))
+	}
+
+	start := time.Now()
+	err := p.action(func(content string) error {
+		return ctx.UpdateMessage(content)
+	})
+	c.observe(p.prompt, ConfirmationOutcome{Answer: ConfirmAnswerConfirmed, Err: err, Duration: time.Since(start)})
+	return err
+}
+
+// observe tells the observer, if any, how the prompt shown to prompt was
+// answered.
+func (c *Confirmer) observe(prompt *Context, outcome ConfirmationOutcome) {
+	if c.observer != nil {
+		c.observer(prompt, outcome)
+	}
+}
+
+// take removes and returns the pending confirmation id, stopping its timer.
+// It returns nil if the confirmation was already answered or expired.
+func (c *Confirmer) take(id string) *pendingConfirmation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.pending[id]
+	if !ok {
+		return nil
+	}
+	delete(c.pending, id)
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	return p
+}
+
+// expire cancels an unanswered confirmation, replacing its prompt so the
+// buttons can no longer be clicked.
+func (c *Confirmer) expire(id string) {
+	p := c.take(id)
+	if p == nil {
+		return
+	}
+	c.observe(p.prompt, ConfirmationOutcome{Answer: ConfirmAnswerExpired})
+
+	if err := p.prompt.EditResponse(p.prompt.T(i18n.MsgConfirmTimeout)); err != nil {
+		p.prompt.Logger.Debug().Err(err).Msg("failed to mark confirmation as timed out")
+	}
+}
+
+// AwaitingConfirmation reports whether a Confirmer prompted the invoker to
+// confirm the command's action, so it runs only once they answer, if at all.
+func (c *Context) AwaitingConfirmation() bool {
+	return c != nil && c.awaitingConfirmation.Load()
+}
+
+// Pending returns the number of confirmations awaiting an answer.
+func (c *Confirmer) Pending() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// newConfirmationID returns a random ID that cannot be guessed from other prompts.
+func newConfirmationID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
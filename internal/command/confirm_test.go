@@ -0,0 +1,251 @@
+package command_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// promptButtons returns the custom IDs of the Confirm and Cancel buttons in a
+// recorded confirmation prompt, and asserts the prompt is ephemeral.
+func promptButtons(t *testing.T, req recordedRequest) (confirmID, cancelID string) {
+	t.Helper()
+
+	var body struct {
+		Data struct {
+			Flags      discordgo.MessageFlags `json:"flags"`
+			Components []struct {
+				Components []struct {
+					Label    string `json:"label"`
+					CustomID string `json:"custom_id"`
+				} `json:"components"`
+			} `json:"components"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(req.Body, &body))
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, body.Data.Flags, "prompts should be ephemeral")
+	require.Len(t, body.Data.Components, 1)
+	buttons := body.Data.Components[0].Components
+	require.Len(t, buttons, 2)
+	assert.Equal(t, "Confirm", buttons[0].Label)
+	assert.Equal(t, "Cancel", buttons[1].Label)
+	return buttons[0].CustomID, buttons[1].CustomID
+}
+
+// clickBy creates a click on the button with customID by userID.
+func clickBy(customID, userID string) *discordgo.InteractionCreate {
+	click := componentInteraction(customID)
+	click.Member.User.ID = userID
+	return click
+}
+
+// updatedContent returns the content of a recorded message update response.
+func updatedContent(t *testing.T, req recordedRequest) string {
+	t.Helper()
+	var resp discordgo.InteractionResponse
+	require.NoError(t, json.Unmarshal(req.Body, &resp))
+	require.NotNil(t, resp.Data)
+	return resp.Data.Content
+}
+
+// ============================================================================
+// Confirmer Tests
+// ============================================================================
+
+func Test_Confirmer_Nil(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	ctx := command.NewContext(s, createTestInteractionCreate("user-1", "guild-1", "channel-1", nil), testLogger())
+
+	var c *command.Confirmer
+	err := c.Confirm(ctx, "Sure?", func(reply func(string) error) error {
+		return reply("Done.")
+	})
+
+	require.NoError(t, err)
+	requests := rt.recorded()
+	require.Len(t, requests, 1, "a nil Confirmer should act without prompting")
+	var resp discordgo.InteractionResponse
+	require.NoError(t, json.Unmarshal(requests[0].Body, &resp))
+	assert.Equal(t, "Done.", resp.Data.Content)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, resp.Data.Flags)
+	assert.Zero(t, c.Pending())
+	assert.False(t, ctx.AwaitingConfirmation(), "an action run without prompting is not awaiting confirmation")
+}
+
+func Test_Confirmer_Answers(t *testing.T) {
+	tests := []struct {
+		name        string
+		clicker     string
+		cancel      bool
+		wantRan     bool
+		wantContent string
+		wantPending int
+		wantAnswers []string
+	}{
+		{
+			name:        "invoker confirms",
+			clicker:     "user-1",
+			wantRan:     true,
+			wantContent: "Done.",
+			wantAnswers: []string{command.ConfirmAnswerConfirmed},
+		},
+		{
+			name:        "invoker cancels",
+			clicker:     "user-1",
+			cancel:      true,
+			wantContent: "Cancelled; nothing was done.",
+			wantAnswers: []string{command.ConfirmAnswerCancelled},
+		},
+		{
+			name:        "someone else cannot answer",
+			clicker:     "user-2",
+			wantContent: "Only the person who ran this command can answer it.",
+			wantPending: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			var answers []string
+			c := command.NewConfirmer(time.Minute, command.WithConfirmationObserver(func(prompt *command.Context, outcome command.ConfirmationOutcome) {
+				assert.Equal(t, "user-1", prompt.UserID(), "the observer is given the prompting command's context")
+				answers = append(answers, outcome.Answer)
+			}))
+			ctx := command.NewContext(s, createTestInteractionCreate("user-1", "guild-1", "channel-1", nil), testLogger())
+
+			ran := false
+			require.NoError(t, c.Confirm(ctx, "Sure?", func(reply func(string) error) error {
+				ran = true
+				return reply("Done.")
+			}))
+			assert.Equal(t, 1, c.Pending())
+			assert.True(t, ctx.AwaitingConfirmation())
+			confirmID, cancelID := promptButtons(t, rt.recorded()[0])
+
+			clicked := confirmID
+			if tt.cancel {
+				clicked = cancelID
+			}
+			click := command.NewContext(s, clickBy(clicked, tt.clicker), testLogger())
+			require.NoError(t, c.HandleComponent(click))
+
+			assert.Equal(t, tt.wantRan, ran)
+			assert.Equal(t, tt.wantPending, c.Pending())
+			assert.Equal(t, tt.wantAnswers, answers)
+			requests := rt.recorded()
+			require.Len(t, requests, 2)
+			assert.Equal(t, tt.wantContent, updatedContent(t, requests[1]))
+		})
+	}
+}
+
+func Test_Confirmer_AnswersOnce(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	c := command.NewConfirmer(time.Minute)
+	ctx := command.NewContext(s, createTestInteractionCreate("user-1", "guild-1", "channel-1", nil), testLogger())
+
+	runs := 0
+	require.NoError(t, c.Confirm(ctx, "Sure?", func(reply func(string) error) error {
+		runs++
+		return reply("Done.")
+	}))
+	confirmID, _ := promptButtons(t, rt.recorded()[0])
+
+	for range 2 {
+		require.NoError(t, c.HandleComponent(command.NewContext(s, clickBy(confirmID, "user-1"), testLogger())))
+	}
+
+	assert.Equal(t, 1, runs, "a confirmed action should run once")
+	assert.Equal(t, "This confirmation has expired.", updatedContent(t, rt.recorded()[2]))
+}
+
+func Test_Confirmer_ActionError(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	var outcome command.ConfirmationOutcome
+	c := command.NewConfirmer(time.Minute, command.WithConfirmationObserver(func(_ *command.Context, o command.ConfirmationOutcome) {
+		outcome = o
+	}))
+	ctx := command.NewContext(s, createTestInteractionCreate("user-1", "guild-1", "channel-1", nil), testLogger())
+
+	boom := errors.New("boom")
+	require.NoError(t, c.Confirm(ctx, "Sure?", func(reply func(string) error) error { return boom }))
+	confirmID, _ := promptButtons(t, rt.recorded()[0])
+
+	err := c.HandleComponent(command.NewContext(s, clickBy(confirmID, "user-1"), testLogger()))
+
+	assert.ErrorIs(t, err, boom, "action errors should reach the interaction handler")
+	assert.Equal(t, command.ConfirmAnswerConfirmed, outcome.Answer)
+	assert.ErrorIs(t, outcome.Err, boom, "action errors should reach the observer")
+}
+
+func Test_Confirmer_Timeout(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	answers := make(chan string, 1)
+	c := command.NewConfirmer(10*time.Millisecond, command.WithConfirmationObserver(func(_ *command.Context, outcome command.ConfirmationOutcome) {
+		answers <- outcome.Answer
+	}))
+	interaction := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+	interaction.Token = "prompt-token"
+	ctx := command.NewContext(s, interaction, testLogger())
+
+	require.NoError(t, c.Confirm(ctx, "Sure?", func(reply func(string) error) error {
+		t.Error("a timed-out action should not run")
+		return nil
+	}))
+	confirmID, _ := promptButtons(t, rt.recorded()[0])
+
+	require.Eventually(t, func() bool { return c.Pending() == 0 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool { return len(rt.recorded()) == 2 }, time.Second, 5*time.Millisecond)
+
+	edit := rt.recorded()[1]
+	assert.Equal(t, "PATCH", edit.Method)
+	assert.True(t, strings.HasSuffix(edit.Path, "/prompt-token/messages/@original"), edit.Path)
+	var body struct {
+		Content    string            `json:"content"`
+		Components []json.RawMessage `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(edit.Body, &body))
+	assert.Equal(t, "Timed out; nothing was done.", body.Content)
+	assert.NotNil(t, body.Components, "buttons should be removed")
+	assert.Empty(t, body.Components)
+	assert.Equal(t, command.ConfirmAnswerExpired, <-answers)
+
+	// A late click finds the prompt expired
+	require.NoError(t, c.HandleComponent(command.NewContext(s, clickBy(confirmID, "user-1"), testLogger())))
+	assert.Equal(t, "This confirmation has expired.", updatedContent(t, rt.recorded()[2]))
+}
+
+// ============================================================================
+// BanCommand Confirmation Tests
+// ============================================================================
+
+func Test_BanCommand_Confirmation(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	c := command.NewConfirmer(time.Minute)
+	cmd := &command.BanCommand{Confirmer: c}
+	interaction := createBanInteractionWithResolvedUser("mod-1", "target-1", "guild-1", "channel-1", 0, false, "spam", true, false)
+
+	require.NoError(t, cmd.Execute(command.NewContext(s, interaction, testLogger())))
+
+	requests := rt.recorded()
+	require.Len(t, requests, 1, "nothing should happen before confirmation")
+	assert.Contains(t, string(requests[0].Body), "Ban targetuser? Reason: spam")
+	confirmID, _ := promptButtons(t, requests[0])
+
+	require.NoError(t, c.HandleComponent(command.NewContext(s, clickBy(confirmID, "mod-1"), testLogger())))
+
+	requests = rt.recorded()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "PUT", requests[1].Method)
+	assert.Equal(t, "/api/v9/guilds/guild-1/bans/target-1", requests[1].Path)
+	assert.Contains(t, updatedContent(t, requests[2]), "Successfully banned targetuser")
+}
@@ -1,17 +1,40 @@
 package command
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"jamesbot/internal/i18n"
+	"jamesbot/internal/rules"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/zerolog"
 )
 
+// InteractionTokenLifetime is how long after an interaction is created that
+// Discord accepts responses and followups to it. A command still working
+// after that can no longer tell the user anything.
+const InteractionTokenLifetime = 15 * time.Minute
+
 // Context provides command execution context and helper methods.
 // It wraps the Discord session, interaction, and logger to provide
 // convenient access to command execution resources.
+//
+// Accessors work without a session. Methods that call Discord, such as the
+// Respond family, return an error wrapping ErrNoSession when Session is nil.
+// The first response answers the interaction and later ones are sent as
+// followup messages, so a Context is safe to respond to more than once and
+// from more than one goroutine.
 type Context struct {
-	// Session is the Discord session for API interactions.
+	// Session is the Discord session for API interactions. It may be nil,
+	// as in tests.
 	Session *discordgo.Session
 
 	// Interaction contains the interaction data from Discord.
@@ -19,81 +42,572 @@ type Context struct {
 
 	// Logger is a structured logger for command execution.
 	Logger zerolog.Logger
+
+	// Members caches members fetched by GuildMember. It may be nil.
+	Members *MemberCache
+
+	// Catalog holds the messages T translates. If nil, the built-in
+	// catalog is used.
+	Catalog *i18n.Catalog
+
+	// Settings holds the guild settings commands read, such as the mod-log
+	// channel. It may be nil, in which case every setting is empty.
+	Settings *rules.Set
+
+	// Message is the message that invoked a text command, or nil for slash
+	// commands and components. Responses to a text command are sent as
+	// replies to it.
+	Message *discordgo.Message
+
+	// reply is the reply sent to Message, kept for EditResponse.
+	reply atomic.Pointer[discordgo.Message]
+
+	// awaitingConfirmation is set once a Confirmer has prompted the invoker
+	// to confirm the command's action, which then runs only on their answer.
+	awaitingConfirmation atomic.Bool
+
+	// mu serializes responses, so concurrent ones, such as an AutoDefer
+	// deferral racing the command's own response, acknowledge the
+	// interaction once and follow up after that.
+	mu sync.Mutex
+	// deferred is set once Defer has acknowledged the interaction, and
+	// deferredEphemeral if only the invoker can see the deferred response.
+	deferred          bool
+	deferredEphemeral bool
+	// responded is set once the interaction or text command has a response,
+	// after which responses are followups and a deferral does nothing.
+	responded bool
+
+	// parent is the context set by WithContext, and ctx the one Context
+	// derives from it on first use, cancelled by cancel.
+	ctxMu  sync.Mutex
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// ContextOption is a functional option for configuring a Context created by NewContext.
+type ContextOption func(*Context)
+
+// WithResolvedUsers adds users to the interaction's resolved data, keyed by ID,
+// so UserOption finds them without a live session. It is intended for tests
+// and modifies the interaction passed to NewContext.
+func WithResolvedUsers(users ...*discordgo.User) ContextOption {
+	return func(c *Context) {
+		resolved := c.resolvedData()
+		if resolved == nil {
+			return
+		}
+		if resolved.Users == nil {
+			resolved.Users = make(map[string]*discordgo.User, len(users))
+		}
+		for _, u := range users {
+			if u != nil {
+				resolved.Users[u.ID] = u
+			}
+		}
+	}
+}
+
+// WithResolvedRoles adds roles to the interaction's resolved data, keyed by ID,
+// so RoleOption finds them without a live session. It is intended for tests
+// and modifies the interaction passed to NewContext.
+func WithResolvedRoles(roles ...*discordgo.Role) ContextOption {
+	return func(c *Context) {
+		resolved := c.resolvedData()
+		if resolved == nil {
+			return
+		}
+		if resolved.Roles == nil {
+			resolved.Roles = make(map[string]*discordgo.Role, len(roles))
+		}
+		for _, r := range roles {
+			if r != nil {
+				resolved.Roles[r.ID] = r
+			}
+		}
+	}
+}
+
+// WithResolvedChannels adds channels to the interaction's resolved data, keyed
+// by ID, so ChannelOption finds them without a live session. It is intended
+// for tests and modifies the interaction passed to NewContext.
+func WithResolvedChannels(channels ...*discordgo.Channel) ContextOption {
+	return func(c *Context) {
+		resolved := c.resolvedData()
+		if resolved == nil {
+			return
+		}
+		if resolved.Channels == nil {
+			resolved.Channels = make(map[string]*discordgo.Channel, len(channels))
+		}
+		for _, ch := range channels {
+			if ch != nil {
+				resolved.Channels[ch.ID] = ch
+			}
+		}
+	}
+}
+
+// WithMemberCache makes GuildMember check cache before asking Discord.
+func WithMemberCache(cache *MemberCache) ContextOption {
+	return func(c *Context) {
+		c.Members = cache
+	}
+}
+
+// WithGuildSettings makes commands read guild settings from settings.
+func WithGuildSettings(settings *rules.Set) ContextOption {
+	return func(c *Context) {
+		c.Settings = settings
+	}
+}
+
+// WithCatalog makes T look messages up in catalog.
+func WithCatalog(catalog *i18n.Catalog) ContextOption {
+	return func(c *Context) {
+		c.Catalog = catalog
+	}
+}
+
+// WithContext makes the context returned by Context derive from parent, so
+// commands see it cancelled when parent is, such as when the bot shuts down.
+func WithContext(parent context.Context) ContextOption {
+	return func(c *Context) {
+		c.parent = parent
+	}
+}
+
+// WithMessage makes the context respond to a text command invoked by m,
+// replying in its channel instead of responding to an interaction. The
+// interaction passed to NewContext is built from m by NewTextInteraction.
+func WithMessage(m *discordgo.Message) ContextOption {
+	return func(c *Context) {
+		c.Message = m
+	}
 }
 
 // NewContext creates a new command context with the provided components.
 // The logger will be enhanced with contextual fields for the command execution.
-func NewContext(s *discordgo.Session, i *discordgo.InteractionCreate, logger zerolog.Logger) *Context {
+// Options are applied after the context is built.
+func NewContext(s *discordgo.Session, i *discordgo.InteractionCreate, logger zerolog.Logger, opts ...ContextOption) *Context {
+	var ctx *Context
 	if i == nil {
-		return &Context{
+		ctx = &Context{
 			Session:     s,
 			Interaction: nil,
 			Logger:      logger,
 		}
+	} else {
+		// Enhance logger with context
+		contextLogger := logger.With().
+			Str("guild_id", guildIDFromInteraction(i)).
+			Str("channel_id", channelIDFromInteraction(i)).
+			Str("user_id", userIDFromInteraction(i)).
+			Logger()
+
+		ctx = &Context{
+			Session:     s,
+			Interaction: i,
+			Logger:      contextLogger,
+		}
+	}
+
+	for _, opt := range opts {
+		opt(ctx)
 	}
 
-	// Enhance logger with context
-	contextLogger := logger.With().
-		Str("guild_id", guildIDFromInteraction(i)).
-		Str("channel_id", channelIDFromInteraction(i)).
-		Str("user_id", userIDFromInteraction(i)).
-		Logger()
+	return ctx
+}
 
-	return &Context{
-		Session:     s,
-		Interaction: i,
-		Logger:      contextLogger,
+// resolvedData returns the interaction's resolved data, creating it if needed.
+// Returns nil if the interaction is not an application command.
+func (c *Context) resolvedData() *discordgo.ApplicationCommandInteractionDataResolved {
+	if c.Interaction == nil || c.Interaction.Interaction == nil {
+		return nil
 	}
+
+	data, ok := c.Interaction.Data.(discordgo.ApplicationCommandInteractionData)
+	if !ok {
+		return nil
+	}
+
+	if data.Resolved == nil {
+		data.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{}
+		c.Interaction.Data = data
+	}
+
+	return data.Resolved
 }
 
 // Respond sends a response message to the interaction.
 // This creates a public response visible to all users in the channel.
 func (c *Context) Respond(content string) error {
-	if c.Session == nil || c.Interaction == nil {
-		return fmt.Errorf("cannot respond: session or interaction is nil")
-	}
-
-	return c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: content,
-		},
+	return c.respond(&discordgo.InteractionResponseData{
+		Content: content,
 	})
 }
 
 // RespondEphemeral sends an ephemeral response message to the interaction.
 // This creates a private response visible only to the user who invoked the command.
+// Text commands cannot respond privately, so for them it replies publicly.
 func (c *Context) RespondEphemeral(content string) error {
-	if c.Session == nil || c.Interaction == nil {
-		return fmt.Errorf("cannot respond: session or interaction is nil")
-	}
-
-	return c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: content,
-			Flags:   discordgo.MessageFlagsEphemeral,
-		},
+	return c.respond(&discordgo.InteractionResponseData{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
 	})
 }
 
 // RespondEmbed sends an embed response to the interaction.
 // This creates a public response with a rich embed visible to all users.
 func (c *Context) RespondEmbed(embed *discordgo.MessageEmbed) error {
-	if c.Session == nil || c.Interaction == nil {
-		return fmt.Errorf("cannot respond: session or interaction is nil")
-	}
-
 	if embed == nil {
 		return fmt.Errorf("embed cannot be nil")
 	}
 
-	return c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+	return c.respond(&discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	})
+}
+
+// RespondComponents sends a response message with message components, such as
+// action rows of buttons. An ephemeral response is visible only to the user
+// who invoked the command. Clicks are routed through the ComponentRegistry.
+func (c *Context) RespondComponents(content string, ephemeral bool, components ...discordgo.MessageComponent) error {
+	data := &discordgo.InteractionResponseData{
+		Content:    content,
+		Components: components,
+	}
+	if ephemeral {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+
+	return c.respond(data)
+}
+
+// MaxFileSize is the largest file, in bytes, that RespondFile sends: the
+// upload limit Discord applies to bots in servers without boosts.
+const MaxFileSize = 10 << 20
+
+// RespondFile sends a response with data attached as a file called name, such
+// as an export members can download. Its visibility follows the deferral, if
+// any, so deferring ephemerally first keeps the file private.
+//
+// Returns an error wrapping ErrFileTooLarge if data is larger than
+// MaxFileSize, or ErrNoSession if there is no session.
+func (c *Context) RespondFile(name string, data []byte) error {
+	file, err := NewFile(name, data)
+	if err != nil {
+		return err
+	}
+	return c.respond(&discordgo.InteractionResponseData{
+		Files: []*discordgo.File{file},
+	})
+}
+
+// NewFile prepares data to be attached to a response as a file called name,
+// with a content type guessed from the name's extension. It returns an error
+// if name is empty or data is larger than MaxFileSize.
+func NewFile(name string, data []byte) (*discordgo.File, error) {
+	if name == "" {
+		return nil, fmt.Errorf("file name cannot be empty")
+	}
+	if len(data) > MaxFileSize {
+		return nil, fmt.Errorf("cannot attach %s: %w (%d bytes, at most %d)", name, ErrFileTooLarge, len(data), MaxFileSize)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &discordgo.File{
+		Name:        name,
+		ContentType: contentType,
+		Reader:      bytes.NewReader(data),
+	}, nil
+}
+
+// respond sends data as the response to the interaction or, for a text
+// command, as a reply to the message that invoked it. Replies mention no one,
+// so echoed text cannot ping members.
+//
+// Discord accepts one response to an interaction, so once it has responded,
+// later responses are sent as followup messages.
+func (c *Context) respond(data *discordgo.InteractionResponseData) error {
+	if c.Session == nil {
+		return fmt.Errorf("cannot respond: %w", ErrNoSession)
+	}
+	if c.Interaction == nil {
+		return fmt.Errorf("cannot respond: interaction is nil")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Message != nil {
+		reply, err := c.Session.ChannelMessageSendComplex(c.Message.ChannelID, &discordgo.MessageSend{
+			Content:         data.Content,
+			Embeds:          data.Embeds,
+			Components:      data.Components,
+			Files:           data.Files,
+			Reference:       c.Message.Reference(),
+			AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+		})
+		if err != nil {
+			return err
+		}
+		c.reply.Store(reply)
+		c.responded = true
+		return nil
+	}
+
+	switch {
+	case c.responded:
+		_, err := c.Session.FollowupMessageCreate(c.Interaction.Interaction, true, webhookParams(data))
+		return err
+	case c.deferred:
+		if err := c.completeDeferred(data); err != nil {
+			return err
+		}
+	default:
+		if err := c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: data,
+		}); err != nil {
+			return err
+		}
+	}
+	c.responded = true
+	return nil
+}
+
+// Responded reports whether the command has responded, or deferred its
+// response, so another response would be sent as a followup.
+func (c *Context) Responded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deferred || c.responded
+}
+
+// Context returns the context for the command's work. For an interaction,
+// its deadline is when the interaction's token expires,
+// InteractionTokenLifetime after Discord created it, so long-running commands,
+// such as those working through a batch, can check Deadline() and stop while
+// they can still respond; by the time the context is done, they cannot. Text
+// commands, which have no such limit, get no deadline. The context is
+// cancelled once the command returns.
+func (c *Context) Context() context.Context {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+	if c.ctx == nil {
+		parent := c.parent
+		if parent == nil {
+			parent = context.Background()
+		}
+		if deadline, ok := c.interactionDeadline(); ok {
+			c.ctx, c.cancel = context.WithDeadline(parent, deadline)
+		} else {
+			c.ctx, c.cancel = context.WithCancel(parent)
+		}
+	}
+	return c.ctx
+}
+
+// Release cancels the context returned by Context, stopping its deadline
+// timer. The interaction handler calls it once the command returns.
+func (c *Context) Release() {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// interactionDeadline returns when the interaction's token expires, read from
+// the creation time encoded in its ID. It reports false for text commands and
+// interactions without a valid ID.
+func (c *Context) interactionDeadline() (time.Time, bool) {
+	if c.Message != nil || c.Interaction == nil || c.Interaction.Interaction == nil {
+		return time.Time{}, false
+	}
+	created, err := discordgo.SnowflakeTimestamp(c.Interaction.ID)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created.Add(InteractionTokenLifetime), true
+}
+
+// webhookParams converts response data to a followup message.
+func webhookParams(data *discordgo.InteractionResponseData) *discordgo.WebhookParams {
+	return &discordgo.WebhookParams{
+		Content:    data.Content,
+		Embeds:     data.Embeds,
+		Components: data.Components,
+		Files:      data.Files,
+		Flags:      data.Flags,
+	}
+}
+
+// Defer acknowledges the interaction without a response yet, showing the
+// invoker that the bot is thinking, for commands that may take longer than
+// the three seconds Discord allows before a response. An ephemeral deferral is
+// shown only to the invoker.
+//
+// The next Respond, RespondEphemeral, RespondEmbed, RespondComponents, or
+// RespondFile call replaces the deferred response, and any after it are
+// followups. Its visibility was fixed by Defer, except that an ephemeral
+// response to a public deferral is sent privately and the deferred response
+// removed. Deferring a text command, which has no deadline, or an interaction
+// that was already deferred or responded to does nothing.
+func (c *Context) Defer(ephemeral bool) error {
+	if c.Session == nil {
+		return fmt.Errorf("cannot defer: %w", ErrNoSession)
+	}
+	if c.Interaction == nil {
+		return fmt.Errorf("cannot defer: interaction is nil")
+	}
+	if c.Message != nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deferred || c.responded {
+		return nil
+	}
+
+	data := &discordgo.InteractionResponseData{}
+	if ephemeral {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+	if err := c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: data,
+	}); err != nil {
+		return err
+	}
+	c.deferred = true
+	c.deferredEphemeral = ephemeral
+	return nil
+}
+
+// completeDeferred sends data as the response to a deferred interaction by
+// editing the deferred response, or, when data is ephemeral but the deferral
+// was public, by sending it as an ephemeral followup and deleting the
+// deferred response. The caller must hold c.mu.
+func (c *Context) completeDeferred(data *discordgo.InteractionResponseData) error {
+	if data.Flags&discordgo.MessageFlagsEphemeral != 0 && !c.deferredEphemeral {
+		if _, err := c.Session.FollowupMessageCreate(c.Interaction.Interaction, true, webhookParams(data)); err != nil {
+			return err
+		}
+		if err := c.Session.InteractionResponseDelete(c.Interaction.Interaction); err != nil {
+			c.Logger.Warn().Err(err).Msg("failed to delete deferred response")
+		}
+		return nil
+	}
+
+	edit := &discordgo.WebhookEdit{Content: &data.Content}
+	if data.Embeds != nil {
+		edit.Embeds = &data.Embeds
+	}
+	if data.Components != nil {
+		edit.Components = &data.Components
+	}
+	edit.Files = data.Files
+	_, err := c.Session.InteractionResponseEdit(c.Interaction.Interaction, edit)
+	return err
+}
+
+// EditResponse replaces the content and components of the response already
+// sent for the command, such as to disable buttons that are no longer
+// useful. Passing no components removes them.
+func (c *Context) EditResponse(content string, components ...discordgo.MessageComponent) error {
+	if c.Session == nil {
+		return fmt.Errorf("cannot edit response: %w", ErrNoSession)
+	}
+	if c.Interaction == nil {
+		return fmt.Errorf("cannot edit response: interaction is nil")
+	}
+
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+
+	if c.Message == nil {
+		_, err := c.Session.InteractionResponseEdit(c.Interaction.Interaction, &discordgo.WebhookEdit{
+			Content:    &content,
+			Components: &components,
+		})
+		return err
+	}
+
+	reply := c.reply.Load()
+	if reply == nil {
+		return fmt.Errorf("cannot edit response: no response has been sent")
+	}
+	_, err := c.Session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         reply.ID,
+		Channel:    reply.ChannelID,
+		Content:    &content,
+		Components: &components,
+	})
+	return err
+}
+
+// UpdateMessage responds to a component interaction by editing the message
+// the component is attached to, replacing its content and components.
+// Passing no components removes them, so buttons cannot be clicked again.
+// It must be the interaction's only response; after another it returns an
+// error wrapping ErrAlreadyResponded.
+func (c *Context) UpdateMessage(content string, components ...discordgo.MessageComponent) error {
+	if c.Session == nil {
+		return fmt.Errorf("cannot update message: %w", ErrNoSession)
+	}
+	if c.Interaction == nil {
+		return fmt.Errorf("cannot update message: interaction is nil")
+	}
+
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deferred || c.responded {
+		return fmt.Errorf("cannot update message: %w", ErrAlreadyResponded)
+	}
+
+	if err := c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
+			Content:    content,
+			Components: components,
 		},
-	})
+	}); err != nil {
+		return err
+	}
+	c.responded = true
+	return nil
+}
+
+// CustomID returns the custom ID of the component that was used.
+// Returns an empty string if the interaction is not a component interaction.
+func (c *Context) CustomID() string {
+	if c.Interaction == nil || c.Interaction.Interaction == nil {
+		return ""
+	}
+	data, ok := c.Interaction.Data.(discordgo.MessageComponentInteractionData)
+	if !ok {
+		return ""
+	}
+	return data.CustomID
+}
+
+// ComponentData returns the data carried in the custom ID of the component
+// that was used, as built by ComponentID. Returns an empty string if there is none.
+func (c *Context) ComponentData() string {
+	_, data, _ := strings.Cut(c.CustomID(), ComponentIDSeparator)
+	return data
 }
 
 // StringOption retrieves a string option value by name.
@@ -153,6 +667,56 @@ func (c *Context) UserOption(name string) *discordgo.User {
 	return nil
 }
 
+// RoleOption retrieves a role option value by name.
+// Returns nil if the option is not found or has no value.
+func (c *Context) RoleOption(name string) *discordgo.Role {
+	if c.Interaction == nil || c.Interaction.ApplicationCommandData().Options == nil {
+		return nil
+	}
+
+	for _, opt := range c.Interaction.ApplicationCommandData().Options {
+		if opt.Name == name && opt.Type == discordgo.ApplicationCommandOptionRole {
+			// First, try to get from resolved data (works without session)
+			roleID := opt.Value.(string)
+			if c.Interaction.ApplicationCommandData().Resolved != nil {
+				if role, ok := c.Interaction.ApplicationCommandData().Resolved.Roles[roleID]; ok {
+					return role
+				}
+			}
+
+			// Fallback to RoleValue (requires session)
+			return opt.RoleValue(c.Session, c.GuildID())
+		}
+	}
+
+	return nil
+}
+
+// ChannelOption retrieves a channel option value by name.
+// Returns nil if the option is not found or has no value.
+func (c *Context) ChannelOption(name string) *discordgo.Channel {
+	if c.Interaction == nil || c.Interaction.ApplicationCommandData().Options == nil {
+		return nil
+	}
+
+	for _, opt := range c.Interaction.ApplicationCommandData().Options {
+		if opt.Name == name && opt.Type == discordgo.ApplicationCommandOptionChannel {
+			// First, try to get from resolved data (works without session)
+			channelID := opt.Value.(string)
+			if c.Interaction.ApplicationCommandData().Resolved != nil {
+				if channel, ok := c.Interaction.ApplicationCommandData().Resolved.Channels[channelID]; ok {
+					return channel
+				}
+			}
+
+			// Fallback to ChannelValue (requires session)
+			return opt.ChannelValue(c.Session)
+		}
+	}
+
+	return nil
+}
+
 // BoolOption retrieves a boolean option value by name.
 // Returns false if the option is not found or has no value.
 func (c *Context) BoolOption(name string) bool {
@@ -181,12 +745,144 @@ func (c *Context) GuildID() string {
 	return guildIDFromInteraction(c.Interaction)
 }
 
+// IsDM reports whether the command was invoked outside a guild, in a direct
+// message or group DM, where GuildID is empty. It also reports true if the
+// interaction is nil. Guild-only commands such as the moderation commands
+// should check it before acting, in addition to the Scope middleware.
+func (c *Context) IsDM() bool {
+	return c.GuildID() == ""
+}
+
 // ChannelID returns the ID of the channel where the command was invoked.
 // Returns an empty string if the interaction is nil.
 func (c *Context) ChannelID() string {
 	return channelIDFromInteraction(c.Interaction)
 }
 
+// Locale returns the Discord locale of the user who invoked the command, or
+// the guild's locale if Discord sent none. Returns an empty string if the
+// interaction is nil.
+func (c *Context) Locale() string {
+	if c.Interaction == nil || c.Interaction.Interaction == nil {
+		return ""
+	}
+	if c.Interaction.Locale != "" {
+		return string(c.Interaction.Locale)
+	}
+	if c.Interaction.GuildLocale != nil {
+		return string(*c.Interaction.GuildLocale)
+	}
+	return ""
+}
+
+// GuildLocale returns the preferred locale of the guild the command was
+// invoked in, as set in its community settings. Returns an empty string in
+// DMs or if the interaction is nil.
+func (c *Context) GuildLocale() string {
+	if c.Interaction == nil || c.Interaction.Interaction == nil || c.Interaction.GuildLocale == nil {
+		return ""
+	}
+	return string(*c.Interaction.GuildLocale)
+}
+
+// userLocale returns the locale of the invoking user's client, or "".
+func (c *Context) userLocale() string {
+	if c.Interaction == nil || c.Interaction.Interaction == nil {
+		return ""
+	}
+	return string(c.Interaction.Locale)
+}
+
+// T returns the message with id in the invoker's locale, formatted with args.
+// Messages missing for that locale fall back to the guild's locale, then to
+// i18n.DefaultLocale.
+func (c *Context) T(id string, args ...any) string {
+	return c.catalog().TPreferred([]string{c.userLocale(), c.GuildLocale()}, id, args...)
+}
+
+// GuildT is like T, but prefers the guild's locale over the invoker's. Use it
+// for responses meant for the whole server, such as announcements, which
+// should match the server's language rather than whoever ran the command.
+func (c *Context) GuildT(id string, args ...any) string {
+	return c.catalog().TPreferred([]string{c.GuildLocale(), c.userLocale()}, id, args...)
+}
+
+// catalog returns the messages responses are built from.
+func (c *Context) catalog() *i18n.Catalog {
+	if c.Catalog == nil {
+		return i18n.Default()
+	}
+	return c.Catalog
+}
+
+// Member returns the guild member who invoked the command.
+// Returns nil if the interaction is nil or was not sent from a guild.
+func (c *Context) Member() *discordgo.Member {
+	if c.Interaction == nil || c.Interaction.Interaction == nil {
+		return nil
+	}
+	return c.Interaction.Member
+}
+
+// GuildMember returns the member of the invoking guild with userID, from the
+// member cache when possible and otherwise from Discord, caching the result.
+// The member may be shared with other commands and must not be modified.
+// Returns an error outside a guild, or one wrapping ErrNoSession if the member
+// is not cached and there is no session.
+func (c *Context) GuildMember(userID string) (*discordgo.Member, error) {
+	if c.IsDM() {
+		return nil, fmt.Errorf("cannot fetch member: not in a guild")
+	}
+	guildID := c.GuildID()
+
+	if member, ok := c.Members.Get(guildID, userID); ok {
+		return member, nil
+	}
+
+	if c.Session == nil {
+		return nil, fmt.Errorf("cannot fetch member: %w", ErrNoSession)
+	}
+
+	member, err := c.Session.GuildMember(guildID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch member: %w", err)
+	}
+
+	c.Members.Put(guildID, member)
+	return member, nil
+}
+
+// HasPermission reports whether the invoking member has every permission in bits.
+// Members with the Administrator permission have all permissions.
+// Returns false outside a guild, where there is no member.
+func (c *Context) HasPermission(bits int64) bool {
+	member := c.Member()
+	if member == nil {
+		return false
+	}
+
+	if member.Permissions&discordgo.PermissionAdministrator != 0 {
+		return true
+	}
+	return member.Permissions&bits == bits
+}
+
+// HasRole reports whether the invoking member has the role with the given ID.
+// Returns false outside a guild, where there is no member.
+func (c *Context) HasRole(roleID string) bool {
+	member := c.Member()
+	if member == nil {
+		return false
+	}
+
+	for _, id := range member.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
+
 // userIDFromInteraction safely extracts the user ID from an interaction.
 func userIDFromInteraction(i *discordgo.InteractionCreate) string {
 	if i == nil {
@@ -1,10 +1,17 @@
 package command_test
 
 import (
+	"context"
+	"encoding/json"
 	"io"
+	"net/http"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"jamesbot/internal/command"
+	"jamesbot/internal/i18n"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/zerolog"
@@ -452,6 +459,47 @@ func Test_Context_GuildID(t *testing.T) {
 	}
 }
 
+func Test_Context_IsDM(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.InteractionCreate
+		want        bool
+	}{
+		{
+			name:        "guild interaction",
+			interaction: createTestInteractionCreate("user-1", "guild-789012", "channel-1", nil),
+			want:        false,
+		},
+		{
+			name: "DM interaction",
+			interaction: &discordgo.InteractionCreate{
+				Interaction: &discordgo.Interaction{
+					ChannelID: "dm-channel",
+					User: &discordgo.User{
+						ID: "user-1",
+					},
+					Type: discordgo.InteractionApplicationCommand,
+					Data: discordgo.ApplicationCommandInteractionData{},
+				},
+			},
+			want: true,
+		},
+		{
+			name:        "nil interaction",
+			interaction: nil,
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := command.NewContext(createTestSession(), tt.interaction, testLogger())
+
+			assert.Equal(t, tt.want, ctx.IsDM())
+		})
+	}
+}
+
 func Test_Context_ChannelID(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -867,6 +915,166 @@ func Test_Context_UserOption(t *testing.T) {
 	}
 }
 
+// Test resolved-data options make option accessors work without a session
+func Test_NewContext_WithResolvedData(t *testing.T) {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "target", Type: discordgo.ApplicationCommandOptionUser, Value: "user-9"},
+		{Name: "role", Type: discordgo.ApplicationCommandOptionRole, Value: "role-9"},
+		{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "channel-9"},
+	}
+	user := &discordgo.User{ID: "user-9", Username: "target"}
+	role := &discordgo.Role{ID: "role-9", Name: "Moderators"}
+	channel := &discordgo.Channel{ID: "channel-9", Name: "mod-log"}
+
+	tests := []struct {
+		name        string
+		opts        []command.ContextOption
+		wantUser    *discordgo.User
+		wantRole    *discordgo.Role
+		wantChannel *discordgo.Channel
+	}{
+		{
+			name:        "without options only IDs are known",
+			wantUser:    &discordgo.User{ID: "user-9"},
+			wantRole:    &discordgo.Role{ID: "role-9"},
+			wantChannel: &discordgo.Channel{ID: "channel-9"},
+		},
+		{
+			name: "resolved data is returned in full",
+			opts: []command.ContextOption{
+				command.WithResolvedUsers(user),
+				command.WithResolvedRoles(role),
+				command.WithResolvedChannels(channel),
+			},
+			wantUser:    user,
+			wantRole:    role,
+			wantChannel: channel,
+		},
+		{
+			name: "nil entries are ignored",
+			opts: []command.ContextOption{
+				command.WithResolvedUsers(nil, user),
+				command.WithResolvedRoles(nil),
+			},
+			wantUser:    user,
+			wantRole:    &discordgo.Role{ID: "role-9"},
+			wantChannel: &discordgo.Channel{ID: "channel-9"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interaction := createTestInteractionCreate("user-1", "guild-1", "channel-1", options)
+			ctx := command.NewContext(createTestSession(), interaction, testLogger(), tt.opts...)
+
+			assert.Equal(t, tt.wantUser, ctx.UserOption("target"))
+			assert.Equal(t, tt.wantRole, ctx.RoleOption("role"))
+			assert.Equal(t, tt.wantChannel, ctx.ChannelOption("channel"))
+		})
+	}
+}
+
+// Test resolved-data options are merged with existing resolved data
+func Test_NewContext_WithResolvedDataMerges(t *testing.T) {
+	interaction := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+	existing := &discordgo.User{ID: "user-1"}
+	data := interaction.ApplicationCommandData()
+	data.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{
+		Users: map[string]*discordgo.User{existing.ID: existing},
+	}
+	interaction.Data = data
+
+	added := &discordgo.User{ID: "user-2"}
+	ctx := command.NewContext(createTestSession(), interaction, testLogger(), command.WithResolvedUsers(added))
+
+	users := ctx.Interaction.ApplicationCommandData().Resolved.Users
+	assert.Equal(t, existing, users["user-1"])
+	assert.Equal(t, added, users["user-2"])
+}
+
+// Test resolved-data options are safe without an application command interaction
+func Test_NewContext_WithResolvedDataNilSafety(t *testing.T) {
+	assert.NotPanics(t, func() {
+		ctx := command.NewContext(createTestSession(), nil, testLogger(), command.WithResolvedUsers(&discordgo.User{ID: "u"}))
+		assert.Nil(t, ctx.UserOption("target"))
+	})
+
+	assert.NotPanics(t, func() {
+		interaction := &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{Type: discordgo.InteractionMessageComponent, Data: discordgo.MessageComponentInteractionData{}},
+		}
+		command.NewContext(createTestSession(), interaction, testLogger(), command.WithResolvedRoles(&discordgo.Role{ID: "r"}))
+	})
+}
+
+// Test Member and the permission and role helpers
+func Test_Context_MemberHelpers(t *testing.T) {
+	guildInteraction := func(perms int64, roles ...string) *discordgo.InteractionCreate {
+		i := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+		i.Member.Permissions = perms
+		i.Member.Roles = roles
+		return i
+	}
+	dmInteraction := createTestInteractionCreate("user-1", "", "channel-1", nil)
+	dmInteraction.Member = nil
+
+	tests := []struct {
+		name        string
+		interaction *discordgo.InteractionCreate
+		perm        int64
+		role        string
+		wantMember  bool
+		wantPerm    bool
+		wantRole    bool
+	}{
+		{
+			name:        "member with permission and role",
+			interaction: guildInteraction(discordgo.PermissionKickMembers|discordgo.PermissionBanMembers, "role-1", "role-2"),
+			perm:        discordgo.PermissionBanMembers,
+			role:        "role-2",
+			wantMember:  true,
+			wantPerm:    true,
+			wantRole:    true,
+		},
+		{
+			name:        "member missing one of several permissions",
+			interaction: guildInteraction(discordgo.PermissionKickMembers, "role-1"),
+			perm:        discordgo.PermissionKickMembers | discordgo.PermissionBanMembers,
+			role:        "role-3",
+			wantMember:  true,
+		},
+		{
+			name:        "administrator has every permission",
+			interaction: guildInteraction(discordgo.PermissionAdministrator),
+			perm:        discordgo.PermissionModerateMembers,
+			role:        "role-1",
+			wantMember:  true,
+			wantPerm:    true,
+		},
+		{
+			name:        "dm has no member",
+			interaction: dmInteraction,
+			perm:        discordgo.PermissionSendMessages,
+			role:        "role-1",
+		},
+		{
+			name: "nil interaction",
+			perm: discordgo.PermissionSendMessages,
+			role: "role-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := command.NewContext(createTestSession(), tt.interaction, testLogger())
+
+			assert.Equal(t, tt.wantMember, ctx.Member() != nil, "Member()")
+			assert.Equal(t, tt.wantPerm, ctx.HasPermission(tt.perm), "HasPermission()")
+			assert.Equal(t, tt.wantRole, ctx.HasRole(tt.role), "HasRole()")
+		})
+	}
+}
+
 // Test interaction with nil Member but valid User (DM case)
 func Test_Context_DMInteraction(t *testing.T) {
 	interaction := &discordgo.InteractionCreate{
@@ -922,3 +1130,592 @@ func Test_Context_GuildInteractionUserPrecedence(t *testing.T) {
 	// Member.User should take precedence over User
 	assert.Equal(t, "member-user-id", ctx.UserID(), "should extract user ID from Member.User in guild")
 }
+
+// Test Locale and T choose the invoker's language
+func Test_Context_Locale(t *testing.T) {
+	german := discordgo.German
+
+	tests := []struct {
+		name        string
+		locale      discordgo.Locale
+		guildLocale *discordgo.Locale
+		wantLocale  string
+		wantText    string
+	}{
+		{name: "user locale", locale: discordgo.SpanishES, wantLocale: "es-ES", wantText: "No puedes expulsar bots."},
+		{name: "user locale wins over guild locale", locale: discordgo.SpanishES, guildLocale: &german, wantLocale: "es-ES", wantText: "No puedes expulsar bots."},
+		{name: "guild locale when user locale is missing", guildLocale: &german, wantLocale: "de", wantText: "Du kannst keine Bots kicken."},
+		{name: "untranslated user locale falls back to guild locale", locale: discordgo.Japanese, guildLocale: &german, wantLocale: "ja", wantText: "Du kannst keine Bots kicken."},
+		{name: "untranslated locale falls back to English", locale: discordgo.Japanese, wantLocale: "ja", wantText: "You cannot kick bots."},
+		{name: "no locale", wantLocale: "", wantText: "You cannot kick bots."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interaction := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+			interaction.Locale = tt.locale
+			interaction.GuildLocale = tt.guildLocale
+			ctx := command.NewContext(nil, interaction, testLogger())
+
+			assert.Equal(t, tt.wantLocale, ctx.Locale())
+			assert.Equal(t, tt.wantText, ctx.T(i18n.MsgKickBot))
+		})
+	}
+}
+
+// Test GuildT prefers the server's language over the invoker's
+func Test_Context_GuildT(t *testing.T) {
+	german := discordgo.German
+	japanese := discordgo.Japanese
+
+	tests := []struct {
+		name            string
+		locale          discordgo.Locale
+		guildLocale     *discordgo.Locale
+		wantGuildLocale string
+		wantText        string
+	}{
+		{name: "guild locale wins over user locale", locale: discordgo.SpanishES, guildLocale: &german, wantGuildLocale: "de", wantText: "Du kannst keine Bots kicken."},
+		{name: "user locale when guild locale is missing", locale: discordgo.SpanishES, wantText: "No puedes expulsar bots."},
+		{name: "untranslated guild locale falls back to user locale", locale: discordgo.SpanishES, guildLocale: &japanese, wantGuildLocale: "ja", wantText: "No puedes expulsar bots."},
+		{name: "no locale", wantText: "You cannot kick bots."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interaction := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+			interaction.Locale = tt.locale
+			interaction.GuildLocale = tt.guildLocale
+			ctx := command.NewContext(nil, interaction, testLogger())
+
+			assert.Equal(t, tt.wantGuildLocale, ctx.GuildLocale())
+			assert.Equal(t, tt.wantText, ctx.GuildT(i18n.MsgKickBot))
+		})
+	}
+
+	assert.Equal(t, "", command.NewContext(nil, nil, testLogger()).GuildLocale())
+}
+
+// Test WithCatalog replaces the built-in messages
+func Test_Context_WithCatalog(t *testing.T) {
+	catalog := i18n.NewCatalog(i18n.DefaultLocale)
+	catalog.Add(i18n.DefaultLocale, map[string]string{i18n.MsgGuildOnly: "Servers only, %s."})
+
+	ctx := command.NewContext(nil, createTestInteractionCreate("user-1", "", "channel-1", nil), testLogger(), command.WithCatalog(catalog))
+
+	assert.Equal(t, "Servers only, friend.", ctx.T(i18n.MsgGuildOnly, "friend"))
+	assert.Equal(t, i18n.MsgKickBot, ctx.T(i18n.MsgKickBot), "messages missing from the catalog show their ID")
+	assert.Equal(t, "", command.NewContext(nil, nil, testLogger()).Locale())
+}
+
+// =============================================================================
+// Defer Tests
+// =============================================================================
+
+// Paths of the Discord endpoints used to answer the interaction created by
+// createDeferTestInteraction.
+const (
+	deferCallbackPath = "/api/v9/interactions/interaction-123/token-1/callback"
+	deferOriginalPath = "/api/v9/webhooks/app-1/token-1/messages/@original"
+	deferFollowupPath = "/api/v9/webhooks/app-1/token-1"
+)
+
+// createDeferTestInteraction creates an interaction with the application ID
+// and token its responses are addressed by.
+func createDeferTestInteraction() *discordgo.InteractionCreate {
+	i := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+	i.AppID = "app-1"
+	i.Token = "token-1"
+	return i
+}
+
+func Test_Context_Defer(t *testing.T) {
+	tests := []struct {
+		name      string
+		ephemeral bool
+		respond   func(ctx *command.Context) error
+		want      []string
+		wantFlags discordgo.MessageFlags
+	}{
+		{
+			name:    "response edits the deferred response",
+			respond: func(ctx *command.Context) error { return ctx.Respond("done") },
+			want: []string{
+				http.MethodPost + " " + deferCallbackPath,
+				http.MethodPatch + " " + deferOriginalPath,
+			},
+		},
+		{
+			name:      "ephemeral deferral keeps a public response private",
+			ephemeral: true,
+			respond:   func(ctx *command.Context) error { return ctx.Respond("done") },
+			want: []string{
+				http.MethodPost + " " + deferCallbackPath,
+				http.MethodPatch + " " + deferOriginalPath,
+			},
+			wantFlags: discordgo.MessageFlagsEphemeral,
+		},
+		{
+			name:    "ephemeral response to a public deferral is a followup",
+			respond: func(ctx *command.Context) error { return ctx.RespondEphemeral("done") },
+			want: []string{
+				http.MethodPost + " " + deferCallbackPath,
+				http.MethodPost + " " + deferFollowupPath,
+				http.MethodDelete + " " + deferOriginalPath,
+			},
+		},
+		{
+			name: "embed edits the deferred response",
+			respond: func(ctx *command.Context) error {
+				return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: "done"})
+			},
+			want: []string{
+				http.MethodPost + " " + deferCallbackPath,
+				http.MethodPatch + " " + deferOriginalPath,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			rt.responses = map[string]string{
+				http.MethodPatch + " " + deferOriginalPath: `{"id":"message-1"}`,
+				http.MethodPost + " " + deferFollowupPath:  `{"id":"message-2"}`,
+			}
+			ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+			require.NoError(t, ctx.Defer(tt.ephemeral))
+			require.NoError(t, tt.respond(ctx))
+
+			requests := rt.recorded()
+			got := make([]string, len(requests))
+			for i, req := range requests {
+				got[i] = req.Method + " " + req.Path
+			}
+			assert.Equal(t, tt.want, got)
+
+			var deferral discordgo.InteractionResponse
+			require.NoError(t, json.Unmarshal(requests[0].Body, &deferral))
+			assert.Equal(t, discordgo.InteractionResponseDeferredChannelMessageWithSource, deferral.Type)
+			require.NotNil(t, deferral.Data)
+			assert.Equal(t, tt.wantFlags, deferral.Data.Flags)
+			assert.Contains(t, string(requests[1].Body), "done")
+		})
+	}
+}
+
+func Test_Context_Defer_NoOp(t *testing.T) {
+	t.Run("after responding", func(t *testing.T) {
+		session, rt := newRecordingSession(t)
+		ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+		require.NoError(t, ctx.Respond("done"))
+		require.NoError(t, ctx.Defer(false))
+
+		assert.Len(t, rt.recorded(), 1, "deferring after responding should send nothing")
+	})
+
+	t.Run("twice", func(t *testing.T) {
+		session, rt := newRecordingSession(t)
+		ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+		require.NoError(t, ctx.Defer(false))
+		require.NoError(t, ctx.Defer(true))
+
+		assert.Len(t, rt.recorded(), 1, "deferring again should send nothing")
+	})
+
+	t.Run("text command", func(t *testing.T) {
+		session, rt := newRecordingSession(t)
+		m := textMessage()
+		event, err := command.NewTextInteraction(m, &command.PingCommand{}, "")
+		require.NoError(t, err)
+		ctx := command.NewContext(session, event, testLogger(), command.WithMessage(m))
+
+		require.NoError(t, ctx.Defer(false))
+
+		assert.Empty(t, rt.recorded(), "text commands have no deadline to defer for")
+	})
+}
+
+// =============================================================================
+// Response State Tests
+// =============================================================================
+
+func Test_Context_RespondTwice(t *testing.T) {
+	tests := []struct {
+		name  string
+		first func(ctx *command.Context) error
+		want  []string
+	}{
+		{
+			name:  "respond then respond",
+			first: func(ctx *command.Context) error { return ctx.Respond("first") },
+			want: []string{
+				http.MethodPost + " " + deferCallbackPath,
+				http.MethodPost + " " + deferFollowupPath,
+			},
+		},
+		{
+			name: "defer then respond",
+			first: func(ctx *command.Context) error {
+				if err := ctx.Defer(false); err != nil {
+					return err
+				}
+				return ctx.Respond("first")
+			},
+			want: []string{
+				http.MethodPost + " " + deferCallbackPath,
+				http.MethodPatch + " " + deferOriginalPath,
+				http.MethodPost + " " + deferFollowupPath,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			rt.responses = map[string]string{
+				http.MethodPatch + " " + deferOriginalPath: `{"id":"message-1"}`,
+				http.MethodPost + " " + deferFollowupPath:  `{"id":"message-2"}`,
+			}
+			ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+			assert.False(t, ctx.Responded())
+			require.NoError(t, tt.first(ctx))
+			assert.True(t, ctx.Responded())
+			require.NoError(t, ctx.RespondEphemeral("second"), "a second response should be a followup")
+
+			requests := rt.recorded()
+			got := make([]string, len(requests))
+			for i, req := range requests {
+				got[i] = req.Method + " " + req.Path
+			}
+			assert.Equal(t, tt.want, got)
+
+			var followup discordgo.WebhookParams
+			require.NoError(t, json.Unmarshal(requests[len(requests)-1].Body, &followup))
+			assert.Equal(t, "second", followup.Content)
+			assert.Equal(t, discordgo.MessageFlagsEphemeral, followup.Flags, "followups keep their visibility")
+		})
+	}
+}
+
+func Test_Context_UpdateMessage_AfterResponse(t *testing.T) {
+	session, rt := newRecordingSession(t)
+	ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+	require.NoError(t, ctx.UpdateMessage("updated"))
+	err := ctx.UpdateMessage("again")
+
+	assert.ErrorIs(t, err, command.ErrAlreadyResponded)
+	assert.Len(t, rt.recorded(), 1, "the second update should not reach Discord")
+}
+
+func Test_Context_RespondConcurrently(t *testing.T) {
+	session, rt := newRecordingSession(t)
+	rt.responses = map[string]string{
+		http.MethodPost + " " + deferFollowupPath: `{"id":"message-2"}`,
+	}
+	ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+	const responses = 10
+	var wg sync.WaitGroup
+	for i := 0; i < responses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ctx.Respond("hi"))
+		}()
+	}
+	wg.Wait()
+
+	callbacks := 0
+	for _, req := range rt.recorded() {
+		if req.Path == deferCallbackPath {
+			callbacks++
+		}
+	}
+	assert.Equal(t, 1, callbacks, "only one response should answer the interaction")
+	assert.Len(t, rt.recorded(), responses)
+}
+
+// =============================================================================
+// Deadline Tests
+// =============================================================================
+
+// snowflakeAt returns a Discord ID created at t.
+func snowflakeAt(t time.Time) string {
+	return strconv.FormatInt((t.UnixMilli()-1420070400000)<<22, 10)
+}
+
+func Test_Context_Context(t *testing.T) {
+	created := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+
+	interactionCreatedAt := func(at time.Time) *discordgo.InteractionCreate {
+		i := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+		i.ID = snowflakeAt(at)
+		return i
+	}
+
+	tests := []struct {
+		name         string
+		interaction  *discordgo.InteractionCreate
+		opts         []command.ContextOption
+		wantDeadline time.Time
+		wantErr      error
+	}{
+		{
+			name:         "interaction expires with its token",
+			interaction:  interactionCreatedAt(created),
+			wantDeadline: created.Add(command.InteractionTokenLifetime),
+		},
+		{
+			name:         "expired interaction",
+			interaction:  interactionCreatedAt(created.Add(-command.InteractionTokenLifetime)),
+			wantDeadline: created,
+			wantErr:      context.DeadlineExceeded,
+		},
+		{
+			name:        "text command has no deadline",
+			interaction: interactionCreatedAt(created),
+			opts:        []command.ContextOption{command.WithMessage(&discordgo.Message{ID: "message-1"})},
+		},
+		{
+			name:        "invalid interaction ID has no deadline",
+			interaction: createTestInteractionCreate("user-1", "guild-1", "channel-1", nil),
+		},
+		{
+			name: "no interaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := command.NewContext(nil, tt.interaction, testLogger(), tt.opts...)
+			defer ctx.Release()
+
+			deadline, ok := ctx.Context().Deadline()
+
+			if tt.wantDeadline.IsZero() {
+				assert.False(t, ok, "unexpected deadline %s", deadline)
+			} else {
+				require.True(t, ok)
+				assert.True(t, tt.wantDeadline.Equal(deadline), "deadline %s, want %s", deadline, tt.wantDeadline)
+			}
+			assert.Equal(t, tt.wantErr, ctx.Context().Err())
+			assert.Same(t, ctx.Context(), ctx.Context(), "every call should return the same context")
+		})
+	}
+}
+
+func Test_Context_Release(t *testing.T) {
+	ctx := command.NewContext(nil, createTestInteractionCreate("user-1", "guild-1", "channel-1", nil), testLogger())
+	work := ctx.Context()
+
+	ctx.Release()
+
+	assert.ErrorIs(t, work.Err(), context.Canceled, "work still running should stop once the command returns")
+	assert.NotPanics(t, func() {
+		command.NewContext(nil, nil, testLogger()).Release()
+	}, "releasing a context never asked for is a no-op")
+}
+
+func Test_Context_WithContext(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx := command.NewContext(nil, nil, testLogger(), command.WithContext(parent))
+	defer ctx.Release()
+
+	cancel()
+
+	assert.ErrorIs(t, ctx.Context().Err(), context.Canceled)
+}
+
+// =============================================================================
+// File Tests
+// =============================================================================
+
+func Test_NewFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		fileName        string
+		size            int
+		wantContentType string
+		wantErr         error
+	}{
+		{name: "json", fileName: "rules.json", size: 2, wantContentType: "application/json"},
+		{name: "text", fileName: "notes.txt", size: 2, wantContentType: "text/plain; charset=utf-8"},
+		{name: "unknown extension", fileName: "dump.bin-x", size: 2, wantContentType: "application/octet-stream"},
+		{name: "no extension", fileName: "dump", size: 2, wantContentType: "application/octet-stream"},
+		{name: "at the limit", fileName: "big.json", size: command.MaxFileSize, wantContentType: "application/json"},
+		{name: "over the limit", fileName: "big.json", size: command.MaxFileSize + 1, wantErr: command.ErrFileTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make([]byte, tt.size)
+
+			file, err := command.NewFile(tt.fileName, data)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, file)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.fileName, file.Name)
+			assert.Equal(t, tt.wantContentType, file.ContentType)
+			got, err := io.ReadAll(file.Reader)
+			require.NoError(t, err)
+			assert.Len(t, got, tt.size)
+		})
+	}
+
+	t.Run("empty name", func(t *testing.T) {
+		_, err := command.NewFile("", []byte("{}"))
+		assert.Error(t, err)
+	})
+}
+
+func Test_Context_RespondFile(t *testing.T) {
+	t.Run("attaches the file to the response", func(t *testing.T) {
+		session, rt := newRecordingSession(t)
+		ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+		require.NoError(t, ctx.RespondFile("rules.json", []byte(`{"enabled":true}`)))
+
+		requests := rt.recorded()
+		require.Len(t, requests, 1)
+		assert.Equal(t, http.MethodPost+" "+deferCallbackPath, requests[0].Method+" "+requests[0].Path)
+		body := string(requests[0].Body)
+		assert.Contains(t, body, `filename="rules.json"`)
+		assert.Contains(t, body, "Content-Type: application/json")
+		assert.Contains(t, body, `{"enabled":true}`)
+	})
+
+	t.Run("edits an ephemeral deferral", func(t *testing.T) {
+		session, rt := newRecordingSession(t)
+		rt.responses = map[string]string{
+			http.MethodPatch + " " + deferOriginalPath: `{"id":"message-1"}`,
+		}
+		ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+		require.NoError(t, ctx.Defer(true))
+		require.NoError(t, ctx.RespondFile("rules.json", []byte("[]")))
+
+		requests := rt.recorded()
+		require.Len(t, requests, 2)
+		assert.Equal(t, http.MethodPatch+" "+deferOriginalPath, requests[1].Method+" "+requests[1].Path)
+		assert.Contains(t, string(requests[1].Body), `filename="rules.json"`)
+	})
+
+	t.Run("oversized file sends nothing", func(t *testing.T) {
+		session, rt := newRecordingSession(t)
+		ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+		err := ctx.RespondFile("big.json", make([]byte, command.MaxFileSize+1))
+
+		assert.ErrorIs(t, err, command.ErrFileTooLarge)
+		assert.Empty(t, rt.recorded())
+	})
+}
+
+// =============================================================================
+// Nil Session Tests
+// =============================================================================
+
+func Test_Context_NilSessionActions(t *testing.T) {
+	slash := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+	message := &discordgo.Message{ID: "message-1", ChannelID: "channel-1", GuildID: "guild-1", Author: &discordgo.User{ID: "user-1"}}
+
+	contexts := map[string]*command.Context{
+		"slash command":  command.NewContext(nil, slash, testLogger()),
+		"text command":   command.NewContext(nil, slash, testLogger(), command.WithMessage(message)),
+		"no interaction": command.NewContext(nil, nil, testLogger()),
+	}
+
+	actions := []struct {
+		name   string
+		action func(ctx *command.Context) error
+	}{
+		{name: "Respond", action: func(ctx *command.Context) error { return ctx.Respond("hi") }},
+		{name: "RespondEphemeral", action: func(ctx *command.Context) error { return ctx.RespondEphemeral("hi") }},
+		{name: "RespondEmbed", action: func(ctx *command.Context) error {
+			return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: "hi"})
+		}},
+		{name: "RespondComponents", action: func(ctx *command.Context) error { return ctx.RespondComponents("hi", true) }},
+		{name: "Defer", action: func(ctx *command.Context) error { return ctx.Defer(false) }},
+		{name: "EditResponse", action: func(ctx *command.Context) error { return ctx.EditResponse("hi") }},
+		{name: "UpdateMessage", action: func(ctx *command.Context) error { return ctx.UpdateMessage("hi") }},
+		{name: "RespondFile", action: func(ctx *command.Context) error { return ctx.RespondFile("hi.txt", []byte("hi")) }},
+		{name: "GuildMember", action: func(ctx *command.Context) error {
+			_, err := ctx.GuildMember("user-2")
+			return err
+		}},
+	}
+
+	for ctxName, ctx := range contexts {
+		for _, tt := range actions {
+			t.Run(ctxName+"/"+tt.name, func(t *testing.T) {
+				var err error
+				require.NotPanics(t, func() { err = tt.action(ctx) })
+
+				if ctxName == "no interaction" && tt.name == "GuildMember" {
+					// Outside a guild the member cannot be looked up at all
+					assert.Error(t, err)
+					return
+				}
+				assert.ErrorIs(t, err, command.ErrNoSession)
+			})
+		}
+	}
+}
+
+func Test_Commands_NilSession(t *testing.T) {
+	tests := []struct {
+		name  string
+		cmd   command.Command
+		event *discordgo.InteractionCreate
+	}{
+		{
+			name:  "kick",
+			cmd:   &command.KickCommand{},
+			event: createKickInteractionWithResolvedUser("mod-1", "user-2", "guild-1", "channel-1", "spam", true, false),
+		},
+		{
+			name:  "ban",
+			cmd:   &command.BanCommand{},
+			event: createBanInteractionWithResolvedUser("mod-1", "user-2", "guild-1", "channel-1", 0, false, "spam", true, false),
+		},
+		{
+			name:  "mute",
+			cmd:   &command.MuteCommand{},
+			event: createMuteInteractionWithResolvedUser("mod-1", "user-2", "guild-1", "channel-1", "1h", "spam", true, false),
+		},
+		{
+			name:  "warn",
+			cmd:   &command.WarnCommand{},
+			event: createWarnInteractionWithResolvedUser("mod-1", "user-2", "guild-1", "channel-1", "spam", false),
+		},
+		{
+			name:  "ping",
+			cmd:   &command.PingCommand{},
+			event: createPingTestInteraction("user-1", "guild-1", "channel-1"),
+		},
+		{
+			name:  "echo",
+			cmd:   &command.EchoCommand{},
+			event: createEchoTestInteraction("user-1", "guild-1", "channel-1", createTextOption("hi")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			require.NotPanics(t, func() { err = tt.cmd.Execute(command.NewContext(nil, tt.event, testLogger())) })
+
+			assert.ErrorIs(t, err, command.ErrNoSession)
+		})
+	}
+}
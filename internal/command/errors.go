@@ -0,0 +1,30 @@
+package command
+
+import "errors"
+
+var (
+	// ErrNilCommand is returned when a nil command is registered or used as a replacement.
+	ErrNilCommand = errors.New("nil command")
+
+	// ErrCommandExists is returned when a command name is already registered.
+	ErrCommandExists = errors.New("already registered")
+
+	// ErrInvalidCommand is returned when a command's definition breaks one of
+	// Discord's limits on names, descriptions, or options.
+	ErrInvalidCommand = errors.New("invalid command definition")
+
+	// ErrNilComponentHandler is returned when a nil component handler is registered.
+	ErrNilComponentHandler = errors.New("nil component handler")
+
+	// ErrNoSession is returned by actions that need a Discord session when a
+	// Context or command has none, as in tests.
+	ErrNoSession = errors.New("no discord session")
+
+	// ErrFileTooLarge is returned by Context.RespondFile for files larger
+	// than MaxFileSize, which Discord would reject.
+	ErrFileTooLarge = errors.New("file too large")
+
+	// ErrAlreadyResponded is returned by Context.UpdateMessage when the
+	// interaction already has a response, which Discord would reject.
+	ErrAlreadyResponded = errors.New("interaction already responded to")
+)
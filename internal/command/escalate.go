@@ -0,0 +1,100 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"jamesbot/internal/rules"
+	"jamesbot/internal/warnings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// Escalation is a warn-escalation step applied to a member.
+type Escalation struct {
+	warnings.Step
+
+	// MuteFor is how long the member was timed out, for a mute.
+	MuteFor time.Duration
+}
+
+// escalationModLogAction names each escalation action in the mod-log.
+var escalationModLogAction = map[warnings.Action]string{
+	warnings.ActionMute: ModLogTimedOut,
+	warnings.ActionKick: ModLogKicked,
+	warnings.ActionBan:  ModLogBanned,
+}
+
+// Escalate applies the step of the warn-escalation rule, as configured in
+// guildID, that a member reaching count warnings triggers. It reports false
+// when the rule is disabled, its settings are invalid, or no step is at
+// count; an error means the step was found but applying it failed. Both
+// outcomes are logged, and applied steps posted to the mod-log channel, so
+// the warn command and content rules that warn escalate alike.
+func Escalate(s *discordgo.Session, set *rules.Set, guildID, userID string, count int, logger zerolog.Logger) (Escalation, bool, error) {
+	if s == nil || !set.GuildEnabled(guildID, rules.RuleWarnEscalation) {
+		return Escalation{}, false, nil
+	}
+
+	raw, _ := set.GuildValue(guildID, rules.RuleWarnEscalation, rules.KeyEscalationPolicy)
+	policy, err := warnings.ParsePolicy(raw)
+	if err != nil {
+		logger.Warn().Err(err).Str("policy", raw).Msg("invalid warn escalation policy")
+		return Escalation{}, false, nil
+	}
+
+	step, ok := policy.StepAt(count)
+	if !ok {
+		return Escalation{}, false, nil
+	}
+	escalation := Escalation{Step: step}
+
+	auditReason := fmt.Sprintf("Automatic escalation after %d warnings", count)
+	switch step.Action {
+	case warnings.ActionMute:
+		raw, _ := set.GuildValue(guildID, rules.RuleWarnEscalation, rules.KeyEscalationMuteFor)
+		duration, parseErr := time.ParseDuration(raw)
+		if parseErr != nil {
+			logger.Warn().Err(parseErr).Str("duration", raw).Msg("invalid warn escalation mute duration")
+			return Escalation{}, false, nil
+		}
+		escalation.MuteFor = duration
+		until := time.Now().Add(duration)
+		err = retryOnRateLimit(func(opts ...discordgo.RequestOption) error {
+			return s.GuildMemberTimeout(guildID, userID, &until, opts...)
+		})
+	case warnings.ActionKick:
+		err = retryOnRateLimit(func(opts ...discordgo.RequestOption) error {
+			return s.GuildMemberDeleteWithReason(guildID, userID, auditReason, opts...)
+		})
+	case warnings.ActionBan:
+		err = retryOnRateLimit(func(opts ...discordgo.RequestOption) error {
+			return s.GuildBanCreateWithReason(guildID, userID, auditReason, 0, opts...)
+		})
+	}
+
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("target_id", userID).
+			Str("action", string(step.Action)).
+			Int("warnings", count).
+			Msg("warn escalation failed")
+		return escalation, true, err
+	}
+
+	logger.Info().
+		Str("target_id", userID).
+		Str("action", string(step.Action)).
+		Int("warnings", count).
+		Msg("warn escalation applied")
+	PostModLog(s, set, guildID, ModLogEntry{
+		Action:   escalationModLogAction[step.Action],
+		TargetID: userID,
+		Source:   "warn escalation",
+		Reason:   auditReason,
+		Duration: escalation.MuteFor,
+	}, logger)
+	return escalation, true, nil
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"jamesbot/internal/rules"
+	"jamesbot/pkg/errutil"
+)
+
+// ExportRulesCommand implements a command that sends the rule settings in
+// effect in the server as a JSON file, in the format 'jamesbot rules export'
+// writes and 'jamesbot rules import' reads. It requires the Manage Server
+// permission to execute.
+type ExportRulesCommand struct {
+	// Rules is the rule set to export. When nil, the command reports that
+	// rules are unavailable.
+	Rules *rules.Set
+}
+
+// Name returns the command name.
+func (c *ExportRulesCommand) Name() string {
+	return "exportrules"
+}
+
+// Description returns the command description.
+func (c *ExportRulesCommand) Description() string {
+	return "Download this server's rule settings as a JSON file"
+}
+
+// Permissions returns the required Discord permissions.
+// Users must have the Manage Server permission to execute this command.
+func (c *ExportRulesCommand) Permissions() int64 {
+	return discordgo.PermissionManageGuild
+}
+
+// GuildOnly reports that the command only works in a server, whose rule
+// settings it exports.
+func (c *ExportRulesCommand) GuildOnly() bool {
+	return true
+}
+
+// Options returns the command options.
+// The exportrules command has no options.
+func (c *ExportRulesCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+// Execute runs the exportrules command.
+// It replies privately with the server's rule settings attached as
+// rules-<guild ID>.json.
+func (c *ExportRulesCommand) Execute(ctx *Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	if ctx.IsDM() {
+		return errutil.UserFriendlyError{
+			UserMessage: "This command can only be used in a server.",
+			Err:         fmt.Errorf("exportrules command used outside of guild"),
+		}
+	}
+	if c.Rules == nil {
+		return errutil.UserFriendlyError{
+			UserMessage: "Rules are not available.",
+			Err:         fmt.Errorf("exportrules command has no rule set"),
+		}
+	}
+
+	guildID := ctx.GuildID()
+	data, err := json.MarshalIndent(c.Rules.GuildRules(guildID), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode rules: %w", err)
+	}
+
+	// The file goes wherever the response does, so keep it to the invoker
+	if err := ctx.Defer(true); err != nil {
+		return err
+	}
+	return ctx.RespondFile(fmt.Sprintf("rules-%s.json", guildID), append(data, '\n'))
+}
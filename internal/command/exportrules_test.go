@@ -0,0 +1,88 @@
+package command_test
+
+import (
+	"net/http"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/rules"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExportRulesCommand_Metadata(t *testing.T) {
+	cmd := &command.ExportRulesCommand{}
+
+	assert.Equal(t, "exportrules", cmd.Name())
+	assert.NotEmpty(t, cmd.Description())
+	assert.Equal(t, int64(discordgo.PermissionManageGuild), cmd.Permissions())
+	assert.True(t, cmd.GuildOnly())
+	assert.Empty(t, cmd.Options())
+
+	var _ command.PermissionedCommand = (*command.ExportRulesCommand)(nil)
+}
+
+func Test_ExportRulesCommand_Execute(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule(rules.RuleWordFilter, rules.KeyEnabled, "true"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleWordFilter, rules.KeyAction, rules.ActionWarn))
+	require.NoError(t, set.SetGuildRule("guild-2", rules.RuleWordFilter, rules.KeyWords, "other-guild-word"))
+
+	session, rt := newRecordingSession(t)
+	rt.responses = map[string]string{
+		http.MethodPatch + " " + deferOriginalPath: `{"id":"message-1"}`,
+	}
+	ctx := command.NewContext(session, createDeferTestInteraction(), testLogger())
+
+	require.NoError(t, (&command.ExportRulesCommand{Rules: set}).Execute(ctx))
+
+	requests := rt.recorded()
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, http.MethodPost+" "+deferCallbackPath, requests[0].Method+" "+requests[0].Path)
+	assert.Contains(t, string(requests[0].Body), `"flags":64`, "the export should only be shown to the invoker")
+
+	assert.Equal(t, http.MethodPatch+" "+deferOriginalPath, requests[1].Method+" "+requests[1].Path)
+	body := string(requests[1].Body)
+	assert.Contains(t, body, `filename="rules-guild-1.json"`)
+	assert.Contains(t, body, `"value": "warn",`+"\n"+`    "guild": "guild-1"`, "the guild's override should be exported")
+	assert.Contains(t, body, `"enabled": true`, "global settings in effect should be exported")
+	assert.NotContains(t, body, "other-guild-word", "other guilds' overrides should not be exported")
+}
+
+func Test_ExportRulesCommand_Execute_Errors(t *testing.T) {
+	t.Run("nil context", func(t *testing.T) {
+		assert.Error(t, (&command.ExportRulesCommand{}).Execute(nil))
+	})
+
+	t.Run("outside a guild", func(t *testing.T) {
+		ctx := command.NewContext(nil, createTestInteractionCreate("user-1", "", "channel-1", nil), testLogger())
+
+		err := (&command.ExportRulesCommand{Rules: rules.NewSet(rules.Defaults()...)}).Execute(ctx)
+
+		var friendly errutil.UserFriendlyError
+		require.ErrorAs(t, err, &friendly)
+		assert.Contains(t, friendly.UserMessage, "server")
+	})
+
+	t.Run("no rule set", func(t *testing.T) {
+		ctx := command.NewContext(nil, createDeferTestInteraction(), testLogger())
+
+		err := (&command.ExportRulesCommand{}).Execute(ctx)
+
+		var friendly errutil.UserFriendlyError
+		require.ErrorAs(t, err, &friendly)
+		assert.Contains(t, friendly.UserMessage, "not available")
+	})
+
+	t.Run("no session", func(t *testing.T) {
+		ctx := command.NewContext(nil, createDeferTestInteraction(), testLogger())
+
+		err := (&command.ExportRulesCommand{Rules: rules.NewSet(rules.Defaults()...)}).Execute(ctx)
+
+		assert.ErrorIs(t, err, command.ErrNoSession)
+	})
+}
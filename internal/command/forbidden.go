@@ -0,0 +1,34 @@
+package command
+
+import (
+	"errors"
+
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// forbiddenMessage returns a user-facing message, in ctx's locale, if err is
+// Discord refusing an action because the bot lacks access or a permission.
+// Missing permissions, which also covers a target whose highest role is
+// above the bot's, are reported with the message permissionMsg formatted
+// with target, naming the permission the bot needs.
+func forbiddenMessage(ctx *Context, err error, permissionMsg, target string) (string, bool) {
+	switch discordErrorCode(err) {
+	case discordgo.ErrCodeMissingPermissions:
+		return ctx.T(permissionMsg, target), true
+	case discordgo.ErrCodeMissingAccess:
+		return ctx.T(i18n.MsgMissingAccess), true
+	}
+	return "", false
+}
+
+// discordErrorCode returns the JSON error code of a Discord REST error, or
+// zero if err is not one.
+func discordErrorCode(err error) int {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		return restErr.Message.Code
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package command_test
+
+import (
+	"errors"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ModerationCommands_Forbidden(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        int
+		wantMessage map[string]string // by command, contained in the reply
+	}{
+		{
+			name: "missing permissions names the permission",
+			code: discordgo.ErrCodeMissingPermissions,
+			wantMessage: map[string]string{
+				"kick": "I don't have permission to kick target",
+				"ban":  "I don't have permission to ban target",
+				"mute": "Check that my role has Moderate Members and is above their highest role",
+			},
+		},
+		{
+			name: "missing access",
+			code: discordgo.ErrCodeMissingAccess,
+			wantMessage: map[string]string{
+				"kick": "I can't access this server or channel. Check that my role can view it.",
+				"ban":  "I can't access this server or channel. Check that my role can view it.",
+				"mute": "I can't access this server or channel. Check that my role can view it.",
+			},
+		},
+		{
+			name: "other errors keep the generic message",
+			code: discordgo.ErrCodeUnknownMember,
+			wantMessage: map[string]string{
+				"kick": "Failed to kick target",
+				"ban":  "Failed to ban target",
+				"mute": "Failed to timeout target",
+			},
+		},
+	}
+
+	for _, mc := range moderationCalls() {
+		for _, tt := range tests {
+			t.Run(mc.name+"/"+tt.name, func(t *testing.T) {
+				session, rt := newRecordingSession(t)
+				rt.failures = map[string]int{mc.pathSuffix: tt.code}
+				ctx := command.NewContext(session, mc.interaction(), testLogger())
+
+				err := mc.cmd.Execute(ctx)
+
+				var friendly errutil.UserFriendlyError
+				require.True(t, errors.As(err, &friendly), "expected a user-friendly error, got %v", err)
+				assert.Contains(t, friendly.UserMessage, tt.wantMessage[mc.name])
+
+				var restErr *discordgo.RESTError
+				require.True(t, errors.As(err, &restErr), "the Discord error should stay wrapped for logging")
+				assert.Equal(t, tt.code, restErr.Message.Code)
+			})
+		}
+	}
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 
+	"jamesbot/internal/i18n"
 	"jamesbot/pkg/errutil"
 
 	"github.com/bwmarrin/discordgo"
@@ -10,7 +11,11 @@ import (
 
 // KickCommand implements a command to kick members from the server.
 // It requires the Kick Members permission to execute.
-type KickCommand struct{}
+type KickCommand struct {
+	// NotifyTarget DMs the member the action and reason before acting.
+	// Members with DMs disabled are kicked without notice.
+	NotifyTarget bool
+}
 
 // Name returns the command name.
 func (c *KickCommand) Name() string {
@@ -28,6 +33,12 @@ func (c *KickCommand) Permissions() int64 {
 	return discordgo.PermissionKickMembers
 }
 
+// GuildOnly reports that the command only works in a server, which has
+// members to act on.
+func (c *KickCommand) GuildOnly() bool {
+	return true
+}
+
 // Options returns the command options.
 // The kick command accepts a user and an optional reason.
 func (c *KickCommand) Options() []*discordgo.ApplicationCommandOption {
@@ -66,7 +77,7 @@ func (c *KickCommand) Execute(ctx *Context) error {
 	// Validate cannot kick self
 	if targetUser.ID == ctx.UserID() {
 		return errutil.UserFriendlyError{
-			UserMessage: "You cannot kick yourself.",
+			UserMessage: ctx.T(i18n.MsgKickSelf),
 			Err:         fmt.Errorf("user attempted to kick yourself"),
 		}
 	}
@@ -74,7 +85,7 @@ func (c *KickCommand) Execute(ctx *Context) error {
 	// Validate cannot kick bots
 	if targetUser.Bot {
 		return errutil.UserFriendlyError{
-			UserMessage: "You cannot kick bots.",
+			UserMessage: ctx.T(i18n.MsgKickBot),
 			Err:         fmt.Errorf("user attempted to kick a bot"),
 		}
 	}
@@ -87,28 +98,53 @@ func (c *KickCommand) Execute(ctx *Context) error {
 
 	// Get guild ID
 	guildID := ctx.GuildID()
-	if guildID == "" {
+	if ctx.IsDM() {
 		return errutil.UserFriendlyError{
-			UserMessage: "This command can only be used in a server.",
+			UserMessage: ctx.T(i18n.MsgGuildOnly),
 			Err:         fmt.Errorf("kick command used outside of guild"),
 		}
 	}
 
 	// Check session before making Discord API calls
 	if ctx.Session == nil {
-		return fmt.Errorf("session cannot be nil")
+		return ErrNoSession
+	}
+
+	// Notify before kicking, while the member can still be messaged
+	var notified bool
+	if c.NotifyTarget {
+		notified = notifyTarget(ctx, guildID, targetUser, "kicked from", reason, 0)
 	}
 
 	// Perform the kick
-	err := ctx.Session.GuildMemberDeleteWithReason(guildID, targetUser.ID, reason)
+	err := retryOnRateLimit(func(opts ...discordgo.RequestOption) error {
+		return ctx.Session.GuildMemberDeleteWithReason(guildID, targetUser.ID, reason, opts...)
+	})
 	if err != nil {
+		if msg, limited := rateLimitMessage(ctx, err); limited {
+			return errutil.UserFriendlyError{
+				UserMessage: msg,
+				Err:         fmt.Errorf("failed to kick user %s: %w", targetUser.ID, err),
+			}
+		}
+		if msg, forbidden := forbiddenMessage(ctx, err, i18n.MsgKickForbidden, targetUser.Username); forbidden {
+			return errutil.UserFriendlyError{
+				UserMessage: msg,
+				Err:         fmt.Errorf("failed to kick user %s: %w", targetUser.ID, err),
+			}
+		}
 		return errutil.UserFriendlyError{
-			UserMessage: fmt.Sprintf("Failed to kick %s. I may lack permissions or the user may have a higher role.", targetUser.Username),
+			UserMessage: ctx.T(i18n.MsgKickFailed, targetUser.Username),
 			Err:         fmt.Errorf("failed to kick user %s: %w", targetUser.ID, err),
 		}
 	}
 
+	modLog(ctx, guildID, ModLogEntry{Action: ModLogKicked, TargetID: targetUser.ID, Reason: reason})
+
 	// Respond with success
-	successMsg := fmt.Sprintf("Successfully kicked %s#%s. Reason: %s", targetUser.Username, targetUser.Discriminator, reason)
+	successMsg := ctx.T(i18n.MsgKickSuccess, targetUser.Username, targetUser.Discriminator, reason)
+	if c.NotifyTarget {
+		successMsg += notifyNote(ctx, notified)
+	}
 	return ctx.RespondEphemeral(successMsg)
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"jamesbot/internal/command"
+	"jamesbot/pkg/errutil"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/zerolog"
@@ -423,3 +424,20 @@ func Benchmark_KickCommand_Permissions(b *testing.B) {
 		_ = cmd.Permissions()
 	}
 }
+
+func Test_KickCommand_Execute_LocalizedError(t *testing.T) {
+	cmd := &command.KickCommand{}
+
+	interaction := createKickInteractionWithResolvedUser(
+		"same-user-id", "same-user-id", "guild-123", "channel-456",
+		"some reason", true, false,
+	)
+	interaction.Locale = discordgo.SpanishES
+	ctx := command.NewContext(nil, interaction, kickTestLogger())
+
+	err := cmd.Execute(ctx)
+
+	var userErr errutil.UserFriendlyError
+	require.ErrorAs(t, err, &userErr)
+	assert.Equal(t, "No puedes expulsarte a ti mismo.", userErr.UserMessage)
+}
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MemberCache holds recently fetched guild members for a short time, so
+// commands that look up the same member do not each call Discord. It holds at
+// most a fixed number of members, evicting the one closest to expiry when
+// full. It is safe for concurrent use; a nil MemberCache caches nothing.
+type MemberCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]cachedMember
+}
+
+type cachedMember struct {
+	member  *discordgo.Member
+	expires time.Time
+}
+
+// NewMemberCache creates a cache that keeps members for ttl and holds at most
+// maxEntries of them. It returns nil, a cache that stores nothing, when either
+// is not positive.
+func NewMemberCache(ttl time.Duration, maxEntries int) *MemberCache {
+	if ttl <= 0 || maxEntries <= 0 {
+		return nil
+	}
+	return &MemberCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cachedMember),
+	}
+}
+
+// Get returns the cached member of guildID with userID, if it has not expired.
+func (c *MemberCache) Get(guildID, userID string) (*discordgo.Member, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	key := memberKey(guildID, userID)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.member, true
+}
+
+// Put caches member as a member of guildID. Members without a user are ignored.
+func (c *MemberCache) Put(guildID string, member *discordgo.Member) {
+	if c == nil || member == nil || member.User == nil {
+		return
+	}
+
+	key := memberKey(guildID, member.User.ID)
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cachedMember{member: member, expires: now.Add(c.ttl)}
+}
+
+// Invalidate drops the cached member of guildID with userID, for example when
+// Discord reports that the member changed or left.
+func (c *MemberCache) Invalidate(guildID, userID string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, memberKey(guildID, userID))
+}
+
+// Len returns the number of cached members, including any that have expired
+// but not yet been removed.
+func (c *MemberCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evict removes expired members, or when none have expired, the member that
+// expires soonest. Callers must hold c.mu.
+func (c *MemberCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+func memberKey(guildID, userID string) string {
+	return guildID + "/" + userID
+}
@@ -0,0 +1,165 @@
+package command_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMember(userID string) *discordgo.Member {
+	return &discordgo.Member{User: &discordgo.User{ID: userID, Username: "user-" + userID}}
+}
+
+// ============================================================================
+// MemberCache Tests
+// ============================================================================
+
+func Test_MemberCache_GetPut(t *testing.T) {
+	cache := command.NewMemberCache(time.Minute, 10)
+	member := testMember("u1")
+
+	_, ok := cache.Get("g1", "u1")
+	assert.False(t, ok, "empty cache should miss")
+
+	cache.Put("g1", member)
+
+	got, ok := cache.Get("g1", "u1")
+	require.True(t, ok)
+	assert.Same(t, member, got)
+
+	_, ok = cache.Get("g2", "u1")
+	assert.False(t, ok, "members are cached per guild")
+
+	cache.Invalidate("g1", "u1")
+	_, ok = cache.Get("g1", "u1")
+	assert.False(t, ok, "invalidated member should miss")
+}
+
+func Test_MemberCache_Expires(t *testing.T) {
+	cache := command.NewMemberCache(20*time.Millisecond, 10)
+	cache.Put("g1", testMember("u1"))
+
+	assert.Eventually(t, func() bool {
+		_, ok := cache.Get("g1", "u1")
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+	assert.Zero(t, cache.Len(), "an expired member should be removed on lookup")
+}
+
+func Test_MemberCache_Bounded(t *testing.T) {
+	cache := command.NewMemberCache(time.Minute, 3)
+
+	for i := range 10 {
+		cache.Put("g1", testMember(fmt.Sprint(i)))
+	}
+
+	assert.Equal(t, 3, cache.Len())
+	_, ok := cache.Get("g1", "9")
+	assert.True(t, ok, "the newest member should be kept")
+	_, ok = cache.Get("g1", "0")
+	assert.False(t, ok, "the oldest member should be evicted")
+}
+
+func Test_MemberCache_Disabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		maxEntries int
+	}{
+		{name: "zero ttl", ttl: 0, maxEntries: 10},
+		{name: "zero size", ttl: time.Minute, maxEntries: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := command.NewMemberCache(tt.ttl, tt.maxEntries)
+			assert.Nil(t, cache)
+
+			cache.Put("g1", testMember("u1"))
+			cache.Invalidate("g1", "u1")
+			_, ok := cache.Get("g1", "u1")
+			assert.False(t, ok)
+			assert.Zero(t, cache.Len())
+		})
+	}
+}
+
+// ============================================================================
+// Context.GuildMember Tests
+// ============================================================================
+
+func Test_Context_GuildMember(t *testing.T) {
+	const memberPath = "GET /api/v9/guilds/guild-1/members/user-2"
+	memberJSON := `{"user":{"id":"user-2","username":"target"},"roles":["role-1"]}`
+
+	tests := []struct {
+		name         string
+		cache        *command.MemberCache
+		guildID      string
+		lookups      int
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "uncached lookups each call Discord",
+			guildID:      "guild-1",
+			lookups:      3,
+			wantRequests: 3,
+		},
+		{
+			name:         "cached lookups call Discord once",
+			cache:        command.NewMemberCache(time.Minute, 10),
+			guildID:      "guild-1",
+			lookups:      3,
+			wantRequests: 1,
+		},
+		{
+			name:    "outside a guild",
+			cache:   command.NewMemberCache(time.Minute, 10),
+			lookups: 1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			rt.responses = map[string]string{memberPath: memberJSON}
+			interaction := createTestInteractionCreate("user-1", tt.guildID, "channel-1", nil)
+			ctx := command.NewContext(s, interaction, testLogger(), command.WithMemberCache(tt.cache))
+
+			for range tt.lookups {
+				member, err := ctx.GuildMember("user-2")
+				if tt.wantErr {
+					assert.Error(t, err)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, "target", member.User.Username)
+				assert.Equal(t, []string{"role-1"}, member.Roles)
+			}
+
+			assert.Len(t, rt.recorded(), tt.wantRequests)
+		})
+	}
+}
+
+func Test_Context_GuildMember_NoSession(t *testing.T) {
+	cache := command.NewMemberCache(time.Minute, 10)
+	cache.Put("guild-1", testMember("user-2"))
+	interaction := createTestInteractionCreate("user-1", "guild-1", "channel-1", nil)
+	ctx := command.NewContext(nil, interaction, testLogger(), command.WithMemberCache(cache))
+
+	member, err := ctx.GuildMember("user-2")
+	require.NoError(t, err, "cached members need no session")
+	assert.Equal(t, "user-2", member.User.ID)
+
+	_, err = ctx.GuildMember("user-3")
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"jamesbot/internal/rules"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// Actions named in mod-log entries.
+const (
+	ModLogWarned   = "Warned"
+	ModLogTimedOut = "Timed out"
+	ModLogUnmuted  = "Timeout lifted"
+	ModLogKicked   = "Kicked"
+	ModLogBanned   = "Banned"
+	ModLogUnbanned = "Unbanned"
+)
+
+// ModLogEntry describes a moderation action for a guild's mod-log channel.
+type ModLogEntry struct {
+	// Action is what was done to the target, such as ModLogBanned.
+	Action string
+
+	// TargetID is the ID of the user acted on.
+	TargetID string
+
+	// ModeratorID is the ID of the member who acted, or empty when the bot
+	// acted on its own.
+	ModeratorID string
+
+	// Source says what acted when no moderator did, such as "warn
+	// escalation". It may be empty.
+	Source string
+
+	// Reason is why, as recorded in the audit log. It may be empty.
+	Reason string
+
+	// Duration is how long a timeout lasts, or zero for other actions.
+	Duration time.Duration
+}
+
+// String formats the entry as a mod-log message.
+func (e ModLogEntry) String() string {
+	msg := fmt.Sprintf("**%s** <@%s> (%s)", e.Action, e.TargetID, e.TargetID)
+	if e.ModeratorID != "" {
+		msg += fmt.Sprintf(" by <@%s>", e.ModeratorID)
+	}
+	if e.Source != "" {
+		msg += " via " + e.Source
+	}
+	if e.Duration > 0 {
+		msg += " for " + formatDuration(e.Duration)
+	}
+	if e.Reason != "" {
+		msg += "\nReason: " + e.Reason
+	}
+	return msg
+}
+
+// PostModLog posts entry to the mod-log channel set for guildID in set, if
+// any. Mentions in it do not ping anyone. Failures are logged and never undo
+// or block the action, which has already been taken.
+func PostModLog(s *discordgo.Session, set *rules.Set, guildID string, entry ModLogEntry, logger zerolog.Logger) {
+	channelID := set.GuildSettings(guildID).ModLogChannel
+	if s == nil || channelID == "" {
+		return
+	}
+	if _, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         entry.String(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+	}); err != nil {
+		logger.Error().
+			Err(err).
+			Str("channel_id", channelID).
+			Str("action", entry.Action).
+			Str("target_id", entry.TargetID).
+			Msg("failed to post to mod-log channel")
+	}
+}
+
+// modLog posts entry, for an action taken by the command's invoker, to the
+// mod-log channel of the guild the command ran in.
+func modLog(ctx *Context, guildID string, entry ModLogEntry) {
+	entry.ModeratorID = ctx.UserID()
+	PostModLog(ctx.Session, ctx.Settings, guildID, entry, ctx.Logger)
+}
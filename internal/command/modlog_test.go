@@ -0,0 +1,123 @@
+package command_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/rules"
+	"jamesbot/internal/warnings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Mod-Log Tests
+// ============================================================================
+
+func Test_ModLogEntry_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry command.ModLogEntry
+		want  string
+	}{
+		{
+			name:  "by a moderator",
+			entry: command.ModLogEntry{Action: command.ModLogKicked, TargetID: "456", ModeratorID: "123", Reason: "spam"},
+			want:  "**Kicked** <@456> (456) by <@123>\nReason: spam",
+		},
+		{
+			name:  "automatic timeout",
+			entry: command.ModLogEntry{Action: command.ModLogTimedOut, TargetID: "456", Source: "warn escalation", Duration: 2 * time.Hour},
+			want:  "**Timed out** <@456> (456) via warn escalation for 2h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.entry.String())
+		})
+	}
+}
+
+func Test_ModerationCommands_ModLog(t *testing.T) {
+	const postModLog = http.MethodPost + " /api/v9/channels/111/messages"
+
+	tests := []struct {
+		name    string
+		cmd     command.Command
+		event   *discordgo.InteractionCreate
+		channel string
+		want    string
+	}{
+		{
+			name:    "kick",
+			cmd:     &command.KickCommand{},
+			event:   createKickInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "spam", true, false),
+			channel: "111",
+			want:    "**Kicked** <@target-456> (target-456) by <@moderator-123>\nReason: spam",
+		},
+		{
+			name:    "ban",
+			cmd:     &command.BanCommand{},
+			event:   createBanInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", 0, false, "spam", true, false),
+			channel: "111",
+			want:    "**Banned** <@target-456> (target-456) by <@moderator-123>\nReason: spam",
+		},
+		{
+			name:    "mute",
+			cmd:     &command.MuteCommand{},
+			event:   createMuteInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "2h", "spam", true, false),
+			channel: "111",
+			want:    "**Timed out** <@target-456> (target-456) by <@moderator-123> for 2h\nReason: spam",
+		},
+		{
+			name:    "warn",
+			cmd:     &command.WarnCommand{Warnings: warnings.NewStore()},
+			event:   createWarnInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "spam", false),
+			channel: "111",
+			want:    "**Warned** <@target-456> (target-456) by <@moderator-123>\nReason: spam",
+		},
+		{
+			name:  "no mod-log channel",
+			cmd:   &command.KickCommand{},
+			event: createKickInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "spam", true, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rules.NewSet(rules.Defaults()...)
+			if tt.channel != "" {
+				require.NoError(t, set.SetGuildRule("guild-789", rules.RuleSettings, rules.KeyModLogChannel, tt.channel))
+			}
+			session, rt := newRecordingSession(t)
+
+			ctx := command.NewContext(session, tt.event, zerolog.Nop(), command.WithGuildSettings(set))
+			require.NoError(t, tt.cmd.Execute(ctx))
+
+			var posted []string
+			for _, req := range rt.recorded() {
+				if req.Method+" "+req.Path != postModLog {
+					continue
+				}
+				var msg discordgo.MessageSend
+				require.NoError(t, json.Unmarshal(req.Body, &msg))
+				require.NotNil(t, msg.AllowedMentions, "mod-log entries should not ping anyone")
+				assert.Empty(t, msg.AllowedMentions.Parse)
+				posted = append(posted, msg.Content)
+			}
+
+			if tt.want == "" {
+				assert.Empty(t, posted)
+				return
+			}
+			assert.Equal(t, []string{tt.want}, posted)
+		})
+	}
+}
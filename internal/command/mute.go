@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"jamesbot/internal/i18n"
 	"jamesbot/pkg/errutil"
 
 	"github.com/bwmarrin/discordgo"
@@ -12,7 +13,16 @@ import (
 
 // MuteCommand implements a command to timeout/mute members in the server.
 // It requires the Moderate Members permission to execute.
-type MuteCommand struct{}
+//
+// Mutes use Discord's native member timeout rather than a muted role, so every
+// mute is temporary: Discord lifts it when the duration elapses. No scheduled
+// unmute or persisted state is needed, and a bot restart cannot leave a member
+// muted indefinitely. A moderator removing the timeout early needs no cleanup.
+type MuteCommand struct {
+	// NotifyTarget DMs the member the action, reason, and duration before
+	// acting. Members with DMs disabled are timed out without notice.
+	NotifyTarget bool
+}
 
 // Name returns the command name.
 func (c *MuteCommand) Name() string {
@@ -30,6 +40,12 @@ func (c *MuteCommand) Permissions() int64 {
 	return discordgo.PermissionModerateMembers
 }
 
+// GuildOnly reports that the command only works in a server, which has
+// members to act on.
+func (c *MuteCommand) GuildOnly() bool {
+	return true
+}
+
 // Options returns the command options.
 // The mute command accepts a user, a duration, and an optional reason.
 func (c *MuteCommand) Options() []*discordgo.ApplicationCommandOption {
@@ -74,7 +90,7 @@ func (c *MuteCommand) Execute(ctx *Context) error {
 	// Validate cannot mute self
 	if targetUser.ID == ctx.UserID() {
 		return errutil.UserFriendlyError{
-			UserMessage: "You cannot timeout yourself.",
+			UserMessage: ctx.T(i18n.MsgMuteSelf),
 			Err:         fmt.Errorf("user attempted to mute yourself"),
 		}
 	}
@@ -82,7 +98,7 @@ func (c *MuteCommand) Execute(ctx *Context) error {
 	// Validate cannot mute bots
 	if targetUser.Bot {
 		return errutil.UserFriendlyError{
-			UserMessage: "You cannot timeout bots.",
+			UserMessage: ctx.T(i18n.MsgMuteBot),
 			Err:         fmt.Errorf("user attempted to mute a bot"),
 		}
 	}
@@ -105,7 +121,7 @@ func (c *MuteCommand) Execute(ctx *Context) error {
 	duration, err := time.ParseDuration(durationStr)
 	if err != nil {
 		return errutil.UserFriendlyError{
-			UserMessage: "Invalid duration format. Use formats like: 1h, 30m, 2d",
+			UserMessage: ctx.T(i18n.MsgMuteInvalidDuration),
 			Err:         fmt.Errorf("failed to parse duration %s: %w", durationStr, err),
 		}
 	}
@@ -141,33 +157,58 @@ func (c *MuteCommand) Execute(ctx *Context) error {
 
 	// Get guild ID
 	guildID := ctx.GuildID()
-	if guildID == "" {
+	if ctx.IsDM() {
 		return errutil.UserFriendlyError{
-			UserMessage: "This command can only be used in a server.",
+			UserMessage: ctx.T(i18n.MsgGuildOnly),
 			Err:         fmt.Errorf("mute command used outside of guild"),
 		}
 	}
 
 	// Check session before making Discord API calls
 	if ctx.Session == nil {
-		return fmt.Errorf("session cannot be nil")
+		return ErrNoSession
 	}
 
 	// Calculate timeout end time
 	timeoutUntil := time.Now().Add(duration)
 
+	// Notify before the timeout, like kick and ban
+	var notified bool
+	if c.NotifyTarget {
+		notified = notifyTarget(ctx, guildID, targetUser, "timed out in", reason, duration)
+	}
+
 	// Perform the timeout
-	err = ctx.Session.GuildMemberTimeout(guildID, targetUser.ID, &timeoutUntil)
+	err = retryOnRateLimit(func(opts ...discordgo.RequestOption) error {
+		return ctx.Session.GuildMemberTimeout(guildID, targetUser.ID, &timeoutUntil, opts...)
+	})
 	if err != nil {
+		if msg, limited := rateLimitMessage(ctx, err); limited {
+			return errutil.UserFriendlyError{
+				UserMessage: msg,
+				Err:         fmt.Errorf("failed to timeout user %s: %w", targetUser.ID, err),
+			}
+		}
+		if msg, forbidden := forbiddenMessage(ctx, err, i18n.MsgMuteForbidden, targetUser.Username); forbidden {
+			return errutil.UserFriendlyError{
+				UserMessage: msg,
+				Err:         fmt.Errorf("failed to timeout user %s: %w", targetUser.ID, err),
+			}
+		}
 		return errutil.UserFriendlyError{
-			UserMessage: fmt.Sprintf("Failed to timeout %s. I may lack permissions or the user may have a higher role.", targetUser.Username),
+			UserMessage: ctx.T(i18n.MsgMuteFailed, targetUser.Username),
 			Err:         fmt.Errorf("failed to timeout user %s: %w", targetUser.ID, err),
 		}
 	}
 
+	modLog(ctx, guildID, ModLogEntry{Action: ModLogTimedOut, TargetID: targetUser.ID, Reason: reason, Duration: duration})
+
 	// Respond with success
-	successMsg := fmt.Sprintf("Successfully timed out %s#%s for %s. Reason: %s",
+	successMsg := ctx.T(i18n.MsgMuteSuccess,
 		targetUser.Username, targetUser.Discriminator, formatDuration(duration), reason)
+	if c.NotifyTarget {
+		successMsg += notifyNote(ctx, notified)
+	}
 	return ctx.RespondEphemeral(successMsg)
 }
 
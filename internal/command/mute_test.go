@@ -1,9 +1,12 @@
 package command_test
 
 import (
+	"encoding/json"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"jamesbot/internal/command"
 
@@ -498,6 +501,52 @@ func Test_MuteCommand_Execute_ValidDurations(t *testing.T) {
 	}
 }
 
+// Test_MuteCommand_Execute_TimeoutExpiresAfterDuration verifies the timeout sent
+// to Discord ends after the requested duration, which is what lifts the mute.
+func Test_MuteCommand_Execute_TimeoutExpiresAfterDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration string
+		want     time.Duration
+	}{
+		{name: "minutes", duration: "30m", want: 30 * time.Minute},
+		{name: "hours", duration: "2h", want: 2 * time.Hour},
+		{name: "days", duration: "3d", want: 72 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			interaction := createMuteInteractionWithResolvedUser(
+				"moderator-123", "target-456", "guild-789", "channel-012",
+				tt.duration, "reason", true, false,
+			)
+			ctx := command.NewContext(session, interaction, muteTestLogger())
+
+			before := time.Now()
+			require.NoError(t, (&command.MuteCommand{}).Execute(ctx))
+			after := time.Now()
+
+			var patch *recordedRequest
+			for _, req := range rt.recorded() {
+				if req.Method == http.MethodPatch && strings.HasSuffix(req.Path, "/guilds/guild-789/members/target-456") {
+					patch = &req
+					break
+				}
+			}
+			require.NotNil(t, patch, "expected a member timeout request")
+
+			var body struct {
+				Until *time.Time `json:"communication_disabled_until"`
+			}
+			require.NoError(t, json.Unmarshal(patch.Body, &body))
+			require.NotNil(t, body.Until)
+			assert.WithinRange(t, *body.Until,
+				before.Add(tt.want).Truncate(time.Second), after.Add(tt.want).Add(time.Second))
+		})
+	}
+}
+
 func Test_MuteCommand_ImplementsCommandInterface(t *testing.T) {
 	// This test verifies that MuteCommand implements the Command interface
 	// If this compiles, MuteCommand satisfies command.Command
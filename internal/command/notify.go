@@ -0,0 +1,52 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// notifyTarget DMs target that they are being acted on in the guild, e.g.
+// "You have been banned from <guild>.", with the reason and, when non-zero,
+// the duration. It must run before the action itself: once kicked or banned,
+// a member who shares no other server with the bot can no longer be messaged.
+// Failures, usually the member having DMs disabled, are logged at debug level
+// and never block the action. It reports whether the DM was delivered.
+func notifyTarget(ctx *Context, guildID string, target *discordgo.User, action, reason string, duration time.Duration) bool {
+	msg := fmt.Sprintf("You have been %s %s", action, guildName(ctx, guildID))
+	if duration > 0 {
+		msg += " for " + formatDuration(duration)
+	}
+	msg += fmt.Sprintf(".\nReason: %s", reason)
+
+	dmChannel, err := ctx.Session.UserChannelCreate(target.ID)
+	if err == nil {
+		_, err = ctx.Session.ChannelMessageSend(dmChannel.ID, msg)
+	}
+	if err != nil {
+		ctx.Logger.Debug().Err(err).Str("user_id", target.ID).Msg("could not DM moderation target")
+		return false
+	}
+	return true
+}
+
+// notifyNote returns the note for a moderator's confirmation describing
+// whether the target was DMed.
+func notifyNote(ctx *Context, sent bool) string {
+	if sent {
+		return ctx.T(i18n.MsgNotified)
+	}
+	return ctx.T(i18n.MsgNotNotified)
+}
+
+// guildName returns the name of the guild, or "this server" if it cannot be fetched.
+func guildName(ctx *Context, guildID string) string {
+	guild, err := ctx.Session.Guild(guildID)
+	if err != nil || guild == nil {
+		return "this server"
+	}
+	return guild.Name
+}
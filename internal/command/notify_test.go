@@ -0,0 +1,140 @@
+package command_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Moderation Target Notification Tests
+// ============================================================================
+
+func Test_ModerationCommands_NotifyTarget(t *testing.T) {
+	const (
+		openDM  = http.MethodPost + " /api/v9/users/@me/channels"
+		sendDM  = http.MethodPost + " /api/v9/channels/dm-1/messages"
+		getInfo = http.MethodGet + " /api/v9/guilds/guild-789"
+	)
+
+	tests := []struct {
+		name       string
+		cmd        command.Command
+		event      *discordgo.InteractionCreate
+		action     string
+		wantDM     string
+		wantNotice bool
+	}{
+		{
+			name:       "kick",
+			cmd:        &command.KickCommand{NotifyTarget: true},
+			event:      createKickInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "spam", true, false),
+			action:     http.MethodDelete + " /api/v9/guilds/guild-789/members/target-456",
+			wantDM:     "You have been kicked from Gophers.\nReason: spam",
+			wantNotice: true,
+		},
+		{
+			name:       "ban",
+			cmd:        &command.BanCommand{NotifyTarget: true},
+			event:      createBanInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", 0, false, "spam", true, false),
+			action:     http.MethodPut + " /api/v9/guilds/guild-789/bans/target-456",
+			wantDM:     "You have been banned from Gophers.\nReason: spam",
+			wantNotice: true,
+		},
+		{
+			name:       "mute includes the duration",
+			cmd:        &command.MuteCommand{NotifyTarget: true},
+			event:      createMuteInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "2h", "spam", true, false),
+			action:     http.MethodPatch + " /api/v9/guilds/guild-789/members/target-456",
+			wantDM:     "You have been timed out in Gophers for 2h.\nReason: spam",
+			wantNotice: true,
+		},
+		{
+			name:   "disabled by default",
+			cmd:    &command.BanCommand{},
+			event:  createBanInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", 0, false, "spam", true, false),
+			action: http.MethodPut + " /api/v9/guilds/guild-789/bans/target-456",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			rt.responses = map[string]string{
+				openDM:  `{"id":"dm-1","type":1}`,
+				sendDM:  `{"id":"msg-1"}`,
+				getInfo: `{"id":"guild-789","name":"Gophers"}`,
+			}
+
+			require.NoError(t, tt.cmd.Execute(command.NewContext(session, tt.event, zerolog.Nop())))
+
+			lines := make([]string, 0)
+			var dm, response string
+			for _, req := range rt.recorded() {
+				line := req.Method + " " + req.Path
+				lines = append(lines, line)
+				switch {
+				case line == sendDM:
+					var msg discordgo.MessageSend
+					require.NoError(t, json.Unmarshal(req.Body, &msg))
+					dm = msg.Content
+				case strings.HasSuffix(req.Path, "/callback"):
+					response = string(req.Body)
+				}
+			}
+
+			assert.Equal(t, tt.wantDM, dm)
+			if tt.wantDM == "" {
+				assert.NotContains(t, lines, openDM)
+				assert.NotContains(t, response, "DM")
+				return
+			}
+			assert.Less(t, indexOf(lines, sendDM), indexOf(lines, tt.action), "the DM must be sent before the action: %v", lines)
+			assert.Contains(t, response, "notified via DM")
+		})
+	}
+}
+
+func Test_ModerationCommands_NotifyTarget_DMsDisabled(t *testing.T) {
+	session, rt := newRecordingSession(t)
+	// The DM channel opens, but the message send fails with no JSON body,
+	// as it would for a member who does not accept DMs.
+	rt.responses = map[string]string{
+		http.MethodPost + " /api/v9/users/@me/channels": `{"id":"dm-1","type":1}`,
+	}
+	cmd := &command.BanCommand{NotifyTarget: true}
+	event := createBanInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", 0, false, "spam", true, false)
+
+	require.NoError(t, cmd.Execute(command.NewContext(session, event, zerolog.Nop())))
+
+	var banned bool
+	var response string
+	for _, req := range rt.recorded() {
+		if req.Method == http.MethodPut && req.Path == "/api/v9/guilds/guild-789/bans/target-456" {
+			banned = true
+		}
+		if strings.HasSuffix(req.Path, "/callback") {
+			response = string(req.Body)
+		}
+	}
+	assert.True(t, banned, "a failed DM must not prevent the ban")
+	assert.Contains(t, response, "Unable to send DM")
+}
+
+// indexOf returns the position of want in lines, or -1.
+func indexOf(lines []string, want string) int {
+	for i, line := range lines {
+		if line == want {
+			return i
+		}
+	}
+	return -1
+}
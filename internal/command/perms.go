@@ -0,0 +1,195 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// permissionNames holds the names Discord shows for the permissions commands
+// commonly require, keyed by bit.
+var permissionNames = map[int64]string{
+	discordgo.PermissionCreateInstantInvite: "Create Invite",
+	discordgo.PermissionKickMembers:         "Kick Members",
+	discordgo.PermissionBanMembers:          "Ban Members",
+	discordgo.PermissionAdministrator:       "Administrator",
+	discordgo.PermissionManageChannels:      "Manage Channels",
+	discordgo.PermissionManageGuild:         "Manage Server",
+	discordgo.PermissionAddReactions:        "Add Reactions",
+	discordgo.PermissionViewAuditLogs:       "View Audit Log",
+	discordgo.PermissionViewChannel:         "View Channel",
+	discordgo.PermissionSendMessages:        "Send Messages",
+	discordgo.PermissionManageMessages:      "Manage Messages",
+	discordgo.PermissionEmbedLinks:          "Embed Links",
+	discordgo.PermissionAttachFiles:         "Attach Files",
+	discordgo.PermissionReadMessageHistory:  "Read Message History",
+	discordgo.PermissionMentionEveryone:     "Mention Everyone",
+	discordgo.PermissionManageNicknames:     "Manage Nicknames",
+	discordgo.PermissionManageRoles:         "Manage Roles",
+	discordgo.PermissionManageWebhooks:      "Manage Webhooks",
+	discordgo.PermissionModerateMembers:     "Timeout Members",
+}
+
+// PermsCommand implements a command that checks the bot's own permissions in
+// the channel it is used in against those the registered commands require,
+// so a moderator can see which commands will fail before running them.
+// It requires the Manage Server permission to execute.
+type PermsCommand struct {
+	// Commands returns the commands to check. When nil, there is nothing to
+	// check.
+	Commands func() []Command
+}
+
+// Name returns the command name.
+func (c *PermsCommand) Name() string {
+	return "perms"
+}
+
+// Description returns the command description.
+func (c *PermsCommand) Description() string {
+	return "Check that I have the permissions my commands need here"
+}
+
+// Permissions returns the required Discord permissions.
+// Users must have the Manage Server permission to execute this command, since
+// it reveals how the server's roles are set up.
+func (c *PermsCommand) Permissions() int64 {
+	return discordgo.PermissionManageGuild
+}
+
+// GuildOnly reports that the command only works in a server, where the bot
+// has roles to check.
+func (c *PermsCommand) GuildOnly() bool {
+	return true
+}
+
+// Options returns the command options.
+// The perms command takes no options.
+func (c *PermsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+// Execute runs the perms command.
+// It responds ephemerally with a checklist of the permissions the registered
+// commands require, marking those the bot lacks in this channel along with
+// the commands that won't work without them.
+func (c *PermsCommand) Execute(ctx *Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	granted, err := botPermissions(ctx)
+	if err != nil {
+		ctx.Logger.Warn().Err(err).Msg("failed to compute bot permissions")
+		return ctx.RespondEphemeral(ctx.T(i18n.MsgPermsUnknown))
+	}
+	// Administrators have every permission, including ones newer than
+	// discordgo.PermissionAll.
+	admin := granted&discordgo.PermissionAdministrator != 0
+
+	required := c.requirements()
+	if len(required) == 0 {
+		return ctx.RespondEphemeral(ctx.T(i18n.MsgPermsNone))
+	}
+
+	bits := make([]int64, 0, len(required))
+	for bit := range required {
+		bits = append(bits, bit)
+	}
+	sort.Slice(bits, func(i, j int) bool { return bits[i] < bits[j] })
+
+	var sb strings.Builder
+	sb.WriteString(ctx.T(i18n.MsgPermsHeader, ctx.ChannelID()))
+	missing := 0
+	for _, bit := range bits {
+		names := strings.Join(required[bit], ", ")
+		if admin || granted&bit != 0 {
+			fmt.Fprintf(&sb, "\n✅ **%s** — %s", permissionName(bit), names)
+			continue
+		}
+		missing++
+		fmt.Fprintf(&sb, "\n❌ **%s** — %s", permissionName(bit), ctx.T(i18n.MsgPermsWontWork, names))
+	}
+
+	sb.WriteString("\n\n")
+	if missing == 0 {
+		sb.WriteString(ctx.T(i18n.MsgPermsAllGranted))
+	} else {
+		sb.WriteString(ctx.T(i18n.MsgPermsMissing, missing))
+	}
+
+	ctx.Logger.Info().
+		Int64("permissions", granted).
+		Int("missing", missing).
+		Msg("checked bot permissions")
+
+	return ctx.RespondEphemeral(sb.String())
+}
+
+// requirements returns the names of the commands requiring each permission
+// bit, sorted. The perms command itself is left out, since its permission is
+// asked of the member using it rather than needed by the bot.
+func (c *PermsCommand) requirements() map[int64][]string {
+	if c.Commands == nil {
+		return nil
+	}
+
+	required := make(map[int64][]string)
+	for _, cmd := range c.Commands() {
+		pc, ok := cmd.(PermissionedCommand)
+		if !ok || cmd.Name() == c.Name() {
+			continue
+		}
+		perms := pc.Permissions()
+		for bit := int64(1); bit > 0 && bit <= perms; bit <<= 1 {
+			if perms&bit != 0 {
+				required[bit] = append(required[bit], cmd.Name())
+			}
+		}
+	}
+	for _, names := range required {
+		sort.Strings(names)
+	}
+	return required
+}
+
+// botPermissions returns the bot's effective permissions in the invoking
+// channel. Slash commands carry them in the interaction; for text commands
+// they are computed from the state cache, or from Discord if the channel is
+// not cached.
+func botPermissions(ctx *Context) (int64, error) {
+	if ctx.Message == nil && ctx.Interaction != nil && ctx.Interaction.Interaction != nil &&
+		ctx.Interaction.AppPermissions != 0 {
+		return ctx.Interaction.AppPermissions, nil
+	}
+
+	if ctx.Session == nil {
+		return 0, fmt.Errorf("cannot compute permissions: %w", ErrNoSession)
+	}
+	state := ctx.Session.State
+	if state == nil || state.User == nil {
+		return 0, fmt.Errorf("cannot compute permissions: bot user is unknown")
+	}
+
+	perms, err := state.UserChannelPermissions(state.User.ID, ctx.ChannelID())
+	if err == nil {
+		return perms, nil
+	}
+	perms, err = ctx.Session.UserChannelPermissions(state.User.ID, ctx.ChannelID())
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute permissions: %w", err)
+	}
+	return perms, nil
+}
+
+// permissionName returns the name Discord shows for the permission bit.
+func permissionName(bit int64) string {
+	if name, ok := permissionNames[bit]; ok {
+		return name
+	}
+	return fmt.Sprintf("Permission %#x", bit)
+}
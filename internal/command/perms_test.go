@@ -0,0 +1,148 @@
+package command_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPermsInteraction creates a perms interaction in a guild channel, sent
+// to a bot holding appPermissions there.
+func createPermsInteraction(appPermissions int64) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:             "interaction-perms-test",
+			GuildID:        "g1",
+			ChannelID:      "c1",
+			AppPermissions: appPermissions,
+			Member: &discordgo.Member{
+				User:        &discordgo.User{ID: "moderator-123", Username: "moderator"},
+				Permissions: discordgo.PermissionManageGuild,
+			},
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{
+				ID:   "cmd-data-perms",
+				Name: "perms",
+			},
+		},
+	}
+}
+
+// moderationCommandSet returns the built-in commands whose permissions the
+// perms command checks.
+func moderationCommandSet() []command.Command {
+	return []command.Command{
+		&command.PingCommand{},
+		&command.KickCommand{},
+		&command.BanCommand{},
+		&command.MuteCommand{},
+		&command.WarnCommand{},
+		&command.PermsCommand{},
+	}
+}
+
+func Test_PermsCommand_Metadata(t *testing.T) {
+	cmd := &command.PermsCommand{}
+
+	assert.Equal(t, "perms", cmd.Name())
+	assert.NotEmpty(t, cmd.Description())
+	assert.Equal(t, int64(discordgo.PermissionManageGuild), cmd.Permissions())
+	assert.True(t, cmd.GuildOnly())
+	assert.Empty(t, cmd.Options())
+
+	var _ command.PermissionedCommand = (*command.PermsCommand)(nil)
+}
+
+func Test_PermsCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name           string
+		commands       func() []command.Command
+		appPermissions int64
+		wantContains   []string
+		wantMissing    []string
+	}{
+		{
+			name:           "all permissions granted",
+			commands:       moderationCommandSet,
+			appPermissions: discordgo.PermissionKickMembers | discordgo.PermissionBanMembers | discordgo.PermissionModerateMembers,
+			wantContains: []string{
+				"<#c1>",
+				"✅ **Kick Members** — kick",
+				"✅ **Ban Members** — ban",
+				"✅ **Timeout Members** — mute, warn",
+				"I have every permission my commands need here.",
+			},
+			wantMissing: []string{"❌", "Manage Server"},
+		},
+		{
+			name:           "missing permissions are marked with their commands",
+			commands:       moderationCommandSet,
+			appPermissions: discordgo.PermissionKickMembers | discordgo.PermissionSendMessages,
+			wantContains: []string{
+				"✅ **Kick Members** — kick",
+				"❌ **Ban Members** — ban won't work",
+				"❌ **Timeout Members** — mute, warn won't work",
+				"2 permission(s) missing.",
+			},
+			wantMissing: []string{"Send Messages"},
+		},
+		{
+			name:           "administrator has every permission",
+			commands:       moderationCommandSet,
+			appPermissions: discordgo.PermissionAdministrator,
+			wantContains:   []string{"✅ **Timeout Members** — mute, warn", "I have every permission"},
+			wantMissing:    []string{"❌"},
+		},
+		{
+			name:           "no commands need permissions",
+			commands:       func() []command.Command { return []command.Command{&command.PingCommand{}} },
+			appPermissions: discordgo.PermissionSendMessages,
+			wantContains:   []string{"None of my commands need extra permissions."},
+		},
+		{
+			name:           "nil command source",
+			appPermissions: discordgo.PermissionSendMessages,
+			wantContains:   []string{"None of my commands need extra permissions."},
+		},
+		{
+			name:         "permissions unknown",
+			commands:     moderationCommandSet,
+			wantContains: []string{"I couldn't work out my permissions"},
+			wantMissing:  []string{"✅", "❌"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			ctx := command.NewContext(session, createPermsInteraction(tt.appPermissions), warnTestLogger())
+
+			require.NoError(t, (&command.PermsCommand{Commands: tt.commands}).Execute(ctx))
+
+			var response discordgo.InteractionResponse
+			for _, req := range rt.recorded() {
+				if strings.HasSuffix(req.Path, "/callback") {
+					require.NoError(t, json.Unmarshal(req.Body, &response))
+				}
+			}
+			require.NotNil(t, response.Data)
+			assert.Equal(t, discordgo.MessageFlagsEphemeral, response.Data.Flags)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, response.Data.Content, want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, response.Data.Content, missing)
+			}
+		})
+	}
+}
+
+func Test_PermsCommand_Execute_NilContext(t *testing.T) {
+	assert.Error(t, (&command.PermsCommand{}).Execute(nil))
+}
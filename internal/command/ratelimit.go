@@ -0,0 +1,71 @@
+package command
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxRateLimitWait is the longest a command waits before retrying a
+// rate-limited call, keeping it inside Discord's three-second window for
+// responding to an interaction.
+const maxRateLimitWait = 2 * time.Second
+
+// retryOnRateLimit runs a mutating Discord API call with discordgo's built-in
+// rate-limit sleeping disabled, so a 429 surfaces as an error. If Discord asks
+// for a wait of at most maxRateLimitWait, the call is retried once after it.
+// Any other error, or a second 429, is returned unchanged.
+func retryOnRateLimit(call func(opts ...discordgo.RequestOption) error) error {
+	noRetry := discordgo.WithRetryOnRatelimit(false)
+
+	err := call(noRetry)
+	wait, limited := rateLimitWait(err)
+	if !limited || wait > maxRateLimitWait {
+		return err
+	}
+
+	time.Sleep(wait)
+	return call(noRetry)
+}
+
+// rateLimitMessage returns a user-facing message, in ctx's locale, if err is
+// a Discord 429.
+func rateLimitMessage(ctx *Context, err error) (string, bool) {
+	wait, limited := rateLimitWait(err)
+	if !limited {
+		return "", false
+	}
+
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return ctx.T(i18n.MsgRateLimited, seconds), true
+}
+
+// rateLimitWait reports whether err is a Discord 429 and how long Discord
+// asked the caller to wait.
+func rateLimitWait(err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	var rlErr *discordgo.RateLimitError
+	if errors.As(err, &rlErr) && rlErr.RateLimit != nil && rlErr.TooManyRequests != nil {
+		return rlErr.RetryAfter, true
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusTooManyRequests {
+		seconds, _ := strconv.ParseFloat(restErr.Response.Header.Get("Retry-After"), 64)
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+
+	return 0, false
+}
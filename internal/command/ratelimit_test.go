@@ -0,0 +1,122 @@
+package command_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moderationCall describes a moderation command and the Discord request it makes.
+type moderationCall struct {
+	name        string
+	cmd         command.Command
+	interaction func() *discordgo.InteractionCreate
+	method      string
+	pathSuffix  string
+}
+
+func moderationCalls() []moderationCall {
+	return []moderationCall{
+		{
+			name: "kick",
+			cmd:  &command.KickCommand{},
+			interaction: func() *discordgo.InteractionCreate {
+				return createKickInteractionWithResolvedUser("mod-1", "target-1", "guild-1", "channel-1", "spam", true, false)
+			},
+			method:     http.MethodDelete,
+			pathSuffix: "/guilds/guild-1/members/target-1",
+		},
+		{
+			name: "ban",
+			cmd:  &command.BanCommand{},
+			interaction: func() *discordgo.InteractionCreate {
+				return createBanInteractionWithResolvedUser("mod-1", "target-1", "guild-1", "channel-1", 0, false, "spam", true, false)
+			},
+			method:     http.MethodPut,
+			pathSuffix: "/guilds/guild-1/bans/target-1",
+		},
+		{
+			name: "mute",
+			cmd:  &command.MuteCommand{},
+			interaction: func() *discordgo.InteractionCreate {
+				return createMuteInteractionWithResolvedUser("mod-1", "target-1", "guild-1", "channel-1", "10m", "spam", true, false)
+			},
+			method:     http.MethodPatch,
+			pathSuffix: "/guilds/guild-1/members/target-1",
+		},
+	}
+}
+
+func Test_ModerationCommands_RateLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		rateLimited  int
+		retryAfter   float64
+		wantCalls    int
+		wantResponse bool
+		wantMessage  string
+	}{
+		{
+			name:         "single 429 is retried",
+			rateLimited:  1,
+			retryAfter:   0.01,
+			wantCalls:    2,
+			wantResponse: true,
+		},
+		{
+			name:        "second 429 is reported",
+			rateLimited: 2,
+			retryAfter:  0.01,
+			wantCalls:   2,
+			wantMessage: "Discord is rate limiting this action. Try again in 1s.",
+		},
+		{
+			name:        "long wait is reported without retrying",
+			rateLimited: 1,
+			retryAfter:  4.2,
+			wantCalls:   1,
+			wantMessage: "Discord is rate limiting this action. Try again in 5s.",
+		},
+	}
+
+	for _, mc := range moderationCalls() {
+		for _, tt := range tests {
+			t.Run(mc.name+"/"+tt.name, func(t *testing.T) {
+				session, rt := newRecordingSession(t)
+				rt.rateLimited = tt.rateLimited
+				rt.retryAfter = tt.retryAfter
+				ctx := command.NewContext(session, mc.interaction(), testLogger())
+
+				err := mc.cmd.Execute(ctx)
+
+				calls, responded := 0, false
+				for _, req := range rt.recorded() {
+					if req.Method == mc.method && strings.HasSuffix(req.Path, mc.pathSuffix) {
+						calls++
+					}
+					if strings.HasSuffix(req.Path, "/callback") {
+						responded = true
+					}
+				}
+				assert.Equal(t, tt.wantCalls, calls, "moderation requests sent")
+				assert.Equal(t, tt.wantResponse, responded, "success response sent")
+
+				if tt.wantMessage == "" {
+					require.NoError(t, err)
+					return
+				}
+				var friendly errutil.UserFriendlyError
+				require.True(t, errors.As(err, &friendly), "expected a user-friendly error, got %v", err)
+				assert.Equal(t, tt.wantMessage, friendly.UserMessage)
+			})
+		}
+	}
+}
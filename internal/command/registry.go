@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
@@ -26,23 +27,27 @@ func NewRegistry(logger zerolog.Logger) *Registry {
 }
 
 // Register adds a command to the registry.
-// It returns an error if the command is nil or if a command with the same name
-// is already registered.
+// It returns an error wrapping ErrNilCommand if the command is nil,
+// ErrInvalidCommand if its definition would be rejected by Discord, or
+// ErrCommandExists if a command with the same name is already registered.
 func (r *Registry) Register(cmd Command) error {
 	if cmd == nil || reflect.ValueOf(cmd).IsNil() {
-		return fmt.Errorf("cannot register nil command")
+		return fmt.Errorf("cannot register %w", ErrNilCommand)
 	}
 
 	name := cmd.Name()
 	if name == "" {
 		return fmt.Errorf("cannot register command with empty name")
 	}
+	if err := validateDefinition(cmd); err != nil {
+		return fmt.Errorf("cannot register command %q: %w", name, err)
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.commands[name]; exists {
-		return fmt.Errorf("command %q is already registered", name)
+	if existing, exists := r.commands[name]; exists {
+		return fmt.Errorf("command %q is %w %s", name, ErrCommandExists, describeConflict(existing, cmd))
 	}
 
 	r.commands[name] = cmd
@@ -51,6 +56,105 @@ func (r *Registry) Register(cmd Command) error {
 	return nil
 }
 
+// Replace overwrites an existing registration with cmd, matched by name.
+// It is the deliberate alternative to Register's duplicate rejection, intended
+// for swapping an implementation (for example when a plugin reloads).
+// It returns an error if the command is nil, if its definition would be
+// rejected by Discord, or if no command with that name is registered.
+func (r *Registry) Replace(cmd Command) error {
+	if cmd == nil || reflect.ValueOf(cmd).IsNil() {
+		return fmt.Errorf("cannot replace with %w", ErrNilCommand)
+	}
+
+	name := cmd.Name()
+	if name == "" {
+		return fmt.Errorf("cannot replace command with empty name")
+	}
+	if err := validateDefinition(cmd); err != nil {
+		return fmt.Errorf("cannot replace command %q: %w", name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.commands[name]; !exists {
+		return fmt.Errorf("command %q is not registered", name)
+	}
+
+	r.commands[name] = cmd
+	r.logger.Debug().Str("command", name).Msg("replaced command")
+
+	return nil
+}
+
+// ReplaceAll swaps the registered commands for cmds, as when the command set
+// is rebuilt without restarting. Each command is checked as Register checks
+// it, and two commands may not share a name. If any check fails, an error is
+// returned and the registry is left unchanged.
+func (r *Registry) ReplaceAll(cmds []Command) error {
+	commands := make(map[string]Command, len(cmds))
+	for _, cmd := range cmds {
+		if cmd == nil || reflect.ValueOf(cmd).IsNil() {
+			return fmt.Errorf("cannot register %w", ErrNilCommand)
+		}
+
+		name := cmd.Name()
+		if name == "" {
+			return fmt.Errorf("cannot register command with empty name")
+		}
+		if err := validateDefinition(cmd); err != nil {
+			return fmt.Errorf("cannot register command %q: %w", name, err)
+		}
+		if existing, exists := commands[name]; exists {
+			return fmt.Errorf("command %q is %w %s", name, ErrCommandExists, describeConflict(existing, cmd))
+		}
+		commands[name] = cmd
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.commands = commands
+	r.logger.Debug().Int("command_count", len(commands)).Msg("replaced all commands")
+
+	return nil
+}
+
+// describeConflict explains how a command colliding on name relates to the one
+// already registered, distinguishing an accidental double registration from a
+// different implementation claiming the same name.
+func describeConflict(existing, incoming Command) string {
+	if sameInstance(existing, incoming) {
+		return "(the same instance was registered twice)"
+	}
+
+	var diffs []string
+	if reflect.TypeOf(existing) != reflect.TypeOf(incoming) {
+		diffs = append(diffs, fmt.Sprintf("type %T vs %T", existing, incoming))
+	}
+	if existing.Description() != incoming.Description() {
+		diffs = append(diffs, fmt.Sprintf("description %q vs %q", existing.Description(), incoming.Description()))
+	}
+	if !reflect.DeepEqual(existing.Options(), incoming.Options()) {
+		diffs = append(diffs, "options differ")
+	}
+
+	if len(diffs) == 0 {
+		return "(duplicate registration of an identical definition)"
+	}
+	return fmt.Sprintf("by a different implementation (%s)", strings.Join(diffs, "; "))
+}
+
+// sameInstance reports whether two commands are the same pointer.
+// Non-pointer commands are never considered the same instance.
+func sameInstance(a, b Command) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() != reflect.Ptr || vb.Kind() != reflect.Ptr {
+		return false
+	}
+	return va.Type() == vb.Type() && va.Pointer() == vb.Pointer()
+}
+
 // Get retrieves a command by name from the registry.
 // It returns the command and true if found, or nil and false if not found.
 func (r *Registry) Get(name string) (Command, bool) {
@@ -76,7 +180,8 @@ func (r *Registry) All() []Command {
 }
 
 // ApplicationCommands converts all registered commands to Discord application commands.
-// This is used to register commands with Discord's API.
+// This is used to register commands with Discord's API. Register and Replace
+// have already checked each definition against Discord's limits.
 func (r *Registry) ApplicationCommands() []*discordgo.ApplicationCommand {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
 
@@ -95,6 +97,7 @@ func Test_Registry_Register(t *testing.T) {
 		commands    []command.Command
 		wantErr     bool
 		errContains string
+		wantIs      error
 	}{
 		{
 			name: "register valid command",
@@ -120,6 +123,7 @@ func Test_Registry_Register(t *testing.T) {
 			},
 			wantErr:     true,
 			errContains: "already registered",
+			wantIs:      command.ErrCommandExists,
 		},
 		{
 			name: "nil command returns error",
@@ -128,6 +132,7 @@ func Test_Registry_Register(t *testing.T) {
 			},
 			wantErr:     true,
 			errContains: "nil command",
+			wantIs:      command.ErrNilCommand,
 		},
 		{
 			name: "nil command after valid command returns error",
@@ -137,6 +142,7 @@ func Test_Registry_Register(t *testing.T) {
 			},
 			wantErr:     true,
 			errContains: "nil command",
+			wantIs:      command.ErrNilCommand,
 		},
 	}
 
@@ -157,6 +163,7 @@ func Test_Registry_Register(t *testing.T) {
 				require.Error(t, lastErr, "Register should return an error")
 				assert.Contains(t, lastErr.Error(), tt.errContains,
 					"error message should contain %q", tt.errContains)
+				assert.ErrorIs(t, lastErr, tt.wantIs)
 			} else {
 				assert.NoError(t, lastErr, "Register should not return an error")
 			}
@@ -164,6 +171,230 @@ func Test_Registry_Register(t *testing.T) {
 	}
 }
 
+func Test_Registry_Register_DuplicateConflictDetail(t *testing.T) {
+	stringOpt := []*discordgo.ApplicationCommandOption{
+		{Type: discordgo.ApplicationCommandOptionString, Name: "text", Description: "Text"},
+	}
+	sameInstance := newMockCommand("dup")
+
+	tests := []struct {
+		name        string
+		first       command.Command
+		second      command.Command
+		errContains []string
+		errExcludes []string
+	}{
+		{
+			name:        "same instance registered twice",
+			first:       sameInstance,
+			second:      sameInstance,
+			errContains: []string{"already registered", "same instance"},
+			errExcludes: []string{"different implementation"},
+		},
+		{
+			name:        "identical definition from a second instance",
+			first:       newMockCommand("dup"),
+			second:      newMockCommand("dup"),
+			errContains: []string{"already registered", "identical definition"},
+			errExcludes: []string{"different implementation"},
+		},
+		{
+			name:        "description differs",
+			first:       newMockCommandWithOptions("dup", "First", nil),
+			second:      newMockCommandWithOptions("dup", "Second", nil),
+			errContains: []string{"already registered", "different implementation", `"First"`, `"Second"`},
+			errExcludes: []string{"options differ"},
+		},
+		{
+			name:        "options differ",
+			first:       newMockCommandWithOptions("dup", "Same", nil),
+			second:      newMockCommandWithOptions("dup", "Same", stringOpt),
+			errContains: []string{"already registered", "different implementation", "options differ"},
+			errExcludes: []string{"description"},
+		},
+		{
+			name:        "different type",
+			first:       newMockCommandWithOptions("dup", "Same", nil),
+			second:      &mockPermissionedCommand{mockCommand: *newMockCommandWithOptions("dup", "Same", nil)},
+			errContains: []string{"already registered", "different implementation", "type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := command.NewRegistry(discardLogger())
+			require.NoError(t, registry.Register(tt.first))
+
+			err := registry.Register(tt.second)
+
+			require.Error(t, err)
+			for _, want := range tt.errContains {
+				assert.Contains(t, err.Error(), want)
+			}
+			for _, unwanted := range tt.errExcludes {
+				assert.NotContains(t, err.Error(), unwanted)
+			}
+		})
+	}
+}
+
+func Test_Registry_Replace(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(r *command.Registry)
+		replacement command.Command
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "replaces existing command",
+			setup: func(r *command.Registry) {
+				_ = r.Register(newMockCommandWithOptions("swap", "Old", nil))
+			},
+			replacement: newMockCommandWithOptions("swap", "New", nil),
+		},
+		{
+			name:        "errors when name is not registered",
+			setup:       func(r *command.Registry) {},
+			replacement: newMockCommand("missing"),
+			wantErr:     true,
+			errContains: "not registered",
+		},
+		{
+			name:        "errors on nil command",
+			setup:       func(r *command.Registry) {},
+			replacement: nil,
+			wantErr:     true,
+			errContains: "nil",
+		},
+		{
+			name:        "errors on nil pointer command",
+			setup:       func(r *command.Registry) {},
+			replacement: (*mockCommand)(nil),
+			wantErr:     true,
+			errContains: "nil",
+		},
+		{
+			name:        "errors on empty name",
+			setup:       func(r *command.Registry) {},
+			replacement: newMockCommand(""),
+			wantErr:     true,
+			errContains: "empty name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := command.NewRegistry(discardLogger())
+			tt.setup(registry)
+
+			err := registry.Replace(tt.replacement)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+
+			got, ok := registry.Get(tt.replacement.Name())
+			require.True(t, ok)
+			assert.Same(t, tt.replacement, got, "Get should return the replacement")
+			assert.Len(t, registry.All(), 1, "Replace should not add a second entry")
+		})
+	}
+}
+
+func Test_Registry_ReplaceAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmds        []command.Command
+		wantNames   []string
+		errContains string
+	}{
+		{
+			name:      "replaces the command set",
+			cmds:      []command.Command{newMockCommand("ping"), newMockCommand("echo")},
+			wantNames: []string{"echo", "ping"},
+		},
+		{
+			name: "empty set removes every command",
+		},
+		{
+			name:        "duplicate names leave the registry unchanged",
+			cmds:        []command.Command{newMockCommand("ping"), newMockCommand("ping")},
+			wantNames:   []string{"kick", "ping"},
+			errContains: "already registered",
+		},
+		{
+			name:        "invalid definitions leave the registry unchanged",
+			cmds:        []command.Command{newMockCommand("echo"), newMockCommandWithOptions("ban", strings.Repeat("x", 101), nil)},
+			wantNames:   []string{"kick", "ping"},
+			errContains: "ban",
+		},
+		{
+			name:        "nil commands leave the registry unchanged",
+			cmds:        []command.Command{(*mockCommand)(nil)},
+			wantNames:   []string{"kick", "ping"},
+			errContains: "nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := command.NewRegistry(discardLogger())
+			require.NoError(t, registry.Register(newMockCommand("ping")))
+			require.NoError(t, registry.Register(newMockCommand("kick")))
+
+			err := registry.ReplaceAll(tt.cmds)
+
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+			var names []string
+			for _, cmd := range registry.All() {
+				names = append(names, cmd.Name())
+			}
+			sort.Strings(names)
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func Test_Registry_ConcurrentReplaceAndGet(t *testing.T) {
+	registry := command.NewRegistry(discardLogger())
+	require.NoError(t, registry.Register(newMockCommand("hot")))
+
+	const numGoroutines = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, numGoroutines)
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- registry.Replace(newMockCommandWithOptions("hot", fmt.Sprintf("v%d", i), nil))
+		}(i)
+		go func() {
+			defer wg.Done()
+			cmd, ok := registry.Get("hot")
+			assert.True(t, ok, "command should always be present during replacement")
+			assert.NotNil(t, cmd)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Len(t, registry.All(), 1)
+}
+
 func Test_Registry_Get(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -195,16 +426,10 @@ func Test_Registry_Get(t *testing.T) {
 			getCmdName:     "pong",
 			wantFound:      true,
 		},
-		{
-			name:           "case sensitive lookup - exact match",
-			registeredCmds: []string{"Ping"},
-			getCmdName:     "Ping",
-			wantFound:      true,
-		},
 		{
 			name:           "case sensitive lookup - wrong case",
-			registeredCmds: []string{"Ping"},
-			getCmdName:     "ping",
+			registeredCmds: []string{"ping"},
+			getCmdName:     "Ping",
 			wantFound:      false,
 		},
 	}
@@ -484,14 +709,14 @@ func Test_Registry_ConcurrentRegisterAndGet(t *testing.T) {
 		// Register goroutine
 		go func(id int) {
 			defer wg.Done()
-			cmd := newMockCommand("cmd-" + string(rune('A'+id%26)))
+			cmd := newMockCommand("cmd-" + string(rune('a'+id%26)))
 			_ = registry.Register(cmd) // Ignore errors (some will be duplicates)
 		}(i)
 
 		// Get goroutine
 		go func(id int) {
 			defer wg.Done()
-			_, _ = registry.Get("cmd-" + string(rune('A'+id%26)))
+			_, _ = registry.Get("cmd-" + string(rune('a'+id%26)))
 		}(i)
 	}
 
@@ -523,6 +748,131 @@ func Test_Registry_Get_EmptyName(t *testing.T) {
 	assert.Nil(t, cmd, "getting command with empty name should return nil")
 }
 
+// Test that definitions Discord would reject are caught at registration
+func Test_Registry_Register_ValidatesDefinition(t *testing.T) {
+	str := func(name string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionString, Name: name, Description: "An option"}
+	}
+	required := func(opt *discordgo.ApplicationCommandOption) *discordgo.ApplicationCommandOption {
+		opt.Required = true
+		return opt
+	}
+	sub := func(name string, opts ...*discordgo.ApplicationCommandOption) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionSubCommand, Name: name, Description: "A subcommand", Options: opts}
+	}
+	group := func(name string, opts ...*discordgo.ApplicationCommandOption) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionSubCommandGroup, Name: name, Description: "A group", Options: opts}
+	}
+	manyOptions := func(n int) []*discordgo.ApplicationCommandOption {
+		opts := make([]*discordgo.ApplicationCommandOption, n)
+		for i := range opts {
+			opts[i] = str(fmt.Sprintf("opt-%d", i))
+		}
+		return opts
+	}
+	withChoices := func(n int, name, value string) *discordgo.ApplicationCommandOption {
+		opt := str("choice")
+		for i := 0; i < n; i++ {
+			opt.Choices = append(opt.Choices, &discordgo.ApplicationCommandOptionChoice{Name: fmt.Sprintf("%s%d", name, i), Value: value})
+		}
+		return opt
+	}
+	longDescriptions := func() []*discordgo.ApplicationCommandOption {
+		opts := make([]*discordgo.ApplicationCommandOption, 0, 25)
+		for i := 0; i < 25; i++ {
+			opt := withChoices(25, "c", strings.Repeat("v", 10))
+			opt.Name = fmt.Sprintf("opt-%d", i)
+			opt.Description = strings.Repeat("d", 100)
+			opts = append(opts, opt)
+		}
+		return opts
+	}
+
+	tests := []struct {
+		name        string
+		cmdName     string
+		description string
+		options     []*discordgo.ApplicationCommandOption
+		wantErr     string
+	}{
+		{name: "valid command", cmdName: "ping", description: "Ping"},
+		{name: "valid non-latin name", cmdName: "привет", description: "Greet"},
+		{name: "valid options", cmdName: "ban", description: "Ban", options: []*discordgo.ApplicationCommandOption{required(str("user")), str("reason")}},
+		{name: "valid subcommands", cmdName: "role", description: "Roles", options: []*discordgo.ApplicationCommandOption{group("color", sub("set", str("hex"))), sub("list")}},
+		{name: "maximum options", cmdName: "many", description: "Many", options: manyOptions(25)},
+		{name: "uppercase name", cmdName: "Ping", description: "Ping", wantErr: `name "Ping" must be lowercase`},
+		{name: "name with a space", cmdName: "my command", description: "Mine", wantErr: `name "my command" must be 1-32`},
+		{name: "name too long", cmdName: strings.Repeat("a", 33), description: "Long", wantErr: "must be 1-32"},
+		{name: "missing description", cmdName: "ping", wantErr: "description is required"},
+		{name: "description too long", cmdName: "ping", description: strings.Repeat("x", 101), wantErr: "description is 101 characters, at most 100 allowed"},
+		{name: "too many options", cmdName: "many", description: "Many", options: manyOptions(26), wantErr: "26 options given, at most 25 allowed"},
+		{
+			name: "option without description", cmdName: "ban", description: "Ban",
+			options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionUser, Name: "user"}},
+			wantErr: `option "user": description is required`,
+		},
+		{name: "invalid option name", cmdName: "ban", description: "Ban", options: []*discordgo.ApplicationCommandOption{str("User")}, wantErr: `option "User": name "User" must be lowercase`},
+		{name: "duplicate option", cmdName: "ban", description: "Ban", options: []*discordgo.ApplicationCommandOption{str("user"), str("user")}, wantErr: `option "user" is defined more than once`},
+		{name: "required after optional", cmdName: "ban", description: "Ban", options: []*discordgo.ApplicationCommandOption{str("reason"), required(str("user"))}, wantErr: `required option "user" must come before optional options`},
+		{name: "too many choices", cmdName: "pick", description: "Pick", options: []*discordgo.ApplicationCommandOption{withChoices(26, "c", "v")}, wantErr: `option "choice": 26 choices given, at most 25 allowed`},
+		{name: "choice value too long", cmdName: "pick", description: "Pick", options: []*discordgo.ApplicationCommandOption{withChoices(1, "c", strings.Repeat("v", 101))}, wantErr: `choice "c0": value must be at most 100 characters`},
+		{name: "invalid nested option", cmdName: "role", description: "Roles", options: []*discordgo.ApplicationCommandOption{group("color", sub("set", str("Hex")))}, wantErr: `option "color": option "set": option "Hex": name "Hex" must be lowercase`},
+		{name: "subcommands mixed with options", cmdName: "role", description: "Roles", options: []*discordgo.ApplicationCommandOption{sub("list"), str("user")}, wantErr: "subcommands cannot be mixed with other options"},
+		{name: "nested subcommand", cmdName: "role", description: "Roles", options: []*discordgo.ApplicationCommandOption{sub("list", sub("all"))}, wantErr: "subcommands cannot hold subcommands or groups"},
+		{name: "group holding an option", cmdName: "role", description: "Roles", options: []*discordgo.ApplicationCommandOption{group("color", str("hex"))}, wantErr: "subcommand groups can only hold subcommands"},
+		{name: "too long in total", cmdName: "long", description: "Long", options: longDescriptions(), wantErr: "at most 4000 allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := command.NewRegistry(discardLogger())
+
+			err := registry.Register(newMockCommandWithOptions(tt.cmdName, tt.description, tt.options))
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ErrorIs(t, err, command.ErrInvalidCommand)
+			assert.Contains(t, err.Error(), tt.cmdName, "error should name the command")
+			assert.Contains(t, err.Error(), tt.wantErr)
+			_, found := registry.Get(tt.cmdName)
+			assert.False(t, found, "an invalid command should not be registered")
+		})
+	}
+}
+
+func Test_Registry_Replace_ValidatesDefinition(t *testing.T) {
+	registry := command.NewRegistry(discardLogger())
+	require.NoError(t, registry.Register(newMockCommand("ping")))
+
+	err := registry.Replace(newMockCommandWithOptions("ping", strings.Repeat("x", 101), nil))
+
+	assert.ErrorIs(t, err, command.ErrInvalidCommand)
+	cmd, _ := registry.Get("ping")
+	assert.Equal(t, "A mock command for testing", cmd.Description(), "the registered command should be kept")
+}
+
+// Test that every built-in command fits within Discord's limits
+func Test_Registry_Register_BuiltinCommands(t *testing.T) {
+	registry := command.NewRegistry(discardLogger())
+
+	for _, cmd := range []command.Command{
+		&command.PingCommand{},
+		&command.EchoCommand{},
+		&command.KickCommand{},
+		&command.BanCommand{},
+		&command.MuteCommand{},
+		&command.WarnCommand{},
+		&command.ClearWarningsCommand{},
+		&command.SnipeCommand{},
+		&command.ExportRulesCommand{},
+	} {
+		assert.NoError(t, registry.Register(cmd), "built-in command %q should be valid", cmd.Name())
+	}
+}
+
 // Verify that Command interface is properly defined
 func Test_Command_Interface(t *testing.T) {
 	// This test verifies that our mock satisfies the Command interface
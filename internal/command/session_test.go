@@ -0,0 +1,111 @@
+package command_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedRequest is a Discord REST request captured by recordingTransport.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// recordingTransport is an http.RoundTripper that records Discord REST calls
+// and answers them with 204 No Content, so commands can run against a real
+// *discordgo.Session without network access.
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+
+	// rateLimited is the number of leading requests answered with
+	// 429 Too Many Requests, asking the caller to wait retryAfter seconds.
+	rateLimited int
+	retryAfter  float64
+
+	// responses maps "METHOD /path" to a JSON body answered with 200 OK.
+	responses map[string]string
+
+	// failures maps a path suffix to a Discord JSON error code, answered with
+	// 403 Forbidden to requests for paths ending in it.
+	failures map[string]int
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, recordedRequest{Method: req.Method, Path: req.URL.Path, Body: body})
+	response, ok := rt.responses[req.Method+" "+req.URL.Path]
+	limited := rt.rateLimited > 0
+	if limited {
+		rt.rateLimited--
+	}
+	rt.mu.Unlock()
+
+	if limited {
+		payload := fmt.Sprintf(`{"message":"You are being rate limited.","retry_after":%g,"global":false}`, rt.retryAfter)
+		return &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(payload)),
+			Request:    req,
+		}, nil
+	}
+
+	for suffix, code := range rt.failures {
+		if strings.HasSuffix(req.URL.Path, suffix) {
+			payload := fmt.Sprintf(`{"message":"Missing Permissions","code":%d}`, code)
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(payload)),
+				Request:    req,
+			}, nil
+		}
+	}
+
+	if ok {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(response)),
+			Request:    req,
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+func (rt *recordingTransport) recorded() []recordedRequest {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return append([]recordedRequest(nil), rt.requests...)
+}
+
+// newRecordingSession creates a session whose REST calls are captured by the returned transport.
+func newRecordingSession(t *testing.T) (*discordgo.Session, *recordingTransport) {
+	t.Helper()
+	s, err := discordgo.New("Bot test-token")
+	require.NoError(t, err)
+	rt := &recordingTransport{}
+	s.Client = &http.Client{Transport: rt}
+	return s, rt
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxSnipeContent bounds how many characters of a sniped message are shown,
+// keeping the response within Discord's message length limit.
+const maxSnipeContent = 1800
+
+// SnipeCommand implements a command that shows the last message deleted in
+// the channel it is used in, privately to the moderator who asks.
+// It requires the Manage Messages permission to execute.
+type SnipeCommand struct {
+	// Snipes remembers deleted messages. When nil, nothing is remembered and
+	// the command reports that no message was deleted.
+	Snipes *SnipeCache
+}
+
+// Name returns the command name.
+func (c *SnipeCommand) Name() string {
+	return "snipe"
+}
+
+// Description returns the command description.
+func (c *SnipeCommand) Description() string {
+	return "Show the last message deleted in this channel"
+}
+
+// Permissions returns the required Discord permissions.
+// Users must have the Manage Messages permission to execute this command,
+// since it shows content its author chose to remove.
+func (c *SnipeCommand) Permissions() int64 {
+	return discordgo.PermissionManageMessages
+}
+
+// GuildOnly reports that the command only works in a server, whose channels
+// are the only ones remembered.
+func (c *SnipeCommand) GuildOnly() bool {
+	return true
+}
+
+// Options returns the command options.
+// The snipe command takes no options.
+func (c *SnipeCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+// Execute runs the snipe command.
+// It responds ephemerally with the channel's last deleted message, quoted.
+func (c *SnipeCommand) Execute(ctx *Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	msg, ok := c.Snipes.Last(ctx.ChannelID())
+	if !ok {
+		return ctx.RespondEphemeral(ctx.T(i18n.MsgSnipeNone))
+	}
+
+	content := msg.Content
+	if runes := []rune(content); len(runes) > maxSnipeContent {
+		content = string(runes[:maxSnipeContent]) + "..."
+	}
+	quoted := "> " + strings.ReplaceAll(content, "\n", "\n> ")
+
+	ctx.Logger.Info().
+		Str("author_id", msg.AuthorID).
+		Msg("sniped deleted message")
+
+	return ctx.RespondEphemeral(ctx.T(i18n.MsgSnipe, msg.AuthorID, msg.DeletedAt.Unix(), quoted))
+}
@@ -0,0 +1,113 @@
+package command_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createSnipeInteraction creates a snipe interaction in a guild channel.
+func createSnipeInteraction(channelID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        "interaction-snipe-test",
+			GuildID:   "g1",
+			ChannelID: channelID,
+			Member: &discordgo.Member{
+				User: &discordgo.User{ID: "moderator-123", Username: "moderator"},
+			},
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{
+				ID:   "cmd-data-snipe",
+				Name: "snipe",
+			},
+		},
+	}
+}
+
+func Test_SnipeCommand_Metadata(t *testing.T) {
+	cmd := &command.SnipeCommand{}
+
+	assert.Equal(t, "snipe", cmd.Name())
+	assert.NotEmpty(t, cmd.Description())
+	assert.Equal(t, int64(discordgo.PermissionManageMessages), cmd.Permissions())
+	assert.True(t, cmd.GuildOnly())
+	assert.Empty(t, cmd.Options())
+
+	var _ command.PermissionedCommand = (*command.SnipeCommand)(nil)
+}
+
+func Test_SnipeCommand_Execute(t *testing.T) {
+	long := strings.Repeat("a", 2000)
+
+	tests := []struct {
+		name         string
+		snipes       *command.SnipeCache
+		deleted      string
+		wantContains []string
+		wantMissing  string
+	}{
+		{
+			name:         "nothing deleted",
+			snipes:       command.NewSnipeCache(time.Minute),
+			wantContains: []string{"No message has been deleted"},
+		},
+		{
+			name:         "snipes disabled",
+			wantContains: []string{"No message has been deleted"},
+		},
+		{
+			name:         "quotes the deleted message",
+			snipes:       command.NewSnipeCache(time.Minute),
+			deleted:      "oops\nwrong channel",
+			wantContains: []string{"<@author-m1>", "<t:", "> oops\n> wrong channel"},
+		},
+		{
+			name:         "truncates long messages",
+			snipes:       command.NewSnipeCache(time.Minute),
+			deleted:      long,
+			wantContains: []string{strings.Repeat("a", 1800) + "..."},
+			wantMissing:  strings.Repeat("a", 1801),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.deleted != "" {
+				tt.snipes.Add(testGuildMessage("c1", "m1", tt.deleted))
+				tt.snipes.Delete("c1", "m1")
+			}
+
+			session, rt := newRecordingSession(t)
+			ctx := command.NewContext(session, createSnipeInteraction("c1"), warnTestLogger())
+
+			require.NoError(t, (&command.SnipeCommand{Snipes: tt.snipes}).Execute(ctx))
+
+			var response discordgo.InteractionResponse
+			for _, req := range rt.recorded() {
+				if strings.HasSuffix(req.Path, "/callback") {
+					require.NoError(t, json.Unmarshal(req.Body, &response))
+				}
+			}
+			require.NotNil(t, response.Data)
+			assert.Equal(t, discordgo.MessageFlagsEphemeral, response.Data.Flags)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, response.Data.Content, want)
+			}
+			if tt.wantMissing != "" {
+				assert.NotContains(t, response.Data.Content, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func Test_SnipeCommand_Execute_NilContext(t *testing.T) {
+	assert.Error(t, (&command.SnipeCommand{}).Execute(nil))
+}
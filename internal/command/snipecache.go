@@ -0,0 +1,223 @@
+package command
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// SnipeChannelMessages is how many recent messages a SnipeCache keeps per
+	// channel. A message deleted after this many newer ones were sent in its
+	// channel cannot be sniped.
+	SnipeChannelMessages = 50
+
+	// SnipeMaxChannels is how many channels a SnipeCache tracks at once,
+	// dropping the least recently active when full.
+	SnipeMaxChannels = 1000
+)
+
+// SnipedMessage is a deleted message kept by a SnipeCache.
+type SnipedMessage struct {
+	AuthorID  string
+	Content   string
+	SentAt    time.Time
+	DeletedAt time.Time
+}
+
+// SnipeCache remembers recent guild messages so the last one deleted in each
+// channel can be shown by the snipe command. Content is kept only briefly:
+// a message is forgotten once the retention period has passed since it was
+// sent, if it is still up, or since it was deleted. Messages from bots and
+// from excluded channels are never kept. Memory is bounded by
+// SnipeChannelMessages and SnipeMaxChannels.
+// It is safe for concurrent use; a nil SnipeCache keeps nothing.
+type SnipeCache struct {
+	retention time.Duration
+	excluded  map[string]bool
+
+	mu       sync.Mutex
+	channels map[string]*snipeChannel
+}
+
+// snipeChannel holds one channel's recent messages, oldest first, and the
+// last one deleted.
+type snipeChannel struct {
+	recent  []snipeEntry
+	deleted *SnipedMessage
+	active  time.Time
+}
+
+type snipeEntry struct {
+	id  string
+	msg SnipedMessage
+}
+
+// NewSnipeCache creates a cache that keeps messages for retention, ignoring
+// those in excludedChannels. It returns nil, a cache that keeps nothing, when
+// retention is not positive.
+func NewSnipeCache(retention time.Duration, excludedChannels ...string) *SnipeCache {
+	if retention <= 0 {
+		return nil
+	}
+	excluded := make(map[string]bool, len(excludedChannels))
+	for _, id := range excludedChannels {
+		excluded[id] = true
+	}
+	return &SnipeCache{
+		retention: retention,
+		excluded:  excluded,
+		channels:  make(map[string]*snipeChannel),
+	}
+}
+
+// Add remembers a message sent in a guild channel, or replaces the content of
+// one already remembered when it is edited. Direct messages, messages without
+// content, and messages from bots or excluded channels are ignored.
+func (c *SnipeCache) Add(m *discordgo.Message) {
+	if c == nil || m == nil || m.GuildID == "" || m.Content == "" || c.excluded[m.ChannelID] {
+		return
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := c.channels[m.ChannelID]
+	if ch != nil {
+		for i := range ch.recent {
+			if ch.recent[i].id == m.ID {
+				ch.recent[i].msg.Content = m.Content
+				return
+			}
+		}
+	}
+
+	// Edits of messages not remembered may lack an author; only new
+	// messages from members are added
+	if m.Author == nil || m.Author.Bot {
+		return
+	}
+	if ch == nil {
+		if len(c.channels) >= SnipeMaxChannels {
+			c.evict(now)
+		}
+		ch = &snipeChannel{}
+		c.channels[m.ChannelID] = ch
+	}
+
+	ch.prune(now, c.retention)
+	if len(ch.recent) >= SnipeChannelMessages {
+		ch.recent = ch.recent[1:]
+	}
+	ch.recent = append(ch.recent, snipeEntry{
+		id:  m.ID,
+		msg: SnipedMessage{AuthorID: m.Author.ID, Content: m.Content, SentAt: now},
+	})
+	ch.active = now
+}
+
+// Delete records that the message with messageID was deleted from channelID,
+// making it the channel's last deleted message if it is remembered.
+func (c *SnipeCache) Delete(channelID, messageID string) {
+	if c == nil {
+		return
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := c.channels[channelID]
+	if ch == nil {
+		return
+	}
+	ch.prune(now, c.retention)
+	for i, entry := range ch.recent {
+		if entry.id == messageID {
+			msg := entry.msg
+			msg.DeletedAt = now
+			ch.deleted = &msg
+			ch.recent = append(ch.recent[:i], ch.recent[i+1:]...)
+			ch.active = now
+			return
+		}
+	}
+}
+
+// Last returns the message most recently deleted from channelID, if it was
+// deleted within the retention period.
+func (c *SnipeCache) Last(channelID string) (SnipedMessage, bool) {
+	if c == nil {
+		return SnipedMessage{}, false
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := c.channels[channelID]
+	if ch == nil {
+		return SnipedMessage{}, false
+	}
+	ch.prune(now, c.retention)
+	if ch.deleted == nil {
+		return SnipedMessage{}, false
+	}
+	return *ch.deleted, true
+}
+
+// Len returns the number of messages kept, deleted or not, including any that
+// have expired but not yet been removed.
+func (c *SnipeCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, ch := range c.channels {
+		n += len(ch.recent)
+		if ch.deleted != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// prune forgets messages sent, and a deleted message deleted, longer than
+// retention before now.
+func (ch *snipeChannel) prune(now time.Time, retention time.Duration) {
+	cutoff := now.Add(-retention)
+	i := 0
+	for i < len(ch.recent) && !ch.recent[i].msg.SentAt.After(cutoff) {
+		i++
+	}
+	ch.recent = ch.recent[i:]
+	if ch.deleted != nil && !ch.deleted.DeletedAt.After(cutoff) {
+		ch.deleted = nil
+	}
+}
+
+// evict removes channels with nothing left to keep, or when none are empty,
+// the least recently active channel. Callers must hold c.mu.
+func (c *SnipeCache) evict(now time.Time) {
+	var oldestID string
+	var oldest time.Time
+	for id, ch := range c.channels {
+		ch.prune(now, c.retention)
+		if len(ch.recent) == 0 && ch.deleted == nil {
+			delete(c.channels, id)
+			continue
+		}
+		if oldestID == "" || ch.active.Before(oldest) {
+			oldestID, oldest = id, ch.active
+		}
+	}
+
+	if len(c.channels) >= SnipeMaxChannels && oldestID != "" {
+		delete(c.channels, oldestID)
+	}
+}
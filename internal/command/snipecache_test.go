@@ -0,0 +1,148 @@
+package command_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGuildMessage creates a guild message sent by a member.
+func testGuildMessage(channelID, messageID, content string) *discordgo.Message {
+	return &discordgo.Message{
+		ID:        messageID,
+		ChannelID: channelID,
+		GuildID:   "g1",
+		Content:   content,
+		Author:    &discordgo.User{ID: "author-" + messageID},
+	}
+}
+
+// ============================================================================
+// SnipeCache Tests
+// ============================================================================
+
+func Test_SnipeCache_AddDeleteLast(t *testing.T) {
+	cache := command.NewSnipeCache(time.Minute)
+
+	_, ok := cache.Last("c1")
+	assert.False(t, ok, "empty cache should have nothing to snipe")
+
+	cache.Add(testGuildMessage("c1", "m1", "first"))
+	cache.Add(testGuildMessage("c1", "m2", "second"))
+	_, ok = cache.Last("c1")
+	assert.False(t, ok, "messages still up are not sniped")
+
+	cache.Delete("c1", "m1")
+	got, ok := cache.Last("c1")
+	require.True(t, ok)
+	assert.Equal(t, "first", got.Content)
+	assert.Equal(t, "author-m1", got.AuthorID)
+	assert.False(t, got.DeletedAt.IsZero())
+
+	cache.Delete("c1", "m2")
+	got, ok = cache.Last("c1")
+	require.True(t, ok)
+	assert.Equal(t, "second", got.Content, "the most recent deletion wins")
+
+	_, ok = cache.Last("c2")
+	assert.False(t, ok, "deletions are kept per channel")
+
+	cache.Delete("c1", "unknown")
+	got, _ = cache.Last("c1")
+	assert.Equal(t, "second", got.Content, "deleting an unknown message changes nothing")
+}
+
+func Test_SnipeCache_Edit(t *testing.T) {
+	cache := command.NewSnipeCache(time.Minute)
+	cache.Add(testGuildMessage("c1", "m1", "original"))
+
+	// Edits may arrive without the author
+	cache.Add(&discordgo.Message{ID: "m1", ChannelID: "c1", GuildID: "g1", Content: "edited"})
+	cache.Delete("c1", "m1")
+
+	got, ok := cache.Last("c1")
+	require.True(t, ok)
+	assert.Equal(t, "edited", got.Content)
+	assert.Equal(t, "author-m1", got.AuthorID)
+}
+
+func Test_SnipeCache_Ignored(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *discordgo.Message
+	}{
+		{name: "nil message", msg: nil},
+		{name: "direct message", msg: &discordgo.Message{ID: "m1", ChannelID: "c1", Content: "hi", Author: &discordgo.User{ID: "u1"}}},
+		{name: "no content", msg: &discordgo.Message{ID: "m1", ChannelID: "c1", GuildID: "g1", Author: &discordgo.User{ID: "u1"}}},
+		{name: "bot author", msg: &discordgo.Message{ID: "m1", ChannelID: "c1", GuildID: "g1", Content: "hi", Author: &discordgo.User{ID: "u1", Bot: true}}},
+		{name: "no author", msg: &discordgo.Message{ID: "m1", ChannelID: "c1", GuildID: "g1", Content: "hi"}},
+		{name: "excluded channel", msg: testGuildMessage("private", "m1", "secret")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := command.NewSnipeCache(time.Minute, "private")
+
+			cache.Add(tt.msg)
+
+			assert.Zero(t, cache.Len())
+		})
+	}
+}
+
+func Test_SnipeCache_Expires(t *testing.T) {
+	cache := command.NewSnipeCache(20 * time.Millisecond)
+	cache.Add(testGuildMessage("c1", "m1", "first"))
+	cache.Add(testGuildMessage("c1", "m2", "second"))
+	cache.Delete("c1", "m1")
+
+	_, ok := cache.Last("c1")
+	require.True(t, ok)
+
+	assert.Eventually(t, func() bool {
+		_, ok := cache.Last("c1")
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+	assert.Zero(t, cache.Len(), "expired messages should be forgotten")
+
+	cache.Delete("c1", "m2")
+	_, ok = cache.Last("c1")
+	assert.False(t, ok, "a message deleted after it expired cannot be sniped")
+}
+
+func Test_SnipeCache_Bounded(t *testing.T) {
+	cache := command.NewSnipeCache(time.Minute)
+
+	for i := range command.SnipeChannelMessages + 10 {
+		cache.Add(testGuildMessage("c1", fmt.Sprint(i), "message"))
+	}
+	assert.Equal(t, command.SnipeChannelMessages, cache.Len())
+
+	cache.Delete("c1", "0")
+	_, ok := cache.Last("c1")
+	assert.False(t, ok, "the oldest messages should be dropped")
+
+	for i := range command.SnipeMaxChannels + 10 {
+		cache.Add(testGuildMessage(fmt.Sprint("channel-", i), "m", "message"))
+	}
+	assert.LessOrEqual(t, cache.Len(), command.SnipeMaxChannels)
+}
+
+func Test_SnipeCache_Disabled(t *testing.T) {
+	cache := command.NewSnipeCache(0)
+	require.Nil(t, cache)
+
+	assert.NotPanics(t, func() {
+		cache.Add(testGuildMessage("c1", "m1", "content"))
+		cache.Delete("c1", "m1")
+	})
+	_, ok := cache.Last("c1")
+	assert.False(t, ok)
+	assert.Zero(t, cache.Len())
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SyncDiff describes how the application commands registered with Discord
+// differ from the commands the bot wants registered. Each field lists command
+// names in sorted order.
+type SyncDiff struct {
+	Created   []string
+	Updated   []string
+	Deleted   []string
+	Unchanged []string
+}
+
+// Changed reports whether Discord's commands need to be overwritten.
+func (d SyncDiff) Changed() bool {
+	return len(d.Created)+len(d.Updated)+len(d.Deleted) > 0
+}
+
+// String summarizes the diff as counts, for logs and CLI output.
+func (d SyncDiff) String() string {
+	return fmt.Sprintf("%d created, %d updated, %d deleted, %d unchanged",
+		len(d.Created), len(d.Updated), len(d.Deleted), len(d.Unchanged))
+}
+
+// DiffApplicationCommands compares the commands registered with Discord
+// against the desired set by name. A command is updated when its description,
+// options, or default member permissions differ; fields Discord assigns, such
+// as IDs and versions, are ignored.
+func DiffApplicationCommands(existing, desired []*discordgo.ApplicationCommand) SyncDiff {
+	current := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, cmd := range existing {
+		current[cmd.Name] = cmd
+	}
+
+	var diff SyncDiff
+	for _, cmd := range desired {
+		old, ok := current[cmd.Name]
+		switch {
+		case !ok:
+			diff.Created = append(diff.Created, cmd.Name)
+		case commandDefinition(old) != commandDefinition(cmd):
+			diff.Updated = append(diff.Updated, cmd.Name)
+		default:
+			diff.Unchanged = append(diff.Unchanged, cmd.Name)
+		}
+		delete(current, cmd.Name)
+	}
+	for name := range current {
+		diff.Deleted = append(diff.Deleted, name)
+	}
+
+	sort.Strings(diff.Created)
+	sort.Strings(diff.Updated)
+	sort.Strings(diff.Deleted)
+	sort.Strings(diff.Unchanged)
+	return diff
+}
+
+// commandDefinition returns the user-visible parts of cmd in a comparable form.
+func commandDefinition(cmd *discordgo.ApplicationCommand) string {
+	def := struct {
+		Description string                                `json:"description"`
+		Options     []*discordgo.ApplicationCommandOption `json:"options,omitempty"`
+		Permissions *int64                                `json:"permissions,omitempty"`
+	}{cmd.Description, cmd.Options, cmd.DefaultMemberPermissions}
+
+	// Marshaling cannot fail for these field types.
+	b, _ := json.Marshal(def)
+	return string(b)
+}
+
+// SyncApplicationCommands makes the application commands registered with
+// Discord match desired, in the given guild or globally when guildID is
+// empty. Discord's commands are overwritten in a single request, and only
+// when the diff shows a change; commands not in desired are deleted.
+// The returned diff describes what was (or, on error, would have been) changed.
+func SyncApplicationCommands(s *discordgo.Session, appID, guildID string, desired []*discordgo.ApplicationCommand) (SyncDiff, error) {
+	existing, err := s.ApplicationCommands(appID, guildID)
+	if err != nil {
+		return SyncDiff{}, fmt.Errorf("failed to fetch registered commands: %w", err)
+	}
+
+	diff := DiffApplicationCommands(existing, desired)
+	if !diff.Changed() {
+		return diff, nil
+	}
+
+	if desired == nil {
+		desired = []*discordgo.ApplicationCommand{}
+	}
+	if _, err := s.ApplicationCommandBulkOverwrite(appID, guildID, desired); err != nil {
+		return diff, fmt.Errorf("failed to overwrite commands: %w", err)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,136 @@
+package command_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// DiffApplicationCommands Tests
+// =============================================================================
+
+func Test_DiffApplicationCommands(t *testing.T) {
+	perms := int64(discordgo.PermissionKickMembers)
+	option := &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionString, Name: "message", Description: "Text"}
+
+	tests := []struct {
+		name     string
+		existing []*discordgo.ApplicationCommand
+		desired  []*discordgo.ApplicationCommand
+		want     command.SyncDiff
+	}{
+		{
+			name:    "nothing registered yet",
+			desired: []*discordgo.ApplicationCommand{{Name: "ping", Description: "Ping"}, {Name: "echo", Description: "Echo"}},
+			want:    command.SyncDiff{Created: []string{"echo", "ping"}},
+		},
+		{
+			name:     "identical ignoring Discord-assigned fields",
+			existing: []*discordgo.ApplicationCommand{{ID: "1", Version: "9", Name: "ping", Description: "Ping"}},
+			desired:  []*discordgo.ApplicationCommand{{Name: "ping", Description: "Ping"}},
+			want:     command.SyncDiff{Unchanged: []string{"ping"}},
+		},
+		{
+			name: "description, options, and permissions changes are updates",
+			existing: []*discordgo.ApplicationCommand{
+				{Name: "ping", Description: "Ping"},
+				{Name: "echo", Description: "Echo"},
+				{Name: "kick", Description: "Kick"},
+			},
+			desired: []*discordgo.ApplicationCommand{
+				{Name: "ping", Description: "Check latency"},
+				{Name: "echo", Description: "Echo", Options: []*discordgo.ApplicationCommandOption{option}},
+				{Name: "kick", Description: "Kick", DefaultMemberPermissions: &perms},
+			},
+			want: command.SyncDiff{Updated: []string{"echo", "kick", "ping"}},
+		},
+		{
+			name:     "commands no longer desired are deleted",
+			existing: []*discordgo.ApplicationCommand{{Name: "ping", Description: "Ping"}, {Name: "old", Description: "Old"}},
+			desired:  []*discordgo.ApplicationCommand{{Name: "ping", Description: "Ping"}},
+			want:     command.SyncDiff{Deleted: []string{"old"}, Unchanged: []string{"ping"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := command.DiffApplicationCommands(tt.existing, tt.desired)
+
+			assert.Equal(t, tt.want, diff)
+			assert.Equal(t, len(tt.want.Created)+len(tt.want.Updated)+len(tt.want.Deleted) > 0, diff.Changed())
+		})
+	}
+}
+
+func Test_SyncDiff_String(t *testing.T) {
+	diff := command.SyncDiff{Created: []string{"a"}, Deleted: []string{"b", "c"}, Unchanged: []string{"d"}}
+
+	assert.Equal(t, "1 created, 0 updated, 2 deleted, 1 unchanged", diff.String())
+}
+
+// =============================================================================
+// SyncApplicationCommands Tests
+// =============================================================================
+
+func Test_SyncApplicationCommands(t *testing.T) {
+	const listPath = "/api/v9/applications/app-1/guilds/guild-1/commands"
+
+	tests := []struct {
+		name          string
+		existing      string
+		desired       []*discordgo.ApplicationCommand
+		wantOverwrite bool
+		wantDiff      command.SyncDiff
+	}{
+		{
+			name:          "changes are overwritten in one request",
+			existing:      `[{"id":"1","name":"old","description":"Old"}]`,
+			desired:       []*discordgo.ApplicationCommand{{Name: "ping", Description: "Ping"}},
+			wantOverwrite: true,
+			wantDiff:      command.SyncDiff{Created: []string{"ping"}, Deleted: []string{"old"}},
+		},
+		{
+			name:     "no changes skips the overwrite",
+			existing: `[{"id":"1","name":"ping","description":"Ping"}]`,
+			desired:  []*discordgo.ApplicationCommand{{Name: "ping", Description: "Ping"}},
+			wantDiff: command.SyncDiff{Unchanged: []string{"ping"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			rt.responses = map[string]string{
+				http.MethodGet + " " + listPath: tt.existing,
+				http.MethodPut + " " + listPath: "[]",
+			}
+
+			diff, err := command.SyncApplicationCommands(s, "app-1", "guild-1", tt.desired)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDiff, diff)
+
+			requests := rt.recorded()
+			if !tt.wantOverwrite {
+				require.Len(t, requests, 1)
+				assert.Equal(t, http.MethodGet, requests[0].Method)
+				return
+			}
+			require.Len(t, requests, 2)
+			assert.Equal(t, http.MethodPut, requests[1].Method)
+			assert.Equal(t, listPath, requests[1].Path)
+
+			var sent []map[string]any
+			require.NoError(t, json.Unmarshal(requests[1].Body, &sent))
+			require.Len(t, sent, 1)
+			assert.Equal(t, "ping", sent[0]["name"])
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"jamesbot/internal/i18n"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ParseTextCommand splits the content of a prefixed text command, such as
+// "!ban @user spam", into the command name and the rest of the line. It
+// reports false if content does not start with prefix followed by a name.
+// Names are matched case-insensitively, so the name is returned lowercased.
+func ParseTextCommand(content, prefix string) (name, args string, ok bool) {
+	if prefix == "" {
+		return "", "", false
+	}
+	rest, found := strings.CutPrefix(content, prefix)
+	if !found {
+		return "", "", false
+	}
+
+	end := strings.IndexFunc(rest, unicode.IsSpace)
+	if end < 0 {
+		end = len(rest)
+	}
+	if end == 0 {
+		return "", "", false
+	}
+	name, args = rest[:end], rest[end:]
+	return strings.ToLower(name), strings.TrimSpace(args), true
+}
+
+// TextUsage returns how to invoke cmd as a text command with prefix, such as
+// "!ban <user> [reason] [delete_days]". Required options are in angle
+// brackets and optional ones in square brackets.
+func TextUsage(prefix string, cmd Command) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteString(cmd.Name())
+	for _, opt := range cmd.Options() {
+		if opt.Required {
+			fmt.Fprintf(&sb, " <%s>", opt.Name)
+		} else {
+			fmt.Fprintf(&sb, " [%s]", opt.Name)
+		}
+	}
+	return sb.String()
+}
+
+// NewTextInteraction builds an application command interaction for cmd from
+// a text command in m, so commands written for slash commands run unchanged
+// on a Context created with WithMessage. args, the text after the command
+// name, fills cmd's options in order: each option takes one word, or several
+// in double quotes, except the last string option, which takes whatever the
+// options around it leave over. Mentioned users are added to the resolved data.
+//
+// Returns an errutil.UserFriendlyError, in the catalog's fallback locale, if
+// args do not fit cmd's options.
+func NewTextInteraction(m *discordgo.Message, cmd Command, args string) (*discordgo.InteractionCreate, error) {
+	if m == nil || m.Author == nil {
+		return nil, fmt.Errorf("message cannot be nil")
+	}
+
+	options, err := parseTextOptions(cmd.Options(), splitTextArgs(args))
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &discordgo.ApplicationCommandInteractionDataResolved{}
+	for _, user := range m.Mentions {
+		if user == nil {
+			continue
+		}
+		if resolved.Users == nil {
+			resolved.Users = make(map[string]*discordgo.User, len(m.Mentions))
+		}
+		resolved.Users[user.ID] = user
+	}
+
+	interaction := &discordgo.Interaction{
+		Type:      discordgo.InteractionApplicationCommand,
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:     cmd.Name(),
+			Options:  options,
+			Resolved: resolved,
+		},
+	}
+	if m.GuildID != "" && m.Member != nil {
+		member := *m.Member
+		member.User = m.Author
+		member.GuildID = m.GuildID
+		interaction.Member = &member
+	} else {
+		interaction.User = m.Author
+	}
+
+	return &discordgo.InteractionCreate{Interaction: interaction}, nil
+}
+
+// splitTextArgs splits args into words, keeping words in double quotes together.
+func splitTextArgs(args string) []string {
+	var (
+		words   []string
+		current strings.Builder
+		quoted  bool
+		inWord  bool
+	)
+	for _, r := range args {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case unicode.IsSpace(r) && !quoted:
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words
+}
+
+// parseTextOptions assigns words to opts. Options before the last string
+// option are filled from the front and options after it from the back, each
+// taking one word while the word fits its type; the string option gets the
+// words in between.
+func parseTextOptions(opts []*discordgo.ApplicationCommandOption, words []string) ([]*discordgo.ApplicationCommandInteractionDataOption, error) {
+	greedy := -1
+	for i, opt := range opts {
+		if opt.Type == discordgo.ApplicationCommandOptionString {
+			greedy = i
+		}
+	}
+
+	values := make([]*discordgo.ApplicationCommandInteractionDataOption, len(opts))
+	head := opts
+	if greedy >= 0 {
+		head = opts[:greedy]
+	}
+
+	for i, opt := range head {
+		if len(words) == 0 {
+			break
+		}
+		value, err := textOptionValue(opt, words[0])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		words = words[1:]
+	}
+
+	if greedy >= 0 {
+		for i := len(opts) - 1; i > greedy && len(words) > 0; i-- {
+			value, err := textOptionValue(opts[i], words[len(words)-1])
+			if err != nil {
+				break
+			}
+			values[i] = value
+			words = words[:len(words)-1]
+		}
+		if len(words) > 0 {
+			values[greedy] = &discordgo.ApplicationCommandInteractionDataOption{
+				Name:  opts[greedy].Name,
+				Type:  discordgo.ApplicationCommandOptionString,
+				Value: strings.Join(words, " "),
+			}
+			words = nil
+		}
+	}
+
+	if len(words) > 0 {
+		return nil, textArgError(i18n.MsgTextTooManyArgs)
+	}
+
+	options := make([]*discordgo.ApplicationCommandInteractionDataOption, 0, len(opts))
+	for i, opt := range opts {
+		if values[i] == nil {
+			if opt.Required {
+				return nil, textArgError(i18n.MsgTextMissingArg, opt.Name)
+			}
+			continue
+		}
+		options = append(options, values[i])
+	}
+	return options, nil
+}
+
+// textOptionValue converts word to the value of opt, accepting mentions or
+// bare IDs for users, roles, and channels.
+func textOptionValue(opt *discordgo.ApplicationCommandOption, word string) (*discordgo.ApplicationCommandInteractionDataOption, error) {
+	value := &discordgo.ApplicationCommandInteractionDataOption{Name: opt.Name, Type: opt.Type}
+
+	switch opt.Type {
+	case discordgo.ApplicationCommandOptionString:
+		value.Value = word
+	case discordgo.ApplicationCommandOptionInteger:
+		n, err := strconv.ParseInt(word, 10, 64)
+		if err != nil {
+			return nil, textArgError(i18n.MsgTextInvalidArg, word, opt.Name)
+		}
+		// Discord sends numbers as JSON numbers, which decode to float64
+		value.Value = float64(n)
+	case discordgo.ApplicationCommandOptionNumber:
+		f, err := strconv.ParseFloat(word, 64)
+		if err != nil {
+			return nil, textArgError(i18n.MsgTextInvalidArg, word, opt.Name)
+		}
+		value.Value = f
+	case discordgo.ApplicationCommandOptionBoolean:
+		b, err := strconv.ParseBool(word)
+		if err != nil {
+			return nil, textArgError(i18n.MsgTextInvalidArg, word, opt.Name)
+		}
+		value.Value = b
+	case discordgo.ApplicationCommandOptionUser:
+		id, ok := mentionID(word, "<@", "<@!")
+		if !ok {
+			return nil, textArgError(i18n.MsgTextInvalidArg, word, opt.Name)
+		}
+		value.Value = id
+	case discordgo.ApplicationCommandOptionRole:
+		id, ok := mentionID(word, "<@&")
+		if !ok {
+			return nil, textArgError(i18n.MsgTextInvalidArg, word, opt.Name)
+		}
+		value.Value = id
+	case discordgo.ApplicationCommandOptionChannel:
+		id, ok := mentionID(word, "<#")
+		if !ok {
+			return nil, textArgError(i18n.MsgTextInvalidArg, word, opt.Name)
+		}
+		value.Value = id
+	default:
+		return nil, textArgError(i18n.MsgTextUnsupported, opt.Name)
+	}
+	return value, nil
+}
+
+// mentionID returns the ID in a mention with one of prefixes, such as
+// "<@123>", or word itself if it is a bare ID.
+func mentionID(word string, prefixes ...string) (string, bool) {
+	id := word
+	// Longest prefixes first, so "<@!" is not read as "<@" followed by "!"
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		if inner, ok := strings.CutPrefix(word, prefixes[i]); ok {
+			if inner, ok = strings.CutSuffix(inner, ">"); ok {
+				id = inner
+				break
+			}
+		}
+	}
+	if id == "" || strings.ContainsFunc(id, func(r rune) bool { return r < '0' || r > '9' }) {
+		return "", false
+	}
+	return id, true
+}
+
+// textArgError returns a UserFriendlyError with the message id in the
+// default locale. Text commands carry no user locale.
+func textArgError(id string, args ...any) error {
+	msg := i18n.Default().T(i18n.DefaultLocale, id, args...)
+	return errutil.UserFriendlyError{
+		UserMessage: msg,
+		Err:         errors.New(msg),
+	}
+}
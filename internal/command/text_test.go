@@ -0,0 +1,285 @@
+package command_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// ParseTextCommand Tests
+// =============================================================================
+
+func Test_ParseTextCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		prefix   string
+		wantName string
+		wantArgs string
+		wantOK   bool
+	}{
+		{name: "name only", content: "!ping", prefix: "!", wantName: "ping", wantOK: true},
+		{name: "name and args", content: "!ban <@123> spam", prefix: "!", wantName: "ban", wantArgs: "<@123> spam", wantOK: true},
+		{name: "name lowercased", content: "!KICK <@123>", prefix: "!", wantName: "kick", wantArgs: "<@123>", wantOK: true},
+		{name: "surrounding whitespace trimmed", content: "!echo   hello  ", prefix: "!", wantName: "echo", wantArgs: "hello", wantOK: true},
+		{name: "multi-character prefix", content: "jb.ping", prefix: "jb.", wantName: "ping", wantOK: true},
+		{name: "no prefix", content: "ping", prefix: "!", wantOK: false},
+		{name: "prefix only", content: "!", prefix: "!", wantOK: false},
+		{name: "space after prefix", content: "! ping", prefix: "!", wantOK: false},
+		{name: "empty prefix disables", content: "ping", prefix: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, ok := command.ParseTextCommand(tt.content, tt.prefix)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+// =============================================================================
+// TextUsage Tests
+// =============================================================================
+
+func Test_TextUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  command.Command
+		want string
+	}{
+		{name: "no options", cmd: &command.PingCommand{}, want: "!ping"},
+		{name: "required and optional", cmd: &command.BanCommand{}, want: "!ban <user> [reason] [delete_days]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, command.TextUsage("!", tt.cmd))
+		})
+	}
+}
+
+// =============================================================================
+// NewTextInteraction Tests
+// =============================================================================
+
+// textMessage creates a guild message from user 111 that mentions user 222.
+func textMessage() *discordgo.Message {
+	return &discordgo.Message{
+		ID:        "message-1",
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Author:    &discordgo.User{ID: "111", Username: "moderator"},
+		Member:    &discordgo.Member{Nick: "mod"},
+		Mentions:  []*discordgo.User{{ID: "222", Username: "target"}},
+	}
+}
+
+func Test_NewTextInteraction_Options(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  command.Command
+		args string
+		want map[string]any
+	}{
+		{
+			name: "user mention",
+			cmd:  &command.KickCommand{},
+			args: "<@222>",
+			want: map[string]any{"user": "222"},
+		},
+		{
+			name: "nickname mention",
+			cmd:  &command.KickCommand{},
+			args: "<@!222> rude",
+			want: map[string]any{"user": "222", "reason": "rude"},
+		},
+		{
+			name: "bare ID and last string option takes the rest",
+			cmd:  &command.KickCommand{},
+			args: "222 being very rude",
+			want: map[string]any{"user": "222", "reason": "being very rude"},
+		},
+		{
+			name: "trailing integer after reason",
+			cmd:  &command.BanCommand{},
+			args: "<@222> spamming links 7",
+			want: map[string]any{"user": "222", "reason": "spamming links", "delete_days": float64(7)},
+		},
+		{
+			name: "trailing word that is not an integer stays in reason",
+			cmd:  &command.BanCommand{},
+			args: "<@222> spamming links",
+			want: map[string]any{"user": "222", "reason": "spamming links"},
+		},
+		{
+			name: "integer alone",
+			cmd:  &command.BanCommand{},
+			args: "<@222> 3",
+			want: map[string]any{"user": "222", "delete_days": float64(3)},
+		},
+		{
+			name: "quoted words fill one option",
+			cmd:  &command.MuteCommand{},
+			args: `<@222> "1h" spamming in general`,
+			want: map[string]any{"user": "222", "duration": "1h", "reason": "spamming in general"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := command.NewTextInteraction(textMessage(), tt.cmd, tt.args)
+			require.NoError(t, err)
+
+			data := event.ApplicationCommandData()
+			assert.Equal(t, tt.cmd.Name(), data.Name)
+			got := make(map[string]any, len(data.Options))
+			for _, opt := range data.Options {
+				got[opt.Name] = opt.Value
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewTextInteraction_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         command.Command
+		args        string
+		wantMessage string
+	}{
+		{
+			name:        "missing required option",
+			cmd:         &command.KickCommand{},
+			args:        "",
+			wantMessage: "Missing user.",
+		},
+		{
+			name:        "invalid user",
+			cmd:         &command.KickCommand{},
+			args:        "someone rude",
+			wantMessage: `"someone" is not a valid user.`,
+		},
+		{
+			name:        "too many arguments",
+			cmd:         &command.PingCommand{},
+			args:        "extra",
+			wantMessage: "Too many arguments; put words with spaces in double quotes.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := command.NewTextInteraction(textMessage(), tt.cmd, tt.args)
+
+			var userErr errutil.UserFriendlyError
+			require.ErrorAs(t, err, &userErr)
+			assert.Equal(t, tt.wantMessage, userErr.UserMessage)
+		})
+	}
+}
+
+func Test_NewTextInteraction_Invoker(t *testing.T) {
+	t.Run("guild message uses the member", func(t *testing.T) {
+		m := textMessage()
+		event, err := command.NewTextInteraction(m, &command.PingCommand{}, "")
+		require.NoError(t, err)
+
+		require.NotNil(t, event.Member)
+		assert.Equal(t, "111", event.Member.User.ID)
+		assert.Equal(t, "mod", event.Member.Nick)
+		assert.Nil(t, m.Member.User, "the message's member must not be modified")
+		assert.Equal(t, "guild-1", event.GuildID)
+		assert.Equal(t, "channel-1", event.ChannelID)
+		assert.Equal(t, discordgo.InteractionApplicationCommand, event.Type)
+	})
+
+	t.Run("direct message uses the user", func(t *testing.T) {
+		m := textMessage()
+		m.GuildID, m.Member = "", nil
+		event, err := command.NewTextInteraction(m, &command.PingCommand{}, "")
+		require.NoError(t, err)
+
+		assert.Nil(t, event.Member)
+		require.NotNil(t, event.User)
+		assert.Equal(t, "111", event.User.ID)
+	})
+
+	t.Run("mentions are resolved", func(t *testing.T) {
+		event, err := command.NewTextInteraction(textMessage(), &command.KickCommand{}, "<@222>")
+		require.NoError(t, err)
+
+		ctx := command.NewContext(nil, event, zerolog.Nop())
+		user := ctx.UserOption("user")
+		require.NotNil(t, user)
+		assert.Equal(t, "target", user.Username)
+	})
+
+	t.Run("nil message", func(t *testing.T) {
+		_, err := command.NewTextInteraction(nil, &command.PingCommand{}, "")
+		assert.Error(t, err)
+	})
+}
+
+// =============================================================================
+// Text Command Response Tests
+// =============================================================================
+
+func Test_Context_TextCommandResponses(t *testing.T) {
+	session, rt := newRecordingSession(t)
+	rt.responses = map[string]string{
+		http.MethodPost + " /api/v9/channels/channel-1/messages":          `{"id":"reply-1","channel_id":"channel-1"}`,
+		http.MethodPatch + " /api/v9/channels/channel-1/messages/reply-1": `{"id":"reply-1","channel_id":"channel-1"}`,
+	}
+	m := textMessage()
+	event, err := command.NewTextInteraction(m, &command.PingCommand{}, "")
+	require.NoError(t, err)
+	ctx := command.NewContext(session, event, zerolog.Nop(), command.WithMessage(m))
+
+	require.NoError(t, ctx.RespondEphemeral("<@222> pong"))
+	require.NoError(t, ctx.EditResponse("edited"))
+
+	requests := rt.recorded()
+	require.Len(t, requests, 2)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "/api/v9/channels/channel-1/messages", requests[0].Path)
+	assert.Equal(t, http.MethodPatch, requests[1].Method)
+	assert.Equal(t, "/api/v9/channels/channel-1/messages/reply-1", requests[1].Path)
+
+	var sent struct {
+		Content          string `json:"content"`
+		MessageReference struct {
+			MessageID string `json:"message_id"`
+		} `json:"message_reference"`
+		AllowedMentions struct {
+			Parse []string `json:"parse"`
+		} `json:"allowed_mentions"`
+	}
+	require.NoError(t, json.Unmarshal(requests[0].Body, &sent))
+	assert.Equal(t, "<@222> pong", sent.Content)
+	assert.Equal(t, "message-1", sent.MessageReference.MessageID)
+	assert.Empty(t, sent.AllowedMentions.Parse, "replies must not ping anyone")
+	assert.Contains(t, string(requests[1].Body), `"content":"edited"`)
+}
+
+func Test_Context_EditResponse_TextCommandWithoutReply(t *testing.T) {
+	session, _ := newRecordingSession(t)
+	m := textMessage()
+	event, err := command.NewTextInteraction(m, &command.PingCommand{}, "")
+	require.NoError(t, err)
+	ctx := command.NewContext(session, event, zerolog.Nop(), command.WithMessage(m))
+
+	assert.Error(t, ctx.EditResponse("edited"))
+}
@@ -0,0 +1,189 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord's limits on slash command definitions. Definitions that break them
+// are rejected when commands are synced, with an error that does not say which
+// command or option is at fault.
+const (
+	maxNameLength        = 32
+	maxDescriptionLength = 100
+	maxOptions           = 25
+	maxChoices           = 25
+	maxChoiceNameLength  = 100
+	maxChoiceValueLength = 100
+
+	// maxCommandLength bounds the combined length of a command's name,
+	// description, and those of its options and choices.
+	maxCommandLength = 4000
+)
+
+// namePattern matches the names Discord accepts for commands and options.
+var namePattern = regexp.MustCompile(`^[-_\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+// validateDefinition checks cmd's name, description, and options against
+// Discord's limits, descending into subcommands. It returns an error wrapping
+// ErrInvalidCommand that names the first offending option.
+func validateDefinition(cmd Command) error {
+	if err := validateName(cmd.Name()); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCommand, err)
+	}
+	if err := validateDescription(cmd.Description()); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCommand, err)
+	}
+
+	options := cmd.Options()
+	if err := validateOptions(options, true); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCommand, err)
+	}
+
+	if total := utf8.RuneCountInString(cmd.Name()) + utf8.RuneCountInString(cmd.Description()) + optionsLength(options); total > maxCommandLength {
+		return fmt.Errorf("%w: names, descriptions, and choices total %d characters, at most %d allowed",
+			ErrInvalidCommand, total, maxCommandLength)
+	}
+	return nil
+}
+
+// validateName checks a command or option name.
+func validateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("name %q must be 1-%d letters, numbers, hyphens, or underscores", name, maxNameLength)
+	}
+	if strings.ToLower(name) != name {
+		return fmt.Errorf("name %q must be lowercase", name)
+	}
+	return nil
+}
+
+// validateDescription checks a command or option description.
+func validateDescription(description string) error {
+	n := utf8.RuneCountInString(description)
+	if n == 0 {
+		return fmt.Errorf("description is required")
+	}
+	if n > maxDescriptionLength {
+		return fmt.Errorf("description is %d characters, at most %d allowed", n, maxDescriptionLength)
+	}
+	return nil
+}
+
+// validateOptions checks one level of options. Subcommands and groups are
+// only allowed at the top level, and groups only hold subcommands.
+func validateOptions(options []*discordgo.ApplicationCommandOption, topLevel bool) error {
+	if len(options) > maxOptions {
+		return fmt.Errorf("%d options given, at most %d allowed", len(options), maxOptions)
+	}
+
+	seen := make(map[string]bool, len(options))
+	subcommands, optional := 0, false
+	for _, opt := range options {
+		if opt == nil {
+			return fmt.Errorf("option cannot be nil")
+		}
+		if err := validateOption(opt, topLevel); err != nil {
+			return fmt.Errorf("option %q: %w", opt.Name, err)
+		}
+		if seen[opt.Name] {
+			return fmt.Errorf("option %q is defined more than once", opt.Name)
+		}
+		seen[opt.Name] = true
+
+		switch {
+		case isSubcommand(opt):
+			subcommands++
+		case !opt.Required:
+			optional = true
+		case optional:
+			return fmt.Errorf("required option %q must come before optional options", opt.Name)
+		}
+	}
+
+	if subcommands > 0 && subcommands < len(options) {
+		return fmt.Errorf("subcommands cannot be mixed with other options")
+	}
+	return nil
+}
+
+// validateOption checks a single option and, for subcommands and groups, the
+// options they hold.
+func validateOption(opt *discordgo.ApplicationCommandOption, topLevel bool) error {
+	if err := validateName(opt.Name); err != nil {
+		return err
+	}
+	if err := validateDescription(opt.Description); err != nil {
+		return err
+	}
+
+	switch opt.Type {
+	case discordgo.ApplicationCommandOptionSubCommandGroup:
+		if !topLevel {
+			return fmt.Errorf("subcommand groups can only be used at the top level")
+		}
+		for _, sub := range opt.Options {
+			if sub != nil && sub.Type != discordgo.ApplicationCommandOptionSubCommand {
+				return fmt.Errorf("subcommand groups can only hold subcommands")
+			}
+		}
+		return validateOptions(opt.Options, false)
+	case discordgo.ApplicationCommandOptionSubCommand:
+		for _, sub := range opt.Options {
+			if sub != nil && isSubcommand(sub) {
+				return fmt.Errorf("subcommands cannot hold subcommands or groups")
+			}
+		}
+		return validateOptions(opt.Options, false)
+	}
+
+	if len(opt.Options) > 0 {
+		return fmt.Errorf("only subcommands and groups can hold options")
+	}
+	return validateChoices(opt.Choices)
+}
+
+// validateChoices checks an option's choices.
+func validateChoices(choices []*discordgo.ApplicationCommandOptionChoice) error {
+	if len(choices) > maxChoices {
+		return fmt.Errorf("%d choices given, at most %d allowed", len(choices), maxChoices)
+	}
+	for _, choice := range choices {
+		if choice == nil {
+			return fmt.Errorf("choice cannot be nil")
+		}
+		if n := utf8.RuneCountInString(choice.Name); n == 0 || n > maxChoiceNameLength {
+			return fmt.Errorf("choice %q: name must be 1-%d characters", choice.Name, maxChoiceNameLength)
+		}
+		if value, ok := choice.Value.(string); ok && utf8.RuneCountInString(value) > maxChoiceValueLength {
+			return fmt.Errorf("choice %q: value must be at most %d characters", choice.Name, maxChoiceValueLength)
+		}
+	}
+	return nil
+}
+
+// isSubcommand reports whether opt is a subcommand or subcommand group.
+func isSubcommand(opt *discordgo.ApplicationCommandOption) bool {
+	return opt.Type == discordgo.ApplicationCommandOptionSubCommand ||
+		opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup
+}
+
+// optionsLength returns the combined length of the names and descriptions of
+// options, their choices, and any options they hold.
+func optionsLength(options []*discordgo.ApplicationCommandOption) int {
+	total := 0
+	for _, opt := range options {
+		total += utf8.RuneCountInString(opt.Name) + utf8.RuneCountInString(opt.Description) + optionsLength(opt.Options)
+		for _, choice := range opt.Choices {
+			total += utf8.RuneCountInString(choice.Name)
+			if value, ok := choice.Value.(string); ok {
+				total += utf8.RuneCountInString(value)
+			}
+		}
+	}
+	return total
+}
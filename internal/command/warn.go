@@ -5,13 +5,27 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 
+	"jamesbot/internal/i18n"
+	"jamesbot/internal/rules"
+	"jamesbot/internal/warnings"
 	"jamesbot/pkg/errutil"
 )
 
 // WarnCommand implements a command to warn members.
 // It sends a direct message to the user with the warning.
 // It requires the Moderate Members permission to execute.
-type WarnCommand struct{}
+//
+// When Warnings is set, each warning is recorded, and if the warn-escalation
+// rule is enabled in Rules, reaching a configured warning count automatically
+// mutes, kicks, or bans the member.
+type WarnCommand struct {
+	// Warnings records issued warnings. When nil, warnings are not tracked
+	// and escalation never triggers.
+	Warnings *warnings.Store
+
+	// Rules supplies the warn-escalation rule. When nil, escalation is disabled.
+	Rules *rules.Set
+}
 
 // Name returns the command name.
 func (c *WarnCommand) Name() string {
@@ -29,6 +43,12 @@ func (c *WarnCommand) Permissions() int64 {
 	return discordgo.PermissionModerateMembers
 }
 
+// GuildOnly reports that the command only works in a server, which has
+// members to act on.
+func (c *WarnCommand) GuildOnly() bool {
+	return true
+}
+
 // Options returns the command options.
 // The warn command accepts a user and a required reason.
 func (c *WarnCommand) Options() []*discordgo.ApplicationCommandOption {
@@ -91,7 +111,7 @@ func (c *WarnCommand) Execute(ctx *Context) error {
 
 	// Get guild ID for context
 	guildID := ctx.GuildID()
-	if guildID == "" {
+	if ctx.IsDM() {
 		return errutil.UserFriendlyError{
 			UserMessage: "This command can only be used in a server.",
 			Err:         fmt.Errorf("warn command used outside of guild"),
@@ -100,23 +120,23 @@ func (c *WarnCommand) Execute(ctx *Context) error {
 
 	// Check session before making Discord API calls
 	if ctx.Session == nil {
-		return fmt.Errorf("session cannot be nil")
+		return ErrNoSession
 	}
 
-	// Get guild name for the warning message
-	guild, err := ctx.Session.Guild(guildID)
-	var guildName string
-	if err == nil && guild != nil {
-		guildName = guild.Name
-	} else {
-		guildName = "this server"
-	}
+	// Record the warning before notifying so the count is accurate
+	count := c.Warnings.Add(warnings.Warning{
+		GuildID:     guildID,
+		UserID:      targetUser.ID,
+		ModeratorID: ctx.UserID(),
+		Reason:      reason,
+	})
+	modLog(ctx, guildID, ModLogEntry{Action: ModLogWarned, TargetID: targetUser.ID, Reason: reason})
 
 	// Attempt to send a DM to the user
 	dmChannel, err := ctx.Session.UserChannelCreate(targetUser.ID)
 	dmSent := false
 	if err == nil && dmChannel != nil {
-		warningMsg := fmt.Sprintf("You have been warned in %s.\nReason: %s", guildName, reason)
+		warningMsg := fmt.Sprintf("You have been warned in %s.\nReason: %s", guildName(ctx, guildID), reason)
 		_, err = ctx.Session.ChannelMessageSend(dmChannel.ID, warningMsg)
 		if err == nil {
 			dmSent = true
@@ -133,5 +153,45 @@ func (c *WarnCommand) Execute(ctx *Context) error {
 			targetUser.Username, targetUser.Discriminator, reason)
 	}
 
+	responseMsg += c.escalate(ctx, guildID, targetUser, count)
+
 	return ctx.RespondEphemeral(responseMsg)
 }
+
+// escalate applies the warn-escalation step triggered by the member reaching
+// count warnings, if any, and returns a note to append to the response.
+// Failures are logged and reported in the note rather than failing the warning,
+// which has already been recorded and delivered.
+func (c *WarnCommand) escalate(ctx *Context, guildID string, target *discordgo.User, count int) string {
+	if c.Warnings == nil {
+		return ""
+	}
+
+	escalation, ok, err := Escalate(ctx.Session, c.Rules, guildID, target.ID, count, ctx.Logger)
+	if !ok {
+		return ""
+	}
+
+	var outcome, forbiddenMsg string
+	switch escalation.Action {
+	case warnings.ActionMute:
+		outcome = "timed out for " + formatDuration(escalation.MuteFor)
+		forbiddenMsg = i18n.MsgMuteForbidden
+	case warnings.ActionKick:
+		outcome = "kicked"
+		forbiddenMsg = i18n.MsgKickForbidden
+	case warnings.ActionBan:
+		outcome = "banned"
+		forbiddenMsg = i18n.MsgBanForbidden
+	}
+
+	if err != nil {
+		if msg, forbidden := forbiddenMessage(ctx, err, forbiddenMsg, target.Username); forbidden {
+			return fmt.Sprintf("\nThis is warning #%d; automatic %s failed. %s", count, escalation.Action, msg)
+		}
+		return fmt.Sprintf("\nThis is warning #%d; automatic %s failed. I may lack permissions or the user may have a higher role.",
+			count, escalation.Action)
+	}
+
+	return fmt.Sprintf("\nThis is warning #%d; they have been automatically %s.", count, outcome)
+}
@@ -2,10 +2,13 @@ package command_test
 
 import (
 	"io"
+	"net/http"
 	"strings"
 	"testing"
 
 	"jamesbot/internal/command"
+	"jamesbot/internal/rules"
+	"jamesbot/internal/warnings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/zerolog"
@@ -515,6 +518,124 @@ func Test_WarnCommand_MultipleWarns(t *testing.T) {
 }
 
 // Benchmark tests
+func Test_WarnCommand_Execute_RecordsWarnings(t *testing.T) {
+	store := warnings.NewStore()
+	cmd := &command.WarnCommand{Warnings: store}
+
+	for i := 0; i < 2; i++ {
+		session, _ := newRecordingSession(t)
+		interaction := createWarnInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "spam", false)
+		require.NoError(t, cmd.Execute(command.NewContext(session, interaction, warnTestLogger())))
+	}
+
+	list := store.List("guild-789", "target-456")
+	require.Len(t, list, 2)
+	assert.Equal(t, "moderator-123", list[0].ModeratorID)
+	assert.Equal(t, "spam", list[0].Reason)
+	assert.False(t, list[0].CreatedAt.IsZero())
+}
+
+func Test_WarnCommand_Execute_Escalation(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		policy       string
+		prior        int
+		wantMethod   string
+		wantPath     string
+		wantResponse string
+	}{
+		{
+			name:         "mute at threshold",
+			enabled:      true,
+			policy:       "2=mute,3=ban",
+			prior:        1,
+			wantMethod:   http.MethodPatch,
+			wantPath:     "/guilds/guild-789/members/target-456",
+			wantResponse: "automatically timed out for 1h",
+		},
+		{
+			name:         "kick at threshold",
+			enabled:      true,
+			policy:       "1=kick",
+			prior:        0,
+			wantMethod:   http.MethodDelete,
+			wantPath:     "/guilds/guild-789/members/target-456",
+			wantResponse: "automatically kicked",
+		},
+		{
+			name:         "ban at threshold",
+			enabled:      true,
+			policy:       "2=mute,3=ban",
+			prior:        2,
+			wantMethod:   http.MethodPut,
+			wantPath:     "/guilds/guild-789/bans/target-456",
+			wantResponse: "automatically banned",
+		},
+		{
+			name:    "below threshold does nothing",
+			enabled: true,
+			policy:  "3=ban",
+			prior:   0,
+		},
+		{
+			name:    "past threshold does not repeat",
+			enabled: true,
+			policy:  "1=kick",
+			prior:   1,
+		},
+		{
+			name:    "disabled rule does nothing",
+			enabled: false,
+			policy:  "1=ban",
+			prior:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rules.NewSet(rules.Defaults()...)
+			require.NoError(t, set.SetRule(rules.RuleWarnEscalation, rules.KeyEscalationPolicy, tt.policy))
+			if tt.enabled {
+				require.NoError(t, set.SetRule(rules.RuleWarnEscalation, rules.KeyEnabled, "true"))
+			}
+
+			store := warnings.NewStore()
+			for i := 0; i < tt.prior; i++ {
+				store.Add(warnings.Warning{GuildID: "guild-789", UserID: "target-456"})
+			}
+
+			session, rt := newRecordingSession(t)
+			interaction := createWarnInteractionWithResolvedUser("moderator-123", "target-456", "guild-789", "channel-012", "spam", false)
+			cmd := &command.WarnCommand{Warnings: store, Rules: set}
+
+			require.NoError(t, cmd.Execute(command.NewContext(session, interaction, warnTestLogger())))
+
+			var actions []recordedRequest
+			var response string
+			for _, req := range rt.recorded() {
+				switch {
+				case strings.HasSuffix(req.Path, "/callback"):
+					response = string(req.Body)
+				case strings.Contains(req.Path, "/guilds/guild-789/members/") || strings.Contains(req.Path, "/guilds/guild-789/bans/"):
+					actions = append(actions, req)
+				}
+			}
+
+			if tt.wantMethod == "" {
+				assert.Empty(t, actions, "no escalation expected")
+				assert.NotContains(t, response, "automatically")
+				return
+			}
+
+			require.Len(t, actions, 1)
+			assert.Equal(t, tt.wantMethod, actions[0].Method)
+			assert.True(t, strings.HasSuffix(actions[0].Path, tt.wantPath), "path %s", actions[0].Path)
+			assert.Contains(t, response, tt.wantResponse)
+		})
+	}
+}
+
 func Benchmark_WarnCommand_Name(b *testing.B) {
 	cmd := &command.WarnCommand{}
 
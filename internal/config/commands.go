@@ -0,0 +1,50 @@
+package config
+
+import "sort"
+
+// IsEnabled reports whether the named command should be registered.
+// With no Enabled list every command is enabled unless it appears in Disabled.
+func (c CommandsConfig) IsEnabled(name string) bool {
+	for _, disabled := range c.Disabled {
+		if disabled == name {
+			return false
+		}
+	}
+
+	if len(c.Enabled) == 0 {
+		return true
+	}
+
+	for _, enabled := range c.Enabled {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// UnknownNames returns the configured command names, from Enabled, Disabled,
+// and MaintenanceExempt, that do not appear in known. It is used to warn about typos.
+// The result is sorted and free of duplicates.
+func (c CommandsConfig) UnknownNames(known []string) []string {
+	knownSet := make(map[string]struct{}, len(known))
+	for _, name := range known {
+		knownSet[name] = struct{}{}
+	}
+
+	unknownSet := make(map[string]struct{})
+	for _, list := range [][]string{c.Enabled, c.Disabled, c.MaintenanceExempt} {
+		for _, name := range list {
+			if _, ok := knownSet[name]; !ok {
+				unknownSet[name] = struct{}{}
+			}
+		}
+	}
+
+	unknown := make([]string, 0, len(unknownSet))
+	for name := range unknownSet {
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"testing"
+
+	"jamesbot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CommandsConfig_IsEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.CommandsConfig
+		command string
+		want    bool
+	}{
+		{name: "empty config enables everything", cfg: config.CommandsConfig{}, command: "ban", want: true},
+		{name: "disabled command", cfg: config.CommandsConfig{Disabled: []string{"ban"}}, command: "ban", want: false},
+		{name: "other command unaffected by disabled list", cfg: config.CommandsConfig{Disabled: []string{"ban"}}, command: "kick", want: true},
+		{name: "enabled list includes command", cfg: config.CommandsConfig{Enabled: []string{"ping", "echo"}}, command: "echo", want: true},
+		{name: "enabled list excludes command", cfg: config.CommandsConfig{Enabled: []string{"ping", "echo"}}, command: "ban", want: false},
+		{name: "disabled wins over enabled", cfg: config.CommandsConfig{Enabled: []string{"ban"}, Disabled: []string{"ban"}}, command: "ban", want: false},
+		{name: "names are case sensitive", cfg: config.CommandsConfig{Disabled: []string{"Ban"}}, command: "ban", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cfg.IsEnabled(tt.command))
+		})
+	}
+}
+
+func Test_CommandsConfig_UnknownNames(t *testing.T) {
+	known := []string{"ping", "echo", "ban"}
+
+	tests := []struct {
+		name string
+		cfg  config.CommandsConfig
+		want []string
+	}{
+		{name: "no config", cfg: config.CommandsConfig{}, want: []string{}},
+		{name: "all names known", cfg: config.CommandsConfig{Enabled: []string{"ping"}, Disabled: []string{"ban"}}, want: []string{}},
+		{name: "typo in disabled", cfg: config.CommandsConfig{Disabled: []string{"bna"}}, want: []string{"bna"}},
+		{name: "typo in maintenance exemptions", cfg: config.CommandsConfig{MaintenanceExempt: []string{"ping", "prems"}}, want: []string{"prems"}},
+		{name: "typos across both lists sorted and deduplicated", cfg: config.CommandsConfig{Enabled: []string{"pnig", "zap"}, Disabled: []string{"zap", "ban"}}, want: []string{"pnig", "zap"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cfg.UnknownNames(known))
+		})
+	}
+}
+
+func Test_Load_CommandsSection(t *testing.T) {
+	clearEnvVars(t)
+
+	path := createTempConfigFile(t, `
+discord:
+  token: "test-token"
+commands:
+  enabled: [ping, echo, ban]
+  disabled: [ban]
+  maintenance_message: "Back soon!"
+  maintenance_exempt: [ping]
+`)
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ping", "echo", "ban"}, cfg.Commands.Enabled)
+	assert.Equal(t, []string{"ban"}, cfg.Commands.Disabled)
+	assert.Equal(t, "Back soon!", cfg.Commands.MaintenanceMessage)
+	assert.Equal(t, []string{"ping"}, cfg.Commands.MaintenanceExempt)
+}
+
+func Test_Load_CommandsDisabledFromEnv(t *testing.T) {
+	clearEnvVars(t)
+	t.Setenv("JAMESBOT_DISCORD_TOKEN", "env-token")
+	t.Setenv("JAMESBOT_COMMANDS_DISABLED", "ban,kick")
+
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ban", "kick"}, cfg.Commands.Disabled)
+	assert.False(t, cfg.Commands.IsEnabled("kick"))
+}
@@ -8,20 +8,65 @@ type Config struct {
 	Discord  DiscordConfig  `mapstructure:"discord"`
 	Logging  LoggingConfig  `mapstructure:"logging"`
 	Shutdown ShutdownConfig `mapstructure:"shutdown"`
+	Commands CommandsConfig `mapstructure:"commands"`
+
+	Interactions InteractionsConfig `mapstructure:"interactions"`
+	Cache        CacheConfig        `mapstructure:"cache"`
+	Control      ControlConfig      `mapstructure:"control"`
+	Alerts       AlertsConfig       `mapstructure:"alerts"`
+	Snipe        SnipeConfig        `mapstructure:"snipe"`
+	Stats        StatsConfig        `mapstructure:"stats"`
+	Rules        RulesConfig        `mapstructure:"rules"`
 }
 
 // DiscordConfig contains Discord-specific configuration.
 type DiscordConfig struct {
 	// Token is the Discord bot token used for authentication.
-	Token string `mapstructure:"token"`
+	// It is redacted whenever the config is displayed.
+	Token string `mapstructure:"token" secret:"true"`
+
+	// TokenFile is a file to read the token from instead, such as a secret
+	// mounted by Docker or Kubernetes, so the token stays out of the config
+	// and the environment. Trailing whitespace, such as the newline most
+	// editors add, is trimmed. It is an error to set both Token and TokenFile.
+	TokenFile string `mapstructure:"token_file"`
 
 	// GuildID is the Discord server (guild) ID where the bot operates.
+	// Slash commands are registered to it, for instant updates during
+	// development, unless Global is set.
 	GuildID string `mapstructure:"guild_id"`
 
+	// Global registers slash commands in every server the bot is in, as in
+	// production, even when GuildID is set. Global commands can take up to an
+	// hour to update. Commands are also global when GuildID is empty.
+	Global bool `mapstructure:"global"`
+
 	// CleanupOnShutdown determines whether to remove registered commands on shutdown.
 	CleanupOnShutdown bool `mapstructure:"cleanup_on_shutdown"`
+
+	// AllowedGuilds, when set, lists the IDs of the only guilds the bot
+	// operates in, for a private bot. Empty allows every guild.
+	AllowedGuilds []string `mapstructure:"allowed_guilds"`
+
+	// LeaveOtherGuilds makes the bot leave guilds not in AllowedGuilds as
+	// soon as it finds itself in one. Otherwise it stays but ignores their
+	// commands.
+	LeaveOtherGuilds bool `mapstructure:"leave_other_guilds"`
+
+	// PrivilegedIntents lists privileged gateway intents to request on
+	// connecting even when no rule setting needs them yet, so rules needing
+	// them can be enabled while the bot runs: IntentMessageContent,
+	// IntentServerMembers, or both. Each must also be enabled for the bot in
+	// the Developer Portal.
+	PrivilegedIntents []string `mapstructure:"privileged_intents"`
 }
 
+// Privileged gateway intents, as named in discord.privileged_intents.
+const (
+	IntentMessageContent = "message_content"
+	IntentServerMembers  = "server_members"
+)
+
 // LoggingConfig contains logging configuration.
 type LoggingConfig struct {
 	// Level is the minimum log level (debug, info, warn, error, fatal, panic).
@@ -29,10 +74,193 @@ type LoggingConfig struct {
 
 	// Format is the log output format (console, json).
 	Format string `mapstructure:"format"`
+
+	// SampleSuccesses logs only one in every N successful routine commands.
+	// Failures and moderation commands are always logged. 0 or 1 logs all.
+	SampleSuccesses int `mapstructure:"sample_successes"`
+
+	// Options adds the options each command was run with to its log lines.
+	// Off by default, since option values may hold personal information.
+	Options bool `mapstructure:"options"`
+
+	// OptionsRedact names command options whose values are left out of log
+	// lines when Options is set.
+	OptionsRedact []string `mapstructure:"options_redact"`
+
+	// Audit writes a structured JSON record of every command execution, with
+	// its options and outcome, for auditing moderation activity. Records go
+	// to AuditFile when set; otherwise they replace the usual command log
+	// lines, so executions are not logged twice.
+	Audit bool `mapstructure:"audit"`
+
+	// AuditFile is a file audit records are appended to, apart from the log.
+	AuditFile string `mapstructure:"audit_file"`
+
+	// AuditRedact names command options whose values are left out of audit
+	// records, such as free-text reasons.
+	AuditRedact []string `mapstructure:"audit_redact"`
+
+	// CrashFile is a file crash reports are appended to, apart from the log,
+	// when the bot exits on a panic. Empty writes them to the log only.
+	CrashFile string `mapstructure:"crash_file"`
 }
 
 // ShutdownConfig contains graceful shutdown configuration.
 type ShutdownConfig struct {
 	// Timeout is the maximum duration to wait for graceful shutdown.
 	Timeout time.Duration `mapstructure:"timeout"`
+
+	// InterruptTimeout, when positive, replaces Timeout for shutdowns
+	// triggered by SIGINT, such as Ctrl-C during development.
+	InterruptTimeout time.Duration `mapstructure:"interrupt_timeout"`
+
+	// TerminateTimeout, when positive, replaces Timeout for shutdowns
+	// triggered by SIGTERM, such as from a process manager or orchestrator
+	// that allows time to drain.
+	TerminateTimeout time.Duration `mapstructure:"terminate_timeout"`
+}
+
+// CommandsConfig controls which commands are registered with Discord at startup
+// and how the moderation commands behave.
+type CommandsConfig struct {
+	// Enabled, when non-empty, restricts registration to the listed command names.
+	Enabled []string `mapstructure:"enabled"`
+
+	// Disabled lists command names that should not be registered.
+	// A command listed in both Enabled and Disabled is disabled.
+	Disabled []string `mapstructure:"disabled"`
+
+	// NotifyTargets makes kick, ban, and mute DM the member the action, reason,
+	// and duration before acting, while the bot can still reach them.
+	NotifyTargets bool `mapstructure:"notify_targets"`
+
+	// ConfirmDestructive makes ban ask the moderator to confirm with buttons
+	// before acting. Disable it for speed at the risk of accidental bans.
+	ConfirmDestructive bool `mapstructure:"confirm_destructive"`
+
+	// Prefix, when set, also runs commands written as guild messages starting
+	// with it, such as "!ban @user spam". Empty disables text commands.
+	Prefix string `mapstructure:"prefix"`
+
+	// AutoDefer, when positive, defers the response to any slash command that
+	// has not responded within it, so slow commands show the bot thinking
+	// instead of failing. It must be under Discord's three second limit.
+	AutoDefer time.Duration `mapstructure:"auto_defer"`
+
+	// DeniedMessage, when set, replaces the localized reply to members who
+	// run a text command without the permissions it requires.
+	DeniedMessage string `mapstructure:"denied_message"`
+
+	// Cooldown, when positive, makes each member wait this long between uses
+	// of the same command. Members with the Manage Server permission or one
+	// of CooldownBypassRoles are never held back.
+	Cooldown time.Duration `mapstructure:"cooldown"`
+
+	// CooldownBypassRoles lists IDs of roles whose members skip Cooldown.
+	CooldownBypassRoles []string `mapstructure:"cooldown_bypass_roles"`
+
+	// MaintenanceMessage, when set, replaces the localized reply to commands
+	// held back while the bot is in maintenance mode.
+	MaintenanceMessage string `mapstructure:"maintenance_message"`
+
+	// MaintenanceExempt names commands that keep running in maintenance
+	// mode. Administrators can run every command regardless.
+	MaintenanceExempt []string `mapstructure:"maintenance_exempt"`
+}
+
+// InteractionsConfig bounds how many interactions are processed at once.
+type InteractionsConfig struct {
+	// Workers is the number of commands that may execute concurrently.
+	Workers int `mapstructure:"workers"`
+
+	// QueueSize is how many interactions may wait for a free worker. Once the
+	// queue is full, new interactions get a "busy" response instead of running.
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// CacheConfig controls the caches that save repeated Discord API calls.
+type CacheConfig struct {
+	// MemberTTL is how long a fetched guild member is reused. 0 disables the cache.
+	MemberTTL time.Duration `mapstructure:"member_ttl"`
+
+	// MemberSize is the most members the cache holds at once.
+	MemberSize int `mapstructure:"member_size"`
+}
+
+// ControlConfig configures the local control API that the CLI talks to.
+type ControlConfig struct {
+	// Enabled starts the control API with the bot. When false, no port is
+	// bound and CLI commands that need a running bot cannot reach it.
+	Enabled bool `mapstructure:"enabled"`
+
+	// TLSCertFile and TLSKeyFile are PEM files that make the control API serve
+	// HTTPS. Set both or neither.
+	TLSCertFile string `mapstructure:"tls_cert_file"`
+	TLSKeyFile  string `mapstructure:"tls_key_file"`
+
+	// AuthToken is the bearer token requests to the moderation, punishment
+	// cancel, warning clear, command toggle and reload, and maintenance
+	// endpoints must carry. When empty, those endpoints refuse every request,
+	// since they act on members or Discord as the bot or change its behaviour.
+	AuthToken string `mapstructure:"auth_token" secret:"true"`
+}
+
+// AlertsConfig configures alerts posted to an operators' channel when
+// commands start failing, such as after the bot loses a permission.
+type AlertsConfig struct {
+	// ChannelID is the channel alerts are posted in. Empty disables alerts.
+	ChannelID string `mapstructure:"channel_id"`
+
+	// ErrorThreshold is the fraction of commands, above 0 and at most 1, that
+	// must fail within Window to raise an alert.
+	ErrorThreshold float64 `mapstructure:"error_threshold"`
+
+	// Window is how far back failures are counted.
+	Window time.Duration `mapstructure:"window"`
+
+	// MinCommands is how many commands must run within Window before an
+	// alert can be raised, so one failure on a quiet bot is not an outage.
+	MinCommands int `mapstructure:"min_commands"`
+
+	// Cooldown is how long further alerts are held back after one is posted.
+	// It doubles with each alert while failures continue, up to MaxCooldown.
+	Cooldown    time.Duration `mapstructure:"cooldown"`
+	MaxCooldown time.Duration `mapstructure:"max_cooldown"`
+
+	// DenialThreshold, when positive, posts an alert once a member has been
+	// denied a command for lacking permissions this many times within
+	// DenialWindow. 0 disables denial alerts.
+	DenialThreshold int           `mapstructure:"denial_threshold"`
+	DenialWindow    time.Duration `mapstructure:"denial_window"`
+}
+
+// SnipeConfig configures how deleted messages are remembered for the snipe
+// command. Messages are held in memory only and are forgotten after Retention.
+type SnipeConfig struct {
+	// Retention is how long a message is remembered after it is sent. Zero
+	// disables remembering messages, so the snipe command finds none.
+	Retention time.Duration `mapstructure:"retention"`
+
+	// ExcludedChannels are channels whose messages are never remembered.
+	ExcludedChannels []string `mapstructure:"excluded_channels"`
+}
+
+// StatsConfig configures keeping command execution counts across restarts.
+type StatsConfig struct {
+	// File, when set, is where lifetime command counts are saved. They are
+	// loaded when the bot starts, saved every FlushInterval, and saved again
+	// on shutdown. Empty keeps counts in memory only.
+	File string `mapstructure:"file"`
+
+	// FlushInterval is how often counts are saved to File. Counts since the
+	// last save are lost if the bot crashes.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// RulesConfig configures keeping rule and server settings across restarts.
+type RulesConfig struct {
+	// File, when set, is where settings changed through the control API are
+	// saved. They are loaded when the bot starts and saved after every
+	// change. Empty keeps them in memory only.
+	File string `mapstructure:"file"`
 }
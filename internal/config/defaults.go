@@ -0,0 +1,50 @@
+package config
+
+import "time"
+
+// Defaults returns the configuration used for every option a config file and
+// the environment leave unset. Only discord.token has no usable default, so
+// a config setting just the token runs a fully functional bot.
+func Defaults() *Config {
+	return &Config{
+		Discord: DiscordConfig{
+			LeaveOtherGuilds: true,
+		},
+		Logging: LoggingConfig{
+			Level:           "info",
+			Format:          "console",
+			SampleSuccesses: 1,
+		},
+		Shutdown: ShutdownConfig{
+			Timeout: 10 * time.Second,
+		},
+		Commands: CommandsConfig{
+			ConfirmDestructive: true,
+		},
+		Interactions: InteractionsConfig{
+			Workers:   16,
+			QueueSize: 100,
+		},
+		Cache: CacheConfig{
+			MemberTTL:  30 * time.Second,
+			MemberSize: 1000,
+		},
+		Control: ControlConfig{
+			Enabled: true,
+		},
+		Alerts: AlertsConfig{
+			ErrorThreshold: 0.5,
+			Window:         5 * time.Minute,
+			MinCommands:    10,
+			Cooldown:       10 * time.Minute,
+			MaxCooldown:    6 * time.Hour,
+			DenialWindow:   10 * time.Minute,
+		},
+		Snipe: SnipeConfig{
+			Retention: 5 * time.Minute,
+		},
+		Stats: StatsConfig{
+			FlushInterval: time.Minute,
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PathEnvVar is the environment variable naming a config file to load.
+const PathEnvVar = "JAMESBOT_CONFIG"
+
+// SearchPaths returns the candidate config file locations in priority order:
+// the explicit path (typically the --config flag), $JAMESBOT_CONFIG,
+// ./config/config.yaml, $XDG_CONFIG_HOME/jamesbot/config.yaml (falling back to
+// ~/.config when XDG_CONFIG_HOME is unset), and /etc/jamesbot/config.yaml.
+// Empty candidates are omitted.
+func SearchPaths(explicit string) []string {
+	var paths []string
+	if explicit != "" {
+		paths = append(paths, explicit)
+	}
+	if env := os.Getenv(PathEnvVar); env != "" {
+		paths = append(paths, env)
+	}
+	paths = append(paths, filepath.Join("config", "config.yaml"))
+	if dir := xdgConfigHome(); dir != "" {
+		paths = append(paths, filepath.Join(dir, "jamesbot", "config.yaml"))
+	}
+	paths = append(paths, filepath.Join("/etc", "jamesbot", "config.yaml"))
+	return paths
+}
+
+// Discover returns the config file to load. An explicit path is returned as is
+// if it is a regular file, and is an error otherwise, rather than a reason to
+// load whichever file the search finds instead. Without one, Discover returns
+// the first path from SearchPaths that exists as a regular file, or an empty
+// string if none does, meaning configuration should come from environment
+// variables alone.
+func Discover(explicit string) (string, error) {
+	if explicit != "" {
+		info, err := os.Stat(explicit)
+		if err != nil {
+			return "", fmt.Errorf("failed to read config file: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return "", fmt.Errorf("failed to read config file: %s is not a regular file", explicit)
+		}
+		return explicit, nil
+	}
+
+	for _, path := range SearchPaths("") {
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().IsRegular() {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// xdgConfigHome returns $XDG_CONFIG_HOME, or ~/.config if it is unset.
+func xdgConfigHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
@@ -0,0 +1,148 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jamesbot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("discord:\n  token: test\n"), 0o600))
+}
+
+func Test_SearchPaths_Order(t *testing.T) {
+	t.Setenv(config.PathEnvVar, "/env/config.yaml")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+
+	got := config.SearchPaths("/flag/config.yaml")
+
+	assert.Equal(t, []string{
+		"/flag/config.yaml",
+		"/env/config.yaml",
+		filepath.Join("config", "config.yaml"),
+		filepath.Join("/xdg", "jamesbot", "config.yaml"),
+		filepath.Join("/etc", "jamesbot", "config.yaml"),
+	}, got)
+}
+
+func Test_SearchPaths_OmitsEmpty(t *testing.T) {
+	t.Setenv(config.PathEnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+
+	got := config.SearchPaths("")
+
+	assert.Equal(t, filepath.Join("config", "config.yaml"), got[0])
+	assert.NotContains(t, got, "")
+}
+
+func Test_Discover(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string // relative to the temp root; "cwd/..." lives in the working directory
+		flag    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "flag wins when it exists",
+			files: []string{"flag.yaml", "env.yaml", "cwd/config/config.yaml", "xdg/jamesbot/config.yaml"},
+			flag:  "flag.yaml",
+			env:   "env.yaml",
+			want:  "flag.yaml",
+		},
+		{
+			name:    "missing flag file is an error, not a reason to search",
+			files:   []string{"env.yaml", "cwd/config/config.yaml"},
+			flag:    "missing.yaml",
+			env:     "env.yaml",
+			wantErr: true,
+		},
+		{
+			name:  "env used without a flag",
+			files: []string{"env.yaml", "cwd/config/config.yaml"},
+			env:   "env.yaml",
+			want:  "env.yaml",
+		},
+		{
+			name:  "working directory before XDG",
+			files: []string{"cwd/config/config.yaml", "xdg/jamesbot/config.yaml"},
+			want:  filepath.Join("config", "config.yaml"),
+		},
+		{
+			name:  "XDG used when nothing closer exists",
+			files: []string{"xdg/jamesbot/config.yaml"},
+			want:  "xdg/jamesbot/config.yaml",
+		},
+		{
+			name:    "flag naming a directory is an error",
+			files:   []string{"xdg/jamesbot/config.yaml"},
+			flag:    ".",
+			wantErr: true,
+		},
+		{
+			name:  "directories are skipped",
+			files: []string{"xdg/jamesbot/config.yaml"},
+			env:   "xdg",
+			want:  "xdg/jamesbot/config.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, f := range tt.files {
+				writeConfigFile(t, filepath.Join(root, f))
+			}
+			cwd := filepath.Join(root, "cwd")
+			require.NoError(t, os.MkdirAll(cwd, 0o755))
+			t.Chdir(cwd)
+
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+			env := ""
+			if tt.env != "" {
+				env = filepath.Join(root, tt.env)
+			}
+			t.Setenv(config.PathEnvVar, env)
+			flag := tt.flag
+			if flag != "" && flag != "." {
+				flag = filepath.Join(root, flag)
+			}
+
+			got, err := config.Discover(flag)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), flag, "error should name the flag's path")
+				assert.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+
+			want := tt.want
+			if want != filepath.Join("config", "config.yaml") {
+				want = filepath.Join(root, want)
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func Test_Discover_NoneFound(t *testing.T) {
+	if _, err := os.Stat("/etc/jamesbot/config.yaml"); err == nil {
+		t.Skip("system config file present")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv(config.PathEnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	got, err := config.Discover("")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
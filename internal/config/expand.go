@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+)
+
+// envRefPattern matches ${VAR} and ${VAR:-default} references.
+// Bare $VAR references are deliberately not matched so literal values
+// containing a dollar sign are left untouched.
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnv replaces ${VAR} and ${VAR:-default} references in s with values
+// from the environment. With a default, the default is used when VAR is unset
+// or empty; without one, an unset VAR expands to the empty string.
+func expandEnv(s string) string {
+	return envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		m := envRefPattern.FindStringSubmatchIndex(ref)
+		name := ref[m[2]:m[3]]
+		hasDefault := m[4] >= 0
+
+		value, ok := os.LookupEnv(name)
+		if hasDefault && (!ok || value == "") {
+			return ref[m[4]:m[5]]
+		}
+		return value
+	})
+}
+
+// expandConfigEnv applies expandEnv to every string and string slice field in
+// cfg, recursing into nested structs.
+func expandConfigEnv(cfg *Config) {
+	expandValue(reflect.ValueOf(cfg).Elem())
+}
+
+func expandValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(expandEnv(v.String()))
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.String {
+			for i := 0; i < v.Len(); i++ {
+				expandValue(v.Index(i))
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			expandValue(v.Field(i))
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"jamesbot/internal/config"
+	"jamesbot/pkg/errutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Load_EnvInterpolation(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		unset       []string
+		content     string
+		wantToken   string
+		wantGuildID string
+		wantLevel   string
+		wantEnabled []string
+	}{
+		{
+			name:      "present variable is substituted",
+			env:       map[string]string{"TEST_DISCORD_TOKEN": "secret-token"},
+			content:   "discord:\n  token: ${TEST_DISCORD_TOKEN}\n",
+			wantToken: "secret-token",
+		},
+		{
+			name:      "absent variable uses default",
+			unset:     []string{"TEST_LOG_LEVEL"},
+			content:   "discord:\n  token: abc\nlogging:\n  level: ${TEST_LOG_LEVEL:-debug}\n",
+			wantToken: "abc",
+			wantLevel: "debug",
+		},
+		{
+			name:      "empty variable uses default",
+			env:       map[string]string{"TEST_LOG_LEVEL": ""},
+			content:   "discord:\n  token: abc\nlogging:\n  level: ${TEST_LOG_LEVEL:-warn}\n",
+			wantToken: "abc",
+			wantLevel: "warn",
+		},
+		{
+			name:      "present variable wins over default",
+			env:       map[string]string{"TEST_LOG_LEVEL": "error"},
+			content:   "discord:\n  token: abc\nlogging:\n  level: ${TEST_LOG_LEVEL:-warn}\n",
+			wantToken: "abc",
+			wantLevel: "error",
+		},
+		{
+			name:        "reference embedded in a larger value",
+			env:         map[string]string{"TEST_GUILD": "42"},
+			content:     "discord:\n  token: abc\n  guild_id: \"guild-${TEST_GUILD}\"\n",
+			wantToken:   "abc",
+			wantGuildID: "guild-42",
+		},
+		{
+			name:      "literal values are untouched",
+			env:       map[string]string{"TEST_DISCORD_TOKEN": "unused"},
+			content:   "discord:\n  token: \"lit$TEST_DISCORD_TOKEN$x\"\n",
+			wantToken: "lit$TEST_DISCORD_TOKEN$x",
+		},
+		{
+			name:        "string lists are expanded",
+			env:         map[string]string{"TEST_COMMAND": "ping"},
+			content:     "discord:\n  token: abc\ncommands:\n  enabled: [\"${TEST_COMMAND}\", echo]\n",
+			wantToken:   "abc",
+			wantEnabled: []string{"ping", "echo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			for _, k := range tt.unset {
+				t.Setenv(k, "")
+				os.Unsetenv(k)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantToken, cfg.Discord.Token)
+			if tt.wantGuildID != "" {
+				assert.Equal(t, tt.wantGuildID, cfg.Discord.GuildID)
+			}
+			if tt.wantLevel != "" {
+				assert.Equal(t, tt.wantLevel, cfg.Logging.Level)
+			}
+			if tt.wantEnabled != nil {
+				assert.Equal(t, tt.wantEnabled, cfg.Commands.Enabled)
+			}
+		})
+	}
+}
+
+func Test_Load_EnvInterpolation_AbsentRequiredFails(t *testing.T) {
+	clearEnvVars(t)
+	t.Setenv("TEST_DISCORD_TOKEN", "")
+	os.Unsetenv("TEST_DISCORD_TOKEN")
+
+	cfg, err := config.Load(createTempConfigFile(t, "discord:\n  token: ${TEST_DISCORD_TOKEN}\n"))
+
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+
+	var configErr *errutil.ConfigError
+	if assert.ErrorAs(t, err, &configErr) {
+		assert.Equal(t, "discord.token", configErr.Key)
+	}
+}
@@ -2,29 +2,42 @@ package config
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	"jamesbot/pkg/errutil"
 
 	"github.com/spf13/viper"
 )
 
+// Override changes a loaded configuration before it is validated, such as to
+// apply a command-line flag that takes precedence over the file and
+// environment.
+type Override func(cfg *Config)
+
 // Load reads and validates configuration from the specified file path.
 // It returns a Config struct or an error if loading or validation fails.
 //
-// The loader supports the following features:
-//   - Default values for all configuration options
-//   - Environment variable overrides with JAMESBOT_ prefix
-//   - Validation of required fields
+// Each option is resolved from, in increasing order of precedence:
+//   - Defaults
+//   - the config file at path, unless path is empty
+//   - environment variables with the JAMESBOT_ prefix
+//   - overrides, such as command-line flags, applied in order
+//
+// ${VAR} and ${VAR:-default} references in string values are expanded before
+// overrides are applied. discord.token_file, if set, is then read into
+// discord.token, and the result is validated as a whole.
 //
 // Environment variables use the pattern JAMESBOT_<SECTION>_<KEY>,
 // for example: JAMESBOT_DISCORD_TOKEN, JAMESBOT_LOGGING_LEVEL
-func Load(path string) (*Config, error) {
+func Load(path string, overrides ...Override) (*Config, error) {
 	v := viper.New()
 
 	// Set default values
-	setDefaults(v)
+	setDefaults(v, reflect.ValueOf(*Defaults()), "")
 
 	// Configure environment variable binding
 	v.SetEnvPrefix("JAMESBOT")
@@ -33,8 +46,54 @@ func Load(path string) (*Config, error) {
 
 	// Explicitly bind environment variables for keys that may not exist in config file
 	_ = v.BindEnv("discord.token", "JAMESBOT_DISCORD_TOKEN")
+	_ = v.BindEnv("discord.token_file", "JAMESBOT_DISCORD_TOKEN_FILE")
+	_ = v.BindEnv("discord.global", "JAMESBOT_DISCORD_GLOBAL")
+	_ = v.BindEnv("discord.allowed_guilds", "JAMESBOT_DISCORD_ALLOWED_GUILDS")
+	_ = v.BindEnv("discord.leave_other_guilds", "JAMESBOT_DISCORD_LEAVE_OTHER_GUILDS")
+	_ = v.BindEnv("discord.privileged_intents", "JAMESBOT_DISCORD_PRIVILEGED_INTENTS")
 	_ = v.BindEnv("logging.level", "JAMESBOT_LOGGING_LEVEL")
+	_ = v.BindEnv("logging.sample_successes", "JAMESBOT_LOGGING_SAMPLE_SUCCESSES")
+	_ = v.BindEnv("logging.options", "JAMESBOT_LOGGING_OPTIONS")
+	_ = v.BindEnv("logging.options_redact", "JAMESBOT_LOGGING_OPTIONS_REDACT")
+	_ = v.BindEnv("logging.audit", "JAMESBOT_LOGGING_AUDIT")
+	_ = v.BindEnv("logging.audit_file", "JAMESBOT_LOGGING_AUDIT_FILE")
+	_ = v.BindEnv("logging.audit_redact", "JAMESBOT_LOGGING_AUDIT_REDACT")
+	_ = v.BindEnv("logging.crash_file", "JAMESBOT_LOGGING_CRASH_FILE")
 	_ = v.BindEnv("shutdown.timeout", "JAMESBOT_SHUTDOWN_TIMEOUT")
+	_ = v.BindEnv("shutdown.interrupt_timeout", "JAMESBOT_SHUTDOWN_INTERRUPT_TIMEOUT")
+	_ = v.BindEnv("shutdown.terminate_timeout", "JAMESBOT_SHUTDOWN_TERMINATE_TIMEOUT")
+	_ = v.BindEnv("commands.enabled", "JAMESBOT_COMMANDS_ENABLED")
+	_ = v.BindEnv("commands.disabled", "JAMESBOT_COMMANDS_DISABLED")
+	_ = v.BindEnv("commands.notify_targets", "JAMESBOT_COMMANDS_NOTIFY_TARGETS")
+	_ = v.BindEnv("commands.confirm_destructive", "JAMESBOT_COMMANDS_CONFIRM_DESTRUCTIVE")
+	_ = v.BindEnv("commands.prefix", "JAMESBOT_COMMANDS_PREFIX")
+	_ = v.BindEnv("commands.auto_defer", "JAMESBOT_COMMANDS_AUTO_DEFER")
+	_ = v.BindEnv("commands.denied_message", "JAMESBOT_COMMANDS_DENIED_MESSAGE")
+	_ = v.BindEnv("commands.cooldown", "JAMESBOT_COMMANDS_COOLDOWN")
+	_ = v.BindEnv("commands.cooldown_bypass_roles", "JAMESBOT_COMMANDS_COOLDOWN_BYPASS_ROLES")
+	_ = v.BindEnv("commands.maintenance_message", "JAMESBOT_COMMANDS_MAINTENANCE_MESSAGE")
+	_ = v.BindEnv("commands.maintenance_exempt", "JAMESBOT_COMMANDS_MAINTENANCE_EXEMPT")
+	_ = v.BindEnv("interactions.workers", "JAMESBOT_INTERACTIONS_WORKERS")
+	_ = v.BindEnv("interactions.queue_size", "JAMESBOT_INTERACTIONS_QUEUE_SIZE")
+	_ = v.BindEnv("cache.member_ttl", "JAMESBOT_CACHE_MEMBER_TTL")
+	_ = v.BindEnv("cache.member_size", "JAMESBOT_CACHE_MEMBER_SIZE")
+	_ = v.BindEnv("control.enabled", "JAMESBOT_CONTROL_ENABLED")
+	_ = v.BindEnv("control.tls_cert_file", "JAMESBOT_CONTROL_TLS_CERT_FILE")
+	_ = v.BindEnv("control.tls_key_file", "JAMESBOT_CONTROL_TLS_KEY_FILE")
+	_ = v.BindEnv("control.auth_token", "JAMESBOT_CONTROL_AUTH_TOKEN")
+	_ = v.BindEnv("alerts.channel_id", "JAMESBOT_ALERTS_CHANNEL_ID")
+	_ = v.BindEnv("alerts.error_threshold", "JAMESBOT_ALERTS_ERROR_THRESHOLD")
+	_ = v.BindEnv("alerts.window", "JAMESBOT_ALERTS_WINDOW")
+	_ = v.BindEnv("alerts.min_commands", "JAMESBOT_ALERTS_MIN_COMMANDS")
+	_ = v.BindEnv("alerts.cooldown", "JAMESBOT_ALERTS_COOLDOWN")
+	_ = v.BindEnv("alerts.max_cooldown", "JAMESBOT_ALERTS_MAX_COOLDOWN")
+	_ = v.BindEnv("alerts.denial_threshold", "JAMESBOT_ALERTS_DENIAL_THRESHOLD")
+	_ = v.BindEnv("alerts.denial_window", "JAMESBOT_ALERTS_DENIAL_WINDOW")
+	_ = v.BindEnv("snipe.retention", "JAMESBOT_SNIPE_RETENTION")
+	_ = v.BindEnv("snipe.excluded_channels", "JAMESBOT_SNIPE_EXCLUDED_CHANNELS")
+	_ = v.BindEnv("stats.file", "JAMESBOT_STATS_FILE")
+	_ = v.BindEnv("stats.flush_interval", "JAMESBOT_STATS_FLUSH_INTERVAL")
+	_ = v.BindEnv("rules.file", "JAMESBOT_RULES_FILE")
 
 	// Load configuration file if path is provided
 	if path != "" {
@@ -50,6 +109,17 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Expand ${VAR} and ${VAR:-default} references in string values
+	expandConfigEnv(&cfg)
+
+	for _, override := range overrides {
+		override(&cfg)
+	}
+
+	if err := readTokenFile(&cfg.Discord); err != nil {
+		return nil, err
+	}
+
 	// Validate required fields
 	if err := validate(&cfg); err != nil {
 		return nil, err
@@ -58,17 +128,27 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
-// setDefaults configures default values for all configuration options.
-func setDefaults(v *viper.Viper) {
-	// Logging defaults
-	v.SetDefault("logging.level", "info")
-	v.SetDefault("logging.format", "console")
-
-	// Shutdown defaults
-	v.SetDefault("shutdown.timeout", 10*time.Second)
+// setDefaults makes every option in defaults, by its dotted key, the value
+// v falls back to. Lists are left out, since a default list would replace
+// rather than be replaced by a list from the environment.
+func setDefaults(v *viper.Viper, defaults reflect.Value, prefix string) {
+	t := defaults.Type()
+	for i := 0; i < defaults.NumField(); i++ {
+		field := defaults.Field(i)
+		key := t.Field(i).Tag.Get("mapstructure")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
 
-	// Discord defaults
-	v.SetDefault("discord.cleanup_on_shutdown", false)
+		switch field.Kind() {
+		case reflect.Struct:
+			setDefaults(v, field, key)
+		case reflect.Slice:
+			// Left to the file and environment
+		default:
+			v.SetDefault(key, field.Interface())
+		}
+	}
 }
 
 // validate checks that all required configuration fields are present and valid.
@@ -81,5 +161,181 @@ func validate(cfg *Config) error {
 		}
 	}
 
+	if len(cfg.Discord.AllowedGuilds) > 0 && cfg.Discord.GuildID != "" &&
+		!slices.Contains(cfg.Discord.AllowedGuilds, cfg.Discord.GuildID) {
+		return &errutil.ConfigError{
+			Key:     "discord.allowed_guilds",
+			Message: "must include discord.guild_id, where commands are registered",
+		}
+	}
+
+	for _, intent := range cfg.Discord.PrivilegedIntents {
+		if intent != IntentMessageContent && intent != IntentServerMembers {
+			return &errutil.ConfigError{
+				Key:     "discord.privileged_intents",
+				Message: fmt.Sprintf("unknown intent %q (valid: %s, %s)", intent, IntentMessageContent, IntentServerMembers),
+			}
+		}
+	}
+
+	if cfg.Logging.SampleSuccesses < 0 {
+		return &errutil.ConfigError{
+			Key:     "logging.sample_successes",
+			Message: "must not be negative",
+		}
+	}
+
+	if cfg.Shutdown.InterruptTimeout < 0 {
+		return &errutil.ConfigError{
+			Key:     "shutdown.interrupt_timeout",
+			Message: "must not be negative",
+		}
+	}
+
+	if cfg.Shutdown.TerminateTimeout < 0 {
+		return &errutil.ConfigError{
+			Key:     "shutdown.terminate_timeout",
+			Message: "must not be negative",
+		}
+	}
+
+	if strings.ContainsFunc(cfg.Commands.Prefix, unicode.IsSpace) {
+		return &errutil.ConfigError{
+			Key:     "commands.prefix",
+			Message: "must not contain whitespace",
+		}
+	}
+
+	if cfg.Commands.AutoDefer < 0 || cfg.Commands.AutoDefer >= 3*time.Second {
+		return &errutil.ConfigError{
+			Key:     "commands.auto_defer",
+			Message: "must be between 0s and 3s, Discord's deadline for a response",
+		}
+	}
+
+	if cfg.Commands.Cooldown < 0 {
+		return &errutil.ConfigError{
+			Key:     "commands.cooldown",
+			Message: "must not be negative",
+		}
+	}
+
+	if cfg.Interactions.Workers < 1 {
+		return &errutil.ConfigError{
+			Key:     "interactions.workers",
+			Message: "must be at least 1",
+		}
+	}
+
+	if cfg.Interactions.QueueSize < 0 {
+		return &errutil.ConfigError{
+			Key:     "interactions.queue_size",
+			Message: "must not be negative",
+		}
+	}
+
+	if cfg.Cache.MemberTTL < 0 {
+		return &errutil.ConfigError{
+			Key:     "cache.member_ttl",
+			Message: "must not be negative",
+		}
+	}
+
+	if cfg.Cache.MemberTTL > 0 && cfg.Cache.MemberSize < 1 {
+		return &errutil.ConfigError{
+			Key:     "cache.member_size",
+			Message: "must be at least 1 while the member cache is enabled",
+		}
+	}
+
+	if cfg.Control.TLSCertFile != "" && cfg.Control.TLSKeyFile == "" {
+		return &errutil.ConfigError{
+			Key:     "control.tls_key_file",
+			Message: "is required when control.tls_cert_file is set",
+		}
+	}
+
+	if cfg.Control.TLSKeyFile != "" && cfg.Control.TLSCertFile == "" {
+		return &errutil.ConfigError{
+			Key:     "control.tls_cert_file",
+			Message: "is required when control.tls_key_file is set",
+		}
+	}
+
+	if cfg.Alerts.ChannelID != "" {
+		if err := validateAlerts(cfg.Alerts); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Snipe.Retention < 0 || cfg.Snipe.Retention > time.Hour {
+		return &errutil.ConfigError{
+			Key:     "snipe.retention",
+			Message: "must be between 0s and 1h",
+		}
+	}
+
+	if cfg.Stats.File != "" && cfg.Stats.FlushInterval <= 0 {
+		return &errutil.ConfigError{
+			Key:     "stats.flush_interval",
+			Message: "must be positive when stats.file is set",
+		}
+	}
+
+	return nil
+}
+
+// validateAlerts checks the alert settings, which only matter when alerts
+// are enabled.
+func validateAlerts(cfg AlertsConfig) error {
+	if cfg.ErrorThreshold <= 0 || cfg.ErrorThreshold > 1 {
+		return &errutil.ConfigError{
+			Key:     "alerts.error_threshold",
+			Message: "must be above 0 and at most 1",
+		}
+	}
+
+	if cfg.Window <= 0 {
+		return &errutil.ConfigError{
+			Key:     "alerts.window",
+			Message: "must be positive",
+		}
+	}
+
+	if cfg.MinCommands < 1 {
+		return &errutil.ConfigError{
+			Key:     "alerts.min_commands",
+			Message: "must be at least 1",
+		}
+	}
+
+	if cfg.Cooldown <= 0 {
+		return &errutil.ConfigError{
+			Key:     "alerts.cooldown",
+			Message: "must be positive",
+		}
+	}
+
+	if cfg.MaxCooldown < cfg.Cooldown {
+		return &errutil.ConfigError{
+			Key:     "alerts.max_cooldown",
+			Message: "must not be less than alerts.cooldown",
+		}
+	}
+
+	if cfg.DenialThreshold < 0 {
+		return &errutil.ConfigError{
+			Key:     "alerts.denial_threshold",
+			Message: "must not be negative",
+		}
+	}
+
+	if cfg.DenialThreshold > 0 && cfg.DenialWindow <= 0 {
+		return &errutil.ConfigError{
+			Key:     "alerts.denial_window",
+			Message: "must be positive while alerts.denial_threshold is set",
+		}
+	}
+
 	return nil
 }
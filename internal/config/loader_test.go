@@ -33,8 +33,52 @@ func clearEnvVars(t *testing.T) {
 
 	envVars := []string{
 		"JAMESBOT_DISCORD_TOKEN",
+		"JAMESBOT_DISCORD_TOKEN_FILE",
+		"JAMESBOT_DISCORD_GLOBAL",
+		"JAMESBOT_DISCORD_ALLOWED_GUILDS",
+		"JAMESBOT_DISCORD_LEAVE_OTHER_GUILDS",
+		"JAMESBOT_DISCORD_PRIVILEGED_INTENTS",
 		"JAMESBOT_LOGGING_LEVEL",
+		"JAMESBOT_LOGGING_SAMPLE_SUCCESSES",
+		"JAMESBOT_LOGGING_OPTIONS",
+		"JAMESBOT_LOGGING_OPTIONS_REDACT",
+		"JAMESBOT_LOGGING_AUDIT",
+		"JAMESBOT_LOGGING_AUDIT_FILE",
+		"JAMESBOT_LOGGING_AUDIT_REDACT",
+		"JAMESBOT_LOGGING_CRASH_FILE",
 		"JAMESBOT_SHUTDOWN_TIMEOUT",
+		"JAMESBOT_SHUTDOWN_INTERRUPT_TIMEOUT",
+		"JAMESBOT_SHUTDOWN_TERMINATE_TIMEOUT",
+		"JAMESBOT_COMMANDS_NOTIFY_TARGETS",
+		"JAMESBOT_COMMANDS_CONFIRM_DESTRUCTIVE",
+		"JAMESBOT_COMMANDS_PREFIX",
+		"JAMESBOT_COMMANDS_AUTO_DEFER",
+		"JAMESBOT_COMMANDS_DENIED_MESSAGE",
+		"JAMESBOT_COMMANDS_COOLDOWN",
+		"JAMESBOT_COMMANDS_COOLDOWN_BYPASS_ROLES",
+		"JAMESBOT_COMMANDS_MAINTENANCE_MESSAGE",
+		"JAMESBOT_COMMANDS_MAINTENANCE_EXEMPT",
+		"JAMESBOT_INTERACTIONS_WORKERS",
+		"JAMESBOT_INTERACTIONS_QUEUE_SIZE",
+		"JAMESBOT_CACHE_MEMBER_TTL",
+		"JAMESBOT_CACHE_MEMBER_SIZE",
+		"JAMESBOT_CONTROL_ENABLED",
+		"JAMESBOT_CONTROL_TLS_CERT_FILE",
+		"JAMESBOT_CONTROL_TLS_KEY_FILE",
+		"JAMESBOT_CONTROL_AUTH_TOKEN",
+		"JAMESBOT_ALERTS_CHANNEL_ID",
+		"JAMESBOT_ALERTS_ERROR_THRESHOLD",
+		"JAMESBOT_ALERTS_WINDOW",
+		"JAMESBOT_ALERTS_MIN_COMMANDS",
+		"JAMESBOT_ALERTS_COOLDOWN",
+		"JAMESBOT_ALERTS_MAX_COOLDOWN",
+		"JAMESBOT_ALERTS_DENIAL_THRESHOLD",
+		"JAMESBOT_ALERTS_DENIAL_WINDOW",
+		"JAMESBOT_SNIPE_RETENTION",
+		"JAMESBOT_SNIPE_EXCLUDED_CHANNELS",
+		"JAMESBOT_STATS_FILE",
+		"JAMESBOT_STATS_FLUSH_INTERVAL",
+		"JAMESBOT_RULES_FILE",
 	}
 
 	for _, env := range envVars {
@@ -210,6 +254,89 @@ logging:
 	}
 }
 
+func Test_Load_TokenFile(t *testing.T) {
+	writeTokenFile := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T) string
+		expectedToken string
+		errContains   string
+	}{
+		{
+			name: "token read from file",
+			setup: func(t *testing.T) string {
+				path := writeTokenFile(t, "file-token")
+				return createTempConfigFile(t, "discord:\n  token_file: "+path+"\n")
+			},
+			expectedToken: "file-token",
+		},
+		{
+			name: "trailing newline trimmed",
+			setup: func(t *testing.T) string {
+				path := writeTokenFile(t, "file-token\r\n")
+				return createTempConfigFile(t, "discord:\n  token_file: "+path+"\n")
+			},
+			expectedToken: "file-token",
+		},
+		{
+			name: "token file from environment",
+			setup: func(t *testing.T) string {
+				t.Setenv("JAMESBOT_DISCORD_TOKEN_FILE", writeTokenFile(t, "env-file-token\n"))
+				return ""
+			},
+			expectedToken: "env-file-token",
+		},
+		{
+			name: "missing file",
+			setup: func(t *testing.T) string {
+				path := filepath.Join(t.TempDir(), "missing")
+				return createTempConfigFile(t, "discord:\n  token_file: "+path+"\n")
+			},
+			errContains: "discord.token_file",
+		},
+		{
+			name: "empty file",
+			setup: func(t *testing.T) string {
+				path := writeTokenFile(t, "\n")
+				return createTempConfigFile(t, "discord:\n  token_file: "+path+"\n")
+			},
+			errContains: "is empty",
+		},
+		{
+			name: "both token and token file",
+			setup: func(t *testing.T) string {
+				path := writeTokenFile(t, "file-token")
+				return createTempConfigFile(t, "discord:\n  token: inline-token\n  token_file: "+path+"\n")
+			},
+			errContains: "cannot be set together with discord.token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnvVars(t)
+
+			cfg, err := config.Load(tt.setup(t))
+
+			if tt.errContains != "" {
+				require.Error(t, err)
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, "discord.token_file", configErr.Key)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, cfg.Discord.Token)
+		})
+	}
+}
+
 func Test_Load_DefaultValues(t *testing.T) {
 	clearEnvVars(t)
 
@@ -230,6 +357,1225 @@ discord:
 		"default logging level should be 'info'")
 	assert.Equal(t, 10*time.Second, cfg.Shutdown.Timeout,
 		"default shutdown timeout should be 10s")
+	assert.False(t, cfg.Commands.NotifyTargets,
+		"moderation targets should not be DMed by default")
+}
+
+func Test_Load_TokenOnlyGetsDefaults(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name string
+		load func(t *testing.T) (*config.Config, error)
+	}{
+		{
+			name: "config file",
+			load: func(t *testing.T) (*config.Config, error) {
+				return config.Load(createTempConfigFile(t, "discord:\n  token: test-token\n"))
+			},
+		},
+		{
+			name: "environment only",
+			load: func(t *testing.T) (*config.Config, error) {
+				t.Setenv("JAMESBOT_DISCORD_TOKEN", "test-token")
+				return config.Load("")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.load(t)
+			require.NoError(t, err)
+
+			want := config.Defaults()
+			want.Discord.Token = "test-token"
+			assert.Equal(t, want, cfg, "every option left unset should take its default")
+		})
+	}
+}
+
+func Test_Defaults(t *testing.T) {
+	defaults := config.Defaults()
+
+	assert.Equal(t, "info", defaults.Logging.Level)
+	assert.Equal(t, 10*time.Second, defaults.Shutdown.Timeout)
+	assert.True(t, defaults.Control.Enabled)
+	assert.Empty(t, defaults.Discord.Token, "the token has no default")
+	assert.NotSame(t, defaults, config.Defaults(), "callers may modify the defaults they get")
+}
+
+func Test_Load_Overrides(t *testing.T) {
+	clearEnvVars(t)
+	t.Setenv("JAMESBOT_LOGGING_LEVEL", "warn")
+	path := createTempConfigFile(t, "discord:\n  token: t\n  allowed_guilds: [\"111\"]\nlogging:\n  level: debug\n  format: json\n")
+
+	t.Run("applied over the file and environment in order", func(t *testing.T) {
+		cfg, err := config.Load(path,
+			func(cfg *config.Config) { cfg.Logging.Level = "error" },
+			func(cfg *config.Config) { cfg.Shutdown.Timeout = 2 * cfg.Shutdown.Timeout },
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, "error", cfg.Logging.Level, "overrides beat the environment")
+		assert.Equal(t, "json", cfg.Logging.Format, "options not overridden keep the file's value")
+		assert.Equal(t, 20*time.Second, cfg.Shutdown.Timeout, "overrides see the defaults")
+	})
+
+	t.Run("without overrides the environment wins", func(t *testing.T) {
+		cfg, err := config.Load(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, "warn", cfg.Logging.Level)
+	})
+
+	t.Run("validated with the rest of the config", func(t *testing.T) {
+		_, err := config.Load(path, func(cfg *config.Config) { cfg.Discord.GuildID = "222" })
+
+		var configErr *errutil.ConfigError
+		require.ErrorAs(t, err, &configErr)
+		assert.Equal(t, "discord.allowed_guilds", configErr.Key, "the guild is not one the bot may operate in")
+	})
+}
+
+func Test_Load_NotifyTargets(t *testing.T) {
+	clearEnvVars(t)
+
+	fromFile, err := config.Load(createTempConfigFile(t, "discord:\n  token: t\ncommands:\n  notify_targets: true\n"))
+	require.NoError(t, err)
+	assert.True(t, fromFile.Commands.NotifyTargets)
+
+	t.Setenv("JAMESBOT_COMMANDS_NOTIFY_TARGETS", "true")
+	fromEnv, err := config.Load(createTempConfigFile(t, "discord:\n  token: t\n"))
+	require.NoError(t, err)
+	assert.True(t, fromEnv.Commands.NotifyTargets)
+}
+
+func Test_Load_ConfirmDestructive(t *testing.T) {
+	clearEnvVars(t)
+
+	defaults, err := config.Load(createTempConfigFile(t, "discord:\n  token: t\n"))
+	require.NoError(t, err)
+	assert.True(t, defaults.Commands.ConfirmDestructive, "confirmations should be on by default")
+
+	fromFile, err := config.Load(createTempConfigFile(t, "discord:\n  token: t\ncommands:\n  confirm_destructive: false\n"))
+	require.NoError(t, err)
+	assert.False(t, fromFile.Commands.ConfirmDestructive)
+
+	t.Setenv("JAMESBOT_COMMANDS_CONFIRM_DESTRUCTIVE", "false")
+	fromEnv, err := config.Load(createTempConfigFile(t, "discord:\n  token: t\n"))
+	require.NoError(t, err)
+	assert.False(t, fromEnv.Commands.ConfirmDestructive)
+}
+
+func Test_Load_DiscordGlobal(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          bool
+	}{
+		{
+			name:          "guild registration by default",
+			configContent: "discord:\n  token: t\n  guild_id: \"123\"\n",
+			want:          false,
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\n  guild_id: \"123\"\n  global: true\n",
+			want:          true,
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_DISCORD_GLOBAL": "true"},
+			want:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Discord.Global)
+		})
+	}
+}
+
+func Test_Load_AllowedGuilds(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantAllowed   []string
+		wantLeave     bool
+		wantErrKey    string
+	}{
+		{
+			name:          "every guild by default",
+			configContent: "discord:\n  token: t\n",
+			wantLeave:     true,
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\n  guild_id: \"1\"\n  allowed_guilds: [\"1\", \"2\"]\n  leave_other_guilds: false\n",
+			wantAllowed:   []string{"1", "2"},
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env: map[string]string{
+				"JAMESBOT_DISCORD_ALLOWED_GUILDS":     "1,2",
+				"JAMESBOT_DISCORD_LEAVE_OTHER_GUILDS": "false",
+			},
+			wantAllowed: []string{"1", "2"},
+		},
+		{
+			name:          "registration guild must be allowed",
+			configContent: "discord:\n  token: t\n  guild_id: \"3\"\n  allowed_guilds: [\"1\", \"2\"]\n",
+			wantErrKey:    "discord.allowed_guilds",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, cfg.Discord.AllowedGuilds)
+			assert.Equal(t, tt.wantLeave, cfg.Discord.LeaveOtherGuilds)
+		})
+	}
+}
+
+func Test_Load_PrivilegedIntents(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          []string
+		wantErrKey    string
+	}{
+		{
+			name:          "none by default",
+			configContent: "discord:\n  token: t\n",
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\n  privileged_intents: [message_content]\n",
+			want:          []string{config.IntentMessageContent},
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_DISCORD_PRIVILEGED_INTENTS": "message_content,server_members"},
+			want:          []string{config.IntentMessageContent, config.IntentServerMembers},
+		},
+		{
+			name:          "unknown intent",
+			configContent: "discord:\n  token: t\n  privileged_intents: [presence]\n",
+			wantErrKey:    "discord.privileged_intents",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Discord.PrivilegedIntents)
+		})
+	}
+}
+
+func Test_Load_CommandPrefix(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantPrefix    string
+		wantErrKey    string
+	}{
+		{
+			name:          "disabled by default",
+			configContent: "discord:\n  token: t\n",
+			wantPrefix:    "",
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\ncommands:\n  prefix: \"!\"\n",
+			wantPrefix:    "!",
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_COMMANDS_PREFIX": "jb."},
+			wantPrefix:    "jb.",
+		},
+		{
+			name:          "whitespace rejected",
+			configContent: "discord:\n  token: t\ncommands:\n  prefix: \"! \"\n",
+			wantErrKey:    "commands.prefix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPrefix, cfg.Commands.Prefix)
+		})
+	}
+}
+
+func Test_Load_CommandAutoDefer(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          time.Duration
+		wantErrKey    string
+	}{
+		{
+			name:          "disabled by default",
+			configContent: "discord:\n  token: t\n",
+			want:          0,
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\ncommands:\n  auto_defer: 2.5s\n",
+			want:          2500 * time.Millisecond,
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_COMMANDS_AUTO_DEFER": "2s"},
+			want:          2 * time.Second,
+		},
+		{
+			name:          "negative rejected",
+			configContent: "discord:\n  token: t\ncommands:\n  auto_defer: -1s\n",
+			wantErrKey:    "commands.auto_defer",
+		},
+		{
+			name:          "past Discord's deadline rejected",
+			configContent: "discord:\n  token: t\ncommands:\n  auto_defer: 3s\n",
+			wantErrKey:    "commands.auto_defer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Commands.AutoDefer)
+		})
+	}
+}
+
+func Test_Load_CommandCooldown(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          time.Duration
+		wantRoles     []string
+		wantErrKey    string
+	}{
+		{
+			name:          "disabled by default",
+			configContent: "discord:\n  token: t\n",
+			want:          0,
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\ncommands:\n  cooldown: 5s\n  cooldown_bypass_roles: [\"1\", \"2\"]\n",
+			want:          5 * time.Second,
+			wantRoles:     []string{"1", "2"},
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env: map[string]string{
+				"JAMESBOT_COMMANDS_COOLDOWN":              "10s",
+				"JAMESBOT_COMMANDS_COOLDOWN_BYPASS_ROLES": "1,2",
+			},
+			want:      10 * time.Second,
+			wantRoles: []string{"1", "2"},
+		},
+		{
+			name:          "negative rejected",
+			configContent: "discord:\n  token: t\ncommands:\n  cooldown: -1s\n",
+			wantErrKey:    "commands.cooldown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Commands.Cooldown)
+			assert.Equal(t, tt.wantRoles, cfg.Commands.CooldownBypassRoles)
+		})
+	}
+}
+
+func Test_Load_Stats(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          config.StatsConfig
+		wantErrKey    string
+	}{
+		{
+			name:          "in memory by default",
+			configContent: "discord:\n  token: t\n",
+			want:          config.StatsConfig{FlushInterval: time.Minute},
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\nstats:\n  file: stats.json\n  flush_interval: 30s\n",
+			want:          config.StatsConfig{File: "stats.json", FlushInterval: 30 * time.Second},
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env: map[string]string{
+				"JAMESBOT_STATS_FILE":           "/var/lib/jamesbot/stats.json",
+				"JAMESBOT_STATS_FLUSH_INTERVAL": "5m",
+			},
+			want: config.StatsConfig{File: "/var/lib/jamesbot/stats.json", FlushInterval: 5 * time.Minute},
+		},
+		{
+			name:          "flush interval ignored without a file",
+			configContent: "discord:\n  token: t\nstats:\n  flush_interval: 0s\n",
+			want:          config.StatsConfig{},
+		},
+		{
+			name:          "zero flush interval rejected with a file",
+			configContent: "discord:\n  token: t\nstats:\n  file: stats.json\n  flush_interval: 0s\n",
+			wantErrKey:    "stats.flush_interval",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Stats)
+		})
+	}
+}
+
+func Test_Load_ShutdownSignalTimeouts(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantInterrupt time.Duration
+		wantTerminate time.Duration
+		wantErrKey    string
+	}{
+		{
+			name:          "unset by default",
+			configContent: "discord:\n  token: t\n",
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\nshutdown:\n  interrupt_timeout: 2s\n  terminate_timeout: 1m\n",
+			wantInterrupt: 2 * time.Second,
+			wantTerminate: time.Minute,
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env: map[string]string{
+				"JAMESBOT_SHUTDOWN_INTERRUPT_TIMEOUT": "1s",
+				"JAMESBOT_SHUTDOWN_TERMINATE_TIMEOUT": "30s",
+			},
+			wantInterrupt: time.Second,
+			wantTerminate: 30 * time.Second,
+		},
+		{
+			name:          "negative interrupt timeout rejected",
+			configContent: "discord:\n  token: t\nshutdown:\n  interrupt_timeout: -1s\n",
+			wantErrKey:    "shutdown.interrupt_timeout",
+		},
+		{
+			name:          "negative terminate timeout rejected",
+			configContent: "discord:\n  token: t\nshutdown:\n  terminate_timeout: -1s\n",
+			wantErrKey:    "shutdown.terminate_timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantInterrupt, cfg.Shutdown.InterruptTimeout)
+			assert.Equal(t, tt.wantTerminate, cfg.Shutdown.TerminateTimeout)
+		})
+	}
+}
+
+func Test_Load_CommandDeniedMessage(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          string
+	}{
+		{
+			name:          "localized default",
+			configContent: "discord:\n  token: t\n",
+			want:          "",
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\ncommands:\n  denied_message: \"Moderators only.\"\n",
+			want:          "Moderators only.",
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_COMMANDS_DENIED_MESSAGE": "Nope."},
+			want:          "Nope.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Commands.DeniedMessage)
+		})
+	}
+}
+
+func Test_Load_SampleSuccesses(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		envValue      string
+		want          int
+		wantErr       bool
+	}{
+		{name: "default logs every success", configContent: "discord:\n  token: t\n", want: 1},
+		{name: "from file", configContent: "discord:\n  token: t\nlogging:\n  sample_successes: 10\n", want: 10},
+		{name: "from environment", configContent: "discord:\n  token: t\n", envValue: "50", want: 50},
+		{name: "negative", configContent: "discord:\n  token: t\nlogging:\n  sample_successes: -1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envValue != "" {
+				t.Setenv("JAMESBOT_LOGGING_SAMPLE_SUCCESSES", tt.envValue)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErr {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, "logging.sample_successes", configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Logging.SampleSuccesses)
+		})
+	}
+}
+
+func Test_Load_Audit(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantAudit     bool
+		wantFile      string
+		wantRedact    []string
+	}{
+		{
+			name:          "disabled by default",
+			configContent: "discord:\n  token: t\n",
+		},
+		{
+			name: "from file",
+			configContent: "discord:\n  token: t\nlogging:\n  audit: true\n  audit_file: /var/log/jamesbot/audit.log\n" +
+				"  audit_redact: [reason, message]\n",
+			wantAudit:  true,
+			wantFile:   "/var/log/jamesbot/audit.log",
+			wantRedact: []string{"reason", "message"},
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env: map[string]string{
+				"JAMESBOT_LOGGING_AUDIT":        "true",
+				"JAMESBOT_LOGGING_AUDIT_FILE":   "audit.log",
+				"JAMESBOT_LOGGING_AUDIT_REDACT": "reason,message",
+			},
+			wantAudit:  true,
+			wantFile:   "audit.log",
+			wantRedact: []string{"reason", "message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAudit, cfg.Logging.Audit)
+			assert.Equal(t, tt.wantFile, cfg.Logging.AuditFile)
+			assert.Equal(t, tt.wantRedact, cfg.Logging.AuditRedact)
+		})
+	}
+}
+
+func Test_Load_LoggingOptions(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantOptions   bool
+		wantRedact    []string
+	}{
+		{
+			name:          "disabled by default",
+			configContent: "discord:\n  token: t\n",
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\nlogging:\n  options: true\n  options_redact: [reason]\n",
+			wantOptions:   true,
+			wantRedact:    []string{"reason"},
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env: map[string]string{
+				"JAMESBOT_LOGGING_OPTIONS":        "true",
+				"JAMESBOT_LOGGING_OPTIONS_REDACT": "reason,message",
+			},
+			wantOptions: true,
+			wantRedact:  []string{"reason", "message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOptions, cfg.Logging.Options)
+			assert.Equal(t, tt.wantRedact, cfg.Logging.OptionsRedact)
+		})
+	}
+}
+
+func Test_Load_CrashFile(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          string
+	}{
+		{
+			name:          "empty by default",
+			configContent: "discord:\n  token: t\n",
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\nlogging:\n  crash_file: /var/log/jamesbot/crash.log\n",
+			want:          "/var/log/jamesbot/crash.log",
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_LOGGING_CRASH_FILE": "crash.log"},
+			want:          "crash.log",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Logging.CrashFile)
+		})
+	}
+}
+
+func Test_Load_RulesFile(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          string
+	}{
+		{
+			name:          "empty by default",
+			configContent: "discord:\n  token: t\n",
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\nrules:\n  file: /var/lib/jamesbot/rules.json\n",
+			want:          "/var/lib/jamesbot/rules.json",
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_RULES_FILE": "rules.json"},
+			want:          "rules.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Rules.File)
+		})
+	}
+}
+
+func Test_Load_Interactions(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantWorkers   int
+		wantQueueSize int
+		wantErrKey    string
+	}{
+		{
+			name:          "defaults",
+			configContent: "discord:\n  token: t\n",
+			wantWorkers:   16,
+			wantQueueSize: 100,
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\ninteractions:\n  workers: 4\n  queue_size: 0\n",
+			wantWorkers:   4,
+			wantQueueSize: 0,
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_INTERACTIONS_WORKERS": "8", "JAMESBOT_INTERACTIONS_QUEUE_SIZE": "25"},
+			wantWorkers:   8,
+			wantQueueSize: 25,
+		},
+		{
+			name:          "no workers",
+			configContent: "discord:\n  token: t\ninteractions:\n  workers: 0\n",
+			wantErrKey:    "interactions.workers",
+		},
+		{
+			name:          "negative queue",
+			configContent: "discord:\n  token: t\ninteractions:\n  queue_size: -1\n",
+			wantErrKey:    "interactions.queue_size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWorkers, cfg.Interactions.Workers)
+			assert.Equal(t, tt.wantQueueSize, cfg.Interactions.QueueSize)
+		})
+	}
+}
+
+func Test_Load_Cache(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantTTL       time.Duration
+		wantSize      int
+		wantErrKey    string
+	}{
+		{
+			name:          "defaults",
+			configContent: "discord:\n  token: t\n",
+			wantTTL:       30 * time.Second,
+			wantSize:      1000,
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\ncache:\n  member_ttl: 5s\n  member_size: 50\n",
+			wantTTL:       5 * time.Second,
+			wantSize:      50,
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_CACHE_MEMBER_TTL": "1m", "JAMESBOT_CACHE_MEMBER_SIZE": "10"},
+			wantTTL:       time.Minute,
+			wantSize:      10,
+		},
+		{
+			name:          "disabled cache needs no size",
+			configContent: "discord:\n  token: t\ncache:\n  member_ttl: 0s\n  member_size: 0\n",
+			wantTTL:       0,
+			wantSize:      0,
+		},
+		{
+			name:          "negative ttl",
+			configContent: "discord:\n  token: t\ncache:\n  member_ttl: -1s\n",
+			wantErrKey:    "cache.member_ttl",
+		},
+		{
+			name:          "enabled cache without room",
+			configContent: "discord:\n  token: t\ncache:\n  member_size: 0\n",
+			wantErrKey:    "cache.member_size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTTL, cfg.Cache.MemberTTL)
+			assert.Equal(t, tt.wantSize, cfg.Cache.MemberSize)
+		})
+	}
+}
+
+func Test_Load_Control(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		wantEnabled   bool
+		wantCert      string
+		wantKey       string
+		wantAuthToken string
+		wantErrKey    string
+	}{
+		{
+			name:          "enabled by default",
+			configContent: "discord:\n  token: t\n",
+			wantEnabled:   true,
+		},
+		{
+			name:          "disabled in file",
+			configContent: "discord:\n  token: t\ncontrol:\n  enabled: false\n",
+			wantEnabled:   false,
+		},
+		{
+			name:          "disabled from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_CONTROL_ENABLED": "false"},
+			wantEnabled:   false,
+		},
+		{
+			name:          "tls from file",
+			configContent: "discord:\n  token: t\ncontrol:\n  tls_cert_file: cert.pem\n  tls_key_file: key.pem\n",
+			wantEnabled:   true,
+			wantCert:      "cert.pem",
+			wantKey:       "key.pem",
+		},
+		{
+			name:          "tls from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_CONTROL_TLS_CERT_FILE": "/tls/cert.pem", "JAMESBOT_CONTROL_TLS_KEY_FILE": "/tls/key.pem"},
+			wantEnabled:   true,
+			wantCert:      "/tls/cert.pem",
+			wantKey:       "/tls/key.pem",
+		},
+		{
+			name:          "auth token from file",
+			configContent: "discord:\n  token: t\ncontrol:\n  auth_token: s3cret\n",
+			wantEnabled:   true,
+			wantAuthToken: "s3cret",
+		},
+		{
+			name:          "auth token from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_CONTROL_AUTH_TOKEN": "from-env"},
+			wantEnabled:   true,
+			wantAuthToken: "from-env",
+		},
+		{
+			name:          "certificate without key",
+			configContent: "discord:\n  token: t\ncontrol:\n  tls_cert_file: cert.pem\n",
+			wantErrKey:    "control.tls_key_file",
+		},
+		{
+			name:          "key without certificate",
+			configContent: "discord:\n  token: t\ncontrol:\n  tls_key_file: key.pem\n",
+			wantErrKey:    "control.tls_cert_file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, cfg.Control.Enabled)
+			assert.Equal(t, tt.wantCert, cfg.Control.TLSCertFile)
+			assert.Equal(t, tt.wantKey, cfg.Control.TLSKeyFile)
+			assert.Equal(t, tt.wantAuthToken, cfg.Control.AuthToken)
+		})
+	}
+}
+
+func Test_Load_Alerts(t *testing.T) {
+	clearEnvVars(t)
+
+	defaults := config.AlertsConfig{
+		ErrorThreshold: 0.5,
+		Window:         5 * time.Minute,
+		MinCommands:    10,
+		Cooldown:       10 * time.Minute,
+		MaxCooldown:    6 * time.Hour,
+		DenialWindow:   10 * time.Minute,
+	}
+	withChannel := defaults
+	withChannel.ChannelID = "123"
+	tuned := config.AlertsConfig{
+		ChannelID:       "123",
+		ErrorThreshold:  0.25,
+		Window:          time.Minute,
+		MinCommands:     3,
+		Cooldown:        time.Minute,
+		MaxCooldown:     time.Hour,
+		DenialThreshold: 3,
+		DenialWindow:    time.Minute,
+	}
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          config.AlertsConfig
+		wantErrKey    string
+	}{
+		{
+			name:          "disabled by default",
+			configContent: "discord:\n  token: t\n",
+			want:          defaults,
+		},
+		{
+			name:          "channel from environment",
+			configContent: "discord:\n  token: t\n",
+			env:           map[string]string{"JAMESBOT_ALERTS_CHANNEL_ID": "123"},
+			want:          withChannel,
+		},
+		{
+			name: "tuned in file",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  error_threshold: 0.25\n" +
+				"  window: 1m\n  min_commands: 3\n  cooldown: 1m\n  max_cooldown: 1h\n" +
+				"  denial_threshold: 3\n  denial_window: 1m\n",
+			want: tuned,
+		},
+		{
+			name:          "invalid settings ignored while disabled",
+			configContent: "discord:\n  token: t\nalerts:\n  error_threshold: 2\n",
+			want:          config.AlertsConfig{ErrorThreshold: 2, Window: 5 * time.Minute, MinCommands: 10, Cooldown: 10 * time.Minute, MaxCooldown: 6 * time.Hour, DenialWindow: 10 * time.Minute},
+		},
+		{
+			name:          "threshold above 1",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  error_threshold: 1.5\n",
+			wantErrKey:    "alerts.error_threshold",
+		},
+		{
+			name:          "zero threshold",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  error_threshold: 0\n",
+			wantErrKey:    "alerts.error_threshold",
+		},
+		{
+			name:          "zero window",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  window: 0s\n",
+			wantErrKey:    "alerts.window",
+		},
+		{
+			name:          "zero min commands",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  min_commands: 0\n",
+			wantErrKey:    "alerts.min_commands",
+		},
+		{
+			name:          "zero cooldown",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  cooldown: 0s\n",
+			wantErrKey:    "alerts.cooldown",
+		},
+		{
+			name:          "max cooldown below cooldown",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  cooldown: 1h\n  max_cooldown: 1m\n",
+			wantErrKey:    "alerts.max_cooldown",
+		},
+		{
+			name:          "negative denial threshold",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  denial_threshold: -1\n",
+			wantErrKey:    "alerts.denial_threshold",
+		},
+		{
+			name:          "zero denial window",
+			configContent: "discord:\n  token: t\nalerts:\n  channel_id: \"123\"\n  denial_threshold: 3\n  denial_window: 0s\n",
+			wantErrKey:    "alerts.denial_window",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Alerts)
+		})
+	}
+}
+
+func Test_Load_Snipe(t *testing.T) {
+	clearEnvVars(t)
+
+	tests := []struct {
+		name          string
+		configContent string
+		env           map[string]string
+		want          config.SnipeConfig
+		wantErrKey    string
+	}{
+		{
+			name:          "five minutes by default",
+			configContent: "discord:\n  token: t\n",
+			want:          config.SnipeConfig{Retention: 5 * time.Minute},
+		},
+		{
+			name:          "from file",
+			configContent: "discord:\n  token: t\nsnipe:\n  retention: 30s\n  excluded_channels: [\"1\", \"2\"]\n",
+			want:          config.SnipeConfig{Retention: 30 * time.Second, ExcludedChannels: []string{"1", "2"}},
+		},
+		{
+			name:          "from environment",
+			configContent: "discord:\n  token: t\n",
+			env: map[string]string{
+				"JAMESBOT_SNIPE_RETENTION":         "1m",
+				"JAMESBOT_SNIPE_EXCLUDED_CHANNELS": "1,2",
+			},
+			want: config.SnipeConfig{Retention: time.Minute, ExcludedChannels: []string{"1", "2"}},
+		},
+		{
+			name:          "zero disables",
+			configContent: "discord:\n  token: t\nsnipe:\n  retention: 0s\n",
+			want:          config.SnipeConfig{},
+		},
+		{
+			name:          "negative",
+			configContent: "discord:\n  token: t\nsnipe:\n  retention: -1m\n",
+			wantErrKey:    "snipe.retention",
+		},
+		{
+			name:          "over an hour",
+			configContent: "discord:\n  token: t\nsnipe:\n  retention: 2h\n",
+			wantErrKey:    "snipe.retention",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(createTempConfigFile(t, tt.configContent))
+
+			if tt.wantErrKey != "" {
+				var configErr *errutil.ConfigError
+				require.ErrorAs(t, err, &configErr)
+				assert.Equal(t, tt.wantErrKey, configErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Snipe)
+		})
+	}
 }
 
 func Test_Load_InvalidYAML(t *testing.T) {
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// secretTag marks a config field whose value must be redacted before display.
+// Tag every secret-bearing field with secret:"true".
+const secretTag = "secret"
+
+// Redact masks a secret for display. Long values keep their first and last
+// four characters (e.g. "test-token-12345" becomes "test****2345"); shorter
+// values are masked entirely so little of the secret is revealed. Empty values
+// stay empty so an unset secret is still visible as unset.
+func Redact(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case len(s) < 16:
+		return "****"
+	default:
+		return s[:4] + "****" + s[len(s)-4:]
+	}
+}
+
+// Redacted returns a copy of c with every field tagged secret:"true" masked by Redact.
+func (c *Config) Redacted() *Config {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	redactValue(reflect.ValueOf(&out).Elem())
+	return &out
+}
+
+func redactValue(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch {
+		case field.Kind() == reflect.Struct:
+			redactValue(field)
+		case t.Field(i).Tag.Get(secretTag) == "true" && field.Kind() == reflect.String:
+			field.SetString(Redact(field.String()))
+		}
+	}
+}
+
+// Setting is a single flattened configuration value.
+type Setting struct {
+	// Key is the dotted config key, e.g. "discord.token".
+	Key string
+
+	// Value is the value formatted for display.
+	Value string
+}
+
+// Settings flattens c into dotted keys in declaration order, using the same
+// key names as the YAML file. Values are not redacted; call Redacted first
+// when the output may be shown to a user.
+func (c *Config) Settings() []Setting {
+	if c == nil {
+		return nil
+	}
+	var settings []Setting
+	collectSettings(reflect.ValueOf(*c), "", &settings)
+	return settings
+}
+
+func collectSettings(v reflect.Value, prefix string, settings *[]Setting) {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			key = strings.ToLower(t.Field(i).Name)
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch field.Kind() {
+		case reflect.Struct:
+			collectSettings(field, key, settings)
+		case reflect.Slice:
+			items := make([]string, field.Len())
+			for j := range items {
+				items[j] = fmt.Sprint(field.Index(j).Interface())
+			}
+			*settings = append(*settings, Setting{Key: key, Value: "[" + strings.Join(items, ", ") + "]"})
+		default:
+			*settings = append(*settings, Setting{Key: key, Value: fmt.Sprint(field.Interface())})
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package config_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"jamesbot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Redact(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty stays empty", input: "", want: ""},
+		{name: "short value fully masked", input: "abc", want: "****"},
+		{name: "just under threshold fully masked", input: "123456789012345", want: "****"},
+		{name: "long value keeps ends", input: "test-token-12345", want: "test****2345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, config.Redact(tt.input))
+		})
+	}
+}
+
+func Test_Config_Redacted(t *testing.T) {
+	cfg := &config.Config{
+		Discord: config.DiscordConfig{Token: "test-token-12345", GuildID: "guild-1"},
+	}
+
+	redacted := cfg.Redacted()
+
+	assert.Equal(t, "test****2345", redacted.Discord.Token)
+	assert.Equal(t, "guild-1", redacted.Discord.GuildID, "non-secret fields are unchanged")
+	assert.Equal(t, "test-token-12345", cfg.Discord.Token, "original must not be modified")
+	assert.Nil(t, (*config.Config)(nil).Redacted())
+}
+
+// Test_Config_SecretFieldsTagged guards against adding a secret-bearing field
+// without tagging it, which would leak it through config show. Fields ending
+// in File hold the path of a file, such as one holding a secret, which is not
+// itself secret.
+func Test_Config_SecretFieldsTagged(t *testing.T) {
+	secretWords := []string{"token", "secret", "password", "key"}
+
+	var check func(typ reflect.Type, path string)
+	check = func(typ reflect.Type, path string) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			name := path + field.Name
+			if field.Type.Kind() == reflect.Struct {
+				check(field.Type, name+".")
+				continue
+			}
+			if strings.HasSuffix(field.Name, "File") {
+				assert.Empty(t, field.Tag.Get("secret"), "%s is a path, not a secret", name)
+				continue
+			}
+			lower := strings.ToLower(field.Name)
+			for _, word := range secretWords {
+				if strings.Contains(lower, word) {
+					assert.Equal(t, "true", field.Tag.Get("secret"),
+						"%s looks secret-bearing and must be tagged secret:\"true\"", name)
+				}
+			}
+		}
+	}
+
+	check(reflect.TypeOf(config.Config{}), "")
+}
+
+func Test_Config_Settings(t *testing.T) {
+	cfg := &config.Config{
+		Discord:  config.DiscordConfig{Token: "tok", GuildID: "42"},
+		Commands: config.CommandsConfig{Disabled: []string{"ban", "kick"}},
+	}
+
+	settings := cfg.Settings()
+	require.NotEmpty(t, settings)
+
+	values := make(map[string]string, len(settings))
+	for _, s := range settings {
+		values[s.Key] = s.Value
+	}
+
+	assert.Equal(t, "discord.token", settings[0].Key, "settings follow declaration order")
+	assert.Equal(t, "tok", values["discord.token"])
+	assert.Equal(t, "42", values["discord.guild_id"])
+	assert.Equal(t, "[ban, kick]", values["commands.disabled"])
+	assert.Contains(t, values, "shutdown.timeout")
+	assert.Nil(t, (*config.Config)(nil).Settings())
+}
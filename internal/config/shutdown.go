@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// TimeoutFor returns how long to wait for a graceful shutdown triggered by
+// sig: InterruptTimeout for SIGINT and TerminateTimeout for SIGTERM when they
+// are set, and Timeout otherwise.
+func (c ShutdownConfig) TimeoutFor(sig os.Signal) time.Duration {
+	switch {
+	case sig == os.Interrupt && c.InterruptTimeout > 0:
+		return c.InterruptTimeout
+	case sig == syscall.SIGTERM && c.TerminateTimeout > 0:
+		return c.TerminateTimeout
+	}
+	return c.Timeout
+}
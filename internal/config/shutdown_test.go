@@ -0,0 +1,58 @@
+package config_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"jamesbot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ShutdownConfig_TimeoutFor(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.ShutdownConfig
+		sig  os.Signal
+		want time.Duration
+	}{
+		{
+			name: "interrupt without override uses timeout",
+			cfg:  config.ShutdownConfig{Timeout: 10 * time.Second},
+			sig:  os.Interrupt,
+			want: 10 * time.Second,
+		},
+		{
+			name: "terminate without override uses timeout",
+			cfg:  config.ShutdownConfig{Timeout: 10 * time.Second},
+			sig:  syscall.SIGTERM,
+			want: 10 * time.Second,
+		},
+		{
+			name: "interrupt override",
+			cfg:  config.ShutdownConfig{Timeout: 10 * time.Second, InterruptTimeout: 2 * time.Second, TerminateTimeout: time.Minute},
+			sig:  os.Interrupt,
+			want: 2 * time.Second,
+		},
+		{
+			name: "terminate override",
+			cfg:  config.ShutdownConfig{Timeout: 10 * time.Second, InterruptTimeout: 2 * time.Second, TerminateTimeout: time.Minute},
+			sig:  syscall.SIGTERM,
+			want: time.Minute,
+		},
+		{
+			name: "other signal uses timeout",
+			cfg:  config.ShutdownConfig{Timeout: 10 * time.Second, InterruptTimeout: 2 * time.Second, TerminateTimeout: time.Minute},
+			sig:  syscall.SIGHUP,
+			want: 10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cfg.TimeoutFor(tt.sig))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"jamesbot/pkg/errutil"
+)
+
+// ValidateToken reports whether token has the shape of a Discord bot token:
+// three dot-separated base64url segments, the first of which encodes the
+// bot's numeric user ID. It cannot tell whether Discord will accept the
+// token, only whether it was copied correctly.
+func ValidateToken(token string) error {
+	invalid := func(msg string) error {
+		return &errutil.ConfigError{Key: "discord.token", Message: msg}
+	}
+
+	if token == "" {
+		return invalid("token is required but not provided")
+	}
+	if strings.TrimSpace(token) != token {
+		return invalid("token has leading or trailing whitespace")
+	}
+	if strings.HasPrefix(token, "Bot ") {
+		return invalid(`token must not include the "Bot " prefix`)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return invalid("token should have three dot-separated parts")
+	}
+	for _, part := range parts {
+		if part == "" || strings.Trim(part, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_") != "" {
+			return invalid("token contains characters that are not valid base64url")
+		}
+	}
+
+	id, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[0], "="))
+	if err != nil || len(id) == 0 || strings.Trim(string(id), "0123456789") != "" {
+		return invalid("token does not start with an encoded bot user ID")
+	}
+
+	return nil
+}
+
+// readTokenFile sets cfg.Token to the contents of cfg.TokenFile, if set, less
+// any trailing whitespace. Setting both is an error rather than letting one
+// silently win, since it usually means an old token was left behind.
+func readTokenFile(cfg *DiscordConfig) error {
+	if cfg.TokenFile == "" {
+		return nil
+	}
+	if cfg.Token != "" {
+		return &errutil.ConfigError{
+			Key:     "discord.token_file",
+			Message: "cannot be set together with discord.token",
+		}
+	}
+
+	data, err := os.ReadFile(cfg.TokenFile)
+	if err != nil {
+		return &errutil.ConfigError{
+			Key:     "discord.token_file",
+			Message: fmt.Sprintf("failed to read token: %v", err),
+		}
+	}
+	cfg.Token = strings.TrimRightFunc(string(data), unicode.IsSpace)
+	if cfg.Token == "" {
+		return &errutil.ConfigError{
+			Key:     "discord.token_file",
+			Message: fmt.Sprintf("%s is empty", cfg.TokenFile),
+		}
+	}
+	return nil
+}
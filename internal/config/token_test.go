@@ -0,0 +1,53 @@
+package config_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"jamesbot/internal/config"
+	"jamesbot/pkg/errutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// ValidateToken Tests
+// =============================================================================
+
+func Test_ValidateToken(t *testing.T) {
+	id := base64.RawURLEncoding.EncodeToString([]byte("123456789012345678"))
+	valid := id + ".GhIjKl.abcdefghijklmnopqrstuvwxyz_-0123"
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "well-formed token", token: valid},
+		{name: "empty", token: "", wantErr: "required"},
+		{name: "surrounding whitespace", token: valid + "\n", wantErr: "whitespace"},
+		{name: "Bot prefix", token: "Bot " + valid, wantErr: `"Bot " prefix`},
+		{name: "two parts", token: id + ".GhIjKl", wantErr: "three dot-separated parts"},
+		{name: "empty part", token: id + "..abc", wantErr: "base64url"},
+		{name: "invalid characters", token: id + ".Gh+Kl.abc", wantErr: "base64url"},
+		{name: "first part not a user ID", token: base64.RawURLEncoding.EncodeToString([]byte("not-an-id")) + ".GhIjKl.abc", wantErr: "bot user ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.ValidateToken(tt.token)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			var configErr *errutil.ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, "discord.token", configErr.Key)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package control
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyTTL is how long the server remembers the response to a rule
+// update sent with an idempotency key, so a retry within it gets the original
+// response instead of applying the update again.
+const IdempotencyTTL = 10 * time.Minute
+
+// Limits on idempotency keys. Once maxIdempotencyKeys responses are
+// remembered, expired ones are dropped first; if none have expired, further
+// requests are applied without being remembered.
+const (
+	maxIdempotencyKeyLength = 128
+	maxIdempotencyKeys      = 10000
+)
+
+// idempotencyCache remembers the responses to requests sent with an
+// idempotency key. It is safe for concurrent use.
+type idempotencyCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*idempotentEntry
+}
+
+// idempotentEntry is a request seen with an idempotency key. Its response is
+// set, and done closed, once the request has been handled; the response stays
+// nil if the request failed with a server error and may be tried again.
+type idempotentEntry struct {
+	request  SetRuleRequest
+	done     chan struct{}
+	response *recordedResponse
+	expires  time.Time
+}
+
+// newIdempotencyCache creates a cache that remembers responses for ttl.
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:     ttl,
+		entries: make(map[string]*idempotentEntry),
+	}
+}
+
+// begin looks up req's idempotency key at now. If the key is new, it returns
+// a fresh entry and true, and the caller must handle req and call finish.
+// Otherwise it returns the existing entry, which may still be in progress,
+// and false. It returns nil and true when the cache is full, in which case
+// req is handled without being remembered.
+func (c *idempotencyCache) begin(now time.Time, req SetRuleRequest) (*idempotentEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := req.IdempotencyKey
+	if entry, ok := c.entries[key]; ok && (entry.expires.IsZero() || now.Before(entry.expires)) {
+		return entry, false
+	}
+
+	if len(c.entries) >= maxIdempotencyKeys {
+		c.sweep(now)
+		if len(c.entries) >= maxIdempotencyKeys {
+			return nil, true
+		}
+	}
+
+	entry := &idempotentEntry{request: req, done: make(chan struct{})}
+	c.entries[key] = entry
+	return entry, true
+}
+
+// finish records the response to entry's request at now and releases any
+// duplicates waiting on it. Server errors are not remembered, so a retry
+// applies the request again.
+func (c *idempotencyCache) finish(now time.Time, entry *idempotentEntry, response *recordedResponse) {
+	if entry == nil {
+		return
+	}
+
+	c.mu.Lock()
+	if response.status >= http.StatusInternalServerError {
+		delete(c.entries, entry.request.IdempotencyKey)
+	} else {
+		entry.response = response
+		entry.expires = now.Add(c.ttl)
+	}
+	c.mu.Unlock()
+
+	close(entry.done)
+}
+
+// sweep drops entries that expired by now. Callers must hold c.mu.
+func (c *idempotencyCache) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// recordedResponse is an http.ResponseWriter that keeps the response written
+// to it, so it can be sent again.
+type recordedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// newRecordedResponse creates an empty recordedResponse.
+func newRecordedResponse() *recordedResponse {
+	return &recordedResponse{header: make(http.Header)}
+}
+
+// Header implements http.ResponseWriter.
+func (r *recordedResponse) Header() http.Header {
+	return r.header
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (r *recordedResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// Write implements http.ResponseWriter.
+func (r *recordedResponse) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(b)
+}
+
+// writeTo sends the recorded response to w.
+func (r *recordedResponse) writeTo(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = values
+	}
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(r.body.Bytes())
+}
@@ -0,0 +1,121 @@
+package control
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricsContentType is the Content-Type of the Prometheus text exposition format.
+const MetricsContentType = "text/plain; version=0.0.4"
+
+// labelEscaper escapes Prometheus label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// handleMetrics handles GET /metrics requests in the Prometheus text format.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats := s.bot.Stats()
+	if stats == nil {
+		s.logger.Error().Msg("bot returned nil stats")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var counts map[string]int64
+	if counter, ok := s.bot.(CommandCounter); ok {
+		counts = counter.CommandCounts()
+	}
+
+	w.Header().Set("Content-Type", MetricsContentType)
+	writeMetrics(w, stats, counts, runtime.NumGoroutine())
+}
+
+// writeMetrics writes stats, per-command counts, and the goroutine count in the
+// Prometheus text format. Commands are listed in name order.
+func writeMetrics(w io.Writer, stats *Stats, counts map[string]int64, goroutines int) {
+	var uptime float64
+	if stats.StartTime > 0 {
+		uptime = time.Since(time.Unix(stats.StartTime, 0)).Seconds()
+	}
+
+	writeMetric(w, "jamesbot_uptime_seconds", "gauge", "Seconds since the bot started.", uptime)
+	writeMetric(w, "jamesbot_start_time_seconds", "gauge", "Unix time the bot started.", float64(stats.StartTime))
+	writeMetric(w, "jamesbot_commands_executed_total", "counter", "Commands executed successfully.", float64(stats.CommandsExecuted))
+	writeMetric(w, "jamesbot_guilds", "gauge", "Guilds the bot is in.", float64(stats.GuildCount))
+	writeMetric(w, "jamesbot_active_rules", "gauge", "Moderation rules currently enabled.", float64(stats.ActiveRules))
+	writeMetric(w, "jamesbot_goroutines", "gauge", "Goroutines currently running.", float64(goroutines))
+
+	if pool := stats.Interactions; pool != nil {
+		writeMetric(w, "jamesbot_interaction_workers", "gauge", "Interaction worker goroutines.", float64(pool.Workers))
+		writeMetric(w, "jamesbot_interaction_workers_active", "gauge", "Interaction workers running a command.", float64(pool.ActiveWorkers))
+		writeMetric(w, "jamesbot_interaction_queue_depth", "gauge", "Interactions waiting for a worker.", float64(pool.QueueDepth))
+		writeMetric(w, "jamesbot_interaction_queue_capacity", "gauge", "Interactions that may wait for a worker.", float64(pool.QueueCapacity))
+	}
+
+	writeLatencies(w, stats.Commands)
+
+	if counts == nil {
+		return
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "# HELP jamesbot_command_executions_total Successful executions per command.")
+	fmt.Fprintln(w, "# TYPE jamesbot_command_executions_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "jamesbot_command_executions_total{command=\"%s\"} %d\n", labelEscaper.Replace(name), counts[name])
+	}
+}
+
+// latencyQuantiles are the labels and values of the percentiles in LatencyPercentiles.
+var latencyQuantiles = []struct {
+	label string
+	value func(*LatencyPercentiles) float64
+}{
+	{"0.5", func(p *LatencyPercentiles) float64 { return p.P50 }},
+	{"0.95", func(p *LatencyPercentiles) float64 { return p.P95 }},
+	{"0.99", func(p *LatencyPercentiles) float64 { return p.P99 }},
+}
+
+// writeLatencies writes the execution time percentiles of each timed command,
+// in seconds. Nothing is written when no command has been timed.
+func writeLatencies(w io.Writer, commands []CommandStats) {
+	header := false
+	for _, cmd := range commands {
+		if cmd.Latency == nil {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(w, "# HELP jamesbot_command_latency_seconds Estimated command execution time percentiles.")
+			fmt.Fprintln(w, "# TYPE jamesbot_command_latency_seconds gauge")
+			header = true
+		}
+		name := labelEscaper.Replace(cmd.Name)
+		for _, q := range latencyQuantiles {
+			seconds := q.value(cmd.Latency) / 1000
+			fmt.Fprintf(w, "jamesbot_command_latency_seconds{command=\"%s\",quantile=\"%s\"} %s\n",
+				name, q.label, strconv.FormatFloat(seconds, 'f', -1, 64))
+		}
+	}
+}
+
+// writeMetric writes a single unlabelled sample with its HELP and TYPE lines.
+func writeMetric(w io.Writer, name, kind, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(w, "%s %s\n", name, strconv.FormatFloat(value, 'f', -1, 64))
+}
@@ -0,0 +1,316 @@
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// authenticate wraps an endpoint that acts on Discord or changes how the bot
+// behaves, such as a moderation endpoint, so that it only runs for requests
+// carrying the server's auth token as a bearer token. Requests without the
+// right token get 401 Unauthorized; if the server has no token, every
+// request gets 403 Forbidden.
+func (s *Server) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.authToken == "" {
+			http.Error(w, "Forbidden: this endpoint requires control.auth_token to be set", http.StatusForbidden)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) != 1 {
+			s.logger.Warn().
+				Str("path", r.URL.Path).
+				Str("remote_addr", r.RemoteAddr).
+				Msg("rejected request without a valid auth token")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="jamesbot"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// moderator returns the bot's Moderator, or writes a 501 response and
+// returns false if the bot cannot take moderation actions.
+func (s *Server) moderator(w http.ResponseWriter) (Moderator, bool) {
+	moderator, ok := s.bot.(Moderator)
+	if !ok {
+		http.Error(w, "Not implemented: moderation actions are not available", http.StatusNotImplemented)
+	}
+	return moderator, ok
+}
+
+// isSnowflake reports whether id looks like a Discord ID.
+func isSnowflake(id string) bool {
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
+}
+
+// decodeModerationRequest reads a moderation request and checks its guild
+// and user IDs, writing an error response and returning false if it cannot
+// be used.
+func (s *Server) decodeModerationRequest(w http.ResponseWriter, r *http.Request) (ModerationRequest, bool) {
+	var req ModerationRequest
+	if !s.decodeBody(w, r, &req) {
+		return req, false
+	}
+
+	req.GuildID = strings.TrimSpace(req.GuildID)
+	req.UserID = strings.TrimSpace(req.UserID)
+	if !isSnowflake(req.GuildID) || !isSnowflake(req.UserID) {
+		http.Error(w, "Bad request: guild_id and user_id must be Discord IDs", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+// writeModerationResult writes the response to a moderation action that
+// returned err, logging it either way.
+func (s *Server) writeModerationResult(w http.ResponseWriter, action string, req ModerationRequest, err error) {
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("action", action).
+			Str("guild_id", req.GuildID).
+			Str("user_id", req.UserID).
+			Msg("moderation action failed")
+		if errors.Is(err, ErrMemberNotFound) {
+			http.Error(w, fmt.Sprintf("Not found: %v", err), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to %s user: %v", action, err), http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Info().
+		Str("action", action).
+		Str("guild_id", req.GuildID).
+		Str("user_id", req.UserID).
+		Str("reason", req.Reason).
+		Msg("moderation action taken via control API")
+
+	w.Header().Set("Content-Type", "application/json")
+	response := ModerationResponse{Action: action, GuildID: req.GuildID, UserID: req.UserID}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+// handleBan handles POST /moderation/ban requests.
+func (s *Server) handleBan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	moderator, ok := s.moderator(w)
+	if !ok {
+		return
+	}
+	req, ok := s.decodeModerationRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.DeleteDays < 0 || req.DeleteDays > MaxBanDeleteDays {
+		http.Error(w, fmt.Sprintf("Bad request: delete_days must be between 0 and %d", MaxBanDeleteDays), http.StatusBadRequest)
+		return
+	}
+
+	err := moderator.Ban(req.GuildID, req.UserID, req.Reason, req.DeleteDays)
+	s.writeModerationResult(w, "ban", req, err)
+}
+
+// handleKick handles POST /moderation/kick requests.
+func (s *Server) handleKick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	moderator, ok := s.moderator(w)
+	if !ok {
+		return
+	}
+	req, ok := s.decodeModerationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := moderator.Kick(req.GuildID, req.UserID, req.Reason)
+	s.writeModerationResult(w, "kick", req, err)
+}
+
+// handleMute handles POST /moderation/mute requests, timing the member out
+// for the request's duration.
+func (s *Server) handleMute(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	moderator, ok := s.moderator(w)
+	if !ok {
+		return
+	}
+	req, ok := s.decodeModerationRequest(w, r)
+	if !ok {
+		return
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(req.Duration))
+	if err != nil || duration < MinMuteDuration || duration > MaxMuteDuration {
+		http.Error(w, fmt.Sprintf("Bad request: duration must be between %s and %s", MinMuteDuration, MaxMuteDuration),
+			http.StatusBadRequest)
+		return
+	}
+
+	err = moderator.Mute(req.GuildID, req.UserID, duration, req.Reason)
+	s.writeModerationResult(w, "mute", req, err)
+}
+
+// handleUnban handles POST /moderation/unban requests. Users are unbanned
+// one at a time and independently; the response reports which succeeded and
+// which failed.
+func (s *Server) handleUnban(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	moderator, ok := s.moderator(w)
+	if !ok {
+		return
+	}
+
+	var req UnbanRequest
+	if !s.decodeBody(w, r, &req) {
+		return
+	}
+
+	req.GuildID = strings.TrimSpace(req.GuildID)
+	if !isSnowflake(req.GuildID) {
+		http.Error(w, "Bad request: guild_id must be a Discord ID", http.StatusBadRequest)
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		http.Error(w, "Bad request: at least one user_id is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.UserIDs) > MaxUnbanUsers {
+		http.Error(w, fmt.Sprintf("Bad request: at most %d users can be unbanned per request", MaxUnbanUsers),
+			http.StatusBadRequest)
+		return
+	}
+
+	response := UnbanResponse{Results: make([]UnbanResult, 0, len(req.UserIDs))}
+	for _, userID := range req.UserIDs {
+		result := UnbanResult{UserID: strings.TrimSpace(userID)}
+
+		var err error
+		if !isSnowflake(result.UserID) {
+			err = errors.New("user_id must be a Discord ID")
+		} else {
+			err = moderator.Unban(req.GuildID, result.UserID, req.Reason)
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	s.logger.Info().
+		Str("guild_id", req.GuildID).
+		Int("succeeded", response.Succeeded).
+		Int("failed", response.Failed).
+		Msg("unbanned users")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+// handlePunishments handles GET /punishments requests, listing the temporary
+// punishments in effect, optionally only those in the guild given by the
+// guild query parameter.
+func (s *Server) handlePunishments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	lister, ok := s.bot.(PunishmentLister)
+	if !ok {
+		http.Error(w, "Not implemented: punishments are not available", http.StatusNotImplemented)
+		return
+	}
+
+	guildID := strings.TrimSpace(r.URL.Query().Get("guild"))
+	if guildID != "" && !isSnowflake(guildID) {
+		http.Error(w, "Bad request: guild must be a Discord ID", http.StatusBadRequest)
+		return
+	}
+
+	punishments := lister.Punishments(guildID)
+	if punishments == nil {
+		punishments = []Punishment{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(punishments); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode punishments")
+	}
+}
+
+// handleCancelPunishment handles POST /punishments/cancel requests, lifting a
+// temporary punishment before it expires.
+func (s *Server) handleCancelPunishment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	canceler, ok := s.bot.(PunishmentCanceler)
+	if !ok {
+		http.Error(w, "Not implemented: punishments cannot be cancelled", http.StatusNotImplemented)
+		return
+	}
+
+	var req CancelPunishmentRequest
+	if !s.decodeBody(w, r, &req) {
+		return
+	}
+	req.GuildID = strings.TrimSpace(req.GuildID)
+	req.UserID = strings.TrimSpace(req.UserID)
+	req.Type = strings.TrimSpace(req.Type)
+	if !isSnowflake(req.GuildID) || !isSnowflake(req.UserID) {
+		http.Error(w, "Bad request: guild_id and user_id must be Discord IDs", http.StatusBadRequest)
+		return
+	}
+	if req.Type != PunishmentMute {
+		http.Error(w, fmt.Sprintf("Bad request: type must be %q", PunishmentMute), http.StatusBadRequest)
+		return
+	}
+
+	err := canceler.CancelPunishment(req.GuildID, req.UserID, req.Type, req.Reason)
+	if errors.Is(err, ErrNoPunishment) {
+		http.Error(w, fmt.Sprintf("Conflict: %v", err), http.StatusConflict)
+		return
+	}
+	// Mutes are the only temporary punishment, so cancelling one unmutes
+	s.writeModerationResult(w, "unmute", ModerationRequest{
+		GuildID: req.GuildID,
+		UserID:  req.UserID,
+		Reason:  req.Reason,
+	}, err)
+}
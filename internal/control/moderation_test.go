@@ -0,0 +1,480 @@
+package control_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"jamesbot/internal/control"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuthToken is the auth token moderation tests configure the server with.
+const testAuthToken = "test-auth-token"
+
+// moderatorBotInfo is a mockBotInfo that can take moderation actions. Users
+// in banned can be unbanned and "404" is not a member; "500" fails as
+// Discord would; "3" is not muted; everyone else can be acted on but is not
+// banned.
+type moderatorBotInfo struct {
+	*mockBotInfo
+	banned  map[string]bool
+	actions []string
+	guild   string
+	reason  string
+}
+
+func (m *moderatorBotInfo) act(action, guildID, userID, reason string) error {
+	m.guild, m.reason = guildID, reason
+	switch userID {
+	case "404":
+		return fmt.Errorf("%w: %s", control.ErrMemberNotFound, userID)
+	case "500":
+		return errors.New("discord unavailable")
+	}
+	m.actions = append(m.actions, action+" "+userID)
+	return nil
+}
+
+func (m *moderatorBotInfo) Ban(guildID, userID, reason string, deleteDays int) error {
+	return m.act(fmt.Sprintf("ban(%d)", deleteDays), guildID, userID, reason)
+}
+
+func (m *moderatorBotInfo) Kick(guildID, userID, reason string) error {
+	return m.act("kick", guildID, userID, reason)
+}
+
+func (m *moderatorBotInfo) Mute(guildID, userID string, duration time.Duration, reason string) error {
+	return m.act(fmt.Sprintf("mute(%s)", duration), guildID, userID, reason)
+}
+
+func (m *moderatorBotInfo) Unban(guildID, userID, reason string) error {
+	if userID != "500" && !m.banned[userID] {
+		return control.ErrNotBanned
+	}
+	return m.act("unban", guildID, userID, reason)
+}
+
+func (m *moderatorBotInfo) CancelPunishment(guildID, userID, kind, reason string) error {
+	if userID == "3" {
+		return control.ErrNoPunishment
+	}
+	return m.act("cancel "+kind, guildID, userID, reason)
+}
+
+func newModeratorBotInfo() *moderatorBotInfo {
+	return &moderatorBotInfo{mockBotInfo: newMockBotInfo(), banned: map[string]bool{"1": true, "2": true}}
+}
+
+// moderationRequest builds an authenticated POST to a moderation endpoint.
+func moderationRequest(path, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAuthToken)
+	return req
+}
+
+// =============================================================================
+// Moderation Auth Tests
+// =============================================================================
+
+func Test_ModerationEndpoints_RequireAuth(t *testing.T) {
+	paths := []string{"/moderation/ban", "/moderation/kick", "/moderation/mute", "/moderation/unban", "/punishments/cancel"}
+
+	tests := []struct {
+		name          string
+		serverToken   string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "no token configured", serverToken: "", authorization: "Bearer anything", wantStatus: http.StatusForbidden},
+		{name: "missing header", serverToken: testAuthToken, wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", serverToken: testAuthToken, authorization: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "wrong scheme", serverToken: testAuthToken, authorization: "Basic " + testAuthToken, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		for _, path := range paths {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				bot := newModeratorBotInfo()
+				handler := control.NewServer(0, bot, discardLogger(), control.WithAuthToken(tt.serverToken)).Handler()
+
+				req := httptest.NewRequest(http.MethodPost, path,
+					strings.NewReader(`{"guild_id":"111","user_id":"1","user_ids":["1"],"duration":"1h"}`))
+				req.Header.Set("Content-Type", "application/json")
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+				rec := httptest.NewRecorder()
+
+				handler.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+				assert.Empty(t, bot.actions, "unauthenticated requests must not act")
+			})
+		}
+	}
+}
+
+// =============================================================================
+// POST /moderation/ban, /kick, /mute Endpoint Tests
+// =============================================================================
+
+func Test_ModerationEndpoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		wantStatus  int
+		wantAction  string
+		wantReason  string
+		wantActions []string
+	}{
+		{
+			name:        "ban",
+			path:        "/moderation/ban",
+			body:        `{"guild_id":" 111 ","user_id":"222","reason":"spam","delete_days":1}`,
+			wantStatus:  http.StatusOK,
+			wantAction:  "ban",
+			wantReason:  "spam",
+			wantActions: []string{"ban(1) 222"},
+		},
+		{
+			name:       "ban deletes too many days",
+			path:       "/moderation/ban",
+			body:       `{"guild_id":"111","user_id":"222","delete_days":8}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "kick",
+			path:        "/moderation/kick",
+			body:        `{"guild_id":"111","user_id":"222"}`,
+			wantStatus:  http.StatusOK,
+			wantAction:  "kick",
+			wantActions: []string{"kick 222"},
+		},
+		{
+			name:        "mute",
+			path:        "/moderation/mute",
+			body:        `{"guild_id":"111","user_id":"222","duration":"90m","reason":"cool off"}`,
+			wantStatus:  http.StatusOK,
+			wantAction:  "mute",
+			wantReason:  "cool off",
+			wantActions: []string{"mute(1h30m0s) 222"},
+		},
+		{
+			name:       "mute without duration",
+			path:       "/moderation/mute",
+			body:       `{"guild_id":"111","user_id":"222"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "mute too long",
+			path:       "/moderation/mute",
+			body:       `{"guild_id":"111","user_id":"222","duration":"700h"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid guild ID",
+			path:       "/moderation/kick",
+			body:       `{"guild_id":"guild","user_id":"222"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing user ID",
+			path:       "/moderation/ban",
+			body:       `{"guild_id":"111"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not a member",
+			path:       "/moderation/kick",
+			body:       `{"guild_id":"111","user_id":"404"}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "bot error",
+			path:       "/moderation/ban",
+			body:       `{"guild_id":"111","user_id":"500"}`,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newModeratorBotInfo()
+			handler := control.NewServer(0, bot, discardLogger(), control.WithAuthToken(testAuthToken)).Handler()
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, moderationRequest(tt.path, tt.body))
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantActions, bot.actions)
+			if tt.wantStatus == http.StatusOK {
+				var response control.ModerationResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, control.ModerationResponse{Action: tt.wantAction, GuildID: "111", UserID: "222"}, response)
+				assert.Equal(t, tt.wantReason, bot.reason)
+			}
+		})
+	}
+}
+
+func Test_ModerationEndpoints_MethodNotAllowed(t *testing.T) {
+	handler := control.NewServer(0, newModeratorBotInfo(), discardLogger(), control.WithAuthToken(testAuthToken)).Handler()
+
+	for _, path := range []string{"/moderation/ban", "/moderation/kick", "/moderation/mute", "/moderation/unban"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+testAuthToken)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code, path)
+	}
+}
+
+func Test_ModerationEndpoints_NotImplemented(t *testing.T) {
+	handler := control.NewServer(0, newMockBotInfo(), discardLogger(), control.WithAuthToken(testAuthToken)).Handler()
+
+	for _, path := range []string{"/moderation/ban", "/moderation/kick", "/moderation/mute", "/moderation/unban"} {
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, moderationRequest(path, `{"guild_id":"111","user_id":"1","user_ids":["1"],"duration":"1h"}`))
+
+		assert.Equal(t, http.StatusNotImplemented, rec.Code, path)
+	}
+}
+
+// =============================================================================
+// POST /moderation/unban Endpoint Tests
+// =============================================================================
+
+func Test_UnbanEndpoint(t *testing.T) {
+	tooMany := make([]string, control.MaxUnbanUsers+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", strconv.Itoa(i+1))
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		wantStatus   int
+		wantResponse *control.UnbanResponse
+		wantActions  []string
+		wantReason   string
+	}{
+		{
+			name:       "unbans each user",
+			body:       `{"guild_id":" 111 ","user_ids":["1"," 2 "],"reason":"amnesty"}`,
+			wantStatus: http.StatusOK,
+			wantResponse: &control.UnbanResponse{
+				Succeeded: 2,
+				Results:   []control.UnbanResult{{UserID: "1"}, {UserID: "2"}},
+			},
+			wantActions: []string{"unban 1", "unban 2"},
+			wantReason:  "amnesty",
+		},
+		{
+			name:       "reports failures per user",
+			body:       `{"guild_id":"111","user_ids":["1","3","500","","bob"]}`,
+			wantStatus: http.StatusOK,
+			wantResponse: &control.UnbanResponse{
+				Succeeded: 1,
+				Failed:    4,
+				Results: []control.UnbanResult{
+					{UserID: "1"},
+					{UserID: "3", Error: "user is not banned"},
+					{UserID: "500", Error: "discord unavailable"},
+					{UserID: "", Error: "user_id must be a Discord ID"},
+					{UserID: "bob", Error: "user_id must be a Discord ID"},
+				},
+			},
+			wantActions: []string{"unban 1"},
+		},
+		{
+			name:       "missing guild",
+			body:       `{"user_ids":["1"]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "no users",
+			body:       `{"guild_id":"111","user_ids":[]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too many users",
+			body:       `{"guild_id":"111","user_ids":[` + strings.Join(tooMany, ",") + `]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newModeratorBotInfo()
+			handler := control.NewServer(0, bot, discardLogger(), control.WithAuthToken(testAuthToken)).Handler()
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, moderationRequest("/moderation/unban", tt.body))
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantActions, bot.actions)
+			if tt.wantResponse != nil {
+				var response control.UnbanResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, *tt.wantResponse, response)
+				assert.Equal(t, "111", bot.guild)
+				assert.Equal(t, tt.wantReason, bot.reason)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// GET /punishments Endpoint Tests
+// =============================================================================
+
+// punishmentBotInfo is a mockBotInfo with a temporary punishment in each of
+// guilds 111 and 222.
+type punishmentBotInfo struct {
+	*mockBotInfo
+}
+
+func (p *punishmentBotInfo) Punishments(guildID string) []control.Punishment {
+	all := []control.Punishment{
+		{GuildID: "111", UserID: "1", Type: control.PunishmentMute, Reason: "spam", ExpiresAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{GuildID: "222", UserID: "2", Type: control.PunishmentMute, ExpiresAt: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)},
+	}
+	var punishments []control.Punishment
+	for _, p := range all {
+		if guildID == "" || p.GuildID == guildID {
+			punishments = append(punishments, p)
+		}
+	}
+	return punishments
+}
+
+func Test_PunishmentsEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		bot        control.BotInfo
+		method     string
+		query      string
+		wantStatus int
+		wantUsers  []string
+	}{
+		{name: "every guild", bot: &punishmentBotInfo{newMockBotInfo()}, wantStatus: http.StatusOK, wantUsers: []string{"1", "2"}},
+		{name: "one guild", bot: &punishmentBotInfo{newMockBotInfo()}, query: "?guild=222", wantStatus: http.StatusOK, wantUsers: []string{"2"}},
+		{name: "none in guild", bot: &punishmentBotInfo{newMockBotInfo()}, query: "?guild=333", wantStatus: http.StatusOK, wantUsers: []string{}},
+		{name: "invalid guild", bot: &punishmentBotInfo{newMockBotInfo()}, query: "?guild=abc", wantStatus: http.StatusBadRequest},
+		{name: "wrong method", bot: &punishmentBotInfo{newMockBotInfo()}, method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed},
+		{name: "not supported", bot: newMockBotInfo(), wantStatus: http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			handler := control.NewServer(0, tt.bot, discardLogger()).Handler()
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, httptest.NewRequest(method, "/punishments"+tt.query, nil))
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantUsers == nil {
+				return
+			}
+			var punishments []control.Punishment
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&punishments))
+			require.NotNil(t, punishments, "an empty list should be [], not null")
+			users := []string{}
+			for _, p := range punishments {
+				users = append(users, p.UserID)
+			}
+			assert.Equal(t, tt.wantUsers, users)
+		})
+	}
+}
+
+// =============================================================================
+// POST /punishments/cancel Endpoint Tests
+// =============================================================================
+
+func Test_CancelPunishmentEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantActions []string
+	}{
+		{
+			name:        "lifts mute",
+			body:        `{"guild_id":"111","user_id":"1","type":"mute","reason":"appeal accepted"}`,
+			wantStatus:  http.StatusOK,
+			wantActions: []string{"cancel mute 1"},
+		},
+		{
+			name:       "not muted",
+			body:       `{"guild_id":"111","user_id":"3","type":"mute"}`,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "not a member",
+			body:       `{"guild_id":"111","user_id":"404","type":"mute"}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "discord fails",
+			body:       `{"guild_id":"111","user_id":"500","type":"mute"}`,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "unknown type",
+			body:       `{"guild_id":"111","user_id":"1","type":"ban"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid user",
+			body:       `{"guild_id":"111","user_id":"abc","type":"mute"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newModeratorBotInfo()
+			handler := control.NewServer(0, bot, discardLogger(), control.WithAuthToken(testAuthToken)).Handler()
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, moderationRequest("/punishments/cancel", tt.body))
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantActions, bot.actions)
+			if tt.wantStatus == http.StatusOK {
+				var response control.ModerationResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, control.ModerationResponse{Action: "unmute", GuildID: "111", UserID: "1"}, response)
+				assert.Equal(t, "appeal accepted", bot.reason)
+			}
+		})
+	}
+}
+
+func Test_CancelPunishmentEndpoint_NotSupported(t *testing.T) {
+	handler := control.NewServer(0, newMockBotInfo(), discardLogger(), control.WithAuthToken(testAuthToken)).Handler()
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, moderationRequest("/punishments/cancel", `{"guild_id":"111","user_id":"1","type":"mute"}`))
+
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
@@ -0,0 +1,49 @@
+package control
+
+// DefaultMaxBodyBytes is the largest request body the server accepts unless
+// WithMaxBodyBytes says otherwise.
+const DefaultMaxBodyBytes = 1 << 20
+
+// ServerOption is a functional option for configuring a Server created by NewServer.
+type ServerOption func(*Server)
+
+// WithTLS makes Start serve HTTPS using the PEM-encoded certificate and key
+// in certFile and keyFile. Both are required; Start fails if only one is set.
+func WithTLS(certFile, keyFile string) ServerOption {
+	return func(s *Server) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithMaxBodyBytes sets the largest request body, in bytes, that POST
+// endpoints read. Larger bodies are rejected with 413 Request Entity Too
+// Large. A limit that is not positive keeps DefaultMaxBodyBytes.
+func WithMaxBodyBytes(n int64) ServerOption {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxBodyBytes = n
+		}
+	}
+}
+
+// WithStrictDecoding makes POST endpoints reject JSON bodies with fields they
+// do not know, such as a misspelled "naem", with 400 Bad Request. Without it,
+// unknown fields are ignored unless the request asks for strict mode with
+// the ?strict=true query parameter.
+func WithStrictDecoding() ServerOption {
+	return func(s *Server) {
+		s.strict = true
+	}
+}
+
+// WithAuthToken sets the bearer token that requests to the moderation,
+// warning, command, and maintenance endpoints must send in their
+// Authorization header. Without it, or with an empty token, those endpoints
+// refuse every request with 403 Forbidden, since anything that can reach the
+// port could otherwise act on members or change the bot's behaviour.
+func WithAuthToken(token string) ServerOption {
+	return func(s *Server) {
+		s.authToken = token
+	}
+}
@@ -2,11 +2,16 @@ package control
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"net"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -20,21 +25,62 @@ type Server struct {
 	logger     zerolog.Logger
 	httpServer *http.Server
 	listener   net.Listener
+	ready      chan struct{}
+
+	// certFile and keyFile, when set, make Start serve HTTPS.
+	certFile string
+	keyFile  string
+
+	// maxBodyBytes caps the size of request bodies.
+	maxBodyBytes int64
+
+	// strict rejects request bodies with unknown JSON fields.
+	strict bool
+
+	// authToken is the bearer token the moderation, warning, command, and
+	// maintenance endpoints require. When empty, they refuse every request.
+	authToken string
+
+	// idempotency remembers responses to rule updates sent with an
+	// idempotency key.
+	idempotency *idempotencyCache
 }
 
 // NewServer creates a new control API server.
 // The server will bind to 127.0.0.1:port when started.
-func NewServer(port int, bot BotInfo, logger zerolog.Logger) *Server {
+// Options are applied after the server is built.
+func NewServer(port int, bot BotInfo, logger zerolog.Logger, opts ...ServerOption) *Server {
 	s := &Server{
 		port:   port,
 		bot:    bot,
 		logger: logger,
+		ready:  make(chan struct{}),
+
+		maxBodyBytes: DefaultMaxBodyBytes,
+		idempotency:  newIdempotencyCache(IdempotencyTTL),
 	}
 
 	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
 	mux.HandleFunc("/stats", s.handleStats)
+	mux.HandleFunc("/stats/commands/top", s.handleTopCommands)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/overview", s.handleOverview)
 	mux.HandleFunc("/rules", s.handleRules)
 	mux.HandleFunc("/rules/set", s.handleSetRule)
+	mux.HandleFunc("/rules/batch", s.handleSetRules)
+	mux.HandleFunc("/rules/test", s.handleTestRule)
+	mux.HandleFunc("/warnings/clear", s.authenticate(s.handleClearWarnings))
+	mux.HandleFunc("/moderation/ban", s.authenticate(s.handleBan))
+	mux.HandleFunc("/moderation/kick", s.authenticate(s.handleKick))
+	mux.HandleFunc("/moderation/mute", s.authenticate(s.handleMute))
+	mux.HandleFunc("/moderation/unban", s.authenticate(s.handleUnban))
+	mux.HandleFunc("/punishments", s.handlePunishments)
+	mux.HandleFunc("/punishments/cancel", s.authenticate(s.handleCancelPunishment))
+	mux.HandleFunc("/commands", s.handleCommands)
+	mux.HandleFunc("/commands/reload", s.authenticate(s.handleReloadCommands))
+	mux.HandleFunc("/commands/{name}/{action}", s.authenticate(s.handleSetCommand))
+	mux.HandleFunc("/maintenance/{action}", s.authenticate(s.handleSetMaintenance))
 
 	s.httpServer = &http.Server{
 		Addr:         fmt.Sprintf("127.0.0.1:%d", port),
@@ -43,11 +89,19 @@ func NewServer(port int, bot BotInfo, logger zerolog.Logger) *Server {
 		WriteTimeout: 10 * time.Second,
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	return s
 }
 
-// Start starts the HTTP server on localhost.
-// Returns an error if the server fails to start.
+// Start binds the listener on localhost and serves requests in a background goroutine.
+// The bind happens synchronously, so any error (such as the port already being in use)
+// is returned directly, and once Start returns nil the server is accepting connections.
+// Start does not block while serving; use Stop to shut the server down.
+// With WithTLS, the certificate is loaded before binding and the server
+// speaks HTTPS only.
 func (s *Server) Start() error {
 	if s == nil {
 		return fmt.Errorf("server cannot be nil")
@@ -57,15 +111,25 @@ func (s *Server) Start() error {
 		return errors.New("server already started")
 	}
 
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+
 	listener, err := net.Listen("tcp", s.httpServer.Addr)
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
 	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
 
 	s.listener = listener
+	close(s.ready)
 
 	s.logger.Info().
 		Str("address", listener.Addr().String()).
+		Bool("tls", tlsConfig != nil).
 		Msg("control API server starting")
 
 	go func() {
@@ -77,6 +141,28 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// tlsConfig loads the certificate set by WithTLS. It returns nil if TLS is not
+// configured, and an error if only one of the certificate and key is set.
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	switch {
+	case s.certFile == "" && s.keyFile == "":
+		return nil, nil
+	case s.certFile == "":
+		return nil, errors.New("TLS key file is set but certificate file is not; both are required")
+	case s.keyFile == "":
+		return nil, errors.New("TLS certificate file is set but key file is not; both are required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
 // Stop gracefully shuts down the HTTP server.
 // The provided context can be used to set a deadline for the shutdown process.
 func (s *Server) Stop(ctx context.Context) error {
@@ -99,6 +185,17 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Ready returns a channel that is closed once the server's listener is established.
+// Because Start binds synchronously, the channel is always closed by the time a
+// successful Start returns; it is useful for callers that start the server in
+// another goroutine. Returns nil for a nil Server.
+func (s *Server) Ready() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.ready
+}
+
 // Addr returns the listener address of the server.
 // Returns empty string if the server is not started.
 func (s *Server) Addr() string {
@@ -108,9 +205,19 @@ func (s *Server) Addr() string {
 	return s.listener.Addr().String()
 }
 
+// Handler returns the HTTP handler containing the server's routes.
+// It can be mounted in another server or exercised with httptest without
+// binding a socket.
+func (s *Server) Handler() http.Handler {
+	if s == nil || s.httpServer == nil {
+		return http.NotFoundHandler()
+	}
+	return s.httpServer.Handler
+}
+
 // ServeHTTP implements http.Handler interface, allowing the server to be used with httptest.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	s.httpServer.Handler.ServeHTTP(w, r)
+	s.Handler().ServeHTTP(w, r)
 }
 
 // handleStats handles GET /stats requests.
@@ -135,16 +242,78 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleHealth handles GET /health requests. It does not consult the bot, so
+// clients can cheaply tell a running control server from an unreachable one.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(Health{Status: "ok"})
+}
+
+// handleTopCommands handles GET /stats/commands/top requests, returning the
+// most executed commands, most first. ?n=<count> sets how many are returned,
+// DefaultTopCommands if not given, and is capped at MaxTopCommands.
+func (s *Server) handleTopCommands(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	n := DefaultTopCommands
+	if raw := r.URL.Query().Get("n"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			http.Error(w, "Bad request: n must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		n = min(parsed, MaxTopCommands)
+	}
+
+	stats := s.bot.Stats()
+	if stats == nil {
+		s.logger.Error().Msg("bot returned nil stats")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(topCommands(stats.Commands, n)); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode top commands")
+	}
+}
+
+// topCommands returns up to n of cmds ordered by executions, most first, with
+// ties in name order. cmds is not modified.
+func topCommands(cmds []CommandStats, n int) []CommandStats {
+	top := make([]CommandStats, len(cmds))
+	copy(top, cmds)
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].Executions != top[j].Executions {
+			return top[i].Executions > top[j].Executions
+		}
+		return top[i].Name < top[j].Name
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
 // handleRules handles GET /rules requests.
+// With ?guild=<id>, it returns the settings in effect in that guild.
 func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	rules := s.bot.Rules()
-	if rules == nil {
-		rules = []Rule{}
+	rules, ok := s.requestRules(w, r)
+	if !ok {
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -155,11 +324,137 @@ func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleOverview handles GET /overview requests, returning the stats and
+// rules in one response for callers that poll both.
+// With ?guild=<id>, the rules are those in effect in that guild.
+func (s *Server) handleOverview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rules, ok := s.requestRules(w, r)
+	if !ok {
+		return
+	}
+	stats := s.bot.Stats()
+	if stats == nil {
+		s.logger.Error().Msg("bot returned nil stats")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Overview{Stats: stats, Rules: rules}); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode overview")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// requestRules returns the rules a GET request asks for: those in effect in
+// the guild named by ?guild=<id>, or the global rules. The result is never
+// nil. If the bot does not support per-guild rules, it writes a 400 response
+// and returns false.
+func (s *Server) requestRules(w http.ResponseWriter, r *http.Request) ([]Rule, bool) {
+	var rules []Rule
+	if guildID := strings.TrimSpace(r.URL.Query().Get("guild")); guildID != "" {
+		manager, ok := s.bot.(GuildRuleManager)
+		if !ok {
+			http.Error(w, "Bad request: per-guild rules are not supported", http.StatusBadRequest)
+			return nil, false
+		}
+		rules = manager.GuildRules(guildID)
+	} else {
+		rules = s.bot.Rules()
+	}
+	if rules == nil {
+		rules = []Rule{}
+	}
+	return rules, true
+}
+
+// decodeBody decodes the JSON request body into v, reading at most the
+// server's body limit. In strict mode, set by WithStrictDecoding or the
+// request's ?strict=true, unknown fields are an error. On failure it writes a
+// 415 response for a body not declared as JSON, 413 for an oversized body, or
+// 400 for invalid JSON, and returns false.
+func (s *Server) decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if contentType := r.Header.Get("Content-Type"); !isJSONContentType(contentType) {
+		s.logger.Warn().Str("content_type", contentType).Msg("request body is not JSON")
+		http.Error(w, "Unsupported media type: request body must be application/json", http.StatusUnsupportedMediaType)
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+	decoder := json.NewDecoder(r.Body)
+	if s.strictRequest(r) {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.logger.Warn().Int64("limit", tooLarge.Limit).Msg("request body too large")
+			http.Error(w, fmt.Sprintf("Request body too large: limit is %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+			return false
+		}
+		s.logger.Warn().Err(err).Msg("invalid request body")
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			http.Error(w, "Bad request: unknown field "+field, http.StatusBadRequest)
+			return false
+		}
+		http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// isJSONContentType reports whether contentType declares a JSON body:
+// application/json or a structured "+json" type, with any parameters.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+// strictRequest reports whether r's body must not contain unknown fields.
+func (s *Server) strictRequest(r *http.Request) bool {
+	if s.strict {
+		return true
+	}
+	strict, _ := strconv.ParseBool(r.URL.Query().Get("strict"))
+	return strict
+}
+
 // SetRuleRequest represents the JSON payload for setting a rule.
+// Guild, when set, scopes the setting to that guild instead of globally.
+//
+// IdempotencyKey, when set, makes POST /rules/set safe to retry: the server
+// remembers the response for IdempotencyTTL, and a request repeating the key
+// gets that response without the setting being applied again. Items of a
+// batch update ignore it.
 type SetRuleRequest struct {
-	Name  string `json:"name"`
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Guild          string `json:"guild,omitempty"`
+	Name           string `json:"name"`
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// setRule applies req globally, or to req.Guild when set.
+func (s *Server) setRule(req SetRuleRequest) error {
+	if req.Guild == "" {
+		return s.bot.SetRule(req.Name, req.Key, req.Value)
+	}
+
+	manager, ok := s.bot.(GuildRuleManager)
+	if !ok {
+		return fmt.Errorf("%w: per-guild rules are not supported", ErrInvalidRule)
+	}
+	return manager.SetGuildRule(req.Guild, req.Name, req.Key, req.Value)
 }
 
 // handleSetRule handles POST /rules/set requests.
@@ -170,28 +465,112 @@ func (s *Server) handleSetRule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req SetRuleRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.logger.Warn().Err(err).Msg("invalid request body")
-		http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+	if !s.decodeBody(w, r, &req) {
 		return
 	}
 
+	req.Guild = strings.TrimSpace(req.Guild)
+	req.Name = strings.TrimSpace(req.Name)
+	req.Key = strings.TrimSpace(req.Key)
 	if req.Name == "" || req.Key == "" {
 		http.Error(w, "Bad request: name and key are required", http.StatusBadRequest)
 		return
 	}
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		http.Error(w, fmt.Sprintf("Bad request: idempotency_key must be at most %d characters", maxIdempotencyKeyLength),
+			http.StatusBadRequest)
+		return
+	}
+
+	if req.IdempotencyKey == "" {
+		s.writeSetRule(w, req)
+		return
+	}
+
+	for {
+		entry, first := s.idempotency.begin(time.Now(), req)
+		if first {
+			s.applySetRule(entry, req).writeTo(w)
+			return
+		}
+
+		if entry.request != req {
+			http.Error(w, "Unprocessable entity: idempotency_key was already used for a different request",
+				http.StatusUnprocessableEntity)
+			return
+		}
+
+		// Wait for the original request, which may still be applying
+		select {
+		case <-entry.done:
+		case <-r.Context().Done():
+			return
+		}
+		if entry.response != nil {
+			s.logger.Debug().
+				Str("name", req.Name).
+				Str("key", req.Key).
+				Msg("replaying rule update for repeated idempotency key")
+			w.Header().Set("Idempotent-Replayed", "true")
+			entry.response.writeTo(w)
+			return
+		}
+		// The original failed with a server error and was forgotten, so
+		// this request applies the update itself
+	}
+}
+
+// applySetRule applies req for the first request with its idempotency key and
+// records the response in entry for retries to replay. If applying it panics,
+// a server error is recorded instead, so the key is forgotten rather than left
+// in progress, before the panic carries on.
+func (s *Server) applySetRule(entry *idempotentEntry, req SetRuleRequest) *recordedResponse {
+	response := newRecordedResponse()
+	completed := false
+	defer func() {
+		if !completed {
+			response.status = http.StatusInternalServerError
+		}
+		s.idempotency.finish(time.Now(), entry, response)
+	}()
+
+	s.writeSetRule(response, req)
+	completed = true
+	return response
+}
 
-	if err := s.bot.SetRule(req.Name, req.Key, req.Value); err != nil {
+// writeSetRule applies req and writes the outcome to w.
+func (s *Server) writeSetRule(w http.ResponseWriter, req SetRuleRequest) {
+	response := SetRuleResponse{Status: "ok"}
+	err := s.setRule(req)
+	if errors.Is(err, ErrRuleNotSaved) {
+		// The setting took effect, so the request succeeded; the client is
+		// told it will not survive a restart
+		s.logger.Warn().
+			Err(err).
+			Str("guild", req.Guild).
+			Str("name", req.Name).
+			Str("key", req.Key).
+			Msg("rule set but not saved")
+		response.Warning = err.Error()
+		err = nil
+	}
+	if err != nil {
 		s.logger.Error().
 			Err(err).
+			Str("guild", req.Guild).
 			Str("name", req.Name).
 			Str("key", req.Key).
 			Msg("failed to set rule")
 
-		// Return 400 for rule not found, 500 for other errors
+		// Return 400 for unknown rules or invalid settings, 409 for settings
+		// the bot cannot act on until restarted, 500 for other errors
 		statusCode := http.StatusInternalServerError
-		if err == ErrRuleNotFound {
+		switch {
+		case errors.Is(err, ErrRuleNotFound) || errors.Is(err, ErrInvalidRule):
 			statusCode = http.StatusBadRequest
+		case errors.Is(err, ErrIntentNotRequested):
+			statusCode = http.StatusConflict
 		}
 		http.Error(w, fmt.Sprintf("Failed to set rule: %v", err), statusCode)
 		return
@@ -199,8 +578,308 @@ func (s *Server) handleSetRule(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+// handleSetRules handles POST /rules/batch requests.
+// Each item is applied independently: a failing item does not stop the rest,
+// and the response reports the outcome of every item in request order.
+func (s *Server) handleSetRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reqs []SetRuleRequest
+	if !s.decodeBody(w, r, &reqs) {
+		return
+	}
+
+	if len(reqs) == 0 {
+		http.Error(w, "Bad request: at least one rule is required", http.StatusBadRequest)
+		return
+	}
+
+	response := BatchSetRulesResponse{Results: make([]SetRuleResult, 0, len(reqs))}
+	for _, req := range reqs {
+		result := SetRuleResult{
+			Guild: strings.TrimSpace(req.Guild),
+			Name:  strings.TrimSpace(req.Name),
+			Key:   strings.TrimSpace(req.Key),
+			Value: req.Value,
+		}
+
+		var err error
+		if result.Name == "" || result.Key == "" {
+			err = errors.New("name and key are required")
+		} else {
+			err = s.setRule(SetRuleRequest{Guild: result.Guild, Name: result.Name, Key: result.Key, Value: result.Value})
+		}
+
+		switch {
+		case errors.Is(err, ErrRuleNotSaved):
+			result.Warning = err.Error()
+			response.Succeeded++
+		case err != nil:
+			result.Error = err.Error()
+			response.Failed++
+		default:
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	s.logger.Info().
+		Int("succeeded", response.Succeeded).
+		Int("failed", response.Failed).
+		Msg("applied rule batch")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+// handleTestRule handles POST /rules/test requests, reporting whether a
+// content rule would match a sample message and what it would do, without
+// acting on anything.
+func (s *Server) handleTestRule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tester, ok := s.bot.(RuleTester)
+	if !ok {
+		http.Error(w, "Not implemented: rules cannot be tested", http.StatusNotImplemented)
+		return
+	}
+
+	var req TestRuleRequest
+	if !s.decodeBody(w, r, &req) {
+		return
+	}
+
+	req.Guild = strings.TrimSpace(req.Guild)
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "Bad request: name is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := tester.TestRule(req.Guild, req.Name, req.Input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrRuleNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, ErrRuleNotTestable):
+			statusCode = http.StatusBadRequest
+		default:
+			s.logger.Error().Err(err).Str("name", req.Name).Msg("failed to test rule")
+		}
+		http.Error(w, fmt.Sprintf("Failed to test rule: %v", err), statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+// ClearWarningsRequest represents the JSON payload for clearing a member's warnings.
+type ClearWarningsRequest struct {
+	GuildID string `json:"guild_id"`
+	UserID  string `json:"user_id"`
+}
+
+// handleClearWarnings handles POST /warnings/clear requests.
+func (s *Server) handleClearWarnings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ClearWarningsRequest
+	if !s.decodeBody(w, r, &req) {
+		return
+	}
+
+	req.GuildID = strings.TrimSpace(req.GuildID)
+	req.UserID = strings.TrimSpace(req.UserID)
+	if req.GuildID == "" || req.UserID == "" {
+		http.Error(w, "Bad request: guild_id and user_id are required", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := s.bot.ClearWarnings(req.GuildID, req.UserID)
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("guild_id", req.GuildID).
+			Str("user_id", req.UserID).
+			Msg("failed to clear warnings")
+		http.Error(w, fmt.Sprintf("Failed to clear warnings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Info().
+		Str("guild_id", req.GuildID).
+		Str("user_id", req.UserID).
+		Int("removed", removed).
+		Msg("cleared warnings")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ClearWarningsResponse{Removed: removed}); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+// commandManager returns the bot's CommandManager, or writes a 501 response
+// and returns false if the bot does not support toggling commands.
+func (s *Server) commandManager(w http.ResponseWriter) (CommandManager, bool) {
+	manager, ok := s.bot.(CommandManager)
+	if !ok {
+		http.Error(w, "Not implemented: commands cannot be toggled", http.StatusNotImplemented)
+	}
+	return manager, ok
+}
+
+// handleCommands handles GET /commands requests.
+func (s *Server) handleCommands(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	manager, ok := s.commandManager(w)
+	if !ok {
+		return
+	}
+
+	states := manager.CommandStates()
+	if states == nil {
+		states = []CommandState{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(states); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode commands")
+	}
+}
+
+// handleSetCommand handles POST /commands/{name}/enable and
+// POST /commands/{name}/disable requests.
+func (s *Server) handleSetCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.PathValue("name")
+	var enabled bool
+	switch r.PathValue("action") {
+	case "enable":
+		enabled = true
+	case "disable":
+		enabled = false
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	manager, ok := s.commandManager(w)
+	if !ok {
+		return
+	}
+
+	if err := manager.SetCommandEnabled(name, enabled); err != nil {
+		s.logger.Error().Err(err).Str("command", name).Bool("enabled", enabled).Msg("failed to toggle command")
+
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrCommandNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, fmt.Sprintf("Failed to toggle command: %v", err), statusCode)
+		return
+	}
+
+	s.logger.Info().Str("command", name).Bool("enabled", enabled).Msg("toggled command")
+
+	w.Header().Set("Content-Type", "application/json")
 	response := map[string]string{"status": "ok"}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		s.logger.Error().Err(err).Msg("failed to encode response")
 	}
 }
+
+// handleReloadCommands handles POST /commands/reload requests, responding
+// with how Discord's commands changed.
+func (s *Server) handleReloadCommands(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	reloader, ok := s.bot.(CommandReloader)
+	if !ok {
+		http.Error(w, "Not implemented: commands cannot be reloaded", http.StatusNotImplemented)
+		return
+	}
+
+	result, err := reloader.ReloadCommands()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to reload commands")
+		http.Error(w, fmt.Sprintf("Failed to reload commands: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Info().
+		Strs("created", result.Created).
+		Strs("updated", result.Updated).
+		Strs("deleted", result.Deleted).
+		Msg("reloaded commands")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+// handleSetMaintenance handles POST /maintenance/enable and
+// POST /maintenance/disable requests, responding with the resulting
+// MaintenanceState.
+func (s *Server) handleSetMaintenance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var enabled bool
+	switch r.PathValue("action") {
+	case "enable":
+		enabled = true
+	case "disable":
+		enabled = false
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	manager, ok := s.bot.(MaintenanceManager)
+	if !ok {
+		http.Error(w, "Not implemented: maintenance mode is not supported", http.StatusNotImplemented)
+		return
+	}
+
+	state := manager.SetMaintenance(enabled)
+	s.logger.Info().Bool("enabled", state.Enabled).Msg("set maintenance mode")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(state); err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode response")
+	}
+}
@@ -3,11 +3,24 @@ package control_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
+	"fmt"
 	"io"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -37,6 +50,14 @@ type mockBotInfo struct {
 	setRuleName   string
 	setRuleKey    string
 	setRuleValue  string
+	setRuleErrs   map[string]error
+	setRuleNames  []string
+	setRulePanics int
+
+	clearWarningsRemoved int
+	clearWarningsErr     error
+	clearWarningsGuild   string
+	clearWarningsUser    string
 }
 
 // Stats returns the mock stats.
@@ -50,14 +71,31 @@ func (m *mockBotInfo) Rules() []control.Rule {
 }
 
 // SetRule records the call and returns the mock error.
+// An entry in setRuleErrs for the rule name takes precedence over setRuleErr,
+// and the first setRulePanics calls panic instead.
 func (m *mockBotInfo) SetRule(name, key, value string) error {
 	m.setRuleCalled = true
 	m.setRuleName = name
 	m.setRuleKey = key
 	m.setRuleValue = value
+	m.setRuleNames = append(m.setRuleNames, name)
+	if m.setRulePanics > 0 {
+		m.setRulePanics--
+		panic("storage exploded")
+	}
+	if err, ok := m.setRuleErrs[name]; ok {
+		return err
+	}
 	return m.setRuleErr
 }
 
+// ClearWarnings records the call and returns the mock result.
+func (m *mockBotInfo) ClearWarnings(guildID, userID string) (int, error) {
+	m.clearWarningsGuild = guildID
+	m.clearWarningsUser = userID
+	return m.clearWarningsRemoved, m.clearWarningsErr
+}
+
 // newMockBotInfo creates a mock BotInfo with default values.
 func newMockBotInfo() *mockBotInfo {
 	return &mockBotInfo{
@@ -86,80 +124,10 @@ func newMockBotInfoWithStats(stats *control.Stats) *mockBotInfo {
 	return m
 }
 
-// createTestServer creates a test HTTP server using the control server's handler.
-// Since the actual Server doesn't expose ServeHTTP, we use httptest with a handler
-// that mimics the actual implementation for unit testing.
+// createTestHandler returns the real control server's handler for the given bot.
+// No socket is bound, so tests exercise production routing via httptest.
 func createTestHandler(bot control.BotInfo, logger zerolog.Logger) http.Handler {
-	mux := http.NewServeMux()
-
-	// /stats handler
-	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		stats := bot.Stats()
-		if stats == nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(stats); err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-	})
-
-	// /rules handler
-	mux.HandleFunc("/rules", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		rules := bot.Rules()
-		if rules == nil {
-			rules = []control.Rule{}
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(rules); err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-	})
-
-	// /rules/set handler
-	mux.HandleFunc("/rules/set", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		var req control.SetRuleRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
-			return
-		}
-
-		if req.Name == "" || req.Key == "" {
-			http.Error(w, "Bad request: name and key are required", http.StatusBadRequest)
-			return
-		}
-
-		if err := bot.SetRule(req.Name, req.Key, req.Value); err != nil {
-			http.Error(w, "Failed to set rule: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
-	})
-
-	return mux
+	return control.NewServer(0, bot, logger, control.WithAuthToken(testAuthToken)).Handler()
 }
 
 // =============================================================================
@@ -212,6 +180,30 @@ func Test_NewServer_PortZero(t *testing.T) {
 	// Note: Port 0 means 127.0.0.1:0, which will bind to a random available port
 }
 
+func Test_Server_HandlerMatchesServeHTTP(t *testing.T) {
+	bot := newMockBotInfo()
+	server := control.NewServer(0, bot, discardLogger())
+
+	viaHandler := httptest.NewRecorder()
+	server.Handler().ServeHTTP(viaHandler, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	viaServer := httptest.NewRecorder()
+	server.ServeHTTP(viaServer, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	assert.Equal(t, http.StatusOK, viaHandler.Code)
+	assert.Equal(t, viaHandler.Code, viaServer.Code)
+	assert.JSONEq(t, viaHandler.Body.String(), viaServer.Body.String())
+}
+
+func Test_Server_HandlerOnNil(t *testing.T) {
+	var server *control.Server
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code, "nil server handler should return 404")
+}
+
 func Test_NewServer_NilBot(t *testing.T) {
 	logger := discardLogger()
 
@@ -222,6 +214,40 @@ func Test_NewServer_NilBot(t *testing.T) {
 	require.NotNil(t, server, "NewServer accepts nil bot (handles at runtime)")
 }
 
+// =============================================================================
+// GET /health Endpoint Tests
+// =============================================================================
+
+func Test_HealthEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		wantCode int
+	}{
+		{name: "GET reports ok", method: http.MethodGet, wantCode: http.StatusOK},
+		{name: "POST not allowed", method: http.MethodPost, wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The health check must not depend on the bot
+			handler := createTestHandler(nil, discardLogger())
+
+			req := httptest.NewRequest(tt.method, "/health", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusOK {
+				var response control.Health
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, "ok", response.Status)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // GET /stats Endpoint Tests
 // =============================================================================
@@ -319,6 +345,126 @@ func Test_StatsEndpoint_NilStats(t *testing.T) {
 		"GET /stats with nil stats should return 500 Internal Server Error")
 }
 
+// =============================================================================
+// GET /stats/commands/top Endpoint Tests
+// =============================================================================
+
+func Test_TopCommandsEndpoint(t *testing.T) {
+	cmds := make([]control.CommandStats, 0, control.MaxTopCommands+5)
+	for i := 0; i < control.MaxTopCommands+5; i++ {
+		cmds = append(cmds, control.CommandStats{Name: fmt.Sprintf("cmd-%03d", i), Executions: int64(i % 50)})
+	}
+
+	tests := []struct {
+		name       string
+		commands   []control.CommandStats
+		query      string
+		wantStatus int
+		wantNames  []string
+		wantLen    int
+	}{
+		{
+			name: "sorted by executions with ties by name",
+			commands: []control.CommandStats{
+				{Name: "ping", Executions: 3},
+				{Name: "kick", Executions: 7},
+				{Name: "ban", Executions: 7},
+				{Name: "warn", Executions: 1},
+			},
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"ban", "kick", "ping", "warn"},
+		},
+		{
+			name: "n limits the result",
+			commands: []control.CommandStats{
+				{Name: "ping", Executions: 3},
+				{Name: "kick", Executions: 7},
+				{Name: "warn", Executions: 1},
+			},
+			query:      "?n=2",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"kick", "ping"},
+		},
+		{
+			name:       "defaults to ten",
+			commands:   cmds,
+			wantStatus: http.StatusOK,
+			wantLen:    control.DefaultTopCommands,
+		},
+		{
+			name:       "n is capped",
+			commands:   cmds,
+			query:      "?n=100000",
+			wantStatus: http.StatusOK,
+			wantLen:    control.MaxTopCommands,
+		},
+		{
+			name:       "no commands",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{},
+		},
+		{name: "non-numeric n", query: "?n=ten", wantStatus: http.StatusBadRequest},
+		{name: "zero n", query: "?n=0", wantStatus: http.StatusBadRequest},
+		{name: "negative n", query: "?n=-3", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			bot.stats.Commands = tt.commands
+			handler := createTestHandler(bot, discardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/commands/top"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var top []control.CommandStats
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &top))
+			if tt.wantNames != nil {
+				names := make([]string, 0, len(top))
+				for _, cmd := range top {
+					names = append(names, cmd.Name)
+				}
+				assert.Equal(t, tt.wantNames, names)
+			}
+			if tt.wantLen > 0 {
+				assert.Len(t, top, tt.wantLen)
+				for i := 1; i < len(top); i++ {
+					assert.GreaterOrEqual(t, top[i-1].Executions, top[i].Executions, "commands should be sorted by executions")
+				}
+			}
+		})
+	}
+}
+
+func Test_TopCommandsEndpoint_DoesNotReorderStats(t *testing.T) {
+	bot := newMockBotInfo()
+	bot.stats.Commands = []control.CommandStats{{Name: "ban", Executions: 1}, {Name: "kick", Executions: 2}}
+	handler := createTestHandler(bot, discardLogger())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats/commands/top", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ban", bot.stats.Commands[0].Name, "GET /stats lists commands in name order")
+}
+
+func Test_TopCommandsEndpoint_MethodNotAllowed(t *testing.T) {
+	handler := createTestHandler(newMockBotInfo(), discardLogger())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stats/commands/top", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
 // =============================================================================
 // GET /rules Endpoint Tests
 // =============================================================================
@@ -412,6 +558,140 @@ func Test_RulesEndpoint_WrongMethod(t *testing.T) {
 	}
 }
 
+// Test_RulesEndpoint_JSONContract pins the exact field set and JSON types of
+// GET /rules so changes that would break external consumers fail loudly.
+func Test_RulesEndpoint_JSONContract(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []control.Rule
+	}{
+		{name: "nil rules encode as empty array", rules: nil},
+		{name: "empty rules encode as empty array", rules: []control.Rule{}},
+		{
+			name: "zero-value fields are still emitted",
+			rules: []control.Rule{
+				{},
+				{Name: "anti-spam", Description: "Limits spam", Enabled: true, Key: "threshold", Value: "5"},
+			},
+		},
+	}
+
+	wantTypes := map[string]string{
+		"name":        "string",
+		"description": "string",
+		"enabled":     "bool",
+		"key":         "string",
+		"value":       "string",
+		"guild":       "string",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createTestHandler(newMockBotInfoWithRules(tt.rules), discardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/rules", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var raw []map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+			require.NotNil(t, raw, "rules must be a JSON array, never null")
+			require.Len(t, raw, len(tt.rules))
+
+			for i, rule := range raw {
+				gotTypes := make(map[string]string, len(rule))
+				for field, value := range rule {
+					gotTypes[field] = fmt.Sprintf("%T", value)
+				}
+				assert.Equal(t, wantTypes, gotTypes, "rule %d field set and types", i)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// GET /overview Endpoint Tests
+// =============================================================================
+
+func Test_OverviewEndpoint(t *testing.T) {
+	global := []control.Rule{{Name: "anti-spam", Key: "threshold", Value: "5"}}
+	scoped := []control.Rule{{Name: "anti-spam", Key: "threshold", Value: "3", Guild: "guild-1"}}
+
+	tests := []struct {
+		name       string
+		bot        control.BotInfo
+		method     string
+		query      string
+		wantStatus int
+		wantRules  []control.Rule
+	}{
+		{
+			name:       "stats and global rules",
+			bot:        newMockBotInfoWithRules(global),
+			wantStatus: http.StatusOK,
+			wantRules:  global,
+		},
+		{
+			name:       "nil rules are an empty array",
+			bot:        newMockBotInfoWithRules(nil),
+			wantStatus: http.StatusOK,
+			wantRules:  []control.Rule{},
+		},
+		{
+			name:       "guild filter lists the guild's rules",
+			bot:        &guildBotInfo{mockBotInfo: newMockBotInfoWithRules(global), guildRules: map[string][]control.Rule{"guild-1": scoped}},
+			query:      "?guild=guild-1",
+			wantStatus: http.StatusOK,
+			wantRules:  scoped,
+		},
+		{
+			name:       "bot without guild support",
+			bot:        newMockBotInfoWithRules(global),
+			query:      "?guild=guild-1",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "nil stats",
+			bot:        newMockBotInfoWithStats(nil),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "wrong method",
+			bot:        newMockBotInfo(),
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createTestHandler(tt.bot, discardLogger())
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "/overview"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var overview control.Overview
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &overview))
+			require.NotNil(t, overview.Stats)
+			assert.Equal(t, int64(42), overview.Stats.CommandsExecuted)
+			assert.Equal(t, tt.wantRules, overview.Rules)
+			assert.NotContains(t, rec.Body.String(), `"rules":null`, "rules should never be null")
+		})
+	}
+}
+
 // =============================================================================
 // POST /rules/set Endpoint Tests
 // =============================================================================
@@ -555,6 +835,135 @@ func Test_RulesSetEndpoint_WrongMethod(t *testing.T) {
 	}
 }
 
+func Test_RulesSetEndpoint_IdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		first        string
+		second       string
+		setRuleErr   error
+		wantStatus   []int
+		wantCalls    int
+		wantReplayed bool
+	}{
+		{
+			name:         "repeated key replays the original response",
+			first:        `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			second:       `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			wantStatus:   []int{http.StatusOK, http.StatusOK},
+			wantCalls:    1,
+			wantReplayed: true,
+		},
+		{
+			name:         "repeated key replays a rejected update",
+			first:        `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			second:       `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			setRuleErr:   fmt.Errorf("%w: spam-filter", control.ErrRuleNotFound),
+			wantStatus:   []int{http.StatusBadRequest, http.StatusBadRequest},
+			wantCalls:    1,
+			wantReplayed: true,
+		},
+		{
+			name:       "server errors are applied again",
+			first:      `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			second:     `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			setRuleErr: errors.New("storage unavailable"),
+			wantStatus: []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			wantCalls:  2,
+		},
+		{
+			name:       "key reused for a different update",
+			first:      `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			second:     `{"name":"spam-filter","key":"threshold","value":"20","idempotency_key":"k1"}`,
+			wantStatus: []int{http.StatusOK, http.StatusUnprocessableEntity},
+			wantCalls:  1,
+		},
+		{
+			name:       "different keys are applied separately",
+			first:      `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`,
+			second:     `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k2"}`,
+			wantStatus: []int{http.StatusOK, http.StatusOK},
+			wantCalls:  2,
+		},
+		{
+			name:       "updates without a key are applied every time",
+			first:      `{"name":"spam-filter","key":"threshold","value":"10"}`,
+			second:     `{"name":"spam-filter","key":"threshold","value":"10"}`,
+			wantStatus: []int{http.StatusOK, http.StatusOK},
+			wantCalls:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			bot.setRuleErr = tt.setRuleErr
+			handler := createTestHandler(bot, discardLogger())
+
+			var recs []*httptest.ResponseRecorder
+			for _, body := range []string{tt.first, tt.second} {
+				req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				recs = append(recs, rec)
+			}
+
+			assert.Equal(t, tt.wantStatus, []int{recs[0].Code, recs[1].Code})
+			assert.Len(t, bot.setRuleNames, tt.wantCalls, "SetRule calls")
+			if tt.wantReplayed {
+				assert.Equal(t, "true", recs[1].Header().Get("Idempotent-Replayed"))
+				assert.Equal(t, recs[0].Body.String(), recs[1].Body.String())
+				assert.Equal(t, recs[0].Header().Get("Content-Type"), recs[1].Header().Get("Content-Type"))
+			} else {
+				assert.Empty(t, recs[1].Header().Get("Idempotent-Replayed"))
+			}
+		})
+	}
+}
+
+func Test_RulesSetEndpoint_IdempotencyKeyPanic(t *testing.T) {
+	bot := newMockBotInfo()
+	bot.setRulePanics = 1
+	handler := createTestHandler(bot, discardLogger())
+
+	body := `{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":"k1"}`
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Panics(t, func() { send() })
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() { done <- send() }()
+	select {
+	case rec := <-done:
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Idempotent-Replayed"), "a retry should apply the update itself")
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry waited on the key of the request that panicked")
+	}
+	assert.Len(t, bot.setRuleNames, 2, "SetRule calls")
+}
+
+func Test_RulesSetEndpoint_IdempotencyKeyTooLong(t *testing.T) {
+	bot := newMockBotInfo()
+	handler := createTestHandler(bot, discardLogger())
+
+	body := fmt.Sprintf(`{"name":"spam-filter","key":"threshold","value":"10","idempotency_key":%q}`, strings.Repeat("k", 129))
+	req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, bot.setRuleCalled, "SetRule should not be called with an oversized key")
+}
+
 func Test_RulesSetEndpoint_RuleNotFound(t *testing.T) {
 	bot := newMockBotInfo()
 	bot.setRuleErr = errors.New("rule not found")
@@ -573,45 +982,141 @@ func Test_RulesSetEndpoint_RuleNotFound(t *testing.T) {
 	assert.True(t, bot.setRuleCalled, "SetRule should be called")
 }
 
-func Test_RulesSetEndpoint_EmptyName(t *testing.T) {
+func Test_RulesSetEndpoint_InvalidRuleSetting(t *testing.T) {
+	tests := []struct {
+		name       string
+		setRuleErr error
+		wantStatus int
+	}{
+		{
+			name:       "invalid value maps to 400",
+			setRuleErr: fmt.Errorf("%w: anti-spam.threshold: must be a positive integer", control.ErrInvalidRule),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrapped rule not found maps to 400",
+			setRuleErr: fmt.Errorf("%w: %q", control.ErrRuleNotFound, "nonexistent"),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing intent maps to 409",
+			setRuleErr: fmt.Errorf("%w: it needs the Message Content intent", control.ErrIntentNotRequested),
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "other errors map to 500",
+			setRuleErr: errors.New("disk on fire"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			bot.setRuleErr = tt.setRuleErr
+			handler := createTestHandler(bot, discardLogger())
+
+			body := `{"name":"anti-spam","key":"threshold","value":"notanumber"}`
+			req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.setRuleErr.Error(),
+				"response should describe the failure")
+		})
+	}
+}
+
+func Test_RulesSetEndpoint_NotSaved(t *testing.T) {
 	bot := newMockBotInfo()
+	bot.setRuleErr = fmt.Errorf("%w: read-only file system", control.ErrRuleNotSaved)
 	handler := createTestHandler(bot, discardLogger())
 
-	body := `{"name":"","key":"threshold","value":"10"}`
+	body := `{"name":"anti-spam","key":"threshold","value":"5"}`
 	req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
 	handler.ServeHTTP(rec, req)
 
-	assert.Equal(t, http.StatusBadRequest, rec.Code,
-		"POST /rules/set with empty name should return 400 Bad Request")
-	assert.False(t, bot.setRuleCalled, "SetRule should not be called with empty name")
+	assert.Equal(t, http.StatusOK, rec.Code, "the setting was applied, so the request succeeded")
+	var resp control.SetRuleResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "ok", resp.Status)
+	assert.Contains(t, resp.Warning, "read-only file system", "the client should be told it was not saved")
 }
 
-func Test_RulesSetEndpoint_WhitespaceName(t *testing.T) {
+func Test_RulesSetEndpoint_EmptyName(t *testing.T) {
 	bot := newMockBotInfo()
 	handler := createTestHandler(bot, discardLogger())
 
-	body := `{"name":"   ","key":"threshold","value":"10"}`
+	body := `{"name":"","key":"threshold","value":"10"}`
 	req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
 	handler.ServeHTTP(rec, req)
 
-	// Whitespace-only name is accepted as non-empty by current implementation
-	// This documents the actual behavior
-	t.Logf("POST /rules/set with whitespace name returned status: %d", rec.Code)
+	assert.Equal(t, http.StatusBadRequest, rec.Code,
+		"POST /rules/set with empty name should return 400 Bad Request")
+	assert.False(t, bot.setRuleCalled, "SetRule should not be called with empty name")
 }
 
-// =============================================================================
-// Server Lifecycle Tests
-// =============================================================================
-
-func Test_ServerLifecycle_StartThenStop(t *testing.T) {
-	bot := newMockBotInfo()
-	logger := discardLogger()
+func Test_RulesSetEndpoint_WhitespaceName(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "whitespace-only name", body: `{"name":"   ","key":"threshold","value":"10"}`},
+		{name: "tab and newline name", body: `{"name":"\t\n","key":"threshold","value":"10"}`},
+		{name: "whitespace-only key", body: `{"name":"anti-spam","key":"  ","value":"10"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			handler := createTestHandler(bot, discardLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code,
+				"POST /rules/set with blank name or key should return 400 Bad Request")
+			assert.False(t, bot.setRuleCalled, "SetRule should not be called with a blank name or key")
+		})
+	}
+}
+
+func Test_RulesSetEndpoint_TrimsNameAndKey(t *testing.T) {
+	bot := newMockBotInfo()
+	handler := createTestHandler(bot, discardLogger())
+
+	body := `{"name":"  anti-spam ","key":" threshold\t","value":" 10 "}`
+	req := httptest.NewRequest(http.MethodPost, "/rules/set", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "anti-spam", bot.setRuleName, "name should be trimmed")
+	assert.Equal(t, "threshold", bot.setRuleKey, "key should be trimmed")
+	assert.Equal(t, " 10 ", bot.setRuleValue, "value should be passed through untouched")
+}
+
+// =============================================================================
+// Server Lifecycle Tests
+// =============================================================================
+
+func Test_ServerLifecycle_StartThenStop(t *testing.T) {
+	bot := newMockBotInfo()
+	logger := discardLogger()
 	server := control.NewServer(0, bot, logger)
 
 	// Start server
@@ -670,6 +1175,155 @@ func Test_ServerLifecycle_DoubleStart(t *testing.T) {
 	_ = server.Stop(context.Background())
 }
 
+func Test_ServerLifecycle_RequestImmediatelyAfterStart(t *testing.T) {
+	bot := newMockBotInfo()
+	server := control.NewServer(0, bot, discardLogger())
+
+	err := server.Start()
+	require.NoError(t, err, "Start should not return error")
+	defer func() { _ = server.Stop(context.Background()) }()
+
+	// No sleep or retry: the listener must already be accepting connections
+	resp, err := http.Get("http://" + server.Addr() + "/stats")
+	require.NoError(t, err, "request immediately after Start should succeed")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func Test_ServerLifecycle_Ready(t *testing.T) {
+	bot := newMockBotInfo()
+	server := control.NewServer(0, bot, discardLogger())
+
+	select {
+	case <-server.Ready():
+		t.Fatal("Ready channel should not be closed before Start")
+	default:
+	}
+
+	err := server.Start()
+	require.NoError(t, err)
+	defer func() { _ = server.Stop(context.Background()) }()
+
+	select {
+	case <-server.Ready():
+	default:
+		t.Fatal("Ready channel should be closed once Start returns")
+	}
+	assert.NotEmpty(t, server.Addr(), "Addr should be set once ready")
+}
+
+func Test_ServerLifecycle_StartBindError(t *testing.T) {
+	first := control.NewServer(0, newMockBotInfo(), discardLogger())
+	require.NoError(t, first.Start())
+	defer func() { _ = first.Stop(context.Background()) }()
+
+	// Bind a second server to the port the first one already holds
+	_, portStr, err := net.SplitHostPort(first.Addr())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	second := control.NewServer(port, newMockBotInfo(), discardLogger())
+	err = second.Start()
+	assert.Error(t, err, "Start should return the bind error synchronously")
+
+	select {
+	case <-second.Ready():
+		t.Fatal("Ready channel should not be closed after a failed Start")
+	default:
+	}
+}
+
+// =============================================================================
+// TLS Tests
+// =============================================================================
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1 and its
+// key as PEM files in a temporary directory. It returns the file paths and a
+// pool that trusts the certificate.
+func writeTestCertificate(t *testing.T) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jamesbot test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
+
+func Test_Server_TLS(t *testing.T) {
+	certFile, keyFile, pool := writeTestCertificate(t)
+
+	server := control.NewServer(0, newMockBotInfo(), discardLogger(), control.WithTLS(certFile, keyFile))
+	require.NoError(t, server.Start())
+	defer func() { _ = server.Stop(context.Background()) }()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + server.Addr() + "/stats")
+	require.NoError(t, err, "HTTPS request should succeed with the server's certificate trusted")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Plain HTTP is not served on a TLS listener
+	plain, err := http.Get("http://" + server.Addr() + "/stats")
+	if err == nil {
+		defer plain.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, plain.StatusCode, "plain HTTP should be refused")
+	}
+}
+
+func Test_Server_TLSConfigErrors(t *testing.T) {
+	certFile, keyFile, _ := writeTestCertificate(t)
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		wantErr  string
+	}{
+		{name: "certificate without key", certFile: certFile, wantErr: "key file is not"},
+		{name: "key without certificate", keyFile: keyFile, wantErr: "certificate file is not"},
+		{name: "missing files", certFile: certFile + ".missing", keyFile: keyFile, wantErr: "failed to load TLS certificate"},
+		{name: "certificate and key swapped", certFile: keyFile, keyFile: certFile, wantErr: "failed to load TLS certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := control.NewServer(0, newMockBotInfo(), discardLogger(), control.WithTLS(tt.certFile, tt.keyFile))
+
+			err := server.Start()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Empty(t, server.Addr(), "no port should be bound when TLS cannot be set up")
+		})
+	}
+}
+
 // =============================================================================
 // Unknown Endpoint Tests
 // =============================================================================
@@ -980,6 +1634,131 @@ func Test_RulesSetEndpoint_WithExtraFields(t *testing.T) {
 	assert.True(t, bot.setRuleCalled)
 }
 
+func Test_PostEndpoints_ContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		wantStatus  int
+	}{
+		{name: "plain text", path: "/rules/set", contentType: "text/plain", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "form encoded", path: "/rules/set", contentType: "application/x-www-form-urlencoded", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing", path: "/rules/set", contentType: "", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "malformed", path: "/rules/set", contentType: "application/json; charset", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "plain text batch", path: "/rules/batch", contentType: "text/plain", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "plain text warnings clear", path: "/warnings/clear", contentType: "text/plain", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "json", path: "/rules/set", contentType: "application/json", wantStatus: http.StatusOK},
+		{name: "json with charset", path: "/rules/set", contentType: "application/json; charset=utf-8", wantStatus: http.StatusOK},
+		{name: "json in another case", path: "/rules/set", contentType: "Application/JSON", wantStatus: http.StatusOK},
+		{name: "structured json suffix", path: "/rules/set", contentType: "application/merge-patch+json", wantStatus: http.StatusOK},
+	}
+
+	bodies := map[string]string{
+		"/rules/set":      `{"name":"x","key":"y","value":"z"}`,
+		"/rules/batch":    `[{"name":"x","key":"y","value":"z"}]`,
+		"/warnings/clear": `{"guild_id":"1","user_id":"2"}`,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			handler := createTestHandler(bot, discardLogger())
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(bodies[tt.path]))
+			req.Header.Set("Authorization", "Bearer "+testAuthToken)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus == http.StatusUnsupportedMediaType {
+				assert.Contains(t, rec.Body.String(), "application/json")
+				assert.False(t, bot.setRuleCalled, "a rejected request must not change anything")
+			}
+		})
+	}
+}
+
+func Test_PostEndpoints_StrictDecoding(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		opts       []control.ServerOption
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "lenient by default",
+			path:       "/rules/set",
+			body:       `{"name":"x","key":"y","value":"z","extra":"ignored"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "server option rejects a typo",
+			path:       "/rules/set",
+			body:       `{"naem":"x","key":"y","value":"z"}`,
+			opts:       []control.ServerOption{control.WithStrictDecoding()},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `unknown field "naem"`,
+		},
+		{
+			name:       "query parameter rejects a typo",
+			path:       "/rules/set?strict=true",
+			body:       `{"naem":"x","key":"y","value":"z"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `unknown field "naem"`,
+		},
+		{
+			name:       "strict batch rejects a typo in any item",
+			path:       "/rules/batch?strict=1",
+			body:       `[{"name":"x","key":"y","value":"z"},{"name":"x","kye":"y","value":"z"}]`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `unknown field "kye"`,
+		},
+		{
+			name:       "strict warnings clear",
+			path:       "/warnings/clear?strict=true",
+			body:       `{"guild":"1","user_id":"2"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `unknown field "guild"`,
+		},
+		{
+			name:       "strict accepts known fields",
+			path:       "/rules/set",
+			body:       `{"name":"x","key":"y","value":"z"}`,
+			opts:       []control.ServerOption{control.WithStrictDecoding()},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "strict=false stays lenient",
+			path:       "/rules/set?strict=false",
+			body:       `{"name":"x","key":"y","value":"z","extra":"ignored"}`,
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			handler := control.NewServer(0, bot, discardLogger(), append(tt.opts, control.WithAuthToken(testAuthToken))...).Handler()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+testAuthToken)
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
+
 func Test_RulesSetEndpoint_UnicodeValues(t *testing.T) {
 	bot := newMockBotInfo()
 	handler := createTestHandler(bot, discardLogger())
@@ -1018,6 +1797,63 @@ func Test_RulesSetEndpoint_LargeBody(t *testing.T) {
 	assert.Equal(t, largeValue, bot.setRuleValue)
 }
 
+func Test_PostEndpoints_BodyTooLarge(t *testing.T) {
+	oversized := `{"name":"spam-filter","key":"threshold","value":"` + strings.Repeat("x", control.DefaultMaxBodyBytes) + `"}`
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		opts       []control.ServerOption
+		wantStatus int
+	}{
+		{name: "rules set over default limit", path: "/rules/set", body: oversized, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "rules batch over default limit", path: "/rules/batch", body: "[" + oversized + "]", wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "warnings clear over default limit", path: "/warnings/clear", body: `{"guild_id":"` + strings.Repeat("1", control.DefaultMaxBodyBytes) + `","user_id":"2"}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{
+			name:       "configured limit",
+			path:       "/rules/set",
+			body:       `{"name":"spam-filter","key":"threshold","value":"` + strings.Repeat("x", 100) + `"}`,
+			opts:       []control.ServerOption{control.WithMaxBodyBytes(64)},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "body within configured limit",
+			path:       "/rules/set",
+			body:       `{"name":"spam-filter","key":"threshold","value":"10"}`,
+			opts:       []control.ServerOption{control.WithMaxBodyBytes(64)},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "non-positive limit keeps the default",
+			path:       "/rules/set",
+			body:       oversized,
+			opts:       []control.ServerOption{control.WithMaxBodyBytes(0)},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			handler := control.NewServer(0, bot, discardLogger(), append(tt.opts, control.WithAuthToken(testAuthToken))...).Handler()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+testAuthToken)
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus == http.StatusRequestEntityTooLarge {
+				assert.Contains(t, rec.Body.String(), "too large")
+				assert.False(t, bot.setRuleCalled, "an oversized request must not change anything")
+			}
+		})
+	}
+}
+
 // =============================================================================
 // Server Nil Safety Tests
 // =============================================================================
@@ -1140,3 +1976,894 @@ func Test_RulesSetEndpoint_SuccessResponse(t *testing.T) {
 
 	assert.Equal(t, "ok", response["status"])
 }
+
+// =============================================================================
+// GET /metrics Endpoint Tests
+// =============================================================================
+
+// countingBotInfo adds per-command counts to mockBotInfo.
+type countingBotInfo struct {
+	*mockBotInfo
+	counts map[string]int64
+}
+
+func (c *countingBotInfo) CommandCounts() map[string]int64 {
+	return c.counts
+}
+
+func Test_MetricsEndpoint(t *testing.T) {
+	stats := &control.Stats{
+		StartTime:        time.Now().Add(-90 * time.Second).Unix(),
+		CommandsExecuted: 7,
+		GuildCount:       3,
+		ActiveRules:      2,
+	}
+
+	tests := []struct {
+		name       string
+		bot        control.BotInfo
+		method     string
+		wantStatus int
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "core metrics",
+			bot:        newMockBotInfoWithStats(stats),
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			want: []string{
+				"# TYPE jamesbot_uptime_seconds gauge\njamesbot_uptime_seconds 9",
+				"# TYPE jamesbot_commands_executed_total counter\njamesbot_commands_executed_total 7\n",
+				"jamesbot_guilds 3\n",
+				"jamesbot_active_rules 2\n",
+				"jamesbot_goroutines ",
+				"jamesbot_start_time_seconds ",
+			},
+			notWant: []string{"jamesbot_command_executions_total", "jamesbot_command_latency_seconds", "jamesbot_interaction_"},
+		},
+		{
+			name: "latency percentiles for timed commands",
+			bot: newMockBotInfoWithStats(&control.Stats{
+				StartTime: stats.StartTime,
+				Commands: []control.CommandStats{
+					{Name: "ban", Executions: 3, Latency: &control.LatencyPercentiles{Samples: 4, P50: 12, P95: 250, P99: 1500}},
+					{Name: "ping", Executions: 1},
+				},
+			}),
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			want: []string{
+				"# TYPE jamesbot_command_latency_seconds gauge\n" +
+					"jamesbot_command_latency_seconds{command=\"ban\",quantile=\"0.5\"} 0.012\n" +
+					"jamesbot_command_latency_seconds{command=\"ban\",quantile=\"0.95\"} 0.25\n" +
+					"jamesbot_command_latency_seconds{command=\"ban\",quantile=\"0.99\"} 1.5\n",
+			},
+			notWant: []string{`command="ping",quantile`},
+		},
+		{
+			name: "interaction pool gauges",
+			bot: newMockBotInfoWithStats(&control.Stats{
+				StartTime:    stats.StartTime,
+				Interactions: &control.InteractionStats{Workers: 16, ActiveWorkers: 3, QueueDepth: 7, QueueCapacity: 100},
+			}),
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			want: []string{
+				"jamesbot_interaction_workers 16\n",
+				"jamesbot_interaction_workers_active 3\n",
+				"jamesbot_interaction_queue_depth 7\n",
+				"jamesbot_interaction_queue_capacity 100\n",
+			},
+		},
+		{
+			name:       "per-command counters in name order",
+			bot:        &countingBotInfo{mockBotInfo: newMockBotInfoWithStats(stats), counts: map[string]int64{"warn": 2, "ban": 5}},
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			want: []string{
+				"# TYPE jamesbot_command_executions_total counter\n" +
+					"jamesbot_command_executions_total{command=\"ban\"} 5\n" +
+					"jamesbot_command_executions_total{command=\"warn\"} 2\n",
+			},
+		},
+		{
+			name:       "nil stats",
+			bot:        &mockBotInfo{},
+			method:     http.MethodGet,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "wrong method",
+			bot:        newMockBotInfo(),
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createTestHandler(tt.bot, discardLogger())
+
+			req := httptest.NewRequest(tt.method, "/metrics", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, control.MetricsContentType, rec.Header().Get("Content-Type"))
+			body := rec.Body.String()
+			for _, want := range tt.want {
+				assert.Contains(t, body, want)
+			}
+			for _, notWant := range tt.notWant {
+				assert.NotContains(t, body, notWant)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// POST /rules/batch Endpoint Tests
+// =============================================================================
+
+func Test_RulesBatchEndpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		errs          map[string]error
+		wantStatus    int
+		wantCalls     []string
+		wantSucceeded int
+		wantFailed    int
+		wantErrors    []bool
+		wantWarnings  []bool
+	}{
+		{
+			name:          "applies every item",
+			method:        http.MethodPost,
+			body:          `[{"name":"anti-spam","key":"threshold","value":"5"},{"name":"link-filter","key":"action","value":"warn"}]`,
+			wantStatus:    http.StatusOK,
+			wantCalls:     []string{"anti-spam", "link-filter"},
+			wantSucceeded: 2,
+			wantErrors:    []bool{false, false},
+		},
+		{
+			name:          "failing item does not stop the rest",
+			method:        http.MethodPost,
+			body:          `[{"name":"bogus","key":"k","value":"v"},{"name":"anti-spam","key":"threshold","value":"5"}]`,
+			errs:          map[string]error{"bogus": control.ErrRuleNotFound},
+			wantStatus:    http.StatusOK,
+			wantCalls:     []string{"bogus", "anti-spam"},
+			wantSucceeded: 1,
+			wantFailed:    1,
+			wantErrors:    []bool{true, false},
+		},
+		{
+			name:          "unsaved item succeeds with a warning",
+			method:        http.MethodPost,
+			body:          `[{"name":"anti-spam","key":"threshold","value":"5"}]`,
+			errs:          map[string]error{"anti-spam": control.ErrRuleNotSaved},
+			wantStatus:    http.StatusOK,
+			wantCalls:     []string{"anti-spam"},
+			wantSucceeded: 1,
+			wantErrors:    []bool{false},
+			wantWarnings:  []bool{true},
+		},
+		{
+			name:          "blank name is reported without calling the bot",
+			method:        http.MethodPost,
+			body:          `[{"name":"  ","key":"threshold","value":"5"},{"name":" anti-spam ","key":"threshold","value":"5"}]`,
+			wantStatus:    http.StatusOK,
+			wantCalls:     []string{"anti-spam"},
+			wantSucceeded: 1,
+			wantFailed:    1,
+			wantErrors:    []bool{true, false},
+		},
+		{
+			name:       "empty batch",
+			method:     http.MethodPost,
+			body:       `[]`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid json",
+			method:     http.MethodPost,
+			body:       `{"name":"anti-spam"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			bot.setRuleErrs = tt.errs
+			handler := createTestHandler(bot, discardLogger())
+
+			req := httptest.NewRequest(tt.method, "/rules/batch", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantCalls, bot.setRuleNames)
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp control.BatchSetRulesResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantSucceeded, resp.Succeeded)
+			assert.Equal(t, tt.wantFailed, resp.Failed)
+			require.Len(t, resp.Results, len(tt.wantErrors))
+			for i, wantErr := range tt.wantErrors {
+				assert.Equal(t, wantErr, resp.Results[i].Error != "", "result %d error", i)
+			}
+			for i, wantWarning := range tt.wantWarnings {
+				assert.Equal(t, wantWarning, resp.Results[i].Warning != "", "result %d warning", i)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// POST /warnings/clear Endpoint Tests
+// =============================================================================
+
+func Test_ClearWarningsEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		removed     int
+		botErr      error
+		wantStatus  int
+		wantRemoved int
+		wantGuild   string
+		wantUser    string
+	}{
+		{
+			name:        "clears and reports count",
+			method:      http.MethodPost,
+			body:        `{"guild_id":"g1","user_id":"u1"}`,
+			removed:     3,
+			wantStatus:  http.StatusOK,
+			wantRemoved: 3,
+			wantGuild:   "g1",
+			wantUser:    "u1",
+		},
+		{
+			name:        "ids are trimmed",
+			method:      http.MethodPost,
+			body:        `{"guild_id":" g1 ","user_id":" u1 "}`,
+			wantStatus:  http.StatusOK,
+			wantRemoved: 0,
+			wantGuild:   "g1",
+			wantUser:    "u1",
+		},
+		{
+			name:       "missing user id",
+			method:     http.MethodPost,
+			body:       `{"guild_id":"g1"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid json",
+			method:     http.MethodPost,
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "bot error",
+			method:     http.MethodPost,
+			body:       `{"guild_id":"g1","user_id":"u1"}`,
+			botErr:     errors.New("store unavailable"),
+			wantStatus: http.StatusInternalServerError,
+			wantGuild:  "g1",
+			wantUser:   "u1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newMockBotInfo()
+			bot.clearWarningsRemoved = tt.removed
+			bot.clearWarningsErr = tt.botErr
+			handler := createTestHandler(bot, discardLogger())
+
+			req := httptest.NewRequest(tt.method, "/warnings/clear", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+testAuthToken)
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantGuild, bot.clearWarningsGuild)
+			assert.Equal(t, tt.wantUser, bot.clearWarningsUser)
+
+			if tt.wantStatus == http.StatusOK {
+				var resp control.ClearWarningsResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantRemoved, resp.Removed)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Guild-Scoped Rules Tests
+// =============================================================================
+
+// guildBotInfo adds per-guild rule overrides to mockBotInfo.
+type guildBotInfo struct {
+	*mockBotInfo
+	guildRules map[string][]control.Rule
+	setGuilds  []string
+}
+
+func (g *guildBotInfo) GuildRules(guildID string) []control.Rule {
+	return g.guildRules[guildID]
+}
+
+func (g *guildBotInfo) SetGuildRule(guildID, name, key, value string) error {
+	g.setGuilds = append(g.setGuilds, guildID)
+	return g.SetRule(name, key, value)
+}
+
+func Test_RulesEndpoint_GuildFilter(t *testing.T) {
+	global := []control.Rule{{Name: "anti-spam", Key: "threshold", Value: "5"}}
+	scoped := []control.Rule{{Name: "anti-spam", Key: "threshold", Value: "3", Guild: "guild-1"}}
+
+	tests := []struct {
+		name       string
+		bot        control.BotInfo
+		query      string
+		wantStatus int
+		want       []control.Rule
+	}{
+		{
+			name:       "no filter lists global rules",
+			bot:        &guildBotInfo{mockBotInfo: newMockBotInfoWithRules(global)},
+			wantStatus: http.StatusOK,
+			want:       global,
+		},
+		{
+			name:       "guild filter lists the guild's rules",
+			bot:        &guildBotInfo{mockBotInfo: newMockBotInfoWithRules(global), guildRules: map[string][]control.Rule{"guild-1": scoped}},
+			query:      "?guild=guild-1",
+			wantStatus: http.StatusOK,
+			want:       scoped,
+		},
+		{
+			name:       "unknown guild is an empty array",
+			bot:        &guildBotInfo{mockBotInfo: newMockBotInfoWithRules(global)},
+			query:      "?guild=guild-9",
+			wantStatus: http.StatusOK,
+			want:       []control.Rule{},
+		},
+		{
+			name:       "bot without guild support",
+			bot:        newMockBotInfoWithRules(global),
+			query:      "?guild=guild-1",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createTestHandler(tt.bot, discardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/rules"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []control.Rule
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_SetRuleEndpoint_Guild(t *testing.T) {
+	tests := []struct {
+		name       string
+		guildAware bool
+		path       string
+		body       string
+		wantStatus int
+		wantGuilds []string
+	}{
+		{
+			name:       "guild-scoped set",
+			guildAware: true,
+			path:       "/rules/set",
+			body:       `{"guild":" guild-1 ","name":"anti-spam","key":"threshold","value":"3"}`,
+			wantStatus: http.StatusOK,
+			wantGuilds: []string{"guild-1"},
+		},
+		{
+			name:       "global set does not use the guild path",
+			guildAware: true,
+			path:       "/rules/set",
+			body:       `{"name":"anti-spam","key":"threshold","value":"3"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "guild-scoped set on a bot without guild support",
+			path:       "/rules/set",
+			body:       `{"guild":"guild-1","name":"anti-spam","key":"threshold","value":"3"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "batch mixes global and guild items",
+			guildAware: true,
+			path:       "/rules/batch",
+			body:       `[{"name":"anti-spam","key":"threshold","value":"5"},{"guild":"guild-2","name":"anti-spam","key":"threshold","value":"2"}]`,
+			wantStatus: http.StatusOK,
+			wantGuilds: []string{"guild-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockBotInfo()
+			bot := control.BotInfo(mock)
+			guildBot := &guildBotInfo{mockBotInfo: mock}
+			if tt.guildAware {
+				bot = guildBot
+			}
+			handler := createTestHandler(bot, discardLogger())
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantGuilds, guildBot.setGuilds)
+		})
+	}
+}
+
+// =============================================================================
+// Command Toggle Tests
+// =============================================================================
+
+// commandBotInfo adds runtime command toggles to mockBotInfo.
+type commandBotInfo struct {
+	*mockBotInfo
+	states []control.CommandState
+}
+
+func (c *commandBotInfo) CommandStates() []control.CommandState {
+	return c.states
+}
+
+func (c *commandBotInfo) SetCommandEnabled(name string, enabled bool) error {
+	for i := range c.states {
+		if c.states[i].Name == name {
+			c.states[i].Enabled = enabled
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", control.ErrCommandNotFound, name)
+}
+
+func newCommandBotInfo() *commandBotInfo {
+	return &commandBotInfo{
+		mockBotInfo: newMockBotInfo(),
+		states: []control.CommandState{
+			{Name: "ban", Description: "Ban a member", Enabled: true},
+			{Name: "ping", Description: "Check latency", Enabled: true},
+		},
+	}
+}
+
+func Test_CommandsEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		bot        control.BotInfo
+		method     string
+		wantStatus int
+		want       []control.CommandState
+	}{
+		{
+			name:       "lists command states",
+			bot:        newCommandBotInfo(),
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			want:       newCommandBotInfo().states,
+		},
+		{
+			name:       "no commands is an empty array",
+			bot:        &commandBotInfo{mockBotInfo: newMockBotInfo()},
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			want:       []control.CommandState{},
+		},
+		{
+			name:       "bot without toggles",
+			bot:        newMockBotInfo(),
+			method:     http.MethodGet,
+			wantStatus: http.StatusNotImplemented,
+		},
+		{
+			name:       "wrong method",
+			bot:        newCommandBotInfo(),
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createTestHandler(tt.bot, discardLogger())
+
+			req := httptest.NewRequest(tt.method, "/commands", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []control.CommandState
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_SetCommandEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		unsupported bool
+		wantStatus  int
+		wantBan     bool
+	}{
+		{name: "disable", method: http.MethodPost, path: "/commands/ban/disable", wantStatus: http.StatusOK, wantBan: false},
+		{name: "enable", method: http.MethodPost, path: "/commands/ban/enable", wantStatus: http.StatusOK, wantBan: true},
+		{name: "unknown command", method: http.MethodPost, path: "/commands/nope/disable", wantStatus: http.StatusNotFound, wantBan: true},
+		{name: "unknown action", method: http.MethodPost, path: "/commands/ban/delete", wantStatus: http.StatusNotFound, wantBan: true},
+		{name: "wrong method", method: http.MethodGet, path: "/commands/ban/disable", wantStatus: http.StatusMethodNotAllowed, wantBan: true},
+		{name: "bot without toggles", method: http.MethodPost, path: "/commands/ban/disable", unsupported: true, wantStatus: http.StatusNotImplemented, wantBan: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newCommandBotInfo()
+			var info control.BotInfo = bot
+			if tt.unsupported {
+				info = bot.mockBotInfo
+			}
+			if tt.path == "/commands/ban/enable" {
+				require.NoError(t, bot.SetCommandEnabled("ban", false))
+			}
+			handler := createTestHandler(info, discardLogger())
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+testAuthToken)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantBan, bot.states[0].Enabled)
+		})
+	}
+}
+
+// =============================================================================
+// Maintenance Mode Tests
+// =============================================================================
+
+// maintenanceBotInfo adds maintenance mode to mockBotInfo.
+type maintenanceBotInfo struct {
+	*mockBotInfo
+	state control.MaintenanceState
+}
+
+func (m *maintenanceBotInfo) SetMaintenance(enabled bool) control.MaintenanceState {
+	m.state = control.MaintenanceState{Enabled: enabled}
+	if enabled {
+		m.state.Since = 1700000000
+	}
+	return m.state
+}
+
+func Test_SetMaintenanceEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		unsupported bool
+		wantStatus  int
+		want        control.MaintenanceState
+	}{
+		{name: "enable", method: http.MethodPost, path: "/maintenance/enable", wantStatus: http.StatusOK,
+			want: control.MaintenanceState{Enabled: true, Since: 1700000000}},
+		{name: "disable", method: http.MethodPost, path: "/maintenance/disable", wantStatus: http.StatusOK},
+		{name: "unknown action", method: http.MethodPost, path: "/maintenance/pause", wantStatus: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodGet, path: "/maintenance/enable", wantStatus: http.StatusMethodNotAllowed},
+		{name: "bot without maintenance mode", method: http.MethodPost, path: "/maintenance/enable", unsupported: true,
+			wantStatus: http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &maintenanceBotInfo{mockBotInfo: newMockBotInfo()}
+			var info control.BotInfo = bot
+			if tt.unsupported {
+				info = bot.mockBotInfo
+			}
+			handler := createTestHandler(info, discardLogger())
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+testAuthToken)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.want, bot.state)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got control.MaintenanceState
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_StateChangingEndpoints_RequireAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverToken   string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "no token configured", serverToken: "", authorization: "Bearer anything", wantStatus: http.StatusForbidden},
+		{name: "missing header", serverToken: testAuthToken, wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", serverToken: testAuthToken, authorization: "Bearer nope", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		for _, path := range []string{"/warnings/clear", "/commands/ban/disable", "/maintenance/enable"} {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				commands := newCommandBotInfo()
+				maintenance := &maintenanceBotInfo{mockBotInfo: commands.mockBotInfo}
+				var bot control.BotInfo = commands
+				if path == "/maintenance/enable" {
+					bot = maintenance
+				}
+				handler := control.NewServer(0, bot, discardLogger(), control.WithAuthToken(tt.serverToken)).Handler()
+
+				req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"guild_id":"1","user_id":"2"}`))
+				req.Header.Set("Content-Type", "application/json")
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+				assert.Empty(t, commands.clearWarningsUser, "unauthenticated requests must not clear warnings")
+				assert.True(t, commands.states[0].Enabled, "unauthenticated requests must not toggle commands")
+				assert.False(t, maintenance.state.Enabled, "unauthenticated requests must not change maintenance mode")
+			})
+		}
+	}
+}
+
+// =============================================================================
+// Command Reload Tests
+// =============================================================================
+
+// reloaderBotInfo adds command reloading to mockBotInfo.
+type reloaderBotInfo struct {
+	*mockBotInfo
+	reloads int
+	err     error
+}
+
+func (r *reloaderBotInfo) ReloadCommands() (*control.CommandReloadResult, error) {
+	r.reloads++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &control.CommandReloadResult{Created: []string{"poll"}, Deleted: []string{"echo"}, Unchanged: 3}, nil
+}
+
+func Test_ReloadCommandsEndpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		serverToken   string
+		authorization string
+		unsupported   bool
+		reloadErr     error
+		wantStatus    int
+		wantReloads   int
+	}{
+		{name: "reload", method: http.MethodPost, serverToken: testAuthToken, authorization: "Bearer " + testAuthToken,
+			wantStatus: http.StatusOK, wantReloads: 1},
+		{name: "no token configured", method: http.MethodPost, authorization: "Bearer anything", wantStatus: http.StatusForbidden},
+		{name: "missing token", method: http.MethodPost, serverToken: testAuthToken, wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodPost, serverToken: testAuthToken, authorization: "Bearer nope",
+			wantStatus: http.StatusUnauthorized},
+		{name: "wrong method", method: http.MethodGet, serverToken: testAuthToken, authorization: "Bearer " + testAuthToken,
+			wantStatus: http.StatusMethodNotAllowed},
+		{name: "bot without reloading", method: http.MethodPost, serverToken: testAuthToken, authorization: "Bearer " + testAuthToken,
+			unsupported: true, wantStatus: http.StatusNotImplemented},
+		{name: "reload fails", method: http.MethodPost, serverToken: testAuthToken, authorization: "Bearer " + testAuthToken,
+			reloadErr: errors.New("discord unavailable"), wantStatus: http.StatusInternalServerError, wantReloads: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &reloaderBotInfo{mockBotInfo: newMockBotInfo(), err: tt.reloadErr}
+			var info control.BotInfo = bot
+			if tt.unsupported {
+				info = bot.mockBotInfo
+			}
+			handler := control.NewServer(0, info, discardLogger(), control.WithAuthToken(tt.serverToken)).Handler()
+
+			req := httptest.NewRequest(tt.method, "/commands/reload", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantReloads, bot.reloads)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got control.CommandReloadResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, control.CommandReloadResult{Created: []string{"poll"}, Deleted: []string{"echo"}, Unchanged: 3}, got)
+		})
+	}
+}
+
+// =============================================================================
+// POST /rules/test Endpoint Tests
+// =============================================================================
+
+// ruleTesterBotInfo is a mockBotInfo that can test rules, matching "darn"
+// with the word-filter.
+type ruleTesterBotInfo struct {
+	*mockBotInfo
+	testedGuild string
+	testedInput string
+}
+
+func (r *ruleTesterBotInfo) TestRule(guildID, name, input string) (*control.RuleTestResult, error) {
+	r.testedGuild = guildID
+	r.testedInput = input
+	switch name {
+	case "word-filter":
+		result := &control.RuleTestResult{Name: name, Guild: guildID}
+		if strings.Contains(input, "darn") {
+			result.Matched, result.Match, result.Action = true, "darn", "delete"
+		}
+		return result, nil
+	case "welcome":
+		return nil, fmt.Errorf("%w: %q", control.ErrRuleNotTestable, name)
+	case "broken":
+		return nil, errors.New("rule set unavailable")
+	default:
+		return nil, fmt.Errorf("%w: %q", control.ErrRuleNotFound, name)
+	}
+}
+
+func Test_TestRuleEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantResult *control.RuleTestResult
+		wantGuild  string
+	}{
+		{
+			name:       "match",
+			method:     http.MethodPost,
+			body:       `{"name":"word-filter","input":"well darn it"}`,
+			wantStatus: http.StatusOK,
+			wantResult: &control.RuleTestResult{Name: "word-filter", Matched: true, Match: "darn", Action: "delete"},
+		},
+		{
+			name:       "no match in a guild",
+			method:     http.MethodPost,
+			body:       `{"guild":" g1 ","name":" word-filter ","input":"hello"}`,
+			wantStatus: http.StatusOK,
+			wantResult: &control.RuleTestResult{Name: "word-filter", Guild: "g1"},
+			wantGuild:  "g1",
+		},
+		{
+			name:       "unknown rule",
+			method:     http.MethodPost,
+			body:       `{"name":"nope","input":"hello"}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "not a content rule",
+			method:     http.MethodPost,
+			body:       `{"name":"welcome","input":"hello"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing name",
+			method:     http.MethodPost,
+			body:       `{"input":"hello"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "bot error",
+			method:     http.MethodPost,
+			body:       `{"name":"broken","input":"hello"}`,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &ruleTesterBotInfo{mockBotInfo: newMockBotInfo()}
+			handler := createTestHandler(bot, discardLogger())
+
+			req := httptest.NewRequest(tt.method, "/rules/test", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantResult != nil {
+				var result control.RuleTestResult
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+				assert.Equal(t, *tt.wantResult, result)
+				assert.Equal(t, tt.wantGuild, bot.testedGuild)
+			}
+			assert.False(t, bot.setRuleCalled, "testing a rule must not change it")
+		})
+	}
+}
+
+func Test_TestRuleEndpoint_NotImplemented(t *testing.T) {
+	handler := createTestHandler(newMockBotInfo(), discardLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/rules/test", strings.NewReader(`{"name":"word-filter","input":"darn"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
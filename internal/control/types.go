@@ -1,10 +1,56 @@
 // Package control provides the HTTP control API for JamesBot.
 package control
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
-// ErrRuleNotFound is returned when a rule is not found.
-var ErrRuleNotFound = errors.New("rule not found")
+var (
+	// ErrRuleNotFound is returned when a rule is not found.
+	ErrRuleNotFound = errors.New("rule not found")
+
+	// ErrInvalidRule is returned when a rule key is not accepted or its value fails validation.
+	ErrInvalidRule = errors.New("invalid rule setting")
+
+	// ErrCommandNotFound is returned when a command is not registered.
+	ErrCommandNotFound = errors.New("command not found")
+
+	// ErrRuleNotTestable is returned when a rule cannot be tested against a
+	// message because it does not match message content.
+	ErrRuleNotTestable = errors.New("rule does not match message content")
+
+	// ErrNotBanned is returned when unbanning a user who is not banned.
+	ErrNotBanned = errors.New("user is not banned")
+
+	// ErrMemberNotFound is returned when a moderation action names a user who
+	// is not a member of the guild, or does not exist.
+	ErrMemberNotFound = errors.New("member not found")
+
+	// ErrNoPunishment is returned when cancelling a temporary punishment that
+	// is not in effect.
+	ErrNoPunishment = errors.New("no punishment of that type in effect")
+
+	// ErrIntentNotRequested is returned when a rule setting would need a
+	// privileged gateway intent the bot did not request on connecting, so
+	// the rule could not act until the bot is restarted.
+	ErrIntentNotRequested = errors.New("rule setting needs a gateway intent the bot did not request")
+
+	// ErrRuleNotSaved is returned when a rule setting took effect but could
+	// not be written to the rules file, so it will not survive a restart.
+	ErrRuleNotSaved = errors.New("setting applied but not saved")
+
+	// ErrUnauthorized is returned when a request to an authenticated
+	// endpoint, such as a moderation endpoint, lacks the control API's auth
+	// token, or the server has none configured.
+	ErrUnauthorized = errors.New("control API auth token missing or rejected")
+)
+
+// Health is the response to a health check. Status is always "ok"; a server
+// that answers at all is healthy.
+type Health struct {
+	Status string `json:"status"`
+}
 
 // Stats contains bot statistics.
 type Stats struct {
@@ -13,15 +59,368 @@ type Stats struct {
 	CommandsExecuted int64  `json:"commands_executed"`
 	GuildCount       int    `json:"guild_count"`
 	ActiveRules      int    `json:"active_rules"`
+
+	// LifetimeCommandsExecuted counts commands executed over every run of
+	// the bot, while CommandsExecuted counts those since it started. It is
+	// omitted by bots that do not keep counts across restarts.
+	LifetimeCommandsExecuted int64 `json:"lifetime_commands_executed,omitempty"`
+
+	// ConnectedSince is when the gateway connection was last established or
+	// resumed, in Unix seconds, or zero while the bot is disconnected. Unlike
+	// StartTime, it resets whenever the bot reconnects.
+	ConnectedSince int64 `json:"connected_since"`
+
+	// LastCommandAt is when a command last executed, in Unix seconds, or zero
+	// if none has executed since the bot started.
+	LastCommandAt int64 `json:"last_command_at"`
+
+	// Commands lists per-command execution counts and latency percentiles in
+	// name order. It is omitted by bots that do not track commands.
+	Commands []CommandStats `json:"commands,omitempty"`
+
+	// Interactions describes the interaction worker pool. It is omitted until
+	// the bot has started.
+	Interactions *InteractionStats `json:"interactions,omitempty"`
+
+	// Build describes the running binary. It is omitted by bots that do not
+	// report it.
+	Build *BuildInfo `json:"build,omitempty"`
+
+	// Maintenance reports whether the bot is in maintenance mode. It is
+	// omitted by bots that do not support maintenance mode.
+	Maintenance *MaintenanceState `json:"maintenance,omitempty"`
+
+	// Intents describes the privileged gateway intents the bot connected
+	// with. It is omitted by bots that do not report them.
+	Intents *GatewayIntents `json:"intents,omitempty"`
+
+	// RulesFile reports whether rule settings are being saved. It is omitted
+	// by bots that do not keep settings across restarts.
+	RulesFile *RulesFileState `json:"rules_file,omitempty"`
+}
+
+// GatewayIntents names privileged gateway intents as the Developer Portal
+// does, such as "Message Content".
+type GatewayIntents struct {
+	// Requested lists the privileged intents the bot requested when it
+	// connected. Intents are only requested then, so it does not change
+	// while the bot runs.
+	Requested []string `json:"requested"`
+
+	// Missing lists privileged intents the current rule settings need but
+	// the bot did not request. Rules needing them do nothing until the bot
+	// is restarted.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// RulesFileState describes the file rule settings are saved to.
+type RulesFileState struct {
+	// Path is the rules file, as configured by rules.file.
+	Path string `json:"path"`
+
+	// Error explains why settings changed through the control API are not
+	// being saved, when the file could not be loaded at startup or the last
+	// save failed. It is empty while saving works.
+	Error string `json:"error,omitempty"`
+}
+
+// MaintenanceState describes whether the bot is in maintenance mode, during
+// which members' commands are held back.
+type MaintenanceState struct {
+	Enabled bool `json:"enabled"`
+
+	// Since is when maintenance mode was turned on, in Unix seconds, or zero
+	// while it is off.
+	Since int64 `json:"since,omitempty"`
+}
+
+// BuildInfo describes the binary a bot is running.
+type BuildInfo struct {
+	Version string `json:"version"`
+
+	// Commit is the revision the binary was built from, or empty if unknown.
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// InteractionStats is a snapshot of the interaction worker pool.
+type InteractionStats struct {
+	Workers       int `json:"workers"`
+	ActiveWorkers int `json:"active_workers"`
+	QueueDepth    int `json:"queue_depth"`
+	QueueCapacity int `json:"queue_capacity"`
+}
+
+// CommandStats describes how one command has performed since the bot started.
+// LifetimeExecutions also counts earlier runs of the bot, and is omitted by
+// bots that do not keep counts across restarts.
+type CommandStats struct {
+	Name               string `json:"name"`
+	Executions         int64  `json:"executions"`
+	LifetimeExecutions int64  `json:"lifetime_executions,omitempty"`
+
+	// Latency is omitted until the command has been timed at least once.
+	Latency *LatencyPercentiles `json:"latency_ms,omitempty"`
+}
+
+// DefaultTopCommands and MaxTopCommands are how many commands GET
+// /stats/commands/top returns when n is not given, and at most.
+const (
+	DefaultTopCommands = 10
+	MaxTopCommands     = 100
+)
+
+// LatencyPercentiles holds estimated execution time percentiles in milliseconds.
+// Samples counts every timed execution, including failed ones.
+type LatencyPercentiles struct {
+	Samples uint64  `json:"samples"`
+	P50     float64 `json:"p50"`
+	P95     float64 `json:"p95"`
+	P99     float64 `json:"p99"`
 }
 
 // Rule represents a moderation rule.
+//
+// Rule is the JSON contract for GET /rules and `rules list --json`, consumed
+// by external tools. Every field is always emitted, even when empty: name,
+// description, key, value, and guild are strings and enabled is a boolean.
+// Lists of rules are always JSON arrays, never null. Do not rename fields or
+// add omitempty without treating it as a breaking change.
+//
+// Guild is the guild whose override supplies Value, or empty when Value is
+// the global setting.
 type Rule struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Enabled     bool   `json:"enabled"`
 	Key         string `json:"key"`
 	Value       string `json:"value"`
+	Guild       string `json:"guild"`
+}
+
+// Overview is the response to GET /overview: the bot's stats and rules
+// together, so a polling dashboard needs one request instead of two. Rules
+// follows the same contract as GET /rules and is never null.
+type Overview struct {
+	Stats *Stats `json:"stats"`
+	Rules []Rule `json:"rules"`
+}
+
+// SetRuleResult reports the outcome of one item in a batch rule update.
+// Error is empty when the item was applied; Warning is set when it was
+// applied but not saved.
+type SetRuleResult struct {
+	Guild   string `json:"guild,omitempty"`
+	Name    string `json:"name"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// SetRuleResponse is the response to a rule update that was applied.
+// Warning is set when the setting took effect but was not saved to the rules
+// file, so it will not survive a restart.
+type SetRuleResponse struct {
+	Status  string `json:"status"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// BatchSetRulesResponse summarizes a batch rule update.
+type BatchSetRulesResponse struct {
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Results   []SetRuleResult `json:"results"`
+}
+
+// TestRuleRequest represents the JSON payload for testing a rule against a
+// sample message. Guild, when set, tests the rule as configured in that guild.
+type TestRuleRequest struct {
+	Guild string `json:"guild,omitempty"`
+	Name  string `json:"name"`
+	Input string `json:"input"`
+}
+
+// RuleTestResult reports what a content rule would do with a sample message.
+// Match is the word or link that matched and Action the action the rule
+// would take; both are empty when Matched is false. Enabled reports whether
+// the rule is currently on, since a disabled rule is tested as if it were.
+type RuleTestResult struct {
+	Name    string `json:"name"`
+	Guild   string `json:"guild,omitempty"`
+	Enabled bool   `json:"enabled"`
+	Matched bool   `json:"matched"`
+	Match   string `json:"match,omitempty"`
+	Action  string `json:"action,omitempty"`
+}
+
+// ClearWarningsResponse reports the outcome of clearing a member's warnings.
+type ClearWarningsResponse struct {
+	Removed int `json:"removed"`
+}
+
+// Limits on moderation actions, matching Discord's own and those of the
+// /ban and /mute slash commands.
+const (
+	MaxBanDeleteDays = 7
+	MinMuteDuration  = time.Minute
+	MaxMuteDuration  = 28 * 24 * time.Hour
+)
+
+// ModerationRequest represents the JSON payload for POST /moderation/ban,
+// /moderation/kick, and /moderation/mute. Reason, when set, is recorded in the
+// guild's audit log. DeleteDays is only used by ban, and Duration, a Go
+// duration such as "1h" or "30m", is required by mute and only used by it.
+type ModerationRequest struct {
+	GuildID    string `json:"guild_id"`
+	UserID     string `json:"user_id"`
+	Reason     string `json:"reason,omitempty"`
+	DeleteDays int    `json:"delete_days,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+}
+
+// ModerationResponse reports a moderation action that was taken.
+type ModerationResponse struct {
+	Action  string `json:"action"`
+	GuildID string `json:"guild_id"`
+	UserID  string `json:"user_id"`
+}
+
+// MaxUnbanUsers bounds how many users one POST /moderation/unban request may
+// unban, so that the request finishes within the server's write timeout even
+// when Discord rate limits the unbans. Longer lists take several requests.
+const MaxUnbanUsers = 25
+
+// UnbanRequest represents the JSON payload for unbanning users from a guild.
+// Reason, when set, is recorded in the guild's audit log.
+type UnbanRequest struct {
+	GuildID string   `json:"guild_id"`
+	UserIDs []string `json:"user_ids"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// UnbanResult reports the outcome of unbanning one user.
+// Error is empty when the user was unbanned.
+type UnbanResult struct {
+	UserID string `json:"user_id"`
+	Error  string `json:"error,omitempty"`
+}
+
+// UnbanResponse summarizes an unban request.
+type UnbanResponse struct {
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []UnbanResult `json:"results"`
+}
+
+// PunishmentMute is the Type of a Punishment that is a member timeout.
+const PunishmentMute = "mute"
+
+// Punishment is a temporary moderation action still in effect, which Discord
+// lifts on its own at ExpiresAt. Reason is the one recorded in the guild's
+// audit log, empty if there was none or the bot cannot read the audit log.
+type Punishment struct {
+	GuildID   string    `json:"guild_id"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CancelPunishmentRequest represents the JSON payload for POST
+// /punishments/cancel. Type is the Type of the Punishment to lift, and
+// Reason, when set, is recorded in the guild's audit log.
+type CancelPunishmentRequest struct {
+	GuildID string `json:"guild_id"`
+	UserID  string `json:"user_id"`
+	Type    string `json:"type"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// CommandState describes a registered slash command and whether it may run.
+type CommandState struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// CommandReloadResult reports how reloading the bot's commands changed the
+// slash commands registered with Discord, by command name.
+type CommandReloadResult struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Deleted   []string `json:"deleted"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// CommandCounter is implemented by bots that count executions per command.
+// The metrics endpoint reports per-command counters when the bot implements it.
+type CommandCounter interface {
+	CommandCounts() map[string]int64
+}
+
+// GuildRuleManager is implemented by bots that support per-guild rule
+// overrides. Without it, requests naming a guild are rejected.
+type GuildRuleManager interface {
+	GuildRules(guildID string) []Rule
+	SetGuildRule(guildID, name, key, value string) error
+}
+
+// RuleTester is implemented by bots that can test content rules against a
+// sample message without acting on it. Without it, POST /rules/test is not
+// available.
+type RuleTester interface {
+	TestRule(guildID, name, input string) (*RuleTestResult, error)
+}
+
+// CommandManager is implemented by bots whose commands can be enabled and
+// disabled at runtime. Without it, the /commands endpoints are not available.
+type CommandManager interface {
+	CommandStates() []CommandState
+	SetCommandEnabled(name string, enabled bool) error
+}
+
+// CommandReloader is implemented by bots that can rebuild their commands and
+// sync them with Discord without restarting. Without it, POST
+// /commands/reload is not available.
+type CommandReloader interface {
+	ReloadCommands() (*CommandReloadResult, error)
+}
+
+// MaintenanceManager is implemented by bots that can be put into maintenance
+// mode at runtime. Without it, the /maintenance endpoints are not available.
+type MaintenanceManager interface {
+	SetMaintenance(enabled bool) MaintenanceState
+}
+
+// Moderator is implemented by bots that can take moderation actions outside
+// of slash commands. Without it, the /moderation endpoints are not available.
+type Moderator interface {
+	Ban(guildID, userID, reason string, deleteDays int) error
+	Kick(guildID, userID, reason string) error
+	Mute(guildID, userID string, duration time.Duration, reason string) error
+	Unban(guildID, userID, reason string) error
+}
+
+// PunishmentLister is implemented by bots that can report the temporary
+// punishments in effect. Without it, GET /punishments is not available.
+type PunishmentLister interface {
+	// Punishments returns the punishments in effect in guildID, or in every
+	// guild when guildID is empty, soonest to expire first.
+	Punishments(guildID string) []Punishment
+}
+
+// PunishmentCanceler is implemented by bots that can lift a temporary
+// punishment before it expires. Without it, POST /punishments/cancel is not
+// available.
+type PunishmentCanceler interface {
+	// CancelPunishment lifts userID's punishment of type kind in guildID now,
+	// recording reason, if set, in the guild's audit log. It returns
+	// ErrNoPunishment if none is in effect.
+	CancelPunishment(guildID, userID, kind, reason string) error
 }
 
 // BotInfo is the interface that the bot must implement to provide info to the control API.
@@ -29,4 +428,5 @@ type BotInfo interface {
 	Stats() *Stats
 	Rules() []Rule
 	SetRule(name, key, value string) error
+	ClearWarnings(guildID, userID string) (removed int, err error)
 }
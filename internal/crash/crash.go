@@ -0,0 +1,148 @@
+// Package crash reports panics that would otherwise bring the bot down with
+// nothing but the runtime's output on stderr.
+package crash
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"jamesbot/internal/buildinfo"
+
+	"github.com/rs/zerolog"
+)
+
+// maxDumpBytes caps the goroutine dump included in a report.
+const maxDumpBytes = 8 << 20
+
+// Reporter logs panics that reach the top of a goroutine with a dump of every
+// goroutine, and appends them to a crash file, if one is set, for operators
+// to attach to a support request. Panics in command handlers are recovered
+// per command by middleware.Recovery and never reach it.
+type Reporter struct {
+	logger     zerolog.Logger
+	configPath string
+	file       string
+}
+
+// NewReporter creates a Reporter that logs to logger and appends reports to
+// file, unless it is empty. configPath, the config file the bot was started
+// with, is named in each report.
+func NewReporter(logger zerolog.Logger, configPath, file string) *Reporter {
+	return &Reporter{logger: logger, configPath: configPath, file: file}
+}
+
+// Install makes the runtime dump every goroutine, rather than only the one
+// that panicked, when a panic goes unrecovered anywhere in the process, and
+// appends that dump to the crash file as well as stderr. Panics in goroutines
+// without a Guard, such as those handling Discord events, get the runtime's
+// dump without the Header.
+func (r *Reporter) Install() error {
+	debug.SetTraceback("all")
+	if r.file == "" {
+		return nil
+	}
+
+	f, err := openCrashFile(r.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		return fmt.Errorf("failed to set crash output: %w", err)
+	}
+	return nil
+}
+
+// Guard reports a panic in the calling goroutine and panics again with the
+// same value, so the process still exits as it would have. It must be
+// deferred directly, at the top of the goroutine:
+//
+//	defer reporter.Guard()
+func (r *Reporter) Guard() {
+	v := recover()
+	if v == nil {
+		return
+	}
+	r.Report(v)
+	panic(v)
+}
+
+// Report logs v, the value a goroutine panicked with, with a dump of every
+// goroutine, and writes the Header and v to the crash file, if set. With
+// Install, the runtime's own dump follows them there when the process exits.
+func (r *Reporter) Report(v any) {
+	build := buildinfo.Get()
+	r.logger.Error().
+		Interface("panic", v).
+		Str("version", build.Version).
+		Str("commit", build.Commit).
+		Str("config_path", r.configPath).
+		Bytes("goroutines", dumpGoroutines()).
+		Msg("unrecovered panic, exiting")
+
+	if r.file == "" {
+		return
+	}
+	f, err := openCrashFile(r.file)
+	if err != nil {
+		r.logger.Error().Err(err).Str("path", r.file).Msg("failed to write crash report")
+		return
+	}
+	defer f.Close()
+	if err := r.writeHeader(f, v, time.Now()); err != nil {
+		r.logger.Error().Err(err).Str("path", r.file).Msg("failed to write crash report")
+	}
+}
+
+// Header describes the build and configuration that crashed at now, for
+// support requests. Reports include it ahead of the panic value.
+func (r *Reporter) Header(now time.Time) string {
+	build := buildinfo.Get()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "jamesbot %s", build.Version)
+	if build.Commit != "" {
+		fmt.Fprintf(&sb, " (%s)", build.Commit)
+	}
+	fmt.Fprintf(&sb, " %s %s/%s\n", build.GoVersion, build.OS, build.Arch)
+	configPath := r.configPath
+	if configPath == "" {
+		configPath = "none (environment variables only)"
+	}
+	fmt.Fprintf(&sb, "Config: %s\n", configPath)
+	fmt.Fprintf(&sb, "Time: %s\n", now.UTC().Format(time.RFC3339))
+	return sb.String()
+}
+
+// writeHeader starts a crash report in w with the Header and the panic value.
+func (r *Reporter) writeHeader(w io.Writer, v any, now time.Time) error {
+	_, err := fmt.Fprintf(w, "=== crash report ===\n%sPanic: %v\n\n", r.Header(now), v)
+	return err
+}
+
+// openCrashFile opens path for appending reports, creating it if needed. The
+// dump may include message content, so only the owner can read it.
+func openCrashFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open crash file: %w", err)
+	}
+	return f, nil
+}
+
+// dumpGoroutines returns the stacks of every goroutine, growing the buffer
+// until they fit or maxDumpBytes is reached.
+func dumpGoroutines() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxDumpBytes {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
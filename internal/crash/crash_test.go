@@ -0,0 +1,121 @@
+package crash_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jamesbot/internal/buildinfo"
+	"jamesbot/internal/crash"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Header Tests
+// ============================================================================
+
+func Test_Reporter_Header(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		configPath string
+		want       []string
+	}{
+		{
+			name:       "config file",
+			configPath: "/etc/jamesbot/config.yaml",
+			want:       []string{"jamesbot " + buildinfo.Version, "Config: /etc/jamesbot/config.yaml\n", "Time: 2024-01-01T12:00:00Z\n"},
+		},
+		{
+			name: "environment variables only",
+			want: []string{"Config: none (environment variables only)\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := crash.NewReporter(zerolog.Nop(), tt.configPath, "").Header(now)
+
+			for _, want := range tt.want {
+				assert.Contains(t, header, want)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Guard Tests
+// ============================================================================
+
+func Test_Reporter_Guard(t *testing.T) {
+	var logs bytes.Buffer
+	file := filepath.Join(t.TempDir(), "crash.log")
+	reporter := crash.NewReporter(zerolog.New(&logs), "config.yaml", file)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer reporter.Guard()
+		panic("boom")
+	}, "the panic should continue once reported")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "unrecovered panic, exiting", entry["message"])
+	assert.Equal(t, "boom", entry["panic"])
+	assert.Equal(t, buildinfo.Version, entry["version"])
+	assert.Equal(t, "config.yaml", entry["config_path"])
+	assert.Contains(t, entry["goroutines"], "Test_Reporter_Guard", "the dump should include the panicking goroutine")
+
+	report, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(report), "=== crash report ===\njamesbot "+buildinfo.Version))
+	assert.Contains(t, string(report), "Config: config.yaml\n")
+	assert.Contains(t, string(report), "Panic: boom\n")
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "reports may hold message content")
+}
+
+func Test_Reporter_Guard_NoPanic(t *testing.T) {
+	var logs bytes.Buffer
+	file := filepath.Join(t.TempDir(), "crash.log")
+	reporter := crash.NewReporter(zerolog.New(&logs), "config.yaml", file)
+
+	assert.NotPanics(t, func() {
+		defer reporter.Guard()
+	})
+
+	assert.Empty(t, logs.String())
+	assert.NoFileExists(t, file, "no crash file should be written without a crash")
+}
+
+func Test_Reporter_Report_Appends(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "crash.log")
+	reporter := crash.NewReporter(zerolog.Nop(), "", file)
+
+	reporter.Report("first")
+	reporter.Report("second")
+
+	report, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(report), "=== crash report ==="))
+	assert.Less(t, strings.Index(string(report), "Panic: first"), strings.Index(string(report), "Panic: second"))
+}
+
+func Test_Reporter_Report_UnwritableFile(t *testing.T) {
+	var logs bytes.Buffer
+	reporter := crash.NewReporter(zerolog.New(&logs), "", filepath.Join(t.TempDir(), "missing", "crash.log"))
+
+	reporter.Report("boom")
+
+	assert.Contains(t, logs.String(), "unrecovered panic, exiting", "the panic should still be logged")
+	assert.Contains(t, logs.String(), "failed to write crash report")
+}
@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/handler"
+	"jamesbot/internal/middleware"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Component Interaction Tests
+// ============================================================================
+
+func Test_InteractionHandler_Handle_Component(t *testing.T) {
+	tests := []struct {
+		name        string
+		customID    string
+		handlerErr  error
+		wantCalled  bool
+		wantData    string
+		wantContent string
+	}{
+		{
+			name:       "routes by key and passes data",
+			customID:   "confirm:42",
+			wantCalled: true,
+			wantData:   "42",
+		},
+		{
+			name:        "unknown component reports it is inactive",
+			customID:    "expired:1",
+			wantContent: handler.InactiveComponentMessage,
+		},
+		{
+			name:        "handler errors are reported to the user",
+			customID:    "confirm:42",
+			handlerErr:  errutil.UserFriendlyError{UserMessage: "That did not work.", Err: errors.New("boom")},
+			wantCalled:  true,
+			wantData:    "42",
+			wantContent: "That did not work.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := newInteractionLogCapture().logger()
+			components := command.NewComponentRegistry(logger)
+			called, data := false, ""
+			require.NoError(t, components.Register("confirm", func(ctx *command.Context) error {
+				called, data = true, ctx.ComponentData()
+				return tt.handlerErr
+			}))
+
+			h := handler.NewInteractionHandler(createTestRegistry(logger), noopMiddleware(), logger)
+			h.SetComponentRegistry(components)
+			session, transport := newRecordingSession(t)
+
+			interaction := createTestInteraction("", discordgo.InteractionMessageComponent)
+			interaction.Token = "test-token"
+			interaction.Data = discordgo.MessageComponentInteractionData{CustomID: tt.customID}
+
+			h.Handle(session, interaction)
+
+			assert.Equal(t, tt.wantCalled, called)
+			assert.Equal(t, tt.wantData, data)
+
+			requests := transport.recorded()
+			if tt.wantContent == "" {
+				assert.Empty(t, requests)
+				return
+			}
+			require.Len(t, requests, 1)
+			var resp discordgo.InteractionResponse
+			require.NoError(t, json.Unmarshal(requests[0].Body, &resp))
+			require.NotNil(t, resp.Data)
+			assert.Equal(t, tt.wantContent, resp.Data.Content)
+			assert.Equal(t, discordgo.MessageFlagsEphemeral, resp.Data.Flags)
+		})
+	}
+}
+
+func Test_InteractionHandler_Handle_ComponentPanic(t *testing.T) {
+	logger := newInteractionLogCapture().logger()
+	components := command.NewComponentRegistry(logger)
+	require.NoError(t, components.Register("explode", func(*command.Context) error {
+		panic("boom")
+	}))
+	calls := 0
+	require.NoError(t, components.Register("confirm", func(*command.Context) error {
+		calls++
+		return nil
+	}))
+
+	h := handler.NewInteractionHandler(createTestRegistry(logger), noopMiddleware(), logger)
+	h.SetComponentRegistry(components)
+	pool := handler.NewWorkerPool(1, 10)
+	h.SetWorkerPool(pool)
+	session, transport := newRecordingSession(t)
+
+	for _, customID := range []string{"explode:1", "confirm:1"} {
+		interaction := createTestInteraction("", discordgo.InteractionMessageComponent)
+		interaction.Token = "test-token"
+		interaction.Data = discordgo.MessageComponentInteractionData{CustomID: customID}
+		h.Handle(session, interaction)
+	}
+	require.NoError(t, pool.Close(context.Background()))
+
+	assert.Equal(t, 1, calls, "the worker should keep running jobs after a panic")
+	requests := transport.recorded()
+	require.Len(t, requests, 1, "only the panicking handler should respond")
+	var resp discordgo.InteractionResponse
+	require.NoError(t, json.Unmarshal(requests[0].Body, &resp))
+	require.NotNil(t, resp.Data)
+	assert.Equal(t, middleware.PanicMessage, resp.Data.Content)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, resp.Data.Flags)
+}
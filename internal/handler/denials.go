@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxDenialMembers bounds how many members a DenialTracker counts attempts
+// for at once. Members whose attempts have all aged out are dropped first.
+const maxDenialMembers = 10000
+
+// DenialTracker counts commands members tried to run without the permissions
+// they require, and decides when one member's attempts are frequent enough to
+// notify moderators in a channel, since repeated attempts may be probing for
+// a misconfigured permission. It is safe for concurrent use.
+type DenialTracker struct {
+	channelID string
+	threshold int
+	window    time.Duration
+
+	mu       sync.Mutex
+	attempts map[denialKey][]time.Time
+}
+
+// denialKey identifies a member of a guild.
+type denialKey struct {
+	guildID string
+	userID  string
+}
+
+// NewDenialTracker creates a tracker that notifies channelID when a member is
+// denied threshold times within window. It returns nil, which never notifies,
+// when threshold or window is not positive.
+func NewDenialTracker(channelID string, threshold int, window time.Duration) *DenialTracker {
+	if threshold <= 0 || window <= 0 {
+		return nil
+	}
+	return &DenialTracker{
+		channelID: channelID,
+		threshold: threshold,
+		window:    window,
+		attempts:  make(map[denialKey][]time.Time),
+	}
+}
+
+// Record counts one denied attempt by userID in guildID at now to run the
+// named command. It returns the notification to post when this attempt brings
+// the member's attempts within the window to the threshold. The count then
+// starts over, so a member who keeps trying is reported once per threshold
+// attempts rather than on every one.
+func (d *DenialTracker) Record(now time.Time, guildID, userID, name string) (string, bool) {
+	if d == nil {
+		return "", false
+	}
+	key := denialKey{guildID: guildID, userID: userID}
+	cutoff := now.Add(-d.window)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, tracked := d.attempts[key]; !tracked && len(d.attempts) >= maxDenialMembers {
+		d.sweep(cutoff)
+		if len(d.attempts) >= maxDenialMembers {
+			return "", false
+		}
+	}
+
+	attempts := append(recent(d.attempts[key], cutoff), now)
+	if len(attempts) < d.threshold {
+		d.attempts[key] = attempts
+		return "", false
+	}
+	delete(d.attempts, key)
+
+	return fmt.Sprintf("**Permission denied:** <@%s> tried to use commands they lack permission for "+
+		"%d times within %s.\nLatest: `%s`", userID, len(attempts), d.window, name), true
+}
+
+// sweep drops members with no attempts after cutoff. Callers must hold d.mu.
+func (d *DenialTracker) sweep(cutoff time.Time) {
+	for key, attempts := range d.attempts {
+		if len(recent(attempts, cutoff)) == 0 {
+			delete(d.attempts, key)
+		}
+	}
+}
+
+// recent returns the attempts, oldest first, made after cutoff.
+func recent(attempts []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(attempts) && !attempts[i].After(cutoff) {
+		i++
+	}
+	return attempts[i:]
+}
@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"jamesbot/internal/handler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// DenialTracker Tests
+// =============================================================================
+
+func Test_DenialTracker_Record(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		attempts  []time.Duration
+		wantAlert []bool
+	}{
+		{
+			name:      "alerts on reaching the threshold",
+			attempts:  []time.Duration{0, time.Second, 2 * time.Second},
+			wantAlert: []bool{false, false, true},
+		},
+		{
+			name:      "count starts over after an alert",
+			attempts:  []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second, 5 * time.Second},
+			wantAlert: []bool{false, false, true, false, false, true},
+		},
+		{
+			name:      "attempts outside the window are not counted",
+			attempts:  []time.Duration{0, time.Second, 2 * time.Minute, 3 * time.Minute},
+			wantAlert: []bool{false, false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := handler.NewDenialTracker("alerts", 3, time.Minute)
+
+			for i, offset := range tt.attempts {
+				alert, ok := tracker.Record(start.Add(offset), "guild-1", "user", "!kick")
+				assert.Equal(t, tt.wantAlert[i], ok, "attempt %d", i)
+				if ok {
+					assert.Contains(t, alert, "<@user>")
+					assert.Contains(t, alert, "3 times within 1m0s")
+					assert.Contains(t, alert, "`!kick`")
+				}
+			}
+		})
+	}
+}
+
+func Test_DenialTracker_PerMember(t *testing.T) {
+	tracker := handler.NewDenialTracker("alerts", 2, time.Minute)
+	now := time.Now()
+
+	_, ok := tracker.Record(now, "guild-1", "user-a", "!kick")
+	assert.False(t, ok)
+	_, ok = tracker.Record(now, "guild-1", "user-b", "!kick")
+	assert.False(t, ok, "attempts by different members are counted apart")
+	_, ok = tracker.Record(now, "guild-2", "user-a", "!kick")
+	assert.False(t, ok, "attempts in different guilds are counted apart")
+
+	_, ok = tracker.Record(now, "guild-1", "user-a", "!ban")
+	assert.True(t, ok, "attempts at different commands count together")
+}
+
+func Test_DenialTracker_Bounded(t *testing.T) {
+	tracker := handler.NewDenialTracker("alerts", 2, time.Minute)
+	now := time.Now()
+
+	for i := range 10001 {
+		tracker.Record(now, "guild-1", fmt.Sprint("user-", i), "!kick")
+	}
+	_, ok := tracker.Record(now, "guild-1", "user-new", "!kick")
+	assert.False(t, ok)
+	_, ok = tracker.Record(now, "guild-1", "user-new", "!kick")
+	assert.False(t, ok, "new members are not counted while the tracker is full")
+
+	_, ok = tracker.Record(now.Add(2*time.Minute), "guild-1", "user-new", "!kick")
+	assert.False(t, ok)
+	_, ok = tracker.Record(now.Add(2*time.Minute), "guild-1", "user-new", "!kick")
+	assert.True(t, ok, "members whose attempts aged out make room")
+}
+
+func Test_DenialTracker_Disabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		window    time.Duration
+	}{
+		{name: "zero threshold", threshold: 0, window: time.Minute},
+		{name: "zero window", threshold: 3, window: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := handler.NewDenialTracker("alerts", tt.threshold, tt.window)
+			require.Nil(t, tracker)
+
+			_, ok := tracker.Record(time.Now(), "guild-1", "user", "!kick")
+			assert.False(t, ok)
+		})
+	}
+}
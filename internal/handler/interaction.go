@@ -2,17 +2,51 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"runtime/debug"
 
 	"jamesbot/internal/command"
 	"jamesbot/internal/middleware"
+	"jamesbot/internal/rules"
 	"jamesbot/pkg/errutil"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/zerolog"
 )
 
-// CommandExecutedCallback is called after a command is successfully executed.
-type CommandExecutedCallback func()
+// UnknownCommandMessage is the ephemeral response sent when an interaction names
+// a command that is not in the registry, such as a stale Discord-side command.
+const UnknownCommandMessage = "Unknown command. It may have been removed or not yet updated; please try again later."
+
+// InactiveComponentMessage is the ephemeral response sent when a component,
+// such as a button, has no handler, for example because it belongs to a
+// message from before the bot restarted.
+const InactiveComponentMessage = "This button is no longer active."
+
+// CommandExecutedCallback is called with the command's name after it is successfully executed.
+type CommandExecutedCallback func(name string)
+
+// ErrorHandler tells the user that a command or component failed with err,
+// after the middleware chain has returned. The error has already been logged.
+type ErrorHandler func(ctx *command.Context, err error)
+
+// DefaultErrorHandler responds ephemerally with the error's user message if it
+// is an errutil.UserFriendlyError, or a generic message otherwise.
+func DefaultErrorHandler(ctx *command.Context, err error) {
+	userMessage := "An error occurred while executing the command."
+	var userFriendlyErr errutil.UserFriendlyError
+	if errors.As(err, &userFriendlyErr) {
+		if userFriendlyErr.UserMessage != "" {
+			userMessage = userFriendlyErr.UserMessage
+		}
+	}
+
+	if respondErr := ctx.RespondEphemeral(userMessage); respondErr != nil {
+		ctx.Logger.Error().
+			Err(respondErr).
+			Msg("failed to send error response to user")
+	}
+}
 
 // InteractionHandler handles Discord interaction events.
 // It processes application commands by looking them up in the registry
@@ -22,6 +56,11 @@ type InteractionHandler struct {
 	middleware        middleware.Middleware
 	logger            zerolog.Logger
 	onCommandExecuted CommandExecutedCallback
+	onError           ErrorHandler
+	pool              *WorkerPool
+	members           *command.MemberCache
+	components        *command.ComponentRegistry
+	settings          *rules.Set
 }
 
 // NewInteractionHandler creates a new interaction handler with the provided components.
@@ -42,22 +81,77 @@ func (h *InteractionHandler) SetCommandExecutedCallback(callback CommandExecuted
 	}
 }
 
+// SetErrorHandler makes onError tell users about failed commands and
+// components in place of DefaultErrorHandler. A nil onError restores the
+// default.
+func (h *InteractionHandler) SetErrorHandler(onError ErrorHandler) {
+	if h != nil {
+		h.onError = onError
+	}
+}
+
+// SetWorkerPool makes the handler execute commands on pool's workers instead
+// of on the goroutine that delivered the event. A nil pool executes inline.
+func (h *InteractionHandler) SetWorkerPool(pool *WorkerPool) {
+	if h != nil {
+		h.pool = pool
+	}
+}
+
+// SetMemberCache gives every command context cache for GuildMember lookups.
+func (h *InteractionHandler) SetMemberCache(cache *command.MemberCache) {
+	if h != nil {
+		h.members = cache
+	}
+}
+
+// SetComponentRegistry routes message component interactions, such as button
+// clicks, to the handlers in registry. Without a registry they are ignored.
+func (h *InteractionHandler) SetComponentRegistry(registry *command.ComponentRegistry) {
+	if h != nil {
+		h.components = registry
+	}
+}
+
+// SetGuildSettings makes the handler apply each guild's settings in settings,
+// such as the language responses default to, and gives them to commands, and
+// text commands use its prefix. A nil settings leaves interactions as Discord
+// sent them.
+func (h *InteractionHandler) SetGuildSettings(settings *rules.Set) {
+	if h != nil {
+		h.settings = settings
+	}
+}
+
 // Handle processes interaction events from Discord.
-// It currently supports ApplicationCommand interactions and routes them to
-// the appropriate command handler.
+// It routes ApplicationCommand interactions to the appropriate command
+// handler and, once a component registry is set, MessageComponent
+// interactions to the appropriate component handler.
 func (h *InteractionHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if i == nil {
 		h.logger.Warn().Msg("received nil interaction")
 		return
 	}
 
-	// Only handle application command interactions
-	if i.Type != discordgo.InteractionApplicationCommand {
+	if i.Interaction != nil && i.GuildID != "" {
+		applyGuildLocale(i.Interaction, h.settings.GuildSettings(i.GuildID))
+	}
+
+	switch {
+	case i.Type == discordgo.InteractionApplicationCommand:
+		h.handleCommand(s, i)
+	case i.Type == discordgo.InteractionMessageComponent && h.components != nil:
+		h.handleComponent(s, i)
+	default:
 		h.logger.Debug().
 			Int("type", int(i.Type)).
 			Msg("ignoring non-command interaction")
-		return
 	}
+}
+
+// handleCommand looks up and runs the command an ApplicationCommand
+// interaction names.
+func (h *InteractionHandler) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	// Get command name from interaction
 	if i.Data == nil {
@@ -70,18 +164,127 @@ func (h *InteractionHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 	// Look up command in registry
 	cmd, exists := h.registry.Get(commandName)
 	if !exists {
-		h.logger.Error().
+		ctx := command.NewContext(s, i, h.logger)
+
+		// Usually a stale command still registered on Discord's side
+		h.logger.Warn().
 			Str("command", commandName).
-			Msg("command not found in registry")
+			Str("user_id", ctx.UserID()).
+			Str("guild_id", ctx.GuildID()).
+			Msg("unknown command: not found in registry")
 
-		// Respond with error message
+		// Respond so the user doesn't see "interaction failed"
+		if err := ctx.RespondEphemeral(UnknownCommandMessage); err != nil {
+			h.logger.Debug().
+				Err(err).
+				Str("command", commandName).
+				Msg("failed to send unknown command response")
+		}
+		return
+	}
+
+	h.dispatch(s, i, "command", commandName, func() { h.execute(s, i, cmd) })
+}
+
+// handleComponent runs the component handler registered for a
+// MessageComponent interaction's custom ID.
+func (h *InteractionHandler) handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Data == nil {
+		h.logger.Warn().Msg("received component interaction with nil data")
+		return
+	}
+	customID := i.MessageComponentData().CustomID
+
+	componentHandler, exists := h.components.Get(customID)
+	if !exists {
 		ctx := command.NewContext(s, i, h.logger)
-		_ = ctx.RespondEphemeral("Command not found. This might be a configuration issue.")
+
+		h.logger.Info().
+			Str("component", customID).
+			Str("user_id", ctx.UserID()).
+			Str("guild_id", ctx.GuildID()).
+			Msg("no handler for component")
+
+		if err := ctx.RespondEphemeral(InactiveComponentMessage); err != nil {
+			h.logger.Debug().
+				Err(err).
+				Str("component", customID).
+				Msg("failed to send inactive component response")
+		}
+		return
+	}
+
+	h.dispatch(s, i, "component", customID, func() {
+		ctx := command.NewContext(s, i, h.logger, command.WithMemberCache(h.members), command.WithGuildSettings(h.settings))
+		defer ctx.Release()
+		if err := h.runComponent(ctx, customID, componentHandler); err != nil {
+			h.handleError(ctx, "component", customID, err)
+		}
+	})
+}
+
+// runComponent calls handler, turning a panic into an error reported as the
+// Recovery middleware reports one from a command. Component handlers do not
+// run through the middleware chain, so without this a panic would take down
+// the worker running it, and the bot with it.
+func (h *InteractionHandler) runComponent(ctx *command.Context, customID string, handler command.ComponentHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Error().
+				Interface("panic", r).
+				Bytes("stack", debug.Stack()).
+				Str("component", customID).
+				Str("user_id", ctx.UserID()).
+				Str("guild_id", ctx.GuildID()).
+				Msg("panic recovered in component handler")
+
+			err = errutil.UserFriendlyError{
+				UserMessage: middleware.PanicMessage,
+				Err:         fmt.Errorf("panic recovered: %v", r),
+			}
+		}
+	}()
+
+	return handler(ctx)
+}
+
+// dispatch runs job on the worker pool, or inline without one. When the pool
+// is full the user is told the bot is busy, through a context built with
+// opts; kind and name identify the interaction in logs.
+func (h *InteractionHandler) dispatch(s *discordgo.Session, i *discordgo.InteractionCreate, kind, name string, job func(), opts ...command.ContextOption) {
+	if h.pool == nil {
+		job()
 		return
 	}
 
+	if !h.pool.Submit(job) {
+		ctx := command.NewContext(s, i, h.logger, opts...)
+
+		h.logger.Warn().
+			Str(kind, name).
+			Str("user_id", ctx.UserID()).
+			Str("guild_id", ctx.GuildID()).
+			Int("queue_depth", h.pool.QueueDepth()).
+			Msg("interaction queue full: rejecting " + kind)
+
+		if err := ctx.RespondEphemeral(BusyMessage); err != nil {
+			h.logger.Debug().
+				Err(err).
+				Str(kind, name).
+				Msg("failed to send busy response")
+		}
+	}
+}
+
+// execute runs cmd for the interaction through the middleware chain and
+// reports the outcome. opts are applied to the command's context.
+func (h *InteractionHandler) execute(s *discordgo.Session, i *discordgo.InteractionCreate, cmd command.Command, opts ...command.ContextOption) {
+	commandName := i.ApplicationCommandData().Name
+
 	// Create command context
-	ctx := command.NewContext(s, i, h.logger)
+	opts = append([]command.ContextOption{command.WithMemberCache(h.members), command.WithGuildSettings(h.settings)}, opts...)
+	ctx := command.NewContext(s, i, h.logger, opts...)
+	defer ctx.Release()
 
 	// Create the base handler that executes the command
 	handler := middleware.HandlerFunc(func(ctx *command.Context) error {
@@ -94,19 +297,24 @@ func (h *InteractionHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 	}
 
 	// Execute the command through the middleware chain
-	if err := handler(ctx); err != nil {
-		h.handleError(ctx, err)
-	} else {
+	err := handler(ctx)
+	switch {
+	case errors.Is(err, middleware.ErrCommandBlocked):
+		// A middleware answered in the command's place, so it did not run
+	case err != nil:
+		h.handleError(ctx, "command", commandName, err)
+	default:
 		// Command executed successfully
 		if h.onCommandExecuted != nil {
-			h.onCommandExecuted()
+			h.onCommandExecuted(commandName)
 		}
 	}
 }
 
-// handleError processes errors from command execution.
-// It extracts user-friendly messages when available and logs the full error.
-func (h *InteractionHandler) handleError(ctx *command.Context, err error) {
+// handleError processes errors from command or component execution; kind and
+// name identify what failed in the log. It logs the full error and passes it
+// to the error handler to tell the user.
+func (h *InteractionHandler) handleError(ctx *command.Context, kind, name string, err error) {
 	if err == nil {
 		return
 	}
@@ -114,24 +322,24 @@ func (h *InteractionHandler) handleError(ctx *command.Context, err error) {
 	// Log the error
 	h.logger.Error().
 		Err(err).
-		Str("command", ctx.Interaction.ApplicationCommandData().Name).
+		Str(kind, name).
 		Str("user_id", ctx.UserID()).
 		Str("guild_id", ctx.GuildID()).
-		Msg("command execution failed")
+		Msg(kind + " execution failed")
 
-	// Extract user message from UserFriendlyError if present
-	userMessage := "An error occurred while executing the command."
-	var userFriendlyErr errutil.UserFriendlyError
-	if errors.As(err, &userFriendlyErr) {
-		if userFriendlyErr.UserMessage != "" {
-			userMessage = userFriendlyErr.UserMessage
-		}
+	onError := h.onError
+	if onError == nil {
+		onError = DefaultErrorHandler
 	}
+	onError(ctx, err)
+}
 
-	// Respond to the user with an ephemeral message
-	if respondErr := ctx.RespondEphemeral(userMessage); respondErr != nil {
-		h.logger.Error().
-			Err(respondErr).
-			Msg("failed to send error response to user")
+// applyGuildLocale replaces the guild locale of i with the one in settings,
+// if set, so responses that fall back to the guild's language use it.
+func applyGuildLocale(i *discordgo.Interaction, settings rules.GuildSettings) {
+	if settings.Locale == "" {
+		return
 	}
+	locale := discordgo.Locale(settings.Locale)
+	i.GuildLocale = &locale
 }
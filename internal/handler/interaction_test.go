@@ -2,13 +2,22 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"jamesbot/internal/command"
 	"jamesbot/internal/handler"
 	"jamesbot/internal/middleware"
+	"jamesbot/internal/rules"
+	"jamesbot/pkg/errutil"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/zerolog"
@@ -120,6 +129,73 @@ func createTestInteraction(cmdName string, interactionType discordgo.Interaction
 	return interaction
 }
 
+// recordedRequest is a Discord REST request captured by recordingTransport.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// cannedResponse is a reply recordingTransport gives to a specific request.
+type cannedResponse struct {
+	status int
+	body   string
+}
+
+// recordingTransport is an http.RoundTripper that records Discord REST calls
+// and answers them with 204 No Content, so handlers can run against a real
+// *discordgo.Session without network access. Requests listed in responses,
+// keyed "METHOD /api/v9/path", get that reply instead.
+type recordingTransport struct {
+	mu        sync.Mutex
+	requests  []recordedRequest
+	responses map[string]cannedResponse
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, recordedRequest{Method: req.Method, Path: req.URL.Path, Body: body})
+	canned, ok := rt.responses[req.Method+" "+req.URL.Path]
+	rt.mu.Unlock()
+
+	if ok {
+		return &http.Response{
+			StatusCode: canned.status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(canned.body)),
+			Request:    req,
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+func (rt *recordingTransport) recorded() []recordedRequest {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return append([]recordedRequest(nil), rt.requests...)
+}
+
+// newRecordingSession creates a session whose REST calls are captured by the returned transport.
+func newRecordingSession(t *testing.T) (*discordgo.Session, *recordingTransport) {
+	t.Helper()
+	s, err := discordgo.New("Bot test-token")
+	require.NoError(t, err)
+	rt := &recordingTransport{}
+	s.Client = &http.Client{Transport: rt}
+	return s, rt
+}
+
 // createTestRegistry creates a command registry with the given commands.
 func createTestRegistry(logger zerolog.Logger, commands ...*mockCommand) *command.Registry {
 	registry := command.NewRegistry(logger)
@@ -182,6 +258,28 @@ func Test_InteractionHandler_Handle_ValidCommand(t *testing.T) {
 	assert.NotNil(t, pingCmd.executedCtx, "command should receive context")
 }
 
+func Test_InteractionHandler_CommandExecutedCallback(t *testing.T) {
+	logger := zerolog.Nop()
+	failing := newMockCommand("fail")
+	failing.executeFunc = func(ctx *command.Context) error { return errors.New("boom") }
+	blocked := newMockCommand("blocked")
+	blocked.executeFunc = func(ctx *command.Context) error { return middleware.ErrCommandBlocked }
+	registry := createTestRegistry(logger, newMockCommand("ping"), failing, blocked)
+
+	h := handler.NewInteractionHandler(registry, nil, logger)
+	var executed, failed []string
+	h.SetCommandExecutedCallback(func(name string) { executed = append(executed, name) })
+	h.SetErrorHandler(func(ctx *command.Context, err error) { failed = append(failed, err.Error()) })
+
+	h.Handle(nil, createTestInteraction("ping", discordgo.InteractionApplicationCommand))
+	h.Handle(nil, createTestInteraction("fail", discordgo.InteractionApplicationCommand))
+	h.Handle(nil, createTestInteraction("blocked", discordgo.InteractionApplicationCommand))
+	h.Handle(nil, createTestInteraction("ping", discordgo.InteractionApplicationCommand))
+
+	assert.Equal(t, []string{"ping", "ping"}, executed, "callback should receive names of successful commands only")
+	assert.Equal(t, []string{"boom"}, failed, "a blocked command has already been answered and is not an error")
+}
+
 func Test_InteractionHandler_Handle_UnknownCommand(t *testing.T) {
 	capture := newInteractionLogCapture()
 	logger := capture.logger()
@@ -207,6 +305,32 @@ func Test_InteractionHandler_Handle_UnknownCommand(t *testing.T) {
 		"should log warning for unknown command")
 }
 
+func Test_InteractionHandler_Handle_UnknownCommandResponds(t *testing.T) {
+	capture := newInteractionLogCapture()
+	logger := capture.logger()
+	registry := createTestRegistry(logger, newMockCommand("ping"))
+	h := handler.NewInteractionHandler(registry, noopMiddleware(), logger)
+	session, transport := newRecordingSession(t)
+
+	interaction := createTestInteraction("stale-command", discordgo.InteractionApplicationCommand)
+	interaction.Token = "test-token"
+
+	h.Handle(session, interaction)
+
+	requests := transport.recorded()
+	require.Len(t, requests, 1, "handler should send exactly one response")
+	assert.Contains(t, requests[0].Path, "/interactions/test-interaction-id/test-token/callback")
+
+	var resp discordgo.InteractionResponse
+	require.NoError(t, json.Unmarshal(requests[0].Body, &resp))
+	require.NotNil(t, resp.Data)
+	assert.Equal(t, handler.UnknownCommandMessage, resp.Data.Content)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, resp.Data.Flags, "response should be ephemeral")
+
+	assert.True(t, capture.containsLevel("warn"), "unknown command should be logged as a warning")
+	assert.True(t, capture.contains("stale-command"), "log should name the unknown command")
+}
+
 func Test_InteractionHandler_Handle_NonCommandInteraction(t *testing.T) {
 	capture := newInteractionLogCapture()
 	logger := capture.logger()
@@ -371,6 +495,82 @@ func Test_InteractionHandler_Handle_CommandError(t *testing.T) {
 	assert.True(t, failingCmd.executed, "command should still be executed")
 }
 
+func Test_InteractionHandler_SetErrorHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		custom      bool
+		err         error
+		wantMessage string
+	}{
+		{
+			name:        "default responds with the user-friendly message",
+			err:         errutil.UserFriendlyError{UserMessage: "Slow down.", Err: errors.New("rate limited")},
+			wantMessage: "Slow down.",
+		},
+		{
+			name:        "default responds generically to other errors",
+			err:         errors.New("database unreachable"),
+			wantMessage: "An error occurred while executing the command.",
+		},
+		{
+			name:        "custom handler replaces the default",
+			custom:      true,
+			err:         errors.New("database unreachable"),
+			wantMessage: "custom: database unreachable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing := newMockCommand("failing")
+			failing.executeFunc = func(ctx *command.Context) error {
+				return fmt.Errorf("failing: %w", tt.err)
+			}
+			h := handler.NewInteractionHandler(createTestRegistry(zerolog.Nop(), failing), noopMiddleware(), zerolog.Nop())
+
+			var gotErr error
+			if tt.custom {
+				h.SetErrorHandler(func(ctx *command.Context, err error) {
+					gotErr = err
+					_ = ctx.RespondEphemeral("custom: " + errors.Unwrap(err).Error())
+				})
+			}
+
+			session, transport := newRecordingSession(t)
+			interaction := createTestInteraction("failing", discordgo.InteractionApplicationCommand)
+			interaction.Token = "test-token"
+			h.Handle(session, interaction)
+
+			if tt.custom {
+				assert.ErrorIs(t, gotErr, tt.err, "the handler should receive the command's error")
+			}
+			requests := transport.recorded()
+			require.Len(t, requests, 1, "the user should get exactly one error response")
+			var resp discordgo.InteractionResponse
+			require.NoError(t, json.Unmarshal(requests[0].Body, &resp))
+			require.NotNil(t, resp.Data)
+			assert.Equal(t, tt.wantMessage, resp.Data.Content)
+		})
+	}
+}
+
+func Test_InteractionHandler_SetErrorHandler_NilRestoresDefault(t *testing.T) {
+	failing := newMockCommand("failing")
+	failing.executeFunc = func(ctx *command.Context) error {
+		return errors.New("failed")
+	}
+	h := handler.NewInteractionHandler(createTestRegistry(zerolog.Nop(), failing), noopMiddleware(), zerolog.Nop())
+	called := false
+	h.SetErrorHandler(func(ctx *command.Context, err error) { called = true })
+	h.SetErrorHandler(nil)
+
+	session, transport := newRecordingSession(t)
+	h.Handle(session, createTestInteraction("failing", discordgo.InteractionApplicationCommand))
+
+	assert.False(t, called)
+	assert.Len(t, transport.recorded(), 1, "the default handler should respond")
+}
+
 func Test_InteractionHandler_Handle_MultipleCommands(t *testing.T) {
 	capture := newInteractionLogCapture()
 	logger := capture.logger()
@@ -417,6 +617,25 @@ func Test_InteractionHandler_Handle_ContextContainsInteraction(t *testing.T) {
 		"context should contain the original interaction")
 }
 
+func Test_InteractionHandler_Handle_ContextReleased(t *testing.T) {
+	logger := zerolog.Nop()
+
+	var work context.Context
+	slowCmd := newMockCommand("slow")
+	slowCmd.executeFunc = func(ctx *command.Context) error {
+		work = ctx.Context()
+		assert.NoError(t, work.Err(), "the context should be live while the command runs")
+		return nil
+	}
+	registry := createTestRegistry(logger, slowCmd)
+
+	h := handler.NewInteractionHandler(registry, noopMiddleware(), logger)
+	h.Handle(nil, createTestInteraction("slow", discordgo.InteractionApplicationCommand))
+
+	require.NotNil(t, work)
+	assert.ErrorIs(t, work.Err(), context.Canceled, "the context should be released once the command returns")
+}
+
 func Test_InteractionHandler_Handle_EmptyCommandName(t *testing.T) {
 	capture := newInteractionLogCapture()
 	logger := capture.logger()
@@ -550,3 +769,76 @@ func Benchmark_InteractionHandler_Handle_UnknownCommand(b *testing.B) {
 		h.Handle(nil, interaction)
 	}
 }
+
+func Test_InteractionHandler_Handle_MemberCache(t *testing.T) {
+	logger := newInteractionLogCapture().logger()
+	cache := command.NewMemberCache(time.Minute, 10)
+
+	var got *command.MemberCache
+	cmd := newMockCommand("ping")
+	cmd.executeFunc = func(ctx *command.Context) error {
+		got = ctx.Members
+		return nil
+	}
+	h := handler.NewInteractionHandler(createTestRegistry(logger, cmd), noopMiddleware(), logger)
+	h.SetMemberCache(cache)
+
+	h.Handle(nil, createTestInteraction("ping", discordgo.InteractionApplicationCommand))
+
+	assert.Same(t, cache, got, "commands should share the handler's member cache")
+}
+
+func Test_InteractionHandler_Handle_GuildSettings(t *testing.T) {
+	logger := newInteractionLogCapture().logger()
+	set := rules.NewSet(rules.Defaults()...)
+
+	var got *rules.Set
+	cmd := newMockCommand("ping")
+	cmd.executeFunc = func(ctx *command.Context) error {
+		got = ctx.Settings
+		return nil
+	}
+	h := handler.NewInteractionHandler(createTestRegistry(logger, cmd), noopMiddleware(), logger)
+	h.SetGuildSettings(set)
+
+	h.Handle(nil, createTestInteraction("ping", discordgo.InteractionApplicationCommand))
+
+	assert.Same(t, set, got, "commands should read the handler's guild settings")
+}
+
+func Test_InteractionHandler_Handle_GuildSettingsLocale(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetGuildRule("test-guild", rules.RuleSettings, rules.KeyLocale, "de"))
+	discordLocale := discordgo.SpanishES
+
+	tests := []struct {
+		name     string
+		settings *rules.Set
+		guildID  string
+		want     string
+	}{
+		{name: "guild setting replaces the guild's locale", settings: set, guildID: "test-guild", want: "de"},
+		{name: "guild without the setting keeps its locale", settings: set, guildID: "other-guild", want: "es-ES"},
+		{name: "no settings", guildID: "test-guild", want: "es-ES"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			cmd := newMockCommand("ping")
+			cmd.executeFunc = func(ctx *command.Context) error {
+				got = ctx.GuildLocale()
+				return nil
+			}
+			h := handler.NewInteractionHandler(createTestRegistry(zerolog.Nop(), cmd), nil, zerolog.Nop())
+			h.SetGuildSettings(tt.settings)
+			i := createTestInteraction("ping", discordgo.InteractionApplicationCommand)
+			i.GuildID = tt.guildID
+			i.GuildLocale = &discordLocale
+
+			h.Handle(nil, i)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"jamesbot/internal/rules"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// MemberHandler greets members who join a guild and bids farewell to members
+// who leave, as configured by the welcome and goodbye rules.
+type MemberHandler struct {
+	rules  *rules.Set
+	logger zerolog.Logger
+
+	// missing holds guildID/channelID pairs whose channel Discord reported as
+	// deleted, so a stale setting is warned about once instead of on every event.
+	mu      sync.Mutex
+	missing map[string]bool
+}
+
+// NewMemberHandler creates a member handler that reads its settings from set.
+func NewMemberHandler(set *rules.Set, logger zerolog.Logger) *MemberHandler {
+	return &MemberHandler{
+		rules:   set,
+		logger:  logger,
+		missing: make(map[string]bool),
+	}
+}
+
+// HandleAdd processes the GuildMemberAdd event from Discord. If the welcome
+// rule is enabled in the guild, it gives the member the configured role and
+// posts the welcome message in the configured channel. Bots are not greeted.
+func (h *MemberHandler) HandleAdd(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+	if s == nil || m == nil || m.Member == nil || m.User == nil || m.User.Bot {
+		return
+	}
+	if !h.rules.GuildEnabled(m.GuildID, rules.RuleWelcome) {
+		return
+	}
+
+	logger := h.logger.With().
+		Str("guild_id", m.GuildID).
+		Str("user_id", m.User.ID).
+		Str("rule", rules.RuleWelcome).
+		Logger()
+
+	if roleID, _ := h.rules.GuildValue(m.GuildID, rules.RuleWelcome, rules.KeyWelcomeRole); roleID != "" {
+		if err := s.GuildMemberRoleAdd(m.GuildID, m.User.ID, roleID); err != nil {
+			logger.Error().Err(err).Str("role_id", roleID).Msg("failed to give new member the welcome role")
+		}
+	}
+
+	h.post(s, logger, m.GuildID, rules.RuleWelcome, m.User.Mention())
+}
+
+// HandleRemove processes the GuildMemberRemove event from Discord. If the
+// goodbye rule is enabled in the guild, it posts the farewell message in the
+// configured channel. Discord may send only part of the departed member, so
+// {user} falls back from their name to their ID. Bots get no farewell.
+func (h *MemberHandler) HandleRemove(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+	if s == nil || m == nil || m.Member == nil || m.GuildID == "" {
+		return
+	}
+	if m.User != nil && m.User.Bot {
+		return
+	}
+	if !h.rules.GuildEnabled(m.GuildID, rules.RuleGoodbye) {
+		return
+	}
+
+	logger := h.logger.With().
+		Str("guild_id", m.GuildID).
+		Str("rule", rules.RuleGoodbye).
+		Logger()
+	if m.User != nil {
+		logger = logger.With().Str("user_id", m.User.ID).Logger()
+	}
+
+	h.post(s, logger, m.GuildID, rules.RuleGoodbye, departedName(m.User))
+}
+
+// post sends rule's message, with {user} replaced by user, to the channel the
+// rule names in guildID. It does nothing if no channel is set or the channel
+// is known to be deleted.
+func (h *MemberHandler) post(s *discordgo.Session, logger zerolog.Logger, guildID, rule, user string) {
+	channelID, _ := h.rules.GuildValue(guildID, rule, rules.KeyChannel)
+	if channelID == "" || h.isMissing(guildID, channelID) {
+		return
+	}
+
+	template, _ := h.rules.GuildValue(guildID, rule, rules.KeyMessage)
+	name, count := guildSummary(s, guildID)
+	content := FormatMemberMessage(template, user, name, count)
+
+	if _, err := s.ChannelMessageSend(channelID, content); err != nil {
+		if isUnknownChannel(err) {
+			h.markMissing(guildID, channelID)
+			logger.Warn().
+				Str("channel_id", channelID).
+				Msg("configured channel no longer exists; messages are paused until the channel setting changes")
+			return
+		}
+		logger.Error().Err(err).Str("channel_id", channelID).Msg("failed to post member message")
+	}
+}
+
+// FormatMemberMessage fills in a welcome or goodbye message template. {user}
+// becomes user, {server} the guild's name, and {count} its member count.
+// Unknown placeholders are left as written.
+func FormatMemberMessage(template, user, server string, count int) string {
+	return strings.NewReplacer(
+		"{user}", user,
+		"{server}", server,
+		"{count}", strconv.Itoa(count),
+	).Replace(template)
+}
+
+// departedName names a member who has left for a farewell message. A mention
+// would not resolve once they are gone, so it uses their tag, or their ID when
+// Discord sent no username.
+func departedName(user *discordgo.User) string {
+	switch {
+	case user == nil:
+		return "A member"
+	case user.Username != "":
+		return user.String()
+	default:
+		return user.ID
+	}
+}
+
+// guildSummary returns a guild's name and member count from the state cache,
+// which has already counted a joining or leaving member by the time handlers run.
+func guildSummary(s *discordgo.Session, guildID string) (string, int) {
+	if s.State == nil {
+		return "", 0
+	}
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		return "", 0
+	}
+	return guild.Name, guild.MemberCount
+}
+
+// isUnknownChannel reports whether err is Discord rejecting a request because
+// the channel does not exist.
+func isUnknownChannel(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) {
+		return false
+	}
+	if restErr.Message != nil {
+		return restErr.Message.Code == discordgo.ErrCodeUnknownChannel
+	}
+	return restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
+
+func (h *MemberHandler) isMissing(guildID, channelID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.missing[guildID+"/"+channelID]
+}
+
+func (h *MemberHandler) markMissing(guildID, channelID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.missing[guildID+"/"+channelID] = true
+}
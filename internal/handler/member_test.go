@@ -0,0 +1,276 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"jamesbot/internal/handler"
+	"jamesbot/internal/rules"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWelcomeRules returns the default rules with the welcome rule posting to
+// channel 111 and giving role (if non-empty) to new members.
+func newWelcomeRules(t *testing.T, role string) *rules.Set {
+	t.Helper()
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule(rules.RuleWelcome, rules.KeyEnabled, "true"))
+	require.NoError(t, set.SetRule(rules.RuleWelcome, rules.KeyChannel, "111"))
+	require.NoError(t, set.SetRule(rules.RuleWelcome, rules.KeyMessage, "Hi {user}, welcome to {server} (#{count})"))
+	require.NoError(t, set.SetRule(rules.RuleWelcome, rules.KeyWelcomeRole, role))
+	return set
+}
+
+// newMemberAdd creates a join event for a regular member of guild-1.
+func newMemberAdd() *discordgo.GuildMemberAdd {
+	return &discordgo.GuildMemberAdd{Member: &discordgo.Member{
+		GuildID: "guild-1",
+		User:    &discordgo.User{ID: "222", Username: "newcomer"},
+	}}
+}
+
+// ============================================================================
+// FormatMemberMessage Tests
+// ============================================================================
+
+func Test_FormatMemberMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		user     string
+		want     string
+	}{
+		{
+			name:     "all placeholders",
+			template: "Welcome to {server}, {user}! You are member #{count}.",
+			user:     "<@222>",
+			want:     "Welcome to Gophers, <@222>! You are member #42.",
+		},
+		{
+			name:     "repeated placeholders",
+			template: "{user} {user}",
+			user:     "<@222>",
+			want:     "<@222> <@222>",
+		},
+		{
+			name:     "no placeholders",
+			template: "Hello!",
+			user:     "<@222>",
+			want:     "Hello!",
+		},
+		{
+			name:     "unknown placeholders are kept",
+			template: "{user} joined {channel}",
+			user:     "<@222>",
+			want:     "<@222> joined {channel}",
+		},
+		{
+			name:     "placeholders are case-sensitive",
+			template: "{USER}",
+			user:     "<@222>",
+			want:     "{USER}",
+		},
+		{
+			name:     "substituted text is not expanded again",
+			template: "Bye {user}",
+			user:     "{server}",
+			want:     "Bye {server}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, handler.FormatMemberMessage(tt.template, tt.user, "Gophers", 42))
+		})
+	}
+}
+
+// ============================================================================
+// MemberHandler Tests
+// ============================================================================
+
+func Test_MemberHandler_HandleAdd(t *testing.T) {
+	send := http.MethodPost + " /api/v9/channels/111/messages"
+	addRole := http.MethodPut + " /api/v9/guilds/guild-1/members/222/roles/333"
+
+	tests := []struct {
+		name         string
+		rules        func(t *testing.T) *rules.Set
+		event        *discordgo.GuildMemberAdd
+		wantRequests []string
+	}{
+		{
+			name:         "posts the welcome message",
+			rules:        func(t *testing.T) *rules.Set { return newWelcomeRules(t, "") },
+			event:        newMemberAdd(),
+			wantRequests: []string{send},
+		},
+		{
+			name:         "gives the auto-role before posting",
+			rules:        func(t *testing.T) *rules.Set { return newWelcomeRules(t, "333") },
+			event:        newMemberAdd(),
+			wantRequests: []string{addRole, send},
+		},
+		{
+			name:  "disabled rule does nothing",
+			rules: func(t *testing.T) *rules.Set { return rules.NewSet(rules.Defaults()...) },
+			event: newMemberAdd(),
+		},
+		{
+			name: "no channel only assigns the role",
+			rules: func(t *testing.T) *rules.Set {
+				set := newWelcomeRules(t, "333")
+				require.NoError(t, set.SetRule(rules.RuleWelcome, rules.KeyChannel, ""))
+				return set
+			},
+			event:        newMemberAdd(),
+			wantRequests: []string{addRole},
+		},
+		{
+			name: "rule disabled in the guild does nothing",
+			rules: func(t *testing.T) *rules.Set {
+				set := newWelcomeRules(t, "333")
+				require.NoError(t, set.SetGuildRule("guild-1", rules.RuleWelcome, rules.KeyEnabled, "false"))
+				return set
+			},
+			event: newMemberAdd(),
+		},
+		{
+			name:  "bots are not greeted",
+			rules: func(t *testing.T) *rules.Set { return newWelcomeRules(t, "333") },
+			event: func() *discordgo.GuildMemberAdd {
+				e := newMemberAdd()
+				e.User.Bot = true
+				return e
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			rt.responses = map[string]cannedResponse{send: {status: http.StatusOK, body: `{"id":"msg-1"}`}}
+			h := handler.NewMemberHandler(tt.rules(t), zerolog.Nop())
+
+			h.HandleAdd(s, tt.event)
+
+			assert.Equal(t, tt.wantRequests, requestLines(rt.recorded()))
+		})
+	}
+}
+
+func Test_MemberHandler_HandleAdd_FillsPlaceholdersFromState(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	rt.responses = map[string]cannedResponse{
+		http.MethodPost + " /api/v9/channels/111/messages": {status: http.StatusOK, body: `{"id":"msg-1"}`},
+	}
+	require.NoError(t, s.State.GuildAdd(&discordgo.Guild{ID: "guild-1", Name: "Gophers", MemberCount: 7}))
+	h := handler.NewMemberHandler(newWelcomeRules(t, ""), zerolog.Nop())
+
+	h.HandleAdd(s, newMemberAdd())
+
+	reqs := rt.recorded()
+	require.Len(t, reqs, 1)
+	var msg discordgo.MessageSend
+	require.NoError(t, json.Unmarshal(reqs[0].Body, &msg))
+	assert.Equal(t, "Hi <@222>, welcome to Gophers (#7)", msg.Content)
+}
+
+func Test_MemberHandler_HandleAdd_MissingChannel(t *testing.T) {
+	send := http.MethodPost + " /api/v9/channels/111/messages"
+	s, rt := newRecordingSession(t)
+	rt.responses = map[string]cannedResponse{
+		send: {status: http.StatusNotFound, body: `{"code":10003,"message":"Unknown Channel"}`},
+	}
+	set := newWelcomeRules(t, "")
+	h := handler.NewMemberHandler(set, zerolog.Nop())
+
+	h.HandleAdd(s, newMemberAdd())
+	h.HandleAdd(s, newMemberAdd())
+
+	assert.Equal(t, []string{send}, requestLines(rt.recorded()), "a deleted channel should not be retried on every join")
+
+	// Pointing the rule at another channel resumes welcome messages
+	require.NoError(t, set.SetRule(rules.RuleWelcome, rules.KeyChannel, "112"))
+	h.HandleAdd(s, newMemberAdd())
+
+	assert.Equal(t, []string{send, http.MethodPost + " /api/v9/channels/112/messages"}, requestLines(rt.recorded()))
+}
+
+func Test_MemberHandler_HandleRemove(t *testing.T) {
+	send := http.MethodPost + " /api/v9/channels/111/messages"
+
+	tests := []struct {
+		name        string
+		member      *discordgo.Member
+		enabled     bool
+		wantContent string
+	}{
+		{
+			name:        "full member uses their tag",
+			member:      &discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "222", Username: "leaver", Discriminator: "0"}},
+			enabled:     true,
+			wantContent: "Bye leaver from Gophers (6 left)",
+		},
+		{
+			name:        "legacy discriminator is included",
+			member:      &discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "222", Username: "leaver", Discriminator: "1234"}},
+			enabled:     true,
+			wantContent: "Bye leaver#1234 from Gophers (6 left)",
+		},
+		{
+			name:        "partial user falls back to the ID",
+			member:      &discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "222"}},
+			enabled:     true,
+			wantContent: "Bye 222 from Gophers (6 left)",
+		},
+		{
+			name:        "missing user still says goodbye",
+			member:      &discordgo.Member{GuildID: "guild-1"},
+			enabled:     true,
+			wantContent: "Bye A member from Gophers (6 left)",
+		},
+		{
+			name:    "bots get no farewell",
+			member:  &discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "222", Username: "bot", Bot: true}},
+			enabled: true,
+		},
+		{
+			name:   "disabled rule does nothing",
+			member: &discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "222", Username: "leaver"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			rt.responses = map[string]cannedResponse{send: {status: http.StatusOK, body: `{"id":"msg-1"}`}}
+			require.NoError(t, s.State.GuildAdd(&discordgo.Guild{ID: "guild-1", Name: "Gophers", MemberCount: 6}))
+
+			set := rules.NewSet(rules.Defaults()...)
+			require.NoError(t, set.SetRule(rules.RuleGoodbye, rules.KeyEnabled, strconv.FormatBool(tt.enabled)))
+			require.NoError(t, set.SetRule(rules.RuleGoodbye, rules.KeyChannel, "111"))
+			require.NoError(t, set.SetRule(rules.RuleGoodbye, rules.KeyMessage, "Bye {user} from {server} ({count} left)"))
+			h := handler.NewMemberHandler(set, zerolog.Nop())
+
+			h.HandleRemove(s, &discordgo.GuildMemberRemove{Member: tt.member})
+
+			reqs := rt.recorded()
+			if tt.wantContent == "" {
+				assert.Empty(t, reqs)
+				return
+			}
+			require.Len(t, reqs, 1)
+			assert.Equal(t, send, reqs[0].Method+" "+reqs[0].Path)
+			var msg discordgo.MessageSend
+			require.NoError(t, json.Unmarshal(reqs[0].Body, &msg))
+			assert.Equal(t, tt.wantContent, msg.Content)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/rules"
+	"jamesbot/internal/warnings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// ContentTimeout is how long a member is timed out when a content rule with
+// the timeout action matches one of their messages.
+const ContentTimeout = 10 * time.Minute
+
+// MessageHandler enforces content rules on new and edited guild messages, and
+// the anti-spam rule on new ones.
+type MessageHandler struct {
+	rules    *rules.Set
+	warnings *warnings.Store
+	spam     *spamTracker
+	logger   zerolog.Logger
+}
+
+// NewMessageHandler creates a message handler that checks messages against set
+// and records warn actions in store, applying the warn-escalation rule as the
+// warn command does. The store may be nil, in which case warn actions only
+// delete the message.
+func NewMessageHandler(set *rules.Set, store *warnings.Store, logger zerolog.Logger) *MessageHandler {
+	return &MessageHandler{
+		rules:    set,
+		warnings: store,
+		spam:     newSpamTracker(),
+		logger:   logger,
+	}
+}
+
+// HandleCreate processes the MessageCreate event from Discord.
+func (h *MessageHandler) HandleCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m == nil {
+		return
+	}
+	h.enforce(s, m.Message, true)
+}
+
+// HandleUpdate processes the MessageUpdate event from Discord, so text edited
+// into a message after it was posted is held to the same rules.
+// Updates without content, such as embeds being attached to a link, and
+// updates that leave the content unchanged are ignored.
+func (h *MessageHandler) HandleUpdate(s *discordgo.Session, m *discordgo.MessageUpdate) {
+	if m == nil || m.Message == nil {
+		return
+	}
+	if m.BeforeUpdate != nil && m.BeforeUpdate.Content == m.Content {
+		return
+	}
+	h.enforce(s, m.Message, false)
+}
+
+// enforce checks a guild message against the anti-spam rule, if it is new,
+// then against the content rules, and applies the matching rule's action.
+// Messages from bots, including this one, and messages exempted by the ignore
+// rule are skipped before any Discord API call.
+func (h *MessageHandler) enforce(s *discordgo.Session, m *discordgo.Message, isNew bool) {
+	if s == nil || m == nil || m.Author == nil || m.GuildID == "" {
+		return
+	}
+	botID := selfID(s)
+	if m.Author.Bot || (botID != "" && m.Author.ID == botID) {
+		return
+	}
+
+	// Exemptions are checked first so ignored channels cost no further work
+	var roleIDs []string
+	if m.Member != nil {
+		roleIDs = m.Member.Roles
+	}
+	if h.rules.Ignored(m.GuildID, m.ChannelID, m.Author.ID, roleIDs) {
+		return
+	}
+
+	if isNew && h.limitSpam(s, m) {
+		return
+	}
+	if m.Content == "" {
+		return
+	}
+
+	violation, ok := h.rules.CheckContent(m.GuildID, m.Content)
+	if !ok {
+		return
+	}
+
+	logger := h.logger.With().
+		Str("guild_id", m.GuildID).
+		Str("channel_id", m.ChannelID).
+		Str("message_id", m.ID).
+		Str("user_id", m.Author.ID).
+		Str("rule", violation.Rule).
+		Str("action", violation.Action).
+		Logger()
+
+	if err := s.ChannelMessageDelete(m.ChannelID, m.ID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete message that broke a content rule")
+		return
+	}
+
+	reason := fmt.Sprintf("Message broke the %s rule", violation.Rule)
+	switch violation.Action {
+	case rules.ActionWarn:
+		count := h.warnings.Add(warnings.Warning{
+			GuildID:     m.GuildID,
+			UserID:      m.Author.ID,
+			ModeratorID: botID,
+			Reason:      reason,
+		})
+		logger.Info().Int("warnings", count).Msg("content rule warning recorded")
+		command.PostModLog(s, h.rules, m.GuildID, command.ModLogEntry{
+			Action:   command.ModLogWarned,
+			TargetID: m.Author.ID,
+			Source:   fmt.Sprintf("the %s rule", violation.Rule),
+			Reason:   reason,
+		}, logger)
+		if h.warnings != nil {
+			// Escalate logs the step it applies, or why it failed
+			_, _, _ = command.Escalate(s, h.rules, m.GuildID, m.Author.ID, count, logger)
+		}
+	case rules.ActionTimeout:
+		until := time.Now().Add(ContentTimeout)
+		if err := s.GuildMemberTimeout(m.GuildID, m.Author.ID, &until); err != nil {
+			logger.Error().Err(err).Msg("failed to time out member for content rule")
+			return
+		}
+		command.PostModLog(s, h.rules, m.GuildID, command.ModLogEntry{
+			Action:   command.ModLogTimedOut,
+			TargetID: m.Author.ID,
+			Source:   fmt.Sprintf("the %s rule", violation.Rule),
+			Reason:   reason,
+			Duration: ContentTimeout,
+		}, logger)
+	}
+
+	logger.Info().Msg("content rule enforced")
+}
+
+// limitSpam records m against its author's anti-spam limit and deletes it if
+// it goes over, reporting whether it did. Counting messages needs no Message
+// Content, so it also works for messages whose text the bot cannot read.
+func (h *MessageHandler) limitSpam(s *discordgo.Session, m *discordgo.Message) bool {
+	threshold, window, ok := h.rules.SpamLimit(m.GuildID)
+	if !ok {
+		return false
+	}
+
+	sent := h.spam.record(spamKey{guildID: m.GuildID, userID: m.Author.ID}, time.Now(), window)
+	if sent <= threshold {
+		return false
+	}
+
+	logger := h.logger.With().
+		Str("guild_id", m.GuildID).
+		Str("channel_id", m.ChannelID).
+		Str("message_id", m.ID).
+		Str("user_id", m.Author.ID).
+		Str("rule", rules.RuleAntiSpam).
+		Logger()
+	if sent == threshold+1 {
+		logger.Info().
+			Int("threshold", threshold).
+			Dur("window", window).
+			Msg("member went over the anti-spam limit")
+	}
+	if err := s.ChannelMessageDelete(m.ChannelID, m.ID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete message over the anti-spam limit")
+	}
+	return true
+}
+
+// selfID returns the bot's own user ID, or "" before the Ready event.
+func selfID(s *discordgo.Session) string {
+	if s.State == nil || s.State.User == nil {
+		return ""
+	}
+	return s.State.User.ID
+}
@@ -0,0 +1,357 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"jamesbot/internal/handler"
+	"jamesbot/internal/rules"
+	"jamesbot/internal/warnings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newContentRules returns the default rules with the word filter blocking "spam".
+func newContentRules(t *testing.T, action string) *rules.Set {
+	t.Helper()
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule(rules.RuleWordFilter, rules.KeyEnabled, "true"))
+	require.NoError(t, set.SetRule(rules.RuleWordFilter, rules.KeyWords, "spam"))
+	require.NoError(t, set.SetRule(rules.RuleWordFilter, rules.KeyAction, action))
+	return set
+}
+
+// newGuildMessage creates a guild message from a regular member.
+func newGuildMessage(content string) *discordgo.Message {
+	return &discordgo.Message{
+		ID:        "msg-1",
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Content:   content,
+		Author:    &discordgo.User{ID: "user-1", Username: "member"},
+	}
+}
+
+func Test_MessageHandler_HandleCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		action       string
+		settings     func(t *testing.T, set *rules.Set)
+		message      *discordgo.Message
+		wantRequests []string
+		wantWarnings int
+	}{
+		{
+			name:         "clean message is left alone",
+			action:       rules.ActionDelete,
+			message:      newGuildMessage("hello there"),
+			wantRequests: nil,
+		},
+		{
+			name:         "delete action removes the message",
+			action:       rules.ActionDelete,
+			message:      newGuildMessage("buy spam"),
+			wantRequests: []string{http.MethodDelete + " /api/v9/channels/channel-1/messages/msg-1"},
+		},
+		{
+			name:         "warn action deletes and records a warning",
+			action:       rules.ActionWarn,
+			message:      newGuildMessage("buy spam"),
+			wantRequests: []string{http.MethodDelete + " /api/v9/channels/channel-1/messages/msg-1"},
+			wantWarnings: 1,
+		},
+		{
+			name:   "warn action applies warn escalation",
+			action: rules.ActionWarn,
+			settings: func(t *testing.T, set *rules.Set) {
+				require.NoError(t, set.SetRule(rules.RuleWarnEscalation, rules.KeyEnabled, "true"))
+				require.NoError(t, set.SetRule(rules.RuleWarnEscalation, rules.KeyEscalationPolicy, "1=kick"))
+			},
+			message: newGuildMessage("buy spam"),
+			wantRequests: []string{
+				http.MethodDelete + " /api/v9/channels/channel-1/messages/msg-1",
+				http.MethodDelete + " /api/v9/guilds/guild-1/members/user-1",
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:   "actions are posted to the mod-log channel",
+			action: rules.ActionWarn,
+			settings: func(t *testing.T, set *rules.Set) {
+				require.NoError(t, set.SetRule(rules.RuleSettings, rules.KeyModLogChannel, "111"))
+				require.NoError(t, set.SetRule(rules.RuleWarnEscalation, rules.KeyEnabled, "true"))
+				require.NoError(t, set.SetRule(rules.RuleWarnEscalation, rules.KeyEscalationPolicy, "1=kick"))
+			},
+			message: newGuildMessage("buy spam"),
+			wantRequests: []string{
+				http.MethodDelete + " /api/v9/channels/channel-1/messages/msg-1",
+				http.MethodPost + " /api/v9/channels/111/messages",
+				http.MethodDelete + " /api/v9/guilds/guild-1/members/user-1",
+				http.MethodPost + " /api/v9/channels/111/messages",
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:    "timeout action deletes and times out the author",
+			action:  rules.ActionTimeout,
+			message: newGuildMessage("buy spam"),
+			wantRequests: []string{
+				http.MethodDelete + " /api/v9/channels/channel-1/messages/msg-1",
+				http.MethodPatch + " /api/v9/guilds/guild-1/members/user-1",
+			},
+		},
+		{
+			name:   "bot authors are ignored",
+			action: rules.ActionDelete,
+			message: func() *discordgo.Message {
+				m := newGuildMessage("buy spam")
+				m.Author.Bot = true
+				return m
+			}(),
+		},
+		{
+			name:   "direct messages are ignored",
+			action: rules.ActionDelete,
+			message: func() *discordgo.Message {
+				m := newGuildMessage("buy spam")
+				m.GuildID = ""
+				return m
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			store := warnings.NewStore()
+			set := newContentRules(t, tt.action)
+			if tt.settings != nil {
+				tt.settings(t, set)
+			}
+			h := handler.NewMessageHandler(set, store, zerolog.Nop())
+
+			h.HandleCreate(s, &discordgo.MessageCreate{Message: tt.message})
+
+			assert.Equal(t, tt.wantRequests, requestLines(rt.recorded()))
+			assert.Equal(t, tt.wantWarnings, store.Count("guild-1", "user-1"))
+		})
+	}
+}
+
+func Test_MessageHandler_HandleUpdate(t *testing.T) {
+	deleted := []string{http.MethodDelete + " /api/v9/channels/channel-1/messages/msg-1"}
+
+	tests := []struct {
+		name         string
+		update       *discordgo.MessageUpdate
+		wantRequests []string
+	}{
+		{
+			name: "blocked word edited in is enforced",
+			update: &discordgo.MessageUpdate{
+				Message:      newGuildMessage("buy spam"),
+				BeforeUpdate: newGuildMessage("buy eggs"),
+			},
+			wantRequests: deleted,
+		},
+		{
+			name:         "edit without cached original is enforced",
+			update:       &discordgo.MessageUpdate{Message: newGuildMessage("buy spam")},
+			wantRequests: deleted,
+		},
+		{
+			name: "unchanged content is skipped",
+			update: &discordgo.MessageUpdate{
+				Message:      newGuildMessage("buy spam"),
+				BeforeUpdate: newGuildMessage("buy spam"),
+			},
+		},
+		{
+			name: "embed-only update without content is skipped",
+			update: &discordgo.MessageUpdate{Message: &discordgo.Message{
+				ID:        "msg-1",
+				ChannelID: "channel-1",
+				GuildID:   "guild-1",
+				Embeds:    []*discordgo.MessageEmbed{{URL: "https://example.com"}},
+			}},
+		},
+		{
+			name: "the bot's own edits are skipped",
+			update: func() *discordgo.MessageUpdate {
+				m := newGuildMessage("buy spam")
+				m.Author = &discordgo.User{ID: "bot-1"}
+				return &discordgo.MessageUpdate{Message: m}
+			}(),
+		},
+		{
+			name:   "nil message is skipped",
+			update: &discordgo.MessageUpdate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			s.State.User = &discordgo.User{ID: "bot-1"}
+			h := handler.NewMessageHandler(newContentRules(t, rules.ActionDelete), nil, zerolog.Nop())
+
+			h.HandleUpdate(s, tt.update)
+
+			assert.Equal(t, tt.wantRequests, requestLines(rt.recorded()))
+		})
+	}
+}
+
+func Test_MessageHandler_IgnoreRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		message func(m *discordgo.Message)
+	}{
+		{name: "ignored channel", key: rules.KeyIgnoreChannels, value: "100", message: func(m *discordgo.Message) { m.ChannelID = "100" }},
+		{name: "ignored user", key: rules.KeyIgnoreUsers, value: "200", message: func(m *discordgo.Message) { m.Author.ID = "200" }},
+		{
+			name:  "ignored role",
+			key:   rules.KeyIgnoreRoles,
+			value: "300",
+			message: func(m *discordgo.Message) {
+				m.Member = &discordgo.Member{Roles: []string{"299", "300"}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			set := newContentRules(t, rules.ActionDelete)
+			require.NoError(t, set.SetRule(rules.RuleIgnore, rules.KeyEnabled, "true"))
+			require.NoError(t, set.SetRule(rules.RuleIgnore, tt.key, tt.value))
+			h := handler.NewMessageHandler(set, nil, zerolog.Nop())
+
+			m := newGuildMessage("buy spam")
+			tt.message(m)
+			h.HandleCreate(s, &discordgo.MessageCreate{Message: m})
+
+			assert.Empty(t, rt.recorded(), "exempt messages must not reach the Discord API")
+		})
+	}
+}
+
+func Test_MessageHandler_AntiSpam(t *testing.T) {
+	const deleteSecond = http.MethodDelete + " /api/v9/channels/channel-1/messages/msg-2"
+
+	tests := []struct {
+		name         string
+		guildRule    bool
+		send         func(h *handler.MessageHandler, s *discordgo.Session)
+		wantRequests []string
+	}{
+		{
+			name: "messages over the threshold are deleted",
+			send: func(h *handler.MessageHandler, s *discordgo.Session) {
+				for _, id := range []string{"msg-0", "msg-1", "msg-2"} {
+					m := newGuildMessage("hello")
+					m.ID = id
+					h.HandleCreate(s, &discordgo.MessageCreate{Message: m})
+				}
+			},
+			wantRequests: []string{deleteSecond},
+		},
+		{
+			name: "messages without readable content are counted",
+			send: func(h *handler.MessageHandler, s *discordgo.Session) {
+				for _, id := range []string{"msg-0", "msg-1", "msg-2"} {
+					m := newGuildMessage("")
+					m.ID = id
+					h.HandleCreate(s, &discordgo.MessageCreate{Message: m})
+				}
+			},
+			wantRequests: []string{deleteSecond},
+		},
+		{
+			name:      "the rule can be enabled per guild",
+			guildRule: true,
+			send: func(h *handler.MessageHandler, s *discordgo.Session) {
+				for _, id := range []string{"msg-0", "msg-1", "msg-2"} {
+					m := newGuildMessage("hello")
+					m.ID = id
+					h.HandleCreate(s, &discordgo.MessageCreate{Message: m})
+				}
+			},
+			wantRequests: []string{deleteSecond},
+		},
+		{
+			name: "members are counted separately",
+			send: func(h *handler.MessageHandler, s *discordgo.Session) {
+				for _, author := range []string{"user-1", "user-2", "user-3"} {
+					m := newGuildMessage("hello")
+					m.Author.ID = author
+					h.HandleCreate(s, &discordgo.MessageCreate{Message: m})
+				}
+			},
+		},
+		{
+			name: "edits are not counted",
+			send: func(h *handler.MessageHandler, s *discordgo.Session) {
+				for _, content := range []string{"hello", "hello!", "hello!!"} {
+					h.HandleUpdate(s, &discordgo.MessageUpdate{Message: newGuildMessage(content)})
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rt := newRecordingSession(t)
+			set := rules.NewSet(rules.Defaults()...)
+			guildID := ""
+			if tt.guildRule {
+				guildID = "guild-1"
+			}
+			require.NoError(t, set.SetGuildRule(guildID, rules.RuleAntiSpam, rules.KeyEnabled, "true"))
+			require.NoError(t, set.SetGuildRule(guildID, rules.RuleAntiSpam, rules.KeyThreshold, "2"))
+			require.NoError(t, set.SetGuildRule(guildID, rules.RuleAntiSpam, rules.KeyWindow, "1m"))
+			h := handler.NewMessageHandler(set, nil, zerolog.Nop())
+
+			tt.send(h, s)
+
+			assert.Equal(t, tt.wantRequests, requestLines(rt.recorded()))
+		})
+	}
+}
+
+func Test_MessageHandler_AntiSpam_Disabled(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	h := handler.NewMessageHandler(rules.NewSet(rules.Defaults()...), nil, zerolog.Nop())
+
+	for range 10 {
+		h.HandleCreate(s, &discordgo.MessageCreate{Message: newGuildMessage("hello")})
+	}
+
+	assert.Empty(t, rt.recorded(), "anti-spam is disabled by default")
+}
+
+func Test_MessageHandler_NilEvents(t *testing.T) {
+	s, rt := newRecordingSession(t)
+	h := handler.NewMessageHandler(newContentRules(t, rules.ActionDelete), nil, zerolog.Nop())
+
+	assert.NotPanics(t, func() {
+		h.HandleCreate(s, nil)
+		h.HandleUpdate(s, nil)
+		h.HandleCreate(nil, &discordgo.MessageCreate{Message: newGuildMessage("buy spam")})
+	})
+	assert.Empty(t, rt.recorded())
+}
+
+// requestLines formats recorded requests as "METHOD path" for comparison.
+func requestLines(reqs []recordedRequest) []string {
+	var lines []string
+	for _, r := range reqs {
+		lines = append(lines, r.Method+" "+r.Path)
+	}
+	return lines
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Default worker pool sizes, used when the configuration leaves them unset.
+const (
+	DefaultPoolWorkers   = 16
+	DefaultPoolQueueSize = 100
+)
+
+// BusyMessage is the ephemeral response sent when an interaction arrives while
+// every worker is busy and the queue is full.
+const BusyMessage = "The bot is busy right now; please try again in a moment."
+
+// WorkerPool runs jobs on a fixed number of goroutines, holding at most a
+// fixed number of waiting jobs, so a flood of interactions cannot grow the
+// bot's goroutines or memory without bound. It is safe for concurrent use.
+type WorkerPool struct {
+	workers int
+	jobs    chan func()
+	active  atomic.Int64
+	wg      sync.WaitGroup
+
+	// mu guards closed so Submit never sends on a closed channel.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewWorkerPool starts workers goroutines that take jobs from a queue holding
+// up to queueSize jobs. Workers below 1 are raised to 1 and a negative
+// queueSize is treated as 0, in which case jobs are accepted only while a
+// worker is idle.
+func NewWorkerPool(workers, queueSize int) *WorkerPool {
+	workers = max(workers, 1)
+	queueSize = max(queueSize, 0)
+
+	p := &WorkerPool{
+		workers: workers,
+		jobs:    make(chan func(), queueSize),
+	}
+	p.wg.Add(workers)
+	for range workers {
+		go p.work()
+	}
+	return p
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		p.active.Add(1)
+		job()
+		p.active.Add(-1)
+	}
+}
+
+// Submit queues job to run on a worker without blocking. It returns false,
+// and job does not run, when the queue is full or the pool is closed.
+func (p *WorkerPool) Submit(job func()) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// Workers returns the number of worker goroutines.
+func (p *WorkerPool) Workers() int {
+	return p.workers
+}
+
+// ActiveWorkers returns how many workers are running a job.
+func (p *WorkerPool) ActiveWorkers() int {
+	return int(p.active.Load())
+}
+
+// QueueDepth returns how many jobs are waiting for a worker.
+func (p *WorkerPool) QueueDepth() int {
+	return len(p.jobs)
+}
+
+// QueueCapacity returns how many jobs may wait for a worker.
+func (p *WorkerPool) QueueCapacity() int {
+	return cap(p.jobs)
+}
+
+// Close stops accepting jobs and waits for queued and running jobs to finish,
+// or for ctx to end. Closing a closed pool only waits.
+func (p *WorkerPool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
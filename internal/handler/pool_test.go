@@ -0,0 +1,187 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/handler"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// WorkerPool Tests
+// ============================================================================
+
+func Test_WorkerPool_CapsConcurrency(t *testing.T) {
+	const workers = 3
+
+	pool := handler.NewWorkerPool(workers, 50)
+	release := make(chan struct{})
+
+	var running, peak atomic.Int64
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		require.True(t, pool.Submit(func() {
+			defer wg.Done()
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+		}))
+	}
+
+	require.Eventually(t, func() bool { return pool.ActiveWorkers() == workers }, time.Second, time.Millisecond)
+	assert.Equal(t, 20-workers, pool.QueueDepth())
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(workers), peak.Load(), "no more than the configured workers should run at once")
+	require.NoError(t, pool.Close(context.Background()))
+}
+
+func Test_WorkerPool_RejectsWhenFull(t *testing.T) {
+	tests := []struct {
+		name      string
+		queueSize int
+	}{
+		{name: "bounded queue", queueSize: 2},
+		{name: "no queue", queueSize: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := handler.NewWorkerPool(1, tt.queueSize)
+			release := make(chan struct{})
+			started := make(chan struct{})
+
+			require.Eventually(t, func() bool {
+				return pool.Submit(func() { close(started); <-release })
+			}, time.Second, time.Millisecond)
+			<-started
+
+			for range tt.queueSize {
+				require.True(t, pool.Submit(func() {}))
+			}
+			assert.False(t, pool.Submit(func() { t.Error("rejected job ran") }))
+			assert.Equal(t, tt.queueSize, pool.QueueDepth())
+
+			close(release)
+			require.NoError(t, pool.Close(context.Background()))
+		})
+	}
+}
+
+func Test_WorkerPool_Close(t *testing.T) {
+	pool := handler.NewWorkerPool(1, 10)
+
+	var ran atomic.Int64
+	for range 5 {
+		require.True(t, pool.Submit(func() { ran.Add(1) }))
+	}
+
+	require.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, int64(5), ran.Load(), "queued jobs should finish before Close returns")
+	assert.False(t, pool.Submit(func() {}), "a closed pool should reject jobs")
+	assert.NoError(t, pool.Close(context.Background()), "closing twice should be safe")
+}
+
+func Test_WorkerPool_CloseHonorsContext(t *testing.T) {
+	pool := handler.NewWorkerPool(1, 0)
+	release := make(chan struct{})
+	defer close(release)
+	require.Eventually(t, func() bool {
+		return pool.Submit(func() { <-release })
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, pool.Close(ctx), context.DeadlineExceeded)
+}
+
+func Test_NewWorkerPool_Sizes(t *testing.T) {
+	pool := handler.NewWorkerPool(0, -1)
+	defer pool.Close(context.Background())
+
+	assert.Equal(t, 1, pool.Workers())
+	assert.Equal(t, 0, pool.QueueCapacity())
+}
+
+// ============================================================================
+// InteractionHandler Worker Pool Tests
+// ============================================================================
+
+func Test_InteractionHandler_Handle_WorkerPool(t *testing.T) {
+	logger := newInteractionLogCapture().logger()
+	done := make(chan struct{})
+	cmd := newMockCommand("ping")
+	cmd.executeFunc = func(ctx *command.Context) error {
+		close(done)
+		return nil
+	}
+	h := handler.NewInteractionHandler(createTestRegistry(logger, cmd), noopMiddleware(), logger)
+	pool := handler.NewWorkerPool(1, 1)
+	defer pool.Close(context.Background())
+	h.SetWorkerPool(pool)
+
+	h.Handle(nil, createTestInteraction("ping", discordgo.InteractionApplicationCommand))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("command did not run on the pool")
+	}
+}
+
+func Test_InteractionHandler_Handle_BusyPool(t *testing.T) {
+	capture := newInteractionLogCapture()
+	logger := capture.logger()
+	cmd := newMockCommand("ping")
+	cmd.executeFunc = func(ctx *command.Context) error {
+		t.Error("command should not run while the pool is full")
+		return nil
+	}
+	h := handler.NewInteractionHandler(createTestRegistry(logger, cmd), noopMiddleware(), logger)
+
+	// Occupy the only worker, with no room to queue
+	pool := handler.NewWorkerPool(1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.Eventually(t, func() bool {
+		return pool.Submit(func() { close(started); <-release })
+	}, time.Second, time.Millisecond)
+	<-started
+	h.SetWorkerPool(pool)
+
+	session, transport := newRecordingSession(t)
+	interaction := createTestInteraction("ping", discordgo.InteractionApplicationCommand)
+	interaction.Token = "test-token"
+
+	h.Handle(session, interaction)
+	close(release)
+	require.NoError(t, pool.Close(context.Background()))
+
+	requests := transport.recorded()
+	require.Len(t, requests, 1, "handler should send exactly one response")
+	var resp discordgo.InteractionResponse
+	require.NoError(t, json.Unmarshal(requests[0].Body, &resp))
+	require.NotNil(t, resp.Data)
+	assert.Equal(t, handler.BusyMessage, resp.Data.Content)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, resp.Data.Flags)
+	assert.True(t, capture.containsLevel("warn"), "a rejected interaction should be logged as a warning")
+}
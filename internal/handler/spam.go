@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"sync"
+	"time"
+)
+
+// spamSweepInterval is how often members who have stopped sending messages
+// are forgotten by the spam tracker.
+const spamSweepInterval = time.Minute
+
+// spamKey identifies a member of a guild.
+type spamKey struct {
+	guildID string
+	userID  string
+}
+
+// spamHistory is when a member sent their recent messages, oldest first.
+type spamHistory struct {
+	sent   []time.Time
+	window time.Duration
+}
+
+// spamTracker counts the messages each member sent recently, for the
+// anti-spam rule. It is safe for concurrent use.
+type spamTracker struct {
+	mu        sync.Mutex
+	members   map[spamKey]*spamHistory
+	lastSweep time.Time
+}
+
+// newSpamTracker creates an empty spam tracker.
+func newSpamTracker() *spamTracker {
+	return &spamTracker{members: make(map[spamKey]*spamHistory)}
+}
+
+// record notes a message sent by key at now and returns how many messages
+// they have sent within window of it, including this one.
+func (t *spamTracker) record(key spamKey, now time.Time, window time.Duration) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastSweep) >= spamSweepInterval {
+		t.sweep(now)
+	}
+
+	history, ok := t.members[key]
+	if !ok {
+		history = &spamHistory{}
+		t.members[key] = history
+	}
+	history.window = window
+	history.sent = append(expire(history.sent, now.Add(-window)), now)
+	return len(history.sent)
+}
+
+// sweep forgets members with no messages within their window of now. The
+// caller must hold t.mu.
+func (t *spamTracker) sweep(now time.Time) {
+	for key, history := range t.members {
+		if len(expire(history.sent, now.Add(-history.window))) == 0 {
+			delete(t.members, key)
+		}
+	}
+	t.lastSweep = now
+}
+
+// expire drops the times in sent, oldest first, that are not after cutoff.
+func expire(sent []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(sent) && !sent[i].After(cutoff) {
+		i++
+	}
+	return sent[i:]
+}
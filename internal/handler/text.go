@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/i18n"
+	"jamesbot/pkg/errutil"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// TextCommandHandler runs commands written as guild messages starting with a
+// prefix, such as "!ban @user spam", alongside slash commands. Commands are
+// looked up in the interaction handler's registry and run through its
+// middleware chain and worker pool, replying to the message instead of to an
+// interaction. The prefix, and the language of replies, come from the guild
+// settings of the interaction handler, if set. Reading messages needs the
+// Message Content intent.
+type TextCommandHandler struct {
+	// prefix is used when the interaction handler has no guild settings.
+	prefix   string
+	commands *InteractionHandler
+	logger   zerolog.Logger
+
+	// deniedMessage replaces the localized reply to members who lack a
+	// command's permissions when set.
+	deniedMessage string
+
+	// denials notifies moderators of repeated denied attempts; nil disables.
+	denials *DenialTracker
+}
+
+// NewTextCommandHandler creates a handler that runs messages starting with
+// prefix as commands through commands. Once commands has guild settings,
+// each guild's prefix is used instead.
+func NewTextCommandHandler(prefix string, commands *InteractionHandler, logger zerolog.Logger) *TextCommandHandler {
+	return &TextCommandHandler{
+		prefix:   prefix,
+		commands: commands,
+		logger:   logger,
+	}
+}
+
+// SetDeniedMessage makes msg the reply to members who lack the permissions a
+// command requires, in place of the localized default. An empty msg restores
+// the default.
+func (h *TextCommandHandler) SetDeniedMessage(msg string) {
+	if h != nil {
+		h.deniedMessage = msg
+	}
+}
+
+// SetDenialTracker makes the handler record denied attempts in tracker and
+// post its notifications about members who keep trying to its channel. A nil
+// tracker disables notifications.
+func (h *TextCommandHandler) SetDenialTracker(tracker *DenialTracker) {
+	if h != nil {
+		h.denials = tracker
+	}
+}
+
+// HandleCreate processes the MessageCreate event from Discord. Messages from
+// bots and messages naming no registered command are ignored, since other
+// bots in the server may share the prefix.
+func (h *TextCommandHandler) HandleCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if s == nil || m == nil || m.Message == nil || m.Author == nil || m.Author.Bot {
+		return
+	}
+
+	prefix, settingsLocale := h.prefix, ""
+	if settings := h.commands.settings; settings != nil {
+		guildSettings := settings.GuildSettings(m.GuildID)
+		prefix, settingsLocale = guildSettings.Prefix, guildSettings.Locale
+	}
+
+	name, args, ok := command.ParseTextCommand(m.Content, prefix)
+	if !ok {
+		return
+	}
+	cmd, exists := h.commands.registry.Get(name)
+	if !exists {
+		h.logger.Debug().Str("command", name).Msg("ignoring text command not in registry")
+		return
+	}
+
+	logger := h.logger.With().
+		Str("command", name).
+		Str("guild_id", m.GuildID).
+		Str("channel_id", m.ChannelID).
+		Str("user_id", m.Author.ID).
+		Logger()
+
+	catalog := i18n.Default()
+	locale := settingsLocale
+	if locale == "" {
+		locale = guildLocale(s, m.GuildID)
+	}
+
+	// Discord enforces slash command permissions itself; text commands must
+	// be checked here
+	var permissions int64
+	if permissioned, ok := cmd.(command.PermissionedCommand); ok {
+		if m.GuildID == "" {
+			h.reply(s, m.Message, logger, catalog.T(locale, i18n.MsgGuildOnly))
+			return
+		}
+		perms, err := memberPermissions(s, m.Message)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to compute permissions for text command")
+			h.reply(s, m.Message, logger, h.deniedReply(locale))
+			return
+		}
+		if required := permissioned.Permissions(); perms&required != required {
+			h.deny(s, m.Message, logger, locale, prefix+name, required, perms)
+			return
+		}
+		permissions = perms
+	}
+
+	i, err := command.NewTextInteraction(m.Message, cmd, args)
+	if err != nil {
+		msg := err.Error()
+		var userErr errutil.UserFriendlyError
+		if errors.As(err, &userErr) {
+			msg = userErr.UserMessage
+		}
+		h.reply(s, m.Message, logger, msg+"\n"+catalog.T(locale, i18n.MsgTextUsage, command.TextUsage(prefix, cmd)))
+		return
+	}
+	if i.Member != nil {
+		// Lets commands check permissions as they would on an interaction
+		i.Member.Permissions = permissions
+	}
+	if locale != "" {
+		discordLocale := discordgo.Locale(locale)
+		i.GuildLocale = &discordLocale
+	}
+
+	withMessage := command.WithMessage(m.Message)
+	h.commands.dispatch(s, i, "command", name, func() { h.commands.execute(s, i, cmd, withMessage) }, withMessage)
+}
+
+// deny refuses to run the named command for m's author, who has perms but
+// lacks some of required. The attempt is logged as a warning, since members
+// trying commands they cannot use may be probing for a misconfiguration, and
+// counted toward notifying moderators.
+func (h *TextCommandHandler) deny(s *discordgo.Session, m *discordgo.Message, logger zerolog.Logger, locale, name string, required, perms int64) {
+	logger.Warn().
+		Int64("required_permissions", required).
+		Int64("missing_permissions", required&^perms).
+		Msg("command denied: missing permissions")
+	h.reply(s, m, logger, h.deniedReply(locale))
+
+	alert, ok := h.denials.Record(time.Now(), m.GuildID, m.Author.ID, name)
+	if !ok {
+		return
+	}
+	if _, err := s.ChannelMessageSendComplex(h.denials.channelID, &discordgo.MessageSend{
+		Content:         alert,
+		AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+	}); err != nil {
+		logger.Error().Err(err).Str("channel_id", h.denials.channelID).Msg("failed to post permission denial alert")
+	}
+}
+
+// deniedReply returns the reply to members who lack a command's permissions.
+func (h *TextCommandHandler) deniedReply(locale string) string {
+	if h.deniedMessage != "" {
+		return h.deniedMessage
+	}
+	return i18n.Default().T(locale, i18n.MsgNoPermission)
+}
+
+// reply sends content as a reply to m that mentions no one.
+func (h *TextCommandHandler) reply(s *discordgo.Session, m *discordgo.Message, logger zerolog.Logger, content string) {
+	if _, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content:         content,
+		Reference:       m.Reference(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+	}); err != nil {
+		logger.Debug().Err(err).Msg("failed to reply to text command")
+	}
+}
+
+// memberPermissions returns the permissions of m's author in m's channel,
+// from the state cache when possible and otherwise from Discord.
+func memberPermissions(s *discordgo.Session, m *discordgo.Message) (int64, error) {
+	if s.State != nil {
+		if perms, err := s.State.UserChannelPermissions(m.Author.ID, m.ChannelID); err == nil {
+			return perms, nil
+		}
+	}
+	return s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+}
+
+// guildLocale returns the preferred locale of the guild from the state
+// cache, or "" if it is unknown. Messages carry no locale of their own.
+func guildLocale(s *discordgo.Session, guildID string) string {
+	if s.State == nil || guildID == "" {
+		return ""
+	}
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		return ""
+	}
+	return guild.PreferredLocale
+}
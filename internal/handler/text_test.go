@@ -0,0 +1,300 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/handler"
+	"jamesbot/internal/rules"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// permissionedMockCommand is a mockCommand that requires permissions.
+type permissionedMockCommand struct {
+	*mockCommand
+	permissions int64
+}
+
+func (m *permissionedMockCommand) Permissions() int64 { return m.permissions }
+
+// textGuildState caches a guild where member "mod" has the kick permission
+// and member "user" has none.
+func textGuildState(t *testing.T, s *discordgo.Session) {
+	t.Helper()
+	require.NoError(t, s.State.GuildAdd(&discordgo.Guild{
+		ID:              "guild-1",
+		OwnerID:         "owner",
+		PreferredLocale: "es-ES",
+		Roles: []*discordgo.Role{
+			{ID: "guild-1"},
+			{ID: "mods", Permissions: discordgo.PermissionKickMembers},
+		},
+	}))
+	require.NoError(t, s.State.ChannelAdd(&discordgo.Channel{ID: "channel-1", GuildID: "guild-1"}))
+	require.NoError(t, s.State.MemberAdd(&discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "mod"}, Roles: []string{"mods"}}))
+	require.NoError(t, s.State.MemberAdd(&discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "user"}}))
+}
+
+// createTextMessage creates a MessageCreate event from authorID in channel-1 of guild-1.
+func createTextMessage(authorID, content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:        "message-1",
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Content:   content,
+		Author:    &discordgo.User{ID: authorID, Username: authorID},
+		Member:    &discordgo.Member{},
+	}}
+}
+
+// replies returns the content of messages sent to channel-1.
+func replies(rt *recordingTransport) []string {
+	var contents []string
+	for _, req := range rt.recorded() {
+		if req.Method == http.MethodPost && req.Path == "/api/v9/channels/channel-1/messages" {
+			var sent discordgo.MessageSend
+			_ = json.Unmarshal(req.Body, &sent)
+			contents = append(contents, sent.Content)
+		}
+	}
+	return contents
+}
+
+// =============================================================================
+// TextCommandHandler Tests
+// =============================================================================
+
+func Test_TextCommandHandler_HandleCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		bot          bool
+		wantExecuted bool
+		wantReply    string
+	}{
+		{name: "runs registered command", content: "!echo hello there", wantExecuted: true},
+		{name: "name is case-insensitive", content: "!ECHO hello", wantExecuted: true},
+		{name: "ignores unknown command", content: "!play song"},
+		{name: "ignores messages without prefix", content: "echo hello"},
+		{name: "ignores bots", content: "!echo hello", bot: true},
+		{name: "invalid options reply with usage", content: "!echo", wantReply: "!echo <message>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			echo := newMockCommand("echo")
+			echo.options = []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "message", Description: "Text to echo", Required: true},
+			}
+			h := handler.NewInteractionHandler(createTestRegistry(zerolog.Nop(), echo), nil, zerolog.Nop())
+			text := handler.NewTextCommandHandler("!", h, zerolog.Nop())
+
+			m := createTextMessage("user", tt.content)
+			m.Author.Bot = tt.bot
+			text.HandleCreate(session, m)
+
+			assert.Equal(t, tt.wantExecuted, echo.executed)
+			if tt.wantExecuted {
+				ctx := echo.executedCtx
+				require.NotNil(t, ctx)
+				assert.Same(t, m.Message, ctx.Message, "responses should reply to the message")
+				assert.Equal(t, "user", ctx.UserID())
+				assert.NotEmpty(t, ctx.StringOption("message"))
+			}
+			if tt.wantReply != "" {
+				require.Len(t, replies(rt), 1)
+				assert.Contains(t, replies(rt)[0], tt.wantReply)
+			} else if !tt.wantExecuted {
+				assert.Empty(t, rt.recorded(), "ignored messages should get no reply")
+			}
+		})
+	}
+}
+
+func Test_TextCommandHandler_HandleCreate_Permissions(t *testing.T) {
+	tests := []struct {
+		name         string
+		authorID     string
+		guildID      string
+		wantExecuted bool
+		wantReply    string
+	}{
+		{name: "member with permission", authorID: "mod", guildID: "guild-1", wantExecuted: true},
+		{name: "guild owner", authorID: "owner", guildID: "guild-1", wantExecuted: true},
+		{name: "member without permission", authorID: "user", guildID: "guild-1", wantReply: "No tienes permiso"},
+		{name: "direct message", authorID: "mod", guildID: "", wantReply: "server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			textGuildState(t, session)
+			kick := &permissionedMockCommand{mockCommand: newMockCommand("kick"), permissions: discordgo.PermissionKickMembers}
+			registry := command.NewRegistry(zerolog.Nop())
+			require.NoError(t, registry.Register(kick))
+			h := handler.NewInteractionHandler(registry, nil, zerolog.Nop())
+			text := handler.NewTextCommandHandler("!", h, zerolog.Nop())
+
+			m := createTextMessage(tt.authorID, "!kick")
+			m.GuildID = tt.guildID
+			text.HandleCreate(session, m)
+
+			assert.Equal(t, tt.wantExecuted, kick.executed)
+			if tt.wantExecuted {
+				member := kick.executedCtx.Interaction.Member
+				require.NotNil(t, member)
+				assert.NotZero(t, member.Permissions&discordgo.PermissionKickMembers,
+					"the member's permissions should be set for the command")
+				assert.Equal(t, "es-ES", kick.executedCtx.Locale())
+			}
+			if tt.wantReply != "" {
+				require.Len(t, replies(rt), 1)
+				assert.Contains(t, replies(rt)[0], tt.wantReply)
+			}
+		})
+	}
+}
+
+func Test_TextCommandHandler_HandleCreate_Denied(t *testing.T) {
+	tests := []struct {
+		name          string
+		deniedMessage string
+		wantReply     string
+	}{
+		{name: "localized default reply", wantReply: "No tienes permiso para usar este comando."},
+		{name: "configured reply", deniedMessage: "Moderators only.", wantReply: "Moderators only."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			textGuildState(t, session)
+			kick := &permissionedMockCommand{mockCommand: newMockCommand("kick"), permissions: discordgo.PermissionKickMembers}
+			registry := command.NewRegistry(zerolog.Nop())
+			require.NoError(t, registry.Register(kick))
+			lc := newLogCapture()
+			h := handler.NewInteractionHandler(registry, nil, zerolog.Nop())
+			text := handler.NewTextCommandHandler("!", h, lc.logger())
+			text.SetDeniedMessage(tt.deniedMessage)
+
+			text.HandleCreate(session, createTextMessage("user", "!kick"))
+
+			assert.False(t, kick.executed)
+			assert.Equal(t, []string{tt.wantReply}, replies(rt))
+
+			var entry map[string]interface{}
+			for _, e := range lc.entries() {
+				if e["message"] == "command denied: missing permissions" {
+					entry = e
+				}
+			}
+			require.NotNil(t, entry, "the denial should be logged")
+			assert.Equal(t, "warn", entry["level"])
+			assert.Equal(t, "kick", entry["command"])
+			assert.Equal(t, "user", entry["user_id"])
+			assert.Equal(t, "guild-1", entry["guild_id"])
+			assert.EqualValues(t, discordgo.PermissionKickMembers, entry["missing_permissions"])
+		})
+	}
+}
+
+func Test_TextCommandHandler_HandleCreate_GuildSettings(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetDefault(rules.RuleSettings, rules.KeyPrefix, "!"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleSettings, rules.KeyPrefix, "?"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleSettings, rules.KeyLocale, "de"))
+	require.NoError(t, set.SetGuildRule("guild-2", rules.RuleSettings, rules.KeyPrefix, ""))
+	echoOptions := []*discordgo.ApplicationCommandOption{
+		{Type: discordgo.ApplicationCommandOptionString, Name: "message", Description: "Text to echo", Required: true},
+	}
+
+	tests := []struct {
+		name         string
+		guildID      string
+		content      string
+		wantExecuted bool
+	}{
+		{name: "guild prefix", guildID: "guild-1", content: "?echo hi", wantExecuted: true},
+		{name: "global prefix replaced by guild's", guildID: "guild-1", content: "!echo hi"},
+		{name: "other guild uses global prefix", guildID: "guild-3", content: "!echo hi", wantExecuted: true},
+		{name: "guild without text commands", guildID: "guild-2", content: "!echo hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, rt := newRecordingSession(t)
+			echo := newMockCommand("echo")
+			echo.options = echoOptions
+			h := handler.NewInteractionHandler(createTestRegistry(zerolog.Nop(), echo), nil, zerolog.Nop())
+			h.SetGuildSettings(set)
+			text := handler.NewTextCommandHandler("!", h, zerolog.Nop())
+
+			m := createTextMessage("user", tt.content)
+			m.GuildID = tt.guildID
+			text.HandleCreate(session, m)
+
+			assert.Equal(t, tt.wantExecuted, echo.executed)
+			if !tt.wantExecuted {
+				assert.Empty(t, rt.recorded(), "ignored messages should get no reply")
+			}
+		})
+	}
+
+	t.Run("guild locale", func(t *testing.T) {
+		session, _ := newRecordingSession(t)
+		textGuildState(t, session)
+		echo := newMockCommand("echo")
+		echo.options = echoOptions
+		h := handler.NewInteractionHandler(createTestRegistry(zerolog.Nop(), echo), nil, zerolog.Nop())
+		h.SetGuildSettings(set)
+
+		handler.NewTextCommandHandler("!", h, zerolog.Nop()).HandleCreate(session, createTextMessage("user", "?echo hi"))
+
+		require.True(t, echo.executed)
+		assert.Equal(t, "de", echo.executedCtx.GuildLocale(), "the setting should replace the guild's preferred locale")
+	})
+}
+
+func Test_TextCommandHandler_HandleCreate_DenialAlert(t *testing.T) {
+	session, rt := newRecordingSession(t)
+	textGuildState(t, session)
+	kick := &permissionedMockCommand{mockCommand: newMockCommand("kick"), permissions: discordgo.PermissionKickMembers}
+	registry := command.NewRegistry(zerolog.Nop())
+	require.NoError(t, registry.Register(kick))
+	h := handler.NewInteractionHandler(registry, nil, zerolog.Nop())
+	text := handler.NewTextCommandHandler("!", h, zerolog.Nop())
+	text.SetDenialTracker(handler.NewDenialTracker("alerts-channel", 2, time.Minute))
+
+	alerts := func() []string {
+		var contents []string
+		for _, req := range rt.recorded() {
+			if req.Method == http.MethodPost && req.Path == "/api/v9/channels/alerts-channel/messages" {
+				var sent discordgo.MessageSend
+				require.NoError(t, json.Unmarshal(req.Body, &sent))
+				require.NotNil(t, sent.AllowedMentions)
+				assert.Empty(t, sent.AllowedMentions.Parse, "alerts should not ping the member")
+				contents = append(contents, sent.Content)
+			}
+		}
+		return contents
+	}
+
+	text.HandleCreate(session, createTextMessage("mod", "!kick"))
+	text.HandleCreate(session, createTextMessage("user", "!kick"))
+	assert.Empty(t, alerts(), "a single denial should not alert")
+
+	text.HandleCreate(session, createTextMessage("user", "!kick"))
+	got := alerts()
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "<@user>")
+	assert.Contains(t, got[0], "`!kick`")
+	assert.Len(t, replies(rt), 2, "every denied attempt is still answered")
+}
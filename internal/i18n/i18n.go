@@ -0,0 +1,116 @@
+// Package i18n provides a message catalog for localized bot responses.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale used when a message has no translation for the
+// requested locale. Locales are Discord locale codes, such as "en-US" or "de".
+const DefaultLocale = "en-US"
+
+// Catalog holds messages keyed by locale and message ID. It is safe for
+// concurrent use.
+type Catalog struct {
+	fallback string
+
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewCatalog creates an empty catalog that falls back to the fallback locale.
+func NewCatalog(fallback string) *Catalog {
+	return &Catalog{
+		fallback: fallback,
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Add adds messages, keyed by message ID, for locale, replacing existing
+// messages with the same IDs.
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing, ok := c.messages[locale]
+	if !ok {
+		existing = make(map[string]string, len(messages))
+		c.messages[locale] = existing
+	}
+	for id, text := range messages {
+		existing[id] = text
+	}
+}
+
+// Lookup returns the message with id for locale. It tries the locale itself,
+// then its language alone ("es" for "es-ES"), then the fallback locale.
+func (c *Catalog) Lookup(locale, id string) (string, bool) {
+	return c.LookupPreferred([]string{locale}, id)
+}
+
+// LookupPreferred returns the message with id for the first of locales, in
+// order of preference, that has it, such as the user's locale and then the
+// guild's. Each locale is tried as itself and then as its language alone
+// before the next; the fallback locale is tried last. Empty locales are
+// skipped.
+func (c *Catalog) LookupPreferred(locales []string, id string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	candidates := make([]string, 0, 2*len(locales)+1)
+	for _, locale := range locales {
+		if locale == "" {
+			continue
+		}
+		candidates = append(candidates, locale)
+		if language, _, found := strings.Cut(locale, "-"); found {
+			candidates = append(candidates, language)
+		}
+	}
+	candidates = append(candidates, c.fallback)
+
+	for _, candidate := range candidates {
+		if text, ok := c.messages[candidate][id]; ok {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// T returns the message with id for locale, formatted with args as by
+// fmt.Sprintf when any are given. A message missing from every candidate
+// locale is returned as its ID, so gaps show up without breaking replies.
+func (c *Catalog) T(locale, id string, args ...any) string {
+	return c.TPreferred([]string{locale}, id, args...)
+}
+
+// TPreferred is like T, but looks the message up in the first of locales
+// that has it, as by LookupPreferred.
+func (c *Catalog) TPreferred(locales []string, id string, args ...any) string {
+	text, ok := c.LookupPreferred(locales, id)
+	if !ok {
+		return id
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultCatalog *Catalog
+)
+
+// Default returns the built-in catalog of the bot's messages.
+func Default() *Catalog {
+	defaultOnce.Do(func() {
+		defaultCatalog = NewCatalog(DefaultLocale)
+		for locale, messages := range builtin {
+			defaultCatalog.Add(locale, messages)
+		}
+	})
+	return defaultCatalog
+}
@@ -0,0 +1,159 @@
+package i18n_test
+
+import (
+	"sync"
+	"testing"
+
+	"jamesbot/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ===========================================================================
+// Catalog Tests
+// ===========================================================================
+
+func newTestCatalog() *i18n.Catalog {
+	c := i18n.NewCatalog("en-US")
+	c.Add("en-US", map[string]string{
+		"greeting": "Hello, %s!",
+		"farewell": "Goodbye.",
+		"only_en":  "English only",
+	})
+	c.Add("es", map[string]string{
+		"greeting": "¡Hola, %s!",
+		"farewell": "Adiós.",
+	})
+	c.Add("es-MX", map[string]string{
+		"farewell": "Nos vemos.",
+	})
+	return c
+}
+
+func Test_Catalog_T(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		id     string
+		args   []any
+		want   string
+	}{
+		{name: "exact locale", locale: "es-MX", id: "farewell", want: "Nos vemos."},
+		{name: "language of regional locale", locale: "es-ES", id: "farewell", want: "Adiós."},
+		{name: "language alone", locale: "es", id: "greeting", args: []any{"Ana"}, want: "¡Hola, Ana!"},
+		{name: "regional locale falls back to language", locale: "es-MX", id: "greeting", args: []any{"Ana"}, want: "¡Hola, Ana!"},
+		{name: "missing translation falls back to default", locale: "es-ES", id: "only_en", want: "English only"},
+		{name: "unknown locale falls back to default", locale: "ja", id: "greeting", args: []any{"Ana"}, want: "Hello, Ana!"},
+		{name: "empty locale uses default", locale: "", id: "farewell", want: "Goodbye."},
+		{name: "unknown ID is returned as is", locale: "es", id: "missing.id", want: "missing.id"},
+		{name: "no args leaves verbs alone", locale: "en-US", id: "greeting", want: "Hello, %s!"},
+	}
+
+	c := newTestCatalog()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.T(tt.locale, tt.id, tt.args...))
+		})
+	}
+}
+
+func Test_Catalog_TPreferred(t *testing.T) {
+	tests := []struct {
+		name    string
+		locales []string
+		id      string
+		want    string
+	}{
+		{name: "first locale wins", locales: []string{"es-MX", "en-US"}, id: "farewell", want: "Nos vemos."},
+		{name: "first locale's language before the next locale", locales: []string{"es-MX", "en-US"}, id: "greeting", want: "¡Hola, %s!"},
+		{name: "untranslated locale falls back to the next", locales: []string{"ja", "es"}, id: "farewell", want: "Adiós."},
+		{name: "empty locale is skipped", locales: []string{"", "es-MX"}, id: "farewell", want: "Nos vemos."},
+		{name: "missing everywhere falls back to default", locales: []string{"ja", "es-MX"}, id: "only_en", want: "English only"},
+		{name: "no locales uses default", id: "farewell", want: "Goodbye."},
+	}
+
+	c := newTestCatalog()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.TPreferred(tt.locales, tt.id))
+		})
+	}
+}
+
+func Test_Catalog_Lookup(t *testing.T) {
+	c := newTestCatalog()
+
+	text, ok := c.Lookup("es-ES", "farewell")
+	assert.True(t, ok)
+	assert.Equal(t, "Adiós.", text)
+
+	_, ok = c.Lookup("es-ES", "missing.id")
+	assert.False(t, ok)
+}
+
+func Test_Catalog_Add_Merges(t *testing.T) {
+	c := newTestCatalog()
+	c.Add("es", map[string]string{"farewell": "Chao."})
+
+	assert.Equal(t, "Chao.", c.T("es", "farewell"))
+	assert.Equal(t, "¡Hola, Ana!", c.T("es", "greeting", "Ana"), "other messages are kept")
+}
+
+func Test_Catalog_Concurrent(t *testing.T) {
+	c := newTestCatalog()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Add("de", map[string]string{"farewell": "Tschüss."})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = c.T("de", "farewell")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "Tschüss.", c.T("de", "farewell"))
+}
+
+// ===========================================================================
+// Built-in Catalog Tests
+// ===========================================================================
+
+func Test_Default_Translations(t *testing.T) {
+	c := i18n.Default()
+
+	assert.Same(t, c, i18n.Default(), "the built-in catalog is built once")
+	assert.Equal(t, "Cancelled; nothing was done.", c.T(i18n.DefaultLocale, i18n.MsgConfirmCancelled))
+	assert.Equal(t, "Cancelado; no se hizo nada.", c.T("es-ES", i18n.MsgConfirmCancelled))
+	assert.Equal(t, "Abbrechen", c.T("de", i18n.MsgCancelButton))
+	assert.Equal(t, "You cannot ban bots.", c.T("fr", i18n.MsgBanBot), "untranslated locales use English")
+}
+
+func Test_Default_FormatArgsMatch(t *testing.T) {
+	c := i18n.Default()
+
+	// Every translation must take the same arguments as the English message,
+	// or replies would show %!d(MISSING)-style noise.
+	for _, locale := range []string{"es", "de"} {
+		for _, id := range []string{i18n.MsgBanSuccess, i18n.MsgMuteSuccess, i18n.MsgKickFailed, i18n.MsgBanForbidden, i18n.MsgRateLimited, i18n.MsgBanDeleted} {
+			t.Run(locale+"/"+id, func(t *testing.T) {
+				var args []any
+				switch id {
+				case i18n.MsgRateLimited, i18n.MsgBanDeleted:
+					args = []any{3}
+				case i18n.MsgKickFailed, i18n.MsgBanForbidden:
+					args = []any{"user"}
+				case i18n.MsgBanSuccess:
+					args = []any{"user", "0001", "spam"}
+				case i18n.MsgMuteSuccess:
+					args = []any{"user", "0001", "1 hour", "spam"}
+				}
+				assert.NotContains(t, c.T(locale, id, args...), "%!")
+			})
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package i18n
+
+// Message IDs of the built-in messages.
+const (
+	MsgGuildOnly     = "guild_only"
+	MsgDMOnly        = "dm_only"
+	MsgRateLimited   = "rate_limited"
+	MsgCooldown      = "cooldown"
+	MsgNoPermission  = "no_permission"
+	MsgMissingAccess = "missing_access"
+	MsgMaintenance   = "maintenance"
+
+	MsgGuildNotAllowed = "guild_not_allowed"
+
+	MsgTextUsage       = "text.usage"
+	MsgTextMissingArg  = "text.missing_arg"
+	MsgTextInvalidArg  = "text.invalid_arg"
+	MsgTextTooManyArgs = "text.too_many_args"
+	MsgTextUnsupported = "text.unsupported"
+
+	MsgNotified    = "notify.sent"
+	MsgNotNotified = "notify.failed"
+
+	MsgConfirmButton     = "confirm.button.confirm"
+	MsgCancelButton      = "confirm.button.cancel"
+	MsgConfirmCancelled  = "confirm.cancelled"
+	MsgConfirmTimeout    = "confirm.timeout"
+	MsgConfirmExpired    = "confirm.expired"
+	MsgConfirmNotInvoker = "confirm.not_invoker"
+
+	MsgKickSelf      = "kick.self"
+	MsgKickBot       = "kick.bot"
+	MsgKickFailed    = "kick.failed"
+	MsgKickForbidden = "kick.forbidden"
+	MsgKickSuccess   = "kick.success"
+
+	MsgBanSelf          = "ban.self"
+	MsgBanBot           = "ban.bot"
+	MsgBanFailed        = "ban.failed"
+	MsgBanForbidden     = "ban.forbidden"
+	MsgBanSuccess       = "ban.success"
+	MsgBanDeleted       = "ban.deleted"
+	MsgBanPrompt        = "ban.prompt"
+	MsgBanPromptDeletes = "ban.prompt_deletes"
+
+	MsgMuteSelf            = "mute.self"
+	MsgMuteBot             = "mute.bot"
+	MsgMuteInvalidDuration = "mute.invalid_duration"
+	MsgMuteFailed          = "mute.failed"
+	MsgMuteForbidden       = "mute.forbidden"
+	MsgMuteSuccess         = "mute.success"
+
+	MsgSnipeNone = "snipe.none"
+	MsgSnipe     = "snipe.message"
+
+	MsgPermsHeader     = "perms.header"
+	MsgPermsWontWork   = "perms.wont_work"
+	MsgPermsAllGranted = "perms.all_granted"
+	MsgPermsMissing    = "perms.missing"
+	MsgPermsNone       = "perms.none"
+	MsgPermsUnknown    = "perms.unknown"
+)
+
+// builtin holds the built-in messages by locale. Every ID must have a
+// DefaultLocale message; other locales may be partial.
+var builtin = map[string]map[string]string{
+	DefaultLocale: {
+		MsgGuildOnly:     "This command can only be used in a server.",
+		MsgDMOnly:        "This command can only be used in direct messages.",
+		MsgRateLimited:   "Discord is rate limiting this action. Try again in %ds.",
+		MsgCooldown:      "You're using this command too quickly. Try again in %ds.",
+		MsgNoPermission:  "You do not have permission to use this command.",
+		MsgMissingAccess: "I can't access this server or channel. Check that my role can view it.",
+		MsgMaintenance:   "The bot is under maintenance. Try again in a few minutes.",
+
+		MsgGuildNotAllowed: "This bot is not available in this server.",
+
+		MsgTextUsage:       "Usage: %s",
+		MsgTextMissingArg:  "Missing %s.",
+		MsgTextInvalidArg:  "%q is not a valid %s.",
+		MsgTextTooManyArgs: "Too many arguments; put words with spaces in double quotes.",
+		MsgTextUnsupported: "The %s option is only available with the slash command.",
+
+		MsgNotified:    " They have been notified via DM.",
+		MsgNotNotified: " (Unable to send DM - user may have DMs disabled)",
+
+		MsgConfirmButton:     "Confirm",
+		MsgCancelButton:      "Cancel",
+		MsgConfirmCancelled:  "Cancelled; nothing was done.",
+		MsgConfirmTimeout:    "Timed out; nothing was done.",
+		MsgConfirmExpired:    "This confirmation has expired.",
+		MsgConfirmNotInvoker: "Only the person who ran this command can answer it.",
+
+		MsgKickSelf:      "You cannot kick yourself.",
+		MsgKickBot:       "You cannot kick bots.",
+		MsgKickFailed:    "Failed to kick %s. I may lack permissions or the user may have a higher role.",
+		MsgKickForbidden: "I don't have permission to kick %s. Check that my role has Kick Members and is above their highest role.",
+		MsgKickSuccess:   "Successfully kicked %s#%s. Reason: %s",
+
+		MsgBanSelf:          "You cannot ban yourself.",
+		MsgBanBot:           "You cannot ban bots.",
+		MsgBanFailed:        "Failed to ban %s. I may lack permissions or the user may have a higher role.",
+		MsgBanForbidden:     "I don't have permission to ban %s. Check that my role has Ban Members and is above their highest role.",
+		MsgBanSuccess:       "Successfully banned %s#%s. Reason: %s",
+		MsgBanDeleted:       " (Deleted %d days of messages)",
+		MsgBanPrompt:        "Ban %s? Reason: %s",
+		MsgBanPromptDeletes: " (Deletes %d days of messages)",
+
+		MsgMuteSelf:            "You cannot timeout yourself.",
+		MsgMuteBot:             "You cannot timeout bots.",
+		MsgMuteInvalidDuration: "Invalid duration format. Use formats like: 1h, 30m, 2d",
+		MsgMuteFailed:          "Failed to timeout %s. I may lack permissions or the user may have a higher role.",
+		MsgMuteForbidden:       "I don't have permission to timeout %s. Check that my role has Moderate Members and is above their highest role; administrators cannot be timed out.",
+		MsgMuteSuccess:         "Successfully timed out %s#%s for %s. Reason: %s",
+
+		MsgSnipeNone: "No message has been deleted here recently.",
+		MsgSnipe:     "Message from <@%s> deleted <t:%d:R>:\n%s",
+
+		MsgPermsHeader:     "My permissions in <#%s>:",
+		MsgPermsWontWork:   "%s won't work",
+		MsgPermsAllGranted: "I have every permission my commands need here.",
+		MsgPermsMissing:    "%d permission(s) missing. Grant them to my role, or check this channel's permission overrides.",
+		MsgPermsNone:       "None of my commands need extra permissions.",
+		MsgPermsUnknown:    "I couldn't work out my permissions in this channel. Try again in a moment.",
+	},
+	"es": {
+		MsgGuildOnly:     "Este comando solo se puede usar en un servidor.",
+		MsgDMOnly:        "Este comando solo se puede usar en mensajes directos.",
+		MsgRateLimited:   "Discord está limitando esta acción. Inténtalo de nuevo en %ds.",
+		MsgCooldown:      "Estás usando este comando demasiado rápido. Inténtalo de nuevo en %ds.",
+		MsgNoPermission:  "No tienes permiso para usar este comando.",
+		MsgMissingAccess: "No tengo acceso a este servidor o canal. Comprueba que mi rol puede verlo.",
+		MsgMaintenance:   "El bot está en mantenimiento. Inténtalo de nuevo en unos minutos.",
+
+		MsgGuildNotAllowed: "Este bot no está disponible en este servidor.",
+
+		MsgTextUsage:       "Uso: %s",
+		MsgTextMissingArg:  "Falta %s.",
+		MsgTextInvalidArg:  "%q no es un valor válido para %s.",
+		MsgTextTooManyArgs: "Demasiados argumentos; pon las frases con espacios entre comillas dobles.",
+		MsgTextUnsupported: "La opción %s solo está disponible con el comando de barra.",
+
+		MsgNotified:    " Se le ha notificado por mensaje directo.",
+		MsgNotNotified: " (No se pudo enviar el mensaje directo; puede que tenga los MD desactivados)",
+
+		MsgConfirmButton:     "Confirmar",
+		MsgCancelButton:      "Cancelar",
+		MsgConfirmCancelled:  "Cancelado; no se hizo nada.",
+		MsgConfirmTimeout:    "Tiempo agotado; no se hizo nada.",
+		MsgConfirmExpired:    "Esta confirmación ha caducado.",
+		MsgConfirmNotInvoker: "Solo quien ejecutó este comando puede responder.",
+
+		MsgKickSelf:      "No puedes expulsarte a ti mismo.",
+		MsgKickBot:       "No puedes expulsar bots.",
+		MsgKickFailed:    "No se pudo expulsar a %s. Puede que me falten permisos o que el usuario tenga un rol superior.",
+		MsgKickForbidden: "No tengo permiso para expulsar a %s. Comprueba que mi rol tiene Expulsar miembros y está por encima de su rol más alto.",
+		MsgKickSuccess:   "%s#%s ha sido expulsado. Motivo: %s",
+
+		MsgBanSelf:          "No puedes banearte a ti mismo.",
+		MsgBanBot:           "No puedes banear bots.",
+		MsgBanFailed:        "No se pudo banear a %s. Puede que me falten permisos o que el usuario tenga un rol superior.",
+		MsgBanForbidden:     "No tengo permiso para banear a %s. Comprueba que mi rol tiene Banear miembros y está por encima de su rol más alto.",
+		MsgBanSuccess:       "%s#%s ha sido baneado. Motivo: %s",
+		MsgBanDeleted:       " (Se eliminaron %d días de mensajes)",
+		MsgBanPrompt:        "¿Banear a %s? Motivo: %s",
+		MsgBanPromptDeletes: " (Elimina %d días de mensajes)",
+
+		MsgMuteSelf:            "No puedes aislarte a ti mismo.",
+		MsgMuteBot:             "No puedes aislar bots.",
+		MsgMuteInvalidDuration: "Formato de duración no válido. Usa formatos como: 1h, 30m, 2d",
+		MsgMuteFailed:          "No se pudo aislar a %s. Puede que me falten permisos o que el usuario tenga un rol superior.",
+		MsgMuteForbidden:       "No tengo permiso para aislar a %s. Comprueba que mi rol tiene Moderar miembros y está por encima de su rol más alto; los administradores no se pueden aislar.",
+		MsgMuteSuccess:         "%s#%s ha sido aislado durante %s. Motivo: %s",
+
+		MsgSnipeNone: "No se ha eliminado ningún mensaje aquí recientemente.",
+		MsgSnipe:     "Mensaje de <@%s> eliminado <t:%d:R>:\n%s",
+
+		MsgPermsHeader:     "Mis permisos en <#%s>:",
+		MsgPermsWontWork:   "%s no funcionará",
+		MsgPermsAllGranted: "Tengo todos los permisos que mis comandos necesitan aquí.",
+		MsgPermsMissing:    "Faltan %d permiso(s). Concédelos a mi rol o revisa los permisos de este canal.",
+		MsgPermsNone:       "Ninguno de mis comandos necesita permisos adicionales.",
+		MsgPermsUnknown:    "No pude determinar mis permisos en este canal. Inténtalo de nuevo en un momento.",
+	},
+	"de": {
+		MsgGuildOnly:     "Dieser Befehl kann nur auf einem Server verwendet werden.",
+		MsgDMOnly:        "Dieser Befehl kann nur in Direktnachrichten verwendet werden.",
+		MsgRateLimited:   "Discord begrenzt diese Aktion. Versuche es in %ds erneut.",
+		MsgCooldown:      "Du benutzt diesen Befehl zu schnell. Versuche es in %ds erneut.",
+		MsgNoPermission:  "Du hast keine Berechtigung, diesen Befehl zu verwenden.",
+		MsgMissingAccess: "Ich habe keinen Zugriff auf diesen Server oder Kanal. Prüfe, ob meine Rolle ihn sehen kann.",
+		MsgMaintenance:   "Der Bot wird gerade gewartet. Versuche es in ein paar Minuten erneut.",
+
+		MsgGuildNotAllowed: "Dieser Bot ist auf diesem Server nicht verfügbar.",
+
+		MsgTextUsage:       "Verwendung: %s",
+		MsgTextMissingArg:  "%s fehlt.",
+		MsgTextInvalidArg:  "%q ist kein gültiger Wert für %s.",
+		MsgTextTooManyArgs: "Zu viele Argumente; setze Wörter mit Leerzeichen in doppelte Anführungszeichen.",
+		MsgTextUnsupported: "Die Option %s ist nur mit dem Slash-Befehl verfügbar.",
+
+		MsgNotified:    " Die Person wurde per Direktnachricht benachrichtigt.",
+		MsgNotNotified: " (Direktnachricht konnte nicht gesendet werden - Direktnachrichten sind eventuell deaktiviert)",
+
+		MsgConfirmButton:     "Bestätigen",
+		MsgCancelButton:      "Abbrechen",
+		MsgConfirmCancelled:  "Abgebrochen; es wurde nichts getan.",
+		MsgConfirmTimeout:    "Zeit abgelaufen; es wurde nichts getan.",
+		MsgConfirmExpired:    "Diese Bestätigung ist abgelaufen.",
+		MsgConfirmNotInvoker: "Nur die Person, die diesen Befehl ausgeführt hat, kann antworten.",
+
+		MsgKickSelf:      "Du kannst dich nicht selbst kicken.",
+		MsgKickBot:       "Du kannst keine Bots kicken.",
+		MsgKickFailed:    "%s konnte nicht gekickt werden. Mir fehlen eventuell Berechtigungen oder die Person hat eine höhere Rolle.",
+		MsgKickForbidden: "Ich habe keine Berechtigung, %s zu kicken. Prüfe, ob meine Rolle „Mitglieder kicken“ hat und über ihrer höchsten Rolle steht.",
+		MsgKickSuccess:   "%s#%s wurde gekickt. Grund: %s",
+
+		MsgBanSelf:          "Du kannst dich nicht selbst bannen.",
+		MsgBanBot:           "Du kannst keine Bots bannen.",
+		MsgBanFailed:        "%s konnte nicht gebannt werden. Mir fehlen eventuell Berechtigungen oder die Person hat eine höhere Rolle.",
+		MsgBanForbidden:     "Ich habe keine Berechtigung, %s zu bannen. Prüfe, ob meine Rolle „Mitglieder bannen“ hat und über ihrer höchsten Rolle steht.",
+		MsgBanSuccess:       "%s#%s wurde gebannt. Grund: %s",
+		MsgBanDeleted:       " (Nachrichten der letzten %d Tage gelöscht)",
+		MsgBanPrompt:        "%s bannen? Grund: %s",
+		MsgBanPromptDeletes: " (Löscht Nachrichten der letzten %d Tage)",
+
+		MsgMuteSelf:            "Du kannst dir selbst kein Timeout geben.",
+		MsgMuteBot:             "Du kannst Bots kein Timeout geben.",
+		MsgMuteInvalidDuration: "Ungültiges Dauerformat. Verwende Formate wie: 1h, 30m, 2d",
+		MsgMuteFailed:          "%s konnte kein Timeout gegeben werden. Mir fehlen eventuell Berechtigungen oder die Person hat eine höhere Rolle.",
+		MsgMuteForbidden:       "Ich habe keine Berechtigung, %s ein Timeout zu geben. Prüfe, ob meine Rolle „Mitglieder moderieren“ hat und über ihrer höchsten Rolle steht; Administratoren können kein Timeout erhalten.",
+		MsgMuteSuccess:         "%s#%s hat ein Timeout für %s erhalten. Grund: %s",
+
+		MsgSnipeNone: "Hier wurde in letzter Zeit keine Nachricht gelöscht.",
+		MsgSnipe:     "Nachricht von <@%s> <t:%d:R> gelöscht:\n%s",
+
+		MsgPermsHeader:     "Meine Berechtigungen in <#%s>:",
+		MsgPermsWontWork:   "%s wird nicht funktionieren",
+		MsgPermsAllGranted: "Ich habe hier alle Berechtigungen, die meine Befehle brauchen.",
+		MsgPermsMissing:    "%d Berechtigung(en) fehlen. Gib sie meiner Rolle oder prüfe die Berechtigungen dieses Kanals.",
+		MsgPermsNone:       "Keiner meiner Befehle braucht zusätzliche Berechtigungen.",
+		MsgPermsUnknown:    "Ich konnte meine Berechtigungen in diesem Kanal nicht ermitteln. Versuche es gleich noch einmal.",
+	},
+}
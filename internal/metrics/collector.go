@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Collector keeps a latency histogram per command. Memory grows only with the
+// number of distinct command names, which the command registry bounds.
+// It is safe for concurrent use.
+type Collector struct {
+	mu         sync.RWMutex
+	bounds     []time.Duration
+	histograms map[string]*Histogram
+
+	// last is when the most recent observation was recorded, in Unix seconds.
+	last atomic.Int64
+}
+
+// NewCollector creates a collector whose histograms use DefaultBuckets.
+func NewCollector() *Collector {
+	return NewCollectorWithBuckets(DefaultBuckets...)
+}
+
+// NewCollectorWithBuckets creates a collector whose histograms use the given
+// bucket upper bounds.
+func NewCollectorWithBuckets(bounds ...time.Duration) *Collector {
+	return &Collector{
+		bounds:     append([]time.Duration(nil), bounds...),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// Observe records how long one execution of the named command took.
+// A nil Collector discards observations.
+func (c *Collector) Observe(command string, d time.Duration) {
+	if c == nil {
+		return
+	}
+
+	c.mu.RLock()
+	h, ok := c.histograms[command]
+	c.mu.RUnlock()
+
+	if !ok {
+		c.mu.Lock()
+		if h, ok = c.histograms[command]; !ok {
+			h = NewHistogram(c.bounds...)
+			c.histograms[command] = h
+		}
+		c.mu.Unlock()
+	}
+
+	h.Observe(d)
+	c.last.Store(time.Now().Unix())
+}
+
+// LastObserved returns when the most recent observation was recorded, or the
+// zero time if there has been none.
+func (c *Collector) LastObserved() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	last := c.last.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(last, 0)
+}
+
+// Percentiles returns the latency percentiles of every command observed so
+// far, keyed by command name. The map is a copy owned by the caller.
+func (c *Collector) Percentiles() map[string]Percentiles {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make(map[string]Percentiles, len(c.histograms))
+	for name, h := range c.histograms {
+		result[name] = h.Percentiles()
+	}
+	return result
+}
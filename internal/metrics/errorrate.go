@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// errorRateBuckets is how many slices an ErrorRate window is divided into.
+// Counts age out one slice at a time, so the window is accurate to within
+// one slice.
+const errorRateBuckets = 10
+
+// ErrorRate counts executions and failures over a sliding window. Counts are
+// kept in a fixed number of time slices, so its memory use does not grow with
+// traffic. It is safe for concurrent use.
+type ErrorRate struct {
+	mu      sync.Mutex
+	width   time.Duration
+	buckets [errorRateBuckets]rateBucket
+}
+
+// rateBucket counts the executions in one time slice. slice identifies the
+// slice, so a bucket left over from an earlier pass around the ring is
+// recognized as stale.
+type rateBucket struct {
+	slice    int64
+	total    int
+	failures int
+}
+
+// NewErrorRate creates an ErrorRate that counts over window, which must be
+// positive.
+func NewErrorRate(window time.Duration) *ErrorRate {
+	width := window / errorRateBuckets
+	if width <= 0 {
+		width = 1
+	}
+	return &ErrorRate{width: width}
+}
+
+// Window returns the span of time the counts cover.
+func (r *ErrorRate) Window() time.Duration {
+	return r.width * errorRateBuckets
+}
+
+// Record counts one execution at now, as a failure if failed is true.
+func (r *ErrorRate) Record(now time.Time, failed bool) {
+	slice := now.UnixNano() / int64(r.width)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := &r.buckets[slice%errorRateBuckets]
+	if b.slice != slice {
+		*b = rateBucket{slice: slice}
+	}
+	b.total++
+	if failed {
+		b.failures++
+	}
+}
+
+// Counts returns the failures and total executions recorded within the
+// window ending at now.
+func (r *ErrorRate) Counts(now time.Time) (failures, total int) {
+	current := now.UnixNano() / int64(r.width)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.buckets {
+		if b.slice > current-errorRateBuckets && b.slice <= current {
+			failures += b.failures
+			total += b.total
+		}
+	}
+	return failures, total
+}
@@ -0,0 +1,97 @@
+package metrics_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"jamesbot/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ============================================================================
+// ErrorRate Tests
+// ============================================================================
+
+func Test_ErrorRate_Counts(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name         string
+		record       []time.Duration // offsets from start; odd indexes fail
+		at           time.Duration
+		wantFailures int
+		wantTotal    int
+	}{
+		{name: "empty", at: 0},
+		{
+			name:         "within window",
+			record:       []time.Duration{0, time.Second, 30 * time.Second, 50 * time.Second},
+			at:           55 * time.Second,
+			wantFailures: 2,
+			wantTotal:    4,
+		},
+		{
+			name:         "old executions age out",
+			record:       []time.Duration{0, time.Second, 70 * time.Second, 75 * time.Second},
+			at:           80 * time.Second,
+			wantFailures: 1,
+			wantTotal:    2,
+		},
+		{
+			name:         "a slot reused after a full pass is reset",
+			record:       []time.Duration{0, time.Second, 120 * time.Second},
+			at:           120 * time.Second,
+			wantFailures: 0,
+			wantTotal:    1,
+		},
+		{
+			name:         "future executions are not counted",
+			record:       []time.Duration{0, 10 * time.Second},
+			at:           5 * time.Second,
+			wantFailures: 0,
+			wantTotal:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := metrics.NewErrorRate(time.Minute)
+			for i, offset := range tt.record {
+				r.Record(start.Add(offset), i%2 == 1)
+			}
+
+			failures, total := r.Counts(start.Add(tt.at))
+
+			assert.Equal(t, tt.wantFailures, failures)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func Test_ErrorRate_Window(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, metrics.NewErrorRate(5*time.Minute).Window())
+}
+
+func Test_ErrorRate_Concurrent(t *testing.T) {
+	r := metrics.NewErrorRate(time.Minute)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Record(now, j%2 == 0)
+				_, _ = r.Counts(now)
+			}
+		}()
+	}
+	wg.Wait()
+
+	failures, total := r.Counts(now)
+	assert.Equal(t, 500, failures)
+	assert.Equal(t, 1000, total)
+}
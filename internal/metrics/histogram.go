@@ -0,0 +1,133 @@
+// Package metrics collects command execution latencies in bounded,
+// fixed-bucket histograms.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds of the latency buckets used by
+// NewCollector. They are concentrated below Discord's three-second deadline
+// for answering an interaction.
+var DefaultBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Histogram counts observed durations in fixed buckets, so its memory use does
+// not grow with the number of observations. Quantiles are estimates accurate
+// to within a bucket. It is safe for concurrent use.
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []time.Duration
+	counts []uint64 // counts[i] is observations <= bounds[i]; the last is overflow
+	total  uint64
+	max    time.Duration
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds, which
+// must be positive. Bounds are sorted; observations above the largest fall
+// into an overflow bucket.
+func NewHistogram(bounds ...time.Duration) *Histogram {
+	sorted := append([]time.Duration(nil), bounds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &Histogram{
+		bounds: sorted,
+		counts: make([]uint64, len(sorted)+1),
+	}
+}
+
+// Observe records one duration.
+func (h *Histogram) Observe(d time.Duration) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return d <= h.bounds[i] })
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[i]++
+	h.total++
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.total
+}
+
+// Quantile estimates the q-quantile (0 < q <= 1) of the observations by
+// interpolating linearly within the bucket that holds it. Quantiles in the
+// overflow bucket are estimated up to the largest observation. It returns 0
+// when nothing has been observed.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.quantile(q)
+}
+
+// quantile is Quantile without locking; callers must hold h.mu.
+func (h *Histogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 1)
+
+	rank := q * float64(h.total)
+	var seen uint64
+	for i, n := range h.counts {
+		if n == 0 || float64(seen+n) < rank {
+			seen += n
+			continue
+		}
+
+		var lower, upper time.Duration
+		if i > 0 {
+			lower = h.bounds[i-1]
+		}
+		if i < len(h.bounds) {
+			upper = h.bounds[i]
+		} else {
+			upper = h.max
+		}
+		// Never report more than was actually observed
+		upper = min(upper, h.max)
+		lower = min(lower, upper)
+
+		fraction := (rank - float64(seen)) / float64(n)
+		return lower + time.Duration(fraction*float64(upper-lower))
+	}
+	return h.max
+}
+
+// Percentiles summarizes a histogram's median and tail latencies.
+type Percentiles struct {
+	Count uint64
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// Percentiles returns the histogram's p50, p95, and p99 from one consistent view.
+func (h *Histogram) Percentiles() Percentiles {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return Percentiles{
+		Count: h.total,
+		P50:   h.quantile(0.50),
+		P95:   h.quantile(0.95),
+		P99:   h.quantile(0.99),
+	}
+}
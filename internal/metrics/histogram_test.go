@@ -0,0 +1,158 @@
+package metrics_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"jamesbot/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ============================================================================
+// Histogram Tests
+// ============================================================================
+
+func Test_Histogram_Quantile(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name    string
+		bounds  []time.Duration
+		observe []time.Duration
+		q       float64
+		want    time.Duration
+	}{
+		{
+			name:   "empty histogram",
+			bounds: []time.Duration{10 * ms},
+			q:      0.5,
+			want:   0,
+		},
+		{
+			name:    "interpolates within the first bucket",
+			bounds:  []time.Duration{10 * ms, 100 * ms},
+			observe: []time.Duration{2 * ms, 4 * ms, 6 * ms, 10 * ms},
+			q:       0.5,
+			want:    5 * ms,
+		},
+		{
+			name:    "never exceeds the largest observation",
+			bounds:  []time.Duration{10 * ms, 100 * ms},
+			observe: []time.Duration{2 * ms, 4 * ms},
+			q:       1,
+			want:    4 * ms,
+		},
+		{
+			name:    "interpolates between bucket bounds",
+			bounds:  []time.Duration{10 * ms, 100 * ms},
+			observe: []time.Duration{5 * ms, 20 * ms, 40 * ms, 100 * ms},
+			q:       0.75,
+			want:    70 * ms,
+		},
+		{
+			name:    "overflow bucket reaches the largest observation",
+			bounds:  []time.Duration{10 * ms},
+			observe: []time.Duration{5 * ms, 30 * ms},
+			q:       1,
+			want:    30 * ms,
+		},
+		{
+			name:    "bounds are sorted",
+			bounds:  []time.Duration{100 * ms, 10 * ms},
+			observe: []time.Duration{5 * ms, 5 * ms, 50 * ms, 100 * ms},
+			q:       0.5,
+			want:    10 * ms,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := metrics.NewHistogram(tt.bounds...)
+			for _, d := range tt.observe {
+				h.Observe(d)
+			}
+
+			assert.Equal(t, uint64(len(tt.observe)), h.Count())
+			assert.Equal(t, tt.want, h.Quantile(tt.q))
+		})
+	}
+}
+
+func Test_Histogram_Percentiles(t *testing.T) {
+	h := metrics.NewHistogram(metrics.DefaultBuckets...)
+	for i := 0; i < 95; i++ {
+		h.Observe(3 * time.Millisecond)
+	}
+	for i := 0; i < 5; i++ {
+		h.Observe(2 * time.Second)
+	}
+
+	p := h.Percentiles()
+
+	assert.Equal(t, uint64(100), p.Count)
+	assert.LessOrEqual(t, p.P50, 5*time.Millisecond)
+	assert.LessOrEqual(t, p.P95, 5*time.Millisecond)
+	assert.Greater(t, p.P99, time.Second)
+	assert.LessOrEqual(t, p.P99, 2*time.Second)
+}
+
+// ============================================================================
+// Collector Tests
+// ============================================================================
+
+func Test_Collector_Percentiles(t *testing.T) {
+	c := metrics.NewCollector()
+	c.Observe("ping", 2*time.Millisecond)
+	c.Observe("ping", 4*time.Millisecond)
+	c.Observe("ban", time.Second)
+
+	got := c.Percentiles()
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, uint64(2), got["ping"].Count)
+	assert.Equal(t, uint64(1), got["ban"].Count)
+	assert.Greater(t, got["ban"].P99, 500*time.Millisecond)
+	assert.LessOrEqual(t, got["ban"].P99, time.Second)
+}
+
+func Test_Collector_LastObserved(t *testing.T) {
+	c := metrics.NewCollector()
+	assert.True(t, c.LastObserved().IsZero(), "nothing observed yet")
+
+	before := time.Now().Truncate(time.Second)
+	c.Observe("ping", time.Millisecond)
+
+	last := c.LastObserved()
+	assert.False(t, last.Before(before))
+	assert.False(t, last.After(time.Now()))
+}
+
+func Test_Collector_Nil(t *testing.T) {
+	var c *metrics.Collector
+
+	c.Observe("ping", time.Millisecond)
+
+	assert.Nil(t, c.Percentiles())
+	assert.True(t, c.LastObserved().IsZero())
+}
+
+func Test_Collector_Concurrent(t *testing.T) {
+	c := metrics.NewCollector()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Observe("ping", time.Millisecond)
+				_ = c.Percentiles()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(1000), c.Percentiles()["ping"].Count)
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"jamesbot/pkg/fileutil"
+)
+
+// Lifetime holds command execution counts accumulated over every run of the
+// bot, as saved to a stats file between restarts.
+type Lifetime struct {
+	// CommandsExecuted counts successful executions of every command.
+	CommandsExecuted int64 `json:"commands_executed"`
+
+	// Commands counts successful executions by command name.
+	Commands map[string]int64 `json:"commands"`
+}
+
+// Add returns the counts in l plus executed and commands, such as those
+// counted since the bot started. l is not modified.
+func (l Lifetime) Add(executed int64, commands map[string]int64) Lifetime {
+	sum := Lifetime{
+		CommandsExecuted: l.CommandsExecuted + executed,
+		Commands:         make(map[string]int64, len(l.Commands)+len(commands)),
+	}
+	for name, n := range l.Commands {
+		sum.Commands[name] = n
+	}
+	for name, n := range commands {
+		sum.Commands[name] += n
+	}
+	return sum
+}
+
+// LoadLifetime reads the counts saved at path by SaveLifetime. A file that
+// does not exist yet holds no counts.
+func LoadLifetime(path string) (Lifetime, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Lifetime{}, nil
+	}
+	if err != nil {
+		return Lifetime{}, fmt.Errorf("failed to read stats file: %w", err)
+	}
+
+	var l Lifetime
+	if err := json.Unmarshal(data, &l); err != nil {
+		return Lifetime{}, fmt.Errorf("failed to parse stats file %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// SaveLifetime writes l to path. The file is replaced atomically, so a crash
+// while saving leaves the previous counts intact.
+func SaveLifetime(path string, l Lifetime) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode stats: %w", err)
+	}
+
+	if err := fileutil.WriteAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to save stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jamesbot/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Lifetime Tests
+// ============================================================================
+
+func Test_Lifetime_Add(t *testing.T) {
+	saved := metrics.Lifetime{CommandsExecuted: 10, Commands: map[string]int64{"ban": 4, "ping": 6}}
+
+	sum := saved.Add(3, map[string]int64{"ping": 2, "kick": 1})
+
+	assert.Equal(t, metrics.Lifetime{
+		CommandsExecuted: 13,
+		Commands:         map[string]int64{"ban": 4, "ping": 8, "kick": 1},
+	}, sum)
+	assert.Equal(t, int64(6), saved.Commands["ping"], "Add should not modify the saved counts")
+}
+
+func Test_SaveLifetime_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	want := metrics.Lifetime{CommandsExecuted: 42, Commands: map[string]int64{"ban": 2, "ping": 40}}
+
+	require.NoError(t, metrics.SaveLifetime(path, want))
+	got, err := metrics.LoadLifetime(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files should be left behind")
+}
+
+func Test_LoadLifetime(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *string
+		want    metrics.Lifetime
+		wantErr bool
+	}{
+		{
+			name: "missing file holds no counts",
+		},
+		{
+			name:    "saved counts",
+			content: ptr(`{"commands_executed": 5, "commands": {"ping": 5}}`),
+			want:    metrics.Lifetime{CommandsExecuted: 5, Commands: map[string]int64{"ping": 5}},
+		},
+		{
+			name:    "corrupt file",
+			content: ptr(`{"commands_executed": `),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "stats.json")
+			if tt.content != nil {
+				require.NoError(t, os.WriteFile(path, []byte(*tt.content), 0o600))
+			}
+
+			got, err := metrics.LoadLifetime(path)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_SaveLifetime_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "stats.json")
+	assert.Error(t, metrics.SaveLifetime(path, metrics.Lifetime{}))
+}
+
+// ptr returns a pointer to s.
+func ptr(s string) *string {
+	return &s
+}
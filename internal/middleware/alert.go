@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/metrics"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Defaults for an ErrorAlerter created without options.
+const (
+	DefaultAlertThreshold   = 0.5
+	DefaultAlertWindow      = 5 * time.Minute
+	DefaultAlertMinCommands = 10
+	DefaultAlertCooldown    = 10 * time.Minute
+	DefaultAlertMaxCooldown = 6 * time.Hour
+)
+
+// maxAlertErrorLen bounds how much of the latest error an alert quotes.
+const maxAlertErrorLen = 300
+
+// AlertOption configures an ErrorAlerter.
+type AlertOption func(*ErrorAlerter)
+
+// WithAlertThreshold sets the fraction of commands, between 0 and 1, that
+// must fail within the window to raise an alert.
+func WithAlertThreshold(threshold float64) AlertOption {
+	return func(a *ErrorAlerter) {
+		a.threshold = threshold
+	}
+}
+
+// WithAlertWindow sets how far back failures are counted.
+func WithAlertWindow(window time.Duration) AlertOption {
+	return func(a *ErrorAlerter) {
+		a.rate = metrics.NewErrorRate(window)
+	}
+}
+
+// WithAlertMinCommands sets how many commands must run within the window
+// before the failure rate is trusted, so one failure on a quiet bot does not
+// raise an alert.
+func WithAlertMinCommands(n int) AlertOption {
+	return func(a *ErrorAlerter) {
+		a.minCommands = n
+	}
+}
+
+// WithAlertCooldown sets how long alerts are held back after the first one.
+// Each further alert while failures continue doubles the wait, up to
+// maxCooldown.
+func WithAlertCooldown(cooldown, maxCooldown time.Duration) AlertOption {
+	return func(a *ErrorAlerter) {
+		a.cooldown = cooldown
+		a.maxCooldown = maxCooldown
+	}
+}
+
+// ErrorAlerter tracks the rate of failing commands and decides when it is high
+// enough to alert operators in a channel. Alerts are throttled with an
+// exponential backoff that resets once the failure rate drops, so a lasting
+// outage produces a handful of alerts rather than one per failure.
+// It is safe for concurrent use.
+type ErrorAlerter struct {
+	channelID   string
+	threshold   float64
+	minCommands int
+	cooldown    time.Duration
+	maxCooldown time.Duration
+	rate        *metrics.ErrorRate
+
+	mu sync.Mutex
+	// next is the earliest time another alert may be raised.
+	next time.Time
+	// backoff is the wait imposed after the last alert, or zero if the
+	// failure rate has recovered since.
+	backoff time.Duration
+}
+
+// NewErrorAlerter creates an alerter that posts to channelID, using the
+// Default* settings unless overridden by opts.
+func NewErrorAlerter(channelID string, opts ...AlertOption) *ErrorAlerter {
+	a := &ErrorAlerter{
+		channelID:   channelID,
+		threshold:   DefaultAlertThreshold,
+		minCommands: DefaultAlertMinCommands,
+		cooldown:    DefaultAlertCooldown,
+		maxCooldown: DefaultAlertMaxCooldown,
+		rate:        metrics.NewErrorRate(DefaultAlertWindow),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Record counts one execution of the named command at now, failed if err is
+// non-nil. It returns the alert to post if this failure pushes the failure
+// rate over the threshold and no alert is being held back.
+func (a *ErrorAlerter) Record(now time.Time, name string, err error) (string, bool) {
+	a.rate.Record(now, err != nil)
+	failures, total := a.rate.Counts(now)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if total < a.minCommands || float64(failures) < a.threshold*float64(total) {
+		// Recovered: the next outage alerts as soon as it is noticed
+		if !now.Before(a.next) {
+			a.backoff = 0
+		}
+		return "", false
+	}
+	if err == nil || now.Before(a.next) {
+		return "", false
+	}
+
+	if a.backoff == 0 {
+		a.backoff = a.cooldown
+	} else {
+		a.backoff = min(2*a.backoff, a.maxCooldown)
+	}
+	a.next = now.Add(a.backoff)
+
+	msg := err.Error()
+	if len(msg) > maxAlertErrorLen {
+		msg = msg[:maxAlertErrorLen] + "..."
+	}
+	return fmt.Sprintf("**Command errors:** %d of the last %d commands failed within %s.\n"+
+		"Latest: `/%s`: %s\nFurther alerts are held for %s.",
+		failures, total, a.rate.Window(), name, msg, a.backoff), true
+}
+
+// ErrorAlerts creates a middleware that records whether each command fails
+// in alerter and posts an alert to its channel when the failure rate crosses
+// the threshold. Failing to post is logged and does not affect the command.
+func ErrorAlerts(alerter *ErrorAlerter) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if alerter == nil {
+			return next
+		}
+		return func(ctx *command.Context) error {
+			err := next(ctx)
+
+			alert, ok := alerter.Record(time.Now(), getCommandName(ctx), err)
+			if !ok {
+				return err
+			}
+			if ctx.Session == nil {
+				ctx.Logger.Warn().Str("alert", alert).Msg("cannot post command error alert without a session")
+				return err
+			}
+			if _, sendErr := ctx.Session.ChannelMessageSendComplex(alerter.channelID, &discordgo.MessageSend{
+				Content:         alert,
+				AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+			}); sendErr != nil {
+				ctx.Logger.Error().Err(sendErr).Str("channel_id", alerter.channelID).Msg("failed to post command error alert")
+			}
+			return err
+		}
+	}
+}
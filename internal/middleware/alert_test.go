@@ -0,0 +1,171 @@
+package middleware_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// ErrorAlerter Tests
+// ============================================================================
+
+func Test_ErrorAlerter_Record(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	boom := errors.New("HTTP 403 Forbidden, Missing Permissions")
+
+	tests := []struct {
+		name       string
+		successes  int
+		failures   int
+		wantAlerts int
+	}{
+		{name: "too few commands", failures: 3},
+		{name: "below threshold", successes: 8, failures: 2},
+		{name: "at threshold", successes: 2, failures: 2, wantAlerts: 1},
+		{name: "sustained failures alert once per cooldown", failures: 20, wantAlerts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := middleware.NewErrorAlerter("ops",
+				middleware.WithAlertThreshold(0.5),
+				middleware.WithAlertMinCommands(4),
+			)
+
+			var alerts []string
+			for i := 0; i < tt.successes; i++ {
+				a.Record(start, "ping", nil)
+			}
+			for i := 0; i < tt.failures; i++ {
+				if alert, ok := a.Record(start, "ban", boom); ok {
+					alerts = append(alerts, alert)
+				}
+			}
+
+			require.Len(t, alerts, tt.wantAlerts)
+			for _, alert := range alerts {
+				assert.Contains(t, alert, "/ban")
+				assert.Contains(t, alert, "Missing Permissions")
+			}
+		})
+	}
+}
+
+func Test_ErrorAlerter_ExponentialBackoff(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	boom := errors.New("boom")
+	a := middleware.NewErrorAlerter("ops",
+		middleware.WithAlertMinCommands(1),
+		middleware.WithAlertWindow(time.Hour),
+		middleware.WithAlertCooldown(time.Minute, 4*time.Minute),
+	)
+
+	// fail records a failure at offset and reports whether it alerted.
+	fail := func(offset time.Duration) bool {
+		_, ok := a.Record(start.Add(offset), "ban", boom)
+		return ok
+	}
+
+	assert.True(t, fail(0), "the first failure alerts")
+	assert.False(t, fail(59*time.Second), "held for the 1m cooldown")
+	assert.True(t, fail(time.Minute))
+	assert.False(t, fail(2*time.Minute+59*time.Second), "held for 2m after the second alert")
+	assert.True(t, fail(3*time.Minute))
+	assert.False(t, fail(6*time.Minute+59*time.Second), "held for 4m after the third alert")
+	assert.True(t, fail(7*time.Minute))
+	assert.False(t, fail(10*time.Minute+59*time.Second), "the wait is capped at 4m")
+	assert.True(t, fail(11*time.Minute))
+}
+
+func Test_ErrorAlerter_BackoffResetsAfterRecovery(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	boom := errors.New("boom")
+	a := middleware.NewErrorAlerter("ops",
+		middleware.WithAlertMinCommands(1),
+		middleware.WithAlertWindow(time.Minute),
+		middleware.WithAlertCooldown(time.Minute, time.Hour),
+	)
+
+	_, ok := a.Record(start, "ban", boom)
+	require.True(t, ok)
+	_, ok = a.Record(start.Add(time.Minute), "ban", boom)
+	require.True(t, ok)
+
+	// Once the old failures leave the window, a success finds the bot healthy
+	a.Record(start.Add(10*time.Minute), "ping", nil)
+
+	alert, ok := a.Record(start.Add(10*time.Minute+time.Second), "ban", boom)
+	require.True(t, ok)
+	assert.Contains(t, alert, "held for 1m0s", "the cooldown starts over after recovery")
+}
+
+func Test_ErrorAlerter_TruncatesLongErrors(t *testing.T) {
+	a := middleware.NewErrorAlerter("ops", middleware.WithAlertMinCommands(1))
+
+	alert, ok := a.Record(time.Now(), "ban", errors.New(strings.Repeat("x", 1000)))
+
+	require.True(t, ok)
+	assert.Less(t, len(alert), 600)
+	assert.Contains(t, alert, "...")
+}
+
+// ============================================================================
+// ErrorAlerts Middleware Tests
+// ============================================================================
+
+func Test_ErrorAlerts(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantAlert bool
+	}{
+		{name: "success posts nothing", err: nil},
+		{name: "failure over threshold posts an alert", err: errors.New("boom"), wantAlert: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, rc := createGuardTestContext(t)
+			alerter := middleware.NewErrorAlerter("ops-channel", middleware.WithAlertMinCommands(1))
+			handler := middleware.ErrorAlerts(alerter)(func(ctx *command.Context) error {
+				return tt.err
+			})
+
+			err := handler(ctx)
+
+			assert.Equal(t, tt.err, err, "the command's error is passed through")
+			if !tt.wantAlert {
+				assert.Empty(t, rc.bodies)
+				return
+			}
+			require.Len(t, rc.bodies, 1)
+			assert.Contains(t, rc.bodies[0], "/testcmd")
+			assert.Contains(t, rc.bodies[0], `"parse":[]`, "alerts must not ping anyone")
+		})
+	}
+}
+
+func Test_ErrorAlerts_NilAlerter(t *testing.T) {
+	boom := errors.New("boom")
+	handler := middleware.ErrorAlerts(nil)(func(ctx *command.Context) error { return boom })
+
+	assert.Equal(t, boom, handler(createTestContext()))
+}
+
+func Test_ErrorAlerts_NilSession(t *testing.T) {
+	boom := errors.New("boom")
+	alerter := middleware.NewErrorAlerter("ops-channel", middleware.WithAlertMinCommands(1))
+	handler := middleware.ErrorAlerts(alerter)(func(ctx *command.Context) error { return boom })
+
+	assert.NotPanics(t, func() {
+		assert.Equal(t, boom, handler(createTestContext()))
+	})
+}
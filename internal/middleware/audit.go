@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"jamesbot/internal/command"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// RedactedValue replaces the value of options redacted from audit records.
+const RedactedValue = "[REDACTED]"
+
+// AuditOption configures the Audit middleware.
+type AuditOption func(*auditOptions)
+
+type auditOptions struct {
+	redacted map[string]bool
+}
+
+// WithRedactedOptions names options whose values are replaced with
+// RedactedValue in audit records, such as free-text reasons that may hold
+// personal information. The option itself is still recorded.
+func WithRedactedOptions(names ...string) AuditOption {
+	return func(o *auditOptions) {
+		for _, name := range names {
+			o.redacted[name] = true
+		}
+	}
+}
+
+// Audit creates a middleware that writes one structured record per command
+// execution to logger, so moderation activity can be queried or replayed. A
+// record holds the interaction and command names, who ran it where, the
+// options given, whether it was a text command, the outcome, and its
+// duration. Records are written without a level so they are never filtered
+// or sampled.
+//
+// A command that only prompted for confirmation is recorded with the outcome
+// "awaiting_confirmation"; AuditConfirmations records what came of it.
+//
+// Audit records carry everything the Logging middleware logs, so when both
+// write to the same output only one of them should be used.
+func Audit(logger zerolog.Logger, opts ...AuditOption) Middleware {
+	options := newAuditOptions(opts)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *command.Context) error {
+			start := time.Now()
+			err := next(ctx)
+			duration := time.Since(start)
+
+			record := auditRecord(logger, ctx, options)
+			switch {
+			case errors.Is(err, ErrCommandBlocked):
+				record = record.Str("outcome", "blocked")
+			case err != nil:
+				record = record.Str("outcome", "error").Err(err)
+			case ctx.AwaitingConfirmation():
+				record = record.Str("outcome", "awaiting_confirmation")
+			default:
+				record = record.Str("outcome", "success")
+			}
+			record.Dur("duration", duration).Msg("command audit")
+
+			return err
+		}
+	}
+}
+
+// AuditConfirmations returns a command.ConfirmationObserver that writes an
+// audit record, like Audit's, for the answer to each confirmation prompt, so
+// an action that runs once confirmed is recorded with its own outcome. The
+// record names the command that showed the prompt and carries its options,
+// along with the answer: "confirmed", "cancelled", or "expired".
+func AuditConfirmations(logger zerolog.Logger, opts ...AuditOption) command.ConfirmationObserver {
+	options := newAuditOptions(opts)
+
+	return func(prompt *command.Context, outcome command.ConfirmationOutcome) {
+		record := auditRecord(logger, prompt, options).Str("confirmation", outcome.Answer)
+		switch {
+		case outcome.Answer != command.ConfirmAnswerConfirmed:
+			record = record.Str("outcome", outcome.Answer)
+		case outcome.Err != nil:
+			record = record.Str("outcome", "error").Err(outcome.Err)
+		default:
+			record = record.Str("outcome", "success")
+		}
+		record.Dur("duration", outcome.Duration).Msg("command audit")
+	}
+}
+
+// newAuditOptions applies opts to the default audit options.
+func newAuditOptions(opts []AuditOption) auditOptions {
+	options := auditOptions{redacted: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// auditRecord starts an audit record for the command ctx runs, with who ran
+// it where and the options given.
+func auditRecord(logger zerolog.Logger, ctx *command.Context, options auditOptions) *zerolog.Event {
+	record := logger.Log().
+		Str("interaction_id", interactionID(ctx)).
+		Str("command", getCommandName(ctx)).
+		Str("user_id", ctx.UserID()).
+		Str("guild_id", ctx.GuildID()).
+		Str("channel_id", ctx.ChannelID()).
+		Bool("text", ctx.Message != nil)
+	if ctx.Interaction != nil {
+		record = record.Interface("options", optionValues(ctx.Interaction.ApplicationCommandData().Options, options.redacted))
+	}
+	return record
+}
+
+// interactionID returns the ID of the interaction ctx responds to, or "".
+func interactionID(ctx *command.Context) string {
+	if ctx == nil || ctx.Interaction == nil {
+		return ""
+	}
+	return ctx.Interaction.ID
+}
+
+// optionValues returns the values of opts by name, with subcommands and
+// groups as nested maps of their own options and redacted names replaced. It
+// is shared by audit records and, when enabled, command log lines.
+func optionValues(opts []*discordgo.ApplicationCommandInteractionDataOption, redacted map[string]bool) map[string]interface{} {
+	values := make(map[string]interface{}, len(opts))
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		switch {
+		case opt.Type == discordgo.ApplicationCommandOptionSubCommand,
+			opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup:
+			values[opt.Name] = optionValues(opt.Options, redacted)
+		case redacted[opt.Name]:
+			values[opt.Name] = RedactedValue
+		default:
+			values[opt.Name] = opt.Value
+		}
+	}
+	return values
+}
@@ -0,0 +1,192 @@
+package middleware_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Audit Tests
+// ============================================================================
+
+func Test_Audit_Record(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantOutcome string
+		wantError   string
+	}{
+		{name: "success", wantOutcome: "success"},
+		{name: "failure", err: errors.New("missing permissions"), wantOutcome: "error", wantError: "missing permissions"},
+		{name: "blocked", err: middleware.ErrCommandBlocked, wantOutcome: "blocked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := newLoggingLogCapture()
+			ctx := createLoggingTestContext(zerolog.Nop(), "user-1", "guild-1", "channel-1", "ban")
+			ctx.Interaction.Data = discordgo.ApplicationCommandInteractionData{
+				Name: "ban",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "target-1"},
+					{Name: "days", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(7)},
+				},
+			}
+
+			handler := middleware.Audit(lc.logger())(func(ctx *command.Context) error {
+				return tt.err
+			})
+			assert.Equal(t, tt.err, handler(ctx))
+
+			entries := lc.entries()
+			require.Len(t, entries, 1)
+			entry := entries[0]
+			assert.Equal(t, "command audit", entry["message"])
+			assert.NotContains(t, entry, "level", "audit records are written without a level")
+			assert.Equal(t, "interaction-123", entry["interaction_id"])
+			assert.Equal(t, "ban", entry["command"])
+			assert.Equal(t, "user-1", entry["user_id"])
+			assert.Equal(t, "guild-1", entry["guild_id"])
+			assert.Equal(t, "channel-1", entry["channel_id"])
+			assert.Equal(t, false, entry["text"])
+			assert.Equal(t, map[string]interface{}{"user": "target-1", "days": float64(7)}, entry["options"])
+			assert.Equal(t, tt.wantOutcome, entry["outcome"])
+			assert.Contains(t, entry, "duration")
+			if tt.wantError != "" {
+				assert.Equal(t, tt.wantError, entry["error"])
+			} else {
+				assert.NotContains(t, entry, "error")
+			}
+		})
+	}
+}
+
+func Test_Audit_AwaitingConfirmation(t *testing.T) {
+	lc := newLoggingLogCapture()
+	ctx, _ := createGuardTestContext(t)
+
+	handler := middleware.Audit(lc.logger())(func(ctx *command.Context) error {
+		return command.NewConfirmer(time.Minute).Confirm(ctx, "Sure?", func(reply func(string) error) error {
+			t.Error("the action should wait for an answer")
+			return nil
+		})
+	})
+	require.NoError(t, handler(ctx))
+
+	entry := lc.lastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "awaiting_confirmation", entry["outcome"], "showing the prompt is not the action succeeding")
+}
+
+func Test_AuditConfirmations(t *testing.T) {
+	tests := []struct {
+		name        string
+		outcome     command.ConfirmationOutcome
+		wantOutcome string
+		wantError   string
+	}{
+		{name: "confirmed", outcome: command.ConfirmationOutcome{Answer: command.ConfirmAnswerConfirmed}, wantOutcome: "success"},
+		{
+			name:        "confirmed but failed",
+			outcome:     command.ConfirmationOutcome{Answer: command.ConfirmAnswerConfirmed, Err: errors.New("missing permissions")},
+			wantOutcome: "error",
+			wantError:   "missing permissions",
+		},
+		{name: "cancelled", outcome: command.ConfirmationOutcome{Answer: command.ConfirmAnswerCancelled}, wantOutcome: "cancelled"},
+		{name: "expired", outcome: command.ConfirmationOutcome{Answer: command.ConfirmAnswerExpired}, wantOutcome: "expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := newLoggingLogCapture()
+			prompt := createLoggingTestContext(zerolog.Nop(), "user-1", "guild-1", "channel-1", "ban")
+			prompt.Interaction.Data = discordgo.ApplicationCommandInteractionData{
+				Name: "ban",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "target-1"},
+					{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Value: "spam"},
+				},
+			}
+
+			middleware.AuditConfirmations(lc.logger(), middleware.WithRedactedOptions("reason"))(prompt, tt.outcome)
+
+			entries := lc.entries()
+			require.Len(t, entries, 1)
+			entry := entries[0]
+			assert.Equal(t, "command audit", entry["message"])
+			assert.Equal(t, "interaction-123", entry["interaction_id"], "the record names the command that prompted")
+			assert.Equal(t, "ban", entry["command"])
+			assert.Equal(t, "user-1", entry["user_id"])
+			assert.Equal(t, map[string]interface{}{"user": "target-1", "reason": middleware.RedactedValue}, entry["options"])
+			assert.Equal(t, tt.outcome.Answer, entry["confirmation"])
+			assert.Equal(t, tt.wantOutcome, entry["outcome"])
+			if tt.wantError != "" {
+				assert.Equal(t, tt.wantError, entry["error"])
+			} else {
+				assert.NotContains(t, entry, "error")
+			}
+		})
+	}
+}
+
+func Test_Audit_RedactedOptions(t *testing.T) {
+	lc := newLoggingLogCapture()
+	ctx := createLoggingTestContext(zerolog.Nop(), "user-1", "guild-1", "channel-1", "mod")
+	ctx.Interaction.Data = discordgo.ApplicationCommandInteractionData{
+		Name: "mod",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: "warn", Type: discordgo.ApplicationCommandOptionSubCommand, Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "target-1"},
+				{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Value: "lives at 1 Main St"},
+			}},
+		},
+	}
+
+	handler := middleware.Audit(lc.logger(), middleware.WithRedactedOptions("reason"))(func(ctx *command.Context) error {
+		return nil
+	})
+	require.NoError(t, handler(ctx))
+
+	entry := lc.lastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, map[string]interface{}{
+		"warn": map[string]interface{}{"user": "target-1", "reason": middleware.RedactedValue},
+	}, entry["options"], "subcommand options are nested and redacted by name")
+	assert.False(t, lc.contains("Main St"))
+}
+
+func Test_Audit_TextCommand(t *testing.T) {
+	lc := newLoggingLogCapture()
+	ctx := createLoggingTestContext(zerolog.Nop(), "user-1", "guild-1", "channel-1", "ping")
+	ctx.Message = &discordgo.Message{ID: "message-1", ChannelID: "channel-1"}
+
+	handler := middleware.Audit(lc.logger())(func(ctx *command.Context) error {
+		return nil
+	})
+	require.NoError(t, handler(ctx))
+
+	entry := lc.lastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, true, entry["text"])
+}
+
+func Test_Audit_NotFilteredByLevel(t *testing.T) {
+	lc := newLoggingLogCapture()
+	ctx := createLoggingTestContext(zerolog.Nop(), "user-1", "guild-1", "channel-1", "ping")
+
+	handler := middleware.Audit(lc.logger().Level(zerolog.ErrorLevel))(func(ctx *command.Context) error {
+		return nil
+	})
+	require.NoError(t, handler(ctx))
+
+	assert.Len(t, lc.entries(), 1, "audit records must not be dropped by the log level")
+}
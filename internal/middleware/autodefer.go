@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"jamesbot/internal/command"
+)
+
+// DefaultAutoDeferDelay is how long AutoDefer waits for a response, leaving a
+// margin before the three seconds Discord allows.
+const DefaultAutoDeferDelay = 2500 * time.Millisecond
+
+// AutoDefer creates a middleware that defers the response to an interaction
+// if the command has not responded within delay, so slow commands show the
+// bot thinking instead of "The application did not respond". The command's
+// eventual response then replaces the deferred one, as described for
+// command.Context.Defer. The deferral is public; an ephemeral response is
+// still sent privately. Text commands, which have no deadline, are not
+// deferred. A delay of zero or less disables it.
+func AutoDefer(delay time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if delay <= 0 {
+			return next
+		}
+		return func(ctx *command.Context) error {
+			if ctx.Session == nil || ctx.Message != nil {
+				return next(ctx)
+			}
+
+			// done stops a timer that fires as the command returns from
+			// deferring afterwards, which would leave the bot thinking forever
+			var mu sync.Mutex
+			done := false
+			timer := time.AfterFunc(delay, func() {
+				mu.Lock()
+				defer mu.Unlock()
+				if done {
+					return
+				}
+				if err := ctx.Defer(false); err != nil {
+					ctx.Logger.Error().Err(err).Str("command", getCommandName(ctx)).Msg("failed to defer slow command")
+				}
+			})
+
+			err := next(ctx)
+
+			timer.Stop()
+			mu.Lock()
+			done = true
+			mu.Unlock()
+			return err
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// AutoDefer Tests
+// ============================================================================
+
+func Test_AutoDefer(t *testing.T) {
+	tests := []struct {
+		name      string
+		delay     time.Duration
+		work      time.Duration
+		wantTypes []discordgo.InteractionResponseType
+		wantEdit  bool
+	}{
+		{
+			name:      "slow command is deferred and its response edits the deferral",
+			delay:     10 * time.Millisecond,
+			work:      100 * time.Millisecond,
+			wantTypes: []discordgo.InteractionResponseType{discordgo.InteractionResponseDeferredChannelMessageWithSource},
+			wantEdit:  true,
+		},
+		{
+			name:      "fast command responds normally",
+			delay:     100 * time.Millisecond,
+			wantTypes: []discordgo.InteractionResponseType{discordgo.InteractionResponseChannelMessageWithSource},
+		},
+		{
+			name:      "zero delay disables deferral",
+			delay:     0,
+			work:      20 * time.Millisecond,
+			wantTypes: []discordgo.InteractionResponseType{discordgo.InteractionResponseChannelMessageWithSource},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, rc := createGuardTestContext(t)
+			handler := middleware.AutoDefer(tt.delay)(func(ctx *command.Context) error {
+				time.Sleep(tt.work)
+				return ctx.Respond("done")
+			})
+
+			require.NoError(t, handler(ctx))
+			// A deferral must never follow the response
+			time.Sleep(2 * tt.delay)
+
+			rc.mu.Lock()
+			defer rc.mu.Unlock()
+			require.Len(t, rc.bodies, len(tt.wantTypes)+btoi(tt.wantEdit))
+			for i, want := range tt.wantTypes {
+				var resp discordgo.InteractionResponse
+				require.NoError(t, json.Unmarshal([]byte(rc.bodies[i]), &resp))
+				assert.Equal(t, want, resp.Type)
+			}
+			if tt.wantEdit {
+				assert.JSONEq(t, `{"content":"done"}`, rc.bodies[len(rc.bodies)-1])
+			}
+		})
+	}
+}
+
+func Test_AutoDefer_CommandReturnsBeforeDelay(t *testing.T) {
+	ctx, rc := createGuardTestContext(t)
+	handler := middleware.AutoDefer(10 * time.Millisecond)(func(ctx *command.Context) error {
+		return nil
+	})
+
+	require.NoError(t, handler(ctx))
+	time.Sleep(30 * time.Millisecond)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	assert.Empty(t, rc.bodies, "a command that has returned must not be deferred")
+}
+
+func Test_AutoDefer_TextCommand(t *testing.T) {
+	ctx, rc := createGuardTestContext(t)
+	ctx.Message = &discordgo.Message{ID: "message-1", ChannelID: "test-channel"}
+	handler := middleware.AutoDefer(time.Millisecond)(func(ctx *command.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	require.NoError(t, handler(ctx))
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	assert.Empty(t, rc.bodies, "text commands have no deadline to defer for")
+}
+
+// btoi returns 1 for true and 0 for false.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
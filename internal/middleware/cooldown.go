@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultCooldownBypassPermission is the permission that lets members skip
+// cooldowns unless WithCooldownBypass says otherwise.
+const DefaultCooldownBypassPermission = discordgo.PermissionManageGuild
+
+// maxCooldownEntries bounds how many member and command pairs Cooldowns
+// tracks at once. Pairs whose cooldown has passed are dropped first.
+const maxCooldownEntries = 10000
+
+// CooldownBypass reports whether the member running a command skips its
+// cooldown.
+type CooldownBypass func(ctx *command.Context) bool
+
+// BypassPermission lets members with every permission in bits, or with
+// Administrator, skip cooldowns.
+func BypassPermission(bits int64) CooldownBypass {
+	return func(ctx *command.Context) bool {
+		return ctx.HasPermission(bits)
+	}
+}
+
+// BypassRoles lets members with any of the roles skip cooldowns.
+func BypassRoles(roleIDs ...string) CooldownBypass {
+	return func(ctx *command.Context) bool {
+		for _, id := range roleIDs {
+			if ctx.HasRole(id) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CooldownOption configures a Cooldowns.
+type CooldownOption func(*Cooldowns)
+
+// WithCooldownBypass sets who skips cooldowns: members for whom any of the
+// conditions holds. It replaces the default, members with
+// DefaultCooldownBypassPermission; with no conditions, nobody skips them.
+func WithCooldownBypass(conditions ...CooldownBypass) CooldownOption {
+	return func(c *Cooldowns) {
+		c.bypass = conditions
+	}
+}
+
+// Cooldowns makes members wait between uses of the same command, so one
+// member cannot flood the bot, while staff can be let through during an
+// incident. It is safe for concurrent use.
+type Cooldowns struct {
+	period time.Duration
+	bypass []CooldownBypass
+
+	mu sync.Mutex
+	// until holds when each member may next use each command.
+	until map[cooldownKey]time.Time
+}
+
+// cooldownKey identifies one member's use of one command.
+type cooldownKey struct {
+	userID  string
+	command string
+}
+
+// NewCooldowns creates cooldowns that make members wait period between uses
+// of a command. It returns nil, which never holds anyone back, when period
+// is not positive.
+func NewCooldowns(period time.Duration, opts ...CooldownOption) *Cooldowns {
+	if period <= 0 {
+		return nil
+	}
+	c := &Cooldowns{
+		period: period,
+		bypass: []CooldownBypass{BypassPermission(DefaultCooldownBypassPermission)},
+		until:  make(map[cooldownKey]time.Time),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Allow records a use of the named command by userID at now if it is off
+// cooldown. Otherwise it returns how long until it may be used again.
+func (c *Cooldowns) Allow(now time.Time, userID, name string) (time.Duration, bool) {
+	if c == nil {
+		return 0, true
+	}
+	key := cooldownKey{userID: userID, command: name}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if until, ok := c.until[key]; ok && now.Before(until) {
+		return until.Sub(now), false
+	}
+
+	if _, tracked := c.until[key]; !tracked && len(c.until) >= maxCooldownEntries {
+		c.sweep(now)
+		if len(c.until) >= maxCooldownEntries {
+			return 0, true
+		}
+	}
+	c.until[key] = now.Add(c.period)
+	return 0, true
+}
+
+// Bypassed reports whether the member running ctx skips cooldowns.
+func (c *Cooldowns) Bypassed(ctx *command.Context) bool {
+	if c == nil {
+		return true
+	}
+	for _, bypass := range c.bypass {
+		if bypass(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// sweep drops pairs whose cooldown has passed by now. Callers must hold c.mu.
+func (c *Cooldowns) sweep(now time.Time) {
+	for key, until := range c.until {
+		if !now.Before(until) {
+			delete(c.until, key)
+		}
+	}
+}
+
+// Cooldown creates a middleware that holds members back from using a command
+// again before its cooldown has passed. A held back command gets an
+// ephemeral response saying how long to wait and the rest of the chain,
+// including the command itself, is skipped, returning ErrCommandBlocked.
+// Members cooldowns lets bypass them are neither held back nor counted. A nil
+// cooldowns lets every command run.
+func Cooldown(cooldowns *Cooldowns) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if cooldowns == nil {
+			return next
+		}
+		return func(ctx *command.Context) error {
+			if cooldowns.Bypassed(ctx) {
+				return next(ctx)
+			}
+			wait, ok := cooldowns.Allow(time.Now(), ctx.UserID(), getCommandName(ctx))
+			if ok {
+				return next(ctx)
+			}
+			return Block(ctx, ctx.T(i18n.MsgCooldown, int(math.Ceil(wait.Seconds()))))
+		}
+	}
+}
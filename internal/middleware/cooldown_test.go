@@ -0,0 +1,155 @@
+package middleware_test
+
+import (
+	"testing"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Cooldowns Tests
+// ============================================================================
+
+func Test_Cooldowns_Allow(t *testing.T) {
+	cooldowns := middleware.NewCooldowns(10 * time.Second)
+	now := time.Now()
+
+	_, ok := cooldowns.Allow(now, "user-1", "ban")
+	assert.True(t, ok, "first use is allowed")
+
+	wait, ok := cooldowns.Allow(now.Add(4*time.Second), "user-1", "ban")
+	assert.False(t, ok, "second use within the period is held back")
+	assert.Equal(t, 6*time.Second, wait)
+
+	_, ok = cooldowns.Allow(now.Add(4*time.Second), "user-2", "ban")
+	assert.True(t, ok, "other members have their own cooldown")
+
+	_, ok = cooldowns.Allow(now.Add(4*time.Second), "user-1", "kick")
+	assert.True(t, ok, "other commands have their own cooldown")
+
+	_, ok = cooldowns.Allow(now.Add(10*time.Second), "user-1", "ban")
+	assert.True(t, ok, "allowed again once the period has passed")
+}
+
+func Test_Cooldowns_Disabled(t *testing.T) {
+	for _, period := range []time.Duration{0, -time.Second} {
+		cooldowns := middleware.NewCooldowns(period)
+		assert.Nil(t, cooldowns)
+
+		now := time.Now()
+		for i := 0; i < 3; i++ {
+			_, ok := cooldowns.Allow(now, "user-1", "ban")
+			assert.True(t, ok)
+		}
+	}
+}
+
+// ============================================================================
+// Cooldown Middleware Tests
+// ============================================================================
+
+func Test_Cooldown(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []middleware.CooldownOption
+		permissions int64
+		roles       []string
+		wantRuns    int
+	}{
+		{
+			name:     "regular member is held back",
+			wantRuns: 1,
+		},
+		{
+			name:        "member with Manage Server bypasses by default",
+			permissions: discordgo.PermissionManageGuild,
+			wantRuns:    3,
+		},
+		{
+			name:        "administrator bypasses by default",
+			permissions: discordgo.PermissionAdministrator,
+			wantRuns:    3,
+		},
+		{
+			name:        "configured permission bypasses",
+			opts:        []middleware.CooldownOption{middleware.WithCooldownBypass(middleware.BypassPermission(discordgo.PermissionModerateMembers))},
+			permissions: discordgo.PermissionModerateMembers,
+			wantRuns:    3,
+		},
+		{
+			name:        "configured permission replaces the default",
+			opts:        []middleware.CooldownOption{middleware.WithCooldownBypass(middleware.BypassPermission(discordgo.PermissionModerateMembers))},
+			permissions: discordgo.PermissionManageGuild,
+			wantRuns:    1,
+		},
+		{
+			name:     "configured role bypasses",
+			opts:     []middleware.CooldownOption{middleware.WithCooldownBypass(middleware.BypassRoles("staff", "mods"))},
+			roles:    []string{"members", "mods"},
+			wantRuns: 3,
+		},
+		{
+			name:     "other roles are held back",
+			opts:     []middleware.CooldownOption{middleware.WithCooldownBypass(middleware.BypassRoles("staff", "mods"))},
+			roles:    []string{"members"},
+			wantRuns: 1,
+		},
+		{
+			name:        "no bypass conditions holds everyone back",
+			opts:        []middleware.CooldownOption{middleware.WithCooldownBypass()},
+			permissions: discordgo.PermissionAdministrator,
+			wantRuns:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := middleware.Cooldown(middleware.NewCooldowns(time.Minute, tt.opts...))
+			runs := 0
+			handler := mw(func(ctx *command.Context) error {
+				runs++
+				return nil
+			})
+
+			var rc *responseCapture
+			for i := 0; i < 3; i++ {
+				var ctx *command.Context
+				ctx, rc = createGuardTestContext(t)
+				ctx.Interaction.Member.Permissions = tt.permissions
+				ctx.Interaction.Member.Roles = tt.roles
+				if err := handler(ctx); i < tt.wantRuns {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, middleware.ErrCommandBlocked)
+				}
+			}
+
+			assert.Equal(t, tt.wantRuns, runs)
+			if tt.wantRuns < 3 {
+				require.Len(t, rc.bodies, 1, "the last use, held back, should get a response")
+				assert.Contains(t, rc.bodies[0], "too quickly")
+				assert.Contains(t, rc.bodies[0], "60s")
+			}
+		})
+	}
+}
+
+func Test_Cooldown_Nil(t *testing.T) {
+	mw := middleware.Cooldown(nil)
+	runs := 0
+	handler := mw(func(ctx *command.Context) error {
+		runs++
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, handler(createTestContext()))
+	}
+	assert.Equal(t, 3, runs)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"sync"
+
+	"jamesbot/internal/command"
+)
+
+// DisabledCommandMessage is the ephemeral response to a disabled command.
+const DisabledCommandMessage = "This command is currently disabled."
+
+// CommandFlags records which commands have been disabled at runtime.
+// Commands are enabled unless disabled. It is safe for concurrent use.
+type CommandFlags struct {
+	mu       sync.RWMutex
+	disabled map[string]bool
+}
+
+// NewCommandFlags creates a CommandFlags with every command enabled.
+func NewCommandFlags() *CommandFlags {
+	return &CommandFlags{disabled: make(map[string]bool)}
+}
+
+// Enabled reports whether the named command may run.
+// A nil CommandFlags enables every command.
+func (f *CommandFlags) Enabled(name string) bool {
+	if f == nil {
+		return true
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return !f.disabled[name]
+}
+
+// SetEnabled enables or disables the named command.
+func (f *CommandFlags) SetEnabled(name string, enabled bool) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if enabled {
+		delete(f.disabled, name)
+	} else {
+		f.disabled[name] = true
+	}
+}
+
+// Guard creates a middleware that stops disabled commands from running.
+// A disabled command gets an ephemeral DisabledCommandMessage response and
+// the rest of the chain, including the command itself, is skipped, returning
+// ErrCommandBlocked.
+func Guard(flags *CommandFlags) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *command.Context) error {
+			if flags.Enabled(getCommandName(ctx)) {
+				return next(ctx)
+			}
+			return Block(ctx, DisabledCommandMessage)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// responseCapture is an http.RoundTripper that records request bodies and
+// answers with 204 No Content, standing in for Discord's REST API. Webhook
+// requests, such as edits to a deferred response, are answered with a
+// message, as Discord does.
+type responseCapture struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (rc *responseCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	rc.mu.Lock()
+	rc.bodies = append(rc.bodies, string(body))
+	rc.mu.Unlock()
+	if strings.Contains(req.URL.Path, "/webhooks/") {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"message-1"}`)),
+			Request:    req,
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// createGuardTestContext creates a context for the "testcmd" command whose
+// responses are captured by the returned transport.
+func createGuardTestContext(t *testing.T) (*command.Context, *responseCapture) {
+	t.Helper()
+	s, err := discordgo.New("Bot test-token")
+	require.NoError(t, err)
+	rc := &responseCapture{}
+	s.Client = &http.Client{Transport: rc}
+
+	ctx := createTestContext()
+	ctx.Session = s
+	return ctx, rc
+}
+
+// ============================================================================
+// CommandFlags Tests
+// ============================================================================
+
+func Test_CommandFlags_SetEnabled(t *testing.T) {
+	flags := middleware.NewCommandFlags()
+	assert.True(t, flags.Enabled("ban"), "commands start enabled")
+
+	flags.SetEnabled("ban", false)
+	assert.False(t, flags.Enabled("ban"))
+	assert.True(t, flags.Enabled("kick"), "disabling one command leaves others alone")
+
+	flags.SetEnabled("ban", false)
+	flags.SetEnabled("ban", true)
+	assert.True(t, flags.Enabled("ban"))
+}
+
+func Test_CommandFlags_Nil(t *testing.T) {
+	var flags *middleware.CommandFlags
+
+	assert.NotPanics(t, func() { flags.SetEnabled("ban", false) })
+	assert.True(t, flags.Enabled("ban"))
+}
+
+func Test_CommandFlags_Concurrent(t *testing.T) {
+	flags := middleware.NewCommandFlags()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			flags.SetEnabled("ban", i%2 == 0)
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = flags.Enabled("ban")
+		}()
+	}
+	wg.Wait()
+}
+
+// ============================================================================
+// Guard Tests
+// ============================================================================
+
+func Test_Guard(t *testing.T) {
+	tests := []struct {
+		name         string
+		disable      string
+		wantRun      bool
+		wantResponse bool
+	}{
+		{name: "enabled command runs", wantRun: true},
+		{name: "other command disabled", disable: "othercmd", wantRun: true},
+		{name: "disabled command is short-circuited", disable: "testcmd", wantResponse: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := middleware.NewCommandFlags()
+			if tt.disable != "" {
+				flags.SetEnabled(tt.disable, false)
+			}
+			ctx, rc := createGuardTestContext(t)
+
+			ran := false
+			handler := middleware.Guard(flags)(func(ctx *command.Context) error {
+				ran = true
+				return nil
+			})
+
+			if err := handler(ctx); tt.wantRun {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, middleware.ErrCommandBlocked)
+			}
+			assert.Equal(t, tt.wantRun, ran)
+			if !tt.wantResponse {
+				assert.Empty(t, rc.bodies)
+				return
+			}
+			require.Len(t, rc.bodies, 1)
+			assert.Contains(t, rc.bodies[0], middleware.DisabledCommandMessage)
+			assert.Contains(t, rc.bodies[0], `"flags":64`, "the response should be ephemeral")
+		})
+	}
+}
+
+func Test_Guard_ReenabledCommandRuns(t *testing.T) {
+	flags := middleware.NewCommandFlags()
+	runs := 0
+	handler := middleware.Guard(flags)(func(ctx *command.Context) error {
+		runs++
+		return nil
+	})
+
+	flags.SetEnabled("testcmd", false)
+	ctx, _ := createGuardTestContext(t)
+	require.ErrorIs(t, handler(ctx), middleware.ErrCommandBlocked)
+
+	flags.SetEnabled("testcmd", true)
+	require.NoError(t, handler(ctx))
+
+	assert.Equal(t, 1, runs, "the guard reads the flag on every invocation")
+}
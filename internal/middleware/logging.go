@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"time"
 
 	"jamesbot/internal/command"
@@ -8,11 +9,70 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// LoggingOption configures the Logging middleware.
+type LoggingOption func(*loggingOptions)
+
+type loggingOptions struct {
+	sampleSuccesses uint32
+	alwaysLogged    map[string]bool
+	withOptions     bool
+	redacted        map[string]bool
+}
+
+// WithSuccessSampling logs only one in every n successful executions, so a
+// flood of routine commands cannot overwhelm the log pipeline. Failures are
+// always logged. An n of 0 or 1 logs every execution.
+func WithSuccessSampling(n uint32) LoggingOption {
+	return func(o *loggingOptions) {
+		o.sampleSuccesses = n
+	}
+}
+
+// WithAlwaysLogged names commands whose successful executions are logged even
+// when successes are sampled, such as moderation actions.
+func WithAlwaysLogged(names ...string) LoggingOption {
+	return func(o *loggingOptions) {
+		for _, name := range names {
+			o.alwaysLogged[name] = true
+		}
+	}
+}
+
+// WithCommandOptions adds the options each command was run with to its log
+// lines, so operators can see exactly what was invoked. Values of the options
+// named in redacted are replaced with RedactedValue, such as free-text
+// reasons that may hold personal information. Without it, option values are
+// never logged.
+func WithCommandOptions(redacted ...string) LoggingOption {
+	return func(o *loggingOptions) {
+		o.withOptions = true
+		for _, name := range redacted {
+			o.redacted[name] = true
+		}
+	}
+}
+
 // Logging creates a middleware that logs command executions.
 // It records the command name, user ID, guild ID, execution duration,
 // and any errors that occur. Successful executions are logged at Info level,
 // while failures are logged at Error level.
-func Logging(logger zerolog.Logger) Middleware {
+//
+// Successes may be sampled with WithSuccessSampling; failures and commands
+// named with WithAlwaysLogged are never sampled. Command options are only
+// logged with WithCommandOptions.
+func Logging(logger zerolog.Logger, opts ...LoggingOption) Middleware {
+	options := loggingOptions{alwaysLogged: make(map[string]bool), redacted: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// The sampler is shared by every execution, so it counts successes across
+	// all routine commands.
+	successLogger := logger
+	if options.sampleSuccesses > 1 {
+		successLogger = logger.Sample(&zerolog.BasicSampler{N: options.sampleSuccesses})
+	}
+
 	return func(next HandlerFunc) HandlerFunc {
 		return func(ctx *command.Context) error {
 			// Record start time
@@ -30,20 +90,34 @@ func Logging(logger zerolog.Logger) Middleware {
 			// Calculate duration
 			duration := time.Since(start)
 
+			// Failures and audited commands bypass sampling
+			blocked := errors.Is(err, ErrCommandBlocked)
+			base := successLogger
+			if (err != nil && !blocked) || options.alwaysLogged[commandName] {
+				base = logger
+			}
+
 			// Build log event with context
-			logEvent := logger.With().
+			fields := base.With().
 				Str("command", commandName).
 				Str("user_id", ctx.UserID()).
 				Str("guild_id", ctx.GuildID()).
-				Dur("duration", duration).
-				Logger()
+				Dur("duration", duration)
+			if options.withOptions && ctx.Interaction != nil {
+				fields = fields.Interface("options", optionValues(ctx.Interaction.ApplicationCommandData().Options, options.redacted))
+			}
+			logEvent := fields.Logger()
 
 			// Log based on success or failure
-			if err != nil {
+			switch {
+			case blocked:
+				logEvent.Info().
+					Msg("command blocked")
+			case err != nil:
 				logEvent.Error().
 					Err(err).
 					Msg("command execution failed")
-			} else {
+			default:
 				logEvent.Info().
 					Msg("command executed successfully")
 			}
@@ -184,6 +184,24 @@ func Test_Logging_FailedCommand(t *testing.T) {
 	}
 }
 
+func Test_Logging_BlockedCommand(t *testing.T) {
+	capture := newLoggingLogCapture()
+	logger := capture.logger()
+
+	wrapped := middleware.Logging(logger)(func(ctx *command.Context) error {
+		return middleware.ErrCommandBlocked
+	})
+	ctx := createLoggingTestContext(logger, "user-123", "guild-456", "channel-789", "ban")
+
+	assert.ErrorIs(t, wrapped(ctx), middleware.ErrCommandBlocked)
+
+	entry := capture.lastEntry()
+	require.NotNil(t, entry, "should have logged an entry")
+	assert.Equal(t, "command blocked", entry["message"])
+	assert.Equal(t, "info", entry["level"], "a blocked command is not a failure")
+	assert.NotContains(t, entry, "error")
+}
+
 func Test_Logging_LogsDuration(t *testing.T) {
 	capture := newLoggingLogCapture()
 	logger := capture.logger()
@@ -558,3 +576,132 @@ func Benchmark_Logging_Middleware_RealLogger(b *testing.B) {
 		_ = wrapped(ctx)
 	}
 }
+
+func Test_Logging_SuccessSampling(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []middleware.LoggingOption
+		commandName string
+		fail        bool
+		runs        int
+		wantEntries int
+	}{
+		{
+			name:        "no sampling logs every success",
+			commandName: "ping",
+			runs:        10,
+			wantEntries: 10,
+		},
+		{
+			name:        "sampling of one logs every success",
+			opts:        []middleware.LoggingOption{middleware.WithSuccessSampling(1)},
+			commandName: "ping",
+			runs:        10,
+			wantEntries: 10,
+		},
+		{
+			name:        "routine successes are sampled",
+			opts:        []middleware.LoggingOption{middleware.WithSuccessSampling(5)},
+			commandName: "ping",
+			runs:        10,
+			wantEntries: 2,
+		},
+		{
+			name:        "failures are never sampled",
+			opts:        []middleware.LoggingOption{middleware.WithSuccessSampling(5)},
+			commandName: "ping",
+			fail:        true,
+			runs:        10,
+			wantEntries: 10,
+		},
+		{
+			name: "always-logged commands are never sampled",
+			opts: []middleware.LoggingOption{
+				middleware.WithSuccessSampling(5),
+				middleware.WithAlwaysLogged(command.ModerationCommands...),
+			},
+			commandName: "ban",
+			runs:        10,
+			wantEntries: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := newLoggingLogCapture()
+			logger := capture.logger()
+
+			wrapped := middleware.Logging(logger, tt.opts...)(func(ctx *command.Context) error {
+				if tt.fail {
+					return errors.New("boom")
+				}
+				return nil
+			})
+
+			for range tt.runs {
+				ctx := createLoggingTestContext(logger, "user-123", "guild-456", "channel-789", tt.commandName)
+				_ = wrapped(ctx)
+			}
+
+			assert.Len(t, capture.entries(), tt.wantEntries)
+		})
+	}
+}
+
+func Test_Logging_CommandOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []middleware.LoggingOption
+		wantOptions interface{}
+	}{
+		{
+			name: "options are not logged by default",
+		},
+		{
+			name: "options are logged when enabled",
+			opts: []middleware.LoggingOption{middleware.WithCommandOptions()},
+			wantOptions: map[string]interface{}{
+				"user":   "target-1",
+				"reason": "spamming my phone number",
+				"days":   float64(7),
+			},
+		},
+		{
+			name: "redacted options keep their name only",
+			opts: []middleware.LoggingOption{middleware.WithCommandOptions("reason")},
+			wantOptions: map[string]interface{}{
+				"user":   "target-1",
+				"reason": middleware.RedactedValue,
+				"days":   float64(7),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := newLoggingLogCapture()
+			logger := capture.logger()
+			ctx := createLoggingTestContext(logger, "user-123", "guild-456", "channel-789", "ban")
+			ctx.Interaction.Data = discordgo.ApplicationCommandInteractionData{
+				Name: "ban",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "target-1"},
+					{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Value: "spamming my phone number"},
+					{Name: "days", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(7)},
+				},
+			}
+
+			wrapped := middleware.Logging(logger, tt.opts...)(func(ctx *command.Context) error {
+				return nil
+			})
+			require.NoError(t, wrapped(ctx))
+
+			entry := capture.lastEntry()
+			require.NotNil(t, entry)
+			assert.Equal(t, tt.wantOptions, entry["options"])
+			if tt.wantOptions == nil {
+				assert.False(t, capture.contains("target-1"), "option values should not be logged")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaintenanceOption configures a Maintenance.
+type MaintenanceOption func(*Maintenance)
+
+// WithMaintenanceMessage replaces the localized reply to commands held back
+// during maintenance with msg. An empty msg keeps the localized reply.
+func WithMaintenanceMessage(msg string) MaintenanceOption {
+	return func(m *Maintenance) {
+		m.message = msg
+	}
+}
+
+// WithMaintenanceExempt names commands that keep running during maintenance,
+// such as those operators use to check on the bot.
+func WithMaintenanceExempt(names ...string) MaintenanceOption {
+	return func(m *Maintenance) {
+		for _, name := range names {
+			m.exempt[name] = true
+		}
+	}
+}
+
+// Maintenance records whether the bot is in maintenance mode, during which
+// members' commands are held back so operators can quiesce the bot during a
+// deploy without stopping it. It starts off. It is safe for concurrent use.
+type Maintenance struct {
+	message string
+	exempt  map[string]bool
+
+	mu sync.RWMutex
+	// since is when maintenance mode was turned on, or zero while it is off.
+	since time.Time
+}
+
+// NewMaintenance creates a Maintenance with maintenance mode off.
+func NewMaintenance(opts ...MaintenanceOption) *Maintenance {
+	m := &Maintenance{exempt: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Set turns maintenance mode on or off. Turning it on while it is already on
+// keeps the time it was first turned on.
+func (m *Maintenance) Set(enabled bool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case !enabled:
+		m.since = time.Time{}
+	case m.since.IsZero():
+		m.since = time.Now()
+	}
+}
+
+// State reports whether maintenance mode is on and, if so, since when.
+// A nil Maintenance is always off.
+func (m *Maintenance) State() (bool, time.Time) {
+	if m == nil {
+		return false, time.Time{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !m.since.IsZero(), m.since
+}
+
+// Exempt reports whether the command ctx runs keeps running during
+// maintenance: it was named with WithMaintenanceExempt, or the member running
+// it is an administrator, as server owners always are.
+func (m *Maintenance) Exempt(ctx *command.Context) bool {
+	if m == nil {
+		return true
+	}
+	return m.exempt[getCommandName(ctx)] || ctx.HasPermission(discordgo.PermissionAdministrator)
+}
+
+// MaintenanceGuard creates a middleware that holds back commands while
+// maintenance mode is on. A held back command gets an ephemeral maintenance
+// response and the rest of the chain, including the command itself, is
+// skipped, returning ErrCommandBlocked. Commands maintenance exempts run as
+// usual. A nil maintenance lets every command run.
+func MaintenanceGuard(maintenance *Maintenance) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if maintenance == nil {
+			return next
+		}
+		return func(ctx *command.Context) error {
+			if on, _ := maintenance.State(); !on || maintenance.Exempt(ctx) {
+				return next(ctx)
+			}
+			if maintenance.message != "" {
+				return Block(ctx, maintenance.message)
+			}
+			return Block(ctx, ctx.T(i18n.MsgMaintenance))
+		}
+	}
+}
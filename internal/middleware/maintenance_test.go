@@ -0,0 +1,133 @@
+package middleware_test
+
+import (
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Maintenance Tests
+// ============================================================================
+
+func Test_Maintenance_Set(t *testing.T) {
+	maintenance := middleware.NewMaintenance()
+
+	on, since := maintenance.State()
+	assert.False(t, on, "maintenance mode starts off")
+	assert.True(t, since.IsZero())
+
+	maintenance.Set(true)
+	on, since = maintenance.State()
+	assert.True(t, on)
+	assert.False(t, since.IsZero())
+
+	maintenance.Set(true)
+	_, again := maintenance.State()
+	assert.Equal(t, since, again, "turning it on again keeps when it started")
+
+	maintenance.Set(false)
+	on, since = maintenance.State()
+	assert.False(t, on)
+	assert.True(t, since.IsZero())
+}
+
+// ============================================================================
+// MaintenanceGuard Middleware Tests
+// ============================================================================
+
+func Test_MaintenanceGuard(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []middleware.MaintenanceOption
+		on          bool
+		permissions int64
+		wantRun     bool
+		wantReply   string
+	}{
+		{
+			name:    "commands run while off",
+			wantRun: true,
+		},
+		{
+			name:      "commands are held back while on",
+			on:        true,
+			wantReply: "under maintenance",
+		},
+		{
+			name:        "moderators are held back too",
+			on:          true,
+			permissions: discordgo.PermissionManageGuild | discordgo.PermissionBanMembers,
+			wantReply:   "under maintenance",
+		},
+		{
+			name:        "administrators are exempt",
+			on:          true,
+			permissions: discordgo.PermissionAdministrator,
+			wantRun:     true,
+		},
+		{
+			name:    "exempt commands run",
+			opts:    []middleware.MaintenanceOption{middleware.WithMaintenanceExempt("perms", "testcmd")},
+			on:      true,
+			wantRun: true,
+		},
+		{
+			name:      "other commands are still held back",
+			opts:      []middleware.MaintenanceOption{middleware.WithMaintenanceExempt("perms")},
+			on:        true,
+			wantReply: "under maintenance",
+		},
+		{
+			name:      "configured message",
+			opts:      []middleware.MaintenanceOption{middleware.WithMaintenanceMessage("Deploying, back soon!")},
+			on:        true,
+			wantReply: "Deploying, back soon!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maintenance := middleware.NewMaintenance(tt.opts...)
+			maintenance.Set(tt.on)
+			ran := false
+			handler := middleware.MaintenanceGuard(maintenance)(func(ctx *command.Context) error {
+				ran = true
+				return nil
+			})
+
+			ctx, rc := createGuardTestContext(t)
+			ctx.Interaction.Member.Permissions = tt.permissions
+			if err := handler(ctx); tt.wantRun {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, middleware.ErrCommandBlocked)
+			}
+
+			assert.Equal(t, tt.wantRun, ran)
+			if tt.wantRun {
+				assert.Empty(t, rc.bodies, "commands that run should get no maintenance reply")
+				return
+			}
+			require.Len(t, rc.bodies, 1)
+			assert.Contains(t, rc.bodies[0], tt.wantReply)
+			assert.Contains(t, rc.bodies[0], `"flags":64`, "the reply should be ephemeral")
+		})
+	}
+}
+
+func Test_MaintenanceGuard_Nil(t *testing.T) {
+	ran := false
+	handler := middleware.MaintenanceGuard(nil)(func(ctx *command.Context) error {
+		ran = true
+		return nil
+	})
+
+	require.NoError(t, handler(createTestContext()))
+	assert.True(t, ran)
+}
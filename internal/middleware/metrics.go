@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"time"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/metrics"
+)
+
+// Metrics creates a middleware that records how long each command takes to
+// execute in collector, whether or not it succeeds.
+func Metrics(collector *metrics.Collector) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *command.Context) error {
+			start := time.Now()
+			err := next(ctx)
+			collector.Observe(getCommandName(ctx), time.Since(start))
+			return err
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"errors"
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/metrics"
+	"jamesbot/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ============================================================================
+// Metrics Middleware Tests
+// ============================================================================
+
+func Test_Metrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler middleware.HandlerFunc
+		wantErr bool
+	}{
+		{
+			name:    "times successful commands",
+			handler: func(ctx *command.Context) error { return nil },
+		},
+		{
+			name:    "times failed commands",
+			handler: func(ctx *command.Context) error { return errors.New("boom") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := metrics.NewCollector()
+
+			err := middleware.Metrics(collector)(tt.handler)(createTestContext())
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, uint64(1), collector.Percentiles()["testcmd"].Count)
+			assert.False(t, collector.LastObserved().IsZero(), "execution time should be recorded")
+		})
+	}
+}
@@ -1,7 +1,17 @@
 // Package middleware provides middleware support for command execution.
 package middleware
 
-import "jamesbot/internal/command"
+import (
+	"errors"
+
+	"jamesbot/internal/command"
+)
+
+// ErrCommandBlocked is returned by middlewares that answer a command
+// themselves instead of letting it run, such as Guard for a disabled command.
+// The member has already been told why, so it is neither a success nor a
+// failure of the command.
+var ErrCommandBlocked = errors.New("command blocked")
 
 // HandlerFunc is a function that handles command execution.
 // It takes a command context and returns an error if the execution fails.
@@ -32,3 +42,13 @@ func Chain(middlewares ...Middleware) Middleware {
 		return handler
 	}
 }
+
+// Block responds ephemerally to the command in ctx with message in place of
+// running it, and returns ErrCommandBlocked, or the error sending the
+// response.
+func Block(ctx *command.Context, message string) error {
+	if err := ctx.RespondEphemeral(message); err != nil {
+		return err
+	}
+	return ErrCommandBlocked
+}
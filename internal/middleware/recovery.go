@@ -10,6 +10,9 @@ import (
 	"jamesbot/pkg/errutil"
 )
 
+// PanicMessage is the response sent when a handler panics.
+const PanicMessage = "An unexpected error occurred. The issue has been logged."
+
 // Recovery creates a middleware that recovers from panics during command execution.
 // When a panic occurs, it logs the panic with a stack trace and returns a
 // user-friendly error message. This prevents the bot from crashing when
@@ -34,7 +37,7 @@ func Recovery(logger zerolog.Logger) Middleware {
 
 					// Return a user-friendly error
 					err = errutil.UserFriendlyError{
-						UserMessage: "An unexpected error occurred. The issue has been logged.",
+						UserMessage: PanicMessage,
 						Err:         fmt.Errorf("panic recovered: %v", r),
 					}
 				}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"jamesbot/internal/command"
+	"jamesbot/internal/i18n"
+)
+
+// Scope creates a middleware that stops commands from running where they do
+// not apply. A command in registry implementing command.GuildOnlyCommand used
+// in direct messages, or command.DMOnlyCommand used in a server, gets an
+// ephemeral error response and the rest of the chain, including the command
+// itself, is skipped, returning ErrCommandBlocked. A nil registry lets every
+// command run.
+func Scope(registry *command.Registry) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if registry == nil {
+			return next
+		}
+		return func(ctx *command.Context) error {
+			cmd, ok := registry.Get(getCommandName(ctx))
+			if !ok {
+				return next(ctx)
+			}
+
+			inGuild := !ctx.IsDM()
+			if c, ok := cmd.(command.GuildOnlyCommand); ok && c.GuildOnly() && !inGuild {
+				return Block(ctx, ctx.T(i18n.MsgGuildOnly))
+			}
+			if c, ok := cmd.(command.DMOnlyCommand); ok && c.DMOnly() && inGuild {
+				return Block(ctx, ctx.T(i18n.MsgDMOnly))
+			}
+			return next(ctx)
+		}
+	}
+}
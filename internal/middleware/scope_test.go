@@ -0,0 +1,105 @@
+package middleware_test
+
+import (
+	"testing"
+
+	"jamesbot/internal/command"
+	"jamesbot/internal/middleware"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scopedCommand is a "testcmd" command restricted to servers or to direct messages.
+type scopedCommand struct {
+	guildOnly bool
+	dmOnly    bool
+}
+
+func (c *scopedCommand) Name() string        { return "testcmd" }
+func (c *scopedCommand) Description() string { return "Scoped command for testing" }
+func (c *scopedCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+func (c *scopedCommand) Execute(ctx *command.Context) error { return nil }
+func (c *scopedCommand) GuildOnly() bool                    { return c.guildOnly }
+func (c *scopedCommand) DMOnly() bool                       { return c.dmOnly }
+
+// ============================================================================
+// Scope Tests
+// ============================================================================
+
+func Test_Scope(t *testing.T) {
+	tests := []struct {
+		name         string
+		cmd          command.Command
+		inGuild      bool
+		wantRun      bool
+		wantResponse string
+	}{
+		{name: "guild-only command in a server", cmd: &scopedCommand{guildOnly: true}, inGuild: true, wantRun: true},
+		{name: "guild-only command in a DM", cmd: &scopedCommand{guildOnly: true}, wantResponse: "can only be used in a server"},
+		{name: "DM-only command in a DM", cmd: &scopedCommand{dmOnly: true}, wantRun: true},
+		{name: "DM-only command in a server", cmd: &scopedCommand{dmOnly: true}, inGuild: true, wantResponse: "can only be used in direct messages"},
+		{name: "unrestricted command in a DM", cmd: &scopedCommand{}, wantRun: true},
+		{name: "unrestricted command in a server", cmd: &scopedCommand{}, inGuild: true, wantRun: true},
+		{name: "built-in moderation command in a DM", cmd: &command.BanCommand{}, wantResponse: "can only be used in a server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := command.NewRegistry(discardLogger())
+			require.NoError(t, registry.Register(tt.cmd))
+			ctx, rc := createGuardTestContext(t)
+			ctx.Interaction.Data = discordgo.ApplicationCommandInteractionData{Name: tt.cmd.Name()}
+			if !tt.inGuild {
+				ctx.Interaction.GuildID = ""
+				ctx.Interaction.User = ctx.Interaction.Member.User
+				ctx.Interaction.Member = nil
+			}
+
+			ran := false
+			handler := middleware.Scope(registry)(func(ctx *command.Context) error {
+				ran = true
+				return nil
+			})
+
+			if err := handler(ctx); tt.wantRun {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, middleware.ErrCommandBlocked)
+			}
+			assert.Equal(t, tt.wantRun, ran)
+			if tt.wantResponse == "" {
+				assert.Empty(t, rc.bodies)
+				return
+			}
+			require.Len(t, rc.bodies, 1)
+			assert.Contains(t, rc.bodies[0], tt.wantResponse)
+			assert.Contains(t, rc.bodies[0], `"flags":64`, "the response should be ephemeral")
+		})
+	}
+}
+
+func Test_Scope_UnregisteredCommandRuns(t *testing.T) {
+	ran := false
+	handler := middleware.Scope(command.NewRegistry(discardLogger()))(func(ctx *command.Context) error {
+		ran = true
+		return nil
+	})
+
+	require.NoError(t, handler(createTestContext()))
+	assert.True(t, ran)
+}
+
+func Test_Scope_NilRegistry(t *testing.T) {
+	ran := false
+	handler := middleware.Scope(nil)(func(ctx *command.Context) error {
+		ran = true
+		return nil
+	})
+
+	require.NoError(t, handler(createTestContext()))
+	assert.True(t, ran)
+}
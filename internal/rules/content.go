@@ -0,0 +1,210 @@
+package rules
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"jamesbot/internal/control"
+)
+
+// Violation describes a content rule that matched a message.
+type Violation struct {
+	// Rule is the name of the matching rule.
+	Rule string
+
+	// Action is the rule's configured action: ActionDelete, ActionWarn, or ActionTimeout.
+	Action string
+
+	// Match is the blocked word or link that triggered the rule.
+	Match string
+}
+
+// linkPattern finds http(s) links and captures their host.
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://([^\s/?#<>]+)`)
+
+// CheckContent evaluates the content rules enabled in the given guild
+// (word-filter, then link-filter) against message text and returns the first
+// violation. Guild overrides take precedence over global settings. The rules
+// are read under one lock, so a concurrent SetRule cannot mix old and new
+// settings in a single evaluation.
+func (s *Set) CheckContent(guildID, content string) (Violation, bool) {
+	if s == nil || content == "" {
+		return Violation{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, name := range []string{RuleWordFilter, RuleLinkFilter} {
+		if s.value(guildID, name, KeyEnabled) != "true" {
+			continue
+		}
+		if violation, ok := s.match(guildID, name, content); ok {
+			return violation, true
+		}
+	}
+
+	return Violation{}, false
+}
+
+// TestRule runs the named content rule, as configured in guildID, against
+// input without acting on it, so a filter can be tried before it is enabled.
+// The rule is tested whether or not it is enabled. It returns an error
+// wrapping control.ErrRuleNotFound if the rule does not exist, or
+// control.ErrRuleNotTestable if it does not match message content.
+func (s *Set) TestRule(guildID, name, input string) (*control.RuleTestResult, error) {
+	if s == nil {
+		return nil, fmt.Errorf("rule set cannot be nil")
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	name = strings.TrimSpace(name)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.defs[name]; !exists {
+		return nil, fmt.Errorf("%w: %q", control.ErrRuleNotFound, name)
+	}
+	if name != RuleWordFilter && name != RuleLinkFilter {
+		return nil, fmt.Errorf("%w: %q", control.ErrRuleNotTestable, name)
+	}
+
+	result := &control.RuleTestResult{
+		Name:    name,
+		Guild:   guildID,
+		Enabled: s.value(guildID, name, KeyEnabled) == "true",
+	}
+	if violation, ok := s.match(guildID, name, input); ok {
+		result.Matched = true
+		result.Match = violation.Match
+		result.Action = violation.Action
+	}
+	return result, nil
+}
+
+// match runs the matcher of the named content rule, as configured in guildID,
+// against content. The caller must hold s.mu.
+func (s *Set) match(guildID, name, content string) (Violation, bool) {
+	var found string
+	var ok bool
+	switch name {
+	case RuleWordFilter:
+		found, ok = matchWord(content, splitList(s.value(guildID, name, KeyWords)))
+	case RuleLinkFilter:
+		found, ok = matchLink(content, splitList(s.value(guildID, name, KeyAllow)))
+	}
+	if !ok {
+		return Violation{}, false
+	}
+	return Violation{Rule: name, Action: s.value(guildID, name, KeyAction), Match: found}, true
+}
+
+// Ignored reports whether the ignore rule, as in effect in guildID, exempts a
+// message in channelID from userID, who holds roleIDs, from automated content
+// rules. It returns false if the ignore rule is disabled there. Like
+// CheckContent, it reads the rule under one lock.
+func (s *Set) Ignored(guildID, channelID, userID string, roleIDs []string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.value(guildID, RuleIgnore, KeyEnabled) != "true" {
+		return false
+	}
+	if contains(splitList(s.value(guildID, RuleIgnore, KeyIgnoreChannels)), channelID) {
+		return true
+	}
+	if contains(splitList(s.value(guildID, RuleIgnore, KeyIgnoreUsers)), userID) {
+		return true
+	}
+
+	ignoredRoles := splitList(s.value(guildID, RuleIgnore, KeyIgnoreRoles))
+	for _, role := range roleIDs {
+		if contains(ignoredRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether list holds a non-empty value.
+func contains(list []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated rule value into lowercase, trimmed,
+// non-empty entries.
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// matchWord returns the first blocked word that appears as a whole word in
+// content, ignoring case.
+func matchWord(content string, blocked []string) (string, bool) {
+	if len(blocked) == 0 {
+		return "", false
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, w := range words {
+		for _, b := range blocked {
+			if w == b {
+				return b, true
+			}
+		}
+	}
+	return "", false
+}
+
+// matchLink returns the first link in content whose host is not an allowed
+// domain or a subdomain of one.
+func matchLink(content string, allowed []string) (string, bool) {
+	for _, m := range linkPattern.FindAllStringSubmatch(content, -1) {
+		host := strings.ToLower(m[1])
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if !domainAllowed(host, allowed) {
+			return m[0], true
+		}
+	}
+	return "", false
+}
+
+// domainAllowed reports whether host is one of the allowed domains or a subdomain of one.
+func domainAllowed(host string, allowed []string) bool {
+	for _, d := range allowed {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package rules
+
+import "time"
+
+// Actions a content rule can take when it matches a message.
+const (
+	ActionDelete  = "delete"
+	ActionWarn    = "warn"
+	ActionTimeout = "timeout"
+)
+
+// The anti-spam rule and its keys.
+const (
+	RuleAntiSpam = "anti-spam"
+	KeyThreshold = "threshold"
+	KeyWindow    = "window"
+)
+
+// The content rules and their keys.
+const (
+	RuleWordFilter = "word-filter"
+	RuleLinkFilter = "link-filter"
+	KeyWords       = "words"
+	KeyAllow       = "allow"
+	KeyAction      = "action"
+)
+
+// The ignore rule and its keys.
+const (
+	RuleIgnore        = "ignore"
+	KeyIgnoreRoles    = "roles"
+	KeyIgnoreChannels = "channels"
+	KeyIgnoreUsers    = "users"
+)
+
+// The warn-escalation rule and its keys.
+const (
+	RuleWarnEscalation   = "warn-escalation"
+	KeyEscalationPolicy  = "policy"
+	KeyEscalationMuteFor = "mute_duration"
+)
+
+// The welcome and goodbye rules and their keys.
+const (
+	RuleWelcome    = "welcome"
+	RuleGoodbye    = "goodbye"
+	KeyChannel     = "channel"
+	KeyMessage     = "message"
+	KeyWelcomeRole = "role"
+)
+
+// The settings group and its keys.
+const (
+	RuleSettings     = "settings"
+	KeyPrefix        = "prefix"
+	KeyModLogChannel = "modlog_channel"
+	KeyLocale        = "locale"
+)
+
+// maxTimeoutDuration is the longest member timeout Discord allows.
+const maxTimeoutDuration = 28 * 24 * time.Hour
+
+// Defaults returns the built-in rule definitions.
+func Defaults() []Definition {
+	return []Definition{
+		{
+			Name:        RuleAntiSpam,
+			Description: "Limits how many messages a user can send in a short window",
+			Keys: []Key{
+				{Name: KeyThreshold, Description: "Messages allowed per window", Default: "5", Validate: PositiveInt},
+				{Name: KeyWindow, Description: "Length of the rate window", Default: "10s", Validate: PositiveDuration},
+			},
+		},
+		{
+			Name:        RuleWordFilter,
+			Description: "Removes messages containing blocked words",
+			Keys: []Key{
+				{Name: KeyWords, Description: "Comma-separated list of blocked words", Default: ""},
+				{Name: KeyAction, Description: "Action taken on a match", Default: ActionDelete, Validate: OneOf(ActionDelete, ActionWarn, ActionTimeout)},
+			},
+		},
+		{
+			Name:        RuleLinkFilter,
+			Description: "Removes messages containing links",
+			Keys: []Key{
+				{Name: KeyAllow, Description: "Comma-separated list of allowed domains", Default: ""},
+				{Name: KeyAction, Description: "Action taken on a match", Default: ActionDelete, Validate: OneOf(ActionDelete, ActionWarn, ActionTimeout)},
+			},
+		},
+		{
+			Name:        RuleIgnore,
+			Description: "Exempts roles, channels, and users from automated content rules",
+			Keys: []Key{
+				{Name: KeyIgnoreRoles, Description: "Comma-separated role IDs whose members are exempt", Default: "", Validate: IDList},
+				{Name: KeyIgnoreChannels, Description: "Comma-separated channel IDs that are not moderated", Default: "", Validate: IDList},
+				{Name: KeyIgnoreUsers, Description: "Comma-separated user IDs that are exempt", Default: "", Validate: IDList},
+			},
+		},
+		{
+			Name:        RuleWarnEscalation,
+			Description: "Punishes members automatically as their warnings accumulate",
+			Keys: []Key{
+				{Name: KeyEscalationPolicy, Description: "Warning counts and actions, e.g. 3=mute,5=ban", Default: "3=mute,5=kick", Validate: EscalationPolicy},
+				{Name: KeyEscalationMuteFor, Description: "Timeout length for the mute action", Default: "1h", Validate: DurationBetween(time.Minute, maxTimeoutDuration)},
+			},
+		},
+		{
+			Name:        RuleWelcome,
+			Description: "Greets members who join and optionally gives them a role",
+			Keys: []Key{
+				{Name: KeyChannel, Description: "ID of the channel welcome messages are posted in", Default: "", Validate: OptionalID},
+				{Name: KeyMessage, Description: "Welcome message; {user}, {server}, and {count} are replaced", Default: "Welcome to {server}, {user}! You are member #{count}.", Validate: NonEmpty},
+				{Name: KeyWelcomeRole, Description: "ID of a role given to new members, if any", Default: "", Validate: OptionalID},
+			},
+		},
+		{
+			Name:        RuleGoodbye,
+			Description: "Posts a farewell when a member leaves",
+			Keys: []Key{
+				{Name: KeyChannel, Description: "ID of the channel farewells are posted in", Default: "", Validate: OptionalID},
+				{Name: KeyMessage, Description: "Farewell message; {user}, {server}, and {count} are replaced", Default: "{user} has left {server}. We now have {count} members.", Validate: NonEmpty},
+			},
+		},
+		{
+			Name:        RuleSettings,
+			Description: "Per-server settings, such as the text command prefix",
+			Settings:    true,
+			Keys: []Key{
+				{Name: KeyPrefix, Description: "Prefix of text commands, such as !; empty disables them", Default: "", Validate: Prefix},
+				{Name: KeyModLogChannel, Description: "ID of the channel moderation actions are logged in, if any", Default: "", Validate: OptionalID},
+				{Name: KeyLocale, Description: "Language of responses, such as de, in place of the server's", Default: "", Validate: Locale},
+			},
+		},
+	}
+}
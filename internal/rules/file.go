@@ -0,0 +1,107 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+
+	"jamesbot/pkg/fileutil"
+)
+
+// savedValue is a rule setting as kept in a rules file, in the format
+// 'jamesbot rules import' reads.
+type savedValue struct {
+	Name  string `json:"name"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Guild string `json:"guild,omitempty"`
+}
+
+// Load applies the settings saved at path by Save, validated as by
+// SetGuildRule. A file that does not exist yet holds no settings. Settings
+// before an invalid one are applied.
+func (s *Set) Load(path string) error {
+	if s == nil {
+		return fmt.Errorf("rule set cannot be nil")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var saved []savedValue
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+	for _, v := range saved {
+		if err := s.SetGuildRule(v.Guild, v.Name, v.Key, v.Value); err != nil {
+			return fmt.Errorf("rules file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Save writes the global settings that differ from their defaults and every
+// guild override to path, for Load to restore. The file is replaced
+// atomically, so a crash while saving leaves the previous settings intact.
+// It is safe to call concurrently with changes and other saves.
+func (s *Set) Save(path string) error {
+	if s == nil {
+		return fmt.Errorf("rule set cannot be nil")
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	data, err := json.MarshalIndent(s.saved(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode rules: %w", err)
+	}
+
+	if err := fileutil.WriteAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to save rules: %w", err)
+	}
+	return nil
+}
+
+// saved returns the settings Save writes, global ones first, in definition
+// then key order, followed by guild overrides in guild ID order.
+func (s *Set) saved() []savedValue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	guildIDs := make([]string, 0, len(s.guilds))
+	for guildID := range s.guilds {
+		guildIDs = append(guildIDs, guildID)
+	}
+	sort.Strings(guildIDs)
+
+	saved := []savedValue{}
+	for _, guildID := range append([]string{""}, guildIDs...) {
+		for _, name := range s.order {
+			def := s.defs[name]
+			for _, key := range def.keyNames() {
+				var value string
+				var ok bool
+				if guildID == "" {
+					value = s.values[name][key]
+					spec, _ := def.key(key)
+					ok = value != spec.Default
+				} else {
+					value, ok = s.guilds[guildID][name][key]
+				}
+				if ok {
+					saved = append(saved, savedValue{Name: name, Key: key, Value: value, Guild: guildID})
+				}
+			}
+		}
+	}
+	return saved
+}
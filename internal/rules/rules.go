@@ -0,0 +1,401 @@
+// Package rules provides the moderation rule set managed through the control API.
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"jamesbot/internal/control"
+)
+
+// KeyEnabled is the key every rule accepts to toggle it on or off.
+const KeyEnabled = "enabled"
+
+// Key describes a single configurable setting on a rule.
+type Key struct {
+	// Name is the key as passed to SetRule.
+	Name string
+
+	// Description explains what the key controls.
+	Description string
+
+	// Default is the value used until the key is explicitly set.
+	Default string
+
+	// Validate checks a candidate value. A nil Validate accepts any value.
+	Validate Validator
+}
+
+// Definition describes a rule and the keys it accepts.
+// Every rule implicitly accepts the "enabled" key in addition to Keys.
+type Definition struct {
+	Name        string
+	Description string
+	Keys        []Key
+
+	// Settings marks a group of settings rather than a rule that can be
+	// turned off: it has no "enabled" key and is always in effect.
+	Settings bool
+}
+
+// key returns the key spec with the given name, including the implicit enabled key.
+func (d Definition) key(name string) (Key, bool) {
+	if name == KeyEnabled && !d.Settings {
+		return Key{Name: KeyEnabled, Description: "Whether the rule is active", Default: "false", Validate: Bool}, true
+	}
+	for _, k := range d.Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// keyNames returns the sorted names of all keys the rule accepts.
+func (d Definition) keyNames() []string {
+	names := make([]string, 0, len(d.Keys)+1)
+	if !d.Settings {
+		names = append(names, KeyEnabled)
+	}
+	for _, k := range d.Keys {
+		names = append(names, k.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Set holds rule definitions and their current values.
+// Values are global unless overridden for a guild: a guild override replaces
+// the global value of a single key, and keys it does not override fall back
+// to the global value.
+// It is safe for concurrent use.
+type Set struct {
+	mu     sync.RWMutex
+	defs   map[string]Definition
+	order  []string // Maintains definition order
+	values map[string]map[string]string
+
+	// guilds holds per-guild overrides: guild ID, then rule name, then key.
+	guilds map[string]map[string]map[string]string
+
+	// saveMu serializes Save, so the last file written holds the latest values.
+	saveMu sync.Mutex
+}
+
+// NewSet creates a rule set from the given definitions with every key at its default.
+// Definitions with duplicate names are ignored after the first.
+func NewSet(defs ...Definition) *Set {
+	s := &Set{
+		defs:   make(map[string]Definition, len(defs)),
+		order:  make([]string, 0, len(defs)),
+		values: make(map[string]map[string]string, len(defs)),
+		guilds: make(map[string]map[string]map[string]string),
+	}
+
+	for _, def := range defs {
+		if _, exists := s.defs[def.Name]; exists {
+			continue
+		}
+		s.defs[def.Name] = def
+		s.order = append(s.order, def.Name)
+
+		values := make(map[string]string, len(def.Keys)+1)
+		if !def.Settings {
+			values[KeyEnabled] = "false"
+		}
+		for _, k := range def.Keys {
+			values[k.Name] = k.Default
+		}
+		s.values[def.Name] = values
+	}
+
+	return s
+}
+
+// Clone returns a copy of s whose values can be changed without affecting s,
+// such as to see what a change would do before making it.
+func (s *Set) Clone() *Set {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c := &Set{
+		defs:   maps.Clone(s.defs),
+		order:  slices.Clone(s.order),
+		values: make(map[string]map[string]string, len(s.values)),
+		guilds: make(map[string]map[string]map[string]string, len(s.guilds)),
+	}
+	for name, values := range s.values {
+		c.values[name] = maps.Clone(values)
+	}
+	for guildID, overrides := range s.guilds {
+		copied := make(map[string]map[string]string, len(overrides))
+		for name, values := range overrides {
+			copied[name] = maps.Clone(values)
+		}
+		c.guilds[guildID] = copied
+	}
+	return c
+}
+
+// SetRule validates and stores the global value for a rule key.
+// Surrounding whitespace is trimmed from name and key before lookup.
+// It returns an error wrapping control.ErrRuleNotFound if the rule does not exist,
+// or control.ErrInvalidRule if name or key is blank, the key is not accepted,
+// or the value fails validation.
+func (s *Set) SetRule(name, key, value string) error {
+	return s.SetGuildRule("", name, key, value)
+}
+
+// SetGuildRule validates and stores a value for a rule key that applies only
+// in the given guild, overriding the global value there. An empty guildID
+// sets the global value, like SetRule. Errors are as for SetRule.
+func (s *Set) SetGuildRule(guildID, name, key, value string) error {
+	if s == nil {
+		return fmt.Errorf("rule set cannot be nil")
+	}
+
+	guildID = strings.TrimSpace(guildID)
+	name = strings.TrimSpace(name)
+	key = strings.TrimSpace(key)
+	if name == "" || key == "" {
+		return fmt.Errorf("%w: rule name and key are required", control.ErrInvalidRule)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	def, exists := s.defs[name]
+	if !exists {
+		return fmt.Errorf("%w: %q", control.ErrRuleNotFound, name)
+	}
+
+	spec, ok := def.key(key)
+	if !ok {
+		return fmt.Errorf("%w: rule %q does not accept key %q (valid keys: %s)",
+			control.ErrInvalidRule, name, key, strings.Join(def.keyNames(), ", "))
+	}
+
+	if spec.Validate != nil {
+		if err := spec.Validate(value); err != nil {
+			return fmt.Errorf("%w: %s.%s: %v", control.ErrInvalidRule, name, key, err)
+		}
+	}
+
+	if guildID == "" {
+		s.values[name][key] = value
+		return nil
+	}
+
+	overrides, ok := s.guilds[guildID]
+	if !ok {
+		overrides = make(map[string]map[string]string)
+		s.guilds[guildID] = overrides
+	}
+	if overrides[name] == nil {
+		overrides[name] = make(map[string]string)
+	}
+	overrides[name][key] = value
+	return nil
+}
+
+// SetDefault validates and makes value the default of a rule key, such as
+// one taken from the config file, in place of the definition's. The global
+// value follows it unless it was already set to something else. Values equal
+// to their default are not saved by Save.
+func (s *Set) SetDefault(name, key, value string) error {
+	if s == nil {
+		return fmt.Errorf("rule set cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	def, exists := s.defs[name]
+	if !exists {
+		return fmt.Errorf("%w: %q", control.ErrRuleNotFound, name)
+	}
+	for i, k := range def.Keys {
+		if k.Name != key {
+			continue
+		}
+		if k.Validate != nil {
+			if err := k.Validate(value); err != nil {
+				return fmt.Errorf("%w: %s.%s: %v", control.ErrInvalidRule, name, key, err)
+			}
+		}
+		if s.values[name][key] == k.Default {
+			s.values[name][key] = value
+		}
+		// Copy the keys so definitions shared with other sets are untouched
+		keys := append([]Key(nil), def.Keys...)
+		keys[i].Default = value
+		def.Keys = keys
+		s.defs[name] = def
+		return nil
+	}
+	return fmt.Errorf("%w: rule %q has no key %q with a default", control.ErrInvalidRule, name, key)
+}
+
+// Get returns the current global value of a rule key.
+// It returns false if the rule or key does not exist.
+func (s *Set) Get(name, key string) (string, bool) {
+	return s.GuildValue("", name, key)
+}
+
+// GuildValue returns the value of a rule key in effect in the given guild:
+// the guild's override if it has one, otherwise the global value.
+// It returns false if the rule or key does not exist.
+func (s *Set) GuildValue(guildID, name, key string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, _, ok := s.lookup(guildID, name, key)
+	return value, ok
+}
+
+// lookup returns the value of a rule key in effect in guildID and whether it
+// comes from a guild override. The caller must hold s.mu.
+func (s *Set) lookup(guildID, name, key string) (value string, overridden, ok bool) {
+	if guildID != "" {
+		if value, ok := s.guilds[guildID][name][key]; ok {
+			return value, true, true
+		}
+	}
+
+	values, exists := s.values[name]
+	if !exists {
+		return "", false, false
+	}
+	value, ok = values[key]
+	return value, false, ok
+}
+
+// value returns the value of a rule key in effect in guildID, or "" if the
+// rule or key does not exist. The caller must hold s.mu.
+func (s *Set) value(guildID, name, key string) string {
+	value, _, _ := s.lookup(guildID, name, key)
+	return value
+}
+
+// Enabled reports whether the named rule exists and is enabled globally.
+func (s *Set) Enabled(name string) bool {
+	return s.GuildEnabled("", name)
+}
+
+// GuildEnabled reports whether the named rule exists and is enabled in the
+// given guild, taking the guild's override of "enabled" into account.
+func (s *Set) GuildEnabled(guildID, name string) bool {
+	value, _ := s.GuildValue(guildID, name, KeyEnabled)
+	return value == "true"
+}
+
+// EnabledAnywhere reports whether the named rule is enabled globally or in at
+// least one guild.
+func (s *Set) EnabledAnywhere(name string) bool {
+	return s.anyValue(name, KeyEnabled, func(value string) bool { return value == "true" })
+}
+
+// SetAnywhere reports whether a rule key has a non-empty value globally or in
+// at least one guild.
+func (s *Set) SetAnywhere(name, key string) bool {
+	return s.anyValue(name, key, func(value string) bool { return value != "" })
+}
+
+// anyValue reports whether match accepts the global value of a rule key or
+// any guild's override of it.
+func (s *Set) anyValue(name, key string, match func(string) bool) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if value, ok := s.values[name][key]; ok && match(value) {
+		return true
+	}
+	for _, overrides := range s.guilds {
+		if value, ok := overrides[name][key]; ok && match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveCount returns the number of globally enabled rules.
+func (s *Set) ActiveCount() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, values := range s.values {
+		if values[KeyEnabled] == "true" {
+			count++
+		}
+	}
+	return count
+}
+
+// Rules returns the global rule settings as control API rules.
+// Each rule contributes one entry per key (other than "enabled", which is
+// reported through the Enabled field), ordered by definition then key name.
+// A rule with no keys besides "enabled" contributes a single "enabled" entry.
+func (s *Set) Rules() []control.Rule {
+	return s.GuildRules("")
+}
+
+// GuildRules returns the rule settings in effect in the given guild, laid out
+// as in Rules. An entry's Guild is guildID when its value is the guild's
+// override and empty when it is inherited from the global setting.
+func (s *Set) GuildRules(guildID string) []control.Rule {
+	if s == nil {
+		return []control.Rule{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]control.Rule, 0, len(s.order))
+	for _, name := range s.order {
+		def := s.defs[name]
+		enabled, _, _ := s.lookup(guildID, name, KeyEnabled)
+
+		keys := make([]string, 0, len(def.Keys))
+		for _, k := range def.Keys {
+			keys = append(keys, k.Name)
+		}
+		sort.Strings(keys)
+		if len(keys) == 0 {
+			keys = []string{KeyEnabled}
+		}
+
+		for _, key := range keys {
+			value, overridden, _ := s.lookup(guildID, name, key)
+			rule := control.Rule{
+				Name:        name,
+				Description: def.Description,
+				Enabled:     def.Settings || enabled == "true",
+				Key:         key,
+				Value:       value,
+			}
+			if overridden {
+				rule.Guild = guildID
+			}
+			result = append(result, rule)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,864 @@
+package rules_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"jamesbot/internal/control"
+	"jamesbot/internal/rules"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// SetRule Validation Tests
+// =============================================================================
+
+func Test_Set_SetRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		key       string
+		value     string
+		wantErrIs error
+	}{
+		{name: "valid threshold", rule: "anti-spam", key: "threshold", value: "10"},
+		{name: "valid enabled true", rule: "anti-spam", key: "enabled", value: "true"},
+		{name: "valid enabled false", rule: "link-filter", key: "enabled", value: "false"},
+		{name: "valid window duration", rule: "anti-spam", key: "window", value: "30s"},
+		{name: "valid action", rule: "word-filter", key: "action", value: "timeout"},
+		{name: "free-form words", rule: "word-filter", key: "words", value: "foo,bar"},
+		{name: "empty free-form value", rule: "word-filter", key: "words", value: ""},
+		{name: "threshold not a number", rule: "anti-spam", key: "threshold", value: "notanumber", wantErrIs: control.ErrInvalidRule},
+		{name: "threshold zero", rule: "anti-spam", key: "threshold", value: "0", wantErrIs: control.ErrInvalidRule},
+		{name: "threshold negative", rule: "anti-spam", key: "threshold", value: "-3", wantErrIs: control.ErrInvalidRule},
+		{name: "enabled not boolean", rule: "anti-spam", key: "enabled", value: "yes", wantErrIs: control.ErrInvalidRule},
+		{name: "window not a duration", rule: "anti-spam", key: "window", value: "soon", wantErrIs: control.ErrInvalidRule},
+		{name: "action not allowed", rule: "link-filter", key: "action", value: "explode", wantErrIs: control.ErrInvalidRule},
+		{name: "valid escalation policy", rule: "warn-escalation", key: "policy", value: "3=mute,5=ban"},
+		{name: "valid escalation mute duration", rule: "warn-escalation", key: "mute_duration", value: "30m"},
+		{name: "escalation policy bad action", rule: "warn-escalation", key: "policy", value: "3=explode", wantErrIs: control.ErrInvalidRule},
+		{name: "escalation mute longer than 28 days", rule: "warn-escalation", key: "mute_duration", value: "700h", wantErrIs: control.ErrInvalidRule},
+		{name: "valid welcome channel", rule: "welcome", key: "channel", value: "123456789012345678"},
+		{name: "welcome role cleared", rule: "welcome", key: "role", value: ""},
+		{name: "welcome channel not an ID", rule: "welcome", key: "channel", value: "#general", wantErrIs: control.ErrInvalidRule},
+		{name: "welcome role list", rule: "welcome", key: "role", value: "1,2", wantErrIs: control.ErrInvalidRule},
+		{name: "welcome message blank", rule: "welcome", key: "message", value: "  ", wantErrIs: control.ErrInvalidRule},
+		{name: "valid prefix", rule: "settings", key: "prefix", value: "?"},
+		{name: "prefix cleared", rule: "settings", key: "prefix", value: ""},
+		{name: "prefix with a space", rule: "settings", key: "prefix", value: "hey bot", wantErrIs: control.ErrInvalidRule},
+		{name: "valid locale", rule: "settings", key: "locale", value: "de"},
+		{name: "unknown locale", rule: "settings", key: "locale", value: "klingon", wantErrIs: control.ErrInvalidRule},
+		{name: "mod-log channel not an ID", rule: "settings", key: "modlog_channel", value: "#mod-log", wantErrIs: control.ErrInvalidRule},
+		{name: "settings cannot be disabled", rule: "settings", key: "enabled", value: "false", wantErrIs: control.ErrInvalidRule},
+		{name: "unknown key", rule: "anti-spam", key: "colour", value: "red", wantErrIs: control.ErrInvalidRule},
+		{name: "unknown rule", rule: "nonexistent", key: "enabled", value: "true", wantErrIs: control.ErrRuleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rules.NewSet(rules.Defaults()...)
+
+			err := set.SetRule(tt.rule, tt.key, tt.value)
+
+			if tt.wantErrIs != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErrIs), "error should wrap %v, got %v", tt.wantErrIs, err)
+				return
+			}
+
+			require.NoError(t, err)
+			got, ok := set.Get(tt.rule, tt.key)
+			assert.True(t, ok)
+			assert.Equal(t, tt.value, got, "value should be stored")
+		})
+	}
+}
+
+func Test_Set_SetRule_ErrorIsDescriptive(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	err := set.SetRule("anti-spam", "threshold", "notanumber")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anti-spam.threshold")
+	assert.Contains(t, err.Error(), "positive integer")
+
+	err = set.SetRule("anti-spam", "colour", "red")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "valid keys")
+	assert.Contains(t, err.Error(), "threshold")
+}
+
+func Test_Set_SetRule_InvalidValueNotStored(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	before, _ := set.Get("anti-spam", "threshold")
+	_ = set.SetRule("anti-spam", "threshold", "notanumber")
+	after, _ := set.Get("anti-spam", "threshold")
+
+	assert.Equal(t, before, after, "invalid value should not replace the current value")
+}
+
+func Test_Set_SetRule_TrimsWhitespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		key       string
+		wantErrIs error
+	}{
+		{name: "padded name and key", rule: "  anti-spam ", key: " threshold\t"},
+		{name: "whitespace-only name", rule: "   ", key: "threshold", wantErrIs: control.ErrInvalidRule},
+		{name: "whitespace-only key", rule: "anti-spam", key: " \n ", wantErrIs: control.ErrInvalidRule},
+		{name: "empty name", rule: "", key: "threshold", wantErrIs: control.ErrInvalidRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rules.NewSet(rules.Defaults()...)
+
+			err := set.SetRule(tt.rule, tt.key, "7")
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			require.NoError(t, err)
+			got, _ := set.Get("anti-spam", "threshold")
+			assert.Equal(t, "7", got)
+		})
+	}
+}
+
+func Test_Set_SetRule_NilSet(t *testing.T) {
+	var set *rules.Set
+
+	assert.Error(t, set.SetRule("anti-spam", "enabled", "true"))
+}
+
+// =============================================================================
+// Read Tests
+// =============================================================================
+
+func Test_Set_Enabled_AndActiveCount(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	assert.False(t, set.Enabled("anti-spam"), "rules should start disabled")
+	assert.Equal(t, 0, set.ActiveCount())
+
+	require.NoError(t, set.SetRule("anti-spam", "enabled", "true"))
+	require.NoError(t, set.SetRule("link-filter", "enabled", "true"))
+
+	assert.True(t, set.Enabled("anti-spam"))
+	assert.Equal(t, 2, set.ActiveCount())
+	assert.False(t, set.Enabled("nonexistent"))
+}
+
+func Test_Set_Rules(t *testing.T) {
+	set := rules.NewSet(
+		rules.Definition{
+			Name:        "a-rule",
+			Description: "First",
+			Keys: []rules.Key{
+				{Name: "zeta", Default: "z"},
+				{Name: "alpha", Default: "a"},
+			},
+		},
+		rules.Definition{Name: "bare-rule", Description: "No keys"},
+	)
+	require.NoError(t, set.SetRule("a-rule", "enabled", "true"))
+
+	got := set.Rules()
+
+	want := []control.Rule{
+		{Name: "a-rule", Description: "First", Enabled: true, Key: "alpha", Value: "a"},
+		{Name: "a-rule", Description: "First", Enabled: true, Key: "zeta", Value: "z"},
+		{Name: "bare-rule", Description: "No keys", Enabled: false, Key: "enabled", Value: "false"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_Set_Rules_Empty(t *testing.T) {
+	set := rules.NewSet()
+
+	got := set.Rules()
+
+	assert.NotNil(t, got, "Rules should return empty slice, not nil")
+	assert.Empty(t, got)
+}
+
+func Test_NewSet_DuplicateDefinitions(t *testing.T) {
+	set := rules.NewSet(
+		rules.Definition{Name: "dup", Description: "first"},
+		rules.Definition{Name: "dup", Description: "second"},
+	)
+
+	got := set.Rules()
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "first", got[0].Description)
+}
+
+// =============================================================================
+// Content Rule Tests
+// =============================================================================
+
+func Test_Set_CheckContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings [][3]string
+		content  string
+		want     rules.Violation
+		wantHit  bool
+	}{
+		{
+			name:     "disabled rules never match",
+			settings: [][3]string{{"word-filter", "words", "spam"}},
+			content:  "buy spam now",
+		},
+		{
+			name:     "blocked word matches case-insensitively",
+			settings: [][3]string{{"word-filter", "enabled", "true"}, {"word-filter", "words", "Spam, eggs"}},
+			content:  "Buy SPAM now!",
+			want:     rules.Violation{Rule: "word-filter", Action: "delete", Match: "spam"},
+			wantHit:  true,
+		},
+		{
+			name:     "blocked word must be a whole word",
+			settings: [][3]string{{"word-filter", "enabled", "true"}, {"word-filter", "words", "ass"}},
+			content:  "a classic assignment",
+		},
+		{
+			name:     "link matches with configured action",
+			settings: [][3]string{{"link-filter", "enabled", "true"}, {"link-filter", "action", "timeout"}},
+			content:  "see https://evil.example/free",
+			want:     rules.Violation{Rule: "link-filter", Action: "timeout", Match: "https://evil.example"},
+			wantHit:  true,
+		},
+		{
+			name:     "allowed domains and their subdomains pass",
+			settings: [][3]string{{"link-filter", "enabled", "true"}, {"link-filter", "allow", "github.com, youtube.com"}},
+			content:  "https://github.com/x and http://www.youtube.com:443/watch",
+		},
+		{
+			name:     "lookalike domain is not allowed",
+			settings: [][3]string{{"link-filter", "enabled", "true"}, {"link-filter", "allow", "github.com"}},
+			content:  "https://notgithub.com/x",
+			want:     rules.Violation{Rule: "link-filter", Action: "delete", Match: "https://notgithub.com"},
+			wantHit:  true,
+		},
+		{
+			name: "word filter is checked before link filter",
+			settings: [][3]string{
+				{"word-filter", "enabled", "true"}, {"word-filter", "words", "spam"}, {"word-filter", "action", "warn"},
+				{"link-filter", "enabled", "true"},
+			},
+			content: "spam at https://evil.example",
+			want:    rules.Violation{Rule: "word-filter", Action: "warn", Match: "spam"},
+			wantHit: true,
+		},
+		{
+			name:     "empty content",
+			settings: [][3]string{{"link-filter", "enabled", "true"}},
+			content:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rules.NewSet(rules.Defaults()...)
+			for _, s := range tt.settings {
+				require.NoError(t, set.SetRule(s[0], s[1], s[2]))
+			}
+
+			got, hit := set.CheckContent("guild-1", tt.content)
+
+			assert.Equal(t, tt.wantHit, hit)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Set_CheckContent_NilSet(t *testing.T) {
+	var set *rules.Set
+
+	_, hit := set.CheckContent("guild-1", "https://evil.example")
+	assert.False(t, hit)
+}
+
+func Test_Set_Ignored(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  [][3]string
+		channelID string
+		userID    string
+		roles     []string
+		want      bool
+	}{
+		{
+			name:      "disabled ignore rule exempts nothing",
+			settings:  [][3]string{{"ignore", "channels", "100"}},
+			channelID: "100",
+		},
+		{
+			name:      "ignored channel",
+			settings:  [][3]string{{"ignore", "enabled", "true"}, {"ignore", "channels", "100, 101"}},
+			channelID: "101",
+			userID:    "1",
+			want:      true,
+		},
+		{
+			name:      "ignored user",
+			settings:  [][3]string{{"ignore", "enabled", "true"}, {"ignore", "users", "7"}},
+			channelID: "100",
+			userID:    "7",
+			want:      true,
+		},
+		{
+			name:      "member holding an ignored role",
+			settings:  [][3]string{{"ignore", "enabled", "true"}, {"ignore", "roles", "55"}},
+			channelID: "100",
+			userID:    "7",
+			roles:     []string{"54", "55"},
+			want:      true,
+		},
+		{
+			name:      "nothing matches",
+			settings:  [][3]string{{"ignore", "enabled", "true"}, {"ignore", "roles", "55"}, {"ignore", "users", "8"}},
+			channelID: "100",
+			userID:    "7",
+			roles:     []string{"54"},
+		},
+		{
+			name:     "empty ids never match empty lists",
+			settings: [][3]string{{"ignore", "enabled", "true"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rules.NewSet(rules.Defaults()...)
+			for _, s := range tt.settings {
+				require.NoError(t, set.SetRule(s[0], s[1], s[2]))
+			}
+
+			assert.Equal(t, tt.want, set.Ignored("guild-1", tt.channelID, tt.userID, tt.roles))
+		})
+	}
+}
+
+func Test_Set_Ignored_RejectsNonIDs(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	err := set.SetRule("ignore", "roles", "123, moderators")
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, control.ErrInvalidRule))
+}
+
+// =============================================================================
+// Guild Scope Tests
+// =============================================================================
+
+func Test_Set_GuildOverrides(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule("anti-spam", "threshold", "7"))
+	require.NoError(t, set.SetGuildRule("guild-1", "anti-spam", "threshold", "3"))
+	require.NoError(t, set.SetGuildRule("guild-1", "anti-spam", "enabled", "true"))
+
+	tests := []struct {
+		name        string
+		guild       string
+		wantValue   string
+		wantEnabled bool
+	}{
+		{name: "global value", guild: "", wantValue: "7"},
+		{name: "guild override wins", guild: "guild-1", wantValue: "3", wantEnabled: true},
+		{name: "other guild inherits global", guild: "guild-2", wantValue: "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := set.GuildValue(tt.guild, "anti-spam", "threshold")
+			require.True(t, ok)
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantEnabled, set.GuildEnabled(tt.guild, "anti-spam"))
+		})
+	}
+
+	assert.Equal(t, 0, set.ActiveCount(), "guild overrides do not count as globally active")
+}
+
+func Test_Set_Anywhere(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(*rules.Set) error
+		wantEnabled bool
+		wantSet     bool
+	}{
+		{
+			name:  "defaults",
+			setup: func(*rules.Set) error { return nil },
+		},
+		{
+			name: "global values",
+			setup: func(s *rules.Set) error {
+				if err := s.SetRule(rules.RuleWelcome, rules.KeyEnabled, "true"); err != nil {
+					return err
+				}
+				return s.SetRule(rules.RuleWelcome, rules.KeyChannel, "123456789012345678")
+			},
+			wantEnabled: true,
+			wantSet:     true,
+		},
+		{
+			name: "one guild's overrides",
+			setup: func(s *rules.Set) error {
+				if err := s.SetGuildRule("guild-1", rules.RuleWelcome, rules.KeyEnabled, "true"); err != nil {
+					return err
+				}
+				return s.SetGuildRule("guild-1", rules.RuleWelcome, rules.KeyChannel, "123456789012345678")
+			},
+			wantEnabled: true,
+			wantSet:     true,
+		},
+		{
+			name: "guild turns a global rule off",
+			setup: func(s *rules.Set) error {
+				if err := s.SetRule(rules.RuleWelcome, rules.KeyEnabled, "true"); err != nil {
+					return err
+				}
+				return s.SetGuildRule("guild-1", rules.RuleWelcome, rules.KeyEnabled, "false")
+			},
+			wantEnabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rules.NewSet(rules.Defaults()...)
+			require.NoError(t, tt.setup(set))
+
+			assert.Equal(t, tt.wantEnabled, set.EnabledAnywhere(rules.RuleWelcome))
+			assert.Equal(t, tt.wantSet, set.SetAnywhere(rules.RuleWelcome, rules.KeyChannel))
+			assert.False(t, set.EnabledAnywhere("nonexistent"))
+		})
+	}
+
+	var nilSet *rules.Set
+	assert.False(t, nilSet.EnabledAnywhere(rules.RuleWelcome))
+	assert.False(t, nilSet.SetAnywhere(rules.RuleWelcome, rules.KeyChannel))
+}
+
+func Test_Set_SpamLimit(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	_, _, ok := set.SpamLimit("guild-1")
+	assert.False(t, ok, "anti-spam is disabled by default")
+
+	require.NoError(t, set.SetRule(rules.RuleAntiSpam, rules.KeyEnabled, "true"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleAntiSpam, rules.KeyThreshold, "3"))
+
+	threshold, window, ok := set.SpamLimit("guild-1")
+	require.True(t, ok)
+	assert.Equal(t, 3, threshold, "the guild's override should apply")
+	assert.Equal(t, 10*time.Second, window, "keys the guild did not override fall back to the global value")
+
+	threshold, _, ok = set.SpamLimit("guild-2")
+	require.True(t, ok)
+	assert.Equal(t, 5, threshold)
+
+	require.NoError(t, set.SetGuildRule("guild-2", rules.RuleAntiSpam, rules.KeyEnabled, "false"))
+	_, _, ok = set.SpamLimit("guild-2")
+	assert.False(t, ok)
+}
+
+func Test_Set_Clone(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule("anti-spam", "threshold", "7"))
+	require.NoError(t, set.SetGuildRule("guild-1", "anti-spam", "enabled", "true"))
+
+	clone := set.Clone()
+	assert.Equal(t, set.Rules(), clone.Rules())
+	assert.Equal(t, set.GuildRules("guild-1"), clone.GuildRules("guild-1"))
+
+	require.NoError(t, clone.SetRule("anti-spam", "threshold", "9"))
+	require.NoError(t, clone.SetGuildRule("guild-1", "anti-spam", "enabled", "false"))
+	require.NoError(t, clone.SetGuildRule("guild-2", "word-filter", "enabled", "true"))
+
+	value, _ := set.Get("anti-spam", "threshold")
+	assert.Equal(t, "7", value, "changing the clone should leave the original alone")
+	assert.True(t, set.GuildEnabled("guild-1", "anti-spam"))
+	assert.False(t, set.EnabledAnywhere("word-filter"))
+}
+
+func Test_Set_SetGuildRule_Validates(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	assert.ErrorIs(t, set.SetGuildRule("guild-1", "anti-spam", "threshold", "lots"), control.ErrInvalidRule)
+	assert.ErrorIs(t, set.SetGuildRule("guild-1", "nonexistent", "enabled", "true"), control.ErrRuleNotFound)
+
+	value, _ := set.GuildValue("guild-1", "anti-spam", "threshold")
+	assert.Equal(t, "5", value, "rejected overrides are not stored")
+}
+
+func Test_Set_GuildRules(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetGuildRule("guild-1", "anti-spam", "threshold", "3"))
+
+	for _, r := range set.Rules() {
+		assert.Empty(t, r.Guild, "global rules are never marked with a guild")
+	}
+
+	var overridden, inherited int
+	for _, r := range set.GuildRules("guild-1") {
+		if r.Name == "anti-spam" && r.Key == "threshold" {
+			assert.Equal(t, "guild-1", r.Guild)
+			assert.Equal(t, "3", r.Value)
+			overridden++
+			continue
+		}
+		assert.Empty(t, r.Guild)
+		inherited++
+	}
+	assert.Equal(t, 1, overridden)
+	assert.Equal(t, len(set.Rules())-1, inherited)
+}
+
+// =============================================================================
+// Guild Settings Tests
+// =============================================================================
+
+func Test_Set_GuildSettings(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule(rules.RuleSettings, rules.KeyPrefix, "!"))
+	require.NoError(t, set.SetRule(rules.RuleSettings, rules.KeyLocale, "fr"))
+	require.NoError(t, set.SetRule(rules.RuleSettings, rules.KeyModLogChannel, "111"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleSettings, rules.KeyPrefix, "?"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleSettings, rules.KeyLocale, "de"))
+	require.NoError(t, set.SetGuildRule("guild-2", rules.RuleSettings, rules.KeyPrefix, ""))
+	require.NoError(t, set.SetGuildRule("guild-2", rules.RuleSettings, rules.KeyModLogChannel, "222"))
+
+	tests := []struct {
+		name  string
+		guild string
+		want  rules.GuildSettings
+	}{
+		{name: "global settings", guild: "", want: rules.GuildSettings{Prefix: "!", ModLogChannel: "111", Locale: "fr"}},
+		{name: "guild overrides", guild: "guild-1", want: rules.GuildSettings{Prefix: "?", ModLogChannel: "111", Locale: "de"}},
+		{name: "guild turns text commands off", guild: "guild-2", want: rules.GuildSettings{ModLogChannel: "222", Locale: "fr"}},
+		{name: "other guild inherits global", guild: "guild-3", want: rules.GuildSettings{Prefix: "!", ModLogChannel: "111", Locale: "fr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, set.GuildSettings(tt.guild))
+		})
+	}
+
+	var nilSet *rules.Set
+	assert.Equal(t, rules.GuildSettings{}, nilSet.GuildSettings("guild-1"))
+}
+
+func Test_Set_Settings_AlwaysEnabled(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	for _, r := range set.Rules() {
+		if r.Name == rules.RuleSettings {
+			assert.True(t, r.Enabled, "settings are always in effect")
+			assert.NotEqual(t, rules.KeyEnabled, r.Key)
+		}
+	}
+	assert.Equal(t, 0, set.ActiveCount(), "settings do not count as active rules")
+}
+
+func Test_Set_SetDefault(t *testing.T) {
+	t.Run("replaces an unset value", func(t *testing.T) {
+		set := rules.NewSet(rules.Defaults()...)
+
+		require.NoError(t, set.SetDefault(rules.RuleSettings, rules.KeyPrefix, "!"))
+
+		assert.Equal(t, "!", set.GuildSettings("guild-1").Prefix)
+	})
+
+	t.Run("keeps a value already set", func(t *testing.T) {
+		set := rules.NewSet(rules.Defaults()...)
+		require.NoError(t, set.SetRule(rules.RuleSettings, rules.KeyPrefix, "?"))
+
+		require.NoError(t, set.SetDefault(rules.RuleSettings, rules.KeyPrefix, "!"))
+
+		assert.Equal(t, "?", set.GuildSettings("").Prefix)
+	})
+
+	t.Run("leaves other sets untouched", func(t *testing.T) {
+		defs := rules.Defaults()
+		set := rules.NewSet(defs...)
+
+		require.NoError(t, set.SetDefault(rules.RuleSettings, rules.KeyPrefix, "!"))
+
+		assert.Empty(t, rules.NewSet(defs...).GuildSettings("").Prefix)
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		set := rules.NewSet(rules.Defaults()...)
+
+		assert.ErrorIs(t, set.SetDefault(rules.RuleSettings, rules.KeyPrefix, "a b"), control.ErrInvalidRule)
+		assert.ErrorIs(t, set.SetDefault(rules.RuleSettings, "colour", "red"), control.ErrInvalidRule)
+		assert.ErrorIs(t, set.SetDefault("nonexistent", rules.KeyPrefix, "!"), control.ErrRuleNotFound)
+		assert.Empty(t, set.GuildSettings("").Prefix)
+	})
+}
+
+// =============================================================================
+// Save and Load Tests
+// =============================================================================
+
+func Test_Set_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+
+	saved := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, saved.SetDefault(rules.RuleSettings, rules.KeyPrefix, "!"))
+	require.NoError(t, saved.SetRule("anti-spam", "enabled", "true"))
+	require.NoError(t, saved.SetRule("anti-spam", "threshold", "9"))
+	require.NoError(t, saved.SetGuildRule("guild-1", rules.RuleSettings, rules.KeyPrefix, "?"))
+	require.NoError(t, saved.SetGuildRule("guild-1", "anti-spam", "threshold", "5"))
+	require.NoError(t, saved.Save(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"value": "!"`, "values left at their default are not saved")
+	assert.NotContains(t, string(data), "word-filter")
+
+	loaded := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, loaded.SetDefault(rules.RuleSettings, rules.KeyPrefix, "!"))
+	require.NoError(t, loaded.Load(path))
+
+	assert.Equal(t, saved.Rules(), loaded.Rules())
+	assert.Equal(t, saved.GuildRules("guild-1"), loaded.GuildRules("guild-1"))
+	assert.Equal(t, "?", loaded.GuildSettings("guild-1").Prefix)
+
+	value, _ := loaded.GuildValue("guild-1", "anti-spam", "threshold")
+	assert.Equal(t, "5", value, "guild overrides equal to the global default are still saved")
+}
+
+func Test_Set_Load(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{name: "missing file", content: ""},
+		{name: "import format", content: `[{"name":"anti-spam","key":"threshold","value":"8","guild":"guild-1"}]`},
+		{name: "invalid JSON", content: "{", wantErr: errors.New("failed to parse")},
+		{name: "invalid value", content: `[{"name":"anti-spam","key":"threshold","value":"lots"}]`, wantErr: control.ErrInvalidRule},
+		{name: "unknown rule", content: `[{"name":"nonexistent","key":"enabled","value":"true"}]`, wantErr: control.ErrRuleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.json")
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			}
+			set := rules.NewSet(rules.Defaults()...)
+
+			err := set.Load(path)
+
+			switch {
+			case tt.wantErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tt.wantErr, control.ErrInvalidRule), errors.Is(tt.wantErr, control.ErrRuleNotFound):
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+func Test_Set_Save_Unwritable(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+
+	err := set.Save(filepath.Join(t.TempDir(), "missing", "rules.json"))
+
+	assert.Error(t, err)
+}
+
+func Test_Set_CheckContent_GuildScope(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule("word-filter", "words", "darn"))
+	require.NoError(t, set.SetGuildRule("guild-1", "word-filter", "enabled", "true"))
+	require.NoError(t, set.SetGuildRule("guild-2", "word-filter", "enabled", "true"))
+	require.NoError(t, set.SetGuildRule("guild-2", "word-filter", "words", "heck"))
+
+	_, hit := set.CheckContent("guild-0", "darn it")
+	assert.False(t, hit, "rule is disabled globally")
+
+	v, hit := set.CheckContent("guild-1", "darn it")
+	assert.True(t, hit, "guild enables the rule with the global word list")
+	assert.Equal(t, "darn", v.Match)
+
+	_, hit = set.CheckContent("guild-2", "darn it")
+	assert.False(t, hit, "guild word list replaces the global one")
+	_, hit = set.CheckContent("guild-2", "heck no")
+	assert.True(t, hit)
+}
+
+// =============================================================================
+// TestRule Tests
+// =============================================================================
+
+func Test_Set_TestRule(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule(rules.RuleWordFilter, rules.KeyWords, "darn"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleWordFilter, rules.KeyEnabled, "true"))
+	require.NoError(t, set.SetGuildRule("guild-1", rules.RuleWordFilter, rules.KeyWords, "heck"))
+
+	tests := []struct {
+		name    string
+		guildID string
+		rule    string
+		input   string
+		want    *control.RuleTestResult
+		wantErr error
+	}{
+		{
+			name:  "disabled rule is still tested",
+			rule:  rules.RuleWordFilter,
+			input: "well darn it",
+			want: &control.RuleTestResult{
+				Name:    rules.RuleWordFilter,
+				Matched: true,
+				Match:   "darn",
+				Action:  rules.ActionDelete,
+			},
+		},
+		{
+			name:  "no match",
+			rule:  rules.RuleWordFilter,
+			input: "hello there",
+			want:  &control.RuleTestResult{Name: rules.RuleWordFilter},
+		},
+		{
+			name:    "guild overrides apply",
+			guildID: "guild-1",
+			rule:    rules.RuleWordFilter,
+			input:   "well darn it",
+			want:    &control.RuleTestResult{Name: rules.RuleWordFilter, Guild: "guild-1", Enabled: true},
+		},
+		{
+			name:    "trims whitespace",
+			guildID: " guild-1 ",
+			rule:    " word-filter ",
+			input:   "heck no",
+			want: &control.RuleTestResult{
+				Name:    rules.RuleWordFilter,
+				Guild:   "guild-1",
+				Enabled: true,
+				Matched: true,
+				Match:   "heck",
+				Action:  rules.ActionDelete,
+			},
+		},
+		{
+			name:  "link filter",
+			rule:  rules.RuleLinkFilter,
+			input: "see https://example.com/page",
+			want: &control.RuleTestResult{
+				Name:    rules.RuleLinkFilter,
+				Matched: true,
+				Match:   "https://example.com",
+				Action:  rules.ActionDelete,
+			},
+		},
+		{
+			name:    "unknown rule",
+			rule:    "nonexistent",
+			input:   "darn",
+			wantErr: control.ErrRuleNotFound,
+		},
+		{
+			name:    "rule without content matching",
+			rule:    rules.RuleWelcome,
+			input:   "darn",
+			wantErr: control.ErrRuleNotTestable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := set.TestRule(tt.guildID, tt.rule, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, hit := set.CheckContent("", "well darn it")
+	assert.False(t, hit, "testing a rule must not enable it")
+}
+
+func Test_Set_TestRule_NilSet(t *testing.T) {
+	var set *rules.Set
+	_, err := set.TestRule("", rules.RuleWordFilter, "darn")
+	assert.Error(t, err)
+}
+
+// =============================================================================
+// Concurrency Tests
+// =============================================================================
+
+func Test_Set_ConcurrentAccess(t *testing.T) {
+	set := rules.NewSet(rules.Defaults()...)
+	require.NoError(t, set.SetRule(rules.RuleWordFilter, rules.KeyEnabled, "true"))
+	require.NoError(t, set.SetRule(rules.RuleWordFilter, rules.KeyWords, "darn"))
+
+	const numGoroutines = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*numGoroutines)
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(4)
+		go func(i int) {
+			defer wg.Done()
+			errs <- set.SetRule(rules.RuleWordFilter, rules.KeyAction, []string{rules.ActionDelete, rules.ActionWarn}[i%2])
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			errs <- set.SetGuildRule(fmt.Sprintf("guild-%d", i), rules.RuleWordFilter, rules.KeyWords, "heck")
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			assert.NotEmpty(t, set.Rules())
+			assert.NotEmpty(t, set.GuildRules(fmt.Sprintf("guild-%d", i)))
+		}(i)
+		go func() {
+			defer wg.Done()
+			v, hit := set.CheckContent("", "darn it")
+			assert.True(t, hit, "the word list is never changed globally")
+			assert.Contains(t, []string{rules.ActionDelete, rules.ActionWarn}, v.Action)
+			set.Ignored("", "channel-1", "user-1", nil)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	for i := 0; i < numGoroutines; i++ {
+		_, hit := set.CheckContent(fmt.Sprintf("guild-%d", i), "heck no")
+		assert.True(t, hit, "every guild override should be kept")
+	}
+}
@@ -0,0 +1,33 @@
+package rules
+
+// GuildSettings holds the settings in effect in a guild, from the settings
+// group: the guild's own where it has set them, otherwise the global ones.
+type GuildSettings struct {
+	// Prefix starts text commands. Empty disables them.
+	Prefix string
+
+	// ModLogChannel is the ID of the channel moderation actions are logged
+	// in, or empty for none.
+	ModLogChannel string
+
+	// Locale is the language responses default to in place of the guild's
+	// preferred locale, or empty to keep it.
+	Locale string
+}
+
+// GuildSettings returns the settings in effect in the given guild. An empty
+// guildID, as for direct messages, returns the global settings.
+func (s *Set) GuildSettings(guildID string) GuildSettings {
+	if s == nil {
+		return GuildSettings{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return GuildSettings{
+		Prefix:        s.value(guildID, RuleSettings, KeyPrefix),
+		ModLogChannel: s.value(guildID, RuleSettings, KeyModLogChannel),
+		Locale:        s.value(guildID, RuleSettings, KeyLocale),
+	}
+}
@@ -0,0 +1,29 @@
+package rules
+
+import (
+	"strconv"
+	"time"
+)
+
+// SpamLimit returns how many messages the anti-spam rule allows a member to
+// send per window in guildID. It reports false when the rule is disabled
+// there.
+func (s *Set) SpamLimit(guildID string) (threshold int, window time.Duration, ok bool) {
+	if !s.GuildEnabled(guildID, RuleAntiSpam) {
+		return 0, 0, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Both were validated when set
+	threshold, err := strconv.Atoi(s.value(guildID, RuleAntiSpam, KeyThreshold))
+	if err != nil {
+		return 0, 0, false
+	}
+	window, err = time.ParseDuration(s.value(guildID, RuleAntiSpam, KeyWindow))
+	if err != nil {
+		return 0, 0, false
+	}
+	return threshold, window, true
+}
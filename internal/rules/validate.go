@@ -0,0 +1,126 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"jamesbot/internal/warnings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Validator checks whether a raw string value is acceptable for a rule key.
+type Validator func(value string) error
+
+// Bool accepts "true" or "false".
+func Bool(value string) error {
+	if value != "true" && value != "false" {
+		return fmt.Errorf("must be true or false, got %q", value)
+	}
+	return nil
+}
+
+// PositiveInt accepts base-10 integers greater than zero.
+func PositiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("must be a positive integer, got %q", value)
+	}
+	return nil
+}
+
+// PositiveDuration accepts Go duration strings greater than zero, such as "10s" or "5m".
+func PositiveDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("must be a positive duration like 10s or 5m, got %q", value)
+	}
+	return nil
+}
+
+// DurationBetween returns a Validator that accepts Go duration strings in [min, max].
+func DurationBetween(min, max time.Duration) Validator {
+	return func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < min || d > max {
+			return fmt.Errorf("must be a duration between %s and %s, got %q", min, max, value)
+		}
+		return nil
+	}
+}
+
+// EscalationPolicy accepts warning escalation policies such as "3=mute,5=ban".
+func EscalationPolicy(value string) error {
+	if _, err := warnings.ParsePolicy(value); err != nil {
+		return fmt.Errorf("invalid escalation policy: %w", err)
+	}
+	return nil
+}
+
+// IDList accepts a comma-separated list of Discord IDs, or an empty string.
+func IDList(value string) error {
+	for _, id := range strings.Split(value, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return fmt.Errorf("must be a comma-separated list of IDs, got %q", id)
+		}
+	}
+	return nil
+}
+
+// OptionalID accepts a single Discord ID, or an empty string.
+func OptionalID(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		return fmt.Errorf("must be an ID, got %q", value)
+	}
+	return nil
+}
+
+// Prefix accepts a text command prefix without whitespace, or an empty string.
+func Prefix(value string) error {
+	if strings.ContainsFunc(value, unicode.IsSpace) {
+		return fmt.Errorf("must not contain whitespace, got %q", value)
+	}
+	return nil
+}
+
+// Locale accepts a locale Discord supports, such as "en-US" or "de", or an
+// empty string.
+func Locale(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, ok := discordgo.Locales[discordgo.Locale(value)]; !ok {
+		return fmt.Errorf("must be a Discord locale such as en-US or de, got %q", value)
+	}
+	return nil
+}
+
+// NonEmpty accepts any value that is not blank.
+func NonEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
+
+// OneOf returns a Validator that accepts only the listed values.
+func OneOf(allowed ...string) Validator {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+}
@@ -0,0 +1,79 @@
+package warnings
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Action is the punishment applied when a member reaches a warning threshold.
+type Action string
+
+// Escalation actions.
+const (
+	ActionMute Action = "mute"
+	ActionKick Action = "kick"
+	ActionBan  Action = "ban"
+)
+
+// Step applies Action when a member reaches Count warnings.
+type Step struct {
+	Count  int
+	Action Action
+}
+
+// Policy is an escalation policy ordered by ascending Count.
+type Policy []Step
+
+// ParsePolicy parses a policy such as "3=mute,5=ban". Each entry maps a
+// positive warning count to an action; counts must be unique. An empty string
+// yields an empty policy.
+func ParsePolicy(s string) (Policy, error) {
+	var policy Policy
+	seen := make(map[int]bool)
+
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		countStr, actionStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("entry %q must be <count>=<action>", entry)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("entry %q: count must be a positive integer", entry)
+		}
+		if seen[count] {
+			return nil, fmt.Errorf("entry %q: count %d listed more than once", entry, count)
+		}
+		seen[count] = true
+
+		action := Action(strings.ToLower(strings.TrimSpace(actionStr)))
+		switch action {
+		case ActionMute, ActionKick, ActionBan:
+		default:
+			return nil, fmt.Errorf("entry %q: action must be one of %s, %s, %s", entry, ActionMute, ActionKick, ActionBan)
+		}
+
+		policy = append(policy, Step{Count: count, Action: action})
+	}
+
+	sort.Slice(policy, func(i, j int) bool { return policy[i].Count < policy[j].Count })
+	return policy, nil
+}
+
+// StepAt returns the step triggered when a member's warning count becomes count.
+// Only an exact match triggers, so each threshold fires once as it is crossed.
+func (p Policy) StepAt(count int) (Step, bool) {
+	for _, step := range p {
+		if step.Count == count {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
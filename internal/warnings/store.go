@@ -0,0 +1,94 @@
+// Package warnings tracks moderation warnings issued to guild members and the
+// escalation policy applied as they accumulate.
+package warnings
+
+import (
+	"sync"
+	"time"
+)
+
+// Warning is a single warning issued to a guild member.
+type Warning struct {
+	GuildID     string
+	UserID      string
+	ModeratorID string
+	Reason      string
+	CreatedAt   time.Time
+}
+
+type memberKey struct {
+	guildID string
+	userID  string
+}
+
+// Store is an in-memory, concurrency-safe record of warnings per guild member.
+// A nil *Store is valid and records nothing.
+type Store struct {
+	mu       sync.RWMutex
+	byMember map[memberKey][]Warning
+}
+
+// NewStore creates an empty warning store.
+func NewStore() *Store {
+	return &Store{
+		byMember: make(map[memberKey][]Warning),
+	}
+}
+
+// Add records w and returns the member's warning count including it.
+// A zero CreatedAt is set to the current time.
+func (s *Store) Add(w Warning) int {
+	if s == nil {
+		return 0
+	}
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memberKey{guildID: w.GuildID, userID: w.UserID}
+	s.byMember[key] = append(s.byMember[key], w)
+	return len(s.byMember[key])
+}
+
+// List returns a copy of the member's warnings, oldest first.
+func (s *Store) List(guildID, userID string) []Warning {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	warnings := s.byMember[memberKey{guildID: guildID, userID: userID}]
+	return append([]Warning(nil), warnings...)
+}
+
+// Count returns the number of warnings recorded for the member.
+func (s *Store) Count(guildID, userID string) int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.byMember[memberKey{guildID: guildID, userID: userID}])
+}
+
+// Clear deletes all of the member's warnings and returns how many were removed.
+func (s *Store) Clear(guildID, userID string) int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memberKey{guildID: guildID, userID: userID}
+	removed := len(s.byMember[key])
+	delete(s.byMember, key)
+	return removed
+}
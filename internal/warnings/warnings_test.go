@@ -0,0 +1,140 @@
+package warnings_test
+
+import (
+	"sync"
+	"testing"
+
+	"jamesbot/internal/warnings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===========================================================================
+// Store Tests
+// ===========================================================================
+
+func Test_Store_AddCountList(t *testing.T) {
+	s := warnings.NewStore()
+
+	assert.Equal(t, 1, s.Add(warnings.Warning{GuildID: "g1", UserID: "u1", Reason: "first"}))
+	assert.Equal(t, 2, s.Add(warnings.Warning{GuildID: "g1", UserID: "u1", Reason: "second"}))
+	assert.Equal(t, 1, s.Add(warnings.Warning{GuildID: "g2", UserID: "u1"}), "guilds are tracked separately")
+	assert.Equal(t, 1, s.Add(warnings.Warning{GuildID: "g1", UserID: "u2"}), "users are tracked separately")
+
+	assert.Equal(t, 2, s.Count("g1", "u1"))
+	assert.Equal(t, 0, s.Count("g1", "nobody"))
+
+	list := s.List("g1", "u1")
+	require.Len(t, list, 2)
+	assert.Equal(t, "first", list[0].Reason)
+	assert.False(t, list[0].CreatedAt.IsZero(), "CreatedAt is filled in")
+
+	list[0].Reason = "mutated"
+	assert.Equal(t, "first", s.List("g1", "u1")[0].Reason, "List returns a copy")
+}
+
+func Test_Store_Clear(t *testing.T) {
+	s := warnings.NewStore()
+	s.Add(warnings.Warning{GuildID: "g1", UserID: "u1"})
+	s.Add(warnings.Warning{GuildID: "g1", UserID: "u1"})
+	s.Add(warnings.Warning{GuildID: "g2", UserID: "u1"})
+
+	assert.Equal(t, 2, s.Clear("g1", "u1"))
+	assert.Equal(t, 0, s.Count("g1", "u1"))
+	assert.Equal(t, 1, s.Count("g2", "u1"), "other guilds are untouched")
+	assert.Equal(t, 0, s.Clear("g1", "u1"), "clearing again removes nothing")
+	assert.Equal(t, 1, s.Add(warnings.Warning{GuildID: "g1", UserID: "u1"}), "count restarts after clear")
+}
+
+func Test_Store_Nil(t *testing.T) {
+	var s *warnings.Store
+
+	assert.Equal(t, 0, s.Add(warnings.Warning{GuildID: "g", UserID: "u"}))
+	assert.Equal(t, 0, s.Count("g", "u"))
+	assert.Nil(t, s.List("g", "u"))
+	assert.Equal(t, 0, s.Clear("g", "u"))
+}
+
+func Test_Store_ConcurrentAdd(t *testing.T) {
+	s := warnings.NewStore()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Add(warnings.Warning{GuildID: "g", UserID: "u"})
+			_ = s.List("g", "u")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, s.Count("g", "u"))
+}
+
+// ===========================================================================
+// Escalation Policy Tests
+// ===========================================================================
+
+func Test_ParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    warnings.Policy
+		wantErr string
+	}{
+		{name: "empty", input: "", want: nil},
+		{
+			name:  "sorted by count",
+			input: "5=ban, 3=mute",
+			want:  warnings.Policy{{Count: 3, Action: warnings.ActionMute}, {Count: 5, Action: warnings.ActionBan}},
+		},
+		{
+			name:  "actions are case insensitive",
+			input: "2=KICK",
+			want:  warnings.Policy{{Count: 2, Action: warnings.ActionKick}},
+		},
+		{name: "missing equals", input: "3mute", wantErr: "<count>=<action>"},
+		{name: "non-numeric count", input: "x=mute", wantErr: "positive integer"},
+		{name: "zero count", input: "0=mute", wantErr: "positive integer"},
+		{name: "unknown action", input: "3=explode", wantErr: "action must be one of"},
+		{name: "duplicate count", input: "3=mute,3=ban", wantErr: "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := warnings.ParsePolicy(tt.input)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Policy_StepAt(t *testing.T) {
+	policy, err := warnings.ParsePolicy("3=mute,5=ban")
+	require.NoError(t, err)
+
+	tests := []struct {
+		count  int
+		want   warnings.Action
+		wantOK bool
+	}{
+		{count: 1, wantOK: false},
+		{count: 3, want: warnings.ActionMute, wantOK: true},
+		{count: 4, wantOK: false},
+		{count: 5, want: warnings.ActionBan, wantOK: true},
+		{count: 6, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		step, ok := policy.StepAt(tt.count)
+		assert.Equal(t, tt.wantOK, ok, "count %d", tt.count)
+		assert.Equal(t, tt.want, step.Action, "count %d", tt.count)
+	}
+}
@@ -0,0 +1,51 @@
+package fileutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jamesbot/pkg/fileutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteAtomic(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+	}{
+		{name: "creates the file"},
+		{name: "replaces the file", existing: "old contents\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "state.json")
+			if tt.existing != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.existing), 0o600))
+			}
+
+			require.NoError(t, fileutil.WriteAtomic(path, []byte("new contents\n")))
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "new contents\n", string(data))
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Len(t, entries, 1, "the temporary file should not be left behind")
+		})
+	}
+}
+
+func Test_WriteAtomic_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "state.json")
+
+	err := fileutil.WriteAtomic(path, []byte("{}"))
+
+	require.Error(t, err)
+	assert.NoFileExists(t, path)
+}